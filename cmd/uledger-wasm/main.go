@@ -0,0 +1,21 @@
+//go:build js && wasm
+
+// Command uledger-wasm is the WebAssembly module browsers sign ULedger transactions with, see
+// package wasm. It registers the global uledger object and keeps running so JavaScript can call it.
+// uledger.js loads it next to wasm_exec.js from $(go env GOROOT)/lib/wasm:
+//
+//	GOOS=js GOARCH=wasm go build -o uledger.wasm ./cmd/uledger-wasm
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wasm"
+)
+
+func main() {
+	uledger := js.Global().Get("Object").New()
+	wasm.Register(uledger)
+	js.Global().Set("uledger", uledger)
+	select {}
+}
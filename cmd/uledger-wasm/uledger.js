@@ -0,0 +1,24 @@
+// Loads uledger.wasm, wasm_exec.js from $(go env GOROOT)/lib/wasm must be loaded first. The functions
+// of the returned object throw the errors the module returns:
+//
+//   const uledger = await load("uledger.wasm");
+//   const wallet = uledger.generateWallet("secp256k1");
+//   const signed = uledger.signTransaction(input, wallet);
+//   await fetch(`/blockchains/${signed.blockchainId}/transactions`, {method: "POST", body: JSON.stringify(signed)});
+export async function load(url = "uledger.wasm") {
+  const go = new Go();
+  const { instance } = await WebAssembly.instantiateStreaming(fetch(url), go.importObject);
+  go.run(instance);
+
+  const api = {};
+  for (const [name, fn] of Object.entries(globalThis.uledger)) {
+    api[name] = (...args) => {
+      const result = fn(...args);
+      if (result instanceof Error) {
+        throw result;
+      }
+      return result;
+    };
+  }
+  return api;
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/benchcmp"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+func benchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "bench",
+		Usage: i18n.T("bench.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "compare",
+				Usage:       i18n.T("bench.compare.usage"),
+				ArgsUsage:   "<old> <new>",
+				Description: i18n.T("bench.compare.description"),
+				Flags: []cli.Flag{
+					&cli.FloatFlag{Name: "threshold", Aliases: []string{"t"}, Usage: i18n.T("bench.threshold.usage"), Value: benchcmp.DEFAULT_THRESHOLD},
+				},
+				Action: compareAction,
+			},
+		},
+	}
+}
+
+func compareAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 2 {
+		return i18n.Errorf("bench.compare.args")
+	}
+	old, err := parseBenchmarks(cmd.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	new, err := parseBenchmarks(cmd.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	report := benchcmp.Compare(old, new, cmd.Float("threshold"))
+	if err := report.Write(cmd.Root().Writer); err != nil {
+		return err
+	}
+	if regressions := report.Regressions(); len(regressions) > 0 {
+		return i18n.Errorf("bench.compare.regressed", len(regressions), report.Threshold)
+	}
+	return nil
+}
+
+func parseBenchmarks(path string) ([]benchcmp.Result, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, i18n.Errorf("bench.compare.read", err)
+	}
+	defer file.Close()
+	results, err := benchcmp.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return results, nil
+}
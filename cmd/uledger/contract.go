@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/bindgen"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+func contractCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "contract",
+		Usage: i18n.T("contract.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "bind",
+				Usage:       i18n.T("contract.bind.usage"),
+				ArgsUsage:   "<manifest>",
+				Description: i18n.T("contract.bind.description"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "package", Aliases: []string{"p"}, Usage: i18n.T("contract.package.usage"), Required: true},
+					&cli.StringFlag{Name: "type", Aliases: []string{"t"}, Usage: i18n.T("contract.type.usage")},
+					&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: i18n.T("contract.out.usage")},
+				},
+				Action: bindAction,
+			},
+		},
+	}
+}
+
+func bindAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 1 {
+		return i18n.Errorf("contract.bind.args")
+	}
+	path := cmd.Args().First()
+	file, err := os.Open(path)
+	if err != nil {
+		return i18n.Errorf("contract.bind.read", err)
+	}
+	defer file.Close()
+	manifest, err := bindgen.ParseManifest(file)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	source, err := bindgen.Generate(manifest, bindgen.Options{Package: cmd.String("package"), Type: cmd.String("type")})
+	if err != nil {
+		return err
+	}
+	if out := cmd.String("out"); out != "" {
+		return os.WriteFile(out, source, 0644)
+	}
+	_, err = cmd.Root().Writer.Write(source)
+	return err
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/faucet"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+func faucetCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "faucet",
+		Usage: i18n.T("faucet.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:  "request",
+				Usage: i18n.T("faucet.request.usage"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "url", Aliases: []string{"u"}, Usage: i18n.T("faucet.url.usage"), Required: true},
+					&cli.StringFlag{Name: "blockchain", Aliases: []string{"b"}, Usage: i18n.T("faucet.blockchain.usage"), Required: true},
+					&cli.StringFlag{Name: "address", Aliases: []string{"a"}, Usage: i18n.T("faucet.address.usage"), Required: true},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					client := faucet.NewClient(cmd.String("url"))
+					response, err := client.Request(ctx, cmd.String("blockchain"), cmd.String("address"))
+					if err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.Root().Writer, i18n.T("faucet.sent", response.Amount, response.TokenAddress, response.TransactionId))
+					fmt.Fprintln(cmd.Root().Writer, i18n.T("faucet.next", response.NextRequestAt.Format(time.RFC3339)))
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/gateway"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+func gatewayCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "gateway",
+		Usage: i18n.T("gateway.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "generate",
+				Usage:       i18n.T("gateway.generate.usage"),
+				Description: i18n.T("gateway.generate.description"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "package", Aliases: []string{"p"}, Usage: i18n.T("gateway.package.usage"), Required: true},
+					&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: i18n.T("gateway.out.usage")},
+					&cli.StringFlag{Name: "openapi", Usage: i18n.T("gateway.openapi.usage")},
+					&cli.StringFlag{Name: "title", Usage: i18n.T("gateway.title.usage"), Value: "ULedger gateway"},
+					&cli.StringFlag{Name: "version", Usage: i18n.T("gateway.version.usage"), Value: "1.0.0"},
+				},
+				Action: generateGatewayAction,
+			},
+		},
+	}
+}
+
+func generateGatewayAction(ctx context.Context, cmd *cli.Command) error {
+	source, err := gateway.Generate(gateway.Operations, gateway.Options{Package: cmd.String("package")})
+	if err != nil {
+		return err
+	}
+	if path := cmd.String("openapi"); path != "" {
+		definition, err := gateway.OpenAPI(gateway.Operations, gateway.Info{Title: cmd.String("title"), Version: cmd.String("version")})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, append(definition, '\n'), 0644); err != nil {
+			return err
+		}
+	}
+	if out := cmd.String("out"); out != "" {
+		return os.WriteFile(out, source, 0644)
+	}
+	_, err = cmd.Root().Writer.Write(source)
+	return err
+}
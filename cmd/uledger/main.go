@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+)
+
+func main() {
+	app := &cli.Command{
+		Name:                  "uledger",
+		Usage:                 i18n.T("uledger.usage"),
+		EnableShellCompletion: true,
+		Commands: []*cli.Command{
+			txCommand(),
+			faucetCommand(),
+			benchCommand(),
+			walletCommand(),
+			contractCommand(),
+			gatewayCommand(),
+			vectorsCommand(),
+		},
+	}
+
+	if err := app.Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, i18n.T("uledger.error", i18n.Format(err)))
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import "github.com/ULedgerInc/go-sdk/pkg/i18n"
+
+func init() {
+	i18n.Register(i18n.LOCALE_EN, i18n.Messages{
+		"uledger.usage": "Inspect and work with ULedger transactions",
+		"uledger.error": "Error: %s",
+
+		"tx.usage":              "Transaction tools",
+		"tx.decode.usage":       "Print a fully decoded view of a transaction",
+		"tx.decode.description": "Reads the transaction JSON from a file, from stdin when no file or - is given, or fetches it\nfrom a node with --node, --blockchain and --id. The payload root is always recomputed, the\nsignature is checked when the sender public key is passed with --public-key.",
+		"tx.node.usage":         "The node endpoint to fetch the transaction from",
+		"tx.blockchain.usage":   "The blockchain of the transaction to fetch",
+		"tx.id.usage":           "The id of the transaction to fetch",
+		"tx.public_key.usage":   "The sender public key hex, enables the signature check",
+		"tx.fetch.flags":        "--node and --blockchain are required to fetch a transaction by id",
+		"tx.read":               "error reading transaction file: %w",
+		"tx.invalid_json":       "invalid transaction JSON: %s",
+		"tx.transaction":        "Transaction",
+		"tx.blockchain":         "Blockchain",
+		"tx.type":               "Type",
+		"tx.status":             "Status",
+		"tx.output":             "Output",
+		"tx.block_height":       "Block height",
+		"tx.from":               "From",
+		"tx.to":                 "To",
+		"tx.suggestor":          "Suggestor",
+		"tx.key_type":           "Key type",
+		"tx.version":            "Version",
+		"tx.sent_at":            "Sent at",
+		"tx.exact_time":         "Exact time",
+		"tx.approximate_time":   "Approximate time",
+		"tx.memo":               "Memo",
+		"tx.commitment":         "Commitment",
+		"tx.signature":          "Signature",
+		"tx.problem":            "Problem",
+		"tx.payload":            "Payload",
+		"tx.payload_error":      "Payload error",
+		"tx.check.skipped":      "not checked (%s)",
+		"tx.check.valid":        "valid (%s)",
+		"tx.check.invalid":      "INVALID (%s)",
+
+		"faucet.usage":            "Testnet faucet tools",
+		"faucet.request.usage":    "Request testnet tokens for an address",
+		"faucet.url.usage":        "The faucet endpoint",
+		"faucet.blockchain.usage": "The blockchain to fund the address on",
+		"faucet.address.usage":    "The wallet address to fund",
+		"faucet.sent":             "Sent %d of token %s in transaction %s",
+		"faucet.next":             "Next request allowed at %s",
+
+		"bench.usage":               "Benchmark tools",
+		"bench.compare.usage":       "Compare two runs of go test -bench and fail on regressions",
+		"bench.compare.description": "Both files hold the output of go test -bench, -count above 1 is recommended. A benchmark\nregresses when its median time per operation grows by more than --threshold percent or it\nallocates more often.",
+		"bench.threshold.usage":     "The allowed slowdown in percent",
+		"bench.compare.args":        "expected the old and the new benchmark output files",
+		"bench.compare.regressed":   "%d benchmarks regressed beyond %.1f%%",
+		"bench.compare.read":        "error reading benchmark output: %w",
+
+		"wallet.usage":              "Wallet tools",
+		"wallet.health.usage":       "Check the wallet files of a directory against their registrations",
+		"wallet.health.description": "Reports the .ukey files whose wallet is unregistered, disabled, or registered with other auth\ngroups or another key than the file. With --fix the transactions aligning the chain with the\nfiles are printed as JSON inputs, one per line, to be reviewed, signed and submitted.",
+		"wallet.blockchain.usage":   "The blockchain the wallets are registered on",
+		"wallet.fix.usage":          "Print the fix-up transaction inputs",
+		"wallet.health.directory":   "the wallet directory is required",
+		"wallet.health.unhealthy":   "%d of %d wallets are not healthy",
+		"wallet.health.healthy":     "healthy",
+		"wallet.health.file":        "FILE",
+		"wallet.health.address":     "ADDRESS",
+		"wallet.health.status":      "STATUS",
+		"wallet.health.detail":      "DETAIL",
+
+		"wallet.state.usage":   "Show the status, parent and auth groups a wallet is registered with",
+		"wallet.state.address": "the wallet address is required",
+		"wallet.state.enabled": "ENABLED",
+		"wallet.state.parent":  "PARENT",
+		"wallet.state.group":   "AUTH GROUP",
+		"wallet.state.create":  "CREATE",
+		"wallet.state.read":    "READ",
+		"wallet.state.update":  "UPDATE",
+		"wallet.state.delete":  "DELETE",
+
+		"contract.usage":            "Smart contract tools",
+		"contract.bind.usage":       "Generate a typed Go binding of a contract from its manifest",
+		"contract.bind.description": "Reads the JSON manifest of the contract functions and writes a Go file with one method per\nfunction, encoding the arguments and setting the gas limits. The file is printed unless --out\nis given, which suits go:generate directives.",
+		"contract.package.usage":    "The package of the generated file",
+		"contract.type.usage":       "The name of the binding type, the manifest name by default",
+		"contract.out.usage":        "The file to write the binding to",
+		"contract.bind.args":        "expected the manifest file",
+		"contract.bind.read":        "error reading the contract manifest: %w",
+
+		"gateway.usage":                "REST gateway tools",
+		"gateway.generate.usage":       "Generate the REST handlers and OpenAPI definition of a gateway proxying SDK operations",
+		"gateway.generate.description": "Writes a Go file with the Service interface of the SDK operations, an UnimplementedService and\nthe handlers serving them. With --openapi the OpenAPI definition of the same operations is\nwritten too. Both follow the pkg/transaction structs, regenerate them with go:generate.",
+		"gateway.package.usage":        "The package of the generated file",
+		"gateway.out.usage":            "The file to write the handlers to, printed when empty",
+		"gateway.openapi.usage":        "The file to write the OpenAPI definition to",
+		"gateway.title.usage":          "The title of the OpenAPI definition",
+		"gateway.version.usage":        "The version of the OpenAPI definition",
+
+		"vectors.usage":                "Cross-SDK test vector tools",
+		"vectors.generate.usage":       "Generate the test vector suite other SDKs check their outputs against",
+		"vectors.generate.description": "Writes the JSON suite of keys, addresses, transaction commitments, signatures and serializer\nencodings of every type, derived from --seed so the suite is the same on every run. Signatures\nof randomized key types differ between runs and are verified rather than compared.",
+		"vectors.seed.usage":           "The seed the entropy of the keys is derived from",
+		"vectors.out.usage":            "The file to write the suite to, printed when empty",
+		"vectors.verify.usage":         "Check the outputs of another implementation against the Go SDK",
+		"vectors.verify.description":   "Reads a suite written by another SDK from the inputs of a generated suite, recomputes every\noutput and prints the ones that differ.",
+		"vectors.verify.args":          "expected the suite file",
+		"vectors.verify.read":          "error reading the test vector suite: %w",
+		"vectors.verify.failed":        "%d mismatches in %d vectors",
+		"vectors.verify.passed":        "%d vectors match",
+	})
+	i18n.Register(i18n.LOCALE_ES, i18n.Messages{
+		"uledger.usage": "Inspeccionar y trabajar con transacciones de ULedger",
+		"uledger.error": "Error: %s",
+
+		"tx.usage":              "Herramientas de transacciones",
+		"tx.decode.usage":       "Mostrar una vista completamente decodificada de una transacción",
+		"tx.decode.description": "Lee el JSON de la transacción de un archivo, de stdin cuando no se indica archivo o se indica -,\no lo obtiene de un nodo con --node, --blockchain e --id. La raíz del payload siempre se recalcula,\nla firma se verifica cuando se pasa la clave pública del emisor con --public-key.",
+		"tx.node.usage":         "El nodo del que obtener la transacción",
+		"tx.blockchain.usage":   "La blockchain de la transacción a obtener",
+		"tx.id.usage":           "El id de la transacción a obtener",
+		"tx.public_key.usage":   "La clave pública del emisor en hex, activa la verificación de la firma",
+		"tx.fetch.flags":        "--node y --blockchain son obligatorios para obtener una transacción por id",
+		"tx.read":               "error al leer el archivo de transacción: %w",
+		"tx.invalid_json":       "JSON de transacción no válido: %s",
+		"tx.transaction":        "Transacción",
+		"tx.blockchain":         "Blockchain",
+		"tx.type":               "Tipo",
+		"tx.status":             "Estado",
+		"tx.output":             "Resultado",
+		"tx.block_height":       "Altura del bloque",
+		"tx.from":               "De",
+		"tx.to":                 "Para",
+		"tx.suggestor":          "Proponente",
+		"tx.key_type":           "Tipo de clave",
+		"tx.version":            "Versión",
+		"tx.sent_at":            "Enviada",
+		"tx.exact_time":         "Hora exacta",
+		"tx.approximate_time":   "Hora aproximada",
+		"tx.memo":               "Memo",
+		"tx.commitment":         "Compromiso",
+		"tx.signature":          "Firma",
+		"tx.problem":            "Problema",
+		"tx.payload":            "Payload",
+		"tx.payload_error":      "Error del payload",
+		"tx.check.skipped":      "no verificada (%s)",
+		"tx.check.valid":        "válida (%s)",
+		"tx.check.invalid":      "NO VÁLIDA (%s)",
+
+		"faucet.usage":            "Herramientas del faucet de testnet",
+		"faucet.request.usage":    "Solicitar tokens de testnet para una dirección",
+		"faucet.url.usage":        "La dirección del faucet",
+		"faucet.blockchain.usage": "La blockchain en la que financiar la dirección",
+		"faucet.address.usage":    "La dirección de la billetera a financiar",
+		"faucet.sent":             "Se enviaron %d del token %s en la transacción %s",
+		"faucet.next":             "Próxima solicitud permitida a partir de %s",
+
+		"bench.usage":               "Herramientas de benchmarks",
+		"bench.compare.usage":       "Comparar dos ejecuciones de go test -bench y fallar ante regresiones",
+		"bench.compare.description": "Ambos archivos contienen la salida de go test -bench, se recomienda -count mayor que 1. Un\nbenchmark empeora cuando su tiempo mediano por operación crece más de --threshold por ciento\no reserva memoria más a menudo.",
+		"bench.threshold.usage":     "La ralentización permitida en porcentaje",
+		"bench.compare.args":        "se esperaban los archivos de salida de benchmarks antiguo y nuevo",
+		"bench.compare.regressed":   "%d benchmarks empeoraron más de un %.1f%%",
+		"bench.compare.read":        "error al leer la salida de benchmarks: %w",
+
+		"wallet.usage":              "Herramientas de billeteras",
+		"wallet.health.usage":       "Comparar los archivos de billetera de una carpeta con sus registros",
+		"wallet.health.description": "Informa de los archivos .ukey cuya billetera no está registrada, está deshabilitada, o está\nregistrada con otros grupos de autorización u otra clave que el archivo. Con --fix se muestran\nlas transacciones que alinean la cadena con los archivos como entradas JSON, una por línea, para\nrevisarlas, firmarlas y enviarlas.",
+		"wallet.blockchain.usage":   "La blockchain en la que están registradas las billeteras",
+		"wallet.fix.usage":          "Mostrar las entradas de las transacciones de corrección",
+		"wallet.health.directory":   "la carpeta de billeteras es obligatoria",
+		"wallet.health.unhealthy":   "%d de %d billeteras no están en buen estado",
+		"wallet.health.healthy":     "en buen estado",
+		"wallet.health.file":        "ARCHIVO",
+		"wallet.health.address":     "DIRECCIÓN",
+		"wallet.health.status":      "ESTADO",
+		"wallet.health.detail":      "DETALLE",
+
+		"wallet.state.usage":   "Mostrar el estado, el padre y los grupos de autorización con los que está registrada una billetera",
+		"wallet.state.address": "la dirección de la billetera es obligatoria",
+		"wallet.state.enabled": "HABILITADA",
+		"wallet.state.parent":  "PADRE",
+		"wallet.state.group":   "GRUPO DE AUTORIZACIÓN",
+		"wallet.state.create":  "CREAR",
+		"wallet.state.read":    "LEER",
+		"wallet.state.update":  "ACTUALIZAR",
+		"wallet.state.delete":  "ELIMINAR",
+
+		"contract.usage":            "Herramientas de contratos inteligentes",
+		"contract.bind.usage":       "Generar un binding de Go tipado de un contrato a partir de su manifiesto",
+		"contract.bind.description": "Lee el manifiesto JSON de las funciones del contrato y escribe un archivo Go con un método por\nfunción, que codifica los argumentos y fija los límites de gas. El archivo se muestra salvo que\nse indique --out, lo que se adapta a las directivas go:generate.",
+		"contract.package.usage":    "El paquete del archivo generado",
+		"contract.type.usage":       "El nombre del tipo del binding, por defecto el nombre del manifiesto",
+		"contract.out.usage":        "El archivo en el que escribir el binding",
+		"contract.bind.args":        "se esperaba el archivo de manifiesto",
+		"contract.bind.read":        "error al leer el manifiesto del contrato: %w",
+
+		"gateway.usage":                "Herramientas de gateways REST",
+		"gateway.generate.usage":       "Generar los handlers REST y la definición OpenAPI de un gateway que reenvía operaciones del SDK",
+		"gateway.generate.description": "Escribe un archivo Go con la interfaz Service de las operaciones del SDK, un UnimplementedService\ny los handlers que las sirven. Con --openapi también se escribe la definición OpenAPI de las\nmismas operaciones. Ambos siguen los structs de pkg/transaction, regenérelos con go:generate.",
+		"gateway.package.usage":        "El paquete del archivo generado",
+		"gateway.out.usage":            "El archivo en el que escribir los handlers, se muestran si está vacío",
+		"gateway.openapi.usage":        "El archivo en el que escribir la definición OpenAPI",
+		"gateway.title.usage":          "El título de la definición OpenAPI",
+		"gateway.version.usage":        "La versión de la definición OpenAPI",
+
+		"vectors.usage":                "Herramientas de vectores de prueba entre SDKs",
+		"vectors.generate.usage":       "Generar la suite de vectores de prueba con la que otros SDKs comprueban sus resultados",
+		"vectors.generate.description": "Escribe la suite JSON de claves, direcciones, compromisos de transacciones, firmas y codificaciones\ndel serializador de todos los tipos, derivada de --seed para que sea la misma en cada ejecución. Las\nfirmas de los tipos de clave aleatorizados cambian entre ejecuciones y se verifican en lugar de compararse.",
+		"vectors.seed.usage":           "La semilla de la que se deriva la entropía de las claves",
+		"vectors.out.usage":            "El archivo en el que escribir la suite, se muestra si está vacío",
+		"vectors.verify.usage":         "Comprobar los resultados de otra implementación contra el SDK de Go",
+		"vectors.verify.description":   "Lee una suite escrita por otro SDK a partir de las entradas de una suite generada, recalcula\ncada resultado y muestra los que difieren.",
+		"vectors.verify.args":          "se esperaba el archivo de la suite",
+		"vectors.verify.read":          "error al leer la suite de vectores de prueba: %w",
+		"vectors.verify.failed":        "%d discrepancias en %d vectores",
+		"vectors.verify.passed":        "%d vectores coinciden",
+	})
+	i18n.Register(i18n.LOCALE_PT, i18n.Messages{
+		"uledger.usage": "Inspecionar e trabalhar com transações da ULedger",
+		"uledger.error": "Erro: %s",
+
+		"tx.usage":              "Ferramentas de transações",
+		"tx.decode.usage":       "Exibir uma visão totalmente decodificada de uma transação",
+		"tx.decode.description": "Lê o JSON da transação de um arquivo, do stdin quando nenhum arquivo ou - é informado, ou o\nobtém de um nó com --node, --blockchain e --id. A raiz do payload é sempre recalculada, a\nassinatura é verificada quando a chave pública do remetente é informada com --public-key.",
+		"tx.node.usage":         "O nó do qual obter a transação",
+		"tx.blockchain.usage":   "A blockchain da transação a obter",
+		"tx.id.usage":           "O id da transação a obter",
+		"tx.public_key.usage":   "A chave pública do remetente em hex, ativa a verificação da assinatura",
+		"tx.fetch.flags":        "--node e --blockchain são obrigatórios para obter uma transação pelo id",
+		"tx.read":               "erro ao ler o arquivo de transação: %w",
+		"tx.invalid_json":       "JSON de transação inválido: %s",
+		"tx.transaction":        "Transação",
+		"tx.blockchain":         "Blockchain",
+		"tx.type":               "Tipo",
+		"tx.status":             "Status",
+		"tx.output":             "Resultado",
+		"tx.block_height":       "Altura do bloco",
+		"tx.from":               "De",
+		"tx.to":                 "Para",
+		"tx.suggestor":          "Proponente",
+		"tx.key_type":           "Tipo de chave",
+		"tx.version":            "Versão",
+		"tx.sent_at":            "Enviada em",
+		"tx.exact_time":         "Hora exata",
+		"tx.approximate_time":   "Hora aproximada",
+		"tx.memo":               "Memo",
+		"tx.commitment":         "Compromisso",
+		"tx.signature":          "Assinatura",
+		"tx.problem":            "Problema",
+		"tx.payload":            "Payload",
+		"tx.payload_error":      "Erro do payload",
+		"tx.check.skipped":      "não verificada (%s)",
+		"tx.check.valid":        "válida (%s)",
+		"tx.check.invalid":      "INVÁLIDA (%s)",
+
+		"faucet.usage":            "Ferramentas do faucet da testnet",
+		"faucet.request.usage":    "Solicitar tokens da testnet para um endereço",
+		"faucet.url.usage":        "O endereço do faucet",
+		"faucet.blockchain.usage": "A blockchain na qual financiar o endereço",
+		"faucet.address.usage":    "O endereço da carteira a financiar",
+		"faucet.sent":             "Enviados %d do token %s na transação %s",
+		"faucet.next":             "Próxima solicitação permitida a partir de %s",
+
+		"bench.usage":               "Ferramentas de benchmarks",
+		"bench.compare.usage":       "Comparar duas execuções de go test -bench e falhar em caso de regressões",
+		"bench.compare.description": "Ambos os arquivos contêm a saída de go test -bench, recomenda-se -count maior que 1. Um\nbenchmark regride quando seu tempo mediano por operação cresce mais de --threshold por cento\nou aloca memória com mais frequência.",
+		"bench.threshold.usage":     "A lentidão permitida em porcentagem",
+		"bench.compare.args":        "eram esperados os arquivos de saída de benchmarks antigo e novo",
+		"bench.compare.regressed":   "%d benchmarks regrediram mais de %.1f%%",
+		"bench.compare.read":        "erro ao ler a saída de benchmarks: %w",
+
+		"wallet.usage":              "Ferramentas de carteiras",
+		"wallet.health.usage":       "Comparar os arquivos de carteira de uma pasta com seus registros",
+		"wallet.health.description": "Relata os arquivos .ukey cuja carteira não está registrada, está desativada, ou está registrada\ncom outros grupos de autorização ou outra chave que a do arquivo. Com --fix as transações que\nalinham a cadeia com os arquivos são exibidas como entradas JSON, uma por linha, para serem\nrevisadas, assinadas e enviadas.",
+		"wallet.blockchain.usage":   "A blockchain na qual as carteiras estão registradas",
+		"wallet.fix.usage":          "Exibir as entradas das transações de correção",
+		"wallet.health.directory":   "a pasta de carteiras é obrigatória",
+		"wallet.health.unhealthy":   "%d de %d carteiras não estão saudáveis",
+		"wallet.health.healthy":     "saudável",
+		"wallet.health.file":        "ARQUIVO",
+		"wallet.health.address":     "ENDEREÇO",
+		"wallet.health.status":      "STATUS",
+		"wallet.health.detail":      "DETALHE",
+
+		"wallet.state.usage":   "Exibir o estado, o pai e os grupos de autorização com os quais uma carteira está registrada",
+		"wallet.state.address": "o endereço da carteira é obrigatório",
+		"wallet.state.enabled": "ATIVADA",
+		"wallet.state.parent":  "PAI",
+		"wallet.state.group":   "GRUPO DE AUTORIZAÇÃO",
+		"wallet.state.create":  "CRIAR",
+		"wallet.state.read":    "LER",
+		"wallet.state.update":  "ATUALIZAR",
+		"wallet.state.delete":  "EXCLUIR",
+
+		"contract.usage":            "Ferramentas de contratos inteligentes",
+		"contract.bind.usage":       "Gerar um binding Go tipado de um contrato a partir do seu manifesto",
+		"contract.bind.description": "Lê o manifesto JSON das funções do contrato e escreve um arquivo Go com um método por função,\nque codifica os argumentos e define os limites de gás. O arquivo é exibido a menos que --out\nseja informado, o que se adapta às diretivas go:generate.",
+		"contract.package.usage":    "O pacote do arquivo gerado",
+		"contract.type.usage":       "O nome do tipo do binding, por padrão o nome do manifesto",
+		"contract.out.usage":        "O arquivo no qual escrever o binding",
+		"contract.bind.args":        "era esperado o arquivo de manifesto",
+		"contract.bind.read":        "erro ao ler o manifesto do contrato: %w",
+
+		"gateway.usage":                "Ferramentas de gateways REST",
+		"gateway.generate.usage":       "Gerar os handlers REST e a definição OpenAPI de um gateway que encaminha operações do SDK",
+		"gateway.generate.description": "Escreve um arquivo Go com a interface Service das operações do SDK, um UnimplementedService e\nos handlers que as servem. Com --openapi a definição OpenAPI das mesmas operações também é\nescrita. Ambos seguem as structs de pkg/transaction, regenere-os com go:generate.",
+		"gateway.package.usage":        "O pacote do arquivo gerado",
+		"gateway.out.usage":            "O arquivo no qual escrever os handlers, exibidos quando vazio",
+		"gateway.openapi.usage":        "O arquivo no qual escrever a definição OpenAPI",
+		"gateway.title.usage":          "O título da definição OpenAPI",
+		"gateway.version.usage":        "A versão da definição OpenAPI",
+
+		"vectors.usage":                "Ferramentas de vetores de teste entre SDKs",
+		"vectors.generate.usage":       "Gerar a suíte de vetores de teste com a qual outros SDKs verificam seus resultados",
+		"vectors.generate.description": "Escreve a suíte JSON de chaves, endereços, compromissos de transações, assinaturas e codificações\ndo serializador de todos os tipos, derivada de --seed para que seja a mesma em cada execução. As\nassinaturas dos tipos de chave aleatorizados mudam entre execuções e são verificadas em vez de comparadas.",
+		"vectors.seed.usage":           "A semente da qual a entropia das chaves é derivada",
+		"vectors.out.usage":            "O arquivo no qual escrever a suíte, exibida quando vazio",
+		"vectors.verify.usage":         "Verificar os resultados de outra implementação contra o SDK de Go",
+		"vectors.verify.description":   "Lê uma suíte escrita por outro SDK a partir das entradas de uma suíte gerada, recalcula cada\nresultado e exibe os que diferem.",
+		"vectors.verify.args":          "era esperado o arquivo da suíte",
+		"vectors.verify.read":          "erro ao ler a suíte de vetores de teste: %w",
+		"vectors.verify.failed":        "%d divergências em %d vetores",
+		"vectors.verify.passed":        "%d vetores coincidem",
+	})
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	ulcli "github.com/ULedgerInc/go-sdk/pkg/cli"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/urfave/cli/v3"
+)
+
+func txCommand() *cli.Command {
+	output := &ulcli.OutputFormatter{}
+	return &cli.Command{
+		Name:  "tx",
+		Usage: i18n.T("tx.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "decode",
+				Usage:       i18n.T("tx.decode.usage"),
+				ArgsUsage:   "[file|-]",
+				Description: i18n.T("tx.decode.description"),
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{Name: "node", Aliases: []string{"n"}, Usage: i18n.T("tx.node.usage")},
+					&cli.StringFlag{Name: "blockchain", Aliases: []string{"b"}, Usage: i18n.T("tx.blockchain.usage")},
+					&cli.StringFlag{Name: "id", Usage: i18n.T("tx.id.usage")},
+					&cli.StringFlag{Name: "public-key", Aliases: []string{"k"}, Usage: i18n.T("tx.public_key.usage")},
+				}, output.Flags()...),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					output.Writer = cmd.Root().Writer
+					return decodeAction(ctx, cmd, output)
+				},
+			},
+		},
+	}
+}
+
+func decodeAction(ctx context.Context, cmd *cli.Command, output *ulcli.OutputFormatter) error {
+	tx, err := loadTransaction(ctx, cmd)
+	if err != nil {
+		return err
+	}
+	decoded := transaction.DecodeTransaction(tx, cmd.String("public-key"))
+	return output.Print(decoded, func(w io.Writer) error {
+		return printDecoded(w, decoded)
+	})
+}
+
+func loadTransaction(ctx context.Context, cmd *cli.Command) (transaction.ULTransaction, error) {
+	tx := transaction.ULTransaction{}
+	if id := cmd.String("id"); id != "" {
+		if cmd.String("node") == "" || cmd.String("blockchain") == "" {
+			return tx, i18n.Errorf("tx.fetch.flags")
+		}
+		factory := &ulcli.SessionFactory{Node: cmd.String("node")}
+		session, err := factory.Reader()
+		if err != nil {
+			return tx, err
+		}
+		return session.GetTransaction(ctx, cmd.String("blockchain"), id)
+	}
+
+	var reader io.Reader = os.Stdin
+	if path := cmd.Args().First(); path != "" && path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return tx, i18n.Errorf("tx.read", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+	if err := json.NewDecoder(reader).Decode(&tx); err != nil {
+		return tx, i18n.Errorf("tx.invalid_json", utils.HandleJsonError(err))
+	}
+	return tx, nil
+}
+
+func printDecoded(out io.Writer, decoded transaction.DecodedTransaction) error {
+	table := ulcli.NewTable(out)
+	row := func(label string, value any) {
+		table.Row(i18n.T(label)+":", value)
+	}
+	row("tx.transaction", decoded.TransactionId)
+	row("tx.blockchain", decoded.BlockchainId)
+	row("tx.type", decoded.PayloadType)
+	row("tx.status", decoded.Status)
+	row("tx.output", decoded.Output)
+	row("tx.block_height", decoded.BlockHeight)
+	row("tx.from", decoded.From)
+	row("tx.to", decoded.To)
+	row("tx.suggestor", decoded.Suggestor)
+	row("tx.key_type", decoded.KeyType)
+	row("tx.version", decoded.Version)
+	row("tx.sent_at", formatTime(decoded.SenderTimestamp))
+	row("tx.exact_time", formatTime(decoded.ExactTime))
+	row("tx.approximate_time", formatTime(decoded.ApproximateTime))
+	if decoded.Memo != "" {
+		row("tx.memo", decoded.Memo)
+	}
+	row("tx.commitment", formatCheck(decoded.Commitment))
+	row("tx.signature", formatCheck(decoded.Signature))
+	for _, problem := range decoded.Problems {
+		row("tx.problem", problem)
+	}
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	payload, err := json.MarshalIndent(decoded.Payload, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s:\n%s\n", i18n.T("tx.payload"), payload)
+	if decoded.PayloadError != "" {
+		fmt.Fprintf(out, "%s: %s\n", i18n.T("tx.payload_error"), decoded.PayloadError)
+	}
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+func formatCheck(check transaction.CheckResult) string {
+	switch {
+	case !check.Checked:
+		return i18n.T("tx.check.skipped", check.Detail)
+	case check.Valid:
+		return i18n.T("tx.check.valid", check.Detail)
+	default:
+		return i18n.T("tx.check.invalid", check.Detail)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/vectors"
+	"github.com/urfave/cli/v3"
+)
+
+func vectorsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "vectors",
+		Usage: i18n.T("vectors.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "generate",
+				Usage:       i18n.T("vectors.generate.usage"),
+				Description: i18n.T("vectors.generate.description"),
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "seed", Usage: i18n.T("vectors.seed.usage"), Value: vectors.DEFAULT_SEED},
+					&cli.StringFlag{Name: "out", Aliases: []string{"o"}, Usage: i18n.T("vectors.out.usage")},
+				},
+				Action: generateVectorsAction,
+			},
+			{
+				Name:        "verify",
+				Usage:       i18n.T("vectors.verify.usage"),
+				ArgsUsage:   "<suite>",
+				Description: i18n.T("vectors.verify.description"),
+				Action:      verifyVectorsAction,
+			},
+		},
+	}
+}
+
+func generateVectorsAction(ctx context.Context, cmd *cli.Command) error {
+	suite, err := vectors.Generate(cmd.String("seed"))
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if out := cmd.String("out"); out != "" {
+		return os.WriteFile(out, data, 0644)
+	}
+	_, err = cmd.Root().Writer.Write(data)
+	return err
+}
+
+func verifyVectorsAction(ctx context.Context, cmd *cli.Command) error {
+	if cmd.Args().Len() != 1 {
+		return i18n.Errorf("vectors.verify.args")
+	}
+	path := cmd.Args().First()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return i18n.Errorf("vectors.verify.read", err)
+	}
+	suite := vectors.Suite{}
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	mismatches, err := vectors.Verify(suite)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for _, mismatch := range mismatches {
+		fmt.Fprintln(cmd.Root().Writer, mismatch)
+	}
+	total := len(suite.Keys) + len(suite.Commitments) + len(suite.Serializer)
+	if len(mismatches) > 0 {
+		return i18n.Errorf("vectors.verify.failed", len(mismatches), total)
+	}
+	fmt.Fprintln(cmd.Root().Writer, i18n.T("vectors.verify.passed", total))
+	return nil
+}
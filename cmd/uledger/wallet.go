@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	ulcli "github.com/ULedgerInc/go-sdk/pkg/cli"
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/urfave/cli/v3"
+)
+
+func walletCommand() *cli.Command {
+	factory := &ulcli.SessionFactory{}
+	output := &ulcli.OutputFormatter{}
+	return &cli.Command{
+		Name:  "wallet",
+		Usage: i18n.T("wallet.usage"),
+		Commands: []*cli.Command{
+			{
+				Name:        "health",
+				Usage:       i18n.T("wallet.health.usage"),
+				ArgsUsage:   "<directory>",
+				Description: i18n.T("wallet.health.description"),
+				Flags: slices.Concat(factory.Flags(), output.Flags(), []cli.Flag{
+					&cli.StringFlag{Name: "blockchain", Aliases: []string{"b"}, Usage: i18n.T("wallet.blockchain.usage"), Required: true},
+					&cli.BoolFlag{Name: "fix", Usage: i18n.T("wallet.fix.usage")},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					output.Writer = cmd.Root().Writer
+					return healthAction(ctx, cmd, factory, output)
+				},
+			},
+			{
+				Name:      "state",
+				Usage:     i18n.T("wallet.state.usage"),
+				ArgsUsage: "<address>",
+				Flags: slices.Concat(factory.Flags(), output.Flags(), []cli.Flag{
+					&cli.StringFlag{Name: "blockchain", Aliases: []string{"b"}, Usage: i18n.T("wallet.blockchain.usage"), Required: true},
+				}),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					output.Writer = cmd.Root().Writer
+					return stateAction(ctx, cmd, factory, output)
+				},
+			},
+		},
+	}
+}
+
+func healthAction(ctx context.Context, cmd *cli.Command, factory *ulcli.SessionFactory, output *ulcli.OutputFormatter) error {
+	dir := cmd.Args().First()
+	if dir == "" {
+		return i18n.Errorf("wallet.health.directory")
+	}
+	session, err := factory.Reader()
+	if err != nil {
+		return err
+	}
+	report, err := transaction.CheckFleet(ctx, session, cmd.String("blockchain"), dir)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Bool("fix") {
+		inputs, err := report.FixInputs()
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(cmd.Root().Writer)
+		for _, input := range inputs {
+			if err := encoder.Encode(input); err != nil {
+				return err
+			}
+		}
+	} else if err := output.PrintList(report, report.Wallets, func(out io.Writer) error {
+		table := ulcli.NewTable(out, i18n.T("wallet.health.file"), i18n.T("wallet.health.address"), i18n.T("wallet.health.status"), i18n.T("wallet.health.detail"))
+		for _, result := range report.Wallets {
+			status := i18n.T("wallet.health.healthy")
+			if !result.Healthy() {
+				issues := make([]string, len(result.Issues))
+				for i, issue := range result.Issues {
+					issues[i] = string(issue)
+				}
+				status = strings.Join(issues, ",")
+			}
+			table.Row(result.Path, result.Address, status, result.Detail)
+		}
+		return table.Flush()
+	}); err != nil {
+		return err
+	}
+	if !report.Healthy() {
+		return i18n.Errorf("wallet.health.unhealthy", report.Unhealthy(), len(report.Wallets))
+	}
+	return nil
+}
+
+func stateAction(ctx context.Context, cmd *cli.Command, factory *ulcli.SessionFactory, output *ulcli.OutputFormatter) error {
+	address := cmd.Args().First()
+	if address == "" {
+		return i18n.Errorf("wallet.state.address")
+	}
+	session, err := factory.Reader()
+	if err != nil {
+		return err
+	}
+	state, err := session.GetWalletState(ctx, cmd.String("blockchain"), address)
+	if err != nil {
+		return err
+	}
+	return output.Print(state, func(out io.Writer) error {
+		table := ulcli.NewTable(out)
+		table.Row(i18n.T("wallet.health.address"), state.Address)
+		table.Row(i18n.T("wallet.state.enabled"), state.Enabled)
+		table.Row(i18n.T("wallet.state.parent"), state.Parent)
+		if err := table.Flush(); err != nil {
+			return err
+		}
+		if len(state.AuthGroups) == 0 {
+			return nil
+		}
+		fmt.Fprintln(out)
+		groups := ulcli.NewTable(out, i18n.T("wallet.state.group"), i18n.T("wallet.state.create"), i18n.T("wallet.state.read"), i18n.T("wallet.state.update"), i18n.T("wallet.state.delete"))
+		for _, group := range state.Groups() {
+			permission := state.AuthGroups[group]
+			groups.Row(group, permission.Create, permission.Read, permission.Update, permission.Delete)
+		}
+		return groups.Flush()
+	})
+}
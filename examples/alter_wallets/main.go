@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
+	"slices"
 
+	ulcli "github.com/ULedgerInc/go-sdk/pkg/cli"
 	"github.com/ULedgerInc/go-sdk/pkg/transaction"
 	"github.com/ULedgerInc/go-sdk/pkg/utils"
 	"github.com/ULedgerInc/go-sdk/pkg/wallet"
@@ -15,13 +15,15 @@ import (
 )
 
 func main() {
-	nodeAddress := ""
-	input := ""
+	resolver := &ulcli.WalletInputResolver{}
+	factory := &ulcli.SessionFactory{}
 	targetAddress := ""
 	blockchainId := ""
-	password := ""
 	auth := make(map[string]wallet.UL_AuthPermission)
 	enabled := true
+	rollback := false
+	continueOnError := false
+	reportPath := ""
 
 	command := &cli.Command{
 		Name:                  "Generate Wallet",
@@ -31,45 +33,7 @@ func main() {
 			// Prevent help menu from being shown be default even when flags are present that are not the help flag
 			return nil
 		},
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:     "node",
-				Aliases:  []string{"n"},
-				Usage:    "The node endpoint address",
-				Required: true,
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					if str == "" {
-						return fmt.Errorf("node address cannot be empty")
-					}
-					nodeAddress = str
-					return nil
-				},
-			},
-			&cli.StringFlag{
-				Name:        "input",
-				Aliases:     []string{"i"},
-				Usage:       "The path to the folder containing the wallets, or the json string of a single wallet",
-				Value:       "./wallets",
-				DefaultText: "./wallets",
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					if str == "" {
-						return fmt.Errorf("input cannot be empty")
-					}
-					input = str
-					return nil
-				},
-			},
-			&cli.StringFlag{
-				Name:        "password",
-				Aliases:     []string{"p"},
-				Usage:       "The password to decrypt the wallets",
-				Value:       "",
-				DefaultText: "",
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					password = str
-					return nil
-				},
-			},
+		Flags: slices.Concat(resolver.Flags(), factory.Flags(), []cli.Flag{
 			&cli.StringFlag{
 				Name:        "blockchain",
 				Aliases:     []string{"b"},
@@ -89,11 +53,7 @@ func main() {
 				Aliases:     []string{"t"},
 				Usage:       "The target address to alter (if empty, will use the wallet's own address)",
 				DefaultText: "",
-				Required:    true,
 				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					if str == "" {
-						return fmt.Errorf("target address cannot be empty")
-					}
 					targetAddress = str
 					return nil
 				},
@@ -122,106 +82,82 @@ func main() {
 					return nil
 				},
 			},
-		},
+			&cli.BoolFlag{
+				Name:    "rollback",
+				Aliases: []string{"r"},
+				Usage:   "Restore the previous state of every altered wallet when one alteration fails",
+				Action: func(ctx context.Context, cmd *cli.Command, val bool) error {
+					rollback = val
+					return nil
+				},
+			},
+			&cli.BoolFlag{
+				Name:  "continue",
+				Usage: "Keep altering the remaining wallets after a failure (ignored with --rollback)",
+				Action: func(ctx context.Context, cmd *cli.Command, val bool) error {
+					continueOnError = val
+					return nil
+				},
+			},
+			&cli.StringFlag{
+				Name:  "report",
+				Usage: "Write the JSON report of the run to this file",
+				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
+					reportPath = str
+					return nil
+				},
+			},
+		}),
 		After: func(ctx context.Context, cmd *cli.Command) error {
-			rawWallets := make([]string, 0)
-
-			// Determine if input is a folder or a json string
-			if strings.Contains(input, "{") && strings.Contains(input, "}") {
-				rawWallets = append(rawWallets, input)
-			} else {
-				allWallets := false
-				// Parse if this is getting all of the wallets or a specific one
-				dot := strings.LastIndex(input, ".")
-				if dot > 0 {
-					// Get the character before the dot, if its a * then we are getting all wallets
-					if input[dot-1] == '*' {
-						allWallets = true
-						input = input[:dot-1] // Remove the *.json from the input
-					}
-				}
-				if allWallets {
-					// Get the number of files in the folder
-					files, err := os.ReadDir(input)
-					if err != nil {
-						return fmt.Errorf("error reading folder: %w", err)
-					}
-					if len(files) == 0 {
-						return fmt.Errorf("no files found in the specified folder")
-					}
-					for _, file := range files {
-						if file.IsDir() {
-							continue // Skip directories
-						}
-						content, err := os.ReadFile(filepath.Join(input, file.Name()))
-						if err != nil {
-							return fmt.Errorf("error reading wallet file: %w", err)
-						}
-						rawWallets = append(rawWallets, string(content))
-					}
-				} else {
-					// Just get the single wallet file
-					content, err := os.ReadFile(input)
-					if err != nil {
-						return fmt.Errorf("error reading wallet file: %w", err)
-					}
-					rawWallets = append(rawWallets, string(content))
-				}
-			}
-			if len(rawWallets) == 0 {
-				return fmt.Errorf("no wallets found in the specified input")
+			wallets, err := resolver.Load()
+			if err != nil {
+				return err
 			}
 
-			for _, rawWallet := range rawWallets {
-				// Parse the wallet
-				w, err := wallet.FromJson(rawWallet, password)
+			// Every wallet is loaded and connected before anything is submitted
+			alterations := make([]transaction.WalletAlteration, 0, len(wallets))
+			for _, w := range wallets {
+				session, err := factory.Session(w)
 				if err != nil {
-					panic(fmt.Sprintf("Error parsing wallet from JSON: %s\n", err))
+					return err
 				}
 
-				type UL_AlterWalletPaylod struct {
-					Target     string                              `json:"target"`
-					Enabled    bool                                `json:"enabled"`
-					AuthGroups map[string]wallet.UL_AuthPermission `json:"authGroups"`
+				// empty target should use the wallet's own address as a self alter
+				target := targetAddress
+				if target == "" {
+					target = w.Address
 				}
-
-				payload, err := json.Marshal(UL_AlterWalletPaylod{
-					Target:     targetAddress,
+				alterations = append(alterations, transaction.WalletAlteration{
+					Session:    session,
+					Target:     target,
 					Enabled:    enabled,
 					AuthGroups: auth,
 				})
-				if err != nil {
-					return fmt.Errorf("error marshalling payload: %w", err)
-				}
-
-				// empty to should use the wallet's own address as a self alter
-				to := targetAddress
-				if to == "" {
-					to = w.Address
-				}
-				input := transaction.ULTransactionInput{
-					Payload:      string(payload),
-					From:         w.Address,
-					To:           to,
-					BlockchainId: blockchainId,
-					PayloadType:  transaction.TX_ALTER_WALLET.String(),
-				}
-
-				session, err := transaction.NewUL_TransactionSession(nodeAddress, *w)
-				if err != nil {
-					return fmt.Errorf("error creating transaction session: %w", err)
-				}
+			}
 
-				transaction, err := session.GenerateTransaction(input)
-				if err != nil {
-					return fmt.Errorf("error generating transaction: %w", err)
+			report, err := transaction.BulkAlterWallets(ctx, blockchainId, alterations, transaction.BulkAlterOptions{
+				Rollback:        rollback,
+				ContinueOnError: continueOnError,
+			})
+			for _, result := range report.Results {
+				fmt.Printf("%s %s %s %s\n", result.Status, result.Target, result.TransactionId, result.Error)
+			}
+			fmt.Printf("Applied %d, failed %d, rolled back %d, skipped %d\n",
+				report.Count(transaction.ALTER_STATUS_APPLIED),
+				report.Count(transaction.ALTER_STATUS_FAILED),
+				report.Count(transaction.ALTER_STATUS_ROLLED_BACK),
+				report.Count(transaction.ALTER_STATUS_SKIPPED))
+			if reportPath != "" {
+				content, marshalErr := json.MarshalIndent(report, "", "  ")
+				if marshalErr != nil {
+					return fmt.Errorf("error marshalling report: %w", marshalErr)
 				}
-
-				if transaction.TransactionId == "" {
-					return fmt.Errorf("generated transaction has empty transaction ID")
+				if writeErr := os.WriteFile(reportPath, content, 0644); writeErr != nil {
+					return fmt.Errorf("error writing report: %w", writeErr)
 				}
-
-				fmt.Printf("Transaction: %+v\n", transaction)
+			}
+			if err != nil {
+				return fmt.Errorf("bulk alter failed: %w", err)
 			}
 
 			// Prevent help menu from being shown be default even when flags are present that are not the help flag
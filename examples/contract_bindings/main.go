@@ -0,0 +1,56 @@
+package main
+
+//go:generate go run github.com/ULedgerInc/go-sdk/cmd/uledger contract bind --package token --out token/token.go token/token.json
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/examples/contract_bindings/token"
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func main() {
+	privateKeyHex := "46871FC92D83F41BEC1BE9C820BEBAF1DF906CDA4E11A5E66784B09C3C6B1F76"
+	// Uncompressed public key
+	publicKeyHex := "042D14822C75648ACCC0E44BAE5312D11000351A302AE047A2D0B55984F6D9D392178B12427749ACB67E3A15F4C0EBDD23BE7DBCFAC82826A5FD3055F81B4ACC82"
+	wallet, err := wallet.GetWalletFromHex(publicKeyHex, privateKeyHex, crypto.KeyTypeSecp256k1)
+	if err != nil {
+		fmt.Printf("GetWalletFromPrivateKey() error = %v", err)
+		return
+	}
+
+	// Make sure the node is running!
+	testNodeEndpoint := os.Args[1] // "https://node.testnet.uledger.com"
+	blockchainId := os.Args[2]     // "Testnet"
+	contractAddress := os.Args[3]
+
+	session, err := transaction.NewUL_TransactionSession(testNodeEndpoint, wallet)
+	if err != nil {
+		fmt.Printf("NewUL_TransactionSession() error = %v\n", err)
+		return
+	}
+
+	// The binding encodes the arguments and sets the gas limits of token/token.json
+	contract := token.NewToken(&session, contractAddress)
+	contract.BlockchainId = blockchainId
+	ctx := context.Background()
+	tx, err := contract.Transfer(ctx, "c99d74279e6b5d17aa21fe99a1e9021a731ec9945c9eb294a9095529151759de", 1000)
+	if err != nil {
+		fmt.Printf("Transfer() error = %v\n", err)
+		return
+	}
+
+	fmt.Printf("Transaction: %+v\n", tx)
+
+	// View functions are called without a transaction
+	balance, err := contract.BalanceOf(ctx, wallet.Address)
+	if err != nil {
+		fmt.Printf("BalanceOf() error = %v\n", err)
+		return
+	}
+	fmt.Printf("Balance: %d\n", balance)
+}
@@ -0,0 +1,44 @@
+// Code generated by uledger contract bind. DO NOT EDIT.
+
+package token
+
+import (
+	"context"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// Token binds the functions of the Token contract
+type Token struct {
+	*transaction.ContractClient
+}
+
+// NewToken returns a binding of the Token contract deployed at contractAddress
+func NewToken(session *transaction.UL_TransactionSession, contractAddress string) *Token {
+	return &Token{ContractClient: transaction.NewContractClient(session, contractAddress)}
+}
+
+// Initialize invokes initialize with a gas limit of 100000 unless GasLimit is set
+func (c *Token) Initialize(ctx context.Context, initialSupply int32) (transaction.ULTransaction, error) {
+	return c.Invoke(ctx, "initialize", 100000, initialSupply)
+}
+
+// Transfer invokes transfer with a gas limit of 150000 unless GasLimit is set
+func (c *Token) Transfer(ctx context.Context, to string, amount int32) (transaction.ULTransaction, error) {
+	return c.Invoke(ctx, "transfer", 150000, to, amount)
+}
+
+// TransferBatch invokes transferBatch with a gas limit of 500000 unless GasLimit is set
+func (c *Token) TransferBatch(ctx context.Context, recipients []string, amounts []int32) (transaction.ULTransaction, error) {
+	return c.Invoke(ctx, "transferBatch", 500000, recipients, amounts)
+}
+
+// BalanceOf calls the view function balanceOf without a transaction
+func (c *Token) BalanceOf(ctx context.Context, owner string) (int32, error) {
+	return transaction.CallContractAs[int32](ctx, c.ContractClient, "balanceOf", owner)
+}
+
+// Emit invokes emit with a gas limit of 100000 unless GasLimit is set
+func (c *Token) Emit(ctx context.Context) (transaction.ULTransaction, error) {
+	return c.Invoke(ctx, "emit", 100000)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -13,7 +14,7 @@ import (
 func main() {
 	nodeEndpoint := os.Args[1] // "https://node.testnet.uledger.com"
 	blockchainId := os.Args[2] // "Testnet"
-	operation := os.Args[3]    // "create", "transfer", "approve", "mint", "burn", "transfer_approval"
+	operation := os.Args[3]    // "create", "transfer", "approve", "mint", "burn", "transfer_approval", "set_approval_for_all", "operator_transfer"
 	tokenAddress := ""         // "0x1234567890123456789012345678901234567890"
 
 	privateKeyHex := "46871FC92D83F41BEC1BE9C820BEBAF1DF906CDA4E11A5E66784B09C3C6B1F76"
@@ -39,6 +40,11 @@ func main() {
 
 	amount := uint64(5000)
 
+	if operation == "set_approval_for_all" || operation == "operator_transfer" {
+		runOperator(nodeEndpoint, blockchainId, operation, os.Args[4], firstWallet, secondWallet)
+		return
+	}
+
 	input := transaction.ULTransactionInput{
 		From:         sourceWallet.Address,
 		BlockchainId: blockchainId,
@@ -158,6 +164,48 @@ func main() {
 	}
 }
 
+// runOperator shows the operator flow: the first wallet approves the second for all of its tokens,
+// which then moves them in one batch without a per token allowance
+func runOperator(nodeEndpoint string, blockchainId string, operation string, tokenAddress string, owner wallet.UL_Wallet, operator wallet.UL_Wallet) {
+	switch operation {
+	case "set_approval_for_all":
+		session, err := transaction.NewUL_TransactionSession(nodeEndpoint, owner)
+		if err != nil {
+			fmt.Printf("NewUL_TransactionSession() error = %v\n", err)
+			return
+		}
+		tx, err := session.SetApprovalForAll(context.Background(), blockchainId, tokenAddress, operator.Address, true)
+		if err != nil {
+			fmt.Printf("SetApprovalForAll() error = %v\n", err)
+			return
+		}
+		fmt.Printf("Operator %s approved for ERC1155 Token with transaction id: %s \n %+v\n", operator.Address, tx.TransactionId, tx)
+
+	case "operator_transfer":
+		session, err := transaction.NewUL_TransactionSession(nodeEndpoint, operator)
+		if err != nil {
+			fmt.Printf("NewUL_TransactionSession() error = %v\n", err)
+			return
+		}
+		ctx := context.Background()
+		approved, err := session.IsApprovedForAll(ctx, blockchainId, tokenAddress, owner.Address, operator.Address)
+		if err != nil {
+			fmt.Printf("IsApprovedForAll() error = %v\n", err)
+			return
+		}
+		fmt.Printf("Operator %s approved by %s: %t\n", operator.Address, owner.Address, approved)
+
+		// Not the owner or the operator wallet
+		thirdWalletAddress := "0aa5890b691d2676627874ec20f57882c735e07c86efe64ebab86c46cf9dc53f"
+		tx, err := session.OperatorTransfer(ctx, blockchainId, tokenAddress, owner.Address, thirdWalletAddress, []uint64{0, 1}, []transaction.Amount{transaction.NewAmount(10), transaction.NewAmount(5)})
+		if err != nil {
+			fmt.Printf("OperatorTransfer() error = %v\n", err)
+			return
+		}
+		fmt.Printf("Operator Transfer ERC1155 Token Created with transaction id: %s \n %+v\n", tx.TransactionId, tx)
+	}
+}
+
 func createERC1155Token() ([]byte, error) {
 	payloadBytes, err := json.Marshal(transaction.CreateTokenPayload{
 		TokenType: transaction.ERC1155_TOKEN_TYPE,
@@ -178,7 +226,7 @@ func mintERC1155Token(tokenAddress string, to string) ([]byte, error) {
 		TokenAddress: tokenAddress,
 		To:           to,
 		TokenId:      0,
-		Amount:       1000,
+		Amount:       transaction.NewAmount(1000),
 		TokenURI:     "Ticket URIS!",
 	})
 	if err != nil {
@@ -192,7 +240,7 @@ func transferERC1155Token(tokenAddress string, to string) ([]byte, error) {
 		TokenAddress: tokenAddress,
 		To:           to,
 		TokenId:      1,
-		Amount:       5,
+		Amount:       transaction.NewAmount(5),
 	})
 	if err != nil {
 		return nil, err
@@ -203,7 +251,7 @@ func transferERC1155Token(tokenAddress string, to string) ([]byte, error) {
 func burnERC1155Token(tokenAddress string, amount uint64) ([]byte, error) {
 	payloadBytes, err := json.Marshal(transaction.BurnTokenPayload{
 		TokenAddress: tokenAddress,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 	})
 	if err != nil {
 		return nil, err
@@ -215,7 +263,7 @@ func approveERC1155Token(tokenAddress string, to string, amount uint64) ([]byte,
 	payloadBytes, err := json.Marshal(transaction.ApproveTokenPayload{
 		TokenAddress: tokenAddress,
 		Spender:      to,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 	})
 	if err != nil {
 		return nil, err
@@ -227,7 +275,7 @@ func transferApprovalERC1155Token(tokenAddress string, to string, from string, a
 	payloadBytes, err := json.Marshal(transaction.TransferTokenPayload{
 		TokenAddress: tokenAddress,
 		To:           to,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 		From:         from,
 	})
 	if err != nil {
@@ -240,7 +288,7 @@ func convertERC1155Token(tokenAddress string, fromTokenId uint64, amount uint64)
 	payloadBytes, err := json.Marshal(transaction.ConvertTokenPayload{
 		TokenAddress:   tokenAddress,
 		FromTokenId:    fromTokenId,
-		Amount:         amount,
+		Amount:         transaction.NewAmount(amount),
 		NewTokenURI:    "https://commemorative.example.com/used_ticket",
 		PreserveTokens: false,
 	})
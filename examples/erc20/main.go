@@ -131,12 +131,17 @@ func main() {
 }
 
 func createERC20Token() ([]byte, error) {
+	// One billion whole tokens, far beyond a uint64 once scaled by 18 decimals
+	initialSupply, err := transaction.ParseUnits("1000000000", 18)
+	if err != nil {
+		return nil, err
+	}
 	payloadBytes, err := json.Marshal(transaction.CreateTokenPayload{
 		TokenType:     transaction.ERC20_TOKEN_TYPE,
 		Name:          "ULedger Token Test",
 		Symbol:        "ULTT",
 		Decimals:      18,
-		InitialSupply: 1000000000000000000,
+		InitialSupply: initialSupply,
 		Mintable:      true,
 		Burnable:      true,
 	})
@@ -150,7 +155,7 @@ func transferERC20Token(tokenAddress string, to string, amount uint64) ([]byte,
 	payloadBytes, err := json.Marshal(transaction.TransferTokenPayload{
 		TokenAddress: tokenAddress,
 		To:           to,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 	})
 	if err != nil {
 		return nil, err
@@ -173,7 +178,7 @@ func approveERC20Token(tokenAddress string, to string, amount uint64) ([]byte, e
 	payloadBytes, err := json.Marshal(transaction.ApproveTokenPayload{
 		TokenAddress: tokenAddress,
 		Spender:      to,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 	})
 	if err != nil {
 		return nil, err
@@ -185,7 +190,7 @@ func transferApprovalERC20Token(tokenAddress string, to string, from string, amo
 	payloadBytes, err := json.Marshal(transaction.TransferTokenPayload{
 		TokenAddress: tokenAddress,
 		To:           to,
-		Amount:       amount,
+		Amount:       transaction.NewAmount(amount),
 		From:         from,
 	})
 	if err != nil {
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/explorer"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func main() {
+	// Make sure the node is running!
+	nodeEndpoint := os.Args[1] // "https://node.testnet.uledger.com"
+	address := os.Args[2]      // ":8080"
+
+	// The explorer only reads, the session needs no key
+	session, err := transaction.NewUL_TransactionSession(nodeEndpoint, wallet.UL_Wallet{})
+	if err != nil {
+		fmt.Printf("NewUL_TransactionSession() error = %v\n", err)
+		return
+	}
+
+	fmt.Printf("Serving the explorer of %s on %s, e.g. GET /chains/{blockchainId}/blocks/latest\n", nodeEndpoint, address)
+	if err := http.ListenAndServe(address, explorer.NewHandler(&session, explorer.Options{})); err != nil {
+		fmt.Printf("ListenAndServe() error = %v\n", err)
+	}
+}
@@ -0,0 +1,76 @@
+// Code generated by uledger gateway generate. DO NOT EDIT.
+
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/ULedgerInc/go-sdk/pkg/gateway"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// Service implements the operations of the gateway, gateway.SessionService proxies the default
+// operations to a node
+type Service interface {
+	// Submit a transaction signed by the client
+	SubmitTransaction(ctx context.Context, blockchainId string, request transaction.ULTransactionInput) (transaction.ULTransaction, error)
+	// Get a transaction
+	GetTransaction(ctx context.Context, blockchainId string, transactionId string) (transaction.ULTransaction, error)
+	// Get the name, symbol, decimals, supply and owner of a token
+	GetTokenMetadata(ctx context.Context, blockchainId string, tokenAddress string) (transaction.TokenMetadata, error)
+	// Get the balance of a token owner
+	GetBalance(ctx context.Context, blockchainId string, tokenAddress string, owner string) (transaction.Amount, error)
+}
+
+// UnimplementedService answers every operation with utils.ErrUnsupported, services embedding it
+// only implement the operations they serve
+type UnimplementedService struct{}
+
+func (UnimplementedService) SubmitTransaction(ctx context.Context, blockchainId string, request transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	var response transaction.ULTransaction
+	return response, utils.ErrUnsupported
+}
+
+func (UnimplementedService) GetTransaction(ctx context.Context, blockchainId string, transactionId string) (transaction.ULTransaction, error) {
+	var response transaction.ULTransaction
+	return response, utils.ErrUnsupported
+}
+
+func (UnimplementedService) GetTokenMetadata(ctx context.Context, blockchainId string, tokenAddress string) (transaction.TokenMetadata, error) {
+	var response transaction.TokenMetadata
+	return response, utils.ErrUnsupported
+}
+
+func (UnimplementedService) GetBalance(ctx context.Context, blockchainId string, tokenAddress string, owner string) (transaction.Amount, error) {
+	var response transaction.Amount
+	return response, utils.ErrUnsupported
+}
+
+// NewHandler returns the routes of the operations of service
+func NewHandler(service Service) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /blockchains/{blockchainId}/transactions", func(w http.ResponseWriter, r *http.Request) {
+		var request transaction.ULTransactionInput
+		if err := gateway.DecodeRequest(r, &request); err != nil {
+			gateway.WriteResponse(w, nil, err)
+			return
+		}
+		response, err := service.SubmitTransaction(r.Context(), r.PathValue("blockchainId"), request)
+		gateway.WriteResponse(w, response, err)
+	})
+	mux.HandleFunc("GET /blockchains/{blockchainId}/transactions/{transactionId}", func(w http.ResponseWriter, r *http.Request) {
+		response, err := service.GetTransaction(r.Context(), r.PathValue("blockchainId"), r.PathValue("transactionId"))
+		gateway.WriteResponse(w, response, err)
+	})
+	mux.HandleFunc("GET /blockchains/{blockchainId}/tokens/{tokenAddress}", func(w http.ResponseWriter, r *http.Request) {
+		response, err := service.GetTokenMetadata(r.Context(), r.PathValue("blockchainId"), r.PathValue("tokenAddress"))
+		gateway.WriteResponse(w, response, err)
+	})
+	mux.HandleFunc("GET /blockchains/{blockchainId}/tokens/{tokenAddress}/balances/{owner}", func(w http.ResponseWriter, r *http.Request) {
+		response, err := service.GetBalance(r.Context(), r.PathValue("blockchainId"), r.PathValue("tokenAddress"), r.PathValue("owner"))
+		gateway.WriteResponse(w, response, err)
+	})
+	return mux
+}
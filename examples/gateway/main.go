@@ -0,0 +1,35 @@
+package main
+
+//go:generate go run github.com/ULedgerInc/go-sdk/cmd/uledger gateway generate --package api --out api/api.go --openapi api/openapi.json
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/examples/gateway/api"
+	"github.com/ULedgerInc/go-sdk/pkg/gateway"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// The session proxies the operations of the generated Service
+var _ api.Service = (*gateway.SessionService)(nil)
+
+func main() {
+	// Make sure the node is running!
+	nodeEndpoint := os.Args[1] // "https://node.testnet.uledger.com"
+	address := os.Args[2]      // ":8080"
+
+	// Browsers and mobile apps sign their transactions, the gateway only needs to read
+	session, err := transaction.NewUL_TransactionSession(nodeEndpoint, wallet.UL_Wallet{})
+	if err != nil {
+		fmt.Printf("NewUL_TransactionSession() error = %v\n", err)
+		return
+	}
+
+	fmt.Printf("Serving the API of api/openapi.json on %s\n", address)
+	if err := http.ListenAndServe(address, api.NewHandler(gateway.NewSessionService(&session))); err != nil {
+		fmt.Printf("ListenAndServe() error = %v\n", err)
+	}
+}
@@ -114,7 +114,7 @@ func main() {
 			&cli.StringFlag{
 				Name:        "password",
 				Aliases:     []string{"w"},
-				Usage:       "Password to protect the wallet(s)",
+				Usage:       "Password protecting the mnemonic seed and encrypting the wallet file(s)",
 				Value:       "myPassword",
 				DefaultText: "myPassword",
 				Action: func(ctx context.Context, cmd *cli.Command, s string) error {
@@ -123,6 +123,16 @@ func main() {
 					return nil
 				},
 			},
+			&cli.StringFlag{
+				Name:  "seed",
+				Usage: "Derive the wallets from this seed instead of random entropy, for reproducible demos only",
+				Action: func(ctx context.Context, cmd *cli.Command, s string) error {
+					if s != "" {
+						wallet.SetRandomSource(wallet.NewDeterministicSource(sanitizeString(s)))
+					}
+					return nil
+				},
+			},
 			&cli.StringFlag{
 				Name:        "auth",
 				Aliases:     []string{"a"},
@@ -144,6 +154,9 @@ func main() {
 		After: func(ctx context.Context, cmd *cli.Command) error {
 			// Create output directory if it doesn't exist
 			if outputDir != "" {
+				if password == "" {
+					return fmt.Errorf("a password is required to encrypt the wallet files")
+				}
 				outputDir = filepath.Clean(outputDir)
 				outputDir, err := filepath.Abs(outputDir)
 				if err != nil {
@@ -163,9 +176,9 @@ func main() {
 				}
 
 				if outputDir != "" {
-					// Save wallet using address as filename
+					// Save wallet encrypted using address as filename
 					outputPath := filepath.Join(outputDir, myWallet.Address+".ukey")
-					err = myWallet.SaveToFile(outputPath, mnemonic, true)
+					err = myWallet.SaveToFileEncrypted(outputPath, mnemonic, true, password)
 					if err != nil {
 						return fmt.Errorf("error saving wallet to file: %w", err)
 					}
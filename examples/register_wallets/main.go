@@ -5,9 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
+	"slices"
 
+	ulcli "github.com/ULedgerInc/go-sdk/pkg/cli"
 	"github.com/ULedgerInc/go-sdk/pkg/crypto"
 	"github.com/ULedgerInc/go-sdk/pkg/transaction"
 	"github.com/ULedgerInc/go-sdk/pkg/wallet"
@@ -15,10 +15,9 @@ import (
 )
 
 func main() {
-	nodeAddress := ""
-	input := ""
+	resolver := &ulcli.WalletInputResolver{}
+	factory := &ulcli.SessionFactory{}
 	blockchainId := ""
-	password := ""
 
 	command := &cli.Command{
 		Name:                  "Generate Wallet",
@@ -28,45 +27,7 @@ func main() {
 			// Prevent help menu from being shown be default even when flags are present that are not the help flag
 			return nil
 		},
-		Flags: []cli.Flag{
-			&cli.StringFlag{
-				Name:     "node",
-				Aliases:  []string{"n"},
-				Usage:    "The node endpoint address",
-				Required: true,
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					if str == "" {
-						return fmt.Errorf("node address cannot be empty")
-					}
-					nodeAddress = str
-					return nil
-				},
-			},
-			&cli.StringFlag{
-				Name:        "input",
-				Aliases:     []string{"i"},
-				Usage:       "The path to the folder containing the wallets, or the json string of a single wallet",
-				Value:       "./wallets",
-				DefaultText: "./wallets",
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					if str == "" {
-						return fmt.Errorf("input cannot be empty")
-					}
-					input = str
-					return nil
-				},
-			},
-			&cli.StringFlag{
-				Name:        "password",
-				Aliases:     []string{"p"},
-				Usage:       "The password to decrypt the wallets",
-				Value:       "",
-				DefaultText: "",
-				Action: func(ctx context.Context, cmd *cli.Command, str string) error {
-					password = str
-					return nil
-				},
-			},
+		Flags: slices.Concat(resolver.Flags(), factory.Flags(), []cli.Flag{
 			&cli.StringFlag{
 				Name:        "blockchain",
 				Aliases:     []string{"b"},
@@ -81,62 +42,14 @@ func main() {
 					return nil
 				},
 			},
-		},
+		}),
 		After: func(ctx context.Context, cmd *cli.Command) error {
-			rawWallets := make([]string, 0)
-
-			// Determine if input is a folder or a json string
-			if strings.Contains(input, "{") && strings.Contains(input, "}") {
-				rawWallets = append(rawWallets, input)
-			} else {
-				allWallets := false
-				// Parse if this is getting all of the wallets or a specific one
-				dot := strings.LastIndex(input, ".")
-				if dot > 0 {
-					// Get the character before the dot, if its a * then we are getting all wallets
-					if input[dot-1] == '*' {
-						allWallets = true
-						input = input[:dot-1] // Remove the *.json from the input
-					}
-				}
-				if allWallets {
-					// Get the number of files in the folder
-					files, err := os.ReadDir(input)
-					if err != nil {
-						return fmt.Errorf("error reading folder: %w", err)
-					}
-					if len(files) == 0 {
-						return fmt.Errorf("no files found in the specified folder")
-					}
-					for _, file := range files {
-						if file.IsDir() {
-							continue // Skip directories
-						}
-						content, err := os.ReadFile(filepath.Join(input, file.Name()))
-						if err != nil {
-							panic("Error reading file: " + err.Error())
-						}
-						rawWallets = append(rawWallets, string(content))
-					}
-				} else {
-					// Just get the single wallet file
-					content, err := os.ReadFile(input)
-					if err != nil {
-						return fmt.Errorf("error reading wallet file: %w", err)
-					}
-					rawWallets = append(rawWallets, string(content))
-				}
-			}
-			if len(rawWallets) == 0 {
-				return fmt.Errorf("no wallets found in the specified input")
+			wallets, err := resolver.Load()
+			if err != nil {
+				return err
 			}
 
-			for _, rawWallet := range rawWallets {
-				// Parse the w
-				w, err := wallet.FromJson(rawWallet, password)
-				if err != nil {
-					panic(fmt.Sprintf("Error parsing wallet from JSON: %s\n", err))
-				}
+			for _, w := range wallets {
 				fmt.Printf("Parsed wallet: %+v\n", w)
 
 				type UL_CreateWalletPaylod struct {
@@ -165,9 +78,9 @@ func main() {
 					PayloadType:  transaction.TX_CREATE_WALLET.String(),
 				}
 
-				session, err := transaction.NewUL_TransactionSession(nodeAddress, *w)
+				session, err := factory.Session(w)
 				if err != nil {
-					return fmt.Errorf("error creating transaction session: %w", err)
+					return err
 				}
 
 				transaction, err := session.GenerateTransaction(input)
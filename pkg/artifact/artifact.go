@@ -0,0 +1,164 @@
+// Package artifact verifies binaries retrieved through the SDK ecosystem, e.g. a contract fetched
+// from a node or a downloaded plugin, against a signature of their publisher.
+//
+// A publisher describes the artifact in a Manifest and signs it with a wallet message signature.
+// The manifest pins the SHA256 and the size of the artifact, so checking the signature and then
+// the artifact bytes against the manifest proves the bytes are the ones the publisher released.
+package artifact
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// MANIFEST_SCHEMA is signed along with the manifest so a signature over another kind of message
+// can never pass for an artifact signature
+const MANIFEST_SCHEMA = "uledger-artifact/v1"
+
+// DIGEST_PREFIX names the hash function of Manifest.Digest
+const DIGEST_PREFIX = "sha256:"
+
+type Kind string
+
+const (
+	KIND_CONTRACT Kind = "contract"
+	KIND_PLUGIN   Kind = "plugin"
+)
+
+type ErrInvalidArtifact struct {
+	Msg string
+}
+
+func (e *ErrInvalidArtifact) Error() string {
+	return fmt.Sprintf("invalid artifact, %s", e.Msg)
+}
+
+func (e *ErrInvalidArtifact) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrUntrustedPublisher is returned when a valid signature comes from a key the verifier does not trust
+type ErrUntrustedPublisher struct {
+	PublicKey string
+}
+
+func (e *ErrUntrustedPublisher) Error() string {
+	return fmt.Sprintf("artifact signed by untrusted key %s", e.PublicKey)
+}
+
+func (e *ErrUntrustedPublisher) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Manifest describes one released artifact, it is the message the publisher signs
+type Manifest struct {
+	Schema  string    `json:"schema"`
+	Name    string    `json:"name"`
+	Version string    `json:"version"`
+	Kind    Kind      `json:"kind"`
+	Digest  string    `json:"digest"`
+	Size    int64     `json:"size"`
+	Created time.Time `json:"created"`
+}
+
+// SignedManifest is what publishers distribute next to the artifact, usually as a .sig.json file
+type SignedManifest struct {
+	Manifest  Manifest                `json:"manifest"`
+	Signature wallet.MessageSignature `json:"signature"`
+}
+
+// Digest returns the Manifest.Digest of artifact
+func Digest(artifact []byte) string {
+	digest := sha256.Sum256(artifact)
+	return DIGEST_PREFIX + hex.EncodeToString(digest[:])
+}
+
+// NewManifest describes artifact, released now
+func NewManifest(name string, version string, kind Kind, artifact []byte) Manifest {
+	return Manifest{
+		Schema:  MANIFEST_SCHEMA,
+		Name:    name,
+		Version: version,
+		Kind:    kind,
+		Digest:  Digest(artifact),
+		Size:    int64(len(artifact)),
+		Created: time.Now().UTC().Truncate(time.Second),
+	}
+}
+
+// Bytes returns the signed form of the manifest, JSON in field order
+func (manifest Manifest) Bytes() ([]byte, error) {
+	return json.Marshal(manifest)
+}
+
+// Sign signs manifest with the publisher's wallet
+func Sign(publisher *wallet.UL_Wallet, manifest Manifest) (SignedManifest, error) {
+	if manifest.Schema != MANIFEST_SCHEMA {
+		return SignedManifest{}, &ErrInvalidArtifact{Msg: fmt.Sprintf("unsupported manifest schema %q", manifest.Schema)}
+	}
+	if manifest.Name == "" || !strings.HasPrefix(manifest.Digest, DIGEST_PREFIX) {
+		return SignedManifest{}, &ErrInvalidArtifact{Msg: "the manifest needs a name and a sha256 digest"}
+	}
+	message, err := manifest.Bytes()
+	if err != nil {
+		return SignedManifest{}, err
+	}
+	signature, err := publisher.SignMessage(message)
+	if err != nil {
+		return SignedManifest{}, err
+	}
+	return SignedManifest{Manifest: manifest, Signature: signature}, nil
+}
+
+// Check verifies artifact, read to its end, matches the digest and size of the manifest
+func (manifest Manifest) Check(artifact io.Reader) error {
+	hasher := sha256.New()
+	// Never read past the declared size
+	size, err := io.Copy(hasher, io.LimitReader(artifact, manifest.Size+1))
+	if err != nil {
+		return fmt.Errorf("failed to read the artifact: %w", err)
+	}
+	if size != manifest.Size {
+		return &ErrInvalidArtifact{Msg: fmt.Sprintf("%s is not %d bytes long", manifest.Name, manifest.Size)}
+	}
+	if digest := DIGEST_PREFIX + hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(digest, manifest.Digest) {
+		return &ErrInvalidArtifact{Msg: fmt.Sprintf("%s does not match the digest %s", manifest.Name, manifest.Digest)}
+	}
+	return nil
+}
+
+// Publisher is a key trusted to sign artifacts
+type Publisher struct {
+	Name      string         `json:"name"`
+	KeyType   crypto.KeyType `json:"keyType"`
+	PublicKey string         `json:"publicKey"`
+}
+
+// PublisherOf returns the publisher signing with w
+func PublisherOf(name string, w wallet.UL_Wallet) (Publisher, error) {
+	if err := w.CheckKey(); err != nil {
+		return Publisher{}, err
+	}
+	return Publisher{Name: name, KeyType: w.GetKey().GetType(), PublicKey: w.GetKey().GetPublicKeyHex(false)}, nil
+}
+
+// publicKey returns the canonical hex of a public key, so every encoding of a key compares equal
+func publicKey(keyType crypto.KeyType, publicKeyHex string) (string, error) {
+	key, err := crypto.GetKeyByType(keyType, crypto.GetHasherByType(keyType))
+	if err != nil {
+		return "", err
+	}
+	if err := key.GeneratePublicKeyFromHex(false, publicKeyHex); err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	return key.GetPublicKeyHex(false), nil
+}
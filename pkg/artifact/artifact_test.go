@@ -0,0 +1,125 @@
+package artifact_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/artifact"
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func newPublisher(t *testing.T, name string, keyType crypto.KeyType) (wallet.UL_Wallet, artifact.Publisher) {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", keyType, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	publisher, err := artifact.PublisherOf(name, w)
+	if err != nil {
+		t.Fatalf("PublisherOf() error = %v", err)
+	}
+	return w, publisher
+}
+
+func TestVerify(t *testing.T) {
+	plugin := bytes.Repeat([]byte("plugin"), 1000)
+	for _, keyType := range []crypto.KeyType{crypto.KeyTypeSecp256k1, crypto.KeyTypeED25519} {
+		t.Run(keyType.String(), func(t *testing.T) {
+			w, publisher := newPublisher(t, "ULedger", keyType)
+			signed, err := artifact.Sign(&w, artifact.NewManifest("exporter", "1.2.0", artifact.KIND_PLUGIN, plugin))
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			// Manifests travel as JSON files next to the artifact
+			data, _ := json.Marshal(signed)
+			var decoded artifact.SignedManifest
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			verifier, err := artifact.NewVerifier(publisher)
+			if err != nil {
+				t.Fatalf("NewVerifier() error = %v", err)
+			}
+			got, err := verifier.Verify(bytes.NewReader(plugin), decoded)
+			if err != nil || got.Name != "ULedger" {
+				t.Fatalf("Verify() = %+v, %v", got, err)
+			}
+
+			tampered := append(bytes.Clone(plugin[:len(plugin)-1]), '!')
+			if _, err := verifier.Verify(bytes.NewReader(tampered), decoded); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("Verify() of a tampered artifact error = %v", err)
+			}
+			if _, err := verifier.Verify(bytes.NewReader(append(plugin, 0)), decoded); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("Verify() of a longer artifact error = %v", err)
+			}
+			bumped := decoded
+			bumped.Manifest.Version = "1.2.1"
+			if _, err := verifier.VerifyManifest(bumped); !errors.As(err, new(*artifact.ErrInvalidArtifact)) {
+				t.Fatalf("VerifyManifest() of an edited manifest error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyUntrustedPublisher(t *testing.T) {
+	_, trusted := newPublisher(t, "ULedger", crypto.KeyTypeSecp256k1)
+	impostor, _ := newPublisher(t, "impostor", crypto.KeyTypeSecp256k1)
+	verifier, err := artifact.NewVerifier(trusted)
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	plugin := []byte("plugin")
+	signed, _ := artifact.Sign(&impostor, artifact.NewManifest("exporter", "1.2.0", artifact.KIND_PLUGIN, plugin))
+	var untrusted *artifact.ErrUntrustedPublisher
+	if _, err := verifier.Verify(bytes.NewReader(plugin), signed); !errors.As(err, &untrusted) || !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Verify() of an untrusted signature error = %v", err)
+	}
+
+	// Claiming the trusted key does not help without its signature
+	signed.Signature.PublicKey = trusted.PublicKey
+	if _, err := verifier.Verify(bytes.NewReader(plugin), signed); !errors.As(err, new(*artifact.ErrInvalidArtifact)) {
+		t.Fatalf("Verify() with a borrowed public key error = %v", err)
+	}
+
+	if _, err := artifact.NewVerifier(artifact.Publisher{Name: "broken", PublicKey: "zz"}); err == nil {
+		t.Fatalf("NewVerifier() accepted an invalid key")
+	}
+}
+
+func TestVerifyContract(t *testing.T) {
+	w, publisher := newPublisher(t, "ULedger", crypto.KeyTypeSecp256k1)
+	verifier, _ := artifact.NewVerifier(publisher)
+	source := bytes.Repeat([]byte("(module)"), 500)
+	signed, err := artifact.Sign(&w, artifact.NewManifest("escrow", "2.0.0", artifact.KIND_CONTRACT, source))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	contract, blob, err := transaction.NewContractSource(source, true)
+	if err != nil {
+		t.Fatalf("NewContractSource() error = %v", err)
+	}
+	// The node references the gzip blob uploaded beforehand
+	contract.Data = ""
+	contract.Upload = "blob"
+	got, err := verifier.VerifyContract(contract, blob, signed)
+	if err != nil || !bytes.Equal(got, source) {
+		t.Fatalf("VerifyContract() = %d bytes, %v", len(got), err)
+	}
+
+	other, otherBlob, _ := transaction.NewContractSource([]byte("(module other)"), false)
+	if _, err := verifier.VerifyContract(other, otherBlob, signed); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("VerifyContract() of another contract error = %v", err)
+	}
+	plugin, _ := artifact.Sign(&w, artifact.NewManifest("escrow", "2.0.0", artifact.KIND_PLUGIN, source))
+	if _, err := verifier.VerifyContract(contract, blob, plugin); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("VerifyContract() of a plugin manifest error = %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package artifact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Verifier checks signed manifests against a fixed set of trusted publishers
+type Verifier struct {
+	// publishers are keyed by their normalized public key
+	publishers map[string]Publisher
+}
+
+// NewVerifier trusts the given publishers, their keys are checked up front
+func NewVerifier(publishers ...Publisher) (*Verifier, error) {
+	verifier := &Verifier{publishers: make(map[string]Publisher, len(publishers))}
+	for _, publisher := range publishers {
+		key, err := publicKey(publisher.KeyType, publisher.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("publisher %s: %w", publisher.Name, err)
+		}
+		verifier.publishers[publisher.KeyType.String()+":"+key] = publisher
+	}
+	return verifier, nil
+}
+
+// VerifyManifest checks the signature of signed and that a trusted publisher made it, the artifact
+// itself is not checked. The signing publisher is returned.
+func (v *Verifier) VerifyManifest(signed SignedManifest) (Publisher, error) {
+	manifest := signed.Manifest
+	if manifest.Schema != MANIFEST_SCHEMA {
+		return Publisher{}, &ErrInvalidArtifact{Msg: fmt.Sprintf("unsupported manifest schema %q", manifest.Schema)}
+	}
+	signature := signed.Signature
+	key, err := publicKey(signature.KeyType, signature.PublicKey)
+	if err != nil {
+		return Publisher{}, &ErrInvalidArtifact{Msg: err.Error()}
+	}
+	message, err := manifest.Bytes()
+	if err != nil {
+		return Publisher{}, err
+	}
+	// The address is derived from the signing key, the publisher is identified by the key alone
+	ok, err := wallet.VerifyMessage(wallet.ParseAddress(key), message, signature)
+	if err != nil {
+		return Publisher{}, &ErrInvalidArtifact{Msg: err.Error()}
+	}
+	if !ok {
+		return Publisher{}, &ErrInvalidArtifact{Msg: fmt.Sprintf("the signature of %s does not verify", manifest.Name)}
+	}
+
+	publisher, trusted := v.publishers[signature.KeyType.String()+":"+key]
+	if !trusted {
+		return Publisher{}, &ErrUntrustedPublisher{PublicKey: signature.PublicKey}
+	}
+	return publisher, nil
+}
+
+// Verify checks signed with VerifyManifest, then that artifact is the artifact it describes
+func (v *Verifier) Verify(artifact io.Reader, signed SignedManifest) (Publisher, error) {
+	publisher, err := v.VerifyManifest(signed)
+	if err != nil {
+		return Publisher{}, err
+	}
+	if err := signed.Manifest.Check(artifact); err != nil {
+		return Publisher{}, err
+	}
+	return publisher, nil
+}
+
+// VerifyContract checks a contract envelope retrieved from a node against the signed manifest of
+// the contract, blob is the uploaded blob or nil for inline sources. The decoded source is returned
+// once it verifies.
+func (v *Verifier) VerifyContract(contract transaction.ContractSource, blob []byte, signed SignedManifest) ([]byte, error) {
+	if signed.Manifest.Kind != KIND_CONTRACT {
+		return nil, &ErrInvalidArtifact{Msg: fmt.Sprintf("%s is a %s, not a contract", signed.Manifest.Name, signed.Manifest.Kind)}
+	}
+	source, err := contract.Decode(blob)
+	if err != nil {
+		return nil, &ErrInvalidArtifact{Msg: err.Error()}
+	}
+	if _, err := v.Verify(bytes.NewReader(source), signed); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
@@ -0,0 +1,218 @@
+// Package benchcmp compares two runs of `go test -bench` so performance regressions of the signing,
+// commitment and serialization hot paths are caught before they ship. Record a baseline and a
+// candidate with the same flags, -count above 1 smooths out noise:
+//
+//	go test -run '^$' -bench . -count 5 ./pkg/crypto ./pkg/transaction > old.txt
+//	go test -run '^$' -bench . -count 5 ./pkg/crypto ./pkg/transaction > new.txt
+//	uledger bench compare old.txt new.txt --threshold 10
+package benchcmp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// DEFAULT_THRESHOLD is the slowdown in percent above which a benchmark is a regression
+const DEFAULT_THRESHOLD = 10.0
+
+// Result is the median of the runs of one benchmark, metrics a run did not report are 0
+type Result struct {
+	Name        string
+	Runs        int
+	NsPerOp     float64
+	BytesPerOp  float64
+	AllocsPerOp float64
+	MBPerSec    float64
+}
+
+// Parse reads the output of `go test -bench`. Lines that are not benchmark results are skipped and
+// the GOMAXPROCS suffix is dropped from names, so runs on machines with other core counts compare.
+// Results are returned in the order benchmarks first appear.
+func Parse(reader io.Reader) ([]Result, error) {
+	runs := map[string][]Result{}
+	names := []string{}
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		run, ok, err := parseLine(scanner.Text())
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if _, seen := runs[run.Name]; !seen {
+			names = append(names, run.Name)
+		}
+		runs[run.Name] = append(runs[run.Name], run)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading benchmark output: %w", err)
+	}
+
+	results := make([]Result, 0, len(names))
+	for _, name := range names {
+		results = append(results, median(runs[name]))
+	}
+	return results, nil
+}
+
+func parseLine(line string) (Result, bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return Result{}, false, nil
+	}
+	if _, err := strconv.Atoi(fields[1]); err != nil {
+		// A benchmark that logged output or failed, not a result line
+		return Result{}, false, nil
+	}
+
+	run := Result{Name: trimProcs(fields[0]), Runs: 1}
+	for i := 2; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return Result{}, false, fmt.Errorf("invalid %s of %s: %w", fields[i+1], run.Name, err)
+		}
+		switch fields[i+1] {
+		case "ns/op":
+			run.NsPerOp = value
+		case "B/op":
+			run.BytesPerOp = value
+		case "allocs/op":
+			run.AllocsPerOp = value
+		case "MB/s":
+			run.MBPerSec = value
+		}
+	}
+	return run, true, nil
+}
+
+func trimProcs(name string) string {
+	i := strings.LastIndexByte(name, '-')
+	if i < 0 {
+		return name
+	}
+	if _, err := strconv.Atoi(name[i+1:]); err != nil {
+		return name
+	}
+	return name[:i]
+}
+
+func median(runs []Result) Result {
+	metric := func(get func(Result) float64) float64 {
+		values := make([]float64, len(runs))
+		for i, run := range runs {
+			values[i] = get(run)
+		}
+		slices.Sort(values)
+		if len(values)%2 == 1 {
+			return values[len(values)/2]
+		}
+		return (values[len(values)/2-1] + values[len(values)/2]) / 2
+	}
+	return Result{
+		Name:        runs[0].Name,
+		Runs:        len(runs),
+		NsPerOp:     metric(func(r Result) float64 { return r.NsPerOp }),
+		BytesPerOp:  metric(func(r Result) float64 { return r.BytesPerOp }),
+		AllocsPerOp: metric(func(r Result) float64 { return r.AllocsPerOp }),
+		MBPerSec:    metric(func(r Result) float64 { return r.MBPerSec }),
+	}
+}
+
+// Comparison is a benchmark present in both runs, deltas are in percent of the old value
+type Comparison struct {
+	Old         Result
+	New         Result
+	TimeDelta   float64
+	AllocsDelta float64
+	Regression  bool
+}
+
+type Report struct {
+	Threshold   float64
+	Comparisons []Comparison
+	// Removed and Added are benchmarks present in only one of the runs
+	Removed []string
+	Added   []string
+}
+
+// Compare matches the benchmarks of two runs by name. A benchmark regressed when its time per
+// operation grew by more than threshold percent or it allocates more often than before.
+func Compare(old, new []Result, threshold float64) Report {
+	report := Report{Threshold: threshold}
+	candidates := make(map[string]Result, len(new))
+	for _, result := range new {
+		candidates[result.Name] = result
+	}
+	for _, before := range old {
+		after, ok := candidates[before.Name]
+		if !ok {
+			report.Removed = append(report.Removed, before.Name)
+			continue
+		}
+		delete(candidates, before.Name)
+		comparison := Comparison{
+			Old:         before,
+			New:         after,
+			TimeDelta:   delta(before.NsPerOp, after.NsPerOp),
+			AllocsDelta: delta(before.AllocsPerOp, after.AllocsPerOp),
+		}
+		comparison.Regression = comparison.TimeDelta > threshold || after.AllocsPerOp > before.AllocsPerOp
+		report.Comparisons = append(report.Comparisons, comparison)
+	}
+	for _, result := range new {
+		if _, ok := candidates[result.Name]; ok {
+			report.Added = append(report.Added, result.Name)
+		}
+	}
+	return report
+}
+
+func delta(before, after float64) float64 {
+	if before == 0 {
+		if after == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return (after - before) / before * 100
+}
+
+func (r Report) Regressions() []Comparison {
+	regressions := []Comparison{}
+	for _, comparison := range r.Comparisons {
+		if comparison.Regression {
+			regressions = append(regressions, comparison)
+		}
+	}
+	return regressions
+}
+
+// Write prints the report as a table, regressions are marked with an exclamation mark
+func (r Report) Write(out io.Writer) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "benchmark\told ns/op\tnew ns/op\tdelta\told allocs\tnew allocs\t")
+	for _, c := range r.Comparisons {
+		mark := ""
+		if c.Regression {
+			mark = "!"
+		}
+		fmt.Fprintf(w, "%s\t%.0f\t%.0f\t%+.2f%%\t%.0f\t%.0f\t%s\n", c.Old.Name, c.Old.NsPerOp, c.New.NsPerOp, c.TimeDelta, c.Old.AllocsPerOp, c.New.AllocsPerOp, mark)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	for _, name := range r.Removed {
+		fmt.Fprintf(out, "removed: %s\n", name)
+	}
+	for _, name := range r.Added {
+		fmt.Fprintf(out, "added: %s\n", name)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package benchcmp
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const oldRun = `goos: linux
+goarch: amd64
+pkg: github.com/ULedgerInc/go-sdk/pkg/crypto
+BenchmarkSignData/secp256k1-8         	    1000	    100000 ns/op	    2048 B/op	      20 allocs/op
+BenchmarkSignData/secp256k1-8         	    1000	    120000 ns/op	    2048 B/op	      20 allocs/op
+BenchmarkSignData/secp256k1-8         	    1000	    110000 ns/op	    2048 B/op	      20 allocs/op
+BenchmarkSignData/ed25519-8           	   50000	     20000 ns/op	       0 B/op	       0 allocs/op
+BenchmarkEncode/bytes-8               	  100000	      1000 ns/op	  4096.00 MB/s	    5000 B/op	       2 allocs/op
+PASS
+ok  	github.com/ULedgerInc/go-sdk/pkg/crypto	5.123s
+`
+
+const newRun = `BenchmarkSignData/secp256k1-16        	    1000	    115000 ns/op	    2048 B/op	      20 allocs/op
+BenchmarkSignData/ed25519-16          	   50000	     25000 ns/op	       0 B/op	       0 allocs/op
+BenchmarkEncode/bytes-16              	  100000	       900 ns/op	  4551.11 MB/s	    5000 B/op	       3 allocs/op
+BenchmarkDecode/bytes-16              	  100000	       800 ns/op	    5000 B/op	       2 allocs/op
+`
+
+func TestCompare(t *testing.T) {
+	old, err := Parse(strings.NewReader(oldRun))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(old) != 3 || old[0].Name != "BenchmarkSignData/secp256k1" || old[0].Runs != 3 || old[0].NsPerOp != 110000 {
+		t.Fatalf("Parse() = %+v", old)
+	}
+	if old[2].MBPerSec != 4096 || old[2].BytesPerOp != 5000 {
+		t.Fatalf("Parse() of %s = %+v", old[2].Name, old[2])
+	}
+	new, err := Parse(strings.NewReader(newRun))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	report := Compare(old, new, DEFAULT_THRESHOLD)
+	regressed := map[string]bool{}
+	for _, c := range report.Regressions() {
+		regressed[c.Old.Name] = true
+	}
+	// secp256k1 is 4.5% slower, within the threshold, ed25519 is 25% slower and Encode allocates more
+	if len(regressed) != 2 || !regressed["BenchmarkSignData/ed25519"] || !regressed["BenchmarkEncode/bytes"] {
+		t.Fatalf("Regressions() = %v", regressed)
+	}
+	if len(report.Added) != 1 || report.Added[0] != "BenchmarkDecode/bytes" || len(report.Removed) != 0 {
+		t.Fatalf("Compare() added %v, removed %v", report.Added, report.Removed)
+	}
+
+	var out bytes.Buffer
+	if err := report.Write(&out); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.Contains(out.String(), "+25.00%") || !strings.Contains(out.String(), "added: BenchmarkDecode/bytes") {
+		t.Fatalf("Write() =\n%s", out.String())
+	}
+
+	if _, err := Parse(strings.NewReader("BenchmarkX-8 100 fast ns/op\n")); err == nil {
+		t.Fatal("Parse() accepted an invalid metric")
+	}
+}
@@ -0,0 +1,287 @@
+// Package bindgen generates typed Go bindings of smart contracts from a manifest of their
+// functions, so invocations need no manual Encode calls. The manifest is JSON:
+//
+//	{
+//	  "name": "Token",
+//	  "gasLimit": 100000,
+//	  "functions": [
+//	    {"name": "transfer", "args": [{"name": "to", "type": "string"}, {"name": "amount", "type": "int32"}]},
+//	    {"name": "balanceOf", "args": [{"name": "owner", "type": "string"}], "returns": "int32", "view": true}
+//	  ]
+//	}
+//
+// The binding embeds a transaction.ContractClient and has one method per function. Methods of view
+// functions call them with CallContract and return their decoded result, the others invoke them
+// with a transaction. Bindings are usually regenerated with go generate:
+//
+//	//go:generate go run github.com/ULedgerInc/go-sdk/cmd/uledger contract bind --package token --out token.go token.json
+package bindgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// DEFAULT_GAS_LIMIT is the gas limit of functions when neither they nor the manifest set one
+const DEFAULT_GAS_LIMIT = 100000
+
+// Types maps the manifest argument types to the Go types Encode serializes them from. Any of them
+// but map can be suffixed with [] for an array.
+var Types = map[string]string{
+	"bool":    "bool",
+	"int32":   "int32",
+	"int64":   "int64",
+	"float32": "float32",
+	"float64": "float64",
+	"string":  "string",
+	"bytes":   "[]byte",
+	"map":     "map[string]interface{}",
+}
+
+// ErrInvalidManifest is returned for manifests no binding can be generated from
+type ErrInvalidManifest struct {
+	Function string
+	Msg      string
+}
+
+func (e *ErrInvalidManifest) Error() string {
+	if e.Function == "" {
+		return fmt.Sprintf("invalid contract manifest: %s", e.Msg)
+	}
+	return fmt.Sprintf("invalid contract manifest, function %s: %s", e.Function, e.Msg)
+}
+
+func (e *ErrInvalidManifest) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Manifest describes the functions of a contract
+type Manifest struct {
+	// Name of the contract, the default name of the binding type
+	Name string `json:"name"`
+	// GasLimit of the functions that set none, DEFAULT_GAS_LIMIT when zero
+	GasLimit  uint64     `json:"gasLimit,omitempty"`
+	Functions []Function `json:"functions"`
+}
+
+// Function is a contract function, Returns is empty when it returns nothing. View functions only
+// read the contract state and are called without a transaction, they must return a value.
+type Function struct {
+	Name     string  `json:"name"`
+	Args     []Param `json:"args,omitempty"`
+	Returns  string  `json:"returns,omitempty"`
+	GasLimit uint64  `json:"gasLimit,omitempty"`
+	View     bool    `json:"view,omitempty"`
+}
+
+// Param is a named argument of a function
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Options of Generate
+type Options struct {
+	// Package of the generated file
+	Package string
+	// Type is the name of the binding, the manifest name when empty
+	Type string
+}
+
+// ParseManifest reads and validates a JSON manifest
+func ParseManifest(reader io.Reader) (Manifest, error) {
+	manifest := Manifest{}
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return Manifest{}, &ErrInvalidManifest{Msg: err.Error()}
+	}
+	return manifest, manifest.Validate()
+}
+
+// reserved are the methods and fields bindings get from the embedded ContractClient
+var reserved = []string{"ContractClient", "BlockchainId", "GasLimit", "ContractAddress", "Invoke", "Call", "EstimateGas", "Events"}
+
+// Validate checks that every function has a distinct exported method name and arguments of known types
+func (m Manifest) Validate() error {
+	if len(m.Functions) == 0 {
+		return &ErrInvalidManifest{Msg: "no functions"}
+	}
+	methods := map[string]string{}
+	for _, reservedName := range reserved {
+		methods[reservedName] = "the embedded ContractClient"
+	}
+	for _, function := range m.Functions {
+		method := exported(function.Name)
+		if !token.IsIdentifier(method) {
+			return &ErrInvalidManifest{Function: function.Name, Msg: "the name is not a valid identifier"}
+		}
+		names := []string{method}
+		if function.Returns != "" && !function.View {
+			names = append(names, "Decode"+method)
+		}
+		for _, name := range names {
+			if other, ok := methods[name]; ok {
+				return &ErrInvalidManifest{Function: function.Name, Msg: fmt.Sprintf("the method %s clashes with %s", name, other)}
+			}
+			methods[name] = function.Name
+		}
+
+		args := map[string]bool{}
+		for _, arg := range function.Args {
+			if !token.IsIdentifier(arg.Name) && !token.IsKeyword(arg.Name) {
+				return &ErrInvalidManifest{Function: function.Name, Msg: fmt.Sprintf("the argument name %q is not a valid identifier", arg.Name)}
+			}
+			if args[param(arg.Name)] {
+				return &ErrInvalidManifest{Function: function.Name, Msg: fmt.Sprintf("the argument %s is declared twice", arg.Name)}
+			}
+			args[param(arg.Name)] = true
+			if _, err := goType(arg.Type); err != nil {
+				return &ErrInvalidManifest{Function: function.Name, Msg: fmt.Sprintf("argument %s: %s", arg.Name, err)}
+			}
+		}
+		if function.Returns != "" {
+			if _, err := goType(function.Returns); err != nil {
+				return &ErrInvalidManifest{Function: function.Name, Msg: fmt.Sprintf("return value: %s", err)}
+			}
+		} else if function.View {
+			return &ErrInvalidManifest{Function: function.Name, Msg: "view functions must return a value"}
+		}
+	}
+	return nil
+}
+
+// goType returns the Go type of a manifest type
+func goType(manifestType string) (string, error) {
+	element, array := strings.CutSuffix(manifestType, "[]")
+	goType, ok := Types[element]
+	if !ok || (array && element == "map") {
+		return "", fmt.Errorf("unsupported type %q", manifestType)
+	}
+	if array {
+		return "[]" + goType, nil
+	}
+	return goType, nil
+}
+
+// exported upper cases the first letter of name
+func exported(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// param renames the arguments that are keywords, the receiver or the context of the generated methods
+func param(name string) string {
+	if token.IsKeyword(name) || name == "c" || name == "ctx" {
+		return name + "_"
+	}
+	return name
+}
+
+type methodData struct {
+	Name     string
+	Function string
+	Params   []paramData
+	Returns  string
+	GasLimit uint64
+	View     bool
+}
+
+type paramData struct {
+	Name string
+	Type string
+}
+
+type fileData struct {
+	Package  string
+	Type     string
+	Contract string
+	Methods  []methodData
+}
+
+var bindingTemplate = template.Must(template.New("binding").Parse(`// Code generated by uledger contract bind. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"context"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// {{.Type}} binds the functions of the {{.Contract}} contract
+type {{.Type}} struct {
+	*transaction.ContractClient
+}
+
+// New{{.Type}} returns a binding of the {{.Contract}} contract deployed at contractAddress
+func New{{.Type}}(session *transaction.UL_TransactionSession, contractAddress string) *{{.Type}} {
+	return &{{.Type}}{ContractClient: transaction.NewContractClient(session, contractAddress)}
+}
+{{range .Methods}}{{if .View}}
+// {{.Name}} calls the view function {{.Function}} without a transaction
+func (c *{{$.Type}}) {{.Name}}(ctx context.Context{{range .Params}}, {{.Name}} {{.Type}}{{end}}) ({{.Returns}}, error) {
+	return transaction.CallContractAs[{{.Returns}}](ctx, c.ContractClient, "{{.Function}}"{{range .Params}}, {{.Name}}{{end}})
+}
+{{else}}
+// {{.Name}} invokes {{.Function}} with a gas limit of {{.GasLimit}} unless GasLimit is set
+func (c *{{$.Type}}) {{.Name}}(ctx context.Context{{range .Params}}, {{.Name}} {{.Type}}{{end}}) (transaction.ULTransaction, error) {
+	return c.Invoke(ctx, "{{.Function}}", {{.GasLimit}}{{range .Params}}, {{.Name}}{{end}})
+}
+{{if .Returns}}
+// Decode{{.Name}} decodes the {{.Returns}} returned by {{.Function}}
+func (c *{{$.Type}}) Decode{{.Name}}(data []byte) ({{.Returns}}, error) {
+	return transaction.DecodeContractResult[{{.Returns}}](data)
+}
+{{end}}{{end}}{{end}}`))
+
+// Generate returns the gofmt formatted source of the binding of manifest
+func Generate(manifest Manifest, opts Options) ([]byte, error) {
+	if err := manifest.Validate(); err != nil {
+		return nil, err
+	}
+	data := fileData{Package: opts.Package, Type: opts.Type, Contract: manifest.Name}
+	if data.Type == "" {
+		data.Type = exported(manifest.Name)
+	}
+	if !token.IsIdentifier(data.Package) {
+		return nil, &ErrInvalidManifest{Msg: fmt.Sprintf("invalid package name %q", data.Package)}
+	}
+	if !token.IsIdentifier(data.Type) || !token.IsExported(data.Type) {
+		return nil, &ErrInvalidManifest{Msg: fmt.Sprintf("invalid binding type name %q, set the manifest name or the type", data.Type)}
+	}
+
+	for _, function := range manifest.Functions {
+		method := methodData{Name: exported(function.Name), Function: function.Name, GasLimit: function.GasLimit, View: function.View}
+		if method.GasLimit == 0 {
+			method.GasLimit = manifest.GasLimit
+		}
+		if method.GasLimit == 0 {
+			method.GasLimit = DEFAULT_GAS_LIMIT
+		}
+		for _, arg := range function.Args {
+			argType, _ := goType(arg.Type)
+			method.Params = append(method.Params, paramData{Name: param(arg.Name), Type: argType})
+		}
+		if function.Returns != "" {
+			method.Returns, _ = goType(function.Returns)
+		}
+		data.Methods = append(data.Methods, method)
+	}
+
+	source := bytes.Buffer{}
+	if err := bindingTemplate.Execute(&source, data); err != nil {
+		return nil, err
+	}
+	return format.Source(source.Bytes())
+}
@@ -0,0 +1,88 @@
+package bindgen
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// The example binding must be what the generator produces today
+func TestGenerateExample(t *testing.T) {
+	file, err := os.Open("../../examples/contract_bindings/token/token.json")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer file.Close()
+	manifest, err := ParseManifest(file)
+	if err != nil {
+		t.Fatalf("ParseManifest() error = %v", err)
+	}
+	source, err := Generate(manifest, Options{Package: "token"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	want, err := os.ReadFile("../../examples/contract_bindings/token/token.go")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !bytes.Equal(source, want) {
+		t.Fatalf("Generate() differs from examples/contract_bindings/token/token.go, run go generate:\n%s", source)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	manifest := Manifest{Name: "registry", Functions: []Function{
+		{Name: "set", Args: []Param{{Name: "type", Type: "string"}, {Name: "c", Type: "map"}, {Name: "tags", Type: "bytes[]"}}},
+		{Name: "lookup", Args: []Param{{Name: "key", Type: "string"}}, Returns: "float64[]"},
+		{Name: "owner", Args: []Param{{Name: "ctx", Type: "int64"}}, Returns: "string", View: true},
+	}}
+	source, err := Generate(manifest, Options{Package: "registry"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for _, want := range []string{
+		"type Registry struct",
+		"func (c *Registry) Set(ctx context.Context, type_ string, c_ map[string]interface{}, tags [][]byte) (transaction.ULTransaction, error)",
+		`c.Invoke(ctx, "set", 100000, type_, c_, tags)`,
+		"func (c *Registry) DecodeLookup(data []byte) ([]float64, error)",
+		"func (c *Registry) Owner(ctx context.Context, ctx_ int64) (string, error)",
+		`transaction.CallContractAs[string](ctx, c.ContractClient, "owner", ctx_)`,
+	} {
+		if !strings.Contains(string(source), want) {
+			t.Fatalf("Generate() lacks %q:\n%s", want, source)
+		}
+	}
+}
+
+func TestInvalidManifest(t *testing.T) {
+	tests := map[string]string{
+		"unknown type":        `{"name": "Token", "functions": [{"name": "mint", "args": [{"name": "amount", "type": "uint8"}]}]}`,
+		"map array":           `{"name": "Token", "functions": [{"name": "mint", "args": [{"name": "entries", "type": "map[]"}]}]}`,
+		"clashing methods":    `{"name": "Token", "functions": [{"name": "mint"}, {"name": "Mint"}]}`,
+		"embedded method":     `{"name": "Token", "functions": [{"name": "invoke"}]}`,
+		"decode method":       `{"name": "Token", "functions": [{"name": "balance", "returns": "int32"}, {"name": "decodeBalance"}]}`,
+		"duplicate arg":       `{"name": "Token", "functions": [{"name": "mint", "args": [{"name": "to", "type": "string"}, {"name": "to", "type": "string"}]}]}`,
+		"invalid arg name":    `{"name": "Token", "functions": [{"name": "mint", "args": [{"name": "the amount", "type": "int32"}]}]}`,
+		"unknown field":       `{"name": "Token", "functions": [{"name": "mint", "payable": true}]}`,
+		"no functions":        `{"name": "Token"}`,
+		"view without result": `{"name": "Token", "functions": [{"name": "paused", "view": true}]}`,
+		"invalid func name":   `{"name": "Token", "functions": [{"name": "1mint"}]}`,
+	}
+	for name, manifest := range tests {
+		if _, err := ParseManifest(strings.NewReader(manifest)); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("%s: ParseManifest() error = %v", name, err)
+		}
+	}
+
+	valid := Manifest{Functions: []Function{{Name: "mint"}}}
+	if _, err := Generate(valid, Options{Package: "token"}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("Generate() without a type name error = %v", err)
+	}
+	if _, err := Generate(valid, Options{Package: "my-token", Type: "Token"}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("Generate() of an invalid package error = %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+)
+
+const (
+	ARCHIVE_ZIP = "zip"
+	ARCHIVE_TAR = "tar"
+	// ARCHIVE_TGZ is a gzip compressed tar archive
+	ARCHIVE_TGZ = "tgz"
+)
+
+// archiveFormat returns the archive format of a file name, empty for other files
+func archiveFormat(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		return ARCHIVE_ZIP
+	case strings.HasSuffix(name, ".tar"):
+		return ARCHIVE_TAR
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ARCHIVE_TGZ
+	default:
+		return ""
+	}
+}
+
+// readArchive reads every regular file of an archive in name order, hidden files and nested
+// archives excepted. Entries are named archive:entry.
+func readArchive(file string, format string) ([]WalletInput, []error) {
+	var inputs []WalletInput
+	var errs []error
+	add := func(name string, r io.Reader, err error) {
+		source := file + ":" + name
+		var data []byte
+		if err == nil {
+			data, err = readLimited(r)
+		}
+		if err != nil {
+			errs = append(errs, &ErrWalletInput{Source: source, Err: err})
+			return
+		}
+		inputs = append(inputs, WalletInput{Source: source, Data: data})
+	}
+
+	var err error
+	if format == ARCHIVE_ZIP {
+		err = readZip(file, add)
+	} else {
+		err = readTar(file, format == ARCHIVE_TGZ, add)
+	}
+	if err != nil {
+		errs = append(errs, &ErrWalletInput{Source: file, Err: err})
+	}
+
+	slices.SortStableFunc(inputs, func(a, b WalletInput) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+	return inputs, errs
+}
+
+// walletEntry reports whether an archive entry may hold a wallet
+func walletEntry(name string) bool {
+	base := path.Base(name)
+	return !strings.HasPrefix(base, ".") && archiveFormat(base) == ""
+}
+
+func readZip(file string, add func(string, io.Reader, error)) error {
+	archive, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	for _, entry := range archive.File {
+		if !entry.Mode().IsRegular() || !walletEntry(entry.Name) {
+			continue
+		}
+		r, err := entry.Open()
+		add(entry.Name, r, err)
+		if err == nil {
+			r.Close()
+		}
+	}
+	return nil
+}
+
+func readTar(file string, compressed bool, add func(string, io.Reader, error)) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if compressed {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return i18n.Errorf("cli.input.archive", err)
+		}
+		if header.Typeflag == tar.TypeReg && walletEntry(header.Name) {
+			add(header.Name, archive, nil)
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package cli_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	ulcli "github.com/ULedgerInc/go-sdk/pkg/cli"
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+	"github.com/urfave/cli/v3"
+)
+
+func saveWallets(t *testing.T, dir string, names ...string) []wallet.UL_Wallet {
+	t.Helper()
+	wallets := make([]wallet.UL_Wallet, len(names))
+	for i, name := range names {
+		w, mnemonic, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		if err := w.SaveToFile(filepath.Join(dir, name), mnemonic, true); err != nil {
+			t.Fatalf("SaveToFile() error = %v", err)
+		}
+		wallets[i] = w
+	}
+	return wallets
+}
+
+func TestWalletInputResolver(t *testing.T) {
+	dir := t.TempDir()
+	saved := saveWallets(t, dir, "a", "b")
+	os.Mkdir(filepath.Join(dir, "nested"), 0700)
+	single, _ := os.ReadFile(filepath.Join(dir, "a.ukey"))
+
+	for name, input := range map[string]string{
+		"glob":      filepath.Join(dir, "*.ukey"),
+		"directory": dir,
+	} {
+		resolver := &ulcli.WalletInputResolver{Input: input}
+		wallets, err := resolver.Load()
+		if err != nil {
+			t.Fatalf("Load() of the %s error = %v", name, err)
+		}
+		if len(wallets) != 2 || wallets[0].Address != saved[0].Address || wallets[1].Address != saved[1].Address {
+			t.Fatalf("Load() of the %s = %d wallets", name, len(wallets))
+		}
+	}
+	for name, input := range map[string]string{
+		"file": filepath.Join(dir, "b.ukey"),
+		"json": string(single),
+	} {
+		inputs, err := (&ulcli.WalletInputResolver{Input: input}).Resolve()
+		if err != nil || len(inputs) != 1 {
+			t.Fatalf("Resolve() of a %s = %d inputs, %v", name, len(inputs), err)
+		}
+	}
+
+	if _, err := (&ulcli.WalletInputResolver{Input: filepath.Join(dir, "nested")}).Resolve(); err == nil {
+		t.Fatalf("Resolve() of an empty directory succeeded")
+	}
+	if _, err := (&ulcli.WalletInputResolver{Input: filepath.Join(dir, "*.json")}).Resolve(); err == nil {
+		t.Fatalf("Resolve() of a pattern without matches succeeded")
+	}
+
+	// A broken file is reported without hiding the valid wallets
+	os.WriteFile(filepath.Join(dir, "broken.ukey"), []byte("{"), 0600)
+	wallets, err := (&ulcli.WalletInputResolver{Input: dir}).Load()
+	var invalid *ulcli.ErrWalletInput
+	if !errors.As(err, &invalid) || !errors.Is(err, utils.ErrInvalidInput) || !strings.Contains(err.Error(), "broken.ukey") || len(wallets) != 2 {
+		t.Fatalf("Load() with a broken file = %d wallets, %v", len(wallets), err)
+	}
+}
+
+func TestWalletInputSources(t *testing.T) {
+	dir := t.TempDir()
+	saved := saveWallets(t, dir, "a", "b")
+	a, _ := os.ReadFile(filepath.Join(dir, "a.ukey"))
+	b, _ := os.ReadFile(filepath.Join(dir, "b.ukey"))
+	addresses := func(t *testing.T, resolver *ulcli.WalletInputResolver) []string {
+		t.Helper()
+		wallets, err := resolver.Load()
+		if err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		got := make([]string, len(wallets))
+		for i, w := range wallets {
+			got[i] = w.Address
+		}
+		return got
+	}
+	want := []string{saved[0].Address, saved[1].Address}
+
+	t.Run("stdin", func(t *testing.T) {
+		stdin := strings.NewReader(string(a) + "\n" + string(b))
+		if got := addresses(t, &ulcli.WalletInputResolver{Input: "-", Stdin: stdin}); !slices.Equal(got, want) {
+			t.Fatalf("wallets of stdin = %v, want %v", got, want)
+		}
+		array := &ulcli.WalletInputResolver{Input: "[" + string(a) + "," + string(b) + "]"}
+		if got := addresses(t, array); !slices.Equal(got, want) {
+			t.Fatalf("wallets of a JSON array = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("archives", func(t *testing.T) {
+		archives := t.TempDir()
+		// Entries are written out of order, they are read sorted
+		entries := map[string][]byte{"wallets/b.ukey": b, "wallets/a.ukey": a, "wallets/.DS_Store": {0}}
+		names := []string{"wallets/b.ukey", "wallets/a.ukey", "wallets/.DS_Store"}
+
+		var zipped bytes.Buffer
+		zw := zip.NewWriter(&zipped)
+		for _, name := range names {
+			f, _ := zw.Create(name)
+			f.Write(entries[name])
+		}
+		zw.Close()
+		os.WriteFile(filepath.Join(archives, "wallets.zip"), zipped.Bytes(), 0600)
+
+		var tarred bytes.Buffer
+		gz := gzip.NewWriter(&tarred)
+		tw := tar.NewWriter(gz)
+		for _, name := range names {
+			tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(entries[name]))})
+			tw.Write(entries[name])
+		}
+		tw.Close()
+		gz.Close()
+		os.WriteFile(filepath.Join(archives, "wallets.tar.gz"), tarred.Bytes(), 0600)
+
+		for _, name := range []string{"wallets.zip", "wallets.tar.gz"} {
+			if got := addresses(t, &ulcli.WalletInputResolver{Input: filepath.Join(archives, name)}); !slices.Equal(got, want) {
+				t.Fatalf("wallets of %s = %v, want %v", name, got, want)
+			}
+		}
+		inputs, err := (&ulcli.WalletInputResolver{Input: archives}).Resolve()
+		if err != nil || len(inputs) != 4 || inputs[0].Source != filepath.Join(archives, "wallets.tar.gz")+":wallets/a.ukey" {
+			t.Fatalf("Resolve() of a folder of archives = %d inputs, %v", len(inputs), err)
+		}
+
+		os.WriteFile(filepath.Join(archives, "broken.zip"), []byte("not a zip"), 0600)
+		if _, err := (&ulcli.WalletInputResolver{Input: archives}).Load(); err == nil || !strings.Contains(err.Error(), "broken.zip") {
+			t.Fatalf("Load() with a broken archive error = %v", err)
+		}
+	})
+
+	t.Run("recursive", func(t *testing.T) {
+		root := t.TempDir()
+		os.MkdirAll(filepath.Join(root, "team", "ops"), 0700)
+		os.WriteFile(filepath.Join(root, "team", "ops", "b.ukey"), b, 0600)
+		os.WriteFile(filepath.Join(root, "a.ukey"), a, 0600)
+
+		if got := addresses(t, &ulcli.WalletInputResolver{Input: root}); !slices.Equal(got, want[:1]) {
+			t.Fatalf("wallets of the folder = %v, want %v", got, want[:1])
+		}
+		if got := addresses(t, &ulcli.WalletInputResolver{Input: root, Recursive: true}); !slices.Equal(got, want) {
+			t.Fatalf("wallets of the folder tree = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestCommandBlocks(t *testing.T) {
+	node := transactiontest.NewMockNode("chain")
+	t.Cleanup(node.Close)
+	dir := t.TempDir()
+	saved := saveWallets(t, dir, "signer")
+
+	resolver := &ulcli.WalletInputResolver{}
+	factory := &ulcli.SessionFactory{}
+	output := &ulcli.OutputFormatter{}
+	var out bytes.Buffer
+	command := &cli.Command{
+		Name:   "tool",
+		Writer: &out,
+		Flags:  slices.Concat(resolver.Flags(), factory.Flags(), output.Flags()),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			wallets, err := resolver.Load()
+			if err != nil {
+				return err
+			}
+			session, err := factory.Session(wallets[0])
+			if err != nil {
+				return err
+			}
+			output.Writer = cmd.Root().Writer
+			address := session.GetWallet().Address
+			return output.Print(map[string]string{"address": address}, func(w io.Writer) error {
+				table := ulcli.NewTable(w, "WALLET", "ADDRESS")
+				table.Row("signer", address)
+				return table.Flush()
+			})
+		},
+	}
+
+	if err := command.Run(context.Background(), []string{"tool", "--node", node.URL(), "--input", dir}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := out.String(); got != "WALLET  ADDRESS\nsigner  "+saved[0].Address+"\n" {
+		t.Fatalf("text output = %q", got)
+	}
+
+	out.Reset()
+	if err := command.Run(context.Background(), []string{"tool", "-n", node.URL(), "-i", dir, "--json"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := out.String(); got != "{\n  \"address\": \""+saved[0].Address+"\"\n}\n" {
+		t.Fatalf("JSON output = %q", got)
+	}
+
+	if _, err := (&ulcli.SessionFactory{}).Reader(); err == nil {
+		t.Fatalf("Reader() without a node succeeded")
+	}
+}
+
+func TestOutputFormatter(t *testing.T) {
+	txs := []transaction.ULTransaction{
+		{ULTransactionInput: transaction.ULTransactionInput{BlockchainId: "chain"}, ULTransactionOutput: transaction.ULTransactionOutput{TransactionId: "a1", Status: "ACCEPTED"}},
+		{ULTransactionInput: transaction.ULTransactionInput{BlockchainId: "chain"}, ULTransactionOutput: transaction.ULTransactionOutput{TransactionId: "b2", Status: "SUBMITTED"}},
+	}
+	block := transaction.ULBlock{Hash: "h3", Height: 3, Transactions: txs}
+	noText := func(w io.Writer) error {
+		t.Fatalf("the text renderer was called")
+		return nil
+	}
+	print := func(format ulcli.OutputFormat, fields string, value any) string {
+		t.Helper()
+		var out bytes.Buffer
+		formatter := &ulcli.OutputFormatter{Format: format, Writer: &out}
+		if fields != "" {
+			formatter.Fields = strings.Split(fields, ",")
+		}
+		if err := formatter.Print(value, noText); err != nil {
+			t.Fatalf("Print(%s) error = %v", format, err)
+		}
+		return out.String()
+	}
+
+	for _, test := range []struct {
+		format ulcli.OutputFormat
+		fields string
+		value  any
+		want   string
+	}{
+		{ulcli.OUTPUT_NDJSON, "txId,status", txs, "{\"txId\":\"a1\",\"status\":\"ACCEPTED\"}\n{\"txId\":\"b2\",\"status\":\"SUBMITTED\"}\n"},
+		{ulcli.OUTPUT_TABLE, "txId,Status", txs, "TXID  STATUS\na1    ACCEPTED\nb2    SUBMITTED\n"},
+		{ulcli.OUTPUT_TEXT, "transactionId", txs[0], "TRANSACTIONID\na1\n"},
+		{ulcli.OUTPUT_JSON, "status,missing", txs, "[\n  {\n    \"status\": \"ACCEPTED\",\n    \"missing\": null\n  },\n  {\n    \"status\": \"SUBMITTED\",\n    \"missing\": null\n  }\n]\n"},
+		{ulcli.OUTPUT_YAML, "height,blockHash", block, "height: 3\nblockHash: h3\n"},
+		{ulcli.OUTPUT_YAML, "", map[string]any{"voters": []string{"n1"}, "height": 3}, "height: 3\nvoters:\n  - n1\n"},
+		{ulcli.OUTPUT_TABLE, "", []map[string]any{{"a": 1, "nested": map[string]bool{"ok": true}}}, "A  NESTED\n1  {\"ok\":true}\n"},
+	} {
+		if got := print(test.format, test.fields, test.value); got != test.want {
+			t.Fatalf("Print(%s, %q) = %q, want %q", test.format, test.fields, got, test.want)
+		}
+	}
+
+	// Struct fields keep their order, nested fields are dotted paths
+	if got := print(ulcli.OUTPUT_YAML, "", block); !strings.HasPrefix(got, "blockHash: h3\npreviousBlockHash: \"\"\nheight: 3\n") {
+		t.Fatalf("YAML of a block = %q", got)
+	}
+	nested := struct {
+		Signature struct {
+			Valid bool `json:"valid"`
+		} `json:"signature"`
+	}{}
+	if got := print(ulcli.OUTPUT_NDJSON, "signature.valid", nested); got != "{\"signature.valid\":false}\n" {
+		t.Fatalf("NDJSON of a nested field = %q", got)
+	}
+
+	// Reports print whole as JSON and one entry per row otherwise
+	var out bytes.Buffer
+	report := &ulcli.OutputFormatter{Format: ulcli.OUTPUT_NDJSON, Writer: &out}
+	if err := report.PrintList(block, block.Transactions, noText); err != nil || strings.Count(out.String(), "\n") != 2 {
+		t.Fatalf("PrintList() = %q, %v", out.String(), err)
+	}
+}
+
+func TestOutputFlags(t *testing.T) {
+	run := func(args ...string) (ulcli.OutputFormatter, error) {
+		output := &ulcli.OutputFormatter{}
+		command := &cli.Command{
+			Name:   "tool",
+			Flags:  output.Flags(),
+			Action: func(ctx context.Context, cmd *cli.Command) error { return nil },
+		}
+		err := command.Run(context.Background(), append([]string{"tool"}, args...))
+		return *output, err
+	}
+
+	output, err := run("-o", "yaml", "--fields", "txId, status,")
+	if err != nil || output.Format != ulcli.OUTPUT_YAML || !slices.Equal(output.Fields, []string{"txId", "status"}) {
+		t.Fatalf("flags parsed to %+v, %v", output, err)
+	}
+	if _, err := run("--output", "xml"); err == nil {
+		t.Fatalf("an unknown output format was accepted")
+	}
+
+	t.Setenv(ulcli.ENV_OUTPUT, "ndjson")
+	if output, _ := run(); output.Format != ulcli.OUTPUT_NDJSON {
+		t.Fatalf("format from %s = %q", ulcli.ENV_OUTPUT, output.Format)
+	}
+	if output, _ := run("--json"); output.Format != ulcli.OUTPUT_JSON {
+		t.Fatalf("--json with %s set = %q", ulcli.ENV_OUTPUT, output.Format)
+	}
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldAliases are shorthands accepted by field selection, the output keeps the name as given
+var FieldAliases = map[string]string{
+	"txId": "transactionId",
+}
+
+// field is one member of a record
+type field struct {
+	Key   string
+	Value any
+}
+
+// record is a JSON object decoded with its members in order, so that rendering a struct keeps the
+// order of its fields and selected fields come in the order they were asked for
+type record []field
+
+func (r record) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range r {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		key, _ := json.Marshal(f.Key)
+		value, err := json.Marshal(f.Value)
+		if err != nil {
+			return nil, err
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+// yamlNode converts a document to YAML, numbers keep the digits of their JSON encoding
+func yamlNode(document any) (*yaml.Node, error) {
+	switch v := document.(type) {
+	case record:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range v {
+			value, err := yamlNode(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: f.Key}, value)
+		}
+		return node, nil
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			value, err := yamlNode(item)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, value)
+		}
+		return node, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(v.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: v.String()}, nil
+	default:
+		node := &yaml.Node{}
+		return node, node.Encode(v)
+	}
+}
+
+// get returns the member key, matched exactly, then case insensitively, then through FieldAliases
+func (r record) get(key string) (any, bool) {
+	for _, f := range r {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	for _, f := range r {
+		if strings.EqualFold(f.Key, key) {
+			return f.Value, true
+		}
+	}
+	if alias, ok := FieldAliases[key]; ok {
+		return r.get(alias)
+	}
+	return nil, false
+}
+
+// lookup follows a dotted path of members, e.g. signature.valid
+func lookup(value any, path string) any {
+	for _, key := range strings.Split(path, ".") {
+		r, ok := value.(record)
+		if !ok {
+			return nil
+		}
+		if value, ok = r.get(key); !ok {
+			return nil
+		}
+	}
+	return value
+}
+
+// toDocument converts value to its JSON form of records, slices and scalars
+func toDocument(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeOrdered(decoder)
+}
+
+func decodeOrdered(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		r := record{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, field{Key: key.(string), Value: value})
+		}
+		_, err := decoder.Token()
+		return r, err
+	case json.Delim('['):
+		items := []any{}
+		for decoder.More() {
+			item, err := decodeOrdered(decoder)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		_, err := decoder.Token()
+		return items, err
+	default:
+		return token, nil
+	}
+}
+
+// toRows returns the rows of value: the elements of a list, or value itself. With fields, every row
+// is reduced to the selected fields.
+func toRows(value any, fields []string) ([]any, error) {
+	document, err := toDocument(value)
+	if err != nil {
+		return nil, err
+	}
+	return rowsOf(document, fields), nil
+}
+
+func rowsOf(document any, fields []string) []any {
+	rows, ok := document.([]any)
+	if !ok {
+		rows = []any{document}
+	}
+	if len(fields) == 0 {
+		return rows
+	}
+	for i, row := range rows {
+		selected := make(record, len(fields))
+		for j, name := range fields {
+			selected[j] = field{Key: name, Value: lookup(row, name)}
+		}
+		rows[i] = selected
+	}
+	return rows
+}
+
+// selectFields converts value to its document, reduced to fields when some are given. Lists stay
+// lists, single values stay single.
+func selectFields(value any, fields []string) (any, error) {
+	document, err := toDocument(value)
+	if err != nil || len(fields) == 0 {
+		return document, err
+	}
+	rows := rowsOf(document, fields)
+	if _, list := document.([]any); list {
+		return rows, nil
+	}
+	return rows[0], nil
+}
+
+// writeTable renders rows in columns, the columns are the fields of the rows in order of appearance
+func writeTable(out io.Writer, rows []any, fields []string) error {
+	columns := fields
+	if len(columns) == 0 {
+		seen := map[string]bool{}
+		for _, row := range rows {
+			r, ok := row.(record)
+			if !ok {
+				continue
+			}
+			for _, f := range r {
+				if !seen[f.Key] {
+					seen[f.Key] = true
+					columns = append(columns, f.Key)
+				}
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		// Rows of scalars
+		table := NewTable(out)
+		for _, row := range rows {
+			table.Row(cell(row))
+		}
+		return table.Flush()
+	}
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column)
+	}
+	table := NewTable(out, header...)
+	for _, row := range rows {
+		cells := make([]any, len(columns))
+		for i, column := range columns {
+			cells[i] = cell(lookup(row, column))
+		}
+		table.Row(cells...)
+	}
+	return table.Flush()
+}
+
+// cell renders a value in a table cell, nested values as compact JSON
+func cell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number, bool:
+		return fmt.Sprint(v)
+	default:
+		// Documents hold nothing json cannot encode
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import "github.com/ULedgerInc/go-sdk/pkg/i18n"
+
+func init() {
+	i18n.Register(i18n.LOCALE_EN, i18n.Messages{
+		"cli.input.usage":     "The wallets: a file, folder, glob pattern, zip or tar archive, - for stdin, or the json string of a single wallet",
+		"cli.input.empty":     "input cannot be empty",
+		"cli.input.pattern":   "invalid wallet input pattern: %w",
+		"cli.input.read":      "error reading wallet file: %w",
+		"cli.input.none":      "no wallets found in the specified input",
+		"cli.input.too_large": "larger than %d bytes",
+		"cli.input.archive":   "corrupted archive: %w",
+		"cli.input.parse":     "error parsing wallet from %s: %v",
+		"cli.password.usage":  "The password to decrypt the wallets",
+		"cli.recursive.usage": "Read the wallets of subfolders too",
+		"cli.node.usage":      "The node endpoint address",
+		"cli.node.empty":      "node address cannot be empty",
+		"cli.session.create":  "error creating transaction session: %w",
+		"cli.json.usage":      "Print the output as JSON",
+		"cli.output.unknown":  "unknown output format: %s",
+		"cli.output.usage":    "The output format: %s",
+		"cli.fields.usage":    "Comma separated fields to print, e.g. transactionId,status",
+	})
+	i18n.Register(i18n.LOCALE_ES, i18n.Messages{
+		"cli.input.usage":     "Las billeteras: un archivo, carpeta, patrón glob, archivo zip o tar, - para stdin, o el json de una sola billetera",
+		"cli.input.empty":     "la entrada no puede estar vacía",
+		"cli.input.pattern":   "patrón de entrada de billeteras no válido: %w",
+		"cli.input.read":      "error al leer el archivo de billetera: %w",
+		"cli.input.none":      "no se encontraron billeteras en la entrada indicada",
+		"cli.input.too_large": "supera los %d bytes",
+		"cli.input.archive":   "archivo comprimido dañado: %w",
+		"cli.input.parse":     "error al interpretar la billetera de %s: %v",
+		"cli.password.usage":  "La contraseña para descifrar las billeteras",
+		"cli.recursive.usage": "Leer también las billeteras de las subcarpetas",
+		"cli.node.usage":      "La dirección del nodo",
+		"cli.node.empty":      "la dirección del nodo no puede estar vacía",
+		"cli.session.create":  "error al crear la sesión de transacciones: %w",
+		"cli.json.usage":      "Mostrar la salida en JSON",
+		"cli.output.unknown":  "formato de salida desconocido: %s",
+		"cli.output.usage":    "El formato de salida: %s",
+		"cli.fields.usage":    "Campos a mostrar separados por comas, p. ej. transactionId,status",
+	})
+	i18n.Register(i18n.LOCALE_PT, i18n.Messages{
+		"cli.input.usage":     "As carteiras: um arquivo, pasta, padrão glob, arquivo zip ou tar, - para stdin, ou o json de uma única carteira",
+		"cli.input.empty":     "a entrada não pode estar vazia",
+		"cli.input.pattern":   "padrão de entrada de carteiras inválido: %w",
+		"cli.input.read":      "erro ao ler o arquivo de carteira: %w",
+		"cli.input.none":      "nenhuma carteira encontrada na entrada informada",
+		"cli.input.too_large": "maior que %d bytes",
+		"cli.input.archive":   "arquivo compactado corrompido: %w",
+		"cli.input.parse":     "erro ao interpretar a carteira de %s: %v",
+		"cli.password.usage":  "A senha para descriptografar as carteiras",
+		"cli.recursive.usage": "Ler também as carteiras das subpastas",
+		"cli.node.usage":      "O endereço do nó",
+		"cli.node.empty":      "o endereço do nó não pode estar vazio",
+		"cli.session.create":  "erro ao criar a sessão de transações: %w",
+		"cli.json.usage":      "Exibir a saída em JSON",
+		"cli.output.unknown":  "formato de saída desconhecido: %s",
+		"cli.output.usage":    "O formato de saída: %s",
+		"cli.fields.usage":    "Campos a exibir separados por vírgulas, ex. transactionId,status",
+	})
+}
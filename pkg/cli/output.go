@@ -0,0 +1,215 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+type OutputFormat string
+
+const (
+	// OUTPUT_TEXT is the layout of each command, made for people
+	OUTPUT_TEXT   OutputFormat = "text"
+	OUTPUT_TABLE  OutputFormat = "table"
+	OUTPUT_JSON   OutputFormat = "json"
+	OUTPUT_YAML   OutputFormat = "yaml"
+	OUTPUT_NDJSON OutputFormat = "ndjson"
+)
+
+// OUTPUT_FORMATS lists the formats accepted by --output
+var OUTPUT_FORMATS = []OutputFormat{OUTPUT_TEXT, OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_YAML, OUTPUT_NDJSON}
+
+// ENV_OUTPUT sets the default of --output, e.g. json for every command of a script
+const ENV_OUTPUT = "ULEDGER_OUTPUT"
+
+// ParseOutputFormat returns the format named by s, case insensitively
+func ParseOutputFormat(s string) (OutputFormat, error) {
+	for _, format := range OUTPUT_FORMATS {
+		if strings.EqualFold(s, string(format)) {
+			return format, nil
+		}
+	}
+	return "", i18n.Errorf("cli.output.unknown", s)
+}
+
+// OutputFormatter prints the result of a command for people or scripts. Values are rendered through
+// their JSON encoding, so fields are named as in the JSON output whatever the format.
+type OutputFormatter struct {
+	Format OutputFormat
+	// Fields selects the fields of each row and their order, nested fields are dotted paths such as
+	// signature.valid. The text format becomes a table when fields are selected.
+	Fields []string
+	// Writer defaults to os.Stdout
+	Writer io.Writer
+}
+
+// Flags binds --output, --fields and --json to the formatter, --json overrides --output
+func (f *OutputFormatter) Flags() []cli.Flag {
+	formats := make([]string, len(OUTPUT_FORMATS))
+	for i, format := range OUTPUT_FORMATS {
+		formats[i] = string(format)
+	}
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Aliases:     []string{"o"},
+			Usage:       i18n.T("cli.output.usage", strings.Join(formats, ", ")),
+			Value:       string(OUTPUT_TEXT),
+			Sources:     cli.EnvVars(ENV_OUTPUT),
+			Destination: (*string)(&f.Format),
+			Validator: func(str string) error {
+				_, err := ParseOutputFormat(str)
+				return err
+			},
+		},
+		&cli.StringFlag{
+			Name:  "fields",
+			Usage: i18n.T("cli.fields.usage"),
+			Action: func(ctx context.Context, cmd *cli.Command, str string) error {
+				f.Fields = splitFields(str)
+				return nil
+			},
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: i18n.T("cli.json.usage"),
+			Action: func(ctx context.Context, cmd *cli.Command, json bool) error {
+				if json {
+					f.Format = OUTPUT_JSON
+				}
+				return nil
+			},
+		},
+	}
+}
+
+func splitFields(str string) []string {
+	var fields []string
+	for _, name := range strings.Split(str, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+func (f *OutputFormatter) out() io.Writer {
+	if f.Writer == nil {
+		return os.Stdout
+	}
+	return f.Writer
+}
+
+// Print renders value in the selected format, text calls the renderer of the command. Lists are
+// printed one row per element by the table and NDJSON formats.
+func (f *OutputFormatter) Print(value any, text func(w io.Writer) error) error {
+	return f.PrintList(value, value, text)
+}
+
+// PrintList renders a value holding a list, e.g. a report and its entries. The JSON and YAML
+// formats print value, the table and NDJSON formats print the elements of list, and selecting
+// fields reduces every format to the selected fields of the elements.
+func (f *OutputFormatter) PrintList(value any, list any, text func(w io.Writer) error) error {
+	format := OUTPUT_TEXT
+	if f.Format != "" {
+		var err error
+		if format, err = ParseOutputFormat(string(f.Format)); err != nil {
+			return err
+		}
+	}
+	if format == OUTPUT_TEXT {
+		if len(f.Fields) == 0 {
+			return text(f.out())
+		}
+		format = OUTPUT_TABLE
+	}
+
+	document := value
+	if len(f.Fields) > 0 {
+		document = list
+	}
+	switch format {
+	case OUTPUT_JSON:
+		if len(f.Fields) > 0 {
+			var err error
+			if document, err = selectFields(document, f.Fields); err != nil {
+				return err
+			}
+		}
+		encoder := json.NewEncoder(f.out())
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(document)
+	case OUTPUT_YAML:
+		document, err := selectFields(document, f.Fields)
+		if err != nil {
+			return err
+		}
+		node, err := yamlNode(document)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(f.out())
+		encoder.SetIndent(2)
+		if err := encoder.Encode(node); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case OUTPUT_NDJSON:
+		rows, err := toRows(list, f.Fields)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f.out())
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	case OUTPUT_TABLE:
+		rows, err := toRows(list, f.Fields)
+		if err != nil {
+			return err
+		}
+		return writeTable(f.out(), rows, f.Fields)
+	default:
+		return text(f.out())
+	}
+}
+
+// Table aligns rows in columns separated by two spaces, the text layout of the uledger commands
+type Table struct {
+	w *tabwriter.Writer
+}
+
+// NewTable starts a table on out, the header is written first when given
+func NewTable(out io.Writer, header ...string) *Table {
+	table := &Table{w: tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)}
+	if len(header) > 0 {
+		fmt.Fprintln(table.w, strings.Join(header, "\t"))
+	}
+	return table
+}
+
+// Row adds one row, values are printed with %v
+func (t *Table) Row(values ...any) {
+	cells := make([]string, len(values))
+	for i, value := range values {
+		cells[i] = fmt.Sprint(value)
+	}
+	fmt.Fprintln(t.w, strings.Join(cells, "\t"))
+}
+
+// Flush writes the aligned table
+func (t *Table) Flush() error {
+	return t.w.Flush()
+}
@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+	"github.com/urfave/cli/v3"
+)
+
+// SessionFactory creates the sessions of a command, all of them talk to Node
+type SessionFactory struct {
+	Node string
+	// Options configure the HTTP client of the sessions, see transaction.SessionOptions
+	Options transaction.SessionOptions
+}
+
+// Flags binds --node to the factory
+func (f *SessionFactory) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "node",
+			Aliases:     []string{"n"},
+			Usage:       i18n.T("cli.node.usage"),
+			Required:    true,
+			Destination: &f.Node,
+			Validator: func(str string) error {
+				if str == "" {
+					return i18n.Errorf("cli.node.empty")
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Session connects to the node with a session signing with w
+func (f *SessionFactory) Session(w wallet.UL_Wallet) (*transaction.UL_TransactionSession, error) {
+	if f.Node == "" {
+		return nil, i18n.Errorf("cli.node.empty")
+	}
+	session, err := transaction.NewUL_TransactionSession(f.Node, w, f.Options)
+	if err != nil {
+		return nil, i18n.Errorf("cli.session.create", err)
+	}
+	return &session, nil
+}
+
+// Reader connects to the node with a keyless session, reading needs no key as the session only
+// signs when generating transactions
+func (f *SessionFactory) Reader() (*transaction.UL_TransactionSession, error) {
+	return f.Session(wallet.UL_Wallet{})
+}
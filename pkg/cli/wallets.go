@@ -0,0 +1,276 @@
+// Package cli holds the building blocks of the uledger command and the examples: resolving wallet
+// inputs, creating sessions from flags and printing results. Tools built on urfave/cli add the
+// flags of each block to their commands, other tools set the fields directly.
+//
+//	resolver := &ulcli.WalletInputResolver{}
+//	factory := &ulcli.SessionFactory{}
+//	command := &cli.Command{
+//		Flags: slices.Concat(resolver.Flags(), factory.Flags()),
+//		Action: func(ctx context.Context, cmd *cli.Command) error {
+//			wallets, err := resolver.Load()
+//			...
+//			session, err := factory.Session(wallets[0])
+//			...
+//		},
+//	}
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+	"github.com/urfave/cli/v3"
+)
+
+// DEFAULT_WALLET_INPUT is where the examples look for wallet files
+const DEFAULT_WALLET_INPUT = "./wallets"
+
+// STDIN_WALLET_INPUT reads the wallets from the standard input
+const STDIN_WALLET_INPUT = "-"
+
+// MAX_WALLET_FILE_SIZE bounds every wallet read, archive entries included
+const MAX_WALLET_FILE_SIZE = 1 << 20
+
+// WalletInput is the raw JSON of one wallet and where it was read from
+type WalletInput struct {
+	Source string
+	Data   []byte
+}
+
+// ErrWalletInput reports a wallet that could not be read or parsed, Source names the file, the
+// archive entry or the position in the standard input
+type ErrWalletInput struct {
+	Source string
+	Err    error
+}
+
+func (e *ErrWalletInput) Error() string {
+	return i18n.T("cli.input.parse", e.Source, e.Err)
+}
+
+func (e *ErrWalletInput) Unwrap() error {
+	return e.Err
+}
+
+func (e *ErrWalletInput) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// WalletInputResolver finds the wallets a command works on. Input is one of
+//   - the JSON of a wallet, or of an array of wallets
+//   - STDIN_WALLET_INPUT for wallets read from Stdin, one JSON value after the other
+//   - a wallet file, or a .zip, .tar, .tar.gz or .tgz archive of wallet files
+//   - a directory, of which the files and archives are read, hidden files excepted
+//   - a glob pattern such as wallets/*.ukey, each match is read as above
+//
+// Wallets are returned sorted by source, archive entries in name order, so runs over the same
+// input see the wallets in the same order.
+type WalletInputResolver struct {
+	Input string
+	// Password decrypts the wallets, empty for unencrypted files
+	Password string
+	// Recursive descends into the subdirectories of directories
+	Recursive bool
+	// Stdin is read for STDIN_WALLET_INPUT, defaults to os.Stdin
+	Stdin io.Reader
+}
+
+// Flags binds --input, --password and --recursive to the resolver
+func (r *WalletInputResolver) Flags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:        "input",
+			Aliases:     []string{"i"},
+			Usage:       i18n.T("cli.input.usage"),
+			Value:       DEFAULT_WALLET_INPUT,
+			Destination: &r.Input,
+			Validator: func(str string) error {
+				if str == "" {
+					return i18n.Errorf("cli.input.empty")
+				}
+				return nil
+			},
+		},
+		&cli.StringFlag{
+			Name:        "password",
+			Aliases:     []string{"p"},
+			Usage:       i18n.T("cli.password.usage"),
+			Destination: &r.Password,
+		},
+		&cli.BoolFlag{
+			Name:        "recursive",
+			Usage:       i18n.T("cli.recursive.usage"),
+			Destination: &r.Recursive,
+		},
+	}
+}
+
+// Resolve reads the raw wallets of the input. Sources that cannot be read do not stop the others,
+// they are reported as ErrWalletInput joined in the returned error along with the inputs read.
+func (r *WalletInputResolver) Resolve() ([]WalletInput, error) {
+	input := r.Input
+	if input == "" {
+		input = DEFAULT_WALLET_INPUT
+	}
+
+	var inputs []WalletInput
+	var errs []error
+	switch {
+	case input == STDIN_WALLET_INPUT:
+		stdin := r.Stdin
+		if stdin == nil {
+			stdin = os.Stdin
+		}
+		data, err := readLimited(stdin)
+		if err != nil {
+			return nil, &ErrWalletInput{Source: "stdin", Err: err}
+		}
+		inputs, errs = splitWallets("stdin", data)
+	case strings.Contains(input, "{") && strings.Contains(input, "}"):
+		inputs, errs = splitWallets("input", []byte(input))
+	default:
+		paths := []string{input}
+		if _, err := os.Stat(input); err != nil && strings.ContainsAny(input, "*?[") {
+			matches, err := filepath.Glob(input)
+			if err != nil {
+				return nil, i18n.Errorf("cli.input.pattern", err)
+			}
+			paths = matches
+		} else if err != nil {
+			return nil, i18n.Errorf("cli.input.read", err)
+		}
+		for _, path := range paths {
+			read, readErrs := r.readPath(path)
+			inputs = append(inputs, read...)
+			errs = append(errs, readErrs...)
+		}
+	}
+
+	if len(inputs) == 0 && len(errs) == 0 {
+		return nil, i18n.Errorf("cli.input.none")
+	}
+	return inputs, errors.Join(errs...)
+}
+
+// Load resolves the input and decrypts every wallet. Invalid wallets do not stop the load, the
+// valid ones are returned with an error joining an ErrWalletInput for each of the others.
+func (r *WalletInputResolver) Load() ([]wallet.UL_Wallet, error) {
+	inputs, err := r.Resolve()
+	errs := []error{err}
+	wallets := make([]wallet.UL_Wallet, 0, len(inputs))
+	for _, input := range inputs {
+		w, err := wallet.FromJson(string(input.Data), r.Password)
+		if err != nil {
+			errs = append(errs, &ErrWalletInput{Source: input.Source, Err: err})
+			continue
+		}
+		wallets = append(wallets, *w)
+	}
+	return wallets, errors.Join(errs...)
+}
+
+// readPath reads a wallet file, an archive or a directory
+func (r *WalletInputResolver) readPath(path string) ([]WalletInput, []error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, []error{&ErrWalletInput{Source: path, Err: err}}
+	}
+	if !info.IsDir() {
+		return readFile(path)
+	}
+
+	var inputs []WalletInput
+	var errs []error
+	// WalkDir visits the entries in lexical order
+	err = filepath.WalkDir(path, func(entry string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, &ErrWalletInput{Source: entry, Err: err})
+			return nil
+		}
+		if entry == path {
+			return nil
+		}
+		hidden := strings.HasPrefix(d.Name(), ".")
+		if d.IsDir() && (hidden || !r.Recursive) {
+			return fs.SkipDir
+		}
+		if d.IsDir() || hidden {
+			return nil
+		}
+		read, readErrs := readFile(entry)
+		inputs = append(inputs, read...)
+		errs = append(errs, readErrs...)
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, &ErrWalletInput{Source: path, Err: err})
+	}
+	return inputs, errs
+}
+
+// readFile reads a wallet file, or every wallet of an archive
+func readFile(path string) ([]WalletInput, []error) {
+	if archive := archiveFormat(path); archive != "" {
+		return readArchive(path, archive)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, []error{&ErrWalletInput{Source: path, Err: err}}
+	}
+	defer file.Close()
+	data, err := readLimited(file)
+	if err != nil {
+		return nil, []error{&ErrWalletInput{Source: path, Err: err}}
+	}
+	return []WalletInput{{Source: path, Data: data}}, nil
+}
+
+// readLimited reads r up to MAX_WALLET_FILE_SIZE
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MAX_WALLET_FILE_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MAX_WALLET_FILE_SIZE {
+		return nil, i18n.Errorf("cli.input.too_large", MAX_WALLET_FILE_SIZE)
+	}
+	return data, nil
+}
+
+// splitWallets splits a stream of JSON values into wallets, arrays hold one wallet per element
+func splitWallets(source string, data []byte) ([]WalletInput, []error) {
+	var values []json.RawMessage
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, []error{&ErrWalletInput{Source: fmt.Sprintf("%s[%d]", source, len(values)), Err: err}}
+		}
+		if elements := []json.RawMessage{}; bytes.HasPrefix(value, []byte("[")) && json.Unmarshal(value, &elements) == nil {
+			values = append(values, elements...)
+		} else {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 1 {
+		return []WalletInput{{Source: source, Data: values[0]}}, nil
+	}
+	inputs := make([]WalletInput, len(values))
+	for i, value := range values {
+		inputs[i] = WalletInput{Source: fmt.Sprintf("%s[%d]", source, i), Data: value}
+	}
+	return inputs, nil
+}
@@ -0,0 +1,82 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/cloudflare/circl/sign/mldsa/mldsa87"
+)
+
+// KeyCapabilities describes what a key type can do so tooling can adapt without hard-coding per type.
+// Sizes are in bytes for the encodings produced by the ULKey implementations.
+type KeyCapabilities struct {
+	KeyType                 KeyType
+	SignatureSize           int
+	PublicKeySize           int // Size of GetPublicKeyHex(false)
+	CompressedPublicKeySize int // Size of GetPublicKeyHex(true), zero when the type has no compressed form
+	PrivateKeySize          int // Size of GetPrivateKeyHex()
+	SupportsAggregation     bool
+	SupportsRecovery        bool // Whether the public key can be recovered from a signature alone
+	SupportsCompression     bool
+	PostQuantum             bool
+	// Whether signing the same data twice gives the same signature, randomized schemes only
+	// produce signatures that verify
+	DeterministicSignatures bool
+	// Curve whose scalar field the signature commitment is built on, these signatures can be
+	// verified inside circuits on that curve. Empty when the type is not circuit friendly.
+	ZKCircuitCurve string
+}
+
+const (
+	ZK_CURVE_BN254   = "bn254"
+	ZK_CURVE_BW6_761 = "bw6-761"
+)
+
+var keyCapabilities = map[KeyType]KeyCapabilities{
+	KeyTypeSecp256k1: {
+		KeyType:                 KeyTypeSecp256k1,
+		SignatureSize:           64, // r || s
+		PublicKeySize:           65,
+		CompressedPublicKeySize: 33,
+		PrivateKeySize:          32,
+		SupportsCompression:     true,
+		ZKCircuitCurve:          ZK_CURVE_BN254,
+	},
+	KeyTypeMlDSA87: {
+		KeyType:        KeyTypeMlDSA87,
+		SignatureSize:  mldsa87.SignatureSize,
+		PublicKeySize:  mldsa87.PublicKeySize,
+		PrivateKeySize: mldsa87.PrivateKeySize,
+		PostQuantum:    true,
+	},
+	KeyTypeED25519: {
+		KeyType:                 KeyTypeED25519,
+		SignatureSize:           ed25519.SignatureSize,
+		PublicKeySize:           ed25519.PublicKeySize,
+		PrivateKeySize:          ed25519.PrivateKeySize,
+		DeterministicSignatures: true,
+	},
+	KeyTypeBLS12377: {
+		KeyType:                 KeyTypeBLS12377,
+		SignatureSize:           sizeSignature,
+		PublicKeySize:           sizePublicKey,
+		PrivateKeySize:          sizePrivateKey,
+		SupportsAggregation:     true,
+		DeterministicSignatures: true,
+		ZKCircuitCurve:          ZK_CURVE_BW6_761,
+	},
+}
+
+// Capabilities returns the capability matrix entry of a key type
+func Capabilities(keyType KeyType) (KeyCapabilities, error) {
+	capabilities, ok := keyCapabilities[keyType]
+	if !ok {
+		return KeyCapabilities{}, fmt.Errorf("invalid key type: %d", keyType)
+	}
+	return capabilities, nil
+}
+
+// SupportedKeyTypes lists every key type the SDK can generate and sign with
+func SupportedKeyTypes() []KeyType {
+	return []KeyType{KeyTypeSecp256k1, KeyTypeMlDSA87, KeyTypeED25519, KeyTypeBLS12377}
+}
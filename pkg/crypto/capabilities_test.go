@@ -0,0 +1,56 @@
+package crypto
+
+import (
+	"testing"
+)
+
+func TestCapabilitiesMatchKeys(t *testing.T) {
+	for _, keyType := range SupportedKeyTypes() {
+		t.Run(keyType.String(), func(t *testing.T) {
+			capabilities, err := Capabilities(keyType)
+			if err != nil {
+				t.Fatalf("Capabilities() error = %v", err)
+			}
+
+			key, err := GetKeyByType(keyType, GetHasherByType(keyType))
+			if err != nil {
+				t.Fatalf("GetKeyByType() error = %v", err)
+			}
+			if err := key.GenerateKeyFromSeed([]byte("capabilities")); err != nil {
+				t.Fatalf("GenerateKeyFromSeed() error = %v", err)
+			}
+			signature, err := key.SignData([]byte("message"))
+			if err != nil {
+				t.Fatalf("SignData() error = %v", err)
+			}
+
+			again, err := key.SignData([]byte("message"))
+			if err != nil {
+				t.Fatalf("SignData() error = %v", err)
+			}
+			if deterministic := string(again) == string(signature); deterministic != capabilities.DeterministicSignatures {
+				t.Errorf("deterministic signatures = %v, capabilities say %v", deterministic, capabilities.DeterministicSignatures)
+			}
+			if len(signature) != capabilities.SignatureSize {
+				t.Errorf("signature size = %d, capabilities say %d", len(signature), capabilities.SignatureSize)
+			}
+			if got := len(key.GetPublicKeyHex(false)) / 2; got != capabilities.PublicKeySize {
+				t.Errorf("public key size = %d, capabilities say %d", got, capabilities.PublicKeySize)
+			}
+			if got := len(key.GetPrivateKeyHex()) / 2; got != capabilities.PrivateKeySize {
+				t.Errorf("private key size = %d, capabilities say %d", got, capabilities.PrivateKeySize)
+			}
+			if capabilities.SupportsCompression {
+				if got := len(key.GetPublicKeyHex(true)) / 2; got != capabilities.CompressedPublicKeySize {
+					t.Errorf("compressed public key size = %d, capabilities say %d", got, capabilities.CompressedPublicKeySize)
+				}
+			}
+		})
+	}
+}
+
+func TestCapabilitiesInvalidKeyType(t *testing.T) {
+	if _, err := Capabilities(KeyType(99)); err == nil {
+		t.Error("Capabilities() expected an error for an unknown key type")
+	}
+}
@@ -70,6 +70,9 @@ func (key *ED25519Key) GeneratePublicKeyFromHex(compressed bool, hex string) err
 	if err != nil {
 		return fmt.Errorf("unable to decode public key, %w", err)
 	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("expected %d bytes, got %d", ed25519.PublicKeySize, len(publicKey))
+	}
 	key.publicKey = publicKey
 	return nil
 }
@@ -83,6 +86,9 @@ func (key *ED25519Key) GeneratePrivateKeyFromHex(hex string) error {
 	if err != nil {
 		return fmt.Errorf("unable to decode private key, %w", err)
 	}
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("expected %d bytes, got %d", ed25519.PrivateKeySize, len(privateKey))
+	}
 	key.privateKey = privateKey
 	return nil
 }
@@ -6,10 +6,12 @@ import (
 	"hash"
 	"strings"
 
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
 	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
 	mimc_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/mimc"
 	fr_bw6_761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr"
 	mimc_bw6_761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+	"golang.org/x/crypto/sha3"
 )
 
 type KeyType int
@@ -100,7 +102,7 @@ func GetKeyByType(keyType KeyType, hasher hash.Hash) (ULKey, error) {
 	case KeyTypeBLS12377:
 		return NewBLS12377Key(hasher), nil
 	default:
-		return nil, fmt.Errorf("invalid key type: %d", keyType)
+		return nil, fmt.Errorf("%w key type: %d", utils.ErrUnsupported, keyType)
 	}
 }
 
@@ -114,3 +116,53 @@ func GetHasherByType(keyType KeyType) hash.Hash {
 		return mimc_bn254.NewMiMC(mimc_bn254.WithByteOrder(fr_bn254.BigEndian))
 	}
 }
+
+type HasherType int
+
+const (
+	HasherTypeMiMCBN254 HasherType = iota
+	HasherTypeMiMCBW6761
+	HasherTypeSHA3_256
+	HasherTypeKeccak256
+)
+
+func (h HasherType) String() string {
+	switch h {
+	case HasherTypeMiMCBN254:
+		return "mimc-bn254"
+	case HasherTypeMiMCBW6761:
+		return "mimc-bw6-761"
+	case HasherTypeSHA3_256:
+		return "sha3-256"
+	case HasherTypeKeccak256:
+		return "keccak256"
+	default:
+		return "unknown"
+	}
+}
+
+// GetHasher returns a hasher by name rather than by key type, Keccak256 is the legacy
+// pre-standard SHA3 used by Ethereum and differs from SHA3-256 in its padding
+func GetHasher(hasherType HasherType) (hash.Hash, error) {
+	switch hasherType {
+	case HasherTypeMiMCBN254:
+		return GetHasherByType(KeyTypeSecp256k1), nil
+	case HasherTypeMiMCBW6761:
+		return GetHasherByType(KeyTypeBLS12377), nil
+	case HasherTypeSHA3_256:
+		return sha3.New256(), nil
+	case HasherTypeKeccak256:
+		return sha3.NewLegacyKeccak256(), nil
+	default:
+		return nil, fmt.Errorf("invalid hasher type: %d", hasherType)
+	}
+}
+
+// Keccak256 hashes the concatenation of data with Ethereum's Keccak256
+func Keccak256(data ...[]byte) []byte {
+	hasher := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		hasher.Write(d)
+	}
+	return hasher.Sum(nil)
+}
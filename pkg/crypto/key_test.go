@@ -0,0 +1,85 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestGetHasher(t *testing.T) {
+	tests := []struct {
+		hasherType HasherType
+		input      string
+		want       string
+	}{
+		{HasherTypeKeccak256, "", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{HasherTypeSHA3_256, "", "a7ffc6f8bf1ed76651c14756a061d662f580ff4de43b49fa82d80a4b80f8434a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hasherType.String(), func(t *testing.T) {
+			hasher, err := GetHasher(tt.hasherType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hasher.Write([]byte(tt.input))
+			if got := hex.EncodeToString(hasher.Sum(nil)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := hex.EncodeToString(Keccak256([]byte("a"), []byte("bc"))); got != "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45" {
+		t.Errorf("Keccak256() returned %s", got)
+	}
+	if _, err := GetHasher(HasherType(99)); err == nil {
+		t.Error("expected an error for an unknown hasher type")
+	}
+}
+
+// benchmarkKey returns a seeded key of keyType and a 32 byte message, the size of a commitment
+func benchmarkKey(b *testing.B, keyType KeyType) (ULKey, []byte) {
+	b.Helper()
+	key, err := GetKeyByType(keyType, GetHasherByType(keyType))
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := key.GenerateKeyFromSeed([]byte("benchmark")); err != nil {
+		b.Fatal(err)
+	}
+	message := Keccak256([]byte("commitment"))
+	// MiMC hashers read the message as field elements, keep it below the modulus
+	message[0] = 0
+	return key, message
+}
+
+func BenchmarkSignData(b *testing.B) {
+	for _, keyType := range SupportedKeyTypes() {
+		b.Run(keyType.String(), func(b *testing.B) {
+			key, message := benchmarkKey(b, keyType)
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := key.SignData(message); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkVerifySignature(b *testing.B) {
+	for _, keyType := range SupportedKeyTypes() {
+		b.Run(keyType.String(), func(b *testing.B) {
+			key, message := benchmarkKey(b, keyType)
+			signature, err := key.SignData(message)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ReportAllocs()
+			for b.Loop() {
+				if ok, err := key.VerifySignature(message, signature); err != nil || !ok {
+					b.Fatalf("VerifySignature() = %v, %v", ok, err)
+				}
+			}
+		})
+	}
+}
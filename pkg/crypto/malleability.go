@@ -0,0 +1,100 @@
+package crypto
+
+import (
+	"fmt"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+// ECDSA signatures are malleable, (r, n - s) verifies wherever (r, s) does. Secp256k1 signatures are
+// always produced with the low s and, unless disabled with SetRejectHighS, verification refuses the
+// high s so every signature has a single valid form, as in Bitcoin and Ethereum tooling.
+
+var (
+	secp256k1Order     = fr.Modulus()
+	secp256k1HalfOrder = new(big.Int).Rsh(fr.Modulus(), 1)
+	rejectHighS        atomic.Bool
+)
+
+func init() {
+	rejectHighS.Store(true)
+}
+
+// ErrHighS is returned when verifying a secp256k1 signature whose s is above half the curve order
+type ErrHighS struct {
+	Msg string
+}
+
+func (e *ErrHighS) Error() string {
+	return fmt.Sprintf("non canonical signature, %s", e.Msg)
+}
+
+func (e *ErrHighS) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// SetRejectHighS sets whether secp256k1 verification refuses high s signatures, which it does by
+// default. Disable it only to verify legacy signatures, NormalizeLowS converts them instead.
+func SetRejectHighS(reject bool) {
+	rejectHighS.Store(reject)
+}
+
+// RejectsHighS reports whether secp256k1 verification refuses high s signatures
+func RejectsHighS() bool {
+	return rejectHighS.Load()
+}
+
+// IsLowS reports whether the raw r || s secp256k1 signature has s at most half the curve order
+func IsLowS(signature []byte) (bool, error) {
+	s, err := signatureS(signature)
+	if err != nil {
+		return false, err
+	}
+	return s.Cmp(secp256k1HalfOrder) <= 0, nil
+}
+
+// NormalizeLowS returns the raw r || s secp256k1 signature with s replaced by n - s when it is high.
+// Both forms verify, so legacy signatures can be converted without the private key.
+func NormalizeLowS(signature []byte) ([]byte, error) {
+	s, err := signatureS(signature)
+	if err != nil {
+		return nil, err
+	}
+	normalized := append([]byte(nil), signature...)
+	if s.Cmp(secp256k1HalfOrder) > 0 {
+		s.Sub(secp256k1Order, s)
+		s.FillBytes(normalized[32:])
+	}
+	return normalized, nil
+}
+
+// NormalizeSignature converts a signature of keyType given in encoding to its low s form in the same
+// encoding. Only secp256k1 signatures are malleable, others are returned unchanged.
+func NormalizeSignature(keyType KeyType, signature []byte, encoding SignatureEncoding) ([]byte, error) {
+	if keyType != KeyTypeSecp256k1 {
+		return signature, nil
+	}
+	raw, err := DecodeSignature(keyType, signature, encoding)
+	if err != nil {
+		return nil, err
+	}
+	normalized, err := NormalizeLowS(raw)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSignature(keyType, normalized, encoding)
+}
+
+func signatureS(signature []byte) (*big.Int, error) {
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("expected a 64 bytes r || s signature, got %d bytes", len(signature))
+	}
+	s := new(big.Int).SetBytes(signature[32:])
+	if s.Sign() == 0 || s.Cmp(secp256k1Order) >= 0 {
+		return nil, fmt.Errorf("s is out of range")
+	}
+	return s, nil
+}
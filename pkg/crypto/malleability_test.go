@@ -0,0 +1,67 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+func TestLowSNormalization(t *testing.T) {
+	key := NewSecp256k1Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("malleability test seed")); err != nil {
+		t.Fatalf("GenerateKeyFromSeed() error = %v", err)
+	}
+
+	for i := 0; i < 16; i++ {
+		message := []byte(fmt.Sprintf("message %d", i))
+		signature, err := key.SignData(message)
+		if err != nil {
+			t.Fatalf("SignData() error = %v", err)
+		}
+		if low, err := IsLowS(signature); err != nil || !low {
+			t.Fatalf("SignData() produced a high s signature: %x, %v", signature, err)
+		}
+
+		// The mirrored signature is the legacy high s form of the same signature
+		high := append([]byte(nil), signature...)
+		new(big.Int).Sub(secp256k1Order, new(big.Int).SetBytes(signature[32:])).FillBytes(high[32:])
+		highErr := &ErrHighS{}
+		if ok, err := key.VerifySignature(message, high); ok || !errors.As(err, &highErr) {
+			t.Fatalf("VerifySignature() = %v, %v, want the high s form rejected", ok, err)
+		}
+
+		SetRejectHighS(false)
+		ok, err := key.VerifySignature(message, high)
+		SetRejectHighS(true)
+		if err != nil || !ok {
+			t.Fatalf("VerifySignature() = %v, %v, want legacy signatures accepted when allowed", ok, err)
+		}
+
+		normalized, err := NormalizeLowS(high)
+		if err != nil || !bytes.Equal(normalized, signature) {
+			t.Fatalf("NormalizeLowS() = %x, %v, want %x", normalized, err, signature)
+		}
+		if again, _ := NormalizeLowS(signature); !bytes.Equal(again, signature) {
+			t.Fatal("NormalizeLowS() changed a low s signature")
+		}
+
+		der, _ := RawToDER(high)
+		normalizedDER, err := NormalizeSignature(KeyTypeSecp256k1, der, SIGNATURE_ENCODING_DER)
+		if err != nil {
+			t.Fatalf("NormalizeSignature() error = %v", err)
+		}
+		if ok, err := VerifySignatureWithEncoding(key, message, normalizedDER, SIGNATURE_ENCODING_DER); err != nil || !ok {
+			t.Fatalf("VerifySignatureWithEncoding() = %v, %v", ok, err)
+		}
+	}
+
+	if _, err := NormalizeLowS(make([]byte, 64)); err == nil {
+		t.Fatal("NormalizeLowS() accepted s = 0")
+	}
+	ed := []byte("not an ecdsa signature")
+	if out, err := NormalizeSignature(KeyTypeED25519, ed, SIGNATURE_ENCODING_RAW); err != nil || !bytes.Equal(out, ed) {
+		t.Fatalf("NormalizeSignature() = %x, %v, want ed25519 signatures unchanged", out, err)
+	}
+}
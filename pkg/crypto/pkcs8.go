@@ -0,0 +1,112 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+	"strings"
+)
+
+var (
+	oidPublicKeyECDSA      = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidPublicKeyEd25519    = asn1.ObjectIdentifier{1, 3, 101, 112}
+	oidNamedCurveSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+	oidNamedCurveP256      = asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}
+)
+
+// pkcs8 is the PrivateKeyInfo structure of RFC 5208, the optional attributes are ignored
+type pkcs8 struct {
+	Version    int
+	Algo       pkix.AlgorithmIdentifier
+	PrivateKey []byte
+}
+
+// ecPrivateKey is the SEC1 structure of RFC 5915
+type ecPrivateKey struct {
+	Version       int
+	PrivateKey    []byte
+	NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+	PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+}
+
+// ParsePrivateKeyPEM imports a PKCS#8 "PRIVATE KEY" or SEC1 "EC PRIVATE KEY" PEM block.
+// Encrypted keys must be decrypted first, e.g. with openssl pkey.
+func ParsePrivateKeyPEM(data []byte) (ULKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+	switch block.Type {
+	case "PRIVATE KEY", "EC PRIVATE KEY":
+		return ParsePrivateKeyDER(block.Bytes)
+	case "ENCRYPTED PRIVATE KEY":
+		return nil, fmt.Errorf("encrypted private keys are not supported, decrypt the key first")
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type: %s", block.Type)
+	}
+}
+
+// ParsePrivateKeyDER imports a DER encoded PKCS#8 or SEC1 private key. Ed25519 and secp256k1 keys
+// are supported, other curves such as P-256 have no ULKey implementation yet.
+func ParsePrivateKeyDER(der []byte) (ULKey, error) {
+	info := pkcs8{}
+	if rest, err := asn1.Unmarshal(der, &info); err == nil && len(rest) == 0 {
+		switch {
+		case info.Algo.Algorithm.Equal(oidPublicKeyEd25519):
+			parsed, err := x509.ParsePKCS8PrivateKey(der)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ed25519 key, %w", err)
+			}
+			privateKey := parsed.(ed25519.PrivateKey)
+			return &ED25519Key{privateKey: privateKey, publicKey: privateKey.Public().(ed25519.PublicKey)}, nil
+		case info.Algo.Algorithm.Equal(oidPublicKeyECDSA):
+			curve := asn1.ObjectIdentifier{}
+			if _, err := asn1.Unmarshal(info.Algo.Parameters.FullBytes, &curve); err != nil {
+				return nil, fmt.Errorf("invalid elliptic curve parameters, %w", err)
+			}
+			return parseECPrivateKey(info.PrivateKey, curve)
+		default:
+			return nil, fmt.Errorf("unsupported private key algorithm: %s", info.Algo.Algorithm)
+		}
+	}
+
+	return parseECPrivateKey(der, nil)
+}
+
+// parseECPrivateKey decodes a SEC1 key, curve is taken from the key itself when nil
+func parseECPrivateKey(der []byte, curve asn1.ObjectIdentifier) (ULKey, error) {
+	key := ecPrivateKey{}
+	if _, err := asn1.Unmarshal(der, &key); err != nil {
+		return nil, fmt.Errorf("not a PKCS#8 or SEC1 private key, %w", err)
+	}
+	if curve == nil {
+		curve = key.NamedCurveOID
+	}
+
+	switch {
+	case curve.Equal(oidNamedCurveSecp256k1):
+	case curve.Equal(oidNamedCurveP256):
+		return nil, fmt.Errorf("P-256 keys are not supported yet")
+	default:
+		return nil, fmt.Errorf("unsupported elliptic curve: %s", curve)
+	}
+
+	if len(key.PrivateKey) > 32 {
+		return nil, fmt.Errorf("invalid secp256k1 private key length: %d", len(key.PrivateKey))
+	}
+	scalar := make([]byte, 32)
+	copy(scalar[32-len(key.PrivateKey):], key.PrivateKey)
+
+	secp256k1Key := NewSecp256k1Key(nil)
+	if err := secp256k1Key.GeneratePrivateKeyFromHex(BytesToHex(scalar)); err != nil {
+		return nil, err
+	}
+	// The embedded public key is optional, when present it must match the scalar
+	if len(key.PublicKey.Bytes) > 0 && !strings.EqualFold(BytesToHex(key.PublicKey.Bytes), secp256k1Key.GetPublicKeyHex(false)) {
+		return nil, fmt.Errorf("the embedded public key does not match the private key")
+	}
+	return secp256k1Key, nil
+}
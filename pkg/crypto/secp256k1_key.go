@@ -35,13 +35,22 @@ func (key *Secp256k1Key) SignData(data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("private key is not set")
 	}
 	hasher := GetHasherByType(KeyTypeSecp256k1)
-	return key.privateKey.Sign(data, hasher)
+	signature, err := key.privateKey.Sign(data, hasher)
+	if err != nil {
+		return nil, err
+	}
+	return NormalizeLowS(signature)
 }
 
 func (key *Secp256k1Key) VerifySignature(message []byte, signature []byte) (bool, error) {
 	if key.publicKey == nil {
 		return false, fmt.Errorf("public key is not set")
 	}
+	if RejectsHighS() {
+		if low, err := IsLowS(signature); err == nil && !low {
+			return false, &ErrHighS{Msg: "s is above half the curve order, normalize it with NormalizeLowS"}
+		}
+	}
 	hasher := GetHasherByType(KeyTypeSecp256k1)
 	return key.publicKey.Verify(signature, message, hasher)
 }
@@ -89,6 +98,12 @@ func (key *Secp256k1Key) GeneratePrivateKeyFromHex(hex string) error {
 	if len(hexBytes) != 32 {
 		return fmt.Errorf("expected 32 bytes, got %d", len(hexBytes))
 	}
+	// Derive the public key when only the scalar is known
+	if key.publicKey == nil {
+		var publicKey ecdsa.PublicKey
+		publicKey.A.ScalarMultiplicationBase(new(big.Int).SetBytes(hexBytes))
+		key.publicKey = &publicKey
+	}
 	// Get the public key bytes
 	publicKeyBytes := make([]byte, 96)
 	copy(publicKeyBytes[0:64], key.publicKey.Bytes())
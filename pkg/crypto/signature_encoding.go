@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// SignatureEncoding is the wire format of a signature. ULKey implementations always sign and verify
+// SIGNATURE_ENCODING_RAW, DER only exists for ECDSA keys whose raw form is r || s.
+type SignatureEncoding string
+
+const (
+	SIGNATURE_ENCODING_RAW SignatureEncoding = "raw"
+	SIGNATURE_ENCODING_DER SignatureEncoding = "der"
+)
+
+// ParseSignatureEncoding parses an encoding name, the empty string is the raw default
+func ParseSignatureEncoding(name string) (SignatureEncoding, error) {
+	switch SignatureEncoding(name) {
+	case "", SIGNATURE_ENCODING_RAW:
+		return SIGNATURE_ENCODING_RAW, nil
+	case SIGNATURE_ENCODING_DER:
+		return SIGNATURE_ENCODING_DER, nil
+	default:
+		return "", fmt.Errorf("unknown signature encoding: %s", name)
+	}
+}
+
+// SupportsSignatureEncoding reports whether signatures of keyType can use encoding
+func SupportsSignatureEncoding(keyType KeyType, encoding SignatureEncoding) bool {
+	switch encoding {
+	case "", SIGNATURE_ENCODING_RAW:
+		return true
+	case SIGNATURE_ENCODING_DER:
+		return keyType == KeyTypeSecp256k1
+	default:
+		return false
+	}
+}
+
+// ecdsaSignature is the ASN.1 structure of a DER encoded ECDSA signature
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// RawToDER converts an r || s signature with equally sized halves to DER
+func RawToDER(raw []byte) ([]byte, error) {
+	if len(raw) == 0 || len(raw)%2 != 0 {
+		return nil, fmt.Errorf("raw signature must be r || s, got %d bytes", len(raw))
+	}
+	half := len(raw) / 2
+	return asn1.Marshal(ecdsaSignature{
+		R: new(big.Int).SetBytes(raw[:half]),
+		S: new(big.Int).SetBytes(raw[half:]),
+	})
+}
+
+// DERToRaw converts a DER signature to r || s with each half left padded to size bytes
+func DERToRaw(der []byte, size int) ([]byte, error) {
+	signature := ecdsaSignature{}
+	rest, err := asn1.Unmarshal(der, &signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DER signature, %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("invalid DER signature, %d trailing bytes", len(rest))
+	}
+	// Re-encoding must give back the input, which rules out non canonical BER forms
+	canonical, err := asn1.Marshal(signature)
+	if err != nil || string(canonical) != string(der) {
+		return nil, fmt.Errorf("invalid DER signature, the encoding is not canonical")
+	}
+	if signature.R.Sign() <= 0 || signature.S.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid DER signature, r and s must be positive")
+	}
+	if len(signature.R.Bytes()) > size || len(signature.S.Bytes()) > size {
+		return nil, fmt.Errorf("invalid DER signature, r or s exceeds %d bytes", size)
+	}
+	raw := make([]byte, 2*size)
+	signature.R.FillBytes(raw[:size])
+	signature.S.FillBytes(raw[size:])
+	return raw, nil
+}
+
+// EncodeSignature converts a raw signature produced by a key of keyType to encoding
+func EncodeSignature(keyType KeyType, raw []byte, encoding SignatureEncoding) ([]byte, error) {
+	if !SupportsSignatureEncoding(keyType, encoding) {
+		return nil, fmt.Errorf("%s signatures cannot be encoded as %s", keyType, encoding)
+	}
+	if encoding == SIGNATURE_ENCODING_DER {
+		return RawToDER(raw)
+	}
+	return raw, nil
+}
+
+// DecodeSignature converts a signature in encoding back to the raw form ULKey verifies
+func DecodeSignature(keyType KeyType, signature []byte, encoding SignatureEncoding) ([]byte, error) {
+	if !SupportsSignatureEncoding(keyType, encoding) {
+		return nil, fmt.Errorf("%s signatures cannot be encoded as %s", keyType, encoding)
+	}
+	if encoding == SIGNATURE_ENCODING_DER {
+		capabilities, err := Capabilities(keyType)
+		if err != nil {
+			return nil, err
+		}
+		return DERToRaw(signature, capabilities.SignatureSize/2)
+	}
+	return signature, nil
+}
+
+// SignDataWithEncoding signs data with key and returns the signature in encoding
+func SignDataWithEncoding(key ULKey, data []byte, encoding SignatureEncoding) ([]byte, error) {
+	if !SupportsSignatureEncoding(key.GetType(), encoding) {
+		return nil, fmt.Errorf("%s signatures cannot be encoded as %s", key.GetType(), encoding)
+	}
+	raw, err := key.SignData(data)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeSignature(key.GetType(), raw, encoding)
+}
+
+// VerifySignatureWithEncoding verifies a signature given in encoding
+func VerifySignatureWithEncoding(key ULKey, message []byte, signature []byte, encoding SignatureEncoding) (bool, error) {
+	raw, err := DecodeSignature(key.GetType(), signature, encoding)
+	if err != nil {
+		return false, err
+	}
+	return key.VerifySignature(message, raw)
+}
@@ -0,0 +1,52 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignatureEncoding(t *testing.T) {
+	key := NewSecp256k1Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("signature encoding test seed")); err != nil {
+		t.Fatalf("GenerateKeyFromSeed() error = %v", err)
+	}
+	message := []byte("encode me")
+
+	der, err := SignDataWithEncoding(key, message, SIGNATURE_ENCODING_DER)
+	if err != nil {
+		t.Fatalf("SignDataWithEncoding() error = %v", err)
+	}
+	if ok, err := VerifySignatureWithEncoding(key, message, der, SIGNATURE_ENCODING_DER); err != nil || !ok {
+		t.Fatalf("VerifySignatureWithEncoding() = %v, %v", ok, err)
+	}
+
+	raw, err := DERToRaw(der, 32)
+	if err != nil || len(raw) != 64 {
+		t.Fatalf("DERToRaw() = %d bytes, %v", len(raw), err)
+	}
+	if ok, _ := key.VerifySignature(message, raw); !ok {
+		t.Fatal("the converted signature does not verify as raw")
+	}
+	back, err := RawToDER(raw)
+	if err != nil || !bytes.Equal(back, der) {
+		t.Fatalf("RawToDER() did not round trip: %x, %v", back, err)
+	}
+
+	// Small r and s values keep their leading zeros in the raw form
+	small := append(make([]byte, 31), 1)
+	small = append(small, append(make([]byte, 31), 2)...)
+	der, _ = RawToDER(small)
+	if raw, err := DERToRaw(der, 32); err != nil || !bytes.Equal(raw, small) {
+		t.Fatalf("DERToRaw() = %x, %v", raw, err)
+	}
+
+	if _, err := DERToRaw(append(der, 0), 32); err == nil {
+		t.Fatal("DERToRaw() accepted trailing bytes")
+	}
+	if _, err := SignDataWithEncoding(NewED25519Key(nil), message, SIGNATURE_ENCODING_DER); err == nil {
+		t.Fatal("SignDataWithEncoding() DER encoded an ed25519 signature")
+	}
+	if _, err := ParseSignatureEncoding("base64"); err == nil {
+		t.Fatal("ParseSignatureEncoding() accepted an unknown encoding")
+	}
+}
@@ -3,10 +3,10 @@ package crypto
 import (
 	"crypto/sha1"
 	"encoding/hex"
-	"fmt"
 	"io"
 	"strings"
 
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
 	"golang.org/x/crypto/pbkdf2"
 )
 
@@ -51,7 +51,7 @@ func (r *deterministicReader) Read(p []byte) (n int, err error) {
 func HexToBytes(h string) ([]byte, error) {
 	data, err := hex.DecodeString(h)
 	if err != nil {
-		return nil, fmt.Errorf("unable to decode input string, %w", err)
+		return nil, &utils.ErrMalformed{What: "hex", Msg: err.Error()}
 	}
 
 	return data, nil
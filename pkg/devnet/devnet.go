@@ -0,0 +1,402 @@
+package devnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Runtime selects how the devnet node is provided
+type Runtime string
+
+const (
+	// RUNTIME_DOCKER runs Image in a container
+	RUNTIME_DOCKER Runtime = "docker"
+	// RUNTIME_BINARY runs a node binary as a child process
+	RUNTIME_BINARY Runtime = "binary"
+	// RUNTIME_EXTERNAL attaches to a node that is already running at Endpoint
+	RUNTIME_EXTERNAL Runtime = "external"
+)
+
+const (
+	DEFAULT_IMAGE           = "uledger/node:latest"
+	DEFAULT_NODE_PORT       = 8080
+	DEFAULT_BLOCKCHAIN_ID   = "devnet"
+	DEFAULT_STARTUP_TIMEOUT = time.Minute
+	// PORT_PLACEHOLDER in binary Args and Env is replaced by the port the node should listen on
+	PORT_PLACEHOLDER = "{port}"
+)
+
+type Config struct {
+	Runtime Runtime
+	// Image is the node image for RUNTIME_DOCKER
+	Image string
+	// Binary is the node executable for RUNTIME_BINARY
+	Binary string
+	// Args are passed to the binary or appended to the container command
+	Args []string
+	// Env holds KEY=VALUE pairs for the node
+	Env []string
+	// Port is the host port of the node API, zero picks a free port
+	Port int
+	// Endpoint is the node URL for RUNTIME_EXTERNAL
+	Endpoint string
+	// BlockchainId is created when the node does not serve it yet
+	BlockchainId string
+	// Funder holds the devnet token balance, usually a genesis wallet, and pays for Fund
+	Funder *wallet.UL_Wallet
+	// TokenAddress is the token Fund transfers
+	TokenAddress string
+	// StartupTimeout bounds how long Start waits for the node to become healthy
+	StartupTimeout time.Duration
+	// Logs receives the node output, defaults to discarding it
+	Logs io.Writer
+}
+
+// Devnet is a local node prepared for integration tests and examples
+type Devnet struct {
+	config   Config
+	endpoint string
+
+	cmd         *exec.Cmd
+	containerId string
+
+	mu     sync.Mutex
+	funder *transaction.UL_TransactionSession
+}
+
+// Start launches the node, waits until it is healthy and makes sure the configured chain exists
+func Start(ctx context.Context, config Config) (*Devnet, error) {
+	if config.BlockchainId == "" {
+		config.BlockchainId = DEFAULT_BLOCKCHAIN_ID
+	}
+	if config.StartupTimeout <= 0 {
+		config.StartupTimeout = DEFAULT_STARTUP_TIMEOUT
+	}
+	if config.Logs == nil {
+		config.Logs = io.Discard
+	}
+	d := &Devnet{config: config}
+
+	if err := d.launch(ctx); err != nil {
+		return nil, err
+	}
+	if err := d.waitHealthy(ctx); err != nil {
+		d.Stop(context.Background())
+		return nil, err
+	}
+	if err := d.CreateChain(ctx, config.BlockchainId); err != nil {
+		d.Stop(context.Background())
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Devnet) launch(ctx context.Context) error {
+	switch d.config.Runtime {
+	case RUNTIME_EXTERNAL:
+		if d.config.Endpoint == "" {
+			return fmt.Errorf("an endpoint is required for the external runtime")
+		}
+		d.endpoint = strings.TrimSuffix(d.config.Endpoint, "/")
+		return nil
+	case RUNTIME_DOCKER, RUNTIME_BINARY:
+	default:
+		return fmt.Errorf("unknown devnet runtime %q", d.config.Runtime)
+	}
+
+	port := d.config.Port
+	if port == 0 {
+		free, err := freePort()
+		if err != nil {
+			return err
+		}
+		port = free
+	}
+	d.endpoint = fmt.Sprintf("http://127.0.0.1:%d", port)
+
+	if d.config.Runtime == RUNTIME_DOCKER {
+		image := d.config.Image
+		if image == "" {
+			image = DEFAULT_IMAGE
+		}
+		args := []string{"run", "-d", "--rm", "-p", fmt.Sprintf("127.0.0.1:%d:%d", port, DEFAULT_NODE_PORT)}
+		for _, env := range d.config.Env {
+			args = append(args, "-e", env)
+		}
+		args = append(append(args, image), d.config.Args...)
+		out, err := exec.CommandContext(ctx, "docker", args...).Output()
+		if err != nil {
+			return fmt.Errorf("unable to start the node container: %w", commandError(err))
+		}
+		d.containerId = strings.TrimSpace(string(out))
+		return nil
+	}
+
+	if d.config.Binary == "" {
+		return fmt.Errorf("a node binary is required for the binary runtime")
+	}
+	// The process must outlive ctx, which only bounds the startup
+	d.cmd = exec.Command(d.config.Binary, withPort(d.config.Args, port)...)
+	d.cmd.Env = append(os.Environ(), withPort(d.config.Env, port)...)
+	d.cmd.Stdout = d.config.Logs
+	d.cmd.Stderr = d.config.Logs
+	if err := d.cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start the node binary: %w", err)
+	}
+	return nil
+}
+
+// waitHealthy polls /health until the node answers or the startup timeout expires
+func (d *Devnet) waitHealthy(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.StartupTimeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+"/health", nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+			err = fmt.Errorf("health returned status %d", resp.StatusCode)
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("the node at %s did not become healthy: %w", d.endpoint, lastErr)
+		case <-time.After(200 * time.Millisecond):
+		}
+	}
+}
+
+// Endpoint is the node URL to pass to NewUL_TransactionSession
+func (d *Devnet) Endpoint() string {
+	return d.endpoint
+}
+
+// BlockchainId is the chain the devnet was started with
+func (d *Devnet) BlockchainId() string {
+	return d.config.BlockchainId
+}
+
+// CreateChain creates blockchainId on the node, an existing chain is left as is
+func (d *Devnet) CreateChain(ctx context.Context, blockchainId string) error {
+	chains, err := d.chains(ctx)
+	if err != nil {
+		return err
+	}
+	for _, id := range chains {
+		if id == blockchainId {
+			return nil
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{"blockchainId": blockchainId})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.endpoint+"/blockchains", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to create blockchain %s: %w", blockchainId, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		message, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unable to create blockchain %s, status %d: %s", blockchainId, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
+
+func (d *Devnet) chains(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.endpoint+"/blockchains", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	chains := make([]string, 0)
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing blockchains returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&chains); err != nil {
+		return nil, err
+	}
+	return chains, nil
+}
+
+// NewSession creates a wallet, registers it on the devnet chain, funds it with amount tokens when
+// amount is positive and returns a session signing with it
+func (d *Devnet) NewSession(ctx context.Context, keyType crypto.KeyType, amount uint64) (*transaction.UL_TransactionSession, error) {
+	parent := ""
+	if d.config.Funder != nil {
+		parent = d.config.Funder.Address
+	}
+	w, _, err := wallet.GenerateNewWallet("", keyType, parent, nil, wallet.DefaultEntropy)
+	if err != nil {
+		return nil, err
+	}
+	session, err := transaction.NewUL_TransactionSession(d.endpoint, w)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(transaction.CreateWalletPayload{
+		PublicKey:  w.GetKey().GetPublicKeyHex(false),
+		Parent:     w.Parent,
+		KeyType:    keyType,
+		AuthGroups: w.AuthGroups,
+	})
+	if err != nil {
+		return nil, err
+	}
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: d.config.BlockchainId,
+		From:         w.Parent,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_CREATE_WALLET.String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to register wallet %s: %w", w.Address, err)
+	}
+	if tx.Status == transaction.TX_REJECTED.String() {
+		return nil, fmt.Errorf("wallet %s was rejected: %s", w.Address, tx.Output)
+	}
+
+	if amount > 0 {
+		if err := d.Fund(ctx, w.Address, amount); err != nil {
+			return nil, err
+		}
+	}
+	return &session, nil
+}
+
+// Fund transfers amount of the devnet token from the funder to address, funding an address with the
+// same amount twice in a second waits for the next second instead of failing as a duplicate
+func (d *Devnet) Fund(ctx context.Context, address string, amount uint64) error {
+	if d.config.Funder == nil || d.config.TokenAddress == "" {
+		return fmt.Errorf("funding needs a funder wallet and a token address")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.funder == nil {
+		session, err := transaction.NewUL_TransactionSession(d.endpoint, *d.config.Funder)
+		if err != nil {
+			return err
+		}
+		d.funder = &session
+	}
+
+	payload, err := json.Marshal(transaction.TransferTokenPayload{
+		TokenAddress: d.config.TokenAddress,
+		To:           address,
+		Amount:       transaction.NewAmount(amount),
+	})
+	if err != nil {
+		return err
+	}
+	input := transaction.ULTransactionInput{
+		BlockchainId: d.config.BlockchainId,
+		To:           d.config.TokenAddress,
+		Payload:      string(payload),
+		PayloadType:  transaction.TRANSFER_TOKEN.String(),
+	}
+	tx, err := d.funder.GenerateTransaction(input)
+	if err == nil && tx.Output == transaction.TX_REJECTED_BY_DUPLICATE.String() {
+		// Timestamps have a one second resolution, funding an address twice in a second is a duplicate
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Until(time.Now().Truncate(time.Second).Add(time.Second))):
+		}
+		tx, err = d.funder.GenerateTransaction(input)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to fund %s: %w", address, err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return fmt.Errorf("funding %s was not applied: %s", address, tx.Output)
+	}
+	return nil
+}
+
+// Stop shuts the node down, external nodes are left running
+func (d *Devnet) Stop(ctx context.Context) error {
+	switch {
+	case d.containerId != "":
+		id := d.containerId
+		d.containerId = ""
+		if err := exec.CommandContext(ctx, "docker", "rm", "-f", id).Run(); err != nil {
+			return fmt.Errorf("unable to remove container %s: %w", id, commandError(err))
+		}
+	case d.cmd != nil && d.cmd.Process != nil:
+		cmd := d.cmd
+		d.cmd = nil
+		if err := cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+		cmd.Wait()
+	}
+	return nil
+}
+
+// withPort replaces PORT_PLACEHOLDER in values
+func withPort(values []string, port int) []string {
+	replaced := make([]string, len(values))
+	for i, value := range values {
+		replaced[i] = strings.ReplaceAll(value, PORT_PLACEHOLDER, strconv.Itoa(port))
+	}
+	return replaced
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, fmt.Errorf("unable to find a free port: %w", err)
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// commandError adds the stderr of a failed command to the error
+func commandError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
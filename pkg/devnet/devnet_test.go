@@ -0,0 +1,108 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testToken = "devnet-token"
+
+func TestExternalDevnet(t *testing.T) {
+	node := transactiontest.NewMockNode()
+	defer node.Close()
+	funder, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	node.SetBalance(testToken, funder.Address, 1000)
+
+	ctx := context.Background()
+	d, err := Start(ctx, Config{
+		Runtime:      RUNTIME_EXTERNAL,
+		Endpoint:     node.URL(),
+		Funder:       &funder,
+		TokenAddress: testToken,
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer d.Stop(ctx)
+
+	session, err := d.NewSession(ctx, crypto.KeyTypeED25519, 250)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	address := session.GetWallet().Address
+	if balance := node.Balance(testToken, address); balance != 250 {
+		t.Fatalf("balance = %d, want 250", balance)
+	}
+	if height, err := session.GetBlockHeight(ctx, DEFAULT_BLOCKCHAIN_ID); err != nil || height != 2 {
+		t.Fatalf("GetBlockHeight() = %d, %v, want the registration and the funding", height, err)
+	}
+
+	// Creating the chain again is a no-op
+	if err := d.CreateChain(ctx, DEFAULT_BLOCKCHAIN_ID); err != nil {
+		t.Fatalf("CreateChain() error = %v", err)
+	}
+	if err := d.Fund(ctx, address, 5000); err == nil {
+		t.Fatal("Fund() succeeded beyond the funder balance")
+	}
+}
+
+// TestHelperNode is not a real test, it is the node binary started by TestBinaryDevnet
+func TestHelperNode(t *testing.T) {
+	port := os.Getenv("DEVNET_HELPER_PORT")
+	if port == "" {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"nodeId":"helper","chainsInfo":{}}`)
+	})
+	mux.HandleFunc("GET /blockchains", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `["devnet"]`)
+	})
+	http.ListenAndServe("127.0.0.1:"+port, mux)
+	os.Exit(0)
+}
+
+func TestBinaryDevnet(t *testing.T) {
+	ctx := context.Background()
+	d, err := Start(ctx, Config{
+		Runtime:        RUNTIME_BINARY,
+		Binary:         os.Args[0],
+		Args:           []string{"-test.run=^TestHelperNode$"},
+		Env:            []string{"DEVNET_HELPER_PORT=" + PORT_PLACEHOLDER},
+		StartupTimeout: 10 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if !strings.HasPrefix(d.Endpoint(), "http://127.0.0.1:") {
+		t.Fatalf("Endpoint() = %s", d.Endpoint())
+	}
+	if err := d.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if _, err := http.Get(d.Endpoint() + "/health"); err == nil {
+		t.Fatal("the node is still running after Stop()")
+	}
+}
+
+func TestRequireSkipsWithoutRuntime(t *testing.T) {
+	if os.Getenv(ENV_RUNTIME) != "" {
+		t.Skip("a devnet is configured")
+	}
+	if _, ok, err := ConfigFromEnv(); ok || err != nil {
+		t.Fatalf("ConfigFromEnv() = %v, %v, want no config", ok, err)
+	}
+}
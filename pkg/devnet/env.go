@@ -0,0 +1,68 @@
+package devnet
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Environment variables read by ConfigFromEnv
+const (
+	ENV_RUNTIME         = "ULEDGER_DEVNET_RUNTIME"
+	ENV_IMAGE           = "ULEDGER_DEVNET_IMAGE"
+	ENV_BINARY          = "ULEDGER_DEVNET_BINARY"
+	ENV_ARGS            = "ULEDGER_DEVNET_ARGS"
+	ENV_ENDPOINT        = "ULEDGER_DEVNET_ENDPOINT"
+	ENV_BLOCKCHAIN_ID   = "ULEDGER_DEVNET_BLOCKCHAIN"
+	ENV_FUNDER          = "ULEDGER_DEVNET_FUNDER"
+	ENV_FUNDER_PASSWORD = "ULEDGER_DEVNET_FUNDER_PASSWORD"
+	ENV_TOKEN           = "ULEDGER_DEVNET_TOKEN"
+)
+
+// ConfigFromEnv builds a config from the ULEDGER_DEVNET_* variables, ok is false when no runtime is set.
+// ULEDGER_DEVNET_FUNDER is the path of a .ukey wallet file and ULEDGER_DEVNET_ARGS is split on spaces.
+func ConfigFromEnv() (Config, bool, error) {
+	runtime := os.Getenv(ENV_RUNTIME)
+	if runtime == "" {
+		return Config{}, false, nil
+	}
+	config := Config{
+		Runtime:      Runtime(runtime),
+		Image:        os.Getenv(ENV_IMAGE),
+		Binary:       os.Getenv(ENV_BINARY),
+		Args:         strings.Fields(os.Getenv(ENV_ARGS)),
+		Endpoint:     os.Getenv(ENV_ENDPOINT),
+		BlockchainId: os.Getenv(ENV_BLOCKCHAIN_ID),
+		TokenAddress: os.Getenv(ENV_TOKEN),
+	}
+	if path := os.Getenv(ENV_FUNDER); path != "" {
+		funder, err := wallet.LoadFromFile(path, os.Getenv(ENV_FUNDER_PASSWORD))
+		if err != nil {
+			return Config{}, false, err
+		}
+		config.Funder = &funder
+	}
+	return config, true, nil
+}
+
+// Require starts the devnet configured by the environment for an integration test and stops it when
+// the test ends. The test is skipped when ULEDGER_DEVNET_RUNTIME is not set.
+func Require(tb testing.TB) *Devnet {
+	tb.Helper()
+	config, ok, err := ConfigFromEnv()
+	if err != nil {
+		tb.Fatalf("invalid devnet configuration: %v", err)
+	}
+	if !ok {
+		tb.Skipf("%s is not set, skipping devnet test", ENV_RUNTIME)
+	}
+	d, err := Start(context.Background(), config)
+	if err != nil {
+		tb.Fatalf("unable to start devnet: %v", err)
+	}
+	tb.Cleanup(func() { d.Stop(context.Background()) })
+	return d
+}
@@ -0,0 +1,187 @@
+package devnet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// DEFAULT_POOL_INTERVAL paces the registrations and fundings of a pool, well below faucet limits
+const DEFAULT_POOL_INTERVAL = 100 * time.Millisecond
+
+type PoolConfig struct {
+	// Size is the number of wallets registered up front
+	Size int
+	// KeyType of the generated wallets, the zero value is secp256k1
+	KeyType crypto.KeyType
+	// Amount is funded to every wallet when it is registered
+	Amount uint64
+	// Refill is funded to a wallet every time it is released, zero returns wallets as they are
+	Refill uint64
+	// Interval is the minimum time between two provisioning transactions, defaults to DEFAULT_POOL_INTERVAL
+	Interval time.Duration
+}
+
+// Pool hands registered and funded wallets to parallel tests. A wallet is checked out by one test at a
+// time, so tests never race on the same wallet and never send each other's transactions twice, which
+// the node rejects as REJECTED_BY_DUPLICATE. Released wallets are recycled instead of registering new
+// ones for every test.
+type Pool struct {
+	devnet *Devnet
+	config PoolConfig
+	free   chan wallet.UL_Wallet
+
+	mu  sync.Mutex
+	out map[string]bool
+	// paced serializes provisioning transactions, last is when the previous one was sent
+	paced sync.Mutex
+	last  time.Time
+}
+
+// NewPool registers and funds config.Size wallets on the devnet chain
+func (d *Devnet) NewPool(ctx context.Context, config PoolConfig) (*Pool, error) {
+	if config.Size <= 0 {
+		return nil, fmt.Errorf("the pool size must be positive")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DEFAULT_POOL_INTERVAL
+	}
+	p := &Pool{
+		devnet: d,
+		config: config,
+		free:   make(chan wallet.UL_Wallet, config.Size),
+		out:    map[string]bool{},
+	}
+	for range config.Size {
+		w, err := p.provision(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.free <- w
+	}
+	return p, nil
+}
+
+// provision registers a new wallet and funds it with Amount
+func (p *Pool) provision(ctx context.Context) (wallet.UL_Wallet, error) {
+	if err := p.pace(ctx); err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	session, err := p.devnet.NewSession(ctx, p.config.KeyType, 0)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	w := session.GetWallet()
+	if p.config.Amount > 0 {
+		if err := p.fund(ctx, w.Address, p.config.Amount); err != nil {
+			return wallet.UL_Wallet{}, err
+		}
+	}
+	return w, nil
+}
+
+func (p *Pool) fund(ctx context.Context, address string, amount uint64) error {
+	if err := p.pace(ctx); err != nil {
+		return err
+	}
+	return p.devnet.Fund(ctx, address, amount)
+}
+
+// pace waits until Interval passed since the previous provisioning transaction
+func (p *Pool) pace(ctx context.Context) error {
+	p.paced.Lock()
+	defer p.paced.Unlock()
+	if wait := time.Until(p.last.Add(p.config.Interval)); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	p.last = time.Now()
+	return nil
+}
+
+// Checkout waits for a free wallet and returns a new session signing with it. The wallet belongs to the
+// caller until Release or Discard.
+func (p *Pool) Checkout(ctx context.Context) (*transaction.UL_TransactionSession, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case w := <-p.free:
+		session, err := transaction.NewUL_TransactionSession(p.devnet.endpoint, w)
+		if err != nil {
+			p.free <- w
+			return nil, err
+		}
+		p.mu.Lock()
+		p.out[w.Address] = true
+		p.mu.Unlock()
+		return &session, nil
+	}
+}
+
+// Release returns the wallet of a checked out session to the pool, funding it with Refill first. The
+// wallet is returned even when the refill fails.
+func (p *Pool) Release(ctx context.Context, session *transaction.UL_TransactionSession) error {
+	w := session.GetWallet()
+	if err := p.checkIn(w.Address); err != nil {
+		return err
+	}
+	defer func() { p.free <- w }()
+	if p.config.Refill > 0 {
+		if err := p.fund(ctx, w.Address, p.config.Refill); err != nil {
+			return fmt.Errorf("unable to refill pool wallet %s: %w", w.Address, err)
+		}
+	}
+	return nil
+}
+
+// Discard drops the wallet of a checked out session, for tests that broke it, e.g. by altering its
+// keys, and registers a replacement
+func (p *Pool) Discard(ctx context.Context, session *transaction.UL_TransactionSession) error {
+	address := session.GetWallet().Address
+	if err := p.checkIn(address); err != nil {
+		return err
+	}
+	w, err := p.provision(ctx)
+	if err != nil {
+		// The pool shrinks rather than handing out the broken wallet
+		return fmt.Errorf("unable to replace pool wallet %s: %w", address, err)
+	}
+	p.free <- w
+	return nil
+}
+
+func (p *Pool) checkIn(address string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.out[address] {
+		return fmt.Errorf("wallet %s is not checked out of the pool", address)
+	}
+	delete(p.out, address)
+	return nil
+}
+
+// Acquire checks out a wallet for a test and releases it when the test ends
+func (p *Pool) Acquire(tb testing.TB) *transaction.UL_TransactionSession {
+	tb.Helper()
+	session, err := p.Checkout(tb.Context())
+	if err != nil {
+		tb.Fatalf("unable to check out a pool wallet: %v", err)
+	}
+	tb.Cleanup(func() {
+		if err := p.Release(context.Background(), session); err != nil {
+			tb.Errorf("unable to release pool wallet: %v", err)
+		}
+	})
+	return session
+}
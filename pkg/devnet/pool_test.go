@@ -0,0 +1,124 @@
+package devnet
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestPool(t *testing.T) {
+	node := transactiontest.NewMockNode()
+	defer node.Close()
+	funder, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	node.SetBalance(testToken, funder.Address, 1000)
+
+	ctx := context.Background()
+	d, err := Start(ctx, Config{Runtime: RUNTIME_EXTERNAL, Endpoint: node.URL(), Funder: &funder, TokenAddress: testToken})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if _, err := d.NewPool(ctx, PoolConfig{}); err == nil {
+		t.Fatal("NewPool() accepted an empty pool")
+	}
+
+	interval := 20 * time.Millisecond
+	started := time.Now()
+	pool, err := d.NewPool(ctx, PoolConfig{Size: 2, Amount: 100, Refill: 10, Interval: interval})
+	if err != nil {
+		t.Fatalf("NewPool() error = %v", err)
+	}
+	// Two registrations and two fundings, paced
+	if elapsed := time.Since(started); elapsed < 3*interval {
+		t.Fatalf("provisioning took %v, want at least %v", elapsed, 3*interval)
+	}
+
+	first, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	second, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if first.GetWallet().Address == second.GetWallet().Address {
+		t.Fatal("a wallet was checked out twice")
+	}
+	if balance := node.Balance(testToken, first.GetWallet().Address); balance != 100 {
+		t.Fatalf("balance = %d, want 100", balance)
+	}
+	tx, err := first.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: DEFAULT_BLOCKCHAIN_ID,
+		To:           first.GetWallet().Address,
+		Payload:      "pooled",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil || tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("GenerateTransaction() = %s, %v, the pool wallet is not registered", tx.Output, err)
+	}
+
+	// The pool is exhausted until a wallet is released
+	timeout, cancel := context.WithTimeout(ctx, 3*interval)
+	defer cancel()
+	if _, err := pool.Checkout(timeout); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Checkout() of an exhausted pool error = %v", err)
+	}
+	if err := pool.Release(ctx, first); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if err := pool.Release(ctx, first); err == nil {
+		t.Fatal("Release() accepted a wallet twice")
+	}
+	if balance := node.Balance(testToken, first.GetWallet().Address); balance != 110 {
+		t.Fatalf("balance after the refill = %d, want 110", balance)
+	}
+	recycled, err := pool.Checkout(ctx)
+	if err != nil || recycled.GetWallet().Address != first.GetWallet().Address {
+		t.Fatalf("Checkout() did not recycle the released wallet: %v", err)
+	}
+
+	// Discarded wallets are replaced by new ones
+	if err := pool.Discard(ctx, second); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	replacement, err := pool.Checkout(ctx)
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+	if address := replacement.GetWallet().Address; address == second.GetWallet().Address || node.Balance(testToken, address) != 100 {
+		t.Fatalf("replacement %s is not a new funded wallet", address)
+	}
+	pool.Release(ctx, recycled)
+	pool.Release(ctx, replacement)
+
+	// Parallel tests share the pool without sharing wallets
+	var mu sync.Mutex
+	inUse := map[string]bool{}
+	t.Run("parallel", func(t *testing.T) {
+		for range 4 {
+			t.Run("worker", func(t *testing.T) {
+				t.Parallel()
+				address := pool.Acquire(t).GetWallet().Address
+				mu.Lock()
+				if inUse[address] {
+					t.Errorf("wallet %s is used by two tests", address)
+				}
+				inUse[address] = true
+				mu.Unlock()
+				time.Sleep(interval)
+				mu.Lock()
+				delete(inUse, address)
+				mu.Unlock()
+			})
+		}
+	})
+}
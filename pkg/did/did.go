@@ -0,0 +1,237 @@
+package did
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	DID_METHOD  = "uledger"
+	DID_PREFIX  = "did:" + DID_METHOD + ":"
+	DID_CONTEXT = "https://www.w3.org/ns/did/v1"
+)
+
+// Verification method types, secp256k1 and ed25519 use the registered suites while the
+// BLS and ML-DSA keys have no registered suite yet and use ULedger specific names
+const (
+	VERIFICATION_TYPE_SECP256K1 = "EcdsaSecp256k1VerificationKey2019"
+	VERIFICATION_TYPE_ED25519   = "Ed25519VerificationKey2018"
+	VERIFICATION_TYPE_BLS12377  = "ULedgerBls12377VerificationKey2025"
+	VERIFICATION_TYPE_MLDSA87   = "ULedgerMlDsa87VerificationKey2025"
+)
+
+type ErrInvalidDID struct {
+	Msg string
+}
+
+func (e *ErrInvalidDID) Error() string {
+	return fmt.Sprintf("invalid did, %s", e.Msg)
+}
+
+func (e *ErrInvalidDID) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// DID identifies a wallet on a blockchain: did:uledger:<blockchainId>:<address>
+type DID struct {
+	BlockchainId string
+	Address      string
+}
+
+// New returns the DID of address on blockchainId
+func New(blockchainId string, address string) DID {
+	return DID{BlockchainId: blockchainId, Address: strings.ToLower(address)}
+}
+
+func (d DID) String() string {
+	return DID_PREFIX + d.BlockchainId + ":" + d.Address
+}
+
+// Parse parses a did:uledger identifier, a fragment such as #key-1 is ignored
+func Parse(identifier string) (DID, error) {
+	identifier, _, _ = strings.Cut(identifier, "#")
+	rest, ok := strings.CutPrefix(identifier, DID_PREFIX)
+	if !ok {
+		return DID{}, &ErrInvalidDID{Msg: fmt.Sprintf("%s is not a did:%s identifier", identifier, DID_METHOD)}
+	}
+	blockchainId, address, ok := strings.Cut(rest, ":")
+	if !ok || blockchainId == "" || address == "" {
+		return DID{}, &ErrInvalidDID{Msg: fmt.Sprintf("%s must be did:%s:<blockchainId>:<address>", identifier, DID_METHOD)}
+	}
+	if b, err := hex.DecodeString(address); err != nil || len(b) != 32 {
+		return DID{}, &ErrInvalidDID{Msg: fmt.Sprintf("%s is not a wallet address", address)}
+	}
+	return New(blockchainId, address), nil
+}
+
+// VerificationMethod is a public key the DID subject controls, keys are hex encoded in the
+// uncompressed form wallets use to derive their address
+type VerificationMethod struct {
+	Id           string         `json:"id"`
+	Type         string         `json:"type"`
+	Controller   string         `json:"controller"`
+	KeyType      crypto.KeyType `json:"keyType"`
+	PublicKeyHex string         `json:"publicKeyHex"`
+}
+
+type Service struct {
+	Id              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// Document is a W3C DID document. Verification relationships reference methods by id,
+// relative references such as #key-1 are resolved against the document id.
+type Document struct {
+	Context            []string             `json:"@context"`
+	Id                 string               `json:"id"`
+	Controller         []string             `json:"controller,omitempty"`
+	VerificationMethod []VerificationMethod `json:"verificationMethod"`
+	Authentication     []string             `json:"authentication,omitempty"`
+	AssertionMethod    []string             `json:"assertionMethod,omitempty"`
+	Service            []Service            `json:"service,omitempty"`
+}
+
+// VerificationMethodType returns the verification method type used for keys of keyType
+func VerificationMethodType(keyType crypto.KeyType) string {
+	switch keyType {
+	case crypto.KeyTypeED25519:
+		return VERIFICATION_TYPE_ED25519
+	case crypto.KeyTypeBLS12377:
+		return VERIFICATION_TYPE_BLS12377
+	case crypto.KeyTypeMlDSA87:
+		return VERIFICATION_TYPE_MLDSA87
+	default:
+		return VERIFICATION_TYPE_SECP256K1
+	}
+}
+
+// NewDocument generates the DID document of w on blockchainId. The wallet key becomes #key-1
+// and is used for both authentication and assertions.
+func NewDocument(blockchainId string, w *wallet.UL_Wallet) (Document, error) {
+	if w.GetKey() == nil {
+		return Document{}, fmt.Errorf("wallet has no key")
+	}
+	doc := Document{
+		Context: []string{DID_CONTEXT},
+		Id:      New(blockchainId, w.Address).String(),
+	}
+	id, err := doc.AddVerificationMethod(w.GetKey().GetType(), w.GetKey().GetPublicKeyHex(false))
+	if err != nil {
+		return Document{}, err
+	}
+	doc.Authentication = []string{id}
+	doc.AssertionMethod = []string{id}
+	return doc, nil
+}
+
+// AddVerificationMethod adds a key controlled by the subject and returns its relative id
+func (doc *Document) AddVerificationMethod(keyType crypto.KeyType, publicKeyHex string) (string, error) {
+	if _, err := hex.DecodeString(publicKeyHex); err != nil {
+		return "", fmt.Errorf("invalid public key: %w", err)
+	}
+	id := fmt.Sprintf("#key-%d", len(doc.VerificationMethod)+1)
+	doc.VerificationMethod = append(doc.VerificationMethod, VerificationMethod{
+		Id:           id,
+		Type:         VerificationMethodType(keyType),
+		Controller:   doc.Id,
+		KeyType:      keyType,
+		PublicKeyHex: strings.ToLower(publicKeyHex),
+	})
+	return id, nil
+}
+
+// AddService advertises an endpoint, e.g. a messaging inbox, under #<name>
+func (doc *Document) AddService(name string, serviceType string, endpoint string) {
+	doc.Service = append(doc.Service, Service{Id: "#" + name, Type: serviceType, ServiceEndpoint: endpoint})
+}
+
+// Subject returns the parsed document id
+func (doc *Document) Subject() (DID, error) {
+	return Parse(doc.Id)
+}
+
+// GetVerificationMethod looks a method up by absolute or relative id
+func (doc *Document) GetVerificationMethod(id string) (VerificationMethod, bool) {
+	fragment := id
+	if i := strings.Index(id, "#"); i >= 0 {
+		if i > 0 && id[:i] != doc.Id {
+			return VerificationMethod{}, false
+		}
+		fragment = id[i:]
+	}
+	for _, method := range doc.VerificationMethod {
+		if method.Id == fragment || method.Id == doc.Id+fragment {
+			return method, true
+		}
+	}
+	return VerificationMethod{}, false
+}
+
+// Authorizes reports whether the method is listed under the relationship, e.g. doc.AssertionMethod
+func (doc *Document) Authorizes(relationship []string, methodId string) bool {
+	method, ok := doc.GetVerificationMethod(methodId)
+	if !ok {
+		return false
+	}
+	for _, ref := range relationship {
+		if candidate, ok := doc.GetVerificationMethod(ref); ok && candidate.Id == method.Id {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the document is well formed: a did:uledger id, decodable keys and relationships
+// that point at existing methods
+func (doc *Document) Validate() error {
+	if _, err := doc.Subject(); err != nil {
+		return err
+	}
+	for _, controller := range doc.Controller {
+		if _, err := Parse(controller); err != nil {
+			return fmt.Errorf("invalid controller: %w", err)
+		}
+	}
+	ids := map[string]bool{}
+	for _, method := range doc.VerificationMethod {
+		if ids[method.Id] {
+			return fmt.Errorf("duplicate verification method %s", method.Id)
+		}
+		ids[method.Id] = true
+		if _, err := method.Key(); err != nil {
+			return fmt.Errorf("verification method %s: %w", method.Id, err)
+		}
+	}
+	for _, ref := range append(append([]string{}, doc.Authentication...), doc.AssertionMethod...) {
+		if _, ok := doc.GetVerificationMethod(ref); !ok {
+			return fmt.Errorf("unknown verification method %s", ref)
+		}
+	}
+	return nil
+}
+
+// Key rebuilds the public key of the method so signatures can be checked against it
+func (method VerificationMethod) Key() (crypto.ULKey, error) {
+	if method.Type != VerificationMethodType(method.KeyType) {
+		return nil, fmt.Errorf("type %s does not match key type %s", method.Type, method.KeyType)
+	}
+	key, err := crypto.GetKeyByType(method.KeyType, crypto.GetHasherByType(method.KeyType))
+	if err != nil {
+		return nil, err
+	}
+	if err := key.GeneratePublicKeyFromHex(false, method.PublicKeyHex); err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	return key, nil
+}
+
+// Address returns the wallet address derived from the method's key
+func (method VerificationMethod) Address() string {
+	return wallet.ParseAddress(method.PublicKeyHex)
+}
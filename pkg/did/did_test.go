@@ -0,0 +1,217 @@
+package did_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func newDocument(t *testing.T, session *transaction.UL_TransactionSession) did.Document {
+	t.Helper()
+	w := session.GetWallet()
+	doc, err := did.NewDocument(testBlockchainId, &w)
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+	return doc
+}
+
+func TestParseDID(t *testing.T) {
+	address := "8ba1f109551bd432803012645ac136ddd64dba72f1c5f5c4c7f2e4d8b7d7f6e5"
+	id, err := did.Parse("did:uledger:" + testBlockchainId + ":" + address + "#key-1")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if id.BlockchainId != testBlockchainId || id.Address != address {
+		t.Fatalf("Parse() = %+v", id)
+	}
+	if id.String() != "did:uledger:"+testBlockchainId+":"+address {
+		t.Fatalf("String() = %s", id.String())
+	}
+
+	for _, invalid := range []string{"did:web:example.com", "did:uledger:" + address, "did:uledger:chain:not-an-address"} {
+		var errInvalid *did.ErrInvalidDID
+		if _, err := did.Parse(invalid); !errors.As(err, &errInvalid) {
+			t.Errorf("Parse(%s) error = %v, want ErrInvalidDID", invalid, err)
+		}
+	}
+}
+
+func TestNewDocumentPerKeyType(t *testing.T) {
+	types := map[crypto.KeyType]string{
+		crypto.KeyTypeSecp256k1: did.VERIFICATION_TYPE_SECP256K1,
+		crypto.KeyTypeED25519:   did.VERIFICATION_TYPE_ED25519,
+		crypto.KeyTypeBLS12377:  did.VERIFICATION_TYPE_BLS12377,
+		crypto.KeyTypeMlDSA87:   did.VERIFICATION_TYPE_MLDSA87,
+	}
+	for keyType, methodType := range types {
+		w, _, err := wallet.GenerateNewWallet("", keyType, "", nil, wallet.DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet(%s) error = %v", keyType, err)
+		}
+		doc, err := did.NewDocument(testBlockchainId, &w)
+		if err != nil {
+			t.Fatalf("NewDocument(%s) error = %v", keyType, err)
+		}
+		if err := doc.Validate(); err != nil {
+			t.Fatalf("Validate(%s) error = %v", keyType, err)
+		}
+
+		method, ok := doc.GetVerificationMethod(doc.Id + "#key-1")
+		if !ok {
+			t.Fatalf("%s: #key-1 not found", keyType)
+		}
+		if method.Type != methodType {
+			t.Errorf("%s: type = %s, want %s", keyType, method.Type, methodType)
+		}
+		if method.Address() != w.Address {
+			t.Errorf("%s: key does not derive the wallet address", keyType)
+		}
+		if !doc.Authorizes(doc.AssertionMethod, method.Id) {
+			t.Errorf("%s: #key-1 is not an assertion method", keyType)
+		}
+	}
+}
+
+func TestPublishAndResolve(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	session, _ := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	resolver := did.NewResolver(session)
+	ctx := context.Background()
+
+	doc := newDocument(t, session)
+	if _, err := resolver.Resolve(ctx, doc.Id); !errors.As(err, new(*did.ErrNotFound)) {
+		t.Fatalf("Resolve() before create error = %v, want ErrNotFound", err)
+	}
+
+	created, err := did.Create(session, doc)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	resolution, err := resolver.Resolve(ctx, doc.Id)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolution.Metadata.VersionId != created.TransactionId {
+		t.Fatalf("VersionId = %s, want %s", resolution.Metadata.VersionId, created.TransactionId)
+	}
+
+	doc.AddService("inbox", "ULedgerMessaging", "https://example.com/inbox")
+	updated, err := did.Update(session, doc)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	resolution, err = resolver.Resolve(ctx, doc.Id)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolution.Metadata.VersionId != updated.TransactionId || len(resolution.Document.Service) != 1 {
+		t.Fatalf("Resolve() after update = %+v", resolution)
+	}
+
+	if _, err := did.Deactivate(session, doc.Id); err != nil {
+		t.Fatalf("Deactivate() error = %v", err)
+	}
+	if _, err := did.Update(session, doc); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	resolution, err = resolver.Resolve(ctx, doc.Id)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if !resolution.Metadata.Deactivated {
+		t.Fatalf("Resolve() after deactivate = %+v, want deactivated", resolution.Metadata)
+	}
+}
+
+func TestResolveIgnoresUnauthorizedUpdates(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	owner, _ := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	controller, _ := node.NewSession(t, crypto.KeyTypeED25519, "")
+	stranger, _ := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	ctx := context.Background()
+
+	doc := newDocument(t, owner)
+	controllerDoc := newDocument(t, controller)
+	doc.Controller = []string{controllerDoc.Id}
+	if _, err := did.Create(owner, doc); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	forged := doc
+	forged.VerificationMethod = nil
+	forged.Authentication = nil
+	forged.AssertionMethod = nil
+	strangerWallet := stranger.GetWallet()
+	if _, err := forged.AddVerificationMethod(crypto.KeyTypeSecp256k1, strangerWallet.GetKey().GetPublicKeyHex(false)); err != nil {
+		t.Fatalf("AddVerificationMethod() error = %v", err)
+	}
+	if _, err := did.Update(stranger, forged); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	resolution, err := did.NewResolver(owner).Resolve(ctx, doc.Id)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolution.Document.VerificationMethod[0].PublicKeyHex != doc.VerificationMethod[0].PublicKeyHex {
+		t.Fatal("a stranger replaced the document")
+	}
+
+	if _, err := did.Update(controller, forged); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	resolution, err = did.NewResolver(owner).Resolve(ctx, doc.Id)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if resolution.Document.VerificationMethod[0].PublicKeyHex == doc.VerificationMethod[0].PublicKeyHex {
+		t.Fatal("the controller could not update the document")
+	}
+}
+
+func TestResolverFollowsReorgs(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	session, _ := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	resolver := did.NewResolver(session)
+	ctx := context.Background()
+
+	doc := newDocument(t, session)
+	created, err := did.Create(session, doc)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := resolver.Resolve(ctx, doc.Id); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+
+	if err := node.ReplaceBlock(testBlockchainId, created.BlockHeight, true); err != nil {
+		t.Fatalf("ReplaceBlock() error = %v", err)
+	}
+	node.AppendEmptyBlock(testBlockchainId)
+	if _, err := resolver.Resolve(ctx, doc.Id); !errors.As(err, new(*did.ErrNotFound)) {
+		t.Fatalf("Resolve() after reorg error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestParseOperation(t *testing.T) {
+	if _, ok := did.ParseOperation(`{"hello":"world"}`); ok {
+		t.Fatal("ParseOperation() accepted a plain payload")
+	}
+	doc := did.Document{Id: "did:uledger:a:b"}
+	payload, _ := json.Marshal(did.OperationPayload{Operation: did.OPERATION_UPDATE, DID: "did:uledger:a:c", Document: &doc})
+	if _, ok := did.ParseOperation(string(payload)); ok {
+		t.Fatal("ParseOperation() accepted a document for another DID")
+	}
+}
@@ -0,0 +1,89 @@
+package did
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// Operation is the kind of change a DID transaction makes to a document
+type Operation string
+
+const (
+	OPERATION_CREATE     Operation = "create"
+	OPERATION_UPDATE     Operation = "update"
+	OPERATION_DEACTIVATE Operation = "deactivate"
+)
+
+// OperationPayload is the TX_DATA payload that carries a DID operation. The transaction must be
+// sent by the subject's wallet or by a controller listed in the current document.
+type OperationPayload struct {
+	Operation Operation `json:"didOperation"`
+	DID       string    `json:"did"`
+	Document  *Document `json:"didDocument,omitempty"`
+}
+
+// ParseOperation decodes a DID operation from a transaction payload, ok is false for any other payload
+func ParseOperation(payload string) (OperationPayload, bool) {
+	op := OperationPayload{}
+	if err := json.Unmarshal([]byte(payload), &op); err != nil || op.Operation == "" || op.DID == "" {
+		return OperationPayload{}, false
+	}
+	switch op.Operation {
+	case OPERATION_CREATE, OPERATION_UPDATE:
+		if op.Document == nil || op.Document.Id != op.DID {
+			return OperationPayload{}, false
+		}
+	case OPERATION_DEACTIVATE:
+	default:
+		return OperationPayload{}, false
+	}
+	return op, true
+}
+
+// Create publishes a new DID document. Payloads are bound to the transaction signature so the
+// document must fit in a DATA payload, which rules out ML-DSA keys.
+func Create(session *transaction.UL_TransactionSession, doc Document) (transaction.ULTransaction, error) {
+	return publish(session, OperationPayload{Operation: OPERATION_CREATE, DID: doc.Id, Document: &doc})
+}
+
+// Update replaces the published document of doc.Id
+func Update(session *transaction.UL_TransactionSession, doc Document) (transaction.ULTransaction, error) {
+	return publish(session, OperationPayload{Operation: OPERATION_UPDATE, DID: doc.Id, Document: &doc})
+}
+
+// Deactivate permanently retires the DID, later operations on it are ignored
+func Deactivate(session *transaction.UL_TransactionSession, identifier string) (transaction.ULTransaction, error) {
+	if _, err := Parse(identifier); err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	return publish(session, OperationPayload{Operation: OPERATION_DEACTIVATE, DID: identifier})
+}
+
+func publish(session *transaction.UL_TransactionSession, op OperationPayload) (transaction.ULTransaction, error) {
+	subject, err := Parse(op.DID)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	if op.Document != nil {
+		if err := op.Document.Validate(); err != nil {
+			return transaction.ULTransaction{}, err
+		}
+	}
+	payload, err := json.Marshal(op)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: subject.BlockchainId,
+		To:           subject.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		return transaction.ULTransaction{}, fmt.Errorf("unable to publish %s: %w", op.DID, err)
+	}
+	return tx, nil
+}
@@ -0,0 +1,174 @@
+package did
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+type ErrNotFound struct {
+	Msg string
+}
+
+func (e *ErrNotFound) Error() string {
+	return fmt.Sprintf("did not found, %s", e.Msg)
+}
+
+func (e *ErrNotFound) Is(target error) bool {
+	return target == utils.ErrNotFound
+}
+
+// DocumentMetadata describes the history of a resolved document
+type DocumentMetadata struct {
+	Created     time.Time `json:"created"`
+	Updated     time.Time `json:"updated"`
+	VersionId   string    `json:"versionId"` // Id of the transaction that published the current version
+	BlockHeight int       `json:"blockHeight"`
+	Deactivated bool      `json:"deactivated,omitempty"`
+}
+
+type Resolution struct {
+	Document Document         `json:"didDocument"`
+	Metadata DocumentMetadata `json:"didDocumentMetadata"`
+}
+
+// Resolver resolves did:uledger identifiers by replaying the DID operations found in the chain's
+// blocks. Each chain is indexed once and then only the new blocks are read on every Resolve.
+type Resolver struct {
+	session *transaction.UL_TransactionSession
+
+	mu     sync.Mutex
+	chains map[string]*chainIndex
+}
+
+type chainIndex struct {
+	height    int
+	hash      string
+	documents map[string]*Resolution
+}
+
+func NewResolver(session *transaction.UL_TransactionSession) *Resolver {
+	return &Resolver{
+		session: session,
+		chains:  make(map[string]*chainIndex),
+	}
+}
+
+// Resolve returns the current document of identifier. Deactivated DIDs resolve with
+// Metadata.Deactivated set and the last document published before deactivation.
+func (r *Resolver) Resolve(ctx context.Context, identifier string) (Resolution, error) {
+	subject, err := Parse(identifier)
+	if err != nil {
+		return Resolution{}, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	index, err := r.sync(ctx, subject.BlockchainId)
+	if err != nil {
+		return Resolution{}, err
+	}
+	resolution, ok := index.documents[subject.String()]
+	if !ok {
+		return Resolution{}, &ErrNotFound{Msg: subject.String()}
+	}
+	return *resolution, nil
+}
+
+// sync reads the blocks added since the last call, a reorg below the indexed height rebuilds the index
+func (r *Resolver) sync(ctx context.Context, blockchainId string) (*chainIndex, error) {
+	index, ok := r.chains[blockchainId]
+	if !ok {
+		index = &chainIndex{documents: make(map[string]*Resolution)}
+		r.chains[blockchainId] = index
+	}
+
+	height, err := r.session.GetBlockHeight(ctx, blockchainId)
+	if err != nil {
+		return nil, err
+	}
+	for index.height < height {
+		block, err := r.session.GetBlock(ctx, blockchainId, index.height+1)
+		if err != nil {
+			return nil, err
+		}
+		if index.height > 0 && block.PreviousBlockHash != index.hash {
+			*index = chainIndex{documents: make(map[string]*Resolution)}
+			continue
+		}
+		for _, tx := range block.Transactions {
+			index.apply(tx, block.Height)
+		}
+		index.height = block.Height
+		index.hash = block.Hash
+	}
+	return index, nil
+}
+
+// apply replays one transaction, anything that is not a valid and authorized operation is skipped
+func (index *chainIndex) apply(tx transaction.ULTransaction, height int) {
+	if tx.PayloadType != transaction.TX_DATA.String() || tx.Output != transaction.TX_SUCCESS.String() {
+		return
+	}
+	op, ok := ParseOperation(tx.GetPayload())
+	if !ok {
+		return
+	}
+	subject, err := Parse(op.DID)
+	if err != nil || subject.BlockchainId != tx.BlockchainId {
+		return
+	}
+
+	current, exists := index.documents[subject.String()]
+	if !index.authorized(subject, current, tx.From) {
+		return
+	}
+	at := tx.Timestamp.ExactTime
+
+	switch op.Operation {
+	case OPERATION_CREATE:
+		if exists || op.Document.Validate() != nil {
+			return
+		}
+		index.documents[subject.String()] = &Resolution{
+			Document: *op.Document,
+			Metadata: DocumentMetadata{Created: at, Updated: at, VersionId: tx.TransactionId, BlockHeight: height},
+		}
+	case OPERATION_UPDATE:
+		if !exists || current.Metadata.Deactivated || op.Document.Validate() != nil {
+			return
+		}
+		current.Document = *op.Document
+		current.Metadata.Updated = at
+		current.Metadata.VersionId = tx.TransactionId
+		current.Metadata.BlockHeight = height
+	case OPERATION_DEACTIVATE:
+		if !exists || current.Metadata.Deactivated {
+			return
+		}
+		current.Metadata.Deactivated = true
+		current.Metadata.Updated = at
+		current.Metadata.VersionId = tx.TransactionId
+		current.Metadata.BlockHeight = height
+	}
+}
+
+// authorized allows the subject's own wallet and the controllers of the current document
+func (index *chainIndex) authorized(subject DID, current *Resolution, from string) bool {
+	if subject.Address == from {
+		return true
+	}
+	if current == nil {
+		return false
+	}
+	for _, controller := range current.Document.Controller {
+		if c, err := Parse(controller); err == nil && c.BlockchainId == subject.BlockchainId && c.Address == from {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,232 @@
+// Package exchange sends payloads to a single recipient off-chain while the chain only records their
+// hash and where to fetch them. The payload is encrypted to the recipient's exchange key, stored with
+// a pluggable Transport and anchored with a TX_DATA transaction addressed to the recipient. Receivers
+// fetch the ciphertext, check it against the anchored hash and decrypt it.
+//
+// Ciphertexts are sealed with an ephemeral X25519 key agreement, HKDF-SHA256 and AES-256-GCM. The
+// recipient address is authenticated data, so a ciphertext re-anchored for another wallet does not open.
+package exchange
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/internal/seal"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	// EXCHANGE_VERSION is the version of the sealed format and of Record
+	EXCHANGE_VERSION = 1
+	// MAX_EXCHANGE_SIZE bounds the ciphertexts transports fetch
+	MAX_EXCHANGE_SIZE = 64 << 20
+
+	keyInfo    = "uledger exchange key"
+	sealInfo   = "uledger exchange v1"
+	headerSize = 1 + 32 + 12
+)
+
+// ErrVerification is returned when fetched data does not match its anchor or does not decrypt
+type ErrVerification struct {
+	Msg string
+}
+
+func (e *ErrVerification) Error() string {
+	return fmt.Sprintf("exchange verification failed, %s", e.Msg)
+}
+
+func (e *ErrVerification) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Record is the TX_DATA payload anchoring an exchanged payload, the transaction is sent to the recipient
+type Record struct {
+	Version int `json:"exchangeVersion"`
+	// Sha256 is the hex hash of the ciphertext, anyone can check the stored data without the key
+	Sha256  string `json:"sha256"`
+	Pointer string `json:"pointer"`
+	Size    int    `json:"size"`
+}
+
+// ParseRecord decodes an exchange record from a transaction payload, ok is false for any other payload
+func ParseRecord(payload string) (Record, bool) {
+	record := Record{}
+	if err := json.Unmarshal([]byte(payload), &record); err != nil || record.Version == 0 || record.Sha256 == "" || record.Pointer == "" {
+		return Record{}, false
+	}
+	return record, true
+}
+
+// Receipt identifies an exchanged payload
+type Receipt struct {
+	BlockchainId  string `json:"blockchainId"`
+	TransactionId string `json:"transactionId"`
+	Record
+}
+
+// Message is a received payload with the sender that anchored it
+type Message struct {
+	From    string
+	Payload []byte
+	Record  Record
+}
+
+// ExchangeKey derives the X25519 exchange key of a wallet from its private key, so recipients keep
+// no other secret. Senders need the public half, see PublicExchangeKey.
+func ExchangeKey(w wallet.UL_Wallet) (*ecdh.PrivateKey, error) {
+	if err := w.CheckKey(); err != nil {
+		return nil, err
+	}
+	secret, err := crypto.HexToBytes(w.GetKey().GetPrivateKeyHex())
+	if err != nil {
+		return nil, err
+	}
+	seed, err := hkdf.Key(sha256.New, secret, nil, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPrivateKey(seed)
+}
+
+// PublicExchangeKey returns the hex public exchange key a wallet shares with its senders
+func PublicExchangeKey(w wallet.UL_Wallet) (string, error) {
+	key, err := ExchangeKey(w)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// ParsePublicExchangeKey decodes a key returned by PublicExchangeKey
+func ParsePublicExchangeKey(publicKeyHex string) (*ecdh.PublicKey, error) {
+	data, err := hex.DecodeString(publicKeyHex)
+	if err != nil {
+		return nil, &utils.ErrMalformed{What: "exchange key", Msg: err.Error()}
+	}
+	key, err := ecdh.X25519().NewPublicKey(data)
+	if err != nil {
+		return nil, &utils.ErrMalformed{What: "exchange key", Msg: err.Error()}
+	}
+	return key, nil
+}
+
+// Seal encrypts payload to the exchange key of recipient, the wallet address the record is sent to
+func Seal(payload []byte, recipientKey *ecdh.PublicKey, recipient string) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := sealer(ephemeral, recipientKey, ephemeral.PublicKey(), recipientKey)
+	if err != nil {
+		return nil, err
+	}
+	sealed := make([]byte, headerSize, headerSize+len(payload)+aead.Overhead())
+	sealed[0] = EXCHANGE_VERSION
+	copy(sealed[1:33], ephemeral.PublicKey().Bytes())
+	if _, err := rand.Read(sealed[33:headerSize]); err != nil {
+		return nil, err
+	}
+	return aead.Seal(sealed, sealed[33:headerSize], payload, []byte(recipient)), nil
+}
+
+// Open decrypts a ciphertext made by Seal for recipient
+func Open(sealed []byte, key *ecdh.PrivateKey, recipient string) ([]byte, error) {
+	if len(sealed) < headerSize || sealed[0] != EXCHANGE_VERSION {
+		return nil, &ErrVerification{Msg: "the data is not a sealed payload"}
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(sealed[1:33])
+	if err != nil {
+		return nil, &ErrVerification{Msg: err.Error()}
+	}
+	aead, err := sealer(key, ephemeral, ephemeral, key.PublicKey())
+	if err != nil {
+		return nil, err
+	}
+	payload, err := aead.Open(nil, sealed[33:headerSize], sealed[headerSize:], []byte(recipient))
+	if err != nil {
+		return nil, &ErrVerification{Msg: "the payload does not decrypt with the exchange key of " + recipient}
+	}
+	return payload, nil
+}
+
+// sealer derives the AEAD of a key agreement
+func sealer(private *ecdh.PrivateKey, public *ecdh.PublicKey, ephemeral *ecdh.PublicKey, recipient *ecdh.PublicKey) (cipher.AEAD, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, err
+	}
+	return seal.AEAD(shared, ephemeral.Bytes(), recipient.Bytes(), sealInfo)
+}
+
+// Send encrypts payload to recipient, stores it with transport and anchors its hash with a TX_DATA
+// transaction from the session's wallet to recipient
+func Send(ctx context.Context, session transaction.SessionAPI, transport Transport, blockchainId string, recipient string, recipientKey *ecdh.PublicKey, payload []byte) (Receipt, error) {
+	sealed, err := Seal(payload, recipientKey, recipient)
+	if err != nil {
+		return Receipt{}, err
+	}
+	digest := sha256.Sum256(sealed)
+	record := Record{Version: EXCHANGE_VERSION, Sha256: hex.EncodeToString(digest[:]), Size: len(sealed)}
+	record.Pointer, err = transport.Put(ctx, record.Sha256, sealed)
+	if err != nil {
+		return Receipt{}, fmt.Errorf("unable to store the sealed payload: %w", err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Receipt{}, err
+	}
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           recipient,
+		Payload:      string(data),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		return Receipt{}, fmt.Errorf("unable to anchor the exchange: %w", err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return Receipt{}, fmt.Errorf("the exchange anchor %s was not applied: %s", tx.TransactionId, tx.Output)
+	}
+	return Receipt{BlockchainId: tx.BlockchainId, TransactionId: tx.TransactionId, Record: record}, nil
+}
+
+// Receive fetches the payload anchored by a transaction sent to the owner of key, checks it against
+// the anchored hash and decrypts it
+func Receive(ctx context.Context, session transaction.QueryAPI, transport Transport, key *ecdh.PrivateKey, blockchainId string, transactionId string) (Message, error) {
+	tx, err := session.GetTransaction(ctx, blockchainId, transactionId)
+	if err != nil {
+		return Message{}, fmt.Errorf("unable to fetch the exchange anchor %s: %w", transactionId, err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return Message{}, &ErrVerification{Msg: fmt.Sprintf("anchor %s was not applied", transactionId)}
+	}
+	record, ok := ParseRecord(tx.GetPayload())
+	if !ok {
+		return Message{}, &ErrVerification{Msg: fmt.Sprintf("transaction %s does not anchor an exchange", transactionId)}
+	}
+
+	sealed, err := transport.Get(ctx, record.Pointer)
+	if err != nil {
+		return Message{}, fmt.Errorf("unable to fetch %s: %w", record.Pointer, err)
+	}
+	digest := sha256.Sum256(sealed)
+	if hex.EncodeToString(digest[:]) != record.Sha256 || len(sealed) != record.Size {
+		return Message{}, &ErrVerification{Msg: fmt.Sprintf("the data at %s does not match anchor %s", record.Pointer, transactionId)}
+	}
+	payload, err := Open(sealed, key, tx.To)
+	if err != nil {
+		return Message{}, err
+	}
+	return Message{From: tx.From, Payload: payload, Record: record}, nil
+}
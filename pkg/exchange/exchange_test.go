@@ -0,0 +1,148 @@
+package exchange_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/exchange"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+// tamperingTransport flips a byte of everything it returns while tamper is set
+type tamperingTransport struct {
+	*exchange.MemoryTransport
+	tamper bool
+}
+
+func (t *tamperingTransport) Get(ctx context.Context, pointer string) ([]byte, error) {
+	data, err := t.MemoryTransport.Get(ctx, pointer)
+	if err == nil && t.tamper {
+		data[len(data)-1] ^= 1
+	}
+	return data, err
+}
+
+func TestExchange(t *testing.T) {
+	ctx := context.Background()
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	sender, _ := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	receiver, recipient := node.NewSession(t, crypto.KeyTypeED25519, "")
+	_, other := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+
+	publicKeyHex, err := exchange.PublicExchangeKey(recipient)
+	if err != nil {
+		t.Fatalf("PublicExchangeKey() error = %v", err)
+	}
+	recipientKey, err := exchange.ParsePublicExchangeKey(publicKeyHex)
+	if err != nil {
+		t.Fatalf("ParsePublicExchangeKey() error = %v", err)
+	}
+
+	transport := &tamperingTransport{MemoryTransport: exchange.NewMemoryTransport()}
+	payload := []byte(`{"invoice":"INV-7","lines":[{"sku":"A1","qty":3}]}`)
+	receipt, err := exchange.Send(ctx, sender, transport, testBlockchainId, recipient.Address, recipientKey, payload)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	tx, err := sender.GetTransaction(ctx, testBlockchainId, receipt.TransactionId)
+	if err != nil {
+		t.Fatalf("GetTransaction() error = %v", err)
+	}
+	if tx.To != recipient.Address || strings.Contains(tx.Payload, "INV-7") {
+		t.Fatalf("anchor = %+v", tx.ULTransactionInput)
+	}
+
+	key, err := exchange.ExchangeKey(recipient)
+	if err != nil {
+		t.Fatalf("ExchangeKey() error = %v", err)
+	}
+	message, err := exchange.Receive(ctx, receiver, transport, key, testBlockchainId, receipt.TransactionId)
+	if err != nil {
+		t.Fatalf("Receive() error = %v", err)
+	}
+	if !bytes.Equal(message.Payload, payload) || message.From != sender.GetWallet().Address || message.Record != receipt.Record {
+		t.Fatalf("Receive() = %+v", message)
+	}
+
+	var verification *exchange.ErrVerification
+	transport.tamper = true
+	if _, err := exchange.Receive(ctx, receiver, transport, key, testBlockchainId, receipt.TransactionId); !errors.As(err, &verification) {
+		t.Fatalf("Receive() of tampered data error = %v, want ErrVerification", err)
+	}
+	transport.tamper = false
+	otherKey, _ := exchange.ExchangeKey(other)
+	if _, err := exchange.Receive(ctx, receiver, transport, otherKey, testBlockchainId, receipt.TransactionId); !errors.As(err, &verification) {
+		t.Fatalf("Receive() with another key error = %v, want ErrVerification", err)
+	}
+
+	// A ciphertext re-anchored for another recipient does not open for them
+	sealed, _ := exchange.Seal(payload, recipientKey, recipient.Address)
+	if _, err := exchange.Open(sealed, key, other.Address); !errors.As(err, &verification) {
+		t.Fatalf("Open() for another address error = %v, want ErrVerification", err)
+	}
+}
+
+func TestTransports(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	stored := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		// Presigned uploads carry their authorization in the URL
+		case r.Method == http.MethodPut && (r.Header.Get("Authorization") == "Bearer token" || r.URL.Query().Get("sig") == "put"):
+			stored[r.URL.Path], _ = io.ReadAll(r.Body)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v0/add":
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			stored["/ipfs/bafytest"], _ = io.ReadAll(file)
+			json.NewEncoder(w).Encode(map[string]string{"Name": "file", "Hash": "bafytest"})
+		case r.Method == http.MethodGet && stored[r.URL.Path] != nil:
+			w.Write(stored[r.URL.Path])
+		default:
+			http.Error(w, "denied", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	data := []byte("sealed bytes")
+	for name, transport := range map[string]exchange.Transport{
+		"http": exchange.HTTPTransport{BaseURL: server.URL + "/blobs", Header: http.Header{"Authorization": {"Bearer token"}}},
+		"presigned": exchange.PresignedTransport{Presign: func(ctx context.Context, id string) (string, string, error) {
+			return server.URL + "/bucket/" + id + "?sig=put", server.URL + "/bucket/" + id + "?sig=get", nil
+		}},
+		"ipfs": exchange.IPFSTransport{APIURL: server.URL, GatewayURL: server.URL},
+	} {
+		pointer, err := transport.Put(ctx, "abcd", data)
+		if err != nil {
+			t.Fatalf("%s Put() error = %v", name, err)
+		}
+		fetched, err := transport.Get(ctx, pointer)
+		if err != nil || !bytes.Equal(fetched, data) {
+			t.Fatalf("%s Get(%s) = %q, %v", name, pointer, fetched, err)
+		}
+	}
+
+	if _, err := (exchange.HTTPTransport{BaseURL: server.URL + "/blobs"}).Get(ctx, "http://169.254.169.254/latest"); err == nil {
+		t.Fatal("HTTPTransport fetched a pointer outside its base URL")
+	}
+	if _, err := (exchange.IPFSTransport{GatewayURL: server.URL}).Get(ctx, "ipfs://bafy/../admin"); err == nil {
+		t.Fatal("IPFSTransport fetched a path instead of a CID")
+	}
+}
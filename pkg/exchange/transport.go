@@ -0,0 +1,178 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Transport stores sealed payloads off-chain. Put returns the pointer recorded on-chain and Get
+// fetches the data behind it, data is checked against the anchor so transports need not be trusted.
+type Transport interface {
+	// Put stores data under id, the hex SHA-256 of data
+	Put(ctx context.Context, id string, data []byte) (string, error)
+	Get(ctx context.Context, pointer string) ([]byte, error)
+}
+
+// MemoryTransport keeps payloads in the process, for tests and for peers sharing one
+type MemoryTransport struct {
+	mu    sync.Mutex
+	items map[string][]byte
+}
+
+func NewMemoryTransport() *MemoryTransport {
+	return &MemoryTransport{items: map[string][]byte{}}
+}
+
+func (t *MemoryTransport) Put(ctx context.Context, id string, data []byte) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.items[id] = bytes.Clone(data)
+	return "memory:" + id, nil
+}
+
+func (t *MemoryTransport) Get(ctx context.Context, pointer string) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data, ok := t.items[strings.TrimPrefix(pointer, "memory:")]
+	if !ok {
+		return nil, fmt.Errorf("%s not found", pointer)
+	}
+	return bytes.Clone(data), nil
+}
+
+// HTTPTransport stores payloads on a server accepting PUT requests, pointers are the URLs of the
+// payloads and only URLs below BaseURL are fetched
+type HTTPTransport struct {
+	BaseURL string
+	// Header is added to every request, e.g. for authorization
+	Header http.Header
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+func (t HTTPTransport) Put(ctx context.Context, id string, data []byte) (string, error) {
+	url := strings.TrimSuffix(t.BaseURL, "/") + "/" + id
+	if _, err := do(ctx, t.Client, http.MethodPut, url, t.Header, "application/octet-stream", data); err != nil {
+		return "", err
+	}
+	return url, nil
+}
+
+func (t HTTPTransport) Get(ctx context.Context, pointer string) ([]byte, error) {
+	if !strings.HasPrefix(pointer, strings.TrimSuffix(t.BaseURL, "/")+"/") {
+		return nil, fmt.Errorf("%s is not served by %s", pointer, t.BaseURL)
+	}
+	return do(ctx, t.Client, http.MethodGet, pointer, t.Header, "", nil)
+}
+
+// PresignedTransport uploads to presigned URLs, like those of S3 or any compatible object store.
+// Presign returns the URL the payload is uploaded to and the URL recorded on-chain, download URLs
+// must stay valid until the recipient fetched the payload.
+type PresignedTransport struct {
+	Presign func(ctx context.Context, id string) (putURL string, getURL string, err error)
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+func (t PresignedTransport) Put(ctx context.Context, id string, data []byte) (string, error) {
+	putURL, getURL, err := t.Presign(ctx, id)
+	if err != nil {
+		return "", fmt.Errorf("unable to presign %s: %w", id, err)
+	}
+	if _, err := do(ctx, t.Client, http.MethodPut, putURL, nil, "application/octet-stream", data); err != nil {
+		return "", err
+	}
+	return getURL, nil
+}
+
+func (t PresignedTransport) Get(ctx context.Context, pointer string) ([]byte, error) {
+	if !strings.HasPrefix(pointer, "https://") && !strings.HasPrefix(pointer, "http://") {
+		return nil, fmt.Errorf("%s is not a URL", pointer)
+	}
+	return do(ctx, t.Client, http.MethodGet, pointer, nil, "", nil)
+}
+
+// IPFSTransport adds payloads through the HTTP API of an IPFS node and fetches them from a gateway,
+// pointers are ipfs://<cid> URIs
+type IPFSTransport struct {
+	// APIURL is the node RPC API, e.g. http://127.0.0.1:5001
+	APIURL string
+	// GatewayURL serves /ipfs/<cid>, e.g. https://ipfs.io
+	GatewayURL string
+	// Client defaults to http.DefaultClient
+	Client *http.Client
+}
+
+func (t IPFSTransport) Put(ctx context.Context, id string, data []byte) (string, error) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	file, err := form.CreateFormFile("file", id)
+	if err != nil {
+		return "", err
+	}
+	file.Write(data)
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+	response, err := do(ctx, t.Client, http.MethodPost, strings.TrimSuffix(t.APIURL, "/")+"/api/v0/add?pin=true", nil, form.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return "", err
+	}
+	added := struct{ Hash string }{}
+	if err := json.Unmarshal(response, &added); err != nil || added.Hash == "" {
+		return "", fmt.Errorf("unexpected IPFS add response: %s", response)
+	}
+	return "ipfs://" + added.Hash, nil
+}
+
+func (t IPFSTransport) Get(ctx context.Context, pointer string) ([]byte, error) {
+	cid, ok := strings.CutPrefix(pointer, "ipfs://")
+	if !ok || cid == "" || strings.ContainsAny(cid, "/?#") {
+		return nil, fmt.Errorf("%s is not an IPFS pointer", pointer)
+	}
+	return do(ctx, t.Client, http.MethodGet, strings.TrimSuffix(t.GatewayURL, "/")+"/ipfs/"+cid, nil, "", nil)
+}
+
+// do sends a request and returns the body of a 2xx response, at most MAX_EXCHANGE_SIZE bytes
+func do(ctx context.Context, client *http.Client, method string, url string, header http.Header, contentType string, data []byte) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var body io.Reader
+	if data != nil {
+		body = bytes.NewReader(data)
+	}
+	request, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range header {
+		request.Header[key] = values
+	}
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	content, err := io.ReadAll(io.LimitReader(response.Body, MAX_EXCHANGE_SIZE+1))
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s %s returned status %d: %s", method, url, response.StatusCode, bytes.TrimSpace(content[:min(len(content), 512)]))
+	}
+	if len(content) > MAX_EXCHANGE_SIZE {
+		return nil, fmt.Errorf("%s returned more than %d bytes", url, MAX_EXCHANGE_SIZE)
+	}
+	return content, nil
+}
@@ -0,0 +1,275 @@
+// Package explorer serves a read-only JSON API for browsing chains: blocks, decoded transactions,
+// wallets with their transactions, and a search box resolving heights, hashes and addresses. It is
+// backed by any transaction.QueryAPI, the wallet history comes from the transaction index of the node.
+// An internal explorer takes a few lines:
+//
+//	session, _ := transaction.NewUL_TransactionSession(nodeEndpoint, wallet.UL_Wallet{})
+//	http.Handle("/explorer/", http.StripPrefix("/explorer", explorer.NewHandler(&session, explorer.Options{})))
+package explorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/gateway"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// DEFAULT_PAGE_SIZE is the number of blocks or transactions of a listing when the request sets no limit
+const DEFAULT_PAGE_SIZE = 20
+
+type Options struct {
+	// BlockchainIds restricts the explorer to these chains, empty serves every chain of the node
+	BlockchainIds []string
+	// PageSize is the default limit of listings, DEFAULT_PAGE_SIZE when zero
+	PageSize int
+}
+
+// Page is a listing of the explorer, pass NextCursor as the cursor parameter for the next page
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// BlockSummary is a block without its transactions, as listings show it
+type BlockSummary struct {
+	Height            int    `json:"height"`
+	Hash              string `json:"hash"`
+	PreviousBlockHash string `json:"previousBlockHash"`
+	MerkleRoot        string `json:"merkleRoot"`
+	Transactions      int    `json:"transactions"`
+	Voters            int    `json:"voters"`
+}
+
+// ChainView is the landing page of a chain
+type ChainView struct {
+	Config      transaction.ChainConfig `json:"config"`
+	Height      int                     `json:"height"`
+	LatestBlock BlockSummary            `json:"latestBlock"`
+}
+
+// BlockView is a block with its transactions decoded
+type BlockView struct {
+	BlockSummary
+	Transactions []transaction.DecodedTransaction `json:"transactions"`
+}
+
+// WalletView is a wallet with a page of the transactions it sent or received
+type WalletView struct {
+	State        transaction.WalletState         `json:"state"`
+	Transactions Page[transaction.ULTransaction] `json:"transactions"`
+}
+
+// SearchResult tells what a search matched and the path of the explorer serving it
+type SearchResult struct {
+	// Kind is block, transaction or wallet
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+type handler struct {
+	query transaction.QueryAPI
+	opts  Options
+	mux   *http.ServeMux
+}
+
+// NewHandler serves the explorer API over query:
+//
+//	GET /chains/{blockchainId}                                     ChainView
+//	GET /chains/{blockchainId}/blocks?cursor=&limit=               Page of BlockSummary, oldest first
+//	GET /chains/{blockchainId}/blocks/latest                       BlockView
+//	GET /chains/{blockchainId}/blocks/{height}                     BlockView
+//	GET /chains/{blockchainId}/blocks/hash/{hash}                  BlockView
+//	GET /chains/{blockchainId}/transactions/{transactionId}        transaction.DecodedTransaction
+//	GET /chains/{blockchainId}/wallets/{address}?cursor=&limit=    WalletView
+//	GET /chains/{blockchainId}/search?q=                           SearchResult
+//
+// Failures answer with a gateway.ErrorResponse and the status of gateway.StatusCode.
+func NewHandler(query transaction.QueryAPI, opts Options) http.Handler {
+	if opts.PageSize <= 0 {
+		opts.PageSize = DEFAULT_PAGE_SIZE
+	}
+	h := &handler{query: query, opts: opts, mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET /chains/{blockchainId}", h.serve(h.chain))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/blocks", h.serve(h.blocks))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/blocks/latest", h.serve(h.latestBlock))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/blocks/{height}", h.serve(h.block))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/blocks/hash/{hash}", h.serve(h.blockByHash))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/transactions/{transactionId}", h.serve(h.transaction))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/wallets/{address}", h.serve(h.wallet))
+	h.mux.HandleFunc("GET /chains/{blockchainId}/search", h.serve(h.search))
+	return h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// serve checks the chain of the request is served and writes the response of view
+func (h *handler) serve(view func(r *http.Request, blockchainId string) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		blockchainId := r.PathValue("blockchainId")
+		if len(h.opts.BlockchainIds) > 0 && !slices.Contains(h.opts.BlockchainIds, blockchainId) {
+			gateway.WriteResponse(w, nil, fmt.Errorf("chain %s: %w", blockchainId, utils.ErrNotFound))
+			return
+		}
+		response, err := view(r, blockchainId)
+		gateway.WriteResponse(w, response, err)
+	}
+}
+
+// listOptions reads the cursor and limit parameters of a listing
+func (h *handler) listOptions(r *http.Request) (transaction.ListOptions, error) {
+	limit := h.opts.PageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 || parsed > transaction.MAX_PAGE_SIZE {
+			return transaction.ListOptions{}, &utils.ErrMalformed{What: "limit", Msg: fmt.Sprintf("it must be between 1 and %d", transaction.MAX_PAGE_SIZE)}
+		}
+		limit = parsed
+	}
+	return transaction.ListOptions{PageSize: limit, Limit: limit, Cursor: r.URL.Query().Get("cursor")}, nil
+}
+
+// firstPage reads the page an iterator starts at
+func firstPage[T any](ctx context.Context, it *transaction.Iterator[T]) (Page[T], error) {
+	items, err := it.Collect(ctx)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	return Page[T]{Items: items, NextCursor: it.PageInfo().NextCursor}, nil
+}
+
+func (h *handler) chain(r *http.Request, blockchainId string) (any, error) {
+	config, err := h.query.GetChainConfig(r.Context(), blockchainId)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := h.query.GetLatestBlock(r.Context(), blockchainId)
+	if err != nil {
+		return nil, err
+	}
+	return ChainView{Config: config, Height: latest.Height, LatestBlock: summarize(latest)}, nil
+}
+
+func (h *handler) blocks(r *http.Request, blockchainId string) (any, error) {
+	opts, err := h.listOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	page, err := firstPage(r.Context(), h.query.ListBlocks(blockchainId, opts))
+	if err != nil {
+		return nil, err
+	}
+	summaries := Page[BlockSummary]{Items: make([]BlockSummary, len(page.Items)), NextCursor: page.NextCursor}
+	for i, block := range page.Items {
+		summaries.Items[i] = summarize(block)
+	}
+	return summaries, nil
+}
+
+func (h *handler) latestBlock(r *http.Request, blockchainId string) (any, error) {
+	return blockView(h.query.GetLatestBlock(r.Context(), blockchainId))
+}
+
+func (h *handler) block(r *http.Request, blockchainId string) (any, error) {
+	height, err := strconv.Atoi(r.PathValue("height"))
+	if err != nil || height < 0 {
+		return nil, &utils.ErrMalformed{What: "block height", Msg: "it must be a non negative integer"}
+	}
+	return blockView(h.query.GetBlockByHeight(r.Context(), blockchainId, height))
+}
+
+func (h *handler) blockByHash(r *http.Request, blockchainId string) (any, error) {
+	return blockView(h.query.GetBlockByHash(r.Context(), blockchainId, r.PathValue("hash")))
+}
+
+// transaction decodes a transaction, its signature is left unchecked since the explorer does not
+// look up the public key of the sender
+func (h *handler) transaction(r *http.Request, blockchainId string) (any, error) {
+	tx, err := h.query.GetTransaction(r.Context(), blockchainId, r.PathValue("transactionId"))
+	if err != nil {
+		return nil, err
+	}
+	return transaction.DecodeTransaction(tx, ""), nil
+}
+
+func (h *handler) wallet(r *http.Request, blockchainId string) (any, error) {
+	opts, err := h.listOptions(r)
+	if err != nil {
+		return nil, err
+	}
+	address := r.PathValue("address")
+	state, err := h.query.GetWalletState(r.Context(), blockchainId, address)
+	if err != nil {
+		return nil, err
+	}
+	page, err := firstPage(r.Context(), h.query.ListTransactions(blockchainId, transaction.TransactionFilter{Address: address}, opts))
+	if err != nil {
+		return nil, err
+	}
+	return WalletView{State: state, Transactions: page}, nil
+}
+
+// search resolves q to a block height, then to a transaction, a wallet or a block hash
+func (h *handler) search(r *http.Request, blockchainId string) (any, error) {
+	ctx := r.Context()
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		return nil, &utils.ErrMalformed{What: "search", Msg: "the q parameter is required"}
+	}
+	prefix := "/chains/" + blockchainId
+	if height, err := strconv.Atoi(q); err == nil && height >= 0 {
+		if _, err := h.query.GetBlockByHeight(ctx, blockchainId, height); err == nil {
+			return SearchResult{Kind: "block", Path: fmt.Sprintf("%s/blocks/%d", prefix, height)}, nil
+		}
+	}
+	lookups := []struct {
+		kind   string
+		path   string
+		lookup func() error
+	}{
+		{"transaction", "/transactions/", func() error { _, err := h.query.GetTransaction(ctx, blockchainId, q); return err }},
+		{"wallet", "/wallets/", func() error { _, err := h.query.GetWalletState(ctx, blockchainId, q); return err }},
+		{"block", "/blocks/hash/", func() error { _, err := h.query.GetBlockByHash(ctx, blockchainId, q); return err }},
+	}
+	for _, candidate := range lookups {
+		err := candidate.lookup()
+		if err == nil {
+			return SearchResult{Kind: candidate.kind, Path: prefix + candidate.path + q}, nil
+		}
+		// Anything but a miss, e.g. the node being down, is worth reporting
+		if !errors.Is(err, utils.ErrNotFound) && !errors.Is(err, utils.ErrInvalidInput) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("nothing matches %q on chain %s: %w", q, blockchainId, utils.ErrNotFound)
+}
+
+func blockView(block transaction.ULBlock, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	view := BlockView{BlockSummary: summarize(block), Transactions: make([]transaction.DecodedTransaction, len(block.Transactions))}
+	for i, tx := range block.Transactions {
+		view.Transactions[i] = transaction.DecodeTransaction(tx, "")
+	}
+	return view, nil
+}
+
+func summarize(block transaction.ULBlock) BlockSummary {
+	return BlockSummary{
+		Height:            block.Height,
+		Hash:              block.Hash,
+		PreviousBlockHash: block.PreviousBlockHash,
+		MerkleRoot:        block.MerkleRoot,
+		Transactions:      len(block.Transactions),
+		Voters:            len(block.Voters),
+	}
+}
@@ -0,0 +1,114 @@
+package explorer
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+// get requests path from the explorer and decodes the response into out
+func get(t *testing.T, explorer http.Handler, path string, wantStatus int, out any) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	explorer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	if recorder.Code != wantStatus {
+		t.Fatalf("GET %s = %d %s, want %d", path, recorder.Code, recorder.Body, wantStatus)
+	}
+	if out != nil {
+		if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+			t.Fatalf("GET %s returned invalid JSON: %v", path, err)
+		}
+	}
+}
+
+func TestExplorer(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId, "other")
+	t.Cleanup(node.Close)
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	register, _ := json.Marshal(transaction.CreateWalletPayload{PublicKey: w.GetKey().GetPublicKeyHex(false), KeyType: crypto.KeyTypeSecp256k1})
+	inputs := []transaction.ULTransactionInput{{To: w.Address, Payload: string(register), PayloadType: transaction.TX_CREATE_WALLET.String()}}
+	for _, payload := range []string{`{"invoice": 1}`, `{"invoice": 2}`, `{"invoice": 3}`} {
+		inputs = append(inputs, transaction.ULTransactionInput{To: w.Address, Payload: payload, PayloadType: transaction.TX_DATA.String()})
+	}
+	txs := []transaction.ULTransaction{}
+	for _, input := range inputs {
+		input.BlockchainId = testBlockchainId
+		tx, err := session.GenerateTransaction(input)
+		if err != nil {
+			t.Fatalf("GenerateTransaction() error = %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	explorer := NewHandler(&session, Options{BlockchainIds: []string{testBlockchainId}})
+	prefix := "/chains/" + testBlockchainId
+
+	chain := ChainView{}
+	get(t, explorer, prefix, http.StatusOK, &chain)
+	if chain.Config.BlockchainId != testBlockchainId || chain.Height == 0 || chain.LatestBlock.Height != chain.Height {
+		t.Fatalf("chain view = %+v", chain)
+	}
+
+	// Listings page with the cursor of the node
+	blocks := Page[BlockSummary]{}
+	get(t, explorer, prefix+"/blocks?limit=2", http.StatusOK, &blocks)
+	if len(blocks.Items) != 2 || blocks.NextCursor == "" {
+		t.Fatalf("blocks page = %+v, want 2 blocks and a cursor", blocks)
+	}
+	next := Page[BlockSummary]{}
+	get(t, explorer, prefix+"/blocks?limit=2&cursor="+blocks.NextCursor, http.StatusOK, &next)
+	if len(next.Items) == 0 || next.Items[0].Height <= blocks.Items[1].Height {
+		t.Fatalf("next blocks page = %+v after %+v", next, blocks)
+	}
+
+	tx := txs[len(txs)-1]
+	block := BlockView{}
+	get(t, explorer, prefix+"/blocks/"+strconv.Itoa(tx.BlockHeight), http.StatusOK, &block)
+	if block.Height != tx.BlockHeight || len(block.Transactions) == 0 || block.Transactions[len(block.Transactions)-1].TransactionId != tx.TransactionId {
+		t.Fatalf("block view = %+v, want the block of %s", block, tx.TransactionId)
+	}
+	get(t, explorer, prefix+"/blocks/hash/"+block.Hash, http.StatusOK, &block)
+	if block.Height != tx.BlockHeight {
+		t.Fatalf("block by hash = %d, want %d", block.Height, tx.BlockHeight)
+	}
+
+	decoded := transaction.DecodedTransaction{}
+	get(t, explorer, prefix+"/transactions/"+tx.TransactionId, http.StatusOK, &decoded)
+	if payload, ok := decoded.Payload.(map[string]any); !ok || payload["invoice"] != float64(3) || !decoded.Commitment.Valid {
+		t.Fatalf("decoded transaction = %+v", decoded)
+	}
+
+	view := WalletView{}
+	get(t, explorer, prefix+"/wallets/"+w.Address+"?limit=3", http.StatusOK, &view)
+	if view.State.Address != w.Address || len(view.Transactions.Items) != 3 || view.Transactions.NextCursor == "" {
+		t.Fatalf("wallet view = %+v, want a page of 3 transactions", view)
+	}
+
+	for q, kind := range map[string]string{strconv.Itoa(tx.BlockHeight): "block", tx.TransactionId: "transaction", w.Address: "wallet", block.Hash: "block"} {
+		result := SearchResult{}
+		get(t, explorer, prefix+"/search?q="+q, http.StatusOK, &result)
+		if result.Kind != kind {
+			t.Errorf("search %s = %+v, want a %s", q, result, kind)
+		}
+	}
+	get(t, explorer, prefix+"/search?q=nothing", http.StatusNotFound, nil)
+	get(t, explorer, prefix+"/blocks?limit=0", http.StatusBadRequest, nil)
+	get(t, explorer, prefix+"/blocks/999", http.StatusNotFound, nil)
+	// Chains outside BlockchainIds are not served even when the node has them
+	get(t, explorer, "/chains/other", http.StatusNotFound, nil)
+}
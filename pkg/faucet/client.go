@@ -0,0 +1,131 @@
+package faucet
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// FAUCET_PATH is the endpoint a dispenser serves and a client posts to
+const FAUCET_PATH = "/faucet"
+
+// Request asks the faucet to fund Address on BlockchainId
+type Request struct {
+	BlockchainId string `json:"blockchainId"`
+	Address      string `json:"address"`
+}
+
+// Response describes the transfer the faucet made
+type Response struct {
+	TransactionId string    `json:"transactionId"`
+	TokenAddress  string    `json:"tokenAddress"`
+	Amount        uint64    `json:"amount"`
+	NextRequestAt time.Time `json:"nextRequestAt"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ErrRateLimited is returned when the address or the caller was funded too recently
+type ErrRateLimited struct {
+	RetryAfter time.Duration
+	Msg        string
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("faucet rate limit reached, retry in %s: %s", e.RetryAfter, e.Msg)
+}
+
+func (e *ErrRateLimited) Is(target error) bool {
+	return target == utils.ErrUnavailable
+}
+
+type ErrInvalidAddress struct {
+	Msg string
+}
+
+func (e *ErrInvalidAddress) Error() string {
+	return fmt.Sprintf("invalid wallet address, %s", e.Msg)
+}
+
+func (e *ErrInvalidAddress) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrFaucet is any other failure reported by the faucet
+type ErrFaucet struct {
+	StatusCode int
+	Msg        string
+}
+
+func (e *ErrFaucet) Error() string {
+	return fmt.Sprintf("faucet returned status %d: %s", e.StatusCode, e.Msg)
+}
+
+func (e *ErrFaucet) Is(target error) bool {
+	return target != nil && target == utils.StatusCategory(e.StatusCode)
+}
+
+// Client requests testnet tokens from a faucet endpoint
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient returns a client for the faucet served at endpoint, e.g. https://faucet.example.com
+func NewClient(endpoint string) *Client {
+	return &Client{
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Request funds address on blockchainId
+func (c *Client) Request(ctx context.Context, blockchainId string, address string) (Response, error) {
+	body, err := json.Marshal(Request{BlockchainId: blockchainId, Address: address})
+	if err != nil {
+		return Response{}, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+FAUCET_PATH, bytes.NewReader(body))
+	if err != nil {
+		return Response{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return Response{}, fmt.Errorf("unable to reach the faucet: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		failure := errorResponse{}
+		if json.Unmarshal(respBody, &failure) != nil || failure.Error == "" {
+			failure.Error = strings.TrimSpace(string(respBody))
+		}
+		if resp.StatusCode == http.StatusTooManyRequests {
+			seconds, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+			return Response{}, &ErrRateLimited{RetryAfter: time.Duration(seconds) * time.Second, Msg: failure.Error}
+		}
+		return Response{}, &ErrFaucet{StatusCode: resp.StatusCode, Msg: failure.Error}
+	}
+
+	response := Response{}
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return Response{}, fmt.Errorf("invalid faucet response: %w", err)
+	}
+	return response, nil
+}
@@ -0,0 +1,220 @@
+package faucet
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	DEFAULT_ADDRESS_INTERVAL = 24 * time.Hour
+	DEFAULT_IP_INTERVAL      = time.Hour
+)
+
+type DispenserConfig struct {
+	BlockchainId string
+	TokenAddress string
+	// Amount is transferred on every successful request
+	Amount uint64
+	// AddressInterval is the minimum time between two grants to the same address
+	AddressInterval time.Duration
+	// IPInterval is the minimum time between two grants requested from the same IP
+	IPInterval time.Duration
+	// TrustForwardedFor takes the caller IP from X-Forwarded-For, only enable it behind a trusted proxy
+	TrustForwardedFor bool
+}
+
+// Dispenser is the operator side of a faucet: it transfers tokens from the session's wallet and
+// limits how often an address or an IP can be funded
+type Dispenser struct {
+	session *transaction.UL_TransactionSession
+	config  DispenserConfig
+	now     func() time.Time
+
+	mu        sync.Mutex
+	addresses map[string]time.Time
+	ips       map[string]time.Time
+	// send serializes transfers so grants from the faucet wallet never race each other
+	send sync.Mutex
+}
+
+func NewDispenser(session *transaction.UL_TransactionSession, config DispenserConfig) (*Dispenser, error) {
+	if config.BlockchainId == "" || config.TokenAddress == "" {
+		return nil, fmt.Errorf("the blockchain id and token address are required")
+	}
+	if config.Amount == 0 {
+		return nil, fmt.Errorf("the amount must be positive")
+	}
+	if config.AddressInterval <= 0 {
+		config.AddressInterval = DEFAULT_ADDRESS_INTERVAL
+	}
+	if config.IPInterval <= 0 {
+		config.IPInterval = DEFAULT_IP_INTERVAL
+	}
+	return &Dispenser{
+		session:   session,
+		config:    config,
+		now:       time.Now,
+		addresses: make(map[string]time.Time),
+		ips:       make(map[string]time.Time),
+	}, nil
+}
+
+// Dispense funds address on behalf of a caller at ip, ip may be empty for trusted callers.
+// A failed transfer does not count against the limits.
+func (d *Dispenser) Dispense(ctx context.Context, address string, ip string) (Response, error) {
+	address = strings.ToLower(address)
+	if b, err := hex.DecodeString(address); err != nil || len(b) != 32 {
+		return Response{}, &ErrInvalidAddress{Msg: address}
+	}
+
+	now, err := d.reserve(address, ip)
+	if err != nil {
+		return Response{}, err
+	}
+
+	tx, err := d.transfer(ctx, address)
+	if err != nil {
+		d.release(address, ip, now)
+		return Response{}, err
+	}
+	return Response{
+		TransactionId: tx.TransactionId,
+		TokenAddress:  d.config.TokenAddress,
+		Amount:        d.config.Amount,
+		NextRequestAt: now.Add(d.config.AddressInterval),
+	}, nil
+}
+
+// reserve records the grant up front so concurrent requests for the same address cannot both pass
+func (d *Dispenser) reserve(address string, ip string) (time.Time, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	now := d.now()
+	if last, ok := d.addresses[address]; ok && now.Sub(last) < d.config.AddressInterval {
+		return now, &ErrRateLimited{RetryAfter: last.Add(d.config.AddressInterval).Sub(now), Msg: "address " + address + " was funded recently"}
+	}
+	if last, ok := d.ips[ip]; ip != "" && ok && now.Sub(last) < d.config.IPInterval {
+		return now, &ErrRateLimited{RetryAfter: last.Add(d.config.IPInterval).Sub(now), Msg: "too many requests from " + ip}
+	}
+	d.addresses[address] = now
+	if ip != "" {
+		d.ips[ip] = now
+	}
+	return now, nil
+}
+
+func (d *Dispenser) release(address string, ip string, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.addresses[address] == at {
+		delete(d.addresses, address)
+	}
+	if ip != "" && d.ips[ip] == at {
+		delete(d.ips, ip)
+	}
+}
+
+func (d *Dispenser) transfer(ctx context.Context, address string) (transaction.ULTransaction, error) {
+	payload, err := json.Marshal(transaction.TransferTokenPayload{
+		TokenAddress: d.config.TokenAddress,
+		To:           address,
+		Amount:       transaction.NewAmount(d.config.Amount),
+	})
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return transaction.ULTransaction{}, err
+	}
+
+	d.send.Lock()
+	defer d.send.Unlock()
+	input := transaction.ULTransactionInput{
+		BlockchainId: d.config.BlockchainId,
+		To:           d.config.TokenAddress,
+		Payload:      string(payload),
+		PayloadType:  transaction.TRANSFER_TOKEN.String(),
+	}
+	tx, err := d.session.GenerateTransaction(input)
+	if err == nil && tx.Output == transaction.TX_REJECTED_BY_DUPLICATE.String() {
+		// Timestamps have a one second resolution, an identical grant in the same second is a duplicate
+		select {
+		case <-ctx.Done():
+			return transaction.ULTransaction{}, ctx.Err()
+		case <-time.After(time.Until(time.Now().Truncate(time.Second).Add(time.Second))):
+		}
+		tx, err = d.session.GenerateTransaction(input)
+	}
+	if err != nil {
+		return transaction.ULTransaction{}, fmt.Errorf("faucet transfer failed: %w", err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return transaction.ULTransaction{}, fmt.Errorf("faucet transfer %s was not applied: %s", tx.TransactionId, tx.Output)
+	}
+	return tx, nil
+}
+
+// ServeHTTP implements the faucet endpoint used by Client, mount it at FAUCET_PATH
+func (d *Dispenser) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJson(w, http.StatusMethodNotAllowed, errorResponse{Error: "use POST"})
+		return
+	}
+	request := Request{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&request); err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: utils.HandleJsonError(err)})
+		return
+	}
+	if request.BlockchainId != d.config.BlockchainId {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("this faucet serves blockchain %s", d.config.BlockchainId)})
+		return
+	}
+
+	response, err := d.Dispense(r.Context(), request.Address, d.clientIP(r))
+	var rateLimited *ErrRateLimited
+	var invalidAddress *ErrInvalidAddress
+	switch {
+	case errors.As(err, &rateLimited):
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+		writeJson(w, http.StatusTooManyRequests, errorResponse{Error: rateLimited.Msg})
+	case errors.As(err, &invalidAddress):
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+	case err != nil:
+		writeJson(w, http.StatusBadGateway, errorResponse{Error: err.Error()})
+	default:
+		writeJson(w, http.StatusOK, response)
+	}
+}
+
+func (d *Dispenser) clientIP(r *http.Request) string {
+	if d.config.TrustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			first, _, _ := strings.Cut(forwarded, ",")
+			return strings.TrimSpace(first)
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,115 @@
+package faucet
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	testBlockchainId = "MyBlockchain1"
+	testToken        = "faucet-token"
+)
+
+// newFaucet serves a dispenser funded with balance tokens, the returned clock pointer drives its rate limits
+func newFaucet(t *testing.T, balance uint64) (*transactiontest.MockNode, *Dispenser, *Client, *time.Time) {
+	t.Helper()
+	node := transactiontest.NewMockNode(testBlockchainId)
+	t.Cleanup(node.Close)
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	node.SetBalance(testToken, w.Address, balance)
+
+	dispenser, err := NewDispenser(&session, DispenserConfig{
+		BlockchainId:    testBlockchainId,
+		TokenAddress:    testToken,
+		Amount:          100,
+		AddressInterval: time.Hour,
+		IPInterval:      time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewDispenser() error = %v", err)
+	}
+	clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dispenser.now = func() time.Time { return clock }
+
+	mux := http.NewServeMux()
+	mux.Handle(FAUCET_PATH, dispenser)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return node, dispenser, NewClient(server.URL + "/"), &clock
+}
+
+func TestFaucetRequest(t *testing.T) {
+	node, _, client, clock := newFaucet(t, 1000)
+	ctx := context.Background()
+	alice := strings.Repeat("a1", 32)
+	bob := strings.Repeat("b2", 32)
+
+	response, err := client.Request(ctx, testBlockchainId, alice)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if response.Amount != 100 || response.TransactionId == "" || node.Balance(testToken, alice) != 100 {
+		t.Fatalf("Request() = %+v, balance = %d", response, node.Balance(testToken, alice))
+	}
+
+	// Same address and same IP are both limited
+	var rateLimited *ErrRateLimited
+	if _, err := client.Request(ctx, testBlockchainId, alice); !errors.As(err, &rateLimited) || rateLimited.RetryAfter != time.Hour {
+		t.Fatalf("Request() again error = %v, want ErrRateLimited after an hour", err)
+	}
+	if _, err := client.Request(ctx, testBlockchainId, bob); !errors.As(err, &rateLimited) || rateLimited.RetryAfter != time.Minute {
+		t.Fatalf("Request() from the same IP error = %v, want ErrRateLimited after a minute", err)
+	}
+
+	*clock = clock.Add(2 * time.Minute)
+	if _, err := client.Request(ctx, testBlockchainId, bob); err != nil {
+		t.Fatalf("Request() after the IP interval error = %v", err)
+	}
+	if _, err := client.Request(ctx, testBlockchainId, alice); !errors.As(err, &rateLimited) {
+		t.Fatalf("Request() before the address interval error = %v, want ErrRateLimited", err)
+	}
+
+	*clock = clock.Add(time.Hour)
+	if _, err := client.Request(ctx, testBlockchainId, alice); err != nil {
+		t.Fatalf("Request() after the address interval error = %v", err)
+	}
+	if node.Balance(testToken, alice) != 200 {
+		t.Fatalf("balance = %d, want 200", node.Balance(testToken, alice))
+	}
+}
+
+func TestFaucetFailuresDoNotConsumeLimits(t *testing.T) {
+	node, dispenser, client, _ := newFaucet(t, 50)
+	ctx := context.Background()
+	alice := strings.Repeat("a1", 32)
+
+	var errFaucet *ErrFaucet
+	if _, err := client.Request(ctx, testBlockchainId, alice); !errors.As(err, &errFaucet) || errFaucet.StatusCode != http.StatusBadGateway {
+		t.Fatalf("Request() from an empty faucet error = %v, want a bad gateway", err)
+	}
+	if _, err := client.Request(ctx, testBlockchainId, "not-an-address"); !errors.As(err, &errFaucet) || errFaucet.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Request() with an invalid address error = %v, want a bad request", err)
+	}
+
+	node.SetBalance(testToken, dispenser.session.GetWallet().Address, 1000)
+	if _, err := client.Request(ctx, testBlockchainId, alice); err != nil {
+		t.Fatalf("Request() after refilling error = %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+// Package gateway generates REST gateways that proxy SDK operations, such as submitting a
+// transaction signed in a browser or reading a balance, to a node. Generate emits a Service
+// interface and the handlers serving it, OpenAPI emits the matching OpenAPI definition. Both read
+// the pkg/transaction structs by reflection so the wire types cannot drift from the SDK:
+//
+//	//go:generate go run github.com/ULedgerInc/go-sdk/cmd/uledger gateway generate --package api --out api/api.go --openapi openapi.json
+//
+// SessionService implements the Service of the default Operations with a session:
+//
+//	http.ListenAndServe(":8080", api.NewHandler(gateway.NewSessionService(session)))
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// Operation is an SDK operation served over REST. The service method takes the context, the path
+// parameters in order, then the request body when there is one.
+type Operation struct {
+	// Name of the service method and operationId of the OpenAPI definition
+	Name    string
+	Method  string
+	Path    string
+	Summary string
+	// Request is the type of the JSON body, nil when the operation has none
+	Request reflect.Type
+	// Response is the type of the JSON response
+	Response reflect.Type
+}
+
+// Operations are the SDK operations gateways serve by default
+var Operations = []Operation{
+	{
+		Name:     "SubmitTransaction",
+		Method:   http.MethodPost,
+		Path:     "/blockchains/{blockchainId}/transactions",
+		Summary:  "Submit a transaction signed by the client",
+		Request:  reflect.TypeFor[transaction.ULTransactionInput](),
+		Response: reflect.TypeFor[transaction.ULTransaction](),
+	},
+	{
+		Name:     "GetTransaction",
+		Method:   http.MethodGet,
+		Path:     "/blockchains/{blockchainId}/transactions/{transactionId}",
+		Summary:  "Get a transaction",
+		Response: reflect.TypeFor[transaction.ULTransaction](),
+	},
+	{
+		Name:     "GetTokenMetadata",
+		Method:   http.MethodGet,
+		Path:     "/blockchains/{blockchainId}/tokens/{tokenAddress}",
+		Summary:  "Get the name, symbol, decimals, supply and owner of a token",
+		Response: reflect.TypeFor[transaction.TokenMetadata](),
+	},
+	{
+		Name:     "GetBalance",
+		Method:   http.MethodGet,
+		Path:     "/blockchains/{blockchainId}/tokens/{tokenAddress}/balances/{owner}",
+		Summary:  "Get the balance of a token owner",
+		Response: reflect.TypeFor[transaction.Amount](),
+	},
+}
+
+// ErrorResponse is the body of failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// StatusCode maps err to the status of its response: the error category for SDK errors, 502 for
+// errors of the node or the network
+func StatusCode(err error) int {
+	switch utils.Category(err) {
+	case utils.ErrInvalidInput:
+		return http.StatusBadRequest
+	case utils.ErrNotFound:
+		return http.StatusNotFound
+	case utils.ErrUnsupported:
+		return http.StatusNotImplemented
+	case utils.ErrRejected, utils.ErrConflict:
+		return http.StatusConflict
+	case utils.ErrUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// DecodeRequest decodes the JSON body of r into v
+func DecodeRequest(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &utils.ErrMalformed{What: "request body", Msg: utils.HandleJsonError(err)}
+	}
+	return nil
+}
+
+// WriteResponse writes response as JSON, or the ErrorResponse of err when it is not nil
+func WriteResponse(w http.ResponseWriter, response any, err error) {
+	status := http.StatusOK
+	if err != nil {
+		status, response = StatusCode(err), ErrorResponse{Error: err.Error()}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// SessionService serves the default Operations with a session, which only needs a wallet to read
+// since the transactions it submits are signed by the clients
+type SessionService struct {
+	session *transaction.UL_TransactionSession
+}
+
+// NewSessionService returns a service proxying the default Operations to the node of session
+func NewSessionService(session *transaction.UL_TransactionSession) *SessionService {
+	return &SessionService{session: session}
+}
+
+// SubmitTransaction forwards a signed transaction to the node
+func (s *SessionService) SubmitTransaction(ctx context.Context, blockchainId string, input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	if input.BlockchainId != blockchainId {
+		return transaction.ULTransaction{}, &utils.ErrMalformed{What: "transaction", Msg: fmt.Sprintf("the transaction of blockchain %s was sent to blockchain %s", input.BlockchainId, blockchainId)}
+	}
+	if input.SenderSignature == "" {
+		return transaction.ULTransaction{}, &transaction.ErrIncompleteTransaction{Field: "sender signature", Msg: "the gateway only forwards signed transactions"}
+	}
+	tx := transaction.ULTransaction{}
+	if err := s.session.Do(ctx, http.MethodPost, fmt.Sprintf("/blockchains/%s/transactions", blockchainId), input, &tx); err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	return tx, nil
+}
+
+// GetTransaction returns a transaction of the node
+func (s *SessionService) GetTransaction(ctx context.Context, blockchainId string, transactionId string) (transaction.ULTransaction, error) {
+	return s.session.GetTransaction(ctx, blockchainId, transactionId)
+}
+
+// GetTokenMetadata returns the metadata of a token
+func (s *SessionService) GetTokenMetadata(ctx context.Context, blockchainId string, tokenAddress string) (transaction.TokenMetadata, error) {
+	return s.session.GetTokenMetadata(ctx, blockchainId, tokenAddress)
+}
+
+// GetBalance returns the balance of owner
+func (s *SessionService) GetBalance(ctx context.Context, blockchainId string, tokenAddress string, owner string) (transaction.Amount, error) {
+	return transaction.NewTokenClient(s.session, blockchainId).BalanceOf(ctx, tokenAddress, owner)
+}
@@ -0,0 +1,165 @@
+package gateway_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/examples/gateway/api"
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/gateway"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+// The example gateway must be what the generator produces from the SDK types today
+func TestGenerateExample(t *testing.T) {
+	source, err := gateway.Generate(gateway.Operations, gateway.Options{Package: "api"})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if want, _ := os.ReadFile("../../examples/gateway/api/api.go"); !bytes.Equal(source, want) {
+		t.Fatalf("Generate() differs from examples/gateway/api/api.go, run go generate:\n%s", source)
+	}
+	definition, err := gateway.OpenAPI(gateway.Operations, gateway.Info{Title: "ULedger gateway", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+	if want, _ := os.ReadFile("../../examples/gateway/api/openapi.json"); !bytes.Equal(append(definition, '\n'), want) {
+		t.Fatalf("OpenAPI() differs from examples/gateway/api/openapi.json, run go generate")
+	}
+}
+
+func TestOpenAPI(t *testing.T) {
+	definition, err := gateway.OpenAPI(gateway.Operations, gateway.Info{Title: "test", Version: "1"})
+	if err != nil {
+		t.Fatalf("OpenAPI() error = %v", err)
+	}
+	spec := struct {
+		Paths      map[string]map[string]json.RawMessage
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]any
+				Required   []string
+			}
+		}
+	}{}
+	if err := json.Unmarshal(definition, &spec); err != nil {
+		t.Fatalf("OpenAPI() is not JSON: %v", err)
+	}
+	if _, ok := spec.Paths["/blockchains/{blockchainId}/transactions"]["post"]; !ok {
+		t.Fatalf("OpenAPI() paths = %v", spec.Paths)
+	}
+	// Embedded structs are flattened and omitempty fields are optional, as encoding/json writes them
+	tx := spec.Components.Schemas["ULTransaction"]
+	for _, field := range []string{"senderSignature", "transactionId", "delegationId"} {
+		if _, ok := tx.Properties[field]; !ok {
+			t.Fatalf("ULTransaction lacks %s: %v", field, tx.Properties)
+		}
+	}
+	for _, field := range tx.Required {
+		if field == "delegationId" {
+			t.Fatal("delegationId is required")
+		}
+	}
+	if amount := spec.Components.Schemas["TokenMetadata"].Properties["totalSupply"]; amount["type"] != "string" {
+		t.Fatalf("Amount schema = %v", amount)
+	}
+}
+
+func TestInvalidOperations(t *testing.T) {
+	valid := gateway.Operation{Name: "Ping", Method: http.MethodGet, Path: "/ping", Response: reflect.TypeFor[string]()}
+	tests := map[string]gateway.Operation{
+		"unexported name":  {Name: "ping", Method: http.MethodGet, Path: "/ping", Response: valid.Response},
+		"method":           {Name: "Ping", Method: "FETCH", Path: "/ping", Response: valid.Response},
+		"relative path":    {Name: "Ping", Method: http.MethodGet, Path: "ping", Response: valid.Response},
+		"no response":      {Name: "Ping", Method: http.MethodGet, Path: "/ping"},
+		"parameter":        {Name: "Ping", Method: http.MethodGet, Path: "/ping/{ctx}", Response: valid.Response},
+		"shadowed package": {Name: "Ping", Method: http.MethodGet, Path: "/ping/{http}", Response: valid.Response},
+		"channel":          {Name: "Ping", Method: http.MethodGet, Path: "/ping", Response: reflect.TypeFor[chan int]()},
+	}
+	for name, op := range tests {
+		if _, err := gateway.Generate([]gateway.Operation{op}, gateway.Options{Package: "api"}); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("%s: Generate() error = %v", name, err)
+		}
+	}
+	if _, err := gateway.Generate([]gateway.Operation{valid, valid}, gateway.Options{Package: "api"}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("Generate() of a duplicate operation error = %v", err)
+	}
+}
+
+func TestSessionService(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	t.Cleanup(node.Close)
+	reader, err := transaction.NewUL_TransactionSession(node.URL(), wallet.UL_Wallet{})
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	server := httptest.NewServer(api.NewHandler(gateway.NewSessionService(&reader)))
+	t.Cleanup(server.Close)
+
+	// The client signs, the gateway forwards
+	client, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	signed, err := transaction.BuildSignedTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           client.Address,
+		Payload:      "hello",
+		PayloadType:  transaction.TX_DATA.String(),
+		Suggestor:    reader.GetSuggestor(),
+	}, &client)
+	if err != nil {
+		t.Fatalf("BuildSignedTransaction() error = %v", err)
+	}
+	body, _ := json.Marshal(signed)
+	tx := transaction.ULTransaction{}
+	if status := call(t, http.MethodPost, server.URL+"/blockchains/"+testBlockchainId+"/transactions", body, &tx); status != http.StatusOK || tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("POST transactions = %d, %+v", status, tx)
+	}
+	fetched := transaction.ULTransaction{}
+	if status := call(t, http.MethodGet, server.URL+"/blockchains/"+testBlockchainId+"/transactions/"+tx.TransactionId, nil, &fetched); status != http.StatusOK || fetched.Payload != "hello" {
+		t.Fatalf("GET transaction = %d, %+v", status, fetched)
+	}
+
+	failure := gateway.ErrorResponse{}
+	if status := call(t, http.MethodPost, server.URL+"/blockchains/other/transactions", body, &failure); status != http.StatusBadRequest || failure.Error == "" {
+		t.Fatalf("POST transactions of another chain = %d, %+v", status, failure)
+	}
+	if status := call(t, http.MethodPost, server.URL+"/blockchains/"+testBlockchainId+"/transactions", []byte("{"), &failure); status != http.StatusBadRequest {
+		t.Fatalf("POST malformed transaction = %d", status)
+	}
+	if status := call(t, http.MethodGet, server.URL+"/blockchains/"+testBlockchainId+"/tokens/"+tx.TransactionId, nil, &failure); status != http.StatusNotFound {
+		t.Fatalf("GET unknown token = %d, %+v", status, failure)
+	}
+}
+
+func TestUnimplementedService(t *testing.T) {
+	server := httptest.NewServer(api.NewHandler(api.UnimplementedService{}))
+	t.Cleanup(server.Close)
+	failure := gateway.ErrorResponse{}
+	if status := call(t, http.MethodGet, server.URL+"/blockchains/b/tokens/t/balances/o", nil, &failure); status != http.StatusNotImplemented {
+		t.Fatalf("GET balance = %d, %+v", status, failure)
+	}
+}
+
+func call(t *testing.T, method string, url string, body []byte, out any) int {
+	t.Helper()
+	request, _ := http.NewRequest(method, url, bytes.NewReader(body))
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("%s %s error = %v", method, url, err)
+	}
+	defer response.Body.Close()
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		t.Fatalf("%s %s response error = %v", method, url, err)
+	}
+	return response.StatusCode
+}
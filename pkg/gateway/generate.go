@@ -0,0 +1,221 @@
+package gateway
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// Options of Generate
+type Options struct {
+	// Package of the generated file
+	Package string
+}
+
+// methods are the HTTP methods of operations
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// validateOperations checks that every operation has a distinct method name, route and parameters
+// the service methods can be declared with
+func validateOperations(operations []Operation) error {
+	if len(operations) == 0 {
+		return &ErrInvalidOperation{Msg: "no operations"}
+	}
+	names := map[string]bool{}
+	routes := map[string]bool{}
+	for _, op := range operations {
+		route := op.Method + " " + op.Path
+		switch {
+		case !token.IsIdentifier(op.Name) || !token.IsExported(op.Name):
+			return &ErrInvalidOperation{Operation: op.Name, Msg: "the name must be an exported identifier"}
+		case names[op.Name]:
+			return &ErrInvalidOperation{Operation: op.Name, Msg: "the name is used twice"}
+		case !slices.Contains(methods, op.Method):
+			return &ErrInvalidOperation{Operation: op.Name, Msg: fmt.Sprintf("unsupported method %q", op.Method)}
+		case !strings.HasPrefix(op.Path, "/"):
+			return &ErrInvalidOperation{Operation: op.Name, Msg: fmt.Sprintf("the path %q must start with /", op.Path)}
+		case routes[route]:
+			return &ErrInvalidOperation{Operation: op.Name, Msg: fmt.Sprintf("%s is served twice", route)}
+		case op.Response == nil:
+			return &ErrInvalidOperation{Operation: op.Name, Msg: "the response type is required"}
+		}
+		names[op.Name], routes[route] = true, true
+
+		params := map[string]bool{"ctx": true, "request": true}
+		for _, param := range op.PathParams() {
+			if !token.IsIdentifier(param) || params[param] {
+				return &ErrInvalidOperation{Operation: op.Name, Msg: fmt.Sprintf("invalid or duplicate path parameter %q", param)}
+			}
+			params[param] = true
+		}
+	}
+	return nil
+}
+
+// imports names the packages of the types a generated file refers to
+type imports map[string]string
+
+var majorVersion = regexp.MustCompile(`^v[0-9]+$`)
+
+// typeName returns the Go expression of t, importing the packages of named types
+func (i imports) typeName(t reflect.Type) (string, error) {
+	if t.Name() != "" {
+		if t.PkgPath() == "" {
+			return t.Name(), nil
+		}
+		if strings.Contains(t.Name(), "[") {
+			return "", fmt.Errorf("generic type %s is not supported", t)
+		}
+		name := path.Base(t.PkgPath())
+		if majorVersion.MatchString(name) {
+			name = path.Base(path.Dir(t.PkgPath()))
+		}
+		for other, otherName := range i {
+			if otherName == name && other != t.PkgPath() {
+				return "", fmt.Errorf("packages %s and %s have the same name", other, t.PkgPath())
+			}
+		}
+		i[t.PkgPath()] = name
+		return name + "." + t.Name(), nil
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Slice:
+		elem, err := i.typeName(t.Elem())
+		if t.Kind() == reflect.Pointer {
+			return "*" + elem, err
+		}
+		return "[]" + elem, err
+	case reflect.Map:
+		key, err := i.typeName(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := i.typeName(t.Elem())
+		return "map[" + key + "]" + elem, err
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return "interface{}", nil
+		}
+	}
+	return "", fmt.Errorf("type %s is not supported", t)
+}
+
+type operationData struct {
+	Operation
+	Params       []string
+	RequestType  string
+	ResponseType string
+}
+
+type gatewayData struct {
+	Package    string
+	Std        []string
+	Imports    []string
+	Operations []operationData
+}
+
+var gatewayTemplate = template.Must(template.New("gateway").Parse(`// Code generated by uledger gateway generate. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{range .Std}}	"{{.}}"
+{{end}}
+{{range .Imports}}	"{{.}}"
+{{end}})
+
+// Service implements the operations of the gateway, gateway.SessionService proxies the default
+// operations to a node
+type Service interface {
+{{range .Operations}}	// {{.Summary}}
+	{{.Name}}(ctx context.Context{{range .Params}}, {{.}} string{{end}}{{if .RequestType}}, request {{.RequestType}}{{end}}) ({{.ResponseType}}, error)
+{{end}}}
+
+// UnimplementedService answers every operation with utils.ErrUnsupported, services embedding it
+// only implement the operations they serve
+type UnimplementedService struct{}
+{{range .Operations}}
+func (UnimplementedService) {{.Name}}(ctx context.Context{{range .Params}}, {{.}} string{{end}}{{if .RequestType}}, request {{.RequestType}}{{end}}) ({{.ResponseType}}, error) {
+	var response {{.ResponseType}}
+	return response, utils.ErrUnsupported
+}
+{{end}}
+// NewHandler returns the routes of the operations of service
+func NewHandler(service Service) http.Handler {
+	mux := http.NewServeMux()
+{{range .Operations}}	mux.HandleFunc("{{.Method}} {{.Path}}", func(w http.ResponseWriter, r *http.Request) {
+{{- if .RequestType}}
+		var request {{.RequestType}}
+		if err := gateway.DecodeRequest(r, &request); err != nil {
+			gateway.WriteResponse(w, nil, err)
+			return
+		}
+{{- end}}
+		response, err := service.{{.Name}}(r.Context(){{range .Params}}, r.PathValue("{{.}}"){{end}}{{if .RequestType}}, request{{end}})
+		gateway.WriteResponse(w, response, err)
+	})
+{{end}}	return mux
+}
+`))
+
+// Generate returns the gofmt formatted source of a Service interface with one method per operation,
+// an UnimplementedService and the NewHandler serving them
+func Generate(operations []Operation, opts Options) ([]byte, error) {
+	if err := validateOperations(operations); err != nil {
+		return nil, err
+	}
+	if !token.IsIdentifier(opts.Package) {
+		return nil, &ErrInvalidOperation{Msg: fmt.Sprintf("invalid package name %q", opts.Package)}
+	}
+
+	data := gatewayData{Package: opts.Package}
+	imports := imports{
+		"context":  "context",
+		"net/http": "http",
+		"github.com/ULedgerInc/go-sdk/pkg/gateway": "gateway",
+		"github.com/ULedgerInc/go-sdk/pkg/utils":   "utils",
+	}
+	for _, op := range operations {
+		operation := operationData{Operation: op, Params: op.PathParams()}
+		var err error
+		if op.Request != nil {
+			if operation.RequestType, err = imports.typeName(op.Request); err != nil {
+				return nil, &ErrInvalidOperation{Operation: op.Name, Msg: err.Error()}
+			}
+		}
+		if operation.ResponseType, err = imports.typeName(op.Response); err != nil {
+			return nil, &ErrInvalidOperation{Operation: op.Name, Msg: err.Error()}
+		}
+		data.Operations = append(data.Operations, operation)
+	}
+	for importPath, name := range imports {
+		if !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			data.Std = append(data.Std, importPath)
+		} else {
+			data.Imports = append(data.Imports, importPath)
+		}
+		// Parameters would shadow the packages in the generated bodies
+		for _, operation := range data.Operations {
+			if slices.Contains(operation.Params, name) {
+				return nil, &ErrInvalidOperation{Operation: operation.Name, Msg: fmt.Sprintf("the path parameter %s shadows a package", name)}
+			}
+		}
+	}
+	slices.Sort(data.Std)
+	slices.Sort(data.Imports)
+
+	source := bytes.Buffer{}
+	if err := gatewayTemplate.Execute(&source, data); err != nil {
+		return nil, err
+	}
+	return format.Source(source.Bytes())
+}
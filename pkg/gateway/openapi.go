@@ -0,0 +1,182 @@
+package gateway
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// OPENAPI_VERSION is the version of the OpenAPI definitions OpenAPI writes
+const OPENAPI_VERSION = "3.0.3"
+
+// ErrInvalidOperation is returned for operations no gateway can be generated for
+type ErrInvalidOperation struct {
+	Operation string
+	Msg       string
+}
+
+func (e *ErrInvalidOperation) Error() string {
+	return fmt.Sprintf("invalid gateway operation %s: %s", e.Operation, e.Msg)
+}
+
+func (e *ErrInvalidOperation) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Info describes the API in its OpenAPI definition
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]*)\}`)
+
+// PathParams returns the names of the parameters of the path in order
+func (op Operation) PathParams() []string {
+	params := []string{}
+	for _, match := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		params = append(params, match[1])
+	}
+	return params
+}
+
+// OpenAPI returns the indented JSON OpenAPI definition of operations
+func OpenAPI(operations []Operation, info Info) ([]byte, error) {
+	if err := validateOperations(operations); err != nil {
+		return nil, err
+	}
+	schemas := schemaBuilder{components: map[string]any{}}
+	errorSchema := schemas.schema(reflect.TypeFor[ErrorResponse]())
+	paths := map[string]map[string]any{}
+	for _, op := range operations {
+		parameters := []any{}
+		for _, param := range op.PathParams() {
+			parameters = append(parameters, map[string]any{"name": param, "in": "path", "required": true, "schema": map[string]any{"type": "string"}})
+		}
+		definition := map[string]any{
+			"operationId": op.Name,
+			"summary":     op.Summary,
+			"parameters":  parameters,
+			"responses": map[string]any{
+				"200":     map[string]any{"description": "OK", "content": jsonContent(schemas.schema(op.Response))},
+				"default": map[string]any{"description": "Error", "content": jsonContent(errorSchema)},
+			},
+		}
+		if op.Request != nil {
+			definition["requestBody"] = map[string]any{"required": true, "content": jsonContent(schemas.schema(op.Request))}
+		}
+		if paths[op.Path] == nil {
+			paths[op.Path] = map[string]any{}
+		}
+		paths[op.Path][strings.ToLower(op.Method)] = definition
+	}
+	return json.MarshalIndent(map[string]any{
+		"openapi":    OPENAPI_VERSION,
+		"info":       info,
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas.components},
+	}, "", "  ")
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// schemaBuilder turns Go types into schemas, named structs become components referenced by name
+type schemaBuilder struct {
+	components map[string]any
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// schema returns the schema of the JSON encoding of t. Types marshalling themselves, as Amount,
+// are strings.
+func (b schemaBuilder) schema(t reflect.Type) map[string]any {
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType):
+		return map[string]any{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return b.schema(t.Elem())
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16:
+		return map[string]any{"type": "integer", "format": "int32"}
+	case reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "format": "int64"}
+	case reflect.Float32:
+		return map[string]any{"type": "number", "format": "float"}
+	case reflect.Float64:
+		return map[string]any{"type": "number", "format": "double"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]any{"type": "string", "format": "byte"}
+		}
+		return map[string]any{"type": "array", "items": b.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": b.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.object(t)
+		}
+		if _, ok := b.components[t.Name()]; !ok {
+			// Registered before the fields so recursive types end
+			b.components[t.Name()] = nil
+			b.components[t.Name()] = b.object(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+// object returns the schema of a struct, the fields of embedded structs are promoted as encoding/json does
+func (b schemaBuilder) object(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := []string{}
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if !field.IsExported() || tag == "-" {
+				continue
+			}
+			name, options, _ := strings.Cut(tag, ",")
+			if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+				addFields(field.Type)
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			properties[name] = b.schema(field.Type)
+			if !strings.Contains(options, "omitempty") && !strings.Contains(options, "omitzero") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	object := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	return object
+}
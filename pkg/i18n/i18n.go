@@ -0,0 +1,207 @@
+// Package i18n translates the messages the SDK shows to end users: the output of the uledger
+// command and the explanations of SDK errors. English, Spanish and Portuguese are built in, other
+// languages and product specific wording are added with Register.
+//
+// The language comes from ULEDGER_LOCALE, then from the usual LC_ALL, LC_MESSAGES and LANG
+// variables, unless a product selects it with SetDefault.
+//
+//	i18n.SetDefault(i18n.LOCALE_ES)
+//	fmt.Println(i18n.Format(err))
+package i18n
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// Locale is the language of the messages, a lowercase ISO 639-1 code
+type Locale string
+
+const (
+	LOCALE_EN Locale = "en"
+	LOCALE_ES Locale = "es"
+	LOCALE_PT Locale = "pt"
+)
+
+// ENV_LOCALE selects the language, it takes precedence over the system locale variables
+const ENV_LOCALE = "ULEDGER_LOCALE"
+
+// FALLBACK_LOCALE provides the messages missing from other languages
+const FALLBACK_LOCALE = LOCALE_EN
+
+// Messages maps message keys to fmt templates
+type Messages map[string]string
+
+var (
+	mu       sync.RWMutex
+	catalog  = map[Locale]Messages{}
+	fallback *Localizer
+)
+
+// Register adds messages to a language, replacing the templates of keys it already has
+func Register(locale Locale, messages Messages) {
+	mu.Lock()
+	defer mu.Unlock()
+	if catalog[locale] == nil {
+		catalog[locale] = Messages{}
+	}
+	for key, template := range messages {
+		catalog[locale][key] = template
+	}
+}
+
+// ParseLocale reads the language of a locale tag such as "es", "pt-BR" or "pt_BR.UTF-8", ok is
+// false when no messages are registered for it
+func ParseLocale(tag string) (Locale, bool) {
+	language := strings.ToLower(tag)
+	if i := strings.IndexAny(language, "-_.@"); i >= 0 {
+		language = language[:i]
+	}
+	locale := Locale(language)
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := catalog[locale]
+	return locale, ok
+}
+
+// LocaleFromEnv returns the first supported language of ULEDGER_LOCALE, LC_ALL, LC_MESSAGES and
+// LANG, FALLBACK_LOCALE when none is
+func LocaleFromEnv() Locale {
+	for _, variable := range []string{ENV_LOCALE, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		if locale, ok := ParseLocale(os.Getenv(variable)); ok {
+			return locale
+		}
+	}
+	return FALLBACK_LOCALE
+}
+
+// Localizer renders messages in one language
+type Localizer struct {
+	Locale Locale
+}
+
+func New(locale Locale) *Localizer {
+	return &Localizer{Locale: locale}
+}
+
+// Default returns the localizer of the package functions, its language is read from the
+// environment on first use
+func Default() *Localizer {
+	mu.RLock()
+	l := fallback
+	mu.RUnlock()
+	if l != nil {
+		return l
+	}
+	locale := LocaleFromEnv()
+	mu.Lock()
+	defer mu.Unlock()
+	if fallback == nil {
+		fallback = New(locale)
+	}
+	return fallback
+}
+
+// SetDefault selects the language of the package functions
+func SetDefault(locale Locale) {
+	mu.Lock()
+	defer mu.Unlock()
+	fallback = New(locale)
+}
+
+// T renders the message of key with args. Keys missing from the language fall back to
+// FALLBACK_LOCALE, unknown keys are returned as is.
+func (l *Localizer) T(key string, args ...any) string {
+	template := l.template(key)
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Errorf returns an error with the message of key, %w verbs wrap their argument like fmt.Errorf
+func (l *Localizer) Errorf(key string, args ...any) error {
+	if len(args) == 0 {
+		return errors.New(l.template(key))
+	}
+	return fmt.Errorf(l.template(key), args...)
+}
+
+func (l *Localizer) template(key string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if template, ok := catalog[l.Locale][key]; ok {
+		return template
+	}
+	if template, ok := catalog[FALLBACK_LOCALE][key]; ok {
+		return template
+	}
+	return key
+}
+
+// Explain tells an end user what kind of failure err is, in terms of the utils error taxonomy.
+// The empty string is returned for errors outside of it.
+func (l *Localizer) Explain(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return l.T("error.timeout")
+	case errors.Is(err, context.Canceled):
+		return l.T("error.canceled")
+	}
+	switch utils.Category(err) {
+	case utils.ErrInvalidInput:
+		return l.T("error.invalid_input")
+	case utils.ErrUnsupported:
+		return l.T("error.unsupported")
+	case utils.ErrNotFound:
+		return l.T("error.not_found")
+	case utils.ErrRejected:
+		return l.T("error.rejected")
+	case utils.ErrUnavailable:
+		return l.T("error.unavailable")
+	case utils.ErrConflict:
+		return l.T("error.conflict")
+	default:
+		return ""
+	}
+}
+
+// Format prefixes the message of err with its explanation, the message itself stays as the
+// package that returned err wrote it
+func (l *Localizer) Format(err error) string {
+	if err == nil {
+		return ""
+	}
+	if explanation := l.Explain(err); explanation != "" {
+		return l.T("error.format", explanation, err)
+	}
+	return err.Error()
+}
+
+// T renders a message in the default language, see Localizer.T
+func T(key string, args ...any) string {
+	return Default().T(key, args...)
+}
+
+// Errorf returns an error in the default language, see Localizer.Errorf
+func Errorf(key string, args ...any) error {
+	return Default().Errorf(key, args...)
+}
+
+// Explain explains err in the default language, see Localizer.Explain
+func Explain(err error) string {
+	return Default().Explain(err)
+}
+
+// Format formats err in the default language, see Localizer.Format
+func Format(err error) string {
+	return Default().Format(err)
+}
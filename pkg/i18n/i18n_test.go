@@ -0,0 +1,104 @@
+package i18n_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/i18n"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestParseLocale(t *testing.T) {
+	for tag, want := range map[string]i18n.Locale{
+		"es":          i18n.LOCALE_ES,
+		"pt-BR":       i18n.LOCALE_PT,
+		"pt_BR.UTF-8": i18n.LOCALE_PT,
+		"EN_us":       i18n.LOCALE_EN,
+	} {
+		if got, ok := i18n.ParseLocale(tag); !ok || got != want {
+			t.Fatalf("ParseLocale(%q) = %q, %v", tag, got, ok)
+		}
+	}
+	for _, tag := range []string{"", "C", "POSIX", "de_DE.UTF-8"} {
+		if _, ok := i18n.ParseLocale(tag); ok {
+			t.Fatalf("ParseLocale(%q) accepted an unsupported locale", tag)
+		}
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	t.Setenv(i18n.ENV_LOCALE, "")
+	if got := i18n.LocaleFromEnv(); got != i18n.LOCALE_PT {
+		t.Fatalf("LocaleFromEnv() with LANG = %q", got)
+	}
+	t.Setenv(i18n.ENV_LOCALE, "es")
+	if got := i18n.LocaleFromEnv(); got != i18n.LOCALE_ES {
+		t.Fatalf("LocaleFromEnv() with %s = %q", i18n.ENV_LOCALE, got)
+	}
+	// Unsupported languages are skipped
+	t.Setenv(i18n.ENV_LOCALE, "de")
+	t.Setenv("LANG", "C.UTF-8")
+	if got := i18n.LocaleFromEnv(); got != i18n.FALLBACK_LOCALE {
+		t.Fatalf("LocaleFromEnv() without a supported locale = %q", got)
+	}
+}
+
+func TestLocalizer(t *testing.T) {
+	i18n.Register(i18n.LOCALE_EN, i18n.Messages{"test.greeting": "hello %s", "test.english": "only in English"})
+	i18n.Register(i18n.LOCALE_ES, i18n.Messages{"test.greeting": "hola %s"})
+	es := i18n.New(i18n.LOCALE_ES)
+
+	if got := es.T("test.greeting", "mundo"); got != "hola mundo" {
+		t.Fatalf("T() = %q", got)
+	}
+	if got := es.T("test.english"); got != "only in English" {
+		t.Fatalf("T() of a key missing from the language = %q", got)
+	}
+	if got := es.T("test.unknown"); got != "test.unknown" {
+		t.Fatalf("T() of an unknown key = %q", got)
+	}
+
+	i18n.Register(i18n.LOCALE_EN, i18n.Messages{"test.wrapped": "reading %s: %w"})
+	err := es.Errorf("test.wrapped", "file", os.ErrNotExist)
+	if !errors.Is(err, os.ErrNotExist) || err.Error() != "reading file: "+os.ErrNotExist.Error() {
+		t.Fatalf("Errorf() = %v", err)
+	}
+
+	t.Cleanup(func() { i18n.SetDefault(i18n.LocaleFromEnv()) })
+	i18n.SetDefault(i18n.LOCALE_PT)
+	if got := i18n.Default().Locale; got != i18n.LOCALE_PT {
+		t.Fatalf("Default() after SetDefault() = %q", got)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	rejected := fmt.Errorf("submitting: %w", utils.ErrRejected)
+	pt := i18n.New(i18n.LOCALE_PT)
+	if got := pt.Explain(rejected); got != "A solicitação foi rejeitada" {
+		t.Fatalf("Explain() = %q", got)
+	}
+	if got := pt.Format(rejected); got != "A solicitação foi rejeitada: submitting: rejected" {
+		t.Fatalf("Format() = %q", got)
+	}
+	if got := i18n.New(i18n.LOCALE_ES).Explain(&utils.ErrMalformed{What: "wallet file", Msg: "bad"}); got != "La solicitud contiene datos no válidos" {
+		t.Fatalf("Explain() of an ErrMalformed = %q", got)
+	}
+	if got := pt.Explain(fmt.Errorf("waiting: %w", context.DeadlineExceeded)); got != "A operação excedeu o tempo limite" {
+		t.Fatalf("Explain() of a timeout = %q", got)
+	}
+
+	// Errors outside of the taxonomy are left as they are
+	plain := errors.New("disk full")
+	if got := pt.Explain(plain); got != "" {
+		t.Fatalf("Explain() of an uncategorized error = %q", got)
+	}
+	if got := pt.Format(plain); got != "disk full" {
+		t.Fatalf("Format() of an uncategorized error = %q", got)
+	}
+}
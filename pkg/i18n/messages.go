@@ -0,0 +1,37 @@
+package i18n
+
+func init() {
+	Register(LOCALE_EN, Messages{
+		"error.format":        "%s: %v",
+		"error.invalid_input": "The request contains invalid data",
+		"error.unsupported":   "This operation is not supported",
+		"error.not_found":     "The requested resource does not exist",
+		"error.rejected":      "The request was rejected",
+		"error.unavailable":   "The service is temporarily unavailable, please try again later",
+		"error.conflict":      "The data changed while the request was processed",
+		"error.timeout":       "The operation timed out",
+		"error.canceled":      "The operation was canceled",
+	})
+	Register(LOCALE_ES, Messages{
+		"error.format":        "%s: %v",
+		"error.invalid_input": "La solicitud contiene datos no válidos",
+		"error.unsupported":   "Esta operación no está soportada",
+		"error.not_found":     "El recurso solicitado no existe",
+		"error.rejected":      "La solicitud fue rechazada",
+		"error.unavailable":   "El servicio no está disponible temporalmente, inténtelo de nuevo más tarde",
+		"error.conflict":      "Los datos cambiaron mientras se procesaba la solicitud",
+		"error.timeout":       "La operación superó el tiempo de espera",
+		"error.canceled":      "La operación fue cancelada",
+	})
+	Register(LOCALE_PT, Messages{
+		"error.format":        "%s: %v",
+		"error.invalid_input": "A solicitação contém dados inválidos",
+		"error.unsupported":   "Esta operação não é suportada",
+		"error.not_found":     "O recurso solicitado não existe",
+		"error.rejected":      "A solicitação foi rejeitada",
+		"error.unavailable":   "O serviço está temporariamente indisponível, tente novamente mais tarde",
+		"error.conflict":      "Os dados mudaram enquanto a solicitação era processada",
+		"error.timeout":       "A operação excedeu o tempo limite",
+		"error.canceled":      "A operação foi cancelada",
+	})
+}
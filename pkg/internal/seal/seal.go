@@ -0,0 +1,24 @@
+// Package seal holds the key derivation shared by the packages that encrypt to a wallet
+package seal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"slices"
+)
+
+// AEAD derives the AES-GCM cipher of a key agreement with HKDF-SHA256. The salt binds the ephemeral
+// and recipient public keys, info separates the protocols using it.
+func AEAD(shared []byte, ephemeral []byte, recipient []byte, info string) (cipher.AEAD, error) {
+	key, err := hkdf.Key(sha256.New, shared, slices.Concat(ephemeral, recipient), info, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
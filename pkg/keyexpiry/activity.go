@@ -0,0 +1,152 @@
+package keyexpiry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// ActivityStore keeps the last time each wallet signed a transaction
+type ActivityStore interface {
+	LastUsed(address string) (time.Time, bool, error)
+	// Touch records a use, older timestamps than the stored one are ignored
+	Touch(address string, at time.Time) error
+}
+
+// MemoryStore is an ActivityStore that lives as long as the process
+type MemoryStore struct {
+	mu       sync.Mutex
+	lastUsed map[string]time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{lastUsed: make(map[string]time.Time)}
+}
+
+func (store *MemoryStore) LastUsed(address string) (time.Time, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	at, ok := store.lastUsed[address]
+	return at, ok, nil
+}
+
+func (store *MemoryStore) Touch(address string, at time.Time) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if at.After(store.lastUsed[address]) {
+		store.lastUsed[address] = at.UTC()
+	}
+	return nil
+}
+
+// FileStore is an ActivityStore persisted as a JSON object of address to RFC 3339 timestamp
+type FileStore struct {
+	path   string
+	memory *MemoryStore
+}
+
+// NewFileStore loads path, a missing file starts an empty store
+func NewFileStore(path string) (*FileStore, error) {
+	store := &FileStore{path: path, memory: NewMemoryStore()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read activity file: %w", err)
+	}
+	if err := json.Unmarshal(data, &store.memory.lastUsed); err != nil {
+		return nil, fmt.Errorf("invalid activity file %s: %w", path, err)
+	}
+	return store, nil
+}
+
+func (store *FileStore) LastUsed(address string) (time.Time, bool, error) {
+	return store.memory.LastUsed(address)
+}
+
+func (store *FileStore) Touch(address string, at time.Time) error {
+	store.memory.mu.Lock()
+	defer store.memory.mu.Unlock()
+	if !at.After(store.memory.lastUsed[address]) {
+		return nil
+	}
+	store.memory.lastUsed[address] = at.UTC()
+
+	data, err := json.MarshalIndent(store.memory.lastUsed, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves a truncated file behind
+	tmp := store.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write activity file: %w", err)
+	}
+	if err := os.Rename(tmp, store.path); err != nil {
+		return fmt.Errorf("unable to write activity file: %w", err)
+	}
+	return nil
+}
+
+// Tracker records wallet activity into a store
+type Tracker struct {
+	store ActivityStore
+	// OnError receives store failures from hooks, which cannot return them
+	OnError func(error)
+}
+
+func NewTracker(store ActivityStore) *Tracker {
+	return &Tracker{store: store}
+}
+
+// Store returns the store the tracker writes to
+func (tracker *Tracker) Store() ActivityStore {
+	return tracker.store
+}
+
+// Touch records that address signed a transaction at at
+func (tracker *Tracker) Touch(address string, at time.Time) error {
+	return tracker.store.Touch(address, at)
+}
+
+// Attach records every transaction the session submits as a use of its sender, chaining any
+// OnAfterSubmit hook already installed
+func (tracker *Tracker) Attach(session *transaction.UL_TransactionSession) {
+	hooks := session.Hooks()
+	next := hooks.OnAfterSubmit
+	hooks.OnAfterSubmit = func(event transaction.AfterSubmitEvent) {
+		if event.Input.From != "" {
+			if err := tracker.store.Touch(event.Input.From, event.Input.SenderTimestamp); err != nil && tracker.OnError != nil {
+				tracker.OnError(err)
+			}
+		}
+		if next != nil {
+			next(event)
+		}
+	}
+	session.SetHooks(hooks)
+}
+
+// Seed walks the transactions of a chain and records the newest use of every sender, so a tracker
+// started late does not treat active wallets as dormant
+func (tracker *Tracker) Seed(ctx context.Context, session *transaction.UL_TransactionSession, blockchainId string) error {
+	it := session.ListTransactions(blockchainId, transaction.TransactionFilter{}, transaction.ListOptions{})
+	for tx := range it.All(ctx) {
+		if tx.From == "" {
+			continue
+		}
+		if err := tracker.store.Touch(tx.From, tx.SenderTimestamp); err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("unable to seed wallet activity: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package keyexpiry
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func newSession(t *testing.T, node *transactiontest.MockNode, parent string) *transaction.UL_TransactionSession {
+	t.Helper()
+	session, w := node.NewSession(t, crypto.KeyTypeSecp256k1, parent)
+	payload, _ := json.Marshal(transaction.CreateWalletPayload{PublicKey: w.GetKey().GetPublicKeyHex(false), Parent: parent, KeyType: crypto.KeyTypeSecp256k1})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		From:         parent,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_CREATE_WALLET.String(),
+	}); err != nil {
+		t.Fatalf("CREATE_WALLET error = %v", err)
+	}
+	return session
+}
+
+func enabled(t *testing.T, session *transaction.UL_TransactionSession, address string) bool {
+	t.Helper()
+	wallets, err := session.ListWallets(testBlockchainId, "", transaction.ListOptions{}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("ListWallets() error = %v", err)
+	}
+	for _, registered := range wallets {
+		if registered.Address == address {
+			return registered.Enabled
+		}
+	}
+	t.Fatalf("wallet %s is not registered", address)
+	return false
+}
+
+func TestDisableDormantKeys(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+
+	admin := newSession(t, node, "")
+	adminAddress := admin.GetWallet().Address
+	active := newSession(t, node, adminAddress)
+	idle := newSession(t, node, adminAddress)
+	exempt := newSession(t, node, adminAddress)
+
+	tracker := NewTracker(NewMemoryStore())
+	tracker.Attach(active)
+	if _, err := active.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           active.GetWallet().Address,
+		Payload:      "still here",
+		PayloadType:  transaction.TX_DATA.String(),
+	}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	enforcer, err := NewEnforcer(admin, testBlockchainId, tracker, Policy{
+		MaxIdle: time.Hour,
+		Parent:  adminAddress,
+		Exempt:  []string{exempt.GetWallet().Address},
+	})
+	if err != nil {
+		t.Fatalf("NewEnforcer() error = %v", err)
+	}
+	now := time.Now()
+	enforcer.since = now.Add(-2 * time.Hour)
+	enforcer.now = func() time.Time { return now.Add(30 * time.Minute) }
+
+	actions, err := enforcer.DisableDormant(ctx)
+	if err != nil {
+		t.Fatalf("DisableDormant() error = %v", err)
+	}
+	if len(actions) != 1 || actions[0].Address != idle.GetWallet().Address || actions[0].TransactionId == "" {
+		t.Fatalf("DisableDormant() = %+v, want only the idle wallet", actions)
+	}
+	if enabled(t, admin, idle.GetWallet().Address) || !enabled(t, admin, active.GetWallet().Address) || !enabled(t, admin, exempt.GetWallet().Address) {
+		t.Fatal("the policy disabled the wrong wallets")
+	}
+
+	// A re-enabled key gets a full window before it is disabled again
+	if _, err := enforcer.Reenable(ctx, idle.GetWallet().Address); err != nil {
+		t.Fatalf("Reenable() error = %v", err)
+	}
+	if !enabled(t, admin, idle.GetWallet().Address) {
+		t.Fatal("Reenable() left the wallet disabled")
+	}
+	dormant, err := enforcer.Dormant(ctx)
+	if err != nil || len(dormant) != 0 {
+		t.Fatalf("Dormant() = %+v, %v", dormant, err)
+	}
+
+	enforcer.now = func() time.Time { return now.Add(3 * time.Hour) }
+	dormant, err = enforcer.Dormant(ctx)
+	if err != nil || len(dormant) != 2 {
+		t.Fatalf("Dormant() = %+v, %v, want both non exempt children", dormant, err)
+	}
+}
+
+func TestSeedAndFileStore(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	session := newSession(t, node, "")
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "seed me",
+		PayloadType:  transaction.TX_DATA.String(),
+	}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "activity.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	if err := NewTracker(store).Seed(context.Background(), session, testBlockchainId); err != nil {
+		t.Fatalf("Seed() error = %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore() error = %v", err)
+	}
+	lastUsed, ok, err := reloaded.LastUsed(session.GetWallet().Address)
+	if err != nil || !ok || time.Since(lastUsed) > time.Minute {
+		t.Fatalf("LastUsed() = %v, %v, %v", lastUsed, ok, err)
+	}
+
+	// Older uses never move the timestamp back
+	if err := reloaded.Touch(session.GetWallet().Address, lastUsed.Add(-time.Hour)); err != nil {
+		t.Fatalf("Touch() error = %v", err)
+	}
+	if again, _, _ := reloaded.LastUsed(session.GetWallet().Address); !again.Equal(lastUsed) {
+		t.Fatalf("LastUsed() = %v, want %v", again, lastUsed)
+	}
+}
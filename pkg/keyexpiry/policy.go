@@ -0,0 +1,194 @@
+package keyexpiry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+type Policy struct {
+	// MaxIdle is how long a key may go unused before it is disabled
+	MaxIdle time.Duration
+	// Parent restricts the policy to the direct children of a wallet, empty covers every wallet of the chain
+	Parent string
+	// Exempt wallets are never disabled, the enforcing wallet is always exempt
+	Exempt []string
+}
+
+// DormantKey is an enabled wallet that has been idle longer than the policy allows
+type DormantKey struct {
+	Address string
+	// LastUsed is zero when the tracker never saw the wallet sign
+	LastUsed time.Time
+	Idle     time.Duration
+}
+
+// Action is the outcome of one ALTER_WALLET submitted by the enforcer
+type Action struct {
+	Address       string
+	Enabled       bool
+	TransactionId string
+	Err           error
+}
+
+// Enforcer disables dormant keys with ALTER_WALLET transactions signed by the session's wallet,
+// which must be allowed to alter the wallets the policy covers
+type Enforcer struct {
+	session      *transaction.UL_TransactionSession
+	blockchainId string
+	tracker      *Tracker
+	policy       Policy
+	now          func() time.Time
+	// since stands in for the last use of wallets the tracker never saw
+	since time.Time
+}
+
+func NewEnforcer(session *transaction.UL_TransactionSession, blockchainId string, tracker *Tracker, policy Policy) (*Enforcer, error) {
+	if policy.MaxIdle <= 0 {
+		return nil, fmt.Errorf("the policy needs a positive idle window")
+	}
+	if blockchainId == "" {
+		return nil, fmt.Errorf("a blockchain id is required")
+	}
+	return &Enforcer{
+		session:      session,
+		blockchainId: blockchainId,
+		tracker:      tracker,
+		policy:       policy,
+		now:          time.Now,
+		since:        time.Now().UTC(),
+	}, nil
+}
+
+// Dormant lists the enabled wallets idle for longer than the policy window. Wallets without
+// recorded activity count as idle since the enforcer was created, seed the tracker to avoid it.
+func (e *Enforcer) Dormant(ctx context.Context) ([]DormantKey, error) {
+	now := e.now().UTC()
+	dormant := []DormantKey{}
+	it := e.session.ListWallets(e.blockchainId, e.policy.Parent, transaction.ListOptions{})
+	for registered := range it.All(ctx) {
+		if !registered.Enabled || e.exempt(registered.Address) {
+			continue
+		}
+		lastUsed, ok, err := e.tracker.store.LastUsed(registered.Address)
+		if err != nil {
+			return nil, err
+		}
+		idleSince := lastUsed
+		if !ok {
+			idleSince = e.since
+		}
+		if idle := now.Sub(idleSince); idle > e.policy.MaxIdle {
+			dormant = append(dormant, DormantKey{Address: registered.Address, LastUsed: lastUsed, Idle: idle})
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("unable to list wallets: %w", err)
+	}
+	return dormant, nil
+}
+
+// DisableDormant disables every dormant key. Failures do not stop the sweep, they are reported in
+// the actions and joined in the returned error.
+func (e *Enforcer) DisableDormant(ctx context.Context) ([]Action, error) {
+	dormant, err := e.Dormant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	actions := make([]Action, 0, len(dormant))
+	errs := []error{}
+	for _, key := range dormant {
+		action := e.alter(ctx, key.Address, false)
+		if action.Err != nil {
+			errs = append(errs, action.Err)
+		}
+		actions = append(actions, action)
+	}
+	return actions, errors.Join(errs...)
+}
+
+// Reenable turns a disabled key back on and records the re-enablement as a use, so the key gets a
+// full idle window before the policy disables it again
+func (e *Enforcer) Reenable(ctx context.Context, address string) (Action, error) {
+	action := e.alter(ctx, address, true)
+	if action.Err != nil {
+		return action, action.Err
+	}
+	if err := e.tracker.Touch(address, e.now()); err != nil {
+		return action, err
+	}
+	return action, nil
+}
+
+// Run sweeps dormant keys every interval until ctx is done, report receives the outcome of each sweep
+func (e *Enforcer) Run(ctx context.Context, interval time.Duration, report func([]Action, error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		actions, err := e.DisableDormant(ctx)
+		if report != nil {
+			report(actions, err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Enforcer) exempt(address string) bool {
+	return address == e.session.GetWallet().Address || slices.Contains(e.policy.Exempt, address)
+}
+
+// alter submits ALTER_WALLET for address, keeping its auth groups as registered
+func (e *Enforcer) alter(ctx context.Context, address string, enabled bool) Action {
+	action := Action{Address: address, Enabled: enabled}
+	registered, err := e.lookup(ctx, address)
+	if err != nil {
+		action.Err = err
+		return action
+	}
+	payload, err := json.Marshal(transaction.AlterWalletPayload{
+		Target:     address,
+		Enabled:    enabled,
+		AuthGroups: registered.AuthGroups,
+	})
+	if err != nil {
+		action.Err = err
+		return action
+	}
+	tx, err := e.session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: e.blockchainId,
+		To:           address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_ALTER_WALLET.String(),
+	})
+	if err != nil {
+		action.Err = fmt.Errorf("unable to alter wallet %s: %w", address, err)
+		return action
+	}
+	action.TransactionId = tx.TransactionId
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		action.Err = fmt.Errorf("altering wallet %s failed with %s", address, tx.Output)
+	}
+	return action
+}
+
+func (e *Enforcer) lookup(ctx context.Context, address string) (transaction.ULWalletInfo, error) {
+	it := e.session.ListWallets(e.blockchainId, "", transaction.ListOptions{})
+	for registered := range it.All(ctx) {
+		if registered.Address == address {
+			return registered, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return transaction.ULWalletInfo{}, fmt.Errorf("unable to list wallets: %w", err)
+	}
+	return transaction.ULWalletInfo{}, fmt.Errorf("wallet %s is not registered on %s", address, e.blockchainId)
+}
@@ -0,0 +1,189 @@
+package messaging
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/exchange"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Message is a message received by an Inbox. From is the wallet that sent the transaction, the
+// message only opens if it was sealed for that sender.
+type Message struct {
+	BlockchainId  string
+	TransactionId string
+	BlockHeight   int
+	From          string
+	SentAt        time.Time
+	Body          []byte
+}
+
+// Inbox reads the messages sent to a wallet
+type Inbox struct {
+	// Transport fetches the messages sent by pointer, they cannot be read without it
+	Transport exchange.Transport
+	// OnError is told about messages that cannot be read, which Scan and Subscribe skip
+	OnError func(err error)
+
+	query  transaction.QueryAPI
+	wallet wallet.UL_Wallet
+}
+
+// NewInbox returns the inbox of w, which must hold its private key
+func NewInbox(query transaction.QueryAPI, w wallet.UL_Wallet) (*Inbox, error) {
+	if err := w.CheckKey(); err != nil {
+		return nil, err
+	}
+	if _, err := ephemeralSize(w.GetKey().GetType()); err != nil {
+		return nil, err
+	}
+	return &Inbox{query: query, wallet: w}, nil
+}
+
+// IsMessage reports whether tx looks like a message to the wallet of the inbox, without opening it
+func (i *Inbox) IsMessage(tx transaction.ULTransaction) bool {
+	if !strings.EqualFold(tx.To, i.wallet.Address) || tx.PayloadType != transaction.TX_DATA.String() {
+		return false
+	}
+	_, ok := ParseEnvelope(tx.GetPayload())
+	return ok
+}
+
+// Read opens the message tx carries
+func (i *Inbox) Read(ctx context.Context, tx transaction.ULTransaction) (Message, error) {
+	if !i.IsMessage(tx) {
+		return Message{}, &ErrUnreadable{TransactionId: tx.TransactionId, Msg: "it is not a message to " + i.wallet.Address}
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return Message{}, &ErrUnreadable{TransactionId: tx.TransactionId, Msg: "the transaction was not applied"}
+	}
+	envelope, _ := ParseEnvelope(tx.GetPayload())
+	sealed, err := i.sealed(ctx, tx.TransactionId, envelope)
+	if err != nil {
+		return Message{}, err
+	}
+	body, err := Open(sealed, tx.From, i.wallet)
+	if err != nil {
+		return Message{}, &ErrUnreadable{TransactionId: tx.TransactionId, Msg: err.Error()}
+	}
+	return Message{
+		BlockchainId:  tx.BlockchainId,
+		TransactionId: tx.TransactionId,
+		BlockHeight:   tx.BlockHeight,
+		From:          tx.From,
+		SentAt:        tx.SenderTimestamp,
+		Body:          body,
+	}, nil
+}
+
+// sealed returns the sealed message of an envelope, fetching it from the transport if needed
+func (i *Inbox) sealed(ctx context.Context, transactionId string, envelope Envelope) ([]byte, error) {
+	if envelope.Sealed != "" {
+		sealed, err := crypto.HexToBytes(envelope.Sealed)
+		if err != nil {
+			return nil, &ErrUnreadable{TransactionId: transactionId, Msg: err.Error()}
+		}
+		return sealed, nil
+	}
+	if i.Transport == nil {
+		return nil, &ErrUnreadable{TransactionId: transactionId, Msg: "it is stored at " + envelope.Pointer + " and the inbox has no transport"}
+	}
+	sealed, err := i.Transport.Get(ctx, envelope.Pointer)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch %s: %w", envelope.Pointer, err)
+	}
+	digest := sha256.Sum256(sealed)
+	if hex.EncodeToString(digest[:]) != envelope.Sha256 || len(sealed) != envelope.Size {
+		return nil, &ErrUnreadable{TransactionId: transactionId, Msg: fmt.Sprintf("the data at %s does not match its hash", envelope.Pointer)}
+	}
+	return sealed, nil
+}
+
+// Scan reads the messages of past transactions, in block order. opts pages through the transactions
+// of the wallet, not only its messages.
+func (i *Inbox) Scan(ctx context.Context, blockchainId string, opts transaction.ListOptions) ([]Message, error) {
+	filter := transaction.TransactionFilter{Address: i.wallet.Address, PayloadType: transaction.TX_DATA.String()}
+	txs, err := i.query.ListTransactions(blockchainId, filter, opts).Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	messages := []Message{}
+	for _, tx := range txs {
+		if message, ok := i.read(ctx, tx); ok {
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// read opens the message of tx if it carries one, telling OnError why it could not be read
+func (i *Inbox) read(ctx context.Context, tx transaction.ULTransaction) (Message, bool) {
+	if !i.IsMessage(tx) {
+		return Message{}, false
+	}
+	message, err := i.Read(ctx, tx)
+	if err != nil {
+		if i.OnError != nil {
+			i.OnError(err)
+		}
+		return Message{}, false
+	}
+	return message, true
+}
+
+type SubscribeOptions struct {
+	// PollInterval is how often the node is asked for new blocks
+	PollInterval time.Duration
+	// Store persists the last block read, so a restarted inbox does not deliver messages twice.
+	// Defaults to an in memory store.
+	Store transaction.CheckpointStore
+}
+
+// Subscribe delivers the messages of the blocks from fromBlock on, in chain order, until the
+// context is cancelled and the channel is closed. The blocks are read as SubscribeBlocks reads them.
+func (i *Inbox) Subscribe(ctx context.Context, blockchainId string, fromBlock int, opts SubscribeOptions) (<-chan Message, error) {
+	if fromBlock < 1 {
+		fromBlock = 1
+	}
+	messages := make(chan Message, 16)
+	handler := func(ctx context.Context, block transaction.ULBlock) error {
+		for _, tx := range block.Transactions {
+			if tx.BlockchainId == "" {
+				tx.BlockchainId = blockchainId
+			}
+			message, ok := i.read(ctx, tx)
+			if !ok {
+				continue
+			}
+			select {
+			case messages <- message:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+	go func() {
+		defer close(messages)
+		blocks := transaction.BlockSubscriptionOptions{
+			Consumer:     "messages/" + i.wallet.Address,
+			StartHeight:  fromBlock,
+			PollInterval: opts.PollInterval,
+			Store:        opts.Store,
+			OnError:      i.OnError,
+		}
+		err := i.query.SubscribeBlocks(ctx, blockchainId, blocks, handler)
+		if i.OnError != nil && !errors.Is(err, ctx.Err()) {
+			i.OnError(err)
+		}
+	}()
+	return messages, nil
+}
@@ -0,0 +1,322 @@
+// Package messaging sends encrypted messages from wallet to wallet over TX_DATA transactions, e.g.
+// notifications or the first step of a handshake. Messages are sealed to the public key of the
+// recipient wallet, no other key needs to be published, and travel inline in the transaction or, when
+// large, through an exchange.Transport with only their hash on-chain. Recipients read them with an
+// Inbox, scanning past transactions or subscribing to new blocks.
+//
+// Messages are sealed with an ephemeral key agreement on the curve of the recipient's key, X25519 for
+// ed25519 wallets and ECDH for secp256k1 wallets, HKDF-SHA256 and AES-256-GCM. The sender and the
+// recipient addresses are authenticated data, so a message copied into another transaction does not open.
+package messaging
+
+import (
+	"context"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/exchange"
+	"github.com/ULedgerInc/go-sdk/pkg/internal/seal"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+const (
+	// MESSAGE_VERSION is the version of the sealed format and of Envelope
+	MESSAGE_VERSION = 1
+	// DEFAULT_MAX_INLINE_SIZE is the largest sealed message sent inline when a Transport is set
+	DEFAULT_MAX_INLINE_SIZE = 4 << 10
+
+	sealInfo  = "uledger message v1"
+	nonceSize = 12
+)
+
+// ErrUnreadable is returned for a transaction that is not a message the wallet can read
+type ErrUnreadable struct {
+	TransactionId string
+	Msg           string
+}
+
+func (e *ErrUnreadable) Error() string {
+	return fmt.Sprintf("message %s cannot be read, %s", e.TransactionId, e.Msg)
+}
+
+func (e *ErrUnreadable) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Envelope is the TX_DATA payload of a message, sent to the recipient. Inline messages carry the
+// sealed message, the others where to fetch it and its hash.
+type Envelope struct {
+	Version int    `json:"messageVersion"`
+	Sealed  string `json:"sealed,omitempty"`
+	Sha256  string `json:"sha256,omitempty"`
+	Pointer string `json:"pointer,omitempty"`
+	Size    int    `json:"size,omitempty"`
+}
+
+// ParseEnvelope decodes a message envelope from a transaction payload, ok is false for any other payload
+func ParseEnvelope(payload string) (Envelope, bool) {
+	envelope := Envelope{}
+	if err := json.Unmarshal([]byte(payload), &envelope); err != nil || envelope.Version == 0 {
+		return Envelope{}, false
+	}
+	if envelope.Sealed == "" && (envelope.Sha256 == "" || envelope.Pointer == "") {
+		return Envelope{}, false
+	}
+	return envelope, true
+}
+
+// Seal encrypts message from the wallet at address from to the wallet of the uncompressed public key
+// recipientKey, as GetPublicKeyHex(false) returns it. Only ed25519 and secp256k1 keys can receive.
+func Seal(message []byte, from string, recipientKey string, keyType crypto.KeyType) ([]byte, error) {
+	publicKey, err := crypto.HexToBytes(recipientKey)
+	if err != nil {
+		return nil, &utils.ErrMalformed{What: "recipient key", Msg: err.Error()}
+	}
+	ephemeral, shared, err := agree(publicKey, keyType)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := seal.AEAD(shared, ephemeral, publicKey, sealInfo)
+	if err != nil {
+		return nil, err
+	}
+	header := 1 + len(ephemeral) + nonceSize
+	sealed := make([]byte, header, header+len(message)+aead.Overhead())
+	sealed[0] = MESSAGE_VERSION
+	copy(sealed[1:], ephemeral)
+	nonce := sealed[1+len(ephemeral) : header]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(sealed, nonce, message, associatedData(from, wallet.ParseAddress(recipientKey))), nil
+}
+
+// Open decrypts a message sealed by the wallet at address from to w
+func Open(sealed []byte, from string, w wallet.UL_Wallet) ([]byte, error) {
+	if err := w.CheckKey(); err != nil {
+		return nil, err
+	}
+	size, err := ephemeralSize(w.GetKey().GetType())
+	if err != nil {
+		return nil, err
+	}
+	header := 1 + size + nonceSize
+	if len(sealed) < header || sealed[0] != MESSAGE_VERSION {
+		return nil, &utils.ErrMalformed{What: "sealed message", Msg: "the data is not a sealed message"}
+	}
+	ephemeral := sealed[1 : 1+size]
+	shared, err := recoverShared(ephemeral, w)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := crypto.HexToBytes(w.GetKey().GetPublicKeyHex(false))
+	if err != nil {
+		return nil, err
+	}
+	aead, err := seal.AEAD(shared, ephemeral, publicKey, sealInfo)
+	if err != nil {
+		return nil, err
+	}
+	message, err := aead.Open(nil, sealed[1+size:header], sealed[header:], associatedData(from, w.Address))
+	if err != nil {
+		return nil, &utils.ErrMalformed{What: "sealed message", Msg: fmt.Sprintf("it was not sealed by %s to %s", from, w.Address)}
+	}
+	return message, nil
+}
+
+func associatedData(from string, to string) []byte {
+	return []byte(from + "|" + to)
+}
+
+func ephemeralSize(keyType crypto.KeyType) (int, error) {
+	switch keyType {
+	case crypto.KeyTypeED25519:
+		return 32, nil
+	case crypto.KeyTypeSecp256k1:
+		return 65, nil
+	default:
+		return 0, fmt.Errorf("%w: %s wallets cannot receive messages", utils.ErrUnsupported, keyType)
+	}
+}
+
+// agree generates an ephemeral key and returns its public key and the secret it shares with publicKey
+func agree(publicKey []byte, keyType crypto.KeyType) ([]byte, []byte, error) {
+	switch keyType {
+	case crypto.KeyTypeED25519:
+		recipient, err := montgomeryKey(publicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+		shared, err := ephemeral.ECDH(recipient)
+		if err != nil {
+			return nil, nil, &utils.ErrMalformed{What: "recipient key", Msg: err.Error()}
+		}
+		return ephemeral.PublicKey().Bytes(), shared, nil
+	case crypto.KeyTypeSecp256k1:
+		recipient, err := secp256k1Point(publicKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		scalar, err := rand.Int(rand.Reader, new(big.Int).Sub(fr.Modulus(), big.NewInt(1)))
+		if err != nil {
+			return nil, nil, err
+		}
+		scalar.Add(scalar, big.NewInt(1))
+		var ephemeral secp256k1.G1Affine
+		ephemeral.ScalarMultiplicationBase(scalar)
+		return uncompressed(ephemeral), secp256k1Shared(recipient, scalar), nil
+	default:
+		_, err := ephemeralSize(keyType)
+		return nil, nil, err
+	}
+}
+
+// recoverShared returns the secret w shares with the ephemeral key of a sealed message
+func recoverShared(ephemeral []byte, w wallet.UL_Wallet) ([]byte, error) {
+	private, err := crypto.HexToBytes(w.GetKey().GetPrivateKeyHex())
+	if err != nil || len(private) < 32 {
+		return nil, &utils.ErrMalformed{What: "private key", Msg: "the wallet holds no valid private key"}
+	}
+	switch w.GetKey().GetType() {
+	case crypto.KeyTypeED25519:
+		// The X25519 scalar of an ed25519 key is the first half of the SHA-512 of its seed, as in RFC 8032
+		digest := sha512.Sum512(private[:32])
+		key, err := ecdh.X25519().NewPrivateKey(digest[:32])
+		if err != nil {
+			return nil, err
+		}
+		public, err := ecdh.X25519().NewPublicKey(ephemeral)
+		if err != nil {
+			return nil, &utils.ErrMalformed{What: "sealed message", Msg: err.Error()}
+		}
+		shared, err := key.ECDH(public)
+		if err != nil {
+			return nil, &utils.ErrMalformed{What: "sealed message", Msg: err.Error()}
+		}
+		return shared, nil
+	default:
+		point, err := secp256k1Point(ephemeral)
+		if err != nil {
+			return nil, err
+		}
+		return secp256k1Shared(point, new(big.Int).SetBytes(private)), nil
+	}
+}
+
+// edwardsPrime is the field modulus of Curve25519, 2^255 - 19
+var edwardsPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// montgomeryKey maps an ed25519 public key to its X25519 counterpart, u = (1 + y) / (1 - y)
+func montgomeryKey(publicKey []byte) (*ecdh.PublicKey, error) {
+	if len(publicKey) != 32 {
+		return nil, &utils.ErrMalformed{What: "recipient key", Msg: fmt.Sprintf("ed25519 keys are 32 bytes, not %d", len(publicKey))}
+	}
+	littleEndian := slices.Clone(publicKey)
+	littleEndian[31] &= 0x7f
+	slices.Reverse(littleEndian)
+	y := new(big.Int).SetBytes(littleEndian)
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, edwardsPrime)
+	if y.Cmp(edwardsPrime) >= 0 || denominator.Sign() == 0 {
+		return nil, &utils.ErrMalformed{What: "recipient key", Msg: "it is not a point of ed25519"}
+	}
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, denominator.ModInverse(denominator, edwardsPrime))
+	u.Mod(u, edwardsPrime)
+	encoded := u.FillBytes(make([]byte, 32))
+	slices.Reverse(encoded)
+	return ecdh.X25519().NewPublicKey(encoded)
+}
+
+// secp256k1Point decodes an uncompressed secp256k1 key, checking the point is on the curve
+func secp256k1Point(publicKey []byte) (secp256k1.G1Affine, error) {
+	var point secp256k1.G1Affine
+	if len(publicKey) != 65 || publicKey[0] != 0x04 {
+		return point, &utils.ErrMalformed{What: "secp256k1 key", Msg: "it must be 65 bytes starting with 0x04"}
+	}
+	point.X.SetBytes(publicKey[1:33])
+	point.Y.SetBytes(publicKey[33:])
+	if point.IsInfinity() || !point.IsOnCurve() {
+		return point, &utils.ErrMalformed{What: "secp256k1 key", Msg: "it is not a point of secp256k1"}
+	}
+	return point, nil
+}
+
+// secp256k1Shared is the x coordinate of scalar times point
+func secp256k1Shared(point secp256k1.G1Affine, scalar *big.Int) []byte {
+	var shared secp256k1.G1Affine
+	shared.ScalarMultiplication(&point, scalar)
+	x := shared.X.Bytes()
+	return x[:]
+}
+
+func uncompressed(point secp256k1.G1Affine) []byte {
+	x, y := point.X.Bytes(), point.Y.Bytes()
+	return slices.Concat([]byte{0x04}, x[:], y[:])
+}
+
+type SendOptions struct {
+	// Transport stores the sealed messages above MaxInlineSize, every message is sent inline without it
+	Transport exchange.Transport
+	// MaxInlineSize is the largest sealed message sent inline, DEFAULT_MAX_INLINE_SIZE when zero
+	MaxInlineSize int
+}
+
+// Send seals message from the session's wallet to the wallet of recipientKey and sends it with a
+// TX_DATA transaction addressed to that wallet
+func Send(ctx context.Context, session transaction.SessionAPI, blockchainId string, recipientKey string, keyType crypto.KeyType, message []byte, opts SendOptions) (transaction.ULTransaction, error) {
+	if opts.MaxInlineSize <= 0 {
+		opts.MaxInlineSize = DEFAULT_MAX_INLINE_SIZE
+	}
+	sender := session.GetWallet().Address
+	recipient := wallet.ParseAddress(recipientKey)
+	sealed, err := Seal(message, sender, recipientKey, keyType)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+
+	envelope := Envelope{Version: MESSAGE_VERSION}
+	if opts.Transport != nil && len(sealed) > opts.MaxInlineSize {
+		digest := sha256.Sum256(sealed)
+		envelope.Sha256, envelope.Size = hex.EncodeToString(digest[:]), len(sealed)
+		envelope.Pointer, err = opts.Transport.Put(ctx, envelope.Sha256, sealed)
+		if err != nil {
+			return transaction.ULTransaction{}, fmt.Errorf("unable to store the sealed message: %w", err)
+		}
+	} else {
+		envelope.Sealed = hex.EncodeToString(sealed)
+	}
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           recipient,
+		Payload:      string(data),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		return tx, fmt.Errorf("unable to send the message: %w", err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return tx, fmt.Errorf("the message %s was not applied: %s", tx.TransactionId, tx.Output)
+	}
+	return tx, nil
+}
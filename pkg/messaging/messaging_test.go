@@ -0,0 +1,146 @@
+package messaging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/exchange"
+	"github.com/ULedgerInc/go-sdk/pkg/messaging"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func TestSealAndOpen(t *testing.T) {
+	for _, keyType := range []crypto.KeyType{crypto.KeyTypeED25519, crypto.KeyTypeSecp256k1} {
+		t.Run(keyType.String(), func(t *testing.T) {
+			recipient, _, err := wallet.GenerateNewWallet("", keyType, "", nil, wallet.DefaultEntropy)
+			if err != nil {
+				t.Fatalf("GenerateNewWallet() error = %v", err)
+			}
+			other, _, _ := wallet.GenerateNewWallet("", keyType, "", nil, wallet.DefaultEntropy)
+			sender := strings.Repeat("ab", 32)
+			sealed, err := messaging.Seal([]byte("hello"), sender, recipient.GetKey().GetPublicKeyHex(false), keyType)
+			if err != nil {
+				t.Fatalf("Seal() error = %v", err)
+			}
+			if message, err := messaging.Open(sealed, sender, recipient); err != nil || string(message) != "hello" {
+				t.Fatalf("Open() = %q, %v", message, err)
+			}
+			if _, err := messaging.Open(sealed, sender, other); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("Open() by another wallet error = %v", err)
+			}
+			if _, err := messaging.Open(sealed, strings.Repeat("cd", 32), recipient); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("Open() of a message claimed by another sender error = %v", err)
+			}
+		})
+	}
+
+	mldsa, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeMlDSA87, "", nil, wallet.DefaultEntropy)
+	if _, err := messaging.Seal([]byte("hello"), "", mldsa.GetKey().GetPublicKeyHex(false), crypto.KeyTypeMlDSA87); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("Seal() to an ML-DSA key error = %v", err)
+	}
+	// A key off the curve would leak the secret of the ephemeral key
+	offCurve := "04" + strings.Repeat("01", 64)
+	if _, err := messaging.Seal([]byte("hello"), "", offCurve, crypto.KeyTypeSecp256k1); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Seal() to a point off secp256k1 error = %v", err)
+	}
+}
+
+func TestInbox(t *testing.T) {
+	ctx := context.Background()
+	node := transactiontest.NewMockNode(testBlockchainId)
+	t.Cleanup(node.Close)
+	sender, senderWallet := node.NewSession(t, crypto.KeyTypeSecp256k1, "")
+	receiver, recipient := node.NewSession(t, crypto.KeyTypeED25519, "")
+	recipientKey := recipient.GetKey().GetPublicKeyHex(false)
+
+	transport := exchange.NewMemoryTransport()
+	large := bytes.Repeat([]byte("a"), 256)
+	opts := messaging.SendOptions{Transport: transport, MaxInlineSize: 128}
+	inline, err := messaging.Send(ctx, sender, testBlockchainId, recipientKey, crypto.KeyTypeED25519, []byte("ping"), opts)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if envelope, ok := messaging.ParseEnvelope(inline.Payload); !ok || envelope.Sealed == "" || inline.To != recipient.Address {
+		t.Fatalf("Send() = %s to %s, want an inline message to %s", inline.Payload, inline.To, recipient.Address)
+	}
+	pointer, err := messaging.Send(ctx, sender, testBlockchainId, recipientKey, crypto.KeyTypeED25519, large, opts)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if envelope, ok := messaging.ParseEnvelope(pointer.Payload); !ok || envelope.Pointer == "" {
+		t.Fatalf("Send() of a large message = %s, want a pointer", pointer.Payload)
+	}
+	// Plain data to the recipient is not a message
+	if _, err := sender.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: recipient.Address, Payload: "plain", PayloadType: transaction.TX_DATA.String()}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	// A copied message does not open for its new sender
+	copied, err := receiver.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: recipient.Address, Payload: inline.Payload, PayloadType: transaction.TX_DATA.String()})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	inbox, err := messaging.NewInbox(receiver, recipient)
+	if err != nil {
+		t.Fatalf("NewInbox() error = %v", err)
+	}
+	if _, err := inbox.Read(ctx, pointer); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Read() of a pointer without a transport error = %v", err)
+	}
+	if _, err := inbox.Read(ctx, copied); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Read() of a copied message error = %v", err)
+	}
+	inbox.Transport = transport
+	unreadable := []error{}
+	inbox.OnError = func(err error) { unreadable = append(unreadable, err) }
+	messages, err := inbox.Scan(ctx, testBlockchainId, transaction.ListOptions{})
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if len(messages) != 2 || string(messages[0].Body) != "ping" || !bytes.Equal(messages[1].Body, large) || messages[0].From != senderWallet.Address {
+		t.Fatalf("Scan() = %+v, want the two messages of %s", messages, senderWallet.Address)
+	}
+	if len(unreadable) != 1 {
+		t.Fatalf("Scan() reported %v, want the copied message only", unreadable)
+	}
+
+	// The sender cannot read what it sent to someone else
+	senderInbox, err := messaging.NewInbox(sender, senderWallet)
+	if err != nil {
+		t.Fatalf("NewInbox() error = %v", err)
+	}
+	if messages, _ := senderInbox.Scan(ctx, testBlockchainId, transaction.ListOptions{}); len(messages) != 0 {
+		t.Fatalf("Scan() of the sender = %d messages", len(messages))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	stream, err := inbox.Subscribe(ctx, testBlockchainId, pointer.BlockHeight+1, messaging.SubscribeOptions{PollInterval: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	sent, err := messaging.Send(ctx, sender, testBlockchainId, recipientKey, crypto.KeyTypeED25519, []byte("pong"), messaging.SendOptions{})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	select {
+	case message := <-stream:
+		if message.TransactionId != sent.TransactionId || string(message.Body) != "pong" {
+			data, _ := json.Marshal(message)
+			t.Fatalf("Subscribe() delivered %s, want %s", data, sent.TransactionId)
+		}
+	case <-ctx.Done():
+		t.Fatal("Subscribe() delivered no message")
+	}
+}
@@ -0,0 +1,128 @@
+package migration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func register(t *testing.T, node *transactiontest.MockNode, keyType crypto.KeyType, parent string) wallet.UL_Wallet {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", keyType, parent, map[string]wallet.UL_AuthPermission{"data": {Create: true}}, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	input, _ := transaction.RegisterWalletInput(testBlockchainId, w)
+	if _, err := session.GenerateTransaction(input); err != nil {
+		t.Fatalf("CREATE_WALLET error = %v", err)
+	}
+	// Wallet files do not keep the metadata, the planner has to take it from the chain
+	w.Parent, w.AuthGroups = "", nil
+	return w
+}
+
+func TestMigration(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+
+	admin := register(t, node, crypto.KeyTypeSecp256k1, "")
+	adminSession, _ := transaction.NewUL_TransactionSession(node.URL(), admin)
+	fleet := []wallet.UL_Wallet{
+		register(t, node, crypto.KeyTypeSecp256k1, admin.Address),
+		register(t, node, crypto.KeyTypeED25519, admin.Address),
+		register(t, node, crypto.KeyTypeSecp256k1, admin.Address),
+		register(t, node, crypto.KeyTypeMlDSA87, admin.Address),
+	}
+	unregistered, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+
+	report, err := Scan(ctx, &adminSession, testBlockchainId, append(fleet, unregistered))
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	// The admin is registered but its key is not part of the fleet
+	if report.Total != 5 || report.PostQuantum != 1 || report.Unregistered != 1 || len(report.Vulnerable) != 4 || report.ByKeyType["secp256k1"] != 3 {
+		t.Fatalf("Scan() = %+v", report)
+	}
+
+	dir := t.TempDir()
+	config := Config{
+		Endpoint:     "http://127.0.0.1:1",
+		BlockchainId: testBlockchainId,
+		SuccessorDir: filepath.Join(dir, "successors"),
+		Passphrase:   "rotate",
+		Scrypt:       wallet.ScryptParams{N: 1 << 10, R: 8, P: 1},
+		StatePath:    filepath.Join(dir, "state.json"),
+		BatchSize:    2,
+	}
+	// An unreachable node stops the batch after the successors are generated
+	planner, err := NewPlanner(&adminSession, config)
+	if err != nil {
+		t.Fatalf("NewPlanner() error = %v", err)
+	}
+	if err := planner.Run(ctx, fleet, nil); err == nil {
+		t.Fatal("Run() against an unreachable node succeeded")
+	}
+	interrupted := planner.Entries()
+	if len(interrupted) != 2 || interrupted[0].Phase != PHASE_GENERATED || interrupted[0].Error == "" || interrupted[0].Successor == "" {
+		t.Fatalf("entries after the interrupted run = %+v", interrupted)
+	}
+	// Successors are saved encrypted, their keys are only readable with the passphrase
+	if _, err := wallet.LoadFromFile(interrupted[0].SuccessorFile, ""); err == nil {
+		t.Fatal("LoadFromFile() of a successor without the passphrase succeeded")
+	}
+
+	// A new planner resumes from the saved state and keeps the successors already generated
+	config.Endpoint = node.URL()
+	planner, err = NewPlanner(&adminSession, config)
+	if err != nil {
+		t.Fatalf("NewPlanner() error = %v", err)
+	}
+	phases := map[Phase]int{}
+	if err := planner.Run(ctx, fleet, func(p Progress) { phases[p.Phase]++ }); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if phases[PHASE_PENDING] != 0 || phases[PHASE_GENERATED] != 2 || phases[PHASE_REGISTERED] != 2 {
+		t.Fatalf("progress per phase = %v", phases)
+	}
+	for i, entry := range planner.Entries() {
+		if entry.Phase != PHASE_RETIRED || entry.Successor != interrupted[i].Successor || entry.Error != "" {
+			t.Fatalf("entry after resuming = %+v", entry)
+		}
+	}
+
+	if err := planner.Run(ctx, fleet, nil); err != nil {
+		t.Fatalf("Run() of the second batch error = %v", err)
+	}
+	entries := planner.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("entries = %+v, want the ML-DSA-87 wallet left out", entries)
+	}
+	wallets, _ := adminSession.ListWallets(testBlockchainId, "", transaction.ListOptions{}).Collect(ctx)
+	registered := map[string]transaction.ULWalletInfo{}
+	for _, info := range wallets {
+		registered[info.Address] = info
+	}
+	for _, entry := range entries {
+		successor := registered[entry.Successor]
+		if registered[entry.Address].Enabled || !successor.Enabled || successor.KeyType != crypto.KeyTypeMlDSA87 || successor.Parent != admin.Address || !successor.AuthGroups["data"].Create {
+			t.Fatalf("after migrating %s the chain has %+v and successor %+v", entry.Address, registered[entry.Address], successor)
+		}
+	}
+
+	report, _ = Scan(ctx, &adminSession, testBlockchainId, fleet)
+	if len(report.Vulnerable) != 1 || report.Vulnerable[0].Address != admin.Address || report.Vulnerable[0].HasKey {
+		t.Fatalf("vulnerable after the migration = %+v, want only the admin", report.Vulnerable)
+	}
+}
@@ -0,0 +1,329 @@
+package migration
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Phase is how far the migration of one wallet got, phases only move forward
+type Phase string
+
+const (
+	PHASE_PENDING Phase = "pending"
+	// PHASE_GENERATED wallets have a successor saved in the successor directory
+	PHASE_GENERATED Phase = "generated"
+	// PHASE_REGISTERED wallets have their successor registered on chain
+	PHASE_REGISTERED Phase = "registered"
+	// PHASE_RETIRED wallets are disabled on chain, their migration is complete
+	PHASE_RETIRED Phase = "retired"
+)
+
+// Entry tracks the migration of one wallet
+type Entry struct {
+	Address       string         `json:"address"`
+	KeyType       crypto.KeyType `json:"keyType"`
+	Phase         Phase          `json:"phase"`
+	Successor     string         `json:"successor,omitempty"`
+	SuccessorFile string         `json:"successorFile,omitempty"`
+	RegisteredTx  string         `json:"registeredTx,omitempty"`
+	RetiredTx     string         `json:"retiredTx,omitempty"`
+	// Error is the last failure, the entry is retried from its phase on the next run
+	Error     string    `json:"error,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Progress is reported after each wallet of a phase
+type Progress struct {
+	Phase Phase
+	Done  int
+	Total int
+	Entry Entry
+}
+
+type Config struct {
+	// Endpoint is the node the wallets of the fleet sign their rotation transactions through
+	Endpoint     string
+	BlockchainId string
+	// SuccessorDir receives the successor wallets with their private key and mnemonic before they are
+	// registered, so a crash never loses a key the chain already knows. Protect it like the fleet's keys.
+	SuccessorDir string
+	// Passphrase encrypts the successor wallet files and protects the seed of their mnemonics
+	Passphrase string
+	// Scrypt is the cost of the encryption, the zero value uses wallet.DEFAULT_SCRYPT_PARAMS
+	Scrypt wallet.ScryptParams
+	// StatePath persists the entries, a run resumes from it when it exists. Empty keeps them in memory.
+	StatePath string
+	// BatchSize limits how many wallets a run migrates, zero migrates them all at once. Wallets a
+	// previous run left half way always count towards the batch first.
+	BatchSize int
+}
+
+// Planner migrates the fleet in three phases: every successor of the batch is generated and saved,
+// then registered, and only then are the previous wallets disabled. Each step is checkpointed.
+type Planner struct {
+	session *transaction.UL_TransactionSession
+	config  Config
+
+	mu      sync.Mutex
+	entries map[string]*Entry
+}
+
+// NewPlanner creates a planner listing wallets through session, loading the state of earlier runs
+func NewPlanner(session *transaction.UL_TransactionSession, config Config) (*Planner, error) {
+	if config.Endpoint == "" || config.BlockchainId == "" || config.SuccessorDir == "" || config.Passphrase == "" {
+		return nil, fmt.Errorf("an endpoint, a blockchain id, a successor directory and a passphrase are required")
+	}
+	planner := &Planner{session: session, config: config, entries: make(map[string]*Entry)}
+	if config.StatePath == "" {
+		return planner, nil
+	}
+	data, err := os.ReadFile(config.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return planner, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migration state: %w", err)
+	}
+	entries := []*Entry{}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid migration state %s: %w", config.StatePath, err)
+	}
+	for _, entry := range entries {
+		planner.entries[entry.Address] = entry
+	}
+	return planner, nil
+}
+
+// Entries returns the migration state of every wallet seen so far, ordered by address
+func (p *Planner) Entries() []Entry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entries := make([]Entry, 0, len(p.entries))
+	for _, entry := range p.entries {
+		entries = append(entries, *entry)
+	}
+	slices.SortFunc(entries, func(a, b Entry) int { return strings.Compare(a.Address, b.Address) })
+	return entries
+}
+
+// Run migrates the next batch of the fleet's enabled wallets on classical keys. Failures do not stop
+// the other wallets, they are recorded in the entries and joined in the returned error. Run again to
+// retry them or to continue with the next batch.
+func (p *Planner) Run(ctx context.Context, fleet []wallet.UL_Wallet, progress func(Progress)) error {
+	registered, err := listWallets(ctx, p.session, p.config.BlockchainId)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(p.config.SuccessorDir, 0700); err != nil {
+		return fmt.Errorf("unable to create the successor directory: %w", err)
+	}
+	batch := p.batch(fleet, registered)
+
+	errs := []error{}
+	phases := []struct {
+		from Phase
+		step func(*member, map[string]transaction.ULWalletInfo) error
+	}{
+		{PHASE_PENDING, p.generate},
+		{PHASE_GENERATED, p.register},
+		{PHASE_REGISTERED, p.retire},
+	}
+	for _, phase := range phases {
+		members := []*member{}
+		for _, m := range batch {
+			if m.entry.Phase == phase.from {
+				members = append(members, m)
+			}
+		}
+		for i, m := range members {
+			if err := ctx.Err(); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			err := phase.step(m, registered)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("wallet %s: %w", m.entry.Address, err))
+			}
+			if err := p.checkpoint(m.entry, err); err != nil {
+				return errors.Join(append(errs, err)...)
+			}
+			if progress != nil {
+				progress(Progress{Phase: phase.from, Done: i + 1, Total: len(members), Entry: *m.entry})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// member is a wallet of the current batch, previous carries the parent and auth groups registered
+// on chain, which wallet files do not keep
+type member struct {
+	entry    *Entry
+	previous wallet.UL_Wallet
+}
+
+func (p *Planner) batch(fleet []wallet.UL_Wallet, registered map[string]transaction.ULWalletInfo) []*member {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	batch := []*member{}
+	for _, w := range fleet {
+		info, ok := registered[w.Address]
+		entry := p.entries[w.Address]
+		started := entry != nil && entry.Phase != PHASE_PENDING
+		if !ok || isPostQuantum(w.GetKey().GetType()) || (entry != nil && entry.Phase == PHASE_RETIRED) || (!info.Enabled && !started) {
+			continue
+		}
+		if entry == nil {
+			entry = &Entry{Address: w.Address, KeyType: w.GetKey().GetType(), Phase: PHASE_PENDING}
+		}
+		w.Parent, w.AuthGroups, w.Enabled = info.Parent, info.AuthGroups, info.Enabled
+		batch = append(batch, &member{entry: entry, previous: w})
+	}
+	// Finish what an earlier run started before taking on new wallets
+	slices.SortFunc(batch, func(a, b *member) int {
+		aStarted, bStarted := a.entry.Phase != PHASE_PENDING, b.entry.Phase != PHASE_PENDING
+		if aStarted != bStarted {
+			if aStarted {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.entry.Address, b.entry.Address)
+	})
+	if p.config.BatchSize > 0 && len(batch) > p.config.BatchSize {
+		batch = batch[:p.config.BatchSize]
+	}
+	for _, m := range batch {
+		p.entries[m.entry.Address] = m.entry
+	}
+	return batch
+}
+
+func (p *Planner) generate(m *member, _ map[string]transaction.ULWalletInfo) error {
+	successor, mnemonic, err := wallet.ConvertKeyType(m.previous, p.config.Passphrase, TARGET_KEY_TYPE)
+	if err != nil {
+		return err
+	}
+	params := []wallet.ScryptParams{}
+	if p.config.Scrypt != (wallet.ScryptParams{}) {
+		params = append(params, p.config.Scrypt)
+	}
+	path := filepath.Join(p.config.SuccessorDir, successor.Address+".ukey")
+	if err := successor.SaveToFileEncrypted(path, mnemonic, true, p.config.Passphrase, params...); err != nil {
+		return err
+	}
+	m.entry.Successor, m.entry.SuccessorFile, m.entry.Phase = successor.Address, path, PHASE_GENERATED
+	return nil
+}
+
+func (p *Planner) register(m *member, registered map[string]transaction.ULWalletInfo) error {
+	// A run interrupted after submitting may not have recorded the registration
+	if _, ok := registered[m.entry.Successor]; ok {
+		m.entry.Phase = PHASE_REGISTERED
+		return nil
+	}
+	successor, err := p.successor(m)
+	if err != nil {
+		return err
+	}
+	input, err := transaction.RegisterWalletInput(p.config.BlockchainId, successor)
+	if err != nil {
+		return err
+	}
+	tx, err := p.submit(successor, input)
+	if err != nil {
+		return err
+	}
+	m.entry.RegisteredTx, m.entry.Phase = tx.TransactionId, PHASE_REGISTERED
+	return nil
+}
+
+func (p *Planner) retire(m *member, registered map[string]transaction.ULWalletInfo) error {
+	if info, ok := registered[m.entry.Address]; ok && !info.Enabled {
+		m.entry.Phase = PHASE_RETIRED
+		return nil
+	}
+	input, err := transaction.RetireWalletInput(p.config.BlockchainId, m.previous)
+	if err != nil {
+		return err
+	}
+	tx, err := p.submit(m.previous, input)
+	if err != nil {
+		return err
+	}
+	m.entry.RetiredTx, m.entry.Phase = tx.TransactionId, PHASE_RETIRED
+	return nil
+}
+
+// successor loads the saved successor of a member with the metadata of the wallet it replaces
+func (p *Planner) successor(m *member) (wallet.UL_Wallet, error) {
+	successor, err := wallet.LoadFromFile(m.entry.SuccessorFile, p.config.Passphrase)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	if successor.Address != m.entry.Successor {
+		return wallet.UL_Wallet{}, fmt.Errorf("%s holds %s, not the successor %s", m.entry.SuccessorFile, successor.Address, m.entry.Successor)
+	}
+	successor.Parent, successor.AuthGroups, successor.Enabled = m.previous.Parent, m.previous.AuthGroups, true
+	return successor, nil
+}
+
+func (p *Planner) submit(signer wallet.UL_Wallet, input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	session, err := transaction.NewUL_TransactionSession(p.config.Endpoint, signer)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	tx, err := session.GenerateTransaction(input)
+	if err != nil {
+		return tx, err
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		return tx, fmt.Errorf("%s %s failed with %s", input.PayloadType, tx.TransactionId, tx.Output)
+	}
+	return tx, nil
+}
+
+// checkpoint records the outcome of a step and persists the state
+func (p *Planner) checkpoint(entry *Entry, stepErr error) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	entry.Error = ""
+	if stepErr != nil {
+		entry.Error = stepErr.Error()
+	}
+	entry.UpdatedAt = time.Now().UTC()
+	if p.config.StatePath == "" {
+		return nil
+	}
+
+	entries := make([]*Entry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, e)
+	}
+	slices.SortFunc(entries, func(a, b *Entry) int { return strings.Compare(a.Address, b.Address) })
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Write then rename so a crash never leaves a truncated file behind
+	tmp := p.config.StatePath + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("unable to write migration state: %w", err)
+	}
+	if err := os.Rename(tmp, p.config.StatePath); err != nil {
+		return fmt.Errorf("unable to write migration state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package migration moves a fleet of wallets to post-quantum keys. Scan reports the wallets still
+// using classical key types, a Planner generates their ML-DSA-87 successors and rotates them on chain
+// in phases, checkpointing every step so an interrupted migration resumes where it stopped.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// TARGET_KEY_TYPE is the key type successors are generated with
+const TARGET_KEY_TYPE = crypto.KeyTypeMlDSA87
+
+// Finding is a wallet of the fleet with a key type that is not post-quantum
+type Finding struct {
+	Address string         `json:"address"`
+	KeyType crypto.KeyType `json:"keyType"`
+	// Enabled is the state of the wallet on chain
+	Enabled bool `json:"enabled"`
+	// HasKey is set when the fleet holds the private key, only those wallets can be migrated
+	HasKey bool `json:"hasKey"`
+}
+
+// Report summarizes the post-quantum readiness of a fleet
+type Report struct {
+	ScannedAt    time.Time `json:"scannedAt"`
+	BlockchainId string    `json:"blockchainId"`
+	Total        int       `json:"total"`
+	PostQuantum  int       `json:"postQuantum"`
+	Disabled     int       `json:"disabled"`
+	// Unregistered counts local wallets the chain does not know, they need no rotation
+	Unregistered int            `json:"unregistered"`
+	ByKeyType    map[string]int `json:"byKeyType"`
+	Vulnerable   []Finding      `json:"vulnerable"`
+}
+
+// Ready reports whether no enabled wallet is left on a classical key
+func (r Report) Ready() bool {
+	return len(r.Vulnerable) == 0
+}
+
+// Scan lists the wallets registered on blockchainId together with the local fleet and reports the
+// enabled ones whose key type is not post-quantum. Disabled wallets cannot sign and are only counted.
+func Scan(ctx context.Context, session *transaction.UL_TransactionSession, blockchainId string, fleet []wallet.UL_Wallet) (Report, error) {
+	report := Report{
+		ScannedAt:    time.Now().UTC(),
+		BlockchainId: blockchainId,
+		ByKeyType:    make(map[string]int),
+		Vulnerable:   []Finding{},
+	}
+	registered, err := listWallets(ctx, session, blockchainId)
+	if err != nil {
+		return Report{}, err
+	}
+
+	local := make(map[string]bool, len(fleet))
+	findings := []Finding{}
+	for _, w := range fleet {
+		local[w.Address] = true
+		info, ok := registered[w.Address]
+		if !ok {
+			report.Unregistered++
+			continue
+		}
+		findings = append(findings, Finding{Address: w.Address, KeyType: w.GetKey().GetType(), Enabled: info.Enabled, HasKey: true})
+	}
+	for address, info := range registered {
+		if !local[address] {
+			findings = append(findings, Finding{Address: address, KeyType: info.KeyType, Enabled: info.Enabled})
+		}
+	}
+
+	for _, finding := range findings {
+		report.Total++
+		report.ByKeyType[finding.KeyType.String()]++
+		switch {
+		case isPostQuantum(finding.KeyType):
+			report.PostQuantum++
+		case !finding.Enabled:
+			report.Disabled++
+		default:
+			report.Vulnerable = append(report.Vulnerable, finding)
+		}
+	}
+	slices.SortFunc(report.Vulnerable, func(a, b Finding) int { return strings.Compare(a.Address, b.Address) })
+	return report, nil
+}
+
+func listWallets(ctx context.Context, session *transaction.UL_TransactionSession, blockchainId string) (map[string]transaction.ULWalletInfo, error) {
+	registered := make(map[string]transaction.ULWalletInfo)
+	it := session.ListWallets(blockchainId, "", transaction.ListOptions{})
+	for info := range it.All(ctx) {
+		registered[info.Address] = info
+	}
+	if err := it.Err(); err != nil {
+		return nil, fmt.Errorf("unable to list wallets: %w", err)
+	}
+	return registered, nil
+}
+
+func isPostQuantum(keyType crypto.KeyType) bool {
+	capabilities, err := crypto.Capabilities(keyType)
+	return err == nil && capabilities.PostQuantum
+}
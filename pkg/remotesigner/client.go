@@ -0,0 +1,247 @@
+package remotesigner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+const (
+	DEFAULT_TIMEOUT      = 10 * time.Second
+	DEFAULT_MAX_ATTEMPTS = 3
+	DEFAULT_BACKOFF      = 200 * time.Millisecond
+)
+
+type Config struct {
+	// Endpoint is the base URL of the signing service, e.g. https://signer.internal:8443
+	Endpoint string
+	KeyId    string
+	// TLS configures the connection, see LoadTLSConfig. Nil uses the system roots without a client certificate.
+	TLS *tls.Config
+	// Token is sent as a bearer token when set
+	Token string
+	// Timeout bounds every attempt, defaults to DEFAULT_TIMEOUT
+	Timeout time.Duration
+	// MaxAttempts defaults to DEFAULT_MAX_ATTEMPTS. Signing the same data twice is harmless, so sign
+	// requests are retried like any other.
+	MaxAttempts int
+	// Backoff is doubled after every failed attempt, defaults to DEFAULT_BACKOFF
+	Backoff time.Duration
+}
+
+// LoadTLSConfig trusts the PEM certificates of caFile and, when certFile and keyFile are given,
+// presents that client certificate for mutual TLS. An empty caFile keeps the system roots.
+func LoadTLSConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the CA file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("no certificate found in %s", caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// RemoteKey is a crypto.ULKey whose private key stays in the signing service. Wrap it with
+// wallet.FromKey to sign transactions with it. Only the public key operations run locally.
+type RemoteKey struct {
+	config     Config
+	httpClient *http.Client
+	info       KeyInfo
+	// public verifies signatures locally
+	public crypto.ULKey
+}
+
+// Connect fetches the public key of config.KeyId from the signing service
+func Connect(ctx context.Context, config Config) (*RemoteKey, error) {
+	if config.Endpoint == "" || config.KeyId == "" {
+		return nil, fmt.Errorf("the signer endpoint and key id are required")
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	if config.Timeout <= 0 {
+		config.Timeout = DEFAULT_TIMEOUT
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = DEFAULT_MAX_ATTEMPTS
+	}
+	if config.Backoff <= 0 {
+		config.Backoff = DEFAULT_BACKOFF
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config.TLS
+	key := &RemoteKey{
+		config:     config,
+		httpClient: &http.Client{Timeout: config.Timeout, Transport: transport},
+	}
+
+	if err := key.call(ctx, http.MethodGet, KEYS_PATH+url.PathEscape(config.KeyId), nil, &key.info); err != nil {
+		return nil, err
+	}
+	if key.info.KeyId != config.KeyId {
+		return nil, fmt.Errorf("the signer answered for key %q instead of %q", key.info.KeyId, config.KeyId)
+	}
+	public, err := crypto.GetKeyByType(key.info.KeyType, crypto.GetHasherByType(key.info.KeyType))
+	if err != nil {
+		return nil, err
+	}
+	if err := public.GeneratePublicKeyFromHex(false, key.info.PublicKey); err != nil {
+		return nil, fmt.Errorf("the signer returned an invalid public key: %w", err)
+	}
+	key.public = public
+	return key, nil
+}
+
+// Info returns the key as described by the signer
+func (key *RemoteKey) Info() KeyInfo {
+	return key.info
+}
+
+func (key *RemoteKey) SignData(data []byte) ([]byte, error) {
+	return key.SignDataContext(context.Background(), data)
+}
+
+// SignDataContext signs data with the remote key, the signature is verified before it is returned
+func (key *RemoteKey) SignDataContext(ctx context.Context, data []byte) ([]byte, error) {
+	response := SignResponse{}
+	request := SignRequest{KeyId: key.config.KeyId, Data: crypto.BytesToHex(data)}
+	if err := key.call(ctx, http.MethodPost, SIGN_PATH, request, &response); err != nil {
+		return nil, err
+	}
+	signature, err := crypto.HexToBytes(response.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("the signer returned an invalid signature: %w", err)
+	}
+	// A signature for other data or another key would only be caught by the node
+	if ok, err := key.public.VerifySignature(data, signature); err != nil || !ok {
+		return nil, fmt.Errorf("the signer returned a signature that does not verify for key %s", key.config.KeyId)
+	}
+	return signature, nil
+}
+
+func (key *RemoteKey) VerifySignature(message []byte, signature []byte) (bool, error) {
+	return key.public.VerifySignature(message, signature)
+}
+
+func (key *RemoteKey) GetPublicKeyHex(compressed bool) string {
+	return key.public.GetPublicKeyHex(compressed)
+}
+
+// GetPrivateKeyHex is always empty, the private key never leaves the signer
+func (key *RemoteKey) GetPrivateKeyHex() string {
+	return ""
+}
+
+func (key *RemoteKey) GetType() crypto.KeyType {
+	return key.info.KeyType
+}
+
+func (key *RemoteKey) GeneratePublicKeyFromHex(compressed bool, hex string) error {
+	return &ErrRemoteKey{Msg: "the public key is provided by the signer"}
+}
+
+func (key *RemoteKey) GeneratePrivateKeyFromHex(hex string) error {
+	return &ErrRemoteKey{Msg: "the private key is held by the signer"}
+}
+
+func (key *RemoteKey) GenerateKeyFromSeed(seed []byte) error {
+	return &ErrRemoteKey{Msg: "the private key is held by the signer"}
+}
+
+func (key *RemoteKey) RegenerateKeyFromSeed(seed []byte, salt []byte) error {
+	return &ErrRemoteKey{Msg: "the private key is held by the signer"}
+}
+
+// call sends a request, retrying connection failures, 429 and 5xx answers with a doubling backoff
+func (key *RemoteKey) call(ctx context.Context, method string, path string, body any, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	backoff := key.config.Backoff
+	for attempt := 1; ; attempt++ {
+		err := key.callOnce(ctx, method, path, payload, out)
+		if err == nil || attempt >= key.config.MaxAttempts || ctx.Err() != nil || !isRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (key *RemoteKey) callOnce(ctx context.Context, method string, path string, payload []byte, out any) error {
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, key.config.Endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if key.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+key.config.Token)
+	}
+
+	resp, err := key.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach the remote signer: %w", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("unable to read the remote signer response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		failure := errorResponse{}
+		if json.Unmarshal(respBody, &failure) != nil || failure.Error == "" {
+			failure.Error = strings.TrimSpace(string(respBody))
+		}
+		return &ErrSigner{StatusCode: resp.StatusCode, Msg: failure.Error}
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("invalid remote signer response: %w", err)
+	}
+	return nil
+}
+
+func isRetryable(err error) bool {
+	var signerErr *ErrSigner
+	if errors.As(err, &signerErr) {
+		return signerErr.StatusCode == http.StatusTooManyRequests || signerErr.StatusCode >= 500
+	}
+	// A certificate the client does not trust will not be trusted on a retry either
+	var certErr *tls.CertificateVerificationError
+	return !errors.As(err, &certErr)
+}
@@ -0,0 +1,76 @@
+// Package remotesigner signs with keys held by a remote signing service, so private keys can live in
+// a single hardened service while applications use wallets and sessions as usual.
+//
+// The protocol is JSON over HTTPS, mutual TLS is recommended to authenticate the callers:
+//
+//	GET  /v1/keys/{keyId}  returns the KeyInfo of a key
+//	POST /v1/sign          signs the Data of a SignRequest and returns a SignResponse
+//
+// Connect returns a RemoteKey, a crypto.ULKey that forwards SignData to the service, and Server
+// implements the service side for the keys it is given.
+package remotesigner
+
+import (
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	KEYS_PATH = "/v1/keys/"
+	SIGN_PATH = "/v1/sign"
+	// MAX_SIGN_DATA_SIZE bounds the data of a sign request, transactions only send their commitment
+	MAX_SIGN_DATA_SIZE = 4096
+)
+
+// KeyInfo describes a key held by the signer
+type KeyInfo struct {
+	KeyId     string         `json:"keyId"`
+	KeyType   crypto.KeyType `json:"keyType"`
+	PublicKey string         `json:"publicKey"`
+}
+
+// SignRequest asks the signer to sign Data, the hex encoded bytes of a commitment, with KeyId
+type SignRequest struct {
+	KeyId string `json:"keyId"`
+	Data  string `json:"data"`
+}
+
+// SignResponse carries the raw signature, hex encoded
+type SignResponse struct {
+	KeyId     string         `json:"keyId"`
+	KeyType   crypto.KeyType `json:"keyType"`
+	Signature string         `json:"signature"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ErrSigner is a failure reported by the signing service
+type ErrSigner struct {
+	StatusCode int
+	Msg        string
+}
+
+func (e *ErrSigner) Error() string {
+	return fmt.Sprintf("remote signer returned status %d: %s", e.StatusCode, e.Msg)
+}
+
+func (e *ErrSigner) Is(target error) bool {
+	return target != nil && target == utils.StatusCategory(e.StatusCode)
+}
+
+// ErrRemoteKey is returned by the ULKey methods a remote key cannot support
+type ErrRemoteKey struct {
+	Msg string
+}
+
+func (e *ErrRemoteKey) Error() string {
+	return fmt.Sprintf("unsupported on a remote key, %s", e.Msg)
+}
+
+func (e *ErrRemoteKey) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
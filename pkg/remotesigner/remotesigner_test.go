@@ -0,0 +1,143 @@
+package remotesigner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+// clientCertificate issues a self-signed client certificate for commonName
+func clientCertificate(t *testing.T, commonName string) (tls.Certificate, *x509.Certificate) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, leaf
+}
+
+func TestRemoteSigner(t *testing.T) {
+	signer, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	app, appLeaf := clientCertificate(t, "app")
+	other, otherLeaf := clientCertificate(t, "other")
+
+	server := NewServer(map[string]crypto.ULKey{"app-key": signer.GetKey()}, func(r *http.Request, keyId string) error {
+		if name := r.TLS.PeerCertificates[0].Subject.CommonName; name != "app" {
+			return fmt.Errorf("%s may not use %s", name, keyId)
+		}
+		return nil
+	})
+	// The first sign request fails like an overloaded service would
+	var signRequests atomic.Int32
+	flaky := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == SIGN_PATH && signRequests.Add(1) == 1 {
+			writeJson(w, http.StatusServiceUnavailable, errorResponse{Error: "overloaded"})
+			return
+		}
+		server.ServeHTTP(w, r)
+	})
+	service := httptest.NewUnstartedServer(flaky)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(appLeaf)
+	clientCAs.AddCert(otherLeaf)
+	service.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	service.StartTLS()
+	defer service.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(service.Certificate())
+	config := Config{
+		Endpoint: service.URL,
+		KeyId:    "app-key",
+		TLS:      &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{app}},
+		Backoff:  time.Millisecond,
+	}
+	ctx := context.Background()
+	remote, err := Connect(ctx, config)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if remote.GetPublicKeyHex(false) != signer.GetKey().GetPublicKeyHex(false) || remote.GetPrivateKeyHex() != "" {
+		t.Fatalf("Connect() = %+v", remote.Info())
+	}
+
+	// Transactions signed remotely verify against the key of the signer
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	w := wallet.FromKey(remote)
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           w.Address,
+		Payload:      "signed remotely",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if tx.From != signer.Address || signRequests.Load() != 2 {
+		t.Fatalf("transaction from %s after %d sign requests", tx.From, signRequests.Load())
+	}
+	if decoded := transaction.DecodeTransaction(tx, signer.GetKey().GetPublicKeyHex(false)); !decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() signature = %+v", decoded.Signature)
+	}
+
+	var signerErr *ErrSigner
+	unknown := config
+	unknown.KeyId = "missing"
+	if _, err := Connect(ctx, unknown); !errors.As(err, &signerErr) || signerErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("Connect() to an unknown key error = %v", err)
+	}
+	forbidden := config
+	forbidden.TLS = &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{other}}
+	if _, err := Connect(ctx, forbidden); !errors.As(err, &signerErr) || signerErr.StatusCode != http.StatusForbidden {
+		t.Fatalf("Connect() with another client certificate error = %v", err)
+	}
+	anonymous := config
+	anonymous.TLS = &tls.Config{RootCAs: roots}
+	if _, err := Connect(ctx, anonymous); err == nil {
+		t.Fatal("Connect() without a client certificate succeeded")
+	}
+	var remoteErr *ErrRemoteKey
+	if err := remote.GeneratePrivateKeyFromHex("00"); !errors.As(err, &remoteErr) {
+		t.Fatalf("GeneratePrivateKeyFromHex() error = %v", err)
+	}
+}
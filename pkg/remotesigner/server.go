@@ -0,0 +1,87 @@
+package remotesigner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// Authorizer decides whether the caller of r may use keyId. With mutual TLS the caller is identified
+// by r.TLS.PeerCertificates. A returned error is sent back with a 403.
+type Authorizer func(r *http.Request, keyId string) error
+
+// Server is the signing service side of the protocol, it signs with the keys it is given by id
+type Server struct {
+	keys      map[string]crypto.ULKey
+	authorize Authorizer
+	mux       *http.ServeMux
+}
+
+// NewServer serves keys by id, authorize may be nil when TLS client authentication is enough
+func NewServer(keys map[string]crypto.ULKey, authorize Authorizer) *Server {
+	server := &Server{keys: keys, authorize: authorize, mux: http.NewServeMux()}
+	server.mux.HandleFunc("GET "+KEYS_PATH+"{keyId}", server.handleKey)
+	server.mux.HandleFunc("POST "+SIGN_PATH, server.handleSign)
+	return server
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// key returns the key the caller asked for, or writes the failure
+func (s *Server) key(w http.ResponseWriter, r *http.Request, keyId string) (crypto.ULKey, bool) {
+	key, ok := s.keys[keyId]
+	if !ok {
+		writeJson(w, http.StatusNotFound, errorResponse{Error: fmt.Sprintf("unknown key %q", keyId)})
+		return nil, false
+	}
+	if s.authorize != nil {
+		if err := s.authorize(r, keyId); err != nil {
+			writeJson(w, http.StatusForbidden, errorResponse{Error: err.Error()})
+			return nil, false
+		}
+	}
+	return key, true
+}
+
+func (s *Server) handleKey(w http.ResponseWriter, r *http.Request) {
+	keyId := r.PathValue("keyId")
+	key, ok := s.key(w, r, keyId)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, KeyInfo{KeyId: keyId, KeyType: key.GetType(), PublicKey: key.GetPublicKeyHex(false)})
+}
+
+func (s *Server) handleSign(w http.ResponseWriter, r *http.Request) {
+	request := SignRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 2*MAX_SIGN_DATA_SIZE+1024)).Decode(&request); err != nil {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: utils.HandleJsonError(err)})
+		return
+	}
+	data, err := crypto.HexToBytes(request.Data)
+	if err != nil || len(data) == 0 || len(data) > MAX_SIGN_DATA_SIZE {
+		writeJson(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("data must be 1 to %d hex encoded bytes", MAX_SIGN_DATA_SIZE)})
+		return
+	}
+	key, ok := s.key(w, r, request.KeyId)
+	if !ok {
+		return
+	}
+	signature, err := key.SignData(data)
+	if err != nil {
+		writeJson(w, http.StatusInternalServerError, errorResponse{Error: err.Error()})
+		return
+	}
+	writeJson(w, http.StatusOK, SignResponse{KeyId: request.KeyId, KeyType: key.GetType(), Signature: crypto.BytesToHex(signature)})
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
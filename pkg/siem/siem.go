@@ -0,0 +1,230 @@
+// Package siem emits security events of sessions and wallets as structured log records for SIEM
+// ingestion. Records go through a log/slog handler, so they reach OpenTelemetry logs through an
+// slog bridge handler (such as otelslog) or any JSON handler a collector tails.
+//
+// Every record carries the same attribute names, following the OpenTelemetry event and ECS
+// conventions most SIEMs map out of the box:
+//
+//	event.name      uledger.<category>.<action>, e.g. uledger.key.loaded
+//	event.kind      always "event"
+//	event.category  the Category of the event
+//	event.action    what happened, e.g. "loaded" or "rejected"
+//	event.outcome   "success" or "failure"
+//	uledger.*       the wallet, key, chain and transaction the event is about
+//	error.message   the reason of a failure
+package siem
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Category groups events so each group can be toggled on its own
+type Category string
+
+const (
+	// CATEGORY_KEY covers keys loaded, reloaded or evicted by the process
+	CATEGORY_KEY Category = "key"
+	// CATEGORY_SIGNATURE covers every transaction signature produced by a session
+	CATEGORY_SIGNATURE Category = "signature"
+	// CATEGORY_AUTHORIZATION covers transactions the node refused to authorize
+	CATEGORY_AUTHORIZATION Category = "authorization"
+)
+
+const (
+	OUTCOME_SUCCESS = "success"
+	OUTCOME_FAILURE = "failure"
+)
+
+// Attribute names shared by every record
+const (
+	ATTR_EVENT_NAME         = "event.name"
+	ATTR_EVENT_KIND         = "event.kind"
+	ATTR_EVENT_CATEGORY     = "event.category"
+	ATTR_EVENT_ACTION       = "event.action"
+	ATTR_EVENT_OUTCOME      = "event.outcome"
+	ATTR_ERROR_MESSAGE      = "error.message"
+	ATTR_WALLET_ADDRESS     = "uledger.wallet.address"
+	ATTR_KEY_TYPE           = "uledger.key.type"
+	ATTR_KEY_SOURCE         = "uledger.key.source"
+	ATTR_BLOCKCHAIN_ID      = "uledger.blockchain.id"
+	ATTR_TRANSACTION_ID     = "uledger.transaction.id"
+	ATTR_PAYLOAD_TYPE       = "uledger.payload.type"
+	ATTR_PAYLOAD_ROOT       = "uledger.payload.root"
+	ATTR_SIGNATURE_ENCODING = "uledger.signature.encoding"
+	ATTR_DELEGATION_ID      = "uledger.delegation.id"
+	ATTR_OUTPUT             = "uledger.transaction.output"
+	ATTR_HTTP_STATUS        = "http.response.status_code"
+)
+
+// Emitter writes security events to a logger, categories can be switched at runtime
+type Emitter struct {
+	logger *slog.Logger
+
+	mu       sync.RWMutex
+	disabled map[Category]bool
+}
+
+// NewEmitter emits to logger, slog.Default when nil. Only the given categories are enabled, every
+// category is when none is given.
+func NewEmitter(logger *slog.Logger, categories ...Category) *Emitter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	emitter := &Emitter{logger: logger, disabled: make(map[Category]bool)}
+	if len(categories) > 0 {
+		for _, category := range []Category{CATEGORY_KEY, CATEGORY_SIGNATURE, CATEGORY_AUTHORIZATION} {
+			emitter.disabled[category] = true
+		}
+		for _, category := range categories {
+			delete(emitter.disabled, category)
+		}
+	}
+	return emitter
+}
+
+func (emitter *Emitter) Enable(category Category) {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+	delete(emitter.disabled, category)
+}
+
+func (emitter *Emitter) Disable(category Category) {
+	emitter.mu.Lock()
+	defer emitter.mu.Unlock()
+	emitter.disabled[category] = true
+}
+
+// Enabled reports whether events of category are emitted
+func (emitter *Emitter) Enabled(category Category) bool {
+	emitter.mu.RLock()
+	defer emitter.mu.RUnlock()
+	return !emitter.disabled[category]
+}
+
+// KeyLoaded records that the process loaded the key of w from source, such as a file path
+func (emitter *Emitter) KeyLoaded(w wallet.UL_Wallet, source string) {
+	emitter.emit(CATEGORY_KEY, "loaded", OUTCOME_SUCCESS, "key loaded",
+		slog.String(ATTR_WALLET_ADDRESS, w.Address),
+		slog.String(ATTR_KEY_TYPE, w.GetKey().GetType().String()),
+		slog.String(ATTR_KEY_SOURCE, source),
+	)
+}
+
+// Attach emits the signatures and the authorization rejections of the session, chaining the hooks
+// already installed
+func (emitter *Emitter) Attach(session *transaction.UL_TransactionSession) {
+	hooks := session.Hooks()
+	nextSign, nextSubmit, nextError := hooks.OnAfterSign, hooks.OnAfterSubmit, hooks.OnError
+	hooks.OnAfterSign = func(event transaction.AfterSignEvent) {
+		emitter.signatureProduced(event.Input)
+		if nextSign != nil {
+			nextSign(event)
+		}
+	}
+	hooks.OnAfterSubmit = func(event transaction.AfterSubmitEvent) {
+		if event.Transaction.Output == transaction.TX_REJECTED_BY_UNAUTHORIZED.String() {
+			emitter.rejected(event.Input, event.Transaction.TransactionId, event.Transaction.Output, 0)
+		}
+		if nextSubmit != nil {
+			nextSubmit(event)
+		}
+	}
+	hooks.OnError = func(event transaction.ErrorEvent) {
+		// The node refusing the request itself is an authorization failure as well
+		nodeErr := &transaction.NodeError{}
+		if errors.As(event.Err, &nodeErr) && (nodeErr.StatusCode == http.StatusUnauthorized || nodeErr.StatusCode == http.StatusForbidden) {
+			emitter.rejected(event.Input, "", "", nodeErr.StatusCode)
+		}
+		if nextError != nil {
+			nextError(event)
+		}
+	}
+	session.SetHooks(hooks)
+}
+
+// WatchHandler emits the key events of a wallet.DirectoryWatcher and passes them on to next, use it
+// as WatchOptions.OnEvent
+func (emitter *Emitter) WatchHandler(next func(wallet.WatchEvent)) func(wallet.WatchEvent) {
+	return func(event wallet.WatchEvent) {
+		attrs := []slog.Attr{
+			slog.String(ATTR_WALLET_ADDRESS, event.Address),
+			slog.String(ATTR_KEY_SOURCE, event.Path),
+		}
+		if event.Err != nil {
+			attrs = append(attrs, slog.String(ATTR_ERROR_MESSAGE, event.Err.Error()))
+		}
+		switch event.Type {
+		case wallet.WATCH_EVENT_LOADED:
+			emitter.emit(CATEGORY_KEY, "loaded", OUTCOME_SUCCESS, "key loaded", attrs...)
+		case wallet.WATCH_EVENT_RELOADED:
+			emitter.emit(CATEGORY_KEY, "reloaded", OUTCOME_SUCCESS, "key reloaded", attrs...)
+		case wallet.WATCH_EVENT_EVICTED:
+			emitter.emit(CATEGORY_KEY, "evicted", OUTCOME_SUCCESS, "key evicted", attrs...)
+		case wallet.WATCH_EVENT_INVALID, wallet.WATCH_EVENT_CONFLICT:
+			emitter.emit(CATEGORY_KEY, string(event.Type), OUTCOME_FAILURE, "key rejected", attrs...)
+		}
+		if next != nil {
+			next(event)
+		}
+	}
+}
+
+func (emitter *Emitter) signatureProduced(input transaction.ULTransactionInput) {
+	attrs := append(transactionAttrs(input),
+		slog.String(ATTR_PAYLOAD_ROOT, input.PayloadRoot),
+		slog.String(ATTR_SIGNATURE_ENCODING, string(input.SignatureEncoding)),
+	)
+	emitter.emit(CATEGORY_SIGNATURE, "produced", OUTCOME_SUCCESS, "transaction signed", attrs...)
+}
+
+func (emitter *Emitter) rejected(input transaction.ULTransactionInput, transactionId string, output string, status int) {
+	attrs := transactionAttrs(input)
+	if transactionId != "" {
+		attrs = append(attrs, slog.String(ATTR_TRANSACTION_ID, transactionId))
+	}
+	if output != "" {
+		attrs = append(attrs, slog.String(ATTR_OUTPUT, output))
+	}
+	if status != 0 {
+		attrs = append(attrs, slog.Int(ATTR_HTTP_STATUS, status))
+	}
+	emitter.emit(CATEGORY_AUTHORIZATION, "rejected", OUTCOME_FAILURE, "transaction rejected as unauthorized", attrs...)
+}
+
+func transactionAttrs(input transaction.ULTransactionInput) []slog.Attr {
+	attrs := []slog.Attr{
+		slog.String(ATTR_WALLET_ADDRESS, input.From),
+		slog.String(ATTR_KEY_TYPE, input.KeyType.String()),
+		slog.String(ATTR_BLOCKCHAIN_ID, input.BlockchainId),
+		slog.String(ATTR_PAYLOAD_TYPE, input.PayloadType),
+	}
+	if input.DelegationId != "" {
+		attrs = append(attrs, slog.String(ATTR_DELEGATION_ID, input.DelegationId))
+	}
+	return attrs
+}
+
+func (emitter *Emitter) emit(category Category, action string, outcome string, message string, attrs ...slog.Attr) {
+	if !emitter.Enabled(category) {
+		return
+	}
+	level := slog.LevelInfo
+	if outcome == OUTCOME_FAILURE {
+		level = slog.LevelWarn
+	}
+	attrs = append([]slog.Attr{
+		slog.String(ATTR_EVENT_NAME, "uledger."+string(category)+"."+action),
+		slog.String(ATTR_EVENT_KIND, "event"),
+		slog.String(ATTR_EVENT_CATEGORY, string(category)),
+		slog.String(ATTR_EVENT_ACTION, action),
+		slog.String(ATTR_EVENT_OUTCOME, outcome),
+	}, attrs...)
+	emitter.logger.LogAttrs(context.Background(), level, message, attrs...)
+}
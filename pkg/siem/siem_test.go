@@ -0,0 +1,120 @@
+package siem
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func records(t *testing.T, buffer *bytes.Buffer) []map[string]any {
+	t.Helper()
+	decoded := []map[string]any{}
+	decoder := json.NewDecoder(buffer)
+	for decoder.More() {
+		record := map[string]any{}
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("invalid record: %v", err)
+		}
+		decoded = append(decoded, record)
+	}
+	return decoded
+}
+
+func TestEmitterSessionEvents(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+
+	submitted := 0
+	session.SetHooks(transaction.SessionHooks{OnAfterSubmit: func(transaction.AfterSubmitEvent) { submitted++ }})
+	buffer := &bytes.Buffer{}
+	emitter := NewEmitter(slog.New(slog.NewJSONHandler(buffer, nil)))
+	emitter.Attach(&session)
+
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: w.Address, Payload: "data", PayloadType: transaction.TX_DATA.String()})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	// An unknown delegation is refused by the node
+	rejected, err := session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: w.Address, Payload: "other", PayloadType: transaction.TX_DATA.String(), DelegationId: "unknown"})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if submitted != 2 {
+		t.Fatalf("the hook installed before Attach ran %d times, want 2", submitted)
+	}
+
+	got := records(t, buffer)
+	if len(got) != 3 {
+		t.Fatalf("records = %v, want two signatures and one rejection", got)
+	}
+	signed := got[0]
+	if signed[ATTR_EVENT_NAME] != "uledger.signature.produced" || signed[ATTR_EVENT_OUTCOME] != OUTCOME_SUCCESS ||
+		signed[ATTR_WALLET_ADDRESS] != w.Address || signed[ATTR_BLOCKCHAIN_ID] != testBlockchainId || signed[ATTR_PAYLOAD_ROOT] != tx.PayloadRoot {
+		t.Fatalf("signature record = %v", signed)
+	}
+	denied := got[2]
+	if denied[ATTR_EVENT_NAME] != "uledger.authorization.rejected" || denied["level"] != "WARN" ||
+		denied[ATTR_TRANSACTION_ID] != rejected.TransactionId || denied[ATTR_OUTPUT] != transaction.TX_REJECTED_BY_UNAUTHORIZED.String() ||
+		denied[ATTR_DELEGATION_ID] != "unknown" {
+		t.Fatalf("rejection record = %v", denied)
+	}
+
+	// Signatures can be silenced while rejections keep flowing
+	emitter.Disable(CATEGORY_SIGNATURE)
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: w.Address, Payload: "again", PayloadType: transaction.TX_DATA.String(), DelegationId: "unknown"}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	got = records(t, buffer)
+	if len(got) != 1 || got[0][ATTR_EVENT_CATEGORY] != string(CATEGORY_AUTHORIZATION) {
+		t.Fatalf("records = %v, want only the rejection", got)
+	}
+}
+
+func TestEmitterKeyEvents(t *testing.T) {
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	buffer := &bytes.Buffer{}
+	emitter := NewEmitter(slog.New(slog.NewJSONHandler(buffer, nil)), CATEGORY_KEY)
+	if emitter.Enabled(CATEGORY_SIGNATURE) || !emitter.Enabled(CATEGORY_KEY) {
+		t.Fatal("only the key category should be enabled")
+	}
+
+	emitter.KeyLoaded(w, "vault")
+	forwarded := []wallet.WatchEvent{}
+	handler := emitter.WatchHandler(func(event wallet.WatchEvent) { forwarded = append(forwarded, event) })
+	handler(wallet.WatchEvent{Type: wallet.WATCH_EVENT_EVICTED, Path: "a.ukey", Address: w.Address})
+	handler(wallet.WatchEvent{Type: wallet.WATCH_EVENT_INVALID, Path: "b.ukey", Err: errors.New("bad checksum")})
+
+	got := records(t, buffer)
+	if len(got) != 3 || len(forwarded) != 2 {
+		t.Fatalf("records = %v, forwarded = %v", got, forwarded)
+	}
+	if got[0][ATTR_EVENT_NAME] != "uledger.key.loaded" || got[0][ATTR_KEY_TYPE] != crypto.KeyTypeSecp256k1.String() || got[0][ATTR_KEY_SOURCE] != "vault" {
+		t.Fatalf("loaded record = %v", got[0])
+	}
+	if got[1][ATTR_EVENT_ACTION] != "evicted" || got[1][ATTR_WALLET_ADDRESS] != w.Address {
+		t.Fatalf("evicted record = %v", got[1])
+	}
+	if got[2][ATTR_EVENT_OUTCOME] != OUTCOME_FAILURE || got[2][ATTR_ERROR_MESSAGE] != "bad checksum" {
+		t.Fatalf("invalid record = %v", got[2])
+	}
+}
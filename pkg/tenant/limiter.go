@@ -0,0 +1,96 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrRateLimited is returned when a tenant would have to wait longer than its MaxWait for a request
+type ErrRateLimited struct {
+	Tenant     string
+	RetryAfter time.Duration
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("tenant %s is rate limited, retry after %s", e.Tenant, e.RetryAfter)
+}
+
+func (e *ErrRateLimited) Is(target error) bool {
+	return target == utils.ErrUnavailable
+}
+
+// bucket is a token bucket shared by every session of a tenant
+type bucket struct {
+	tenant  string
+	rate    float64
+	burst   float64
+	maxWait time.Duration
+	now     func() time.Time
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	// limited counts the requests refused with ErrRateLimited
+	limited int64
+}
+
+func newBucket(tenant string, rate float64, burst int, maxWait time.Duration) *bucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &bucket{
+		tenant:  tenant,
+		rate:    rate,
+		burst:   float64(burst),
+		maxWait: maxWait,
+		now:     time.Now,
+		tokens:  float64(burst),
+	}
+}
+
+// Wait takes a token, sleeping for it when it arrives within maxWait
+func (b *bucket) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		b.mu.Unlock()
+		return nil
+	}
+	wait := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	if wait > b.maxWait {
+		b.limited++
+		b.mu.Unlock()
+		return &ErrRateLimited{Tenant: b.tenant, RetryAfter: wait}
+	}
+	// Reserve the token now so concurrent callers queue behind this one
+	b.tokens--
+	b.mu.Unlock()
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (b *bucket) limitedCount() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.limited
+}
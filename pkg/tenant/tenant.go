@@ -0,0 +1,323 @@
+package tenant
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// TENANT_HEADER carries the tenant id on every request so gateways can attribute traffic
+const TENANT_HEADER = "X-Tenant-Id"
+
+type ErrUnknownTenant struct {
+	Msg string
+}
+
+func (e *ErrUnknownTenant) Error() string {
+	return fmt.Sprintf("unknown tenant, %s", e.Msg)
+}
+
+func (e *ErrUnknownTenant) Is(target error) bool {
+	return target == utils.ErrNotFound
+}
+
+type ErrUnknownWallet struct {
+	Msg string
+}
+
+func (e *ErrUnknownWallet) Error() string {
+	return fmt.Sprintf("unknown wallet, %s", e.Msg)
+}
+
+func (e *ErrUnknownWallet) Is(target error) bool {
+	return target == utils.ErrNotFound
+}
+
+// ErrIsolation is returned when an operation would share a key between tenants
+type ErrIsolation struct {
+	Msg string
+}
+
+func (e *ErrIsolation) Error() string {
+	return fmt.Sprintf("tenant isolation violated, %s", e.Msg)
+}
+
+func (e *ErrIsolation) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+type Config struct {
+	Endpoint string
+	// RateLimit is the sustained number of node requests per second across all sessions of the
+	// tenant, zero disables limiting
+	RateLimit float64
+	Burst     int
+	// MaxWait is how long a request may wait for the limiter before failing with ErrRateLimited
+	MaxWait time.Duration
+	// Headers are sent with every request of the tenant, e.g. a gateway key
+	Headers map[string]string
+}
+
+// merge fills the unset fields of config from defaults, headers of config win
+func (config Config) merge(defaults Config) Config {
+	if config.Endpoint == "" {
+		config.Endpoint = defaults.Endpoint
+	}
+	if config.RateLimit == 0 {
+		config.RateLimit = defaults.RateLimit
+	}
+	if config.Burst == 0 {
+		config.Burst = defaults.Burst
+	}
+	if config.MaxWait == 0 {
+		config.MaxWait = defaults.MaxWait
+	}
+	headers := maps.Clone(defaults.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	maps.Copy(headers, config.Headers)
+	config.Headers = headers
+	return config
+}
+
+// Metrics are the counters of one tenant, request counters are summed over its sessions
+type Metrics struct {
+	transaction.SessionMetrics
+	Tenant   string
+	Wallets  int
+	Sessions int
+	// Transactions counts submissions the node answered, FailedTransactions the ones that never got an answer
+	Transactions       int64
+	FailedTransactions int64
+	// RateLimited counts requests refused by the tenant's rate limit
+	RateLimited int64
+}
+
+type tenantState struct {
+	id       string
+	config   Config
+	limiter  *bucket
+	wallets  map[string]wallet.UL_Wallet
+	sessions map[string]*transaction.UL_TransactionSession
+
+	transactions atomic.Int64
+	failed       atomic.Int64
+}
+
+// TenantManager scopes wallets, sessions, rate limits and metrics per tenant so one process can
+// serve many customers. A wallet belongs to exactly one tenant and sessions are only handed out
+// for the wallets of the tenant that asks.
+type TenantManager struct {
+	defaults Config
+
+	mu      sync.Mutex
+	tenants map[string]*tenantState
+	// owners maps wallet addresses to the tenant holding the key
+	owners map[string]string
+}
+
+// NewTenantManager creates a manager whose tenants inherit the unset fields of defaults
+func NewTenantManager(defaults Config) *TenantManager {
+	return &TenantManager{
+		defaults: defaults,
+		tenants:  make(map[string]*tenantState),
+		owners:   make(map[string]string),
+	}
+}
+
+func (m *TenantManager) AddTenant(id string, config Config) error {
+	if id == "" {
+		return fmt.Errorf("a tenant id is required")
+	}
+	config = config.merge(m.defaults)
+	if config.Endpoint == "" {
+		return fmt.Errorf("tenant %s has no node endpoint", id)
+	}
+	if config.RateLimit < 0 {
+		return fmt.Errorf("tenant %s has a negative rate limit", id)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.tenants[id]; ok {
+		return fmt.Errorf("tenant %s already exists", id)
+	}
+	state := &tenantState{
+		id:       id,
+		config:   config,
+		wallets:  make(map[string]wallet.UL_Wallet),
+		sessions: make(map[string]*transaction.UL_TransactionSession),
+	}
+	if config.RateLimit > 0 {
+		state.limiter = newBucket(id, config.RateLimit, config.Burst, config.MaxWait)
+	}
+	m.tenants[id] = state
+	return nil
+}
+
+// RemoveTenant forgets a tenant with its wallets and sessions
+func (m *TenantManager) RemoveTenant(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, err := m.tenant(id)
+	if err != nil {
+		return err
+	}
+	for _, w := range state.wallets {
+		delete(m.owners, w.Address)
+	}
+	delete(m.tenants, id)
+	return nil
+}
+
+// Tenants returns the tenant ids in order
+func (m *TenantManager) Tenants() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return slices.Sorted(maps.Keys(m.tenants))
+}
+
+// AddWallet stores a wallet under name for a tenant, a key already held by another tenant is refused
+func (m *TenantManager) AddWallet(tenantId string, name string, w wallet.UL_Wallet) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, err := m.tenant(tenantId)
+	if err != nil {
+		return err
+	}
+	if owner, ok := m.owners[w.Address]; ok && owner != tenantId {
+		return &ErrIsolation{Msg: fmt.Sprintf("wallet %s belongs to another tenant", w.Address)}
+	}
+	if previous, ok := state.wallets[name]; ok {
+		if previous.Address == w.Address {
+			return nil
+		}
+		return fmt.Errorf("tenant %s already has a wallet named %s", tenantId, name)
+	}
+	state.wallets[name] = w
+	m.owners[w.Address] = tenantId
+	return nil
+}
+
+func (m *TenantManager) RemoveWallet(tenantId string, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, err := m.tenant(tenantId)
+	if err != nil {
+		return err
+	}
+	w, ok := state.wallets[name]
+	if !ok {
+		return &ErrUnknownWallet{Msg: fmt.Sprintf("tenant %s has no wallet %s", tenantId, name)}
+	}
+	delete(state.wallets, name)
+	delete(state.sessions, name)
+	delete(m.owners, w.Address)
+	return nil
+}
+
+// Wallets returns the wallet names of a tenant in order
+func (m *TenantManager) Wallets(tenantId string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, err := m.tenant(tenantId)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(state.wallets)), nil
+}
+
+// Session returns the session signing with a wallet of the tenant, creating it on first use. The
+// session carries the tenant header and rate limit and feeds the tenant metrics through its hooks,
+// chain further hooks through Hooks rather than replacing them.
+func (m *TenantManager) Session(tenantId string, walletName string) (*transaction.UL_TransactionSession, error) {
+	m.mu.Lock()
+	state, err := m.tenant(tenantId)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	if session, ok := state.sessions[walletName]; ok {
+		m.mu.Unlock()
+		return session, nil
+	}
+	w, ok := state.wallets[walletName]
+	m.mu.Unlock()
+	if !ok {
+		return nil, &ErrUnknownWallet{Msg: fmt.Sprintf("tenant %s has no wallet %s", tenantId, walletName)}
+	}
+
+	// Connecting reaches the node, keep the lock free meanwhile
+	created, err := transaction.NewUL_TransactionSession(state.config.Endpoint, w)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open a session for tenant %s: %w", tenantId, err)
+	}
+	session := &created
+	session.SetHeader(TENANT_HEADER, tenantId)
+	for key, value := range state.config.Headers {
+		session.SetHeader(key, value)
+	}
+	if state.limiter != nil {
+		session.SetRateLimiter(state.limiter)
+	}
+	session.SetHooks(transaction.SessionHooks{
+		OnAfterSubmit: func(transaction.AfterSubmitEvent) { state.transactions.Add(1) },
+		OnError:       func(transaction.ErrorEvent) { state.failed.Add(1) },
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if current, ok := m.tenants[tenantId]; !ok || current != state || current.wallets[walletName].Address != w.Address {
+		return nil, &ErrUnknownWallet{Msg: fmt.Sprintf("tenant %s dropped wallet %s", tenantId, walletName)}
+	}
+	if existing, ok := state.sessions[walletName]; ok {
+		// Another caller won the race
+		return existing, nil
+	}
+	state.sessions[walletName] = session
+	return session, nil
+}
+
+// Metrics returns the counters of a tenant
+func (m *TenantManager) Metrics(tenantId string) (Metrics, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	state, err := m.tenant(tenantId)
+	if err != nil {
+		return Metrics{}, err
+	}
+	metrics := Metrics{
+		Tenant:             tenantId,
+		Wallets:            len(state.wallets),
+		Sessions:           len(state.sessions),
+		Transactions:       state.transactions.Load(),
+		FailedTransactions: state.failed.Load(),
+	}
+	for _, session := range state.sessions {
+		sessionMetrics := session.Metrics()
+		metrics.Requests += sessionMetrics.Requests
+		metrics.Failures += sessionMetrics.Failures
+		metrics.Retries += sessionMetrics.Retries
+		metrics.TotalLatency += sessionMetrics.TotalLatency
+	}
+	if state.limiter != nil {
+		metrics.RateLimited = state.limiter.limitedCount()
+	}
+	return metrics, nil
+}
+
+func (m *TenantManager) tenant(id string) (*tenantState, error) {
+	state, ok := m.tenants[id]
+	if !ok {
+		return nil, &ErrUnknownTenant{Msg: id}
+	}
+	return state, nil
+}
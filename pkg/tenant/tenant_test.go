@@ -0,0 +1,147 @@
+package tenant_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/tenant"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func newWallet(t *testing.T) wallet.UL_Wallet {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	return w
+}
+
+func submit(session *transaction.UL_TransactionSession, payload string) error {
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      payload,
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	return err
+}
+
+func TestTenantIsolation(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	manager := tenant.NewTenantManager(tenant.Config{Endpoint: node.URL()})
+	for _, id := range []string{"acme", "globex"} {
+		if err := manager.AddTenant(id, tenant.Config{}); err != nil {
+			t.Fatalf("AddTenant() error = %v", err)
+		}
+	}
+
+	alice := newWallet(t)
+	if err := manager.AddWallet("acme", "signer", alice); err != nil {
+		t.Fatalf("AddWallet() error = %v", err)
+	}
+	var isolation *tenant.ErrIsolation
+	if err := manager.AddWallet("globex", "signer", alice); !errors.As(err, &isolation) {
+		t.Fatalf("AddWallet() error = %v, want ErrIsolation", err)
+	}
+	if err := manager.AddWallet("globex", "signer", newWallet(t)); err != nil {
+		t.Fatalf("AddWallet() error = %v", err)
+	}
+
+	acme, err := manager.Session("acme", "signer")
+	if err != nil {
+		t.Fatalf("Session() error = %v", err)
+	}
+	globex, err := manager.Session("globex", "signer")
+	if err != nil {
+		t.Fatalf("Session() error = %v", err)
+	}
+	if acme.GetWallet().Address != alice.Address || globex.GetWallet().Address == alice.Address {
+		t.Fatal("the sessions sign with the wrong wallets")
+	}
+	if again, _ := manager.Session("acme", "signer"); again != acme {
+		t.Fatal("Session() did not reuse the tenant session")
+	}
+
+	var unknownWallet *tenant.ErrUnknownWallet
+	if _, err := manager.Session("globex", "missing"); !errors.As(err, &unknownWallet) {
+		t.Fatalf("Session() error = %v, want ErrUnknownWallet", err)
+	}
+	var unknownTenant *tenant.ErrUnknownTenant
+	if _, err := manager.Session("initech", "signer"); !errors.As(err, &unknownTenant) {
+		t.Fatalf("Session() error = %v, want ErrUnknownTenant", err)
+	}
+
+	for i := range 3 {
+		if err := submit(acme, fmt.Sprintf("acme %d", i)); err != nil {
+			t.Fatalf("GenerateTransaction() error = %v", err)
+		}
+	}
+	// Opening a session costs the same requests for both tenants, only acme submitted
+	acmeMetrics, _ := manager.Metrics("acme")
+	globexMetrics, _ := manager.Metrics("globex")
+	if acmeMetrics.Transactions != 3 || acmeMetrics.Requests != globexMetrics.Requests+3 || globexMetrics.Transactions != 0 {
+		t.Fatalf("Metrics() = %+v and %+v, counters leaked between tenants", acmeMetrics, globexMetrics)
+	}
+
+	// Removing a tenant releases its keys
+	if err := manager.RemoveTenant("acme"); err != nil {
+		t.Fatalf("RemoveTenant() error = %v", err)
+	}
+	if err := manager.AddWallet("globex", "alice", alice); err != nil {
+		t.Fatalf("AddWallet() after RemoveTenant() error = %v", err)
+	}
+	if tenants := manager.Tenants(); len(tenants) != 1 || tenants[0] != "globex" {
+		t.Fatalf("Tenants() = %v", tenants)
+	}
+}
+
+func TestTenantRateLimit(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	manager := tenant.NewTenantManager(tenant.Config{Endpoint: node.URL(), RateLimit: 0.01, Burst: 2})
+	if err := manager.AddTenant("limited", tenant.Config{}); err != nil {
+		t.Fatalf("AddTenant() error = %v", err)
+	}
+	if err := manager.AddTenant("unlimited", tenant.Config{RateLimit: 1000, Burst: 10}); err != nil {
+		t.Fatalf("AddTenant() error = %v", err)
+	}
+	for _, id := range []string{"limited", "unlimited"} {
+		if err := manager.AddWallet(id, "first", newWallet(t)); err != nil {
+			t.Fatalf("AddWallet() error = %v", err)
+		}
+		if err := manager.AddWallet(id, "second", newWallet(t)); err != nil {
+			t.Fatalf("AddWallet() error = %v", err)
+		}
+	}
+
+	// The budget is shared by every session of the tenant
+	first, _ := manager.Session("limited", "first")
+	second, _ := manager.Session("limited", "second")
+	if err := submit(first, "one"); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if err := submit(second, "two"); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	var limited *tenant.ErrRateLimited
+	if err := submit(first, "three"); !errors.As(err, &limited) || limited.Tenant != "limited" || limited.RetryAfter <= 0 {
+		t.Fatalf("GenerateTransaction() error = %v, want ErrRateLimited", err)
+	}
+
+	other, _ := manager.Session("unlimited", "first")
+	if err := submit(other, "unaffected"); err != nil {
+		t.Fatalf("GenerateTransaction() of another tenant error = %v", err)
+	}
+	metrics, _ := manager.Metrics("limited")
+	if metrics.RateLimited != 1 || metrics.FailedTransactions != 1 || metrics.Sessions != 2 {
+		t.Fatalf("Metrics() = %+v", metrics)
+	}
+}
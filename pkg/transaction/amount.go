@@ -0,0 +1,148 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrInvalidAmount is returned for token amounts that are not non negative integers
+type ErrInvalidAmount struct {
+	Msg string
+}
+
+func (e *ErrInvalidAmount) Error() string {
+	return fmt.Sprintf("invalid amount, %s", e.Msg)
+}
+
+func (e *ErrInvalidAmount) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Amount is a non negative token quantity of any size, an ERC20 supply with 18 decimals overflows a
+// uint64 at about 18 whole tokens. It is sent as a decimal string and decodes from JSON numbers as
+// well, so payloads and responses written with uint64 amounts keep working. The zero value is zero.
+type Amount struct {
+	value *big.Int
+}
+
+// NewAmount returns the amount v
+func NewAmount(v uint64) Amount {
+	return Amount{value: new(big.Int).SetUint64(v)}
+}
+
+// AmountFromBig returns a copy of v as an amount, it fails for negative values
+func AmountFromBig(v *big.Int) (Amount, error) {
+	if v == nil {
+		return Amount{}, nil
+	}
+	if v.Sign() < 0 {
+		return Amount{}, &ErrInvalidAmount{Msg: fmt.Sprintf("%s is negative", v)}
+	}
+	return Amount{value: new(big.Int).Set(v)}, nil
+}
+
+// ParseAmount parses a base 10 integer amount
+func ParseAmount(s string) (Amount, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Amount{}, &ErrInvalidAmount{Msg: fmt.Sprintf("%q is not a base 10 integer", s)}
+	}
+	return AmountFromBig(v)
+}
+
+// MustParseAmount is ParseAmount for constants, it panics when s is not a valid amount
+func MustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// ParseUnits parses a decimal quantity of whole tokens into base units, e.g. "1.5" with 18 decimals
+// is 1500000000000000000. It fails when s has more fractional digits than the token has decimals.
+func ParseUnits(s string, decimals uint8) (Amount, error) {
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > int(decimals) {
+		return Amount{}, &ErrInvalidAmount{Msg: fmt.Sprintf("%q has more than %d decimals", s, decimals)}
+	}
+	if whole == "" || strings.ContainsAny(whole+fraction, "+-") {
+		return Amount{}, &ErrInvalidAmount{Msg: fmt.Sprintf("%q is not a decimal quantity", s)}
+	}
+	return ParseAmount(whole + fraction + strings.Repeat("0", int(decimals)-len(fraction)))
+}
+
+// Big returns the amount as a big.Int the caller may modify
+func (a Amount) Big() *big.Int {
+	if a.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.value)
+}
+
+// Uint64 returns the amount as a uint64, ok is false when it does not fit
+func (a Amount) Uint64() (v uint64, ok bool) {
+	if a.value == nil {
+		return 0, true
+	}
+	return a.value.Uint64(), a.value.IsUint64()
+}
+
+// IsZero reports whether the amount is zero, it lets omitzero drop zero amounts from payloads
+func (a Amount) IsZero() bool {
+	return a.value == nil || a.value.Sign() == 0
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1
+func (a Amount) Cmp(b Amount) int {
+	return a.Big().Cmp(b.Big())
+}
+
+// Add returns a + b
+func (a Amount) Add(b Amount) Amount {
+	return Amount{value: new(big.Int).Add(a.Big(), b.Big())}
+}
+
+// Sub returns a - b, it fails rather than going below zero
+func (a Amount) Sub(b Amount) (Amount, error) {
+	if a.Cmp(b) < 0 {
+		return Amount{}, &ErrInvalidAmount{Msg: fmt.Sprintf("%s is less than %s", a, b)}
+	}
+	return Amount{value: new(big.Int).Sub(a.Big(), b.Big())}, nil
+}
+
+// String returns the amount in base 10
+func (a Amount) String() string {
+	return a.Big().String()
+}
+
+// MarshalJSON writes the amount as a decimal string
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON accepts decimal strings and integer JSON numbers, null leaves the amount at zero
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*a = Amount{}
+		return nil
+	}
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	amount, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = amount
+	return nil
+}
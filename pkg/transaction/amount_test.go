@@ -0,0 +1,85 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestAmountJSON(t *testing.T) {
+	supply, err := transaction.ParseUnits("1000000000", 18)
+	if err != nil || supply.String() != "1000000000000000000000000000" {
+		t.Fatalf("ParseUnits() = %s, %v", supply, err)
+	}
+	if _, ok := supply.Uint64(); ok {
+		t.Fatal("Uint64() reported an 18 decimal supply as fitting")
+	}
+
+	data, err := json.Marshal(transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, InitialSupply: supply})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	decoded := transaction.CreateTokenPayload{}
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.InitialSupply.Cmp(supply) != 0 {
+		t.Fatalf("round trip of %s = %s, %v", data, decoded.InitialSupply, err)
+	}
+
+	// Payloads written with uint64 amounts still decode, zero amounts are left out
+	for _, tt := range []struct {
+		json string
+		want string
+	}{
+		{`{"amount":5}`, "5"},
+		{`{"amount":"18446744073709551616"}`, "18446744073709551616"},
+		{`{"amount":null}`, "0"},
+		{`{}`, "0"},
+	} {
+		transfer := transaction.TransferTokenPayload{}
+		if err := json.Unmarshal([]byte(tt.json), &transfer); err != nil || transfer.Amount.String() != tt.want {
+			t.Errorf("Unmarshal(%s) = %s, %v, want %s", tt.json, transfer.Amount, err, tt.want)
+		}
+	}
+	if data, _ := json.Marshal(transaction.TransferTokenPayload{To: "b"}); string(data) != `{"tokenAddress":"","to":"b"}` {
+		t.Errorf("zero amount encoded as %s", data)
+	}
+
+	invalid := &transaction.ErrInvalidAmount{}
+	for _, bad := range []string{`{"amount":-1}`, `{"amount":1.5}`, `{"amount":"1e18"}`, `{"amount":"ten"}`} {
+		if err := json.Unmarshal([]byte(bad), &transaction.TransferTokenPayload{}); !errors.As(err, &invalid) {
+			t.Errorf("Unmarshal(%s) error = %v", bad, err)
+		}
+	}
+	for _, bad := range []string{"1.0000001", "-1", ".5", "1.2.3"} {
+		if _, err := transaction.ParseUnits(bad, 6); err == nil {
+			t.Errorf("ParseUnits(%q) accepted", bad)
+		}
+	}
+	if _, err := transaction.NewAmount(1).Sub(transaction.NewAmount(2)); !errors.As(err, &invalid) {
+		t.Errorf("Sub() below zero error = %v", err)
+	}
+}
+
+func TestLargeSupplyTransfer(t *testing.T) {
+	node, session := newMockSession(t)
+	supply := transaction.MustParseAmount("1000000000000000000000000000")
+	token := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Wide", Symbol: "WIDE", Decimals: 18, InitialSupply: supply, Mintable: true})
+
+	recipient := "00000000000000000000000000000000000000000000000000000000000000bb"
+	amount, _ := transaction.ParseUnits("250000000.5", 18)
+	submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipient, Amount: amount})
+	if balance := node.BalanceAmount(token, recipient); balance.Cmp(amount) != 0 {
+		t.Fatalf("recipient balance = %s, want %s", balance, amount)
+	}
+	remaining, _ := supply.Sub(amount)
+	if balance := node.BalanceAmount(token, session.GetWallet().Address); balance.Cmp(remaining) != 0 {
+		t.Fatalf("sender balance = %s, want %s", balance, remaining)
+	}
+
+	submitToken(t, session, transaction.MINT_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: recipient, Amount: supply})
+	tokens, err := session.ListTokens(testBlockchainId, transaction.ListOptions{}).Collect(t.Context())
+	if err != nil || tokens[0].TotalSupply.String() != "2000000000000000000000000000" {
+		t.Fatalf("TotalSupply = %s, %v", tokens[0].TotalSupply, err)
+	}
+}
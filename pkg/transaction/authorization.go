@@ -0,0 +1,60 @@
+package transaction
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Authorization tells whether a wallet may send a payload type, the wallet is allowed when Issues is
+// empty. Each issue is a rejection the node would answer with.
+type Authorization struct {
+	PayloadType string           `json:"payloadType"`
+	Issues      []PreflightIssue `json:"issues"`
+}
+
+func (a Authorization) Allowed() bool {
+	return len(a.Issues) == 0
+}
+
+// Reason explains why the wallet is not allowed, it is empty when the wallet is allowed
+func (a Authorization) Reason() string {
+	details := make([]string, len(a.Issues))
+	for i, issue := range a.Issues {
+		details[i] = issue.Detail
+	}
+	return strings.Join(details, ", ")
+}
+
+// Can tells whether a wallet may send transactions of payloadType under the newest of
+// DEFAULT_PREFLIGHT_RULES, without contacting the node. state is usually read with GetWalletState,
+// use PreflightRules.Can or Preflighter.Can for the rules of a given node version.
+func Can(state WalletState, payloadType string) Authorization {
+	return SelectPreflightRules(DEFAULT_PREFLIGHT_RULES, "").Can(state, payloadType)
+}
+
+// Can applies the sender checks of the rules that depend on the wallet alone: a disabled wallet may
+// send nothing and the payload types listed in AuthGroups need the permission of their auth group,
+// which root wallets are exempt from with ExemptRoots.
+func (rules PreflightRules) Can(state WalletState, payloadType string) Authorization {
+	result := Authorization{PayloadType: payloadType, Issues: []PreflightIssue{}}
+	fail := func(check PreflightCheck, format string, args ...any) {
+		result.Issues = append(result.Issues, PreflightIssue{Check: check, Output: check.Output(), Detail: fmt.Sprintf(format, args...)})
+	}
+	enabled := func(check PreflightCheck) bool { return slices.Contains(rules.Checks, check) }
+
+	if enabled(PREFLIGHT_SENDER_ENABLED) && !state.Enabled {
+		fail(PREFLIGHT_SENDER_ENABLED, "wallet %s is disabled", state.Address)
+	}
+	requirement, ok := rules.AuthGroups[payloadType]
+	exempt := rules.ExemptRoots && state.Parent == ""
+	if enabled(PREFLIGHT_AUTH_GROUP) && ok && !exempt && !requirement.Allows(state.AuthGroups) {
+		fail(PREFLIGHT_AUTH_GROUP, "wallet %s lacks the %s permission of auth group %q", state.Address, requirement.Permission, requirement.Group)
+	}
+	return result
+}
+
+// Can tells whether a wallet may send transactions of payloadType under the rules of the node
+func (p *Preflighter) Can(state WalletState, payloadType string) Authorization {
+	return p.rules.Can(state, payloadType)
+}
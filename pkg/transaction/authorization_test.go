@@ -0,0 +1,60 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestCan(t *testing.T) {
+	parent := "aa"
+	writer := transaction.WalletState{Address: "writer", Enabled: true, Parent: parent, AuthGroups: map[string]wallet.UL_AuthPermission{
+		"data": {Create: true},
+	}}
+	admin := transaction.WalletState{Address: "admin", Enabled: true, Parent: parent, AuthGroups: map[string]wallet.UL_AuthPermission{
+		wallet.WALLET_GROUP_NAME: {Create: true, Read: true},
+	}}
+	root := transaction.WalletState{Address: "root", Enabled: true, AuthGroups: map[string]wallet.UL_AuthPermission{}}
+	disabled := writer
+	disabled.Enabled = false
+
+	tests := []struct {
+		name        string
+		state       transaction.WalletState
+		payloadType transaction.ULTransactionType
+		want        []transaction.PreflightCheck
+	}{
+		{"data writer", writer, transaction.TX_DATA, nil},
+		{"data writer creating a wallet", writer, transaction.TX_CREATE_WALLET, []transaction.PreflightCheck{transaction.PREFLIGHT_AUTH_GROUP}},
+		{"wallet admin creating a wallet", admin, transaction.TX_CREATE_WALLET, nil},
+		{"wallet admin without update", admin, transaction.TX_ALTER_WALLET, []transaction.PreflightCheck{transaction.PREFLIGHT_AUTH_GROUP}},
+		{"unlisted payload type", admin, transaction.DEPLOY_SMART_CONTRACT, nil},
+		{"root wallet", root, transaction.TX_ALTER_WALLET, nil},
+		{"disabled wallet", disabled, transaction.TX_DATA, []transaction.PreflightCheck{transaction.PREFLIGHT_SENDER_ENABLED}},
+		{"disabled wallet without permission", disabled, transaction.TX_CREATE_WALLET, []transaction.PreflightCheck{transaction.PREFLIGHT_SENDER_ENABLED, transaction.PREFLIGHT_AUTH_GROUP}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := transaction.Can(tt.state, tt.payloadType.String())
+			if got.Allowed() != (len(tt.want) == 0) || len(got.Issues) != len(tt.want) {
+				t.Fatalf("Can() = %+v, want the issues %v", got, tt.want)
+			}
+			for i, issue := range got.Issues {
+				if issue.Check != tt.want[i] || issue.Output != tt.want[i].Output() {
+					t.Fatalf("Can() issue %d = %+v, want %s", i, issue, tt.want[i])
+				}
+			}
+			if (got.Reason() == "") != got.Allowed() {
+				t.Fatalf("Reason() = %q with Allowed() = %v", got.Reason(), got.Allowed())
+			}
+		})
+	}
+
+	// Rules without root exemption hold roots to their auth groups
+	rules := transaction.SelectPreflightRules(transaction.DEFAULT_PREFLIGHT_RULES, "")
+	rules.ExemptRoots = false
+	if got := rules.Can(root, transaction.TX_ALTER_WALLET.String()); got.Allowed() {
+		t.Fatalf("Can() without root exemption = %+v, want a denial", got)
+	}
+}
@@ -0,0 +1,318 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+)
+
+// BalanceKey identifies a balance, fungible tokens use TokenId 0
+type BalanceKey struct {
+	TokenAddress string `json:"tokenAddress"`
+	TokenId      uint64 `json:"tokenId"`
+	Owner        string `json:"owner"`
+}
+
+// BalanceChange is the balance a transaction left behind
+type BalanceChange struct {
+	BlockHeight   int    `json:"blockHeight"`
+	TransactionId string `json:"transactionId"`
+	Balance       Amount `json:"balance"`
+}
+
+// BalanceHistory reconstructs token balances at any block height by replaying the successful token
+// transactions of a chain, for audits and accounting. Replayed changes are kept in memory, so past
+// heights are answered without the node and Sync only fetches transactions sealed since the last call.
+//
+// Transfers are addressed to the token, not to the recipient, so the whole chain is replayed even
+// when only a few owners are tracked. ERC721 transfers, mints and burns without an Amount move one
+// unit of their TokenId. Conversions to an id the node assigns debit the source id only.
+type BalanceHistory struct {
+	session      *UL_TransactionSession
+	blockchainId string
+	owners       map[string]bool
+
+	mu      sync.Mutex
+	changes map[BalanceKey][]BalanceChange
+	tokens  []ULToken
+	height  int
+	// cursor fetched the page of the last replayed transaction and skip counts the transactions of that
+	// page already replayed, listings only grow so the next Sync resumes there
+	cursor string
+	skip   int
+}
+
+// NewBalanceHistory replays the token transactions of a chain, owners restricts the kept history to
+// those wallets and no owners keeps every balance
+func NewBalanceHistory(session *UL_TransactionSession, blockchainId string, owners ...string) *BalanceHistory {
+	history := &BalanceHistory{
+		session:      session,
+		blockchainId: blockchainId,
+		changes:      map[BalanceKey][]BalanceChange{},
+	}
+	if len(owners) > 0 {
+		history.owners = map[string]bool{}
+		for _, owner := range owners {
+			history.owners[owner] = true
+		}
+	}
+	return history
+}
+
+// Height returns the last block replayed
+func (h *BalanceHistory) Height() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.height
+}
+
+// Sync replays the transactions sealed since the last call and returns the height replayed up to
+func (h *BalanceHistory) Sync(ctx context.Context) (int, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.sync(ctx)
+}
+
+func (h *BalanceHistory) sync(ctx context.Context) (int, error) {
+	target, err := h.session.GetBlockHeight(ctx, h.blockchainId)
+	if err != nil {
+		return h.height, err
+	}
+	if target <= h.height {
+		return h.height, nil
+	}
+
+	it := h.session.ListTransactions(h.blockchainId, TransactionFilter{}, ListOptions{PageSize: MAX_PAGE_SIZE, Cursor: h.cursor})
+	resumed, replayed := h.cursor, h.skip
+	cursor, index := h.cursor, 0
+	for it.Next(ctx) {
+		if page := it.PageInfo().Cursor; page != cursor {
+			cursor, index = page, 0
+		}
+		index++
+		if cursor == resumed && index <= replayed {
+			continue
+		}
+		tx := it.Value()
+		if tx.BlockHeight > target {
+			// Sealed after the height was read, the next Sync replays it
+			break
+		}
+		if err := h.replay(ctx, tx); err != nil {
+			return h.height, fmt.Errorf("unable to replay transaction %s: %w", tx.TransactionId, err)
+		}
+		h.cursor, h.skip = cursor, index
+	}
+	if err := it.Err(); err != nil {
+		return h.height, err
+	}
+	h.height = target
+	return h.height, nil
+}
+
+// BalanceAt returns the balance of owner at a block height, syncing first when the height was not
+// replayed yet. Heights past the chain head return the current balance.
+func (h *BalanceHistory) BalanceAt(ctx context.Context, tokenAddress string, tokenId uint64, owner string, height int) (Amount, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.owners != nil && !h.owners[owner] {
+		return Amount{}, fmt.Errorf("the history does not track %s", owner)
+	}
+	if height > h.height {
+		if _, err := h.sync(ctx); err != nil {
+			return Amount{}, err
+		}
+	}
+	return h.balanceAt(BalanceKey{TokenAddress: tokenAddress, TokenId: tokenId, Owner: owner}, height), nil
+}
+
+// BalancesAt returns every non zero balance of owner at a block height
+func (h *BalanceHistory) BalancesAt(ctx context.Context, owner string, height int) (map[BalanceKey]Amount, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if height > h.height {
+		if _, err := h.sync(ctx); err != nil {
+			return nil, err
+		}
+	}
+	balances := map[BalanceKey]Amount{}
+	for key := range h.changes {
+		if key.Owner != owner {
+			continue
+		}
+		if balance := h.balanceAt(key, height); !balance.IsZero() {
+			balances[key] = balance
+		}
+	}
+	return balances, nil
+}
+
+// Changes returns the replayed changes of a balance, oldest first
+func (h *BalanceHistory) Changes(key BalanceKey) []BalanceChange {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.changes[key])
+}
+
+func (h *BalanceHistory) balanceAt(key BalanceKey, height int) Amount {
+	changes := h.changes[key]
+	i := sort.Search(len(changes), func(i int) bool { return changes[i].BlockHeight > height })
+	if i == 0 {
+		return Amount{}
+	}
+	return changes[i-1].Balance
+}
+
+// current returns the latest replayed balance, untracked owners are always zero
+func (h *BalanceHistory) current(key BalanceKey) Amount {
+	changes := h.changes[key]
+	if len(changes) == 0 {
+		return Amount{}
+	}
+	return changes[len(changes)-1].Balance
+}
+
+func (h *BalanceHistory) record(tx ULTransaction, key BalanceKey, balance Amount) {
+	if h.owners != nil && !h.owners[key.Owner] {
+		return
+	}
+	h.changes[key] = append(h.changes[key], BalanceChange{BlockHeight: tx.BlockHeight, TransactionId: tx.TransactionId, Balance: balance})
+}
+
+func (h *BalanceHistory) credit(tx ULTransaction, key BalanceKey, amount Amount) {
+	if key.Owner != "" && !amount.IsZero() {
+		h.record(tx, key, h.current(key).Add(amount))
+	}
+}
+
+func (h *BalanceHistory) debit(tx ULTransaction, key BalanceKey, amount Amount) error {
+	if amount.IsZero() || (h.owners != nil && !h.owners[key.Owner]) {
+		return nil
+	}
+	balance, err := h.current(key).Sub(amount)
+	if err != nil {
+		return fmt.Errorf("%s spends more of %s than it holds: %w", key.Owner, key.TokenAddress, err)
+	}
+	h.record(tx, key, balance)
+	return nil
+}
+
+// units is the amount a payload moves, one unit of the token id for ERC721 payloads without amount
+func units(amount Amount, tokenId uint64) Amount {
+	if amount.IsZero() && tokenId != 0 {
+		return NewAmount(1)
+	}
+	return amount
+}
+
+func (h *BalanceHistory) replay(ctx context.Context, tx ULTransaction) error {
+	if tx.Output != TX_SUCCESS.String() {
+		return nil
+	}
+	unmarshal := func(payload any) error {
+		if err := json.Unmarshal([]byte(tx.GetPayload()), payload); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", tx.PayloadType, err)
+		}
+		return nil
+	}
+
+	switch tx.PayloadType {
+	case CREATE_TOKEN.String():
+		payload := CreateTokenPayload{}
+		if err := unmarshal(&payload); err != nil {
+			return err
+		}
+		if payload.InitialSupply.IsZero() {
+			return nil
+		}
+		token, err := h.createdToken(ctx, tx, payload)
+		if err != nil {
+			return err
+		}
+		h.credit(tx, BalanceKey{TokenAddress: token, Owner: tx.From}, payload.InitialSupply)
+	case MINT_TOKEN.String(), MINT_NFT.String(), MINT_MULTI_TOKEN.String():
+		payload := MintTokenPayload{}
+		if err := unmarshal(&payload); err != nil {
+			return err
+		}
+		h.credit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: payload.TokenId, Owner: payload.To}, units(payload.Amount, payload.TokenId))
+	case TRANSFER_TOKEN.String(), TRANSFER_NFT.String(), TRANSFER_MULTI_TOKEN.String():
+		payload := TransferTokenPayload{}
+		if err := unmarshal(&payload); err != nil {
+			return err
+		}
+		owner := tx.From
+		if payload.From != "" {
+			owner = payload.From
+		}
+		moves := map[uint64]Amount{payload.TokenId: units(payload.Amount, payload.TokenId)}
+		if len(payload.TokenIds) > 0 {
+			if len(payload.TokenIds) != len(payload.Amounts) {
+				return fmt.Errorf("%d token ids with %d amounts", len(payload.TokenIds), len(payload.Amounts))
+			}
+			moves = map[uint64]Amount{}
+			for i, id := range payload.TokenIds {
+				moves[id] = moves[id].Add(payload.Amounts[i])
+			}
+		}
+		for _, id := range slices.Sorted(maps.Keys(moves)) {
+			if err := h.debit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: id, Owner: owner}, moves[id]); err != nil {
+				return err
+			}
+			h.credit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: id, Owner: payload.To}, moves[id])
+		}
+	case BURN_TOKEN.String():
+		payload := BurnTokenPayload{}
+		if err := unmarshal(&payload); err != nil {
+			return err
+		}
+		owner := tx.From
+		if payload.From != "" {
+			owner = payload.From
+		}
+		return h.debit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: payload.TokenId, Owner: owner}, units(payload.Amount, payload.TokenId))
+	case CONVERT_TOKEN.String():
+		payload := ConvertTokenPayload{}
+		if err := unmarshal(&payload); err != nil {
+			return err
+		}
+		amount := payload.Amount
+		if !payload.PreserveTokens {
+			if err := h.debit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: payload.FromTokenId, Owner: tx.From}, amount); err != nil {
+				return err
+			}
+		}
+		if payload.ToTokenId != 0 {
+			h.credit(tx, BalanceKey{TokenAddress: payload.TokenAddress, TokenId: payload.ToTokenId, Owner: tx.From}, amount)
+		}
+	}
+	return nil
+}
+
+// createdToken finds the address the node gave to the token a CREATE_TOKEN transaction created
+func (h *BalanceHistory) createdToken(ctx context.Context, tx ULTransaction, payload CreateTokenPayload) (string, error) {
+	find := func() (string, bool) {
+		for _, token := range h.tokens {
+			if token.Owner == tx.From && token.Name == payload.Name && token.Symbol == payload.Symbol && token.CreatedBlock == tx.BlockHeight {
+				return token.TokenAddress, true
+			}
+		}
+		return "", false
+	}
+	if address, ok := find(); ok {
+		return address, nil
+	}
+	tokens, err := h.session.ListTokens(h.blockchainId, ListOptions{PageSize: MAX_PAGE_SIZE}).Collect(ctx)
+	if err != nil {
+		return "", err
+	}
+	h.tokens = tokens
+	if address, ok := find(); ok {
+		return address, nil
+	}
+	return "", fmt.Errorf("the token %s created in block %d is not registered", payload.Symbol, tx.BlockHeight)
+}
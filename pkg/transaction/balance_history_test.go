@@ -0,0 +1,83 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestBalanceHistory(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	owner := session.GetWallet().Address
+	recipient := "00000000000000000000000000000000000000000000000000000000000000bb"
+
+	token := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Ledger", Symbol: "LGR", InitialSupply: transaction.NewAmount(1000), Mintable: true, Burnable: true})
+	created, _ := session.GetBlockHeight(ctx, testBlockchainId)
+	submitData(t, session, "unrelated")
+	transfer := submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipient, Amount: transaction.NewAmount(300)})
+	mint := submitToken(t, session, transaction.MINT_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: recipient, Amount: transaction.NewAmount(50)})
+
+	multi := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC1155_TOKEN_TYPE, Name: "Parts", Symbol: "PRT"})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: multi, To: owner, TokenId: 1, Amount: transaction.NewAmount(10)})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: multi, To: owner, TokenId: 2, Amount: transaction.NewAmount(5)})
+	batch := submitToken(t, session, transaction.TRANSFER_MULTI_TOKEN, transaction.TransferTokenPayload{TokenAddress: multi, To: recipient, TokenIds: []uint64{1, 2}, Amounts: amounts(4, 5)})
+
+	history := transaction.NewBalanceHistory(session, testBlockchainId)
+	balanceAt := func(token string, tokenId uint64, address string, height int) uint64 {
+		t.Helper()
+		balance, err := history.BalanceAt(ctx, token, tokenId, address, height)
+		if err != nil {
+			t.Fatalf("BalanceAt() error = %v", err)
+		}
+		value, _ := balance.Uint64()
+		return value
+	}
+	for _, check := range []struct {
+		token   string
+		tokenId uint64
+		address string
+		height  int
+		want    uint64
+	}{
+		{token, 0, owner, created - 1, 0},
+		{token, 0, owner, created, 1000},
+		{token, 0, owner, transfer.BlockHeight, 700},
+		{token, 0, recipient, transfer.BlockHeight - 1, 0},
+		{token, 0, recipient, transfer.BlockHeight, 300},
+		{token, 0, recipient, mint.BlockHeight, 350},
+		{multi, 1, owner, batch.BlockHeight - 1, 10},
+		{multi, 1, owner, batch.BlockHeight, 6},
+		{multi, 2, owner, batch.BlockHeight, 0},
+		{multi, 2, recipient, batch.BlockHeight, 5},
+	} {
+		if got := balanceAt(check.token, check.tokenId, check.address, check.height); got != check.want {
+			t.Errorf("BalanceAt(%s, %d, %s, %d) = %d, want %d", check.token[:8], check.tokenId, check.address[:8], check.height, got, check.want)
+		}
+	}
+	if got, want := node.Balance(token, recipient), balanceAt(token, 0, recipient, history.Height()); got != want {
+		t.Fatalf("replayed balance %d, node balance %d", want, got)
+	}
+
+	// New blocks are replayed incrementally, earlier transactions are not applied twice
+	late := submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipient, Amount: transaction.NewAmount(100)})
+	if got := balanceAt(token, 0, recipient, late.BlockHeight); got != 450 {
+		t.Fatalf("BalanceAt() after a new block = %d, want 450", got)
+	}
+	if history.Height() != late.BlockHeight || len(history.Changes(transaction.BalanceKey{TokenAddress: token, Owner: recipient})) != 3 {
+		t.Fatalf("Height() = %d, changes %+v", history.Height(), history.Changes(transaction.BalanceKey{TokenAddress: token, Owner: recipient}))
+	}
+	balances, err := history.BalancesAt(ctx, recipient, late.BlockHeight)
+	if err != nil || len(balances) != 3 {
+		t.Fatalf("BalancesAt() = %v, %v", balances, err)
+	}
+
+	tracked := transaction.NewBalanceHistory(session, testBlockchainId, recipient)
+	if _, err := tracked.BalanceAt(ctx, token, 0, owner, late.BlockHeight); err == nil {
+		t.Fatal("BalanceAt() answered for an untracked owner")
+	}
+	if balance, err := tracked.BalanceAt(ctx, token, 0, recipient, late.BlockHeight); err != nil || balance.String() != "450" {
+		t.Fatalf("BalanceAt() of a tracked owner = %s, %v", balance, err)
+	}
+}
@@ -0,0 +1,112 @@
+package transaction_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func benchmarkInput(payloadType transaction.ULTransactionType, keyType crypto.KeyType, size int) transaction.ULTransactionInput {
+	return transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		From:         strings.Repeat("a1", 32),
+		To:           strings.Repeat("b2", 32),
+		Payload:      strings.Repeat("x", size),
+		PayloadType:  payloadType.String(),
+		KeyType:      keyType,
+	}
+}
+
+// BenchmarkSigningCommitment covers the Merkle root and commitment hashing of bound payloads up to
+// MAX_BOUND_PAYLOAD_SIZE and of unbound deploy payloads, for every hasher
+func BenchmarkSigningCommitment(b *testing.B) {
+	cases := []struct {
+		payloadType transaction.ULTransactionType
+		sizes       []int
+	}{
+		{transaction.TX_DATA, []int{16, 256, transaction.MAX_BOUND_PAYLOAD_SIZE}},
+		{transaction.DEPLOY_SMART_CONTRACT, []int{1 << 10, 16 << 10, 64 << 10}},
+	}
+	for _, keyType := range []crypto.KeyType{crypto.KeyTypeSecp256k1, crypto.KeyTypeED25519, crypto.KeyTypeBLS12377} {
+		for _, c := range cases {
+			for _, size := range c.sizes {
+				b.Run(fmt.Sprintf("%s/%s/%dB", keyType, c.payloadType, size), func(b *testing.B) {
+					input := benchmarkInput(c.payloadType, keyType, size)
+					b.SetBytes(int64(size))
+					b.ReportAllocs()
+					for b.Loop() {
+						if _, _, err := input.SigningCommitment(); err != nil {
+							b.Fatal(err)
+						}
+					}
+				})
+			}
+		}
+	}
+}
+
+// benchmarkValues are contract arguments of every shape the serializer handles
+var benchmarkValues = map[string]any{
+	"scalars": []any{true, int32(7), int64(1 << 40), 3.5, "transfer"},
+	"bytes":   make([]byte, 4<<10),
+	"strings": []string{strings.Repeat("a", 64), strings.Repeat("b", 64), strings.Repeat("c", 64)},
+	"map": map[string]interface{}{
+		"owner":   strings.Repeat("a1", 32),
+		"amount":  int64(1000),
+		"enabled": true,
+		"tags":    []any{"x", "y", "z"},
+	},
+}
+
+func BenchmarkEncode(b *testing.B) {
+	for name, value := range benchmarkValues {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := transaction.Encode(value); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	for name, value := range benchmarkValues {
+		b.Run(name, func(b *testing.B) {
+			encoded, err := transaction.Encode(value)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.SetBytes(int64(len(encoded)))
+			b.ReportAllocs()
+			for b.Loop() {
+				if _, err := transaction.Decode(encoded); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkCommitmentScheme compares the payload trees of the registered schemes on deploy payloads
+func BenchmarkCommitmentScheme(b *testing.B) {
+	for _, name := range []string{transaction.COMMITMENT_SCHEME_MIMC, transaction.COMMITMENT_SCHEME_SHA256_PARALLEL} {
+		for _, size := range []int{1 << 10, 64 << 10} {
+			b.Run(fmt.Sprintf("%s/%dB", name, size), func(b *testing.B) {
+				input := benchmarkInput(transaction.DEPLOY_SMART_CONTRACT, crypto.KeyTypeSecp256k1, size)
+				input.CommitmentScheme = name
+				b.SetBytes(int64(size))
+				b.ReportAllocs()
+				for b.Loop() {
+					if _, _, err := input.SigningCommitment(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
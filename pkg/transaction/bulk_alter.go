@@ -0,0 +1,242 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// WalletAlteration is one ALTER_WALLET of a bulk run, Session signs it
+type WalletAlteration struct {
+	Session    *UL_TransactionSession
+	Target     string
+	Enabled    bool
+	AuthGroups map[string]wallet.UL_AuthPermission
+}
+
+type BulkAlterOptions struct {
+	// Rollback submits compensating alters restoring the previous state of every applied alteration
+	// once one fails, in reverse order
+	Rollback bool
+	// ContinueOnError attempts the remaining alterations after a failure, ignored when Rollback is set
+	ContinueOnError bool
+	// SkipValidation submits without checking targets and signers against the registered wallets,
+	// for nodes that cannot list wallets. Rollback needs the validation to know the previous state.
+	SkipValidation bool
+}
+
+type AlterStatus string
+
+const (
+	ALTER_STATUS_PENDING         AlterStatus = "pending"
+	ALTER_STATUS_APPLIED         AlterStatus = "applied"
+	ALTER_STATUS_FAILED          AlterStatus = "failed"
+	ALTER_STATUS_SKIPPED         AlterStatus = "skipped"
+	ALTER_STATUS_ROLLED_BACK     AlterStatus = "rolled_back"
+	ALTER_STATUS_ROLLBACK_FAILED AlterStatus = "rollback_failed"
+)
+
+// AlterResult is the outcome of one alteration, Previous is the registered state before the run
+type AlterResult struct {
+	Target                string        `json:"target"`
+	Signer                string        `json:"signer"`
+	Status                AlterStatus   `json:"status"`
+	TransactionId         string        `json:"transactionId,omitempty"`
+	RollbackTransactionId string        `json:"rollbackTransactionId,omitempty"`
+	Error                 string        `json:"error,omitempty"`
+	Previous              *ULWalletInfo `json:"previous,omitempty"`
+}
+
+// BulkAlterReport lists the results in the order of the alterations
+type BulkAlterReport struct {
+	Results  []AlterResult `json:"results"`
+	Started  time.Time     `json:"started"`
+	Finished time.Time     `json:"finished"`
+}
+
+// Count returns the number of results with status
+func (report BulkAlterReport) Count(status AlterStatus) int {
+	count := 0
+	for _, result := range report.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Succeeded reports whether every alteration was applied
+func (report BulkAlterReport) Succeeded() bool {
+	return len(report.Results) > 0 && report.Count(ALTER_STATUS_APPLIED) == len(report.Results)
+}
+
+// ErrBulkAlterValidation lists every problem found before anything was submitted
+type ErrBulkAlterValidation struct {
+	Problems []string
+}
+
+func (e *ErrBulkAlterValidation) Error() string {
+	return fmt.Sprintf("bulk alter validation failed, %s", strings.Join(e.Problems, "; "))
+}
+
+func (e *ErrBulkAlterValidation) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// BulkAlterWallets validates every alteration up front and then submits them in order. Nothing is
+// submitted when validation fails. A failure stops the run unless ContinueOnError is set, and with
+// Rollback the alterations applied so far are compensated so the fleet is never left half updated.
+// The report is returned even when err is not nil.
+func BulkAlterWallets(ctx context.Context, blockchainId string, alterations []WalletAlteration, opts BulkAlterOptions) (report BulkAlterReport, err error) {
+	report = BulkAlterReport{Started: time.Now().UTC(), Results: make([]AlterResult, len(alterations))}
+	defer func() { report.Finished = time.Now().UTC() }()
+	if len(alterations) == 0 {
+		return report, &ErrBulkAlterValidation{Problems: []string{"no alterations"}}
+	}
+	if opts.Rollback && opts.SkipValidation {
+		return report, &ErrBulkAlterValidation{Problems: []string{"rollback needs the validation to record the previous state"}}
+	}
+
+	for i, alteration := range alterations {
+		report.Results[i] = AlterResult{Target: alteration.Target, Status: ALTER_STATUS_PENDING}
+		if alteration.Session != nil {
+			report.Results[i].Signer = alteration.Session.GetWallet().Address
+		}
+	}
+	if err = validateAlterations(ctx, blockchainId, alterations, opts, report.Results); err != nil {
+		return report, err
+	}
+
+	var failure error
+	for i, alteration := range alterations {
+		result := &report.Results[i]
+		if failure != nil && !opts.ContinueOnError || failure != nil && opts.Rollback {
+			result.Status = ALTER_STATUS_SKIPPED
+			continue
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			failure = ctxErr
+			result.Status = ALTER_STATUS_SKIPPED
+			continue
+		}
+		txId, submitErr := submitAlter(alteration.Session, blockchainId, alteration.Target, alteration.Enabled, alteration.AuthGroups)
+		result.TransactionId = txId
+		if submitErr != nil {
+			result.Status = ALTER_STATUS_FAILED
+			result.Error = submitErr.Error()
+			if failure == nil {
+				failure = fmt.Errorf("altering %s failed: %w", alteration.Target, submitErr)
+			}
+			continue
+		}
+		result.Status = ALTER_STATUS_APPLIED
+	}
+	if failure == nil || !opts.Rollback {
+		return report, failure
+	}
+
+	// Compensate in reverse order so later changes are undone first
+	for i := len(alterations) - 1; i >= 0; i-- {
+		result := &report.Results[i]
+		if result.Status != ALTER_STATUS_APPLIED {
+			continue
+		}
+		previous := result.Previous
+		txId, rollbackErr := submitAlter(alterations[i].Session, blockchainId, result.Target, previous.Enabled, previous.AuthGroups)
+		result.RollbackTransactionId = txId
+		if rollbackErr != nil {
+			result.Status = ALTER_STATUS_ROLLBACK_FAILED
+			result.Error = rollbackErr.Error()
+			continue
+		}
+		result.Status = ALTER_STATUS_ROLLED_BACK
+	}
+	if n := report.Count(ALTER_STATUS_ROLLBACK_FAILED); n > 0 {
+		return report, fmt.Errorf("%w, and %d compensating alters failed", failure, n)
+	}
+	return report, failure
+}
+
+// validateAlterations checks the alterations and records the previous state of every target
+func validateAlterations(ctx context.Context, blockchainId string, alterations []WalletAlteration, opts BulkAlterOptions, results []AlterResult) error {
+	problems := []string{}
+	targets := make(map[string]bool, len(alterations))
+	// The registered wallets are listed through the first session
+	var lookup *UL_TransactionSession
+	for i, alteration := range alterations {
+		if alteration.Session == nil {
+			problems = append(problems, fmt.Sprintf("alteration %d has no session", i))
+			continue
+		}
+		if lookup == nil {
+			lookup = alteration.Session
+		}
+		if decoded, err := hex.DecodeString(alteration.Target); err != nil || len(decoded) != 32 {
+			problems = append(problems, fmt.Sprintf("alteration %d targets an invalid address %q", i, alteration.Target))
+			continue
+		}
+		if targets[alteration.Target] {
+			problems = append(problems, fmt.Sprintf("%s is altered more than once", alteration.Target))
+		}
+		targets[alteration.Target] = true
+	}
+	if len(problems) > 0 || opts.SkipValidation {
+		if len(problems) > 0 {
+			return &ErrBulkAlterValidation{Problems: problems}
+		}
+		return nil
+	}
+
+	registered := make(map[string]ULWalletInfo)
+	it := lookup.ListWallets(blockchainId, "", ListOptions{})
+	for info := range it.All(ctx) {
+		registered[info.Address] = info
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("unable to list the registered wallets: %w", err)
+	}
+
+	for i, alteration := range alterations {
+		previous, ok := registered[alteration.Target]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("target %s is not registered", alteration.Target))
+			continue
+		}
+		results[i].Previous = &previous
+		signer, ok := registered[results[i].Signer]
+		if !ok || !signer.Enabled {
+			problems = append(problems, fmt.Sprintf("signer %s is not an enabled wallet", results[i].Signer))
+		}
+	}
+	if len(problems) > 0 {
+		return &ErrBulkAlterValidation{Problems: problems}
+	}
+	return nil
+}
+
+// submitAlter sends one ALTER_WALLET and fails unless the node executed it successfully
+func submitAlter(session *UL_TransactionSession, blockchainId string, target string, enabled bool, authGroups map[string]wallet.UL_AuthPermission) (string, error) {
+	payload, err := json.Marshal(AlterWalletPayload{Target: target, Enabled: enabled, AuthGroups: authGroups})
+	if err != nil {
+		return "", err
+	}
+	tx, err := session.GenerateTransaction(ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           target,
+		Payload:      string(payload),
+		PayloadType:  TX_ALTER_WALLET.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+	if tx.Output != TX_SUCCESS.String() {
+		return tx.TransactionId, fmt.Errorf("the node answered %s / %s", tx.Status, tx.Output)
+	}
+	return tx.TransactionId, nil
+}
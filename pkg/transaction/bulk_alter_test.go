@@ -0,0 +1,156 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func registerWallet(t *testing.T, session *transaction.UL_TransactionSession, parent string) string {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, parent, nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	payload, _ := json.Marshal(transaction.CreateWalletPayload{PublicKey: w.GetKey().GetPublicKeyHex(false), Parent: parent, KeyType: crypto.KeyTypeSecp256k1})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		From:         parent,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_CREATE_WALLET.String(),
+	}); err != nil {
+		t.Fatalf("CREATE_WALLET error = %v", err)
+	}
+	return w.Address
+}
+
+// registerSigner registers the session's own wallet as a root wallet
+func registerSigner(t *testing.T, session *transaction.UL_TransactionSession) {
+	t.Helper()
+	w := session.GetWallet()
+	payload, _ := json.Marshal(transaction.CreateWalletPayload{PublicKey: w.GetKey().GetPublicKeyHex(false), KeyType: crypto.KeyTypeSecp256k1})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_CREATE_WALLET.String(),
+	}); err != nil {
+		t.Fatalf("CREATE_WALLET error = %v", err)
+	}
+}
+
+func registeredWallets(t *testing.T, session *transaction.UL_TransactionSession) map[string]transaction.ULWalletInfo {
+	t.Helper()
+	wallets, err := session.ListWallets(testBlockchainId, "", transaction.ListOptions{}).Collect(context.Background())
+	if err != nil {
+		t.Fatalf("ListWallets() error = %v", err)
+	}
+	registered := make(map[string]transaction.ULWalletInfo, len(wallets))
+	for _, info := range wallets {
+		registered[info.Address] = info
+	}
+	return registered
+}
+
+func TestBulkAlterWallets(t *testing.T) {
+	_, session := newMockSession(t)
+	admin := session.GetWallet().Address
+	registerSigner(t, session)
+	first := registerWallet(t, session, admin)
+	second := registerWallet(t, session, admin)
+
+	report, err := transaction.BulkAlterWallets(context.Background(), testBlockchainId, []transaction.WalletAlteration{
+		{Session: session, Target: first, Enabled: false},
+		{Session: session, Target: second, Enabled: false},
+	}, transaction.BulkAlterOptions{Rollback: true})
+	if err != nil || !report.Succeeded() {
+		t.Fatalf("BulkAlterWallets() = %+v, %v", report, err)
+	}
+	registered := registeredWallets(t, session)
+	if registered[first].Enabled || registered[second].Enabled {
+		t.Fatal("BulkAlterWallets() left wallets enabled")
+	}
+	if report.Results[0].Previous == nil || !report.Results[0].Previous.Enabled || report.Results[0].TransactionId == "" {
+		t.Fatalf("result = %+v, want the previous state and a transaction id", report.Results[0])
+	}
+}
+
+func TestBulkAlterWalletsValidation(t *testing.T) {
+	node, session := newMockSession(t)
+	target := registerWallet(t, session, "")
+	before := len(node.Transactions())
+
+	_, err := transaction.BulkAlterWallets(context.Background(), testBlockchainId, []transaction.WalletAlteration{
+		{Session: session, Target: target},
+		{Session: session, Target: target},
+		{Session: session, Target: "not-an-address"},
+		{Target: target},
+	}, transaction.BulkAlterOptions{})
+	validation := &transaction.ErrBulkAlterValidation{}
+	if !errors.As(err, &validation) || len(validation.Problems) != 3 {
+		t.Fatalf("BulkAlterWallets() error = %v, want 3 validation problems", err)
+	}
+
+	// The signer is not a registered wallet
+	_, err = transaction.BulkAlterWallets(context.Background(), testBlockchainId, []transaction.WalletAlteration{
+		{Session: session, Target: target},
+	}, transaction.BulkAlterOptions{})
+	if !errors.As(err, &validation) {
+		t.Fatalf("BulkAlterWallets() error = %v, want a validation error", err)
+	}
+	if after := len(node.Transactions()); after != before {
+		t.Fatalf("validation failures submitted %d transactions", after-before)
+	}
+}
+
+func TestBulkAlterWalletsRollback(t *testing.T) {
+	_, session := newMockSession(t)
+	admin := session.GetWallet().Address
+	registerSigner(t, session)
+	first := registerWallet(t, session, admin)
+	second := registerWallet(t, session, admin)
+	third := registerWallet(t, session, admin)
+
+	// The same wallet on a node that went away fails the third alteration after validation
+	gone := transactiontest.NewMockNode(testBlockchainId)
+	unreachable, err := transaction.NewUL_TransactionSession(gone.URL(), session.GetWallet())
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	unreachable.SetRetryPolicy(transaction.RetryPolicy{MaxAttempts: 1})
+	gone.Close()
+
+	report, err := transaction.BulkAlterWallets(context.Background(), testBlockchainId, []transaction.WalletAlteration{
+		{Session: session, Target: first, Enabled: false},
+		{Session: session, Target: second, Enabled: false},
+		{Session: &unreachable, Target: third, Enabled: false},
+	}, transaction.BulkAlterOptions{Rollback: true})
+	if err == nil {
+		t.Fatal("BulkAlterWallets() error = nil, want the third alteration to fail")
+	}
+	if validation := (&transaction.ErrBulkAlterValidation{}); errors.As(err, &validation) {
+		t.Fatalf("BulkAlterWallets() error = %v, want a submission failure", err)
+	}
+	want := []transaction.AlterStatus{transaction.ALTER_STATUS_ROLLED_BACK, transaction.ALTER_STATUS_ROLLED_BACK, transaction.ALTER_STATUS_FAILED}
+	for i, result := range report.Results {
+		if result.Status != want[i] {
+			t.Fatalf("result %d = %+v, want %s", i, result, want[i])
+		}
+	}
+	if report.Results[0].RollbackTransactionId == "" || report.Finished.IsZero() {
+		t.Fatalf("report = %+v, want rollback transaction ids and a finish time", report)
+	}
+	registered := registeredWallets(t, session)
+	for _, address := range []string{first, second, third} {
+		if !registered[address].Enabled {
+			t.Fatalf("wallet %s is still disabled after the rollback", address)
+		}
+	}
+}
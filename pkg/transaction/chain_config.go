@@ -0,0 +1,120 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	// MIN_POLL_INTERVAL bounds PollInterval on chains with very short block times
+	MIN_POLL_INTERVAL = 100 * time.Millisecond
+)
+
+// ChainGenesis holds the parameters the chain was created with
+type ChainGenesis struct {
+	BlockchainId string    `json:"blockchainId"`
+	CreatedAt    time.Time `json:"createdAt"`
+	Hash         string    `json:"hash"`
+	// Creator is the wallet that created the chain, empty for chains configured on the node
+	Creator string `json:"creator"`
+	// Wallets are the wallets registered in the genesis block
+	Wallets []string `json:"wallets"`
+}
+
+type ConsensusConfig struct {
+	Algorithm string `json:"algorithm"`
+	// BlockTimeMs is the target interval between blocks, zero when blocks are only sealed on demand
+	BlockTimeMs          int64 `json:"blockTimeMs"`
+	MaxBlockTransactions int   `json:"maxBlockTransactions"`
+	MaxBlockSize         int   `json:"maxBlockSize"`
+	CommitteeSize        int   `json:"committeeSize"`
+	// Quorum is the number of committee votes needed to seal a block
+	Quorum           int      `json:"quorum"`
+	CommitteeMembers []string `json:"committeeMembers"`
+	// RotationInterval is the number of blocks between committee rotations, zero for a fixed committee
+	RotationInterval int `json:"rotationInterval"`
+}
+
+// BlockTime returns the target interval between blocks
+func (c ConsensusConfig) BlockTime() time.Duration {
+	return time.Duration(c.BlockTimeMs) * time.Millisecond
+}
+
+// FaultTolerance returns the number of faulty committee members the committee survives, (n-1)/3
+func (c ConsensusConfig) FaultTolerance() int {
+	if c.CommitteeSize <= 0 {
+		return 0
+	}
+	return (c.CommitteeSize - 1) / 3
+}
+
+// ChainConfig describes how a chain is run. Partial is set when the node predates the config
+// endpoint and only the committee and features could be read from /health.
+type ChainConfig struct {
+	BlockchainId string          `json:"blockchainId"`
+	NodeVersion  string          `json:"nodeVersion"`
+	Genesis      ChainGenesis    `json:"genesis"`
+	Consensus    ConsensusConfig `json:"consensus"`
+	Features     []string        `json:"features"`
+	Partial      bool            `json:"-"`
+}
+
+// HasFeature reports whether the node advertises an optional capability for the chain
+func (c ChainConfig) HasFeature(feature string) bool {
+	return slices.Contains(c.Features, feature)
+}
+
+// PollInterval suggests how often to poll the chain, half the block time and never below
+// MIN_POLL_INTERVAL. Chains without a block time get DEFAULT_POLL_INTERVAL.
+func (c ChainConfig) PollInterval() time.Duration {
+	blockTime := c.Consensus.BlockTime()
+	if blockTime <= 0 {
+		return DEFAULT_POLL_INTERVAL
+	}
+	return max(blockTime/2, MIN_POLL_INTERVAL)
+}
+
+// GetChainConfig returns the genesis parameters, consensus configuration and features of a chain.
+// Nodes without the config endpoint get a partial config built from /health.
+func (session *UL_TransactionSession) GetChainConfig(ctx context.Context, blockchainId string) (ChainConfig, error) {
+	config := ChainConfig{}
+	err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/config", blockchainId), &config)
+	nodeErr := &NodeError{}
+	if errors.As(err, &nodeErr) && nodeErr.StatusCode == http.StatusNotFound {
+		return session.chainConfigFromHealth(ctx, blockchainId)
+	}
+	if err != nil {
+		return ChainConfig{}, fmt.Errorf("unable to get the config of %s: %w", blockchainId, err)
+	}
+	if config.BlockchainId == "" {
+		config.BlockchainId = blockchainId
+	}
+	return config, nil
+}
+
+// chainConfigFromHealth reads what /health exposes of a chain's configuration
+func (session *UL_TransactionSession) chainConfigFromHealth(ctx context.Context, blockchainId string) (ChainConfig, error) {
+	info := healthInfo{}
+	if err := session.getJson(ctx, "/health", &info); err != nil {
+		return ChainConfig{}, err
+	}
+	chain, ok := info.Chains[blockchainId]
+	if !ok {
+		return ChainConfig{}, fmt.Errorf("blockchain %s is not served by the node", blockchainId)
+	}
+	return ChainConfig{
+		BlockchainId: blockchainId,
+		NodeVersion:  info.Version,
+		Genesis:      ChainGenesis{BlockchainId: blockchainId},
+		Consensus: ConsensusConfig{
+			CommitteeSize:    len(chain.CommitteeMembers),
+			CommitteeMembers: chain.CommitteeMembers,
+		},
+		Features: info.Features,
+		Partial:  true,
+	}, nil
+}
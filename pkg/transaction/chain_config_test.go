@@ -0,0 +1,60 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+)
+
+func TestGetChainConfig(t *testing.T) {
+	node, session := newMockSession(t)
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE)
+
+	config, err := session.GetChainConfig(context.Background(), testBlockchainId)
+	if err != nil {
+		t.Fatalf("GetChainConfig() error = %v", err)
+	}
+	if config.Partial || config.Genesis.Hash == "" || config.Consensus.CommitteeSize != 1 {
+		t.Fatalf("GetChainConfig() = %+v", config)
+	}
+	if !config.HasFeature(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE) {
+		t.Fatalf("GetChainConfig() features = %v", config.Features)
+	}
+	if got := config.PollInterval(); got != transactiontest.MOCK_BLOCK_TIME_MS*time.Millisecond/2 {
+		t.Fatalf("PollInterval() = %v", got)
+	}
+
+	node.SetChainConfig(testBlockchainId, transaction.ChainConfig{
+		Consensus: transaction.ConsensusConfig{BlockTimeMs: 50, CommitteeSize: 7},
+	})
+	config, err = session.GetChainConfig(context.Background(), testBlockchainId)
+	if err != nil {
+		t.Fatalf("GetChainConfig() error = %v", err)
+	}
+	if config.BlockchainId != testBlockchainId || config.PollInterval() != transaction.MIN_POLL_INTERVAL || config.Consensus.FaultTolerance() != 2 {
+		t.Fatalf("GetChainConfig() = %+v", config)
+	}
+
+	if _, err := session.GetChainConfig(context.Background(), "unknown"); err == nil {
+		t.Fatal("GetChainConfig() of an unknown chain succeeded")
+	}
+}
+
+func TestGetChainConfigFromHealth(t *testing.T) {
+	node, session := newMockSession(t)
+	node.DisableChainConfig()
+
+	config, err := session.GetChainConfig(context.Background(), testBlockchainId)
+	if err != nil {
+		t.Fatalf("GetChainConfig() error = %v", err)
+	}
+	if !config.Partial || config.NodeVersion != transactiontest.MOCK_NODE_VERSION || config.Consensus.CommitteeSize != 1 {
+		t.Fatalf("GetChainConfig() = %+v, want a partial config from /health", config)
+	}
+	if config.PollInterval() != transaction.DEFAULT_POLL_INTERVAL {
+		t.Fatalf("PollInterval() = %v", config.PollInterval())
+	}
+}
@@ -0,0 +1,289 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// ErrChainNotManaged is returned by a ChainManager for a chain it has no session for
+type ErrChainNotManaged struct {
+	BlockchainId string
+}
+
+func (e *ErrChainNotManaged) Error() string {
+	return fmt.Sprintf("chain %s is not managed", e.BlockchainId)
+}
+
+func (e *ErrChainNotManaged) Is(target error) bool {
+	return target == utils.ErrNotFound
+}
+
+// ErrChainAlreadyManaged is returned when a chain is added to a ChainManager twice
+type ErrChainAlreadyManaged struct {
+	BlockchainId string
+}
+
+func (e *ErrChainAlreadyManaged) Error() string {
+	return fmt.Sprintf("chain %s is already managed, remove it first", e.BlockchainId)
+}
+
+func (e *ErrChainAlreadyManaged) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// managedChain is a chain of a ChainManager with the config read when it was added
+type managedChain struct {
+	session SessionAPI
+	config  ChainConfig
+}
+
+// ChainManager holds a session per chain for applications spanning several chains, on one node or
+// many, and routes each call to the session of its chain. It is safe for concurrent use.
+type ChainManager struct {
+	mu     sync.RWMutex
+	chains map[string]managedChain
+}
+
+func NewChainManager() *ChainManager {
+	return &ChainManager{chains: make(map[string]managedChain)}
+}
+
+// Add manages a chain through session, reading the config of the chain, which also checks that the
+// node of the session serves it
+func (m *ChainManager) Add(ctx context.Context, blockchainId string, session SessionAPI) error {
+	config, err := session.GetChainConfig(ctx, blockchainId)
+	if err != nil {
+		return fmt.Errorf("failed to read the config of chain %s: %w", blockchainId, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.chains[blockchainId]; ok {
+		return &ErrChainAlreadyManaged{BlockchainId: blockchainId}
+	}
+	m.chains[blockchainId] = managedChain{session: session, config: config}
+	return nil
+}
+
+// AddNode connects to a node and manages every chain it serves that is not managed yet through one
+// session signing with w. It returns the chains added.
+func (m *ChainManager) AddNode(ctx context.Context, nodeEndpoint string, w wallet.UL_Wallet, opts ...SessionOptions) ([]string, error) {
+	options, err := sessionOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	session, err := newSession(ctx, nodeEndpoint, w, options)
+	if err != nil {
+		return nil, err
+	}
+	added := []string{}
+	for _, blockchainId := range session.NodeInfo().Chains {
+		if _, err := m.Config(blockchainId); err == nil {
+			continue
+		}
+		// Another caller may add the chain meanwhile
+		err := m.Add(ctx, blockchainId, &session)
+		if already := (&ErrChainAlreadyManaged{}); errors.As(err, &already) {
+			continue
+		}
+		if err != nil {
+			return added, err
+		}
+		added = append(added, blockchainId)
+	}
+	return added, nil
+}
+
+// Remove stops managing a chain and reports whether it was managed
+func (m *ChainManager) Remove(blockchainId string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.chains[blockchainId]
+	delete(m.chains, blockchainId)
+	return ok
+}
+
+// Chains returns the managed chains in order
+func (m *ChainManager) Chains() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chains := make([]string, 0, len(m.chains))
+	for blockchainId := range m.chains {
+		chains = append(chains, blockchainId)
+	}
+	slices.Sort(chains)
+	return chains
+}
+
+// Session returns the session of a chain
+func (m *ChainManager) Session(blockchainId string) (SessionAPI, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chain, ok := m.chains[blockchainId]
+	if !ok {
+		return nil, &ErrChainNotManaged{BlockchainId: blockchainId}
+	}
+	return chain.session, nil
+}
+
+// Config returns the config of a chain as it was read when the chain was added
+func (m *ChainManager) Config(blockchainId string) (ChainConfig, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	chain, ok := m.chains[blockchainId]
+	if !ok {
+		return ChainConfig{}, &ErrChainNotManaged{BlockchainId: blockchainId}
+	}
+	return chain.config, nil
+}
+
+// GenerateTransaction submits input through the session of input.BlockchainId
+func (m *ChainManager) GenerateTransaction(input ULTransactionInput) (ULTransaction, error) {
+	session, err := m.Session(input.BlockchainId)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	return session.GenerateTransaction(input)
+}
+
+// SubmitAndWait submits input through the session of input.BlockchainId and waits for its outcome
+func (m *ChainManager) SubmitAndWait(ctx context.Context, input ULTransactionInput) (ULTransaction, error) {
+	session, err := m.Session(input.BlockchainId)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	return session.SubmitAndWait(ctx, input)
+}
+
+// ChainResult is the outcome of a query on one chain of a FanOut
+type ChainResult[T any] struct {
+	BlockchainId string
+	Value        T
+	Err          error
+}
+
+// FanOut runs query concurrently on every managed chain and returns the results ordered by chain
+func FanOut[T any](ctx context.Context, m *ChainManager, query func(ctx context.Context, blockchainId string, session SessionAPI) (T, error)) []ChainResult[T] {
+	chains := m.Chains()
+	results := make([]ChainResult[T], len(chains))
+	var wg sync.WaitGroup
+	for i, blockchainId := range chains {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i].BlockchainId = blockchainId
+			session, err := m.Session(blockchainId)
+			if err != nil {
+				results[i].Err = err
+				return
+			}
+			results[i].Value, results[i].Err = query(ctx, blockchainId, session)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// Merge concatenates the values of results in chain order. The chains that failed are left out and
+// their errors joined, so partial results come with an error.
+func Merge[T any](results []ChainResult[[]T]) ([]T, error) {
+	merged := []T{}
+	errs := []error{}
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("chain %s: %w", result.BlockchainId, result.Err))
+			continue
+		}
+		merged = append(merged, result.Value...)
+	}
+	return merged, errors.Join(errs...)
+}
+
+// FindTransaction looks a transaction up on every managed chain and returns the chain holding it.
+// It fails with an error matching utils.ErrNotFound when no chain holds it.
+func (m *ChainManager) FindTransaction(ctx context.Context, transactionId string) (string, ULTransaction, error) {
+	results := FanOut(ctx, m, func(ctx context.Context, blockchainId string, session SessionAPI) (ULTransaction, error) {
+		return session.GetTransaction(ctx, blockchainId, transactionId)
+	})
+	errs := []error{}
+	for _, result := range results {
+		if result.Err == nil {
+			return result.BlockchainId, result.Value, nil
+		}
+		if !errors.Is(result.Err, utils.ErrNotFound) {
+			errs = append(errs, fmt.Errorf("chain %s: %w", result.BlockchainId, result.Err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return "", ULTransaction{}, err
+	}
+	return "", ULTransaction{}, fmt.Errorf("transaction %s: %w", transactionId, utils.ErrNotFound)
+}
+
+// ManagedChainHealth is the state of one managed chain when the manager was checked
+type ManagedChainHealth struct {
+	BlockchainId string        `json:"blockchainId"`
+	Reachable    bool          `json:"reachable"`
+	Height       int           `json:"height"`
+	Latency      time.Duration `json:"latency"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// ChainManagerHealth aggregates the health of the managed chains, ordered by chain
+type ChainManagerHealth struct {
+	CheckedAt time.Time            `json:"checkedAt"`
+	Chains    []ManagedChainHealth `json:"chains"`
+}
+
+// Healthy reports whether every managed chain was reachable
+func (h ChainManagerHealth) Healthy() bool {
+	return len(h.Unreachable()) == 0
+}
+
+// Unreachable lists the chains that could not be read
+func (h ChainManagerHealth) Unreachable() []string {
+	unreachable := []string{}
+	for _, chain := range h.Chains {
+		if !chain.Reachable {
+			unreachable = append(unreachable, chain.BlockchainId)
+		}
+	}
+	return unreachable
+}
+
+// Health reads the height of every managed chain concurrently, each read bounded by timeout,
+// DEFAULT_PROBE_TIMEOUT when zero. Unreachable chains are reported rather than failing the check.
+func (m *ChainManager) Health(ctx context.Context, timeout time.Duration) ChainManagerHealth {
+	if timeout <= 0 {
+		timeout = DEFAULT_PROBE_TIMEOUT
+	}
+	health := ChainManagerHealth{CheckedAt: time.Now().UTC()}
+	type probe struct {
+		height  int
+		latency time.Duration
+	}
+	results := FanOut(ctx, m, func(ctx context.Context, blockchainId string, session SessionAPI) (probe, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		started := time.Now()
+		height, err := session.GetBlockHeight(ctx, blockchainId)
+		return probe{height: height, latency: time.Since(started)}, err
+	})
+	health.Chains = make([]ManagedChainHealth, len(results))
+	for i, result := range results {
+		chain := ManagedChainHealth{BlockchainId: result.BlockchainId, Height: result.Value.height, Latency: result.Value.latency}
+		if result.Err != nil {
+			chain.Error = result.Err.Error()
+		} else {
+			chain.Reachable = true
+		}
+		health.Chains[i] = chain
+	}
+	return health
+}
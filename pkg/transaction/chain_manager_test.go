@@ -0,0 +1,95 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/mocks"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestChainManager(t *testing.T) {
+	ctx := context.Background()
+	first, session := newMockSession(t)
+	second := transactiontest.NewMockNode("chain-b", "chain-c")
+	t.Cleanup(second.Close)
+
+	manager := transaction.NewChainManager()
+	if err := manager.Add(ctx, testBlockchainId, session); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := manager.Add(ctx, testBlockchainId, session); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Add() of a managed chain error = %v", err)
+	}
+	added, err := manager.AddNode(ctx, second.URL(), session.GetWallet())
+	if err != nil || !slices.Equal(added, []string{"chain-b", "chain-c"}) {
+		t.Fatalf("AddNode() = %v, %v", added, err)
+	}
+	if added, err := manager.AddNode(ctx, second.URL(), session.GetWallet()); err != nil || len(added) != 0 {
+		t.Fatalf("AddNode() of a managed node = %v, %v, want nothing added", added, err)
+	}
+	if chains := manager.Chains(); !slices.Equal(chains, []string{testBlockchainId, "chain-b", "chain-c"}) {
+		t.Fatalf("Chains() = %v", chains)
+	}
+	if config, err := manager.Config("chain-c"); err != nil || config.BlockchainId != "chain-c" {
+		t.Fatalf("Config() = %+v, %v", config, err)
+	}
+
+	// Transactions go to the node of their chain
+	tx, err := manager.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: "chain-c",
+		To:           session.GetWallet().Address,
+		Payload:      "routed",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if len(second.Transactions()) != 1 || len(first.Transactions()) != 0 {
+		t.Fatalf("the nodes received %d and %d transactions, want the second node only", len(first.Transactions()), len(second.Transactions()))
+	}
+	if _, err := manager.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: "unknown"}); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GenerateTransaction() on an unmanaged chain error = %v", err)
+	}
+	if chain, found, err := manager.FindTransaction(ctx, tx.TransactionId); err != nil || chain != "chain-c" || found.TransactionId != tx.TransactionId {
+		t.Fatalf("FindTransaction() = %s, %s, %v, want chain-c", chain, found.TransactionId, err)
+	}
+	if _, _, err := manager.FindTransaction(ctx, "missing"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("FindTransaction() of a missing transaction error = %v", err)
+	}
+
+	// A failing chain is reported without hiding the others
+	down := errors.New("connection refused")
+	broken := &mocks.Session{
+		GetChainConfigFunc: func(ctx context.Context, blockchainId string) (transaction.ChainConfig, error) {
+			return transaction.ChainConfig{BlockchainId: blockchainId}, nil
+		},
+		GetBlockHeightFunc: func(ctx context.Context, blockchainId string) (int, error) { return 0, down },
+	}
+	if err := manager.Add(ctx, "chain-d", broken); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	results := transaction.FanOut(ctx, manager, func(ctx context.Context, blockchainId string, session transaction.SessionAPI) ([]string, error) {
+		_, err := session.GetBlockHeight(ctx, blockchainId)
+		return []string{blockchainId}, err
+	})
+	merged, err := transaction.Merge(results)
+	if !errors.Is(err, down) || !slices.Equal(merged, []string{testBlockchainId, "chain-b", "chain-c"}) {
+		t.Fatalf("Merge() = %v, %v, want the three reachable chains", merged, err)
+	}
+	health := manager.Health(ctx, 0)
+	if health.Healthy() || !slices.Equal(health.Unreachable(), []string{"chain-d"}) || len(health.Chains) != 4 {
+		t.Fatalf("Health() = %+v, want chain-d unreachable", health)
+	}
+
+	if !manager.Remove("chain-d") || manager.Remove("chain-d") {
+		t.Fatal("Remove() did not report the managed chain once")
+	}
+	if health := manager.Health(ctx, 0); !health.Healthy() {
+		t.Fatalf("Health() = %+v, want every chain reachable", health)
+	}
+}
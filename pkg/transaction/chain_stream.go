@@ -0,0 +1,267 @@
+package transaction
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_EVENT_STREAM is advertised by nodes pushing the changes of their chains as
+// server-sent events, see StreamChain
+const NODE_FEATURE_EVENT_STREAM = "event-stream"
+
+const (
+	DEFAULT_RECONNECT_DELAY     = time.Second
+	DEFAULT_MAX_RECONNECT_DELAY = 30 * time.Second
+)
+
+// ErrEventStreamUnsupported is returned by StreamChain when the node does not advertise
+// NODE_FEATURE_EVENT_STREAM, SubscribeBlocks polls any node instead
+type ErrEventStreamUnsupported struct{}
+
+func (e *ErrEventStreamUnsupported) Error() string {
+	return "the node does not stream chain events"
+}
+
+func (e *ErrEventStreamUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+type ChainEventType string
+
+const (
+	// CHAIN_EVENT_BLOCK is delivered for every block the node seals, including the blocks
+	// replacing reorged ones
+	CHAIN_EVENT_BLOCK ChainEventType = "block"
+	// CHAIN_EVENT_TRANSACTION confirms a transaction of a sealed block, after the block
+	CHAIN_EVENT_TRANSACTION ChainEventType = "transaction"
+	// CHAIN_EVENT_REORG is delivered when blocks are replaced, before the blocks replacing them
+	CHAIN_EVENT_REORG ChainEventType = "reorg"
+)
+
+// ChainReorg describes blocks the node replaced. Transactions of the replaced blocks are
+// confirmed again by the blocks replacing them, or not at all if they were dropped.
+type ChainReorg struct {
+	// Height is the first replaced block, Hash its hash before the reorg
+	Height int    `json:"height"`
+	Hash   string `json:"hash"`
+	// Depth is the number of blocks replaced
+	Depth int `json:"depth"`
+}
+
+// ChainEvent is a change of a chain pushed by the node
+type ChainEvent struct {
+	Type         ChainEventType
+	BlockchainId string
+	// Id is the position of the event in the stream of the node, see StreamOptions.LastEventId
+	Id string
+	// Block is set for CHAIN_EVENT_BLOCK
+	Block ULBlock
+	// Transaction is set for CHAIN_EVENT_TRANSACTION, its BlockHeight is the block sealing it
+	Transaction ULTransaction
+	// Reorg is set for CHAIN_EVENT_REORG
+	Reorg ChainReorg
+}
+
+type StreamOptions struct {
+	// LastEventId resumes the stream after an event delivered by an earlier stream, the stream
+	// starts with the next change of the chain otherwise
+	LastEventId string
+	// ReconnectDelay is the wait before reconnecting a dropped stream, doubled up to
+	// MaxReconnectDelay while reconnecting fails. The node may ask for another delay.
+	ReconnectDelay    time.Duration
+	MaxReconnectDelay time.Duration
+	// OnError is told about dropped connections and events that cannot be read, which are skipped
+	OnError func(err error)
+}
+
+// StreamChain delivers the blocks, transaction confirmations and reorgs of a chain as the node
+// pushes them until the context is cancelled and the channel is closed. Dropped connections are
+// reopened after the last delivered event, so no event is missed or delivered twice as long as
+// the node still holds it. Nodes must advertise NODE_FEATURE_EVENT_STREAM.
+func (session *UL_TransactionSession) StreamChain(ctx context.Context, blockchainId string, opts StreamOptions) (<-chan ChainEvent, error) {
+	supported, err := session.hasFeature(ctx, NODE_FEATURE_EVENT_STREAM)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, &ErrEventStreamUnsupported{}
+	}
+	if opts.ReconnectDelay <= 0 {
+		opts.ReconnectDelay = DEFAULT_RECONNECT_DELAY
+	}
+	if opts.MaxReconnectDelay <= 0 {
+		opts.MaxReconnectDelay = DEFAULT_MAX_RECONNECT_DELAY
+	}
+	opts.MaxReconnectDelay = max(opts.MaxReconnectDelay, opts.ReconnectDelay)
+
+	stream := &chainStream{
+		session:      session,
+		blockchainId: blockchainId,
+		opts:         opts,
+		events:       make(chan ChainEvent, 16),
+		lastEventId:  opts.LastEventId,
+		retry:        opts.ReconnectDelay,
+	}
+	go stream.run(ctx)
+	return stream.events, nil
+}
+
+type chainStream struct {
+	session      *UL_TransactionSession
+	blockchainId string
+	opts         StreamOptions
+	events       chan ChainEvent
+	lastEventId  string
+	// retry is the reconnect delay after a connection that delivered events
+	retry time.Duration
+}
+
+// serverSentEvent is an event of the stream before it is decoded
+type serverSentEvent struct {
+	id    string
+	event string
+	data  []string
+}
+
+func (stream *chainStream) run(ctx context.Context) {
+	defer close(stream.events)
+	delay := stream.retry
+	for {
+		delivered, err := stream.connect(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if stream.opts.OnError != nil {
+			stream.opts.OnError(err)
+		}
+		if delivered {
+			delay = stream.retry
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, stream.opts.MaxReconnectDelay)
+	}
+}
+
+// connect reads the events of one connection until it breaks and reports whether it delivered any
+func (stream *chainStream) connect(ctx context.Context) (bool, error) {
+	path := fmt.Sprintf("/blockchains/%s/events", stream.blockchainId)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s%s", stream.session.nodeEndpoint, path), nil)
+	if err != nil {
+		return false, err
+	}
+	for key, values := range stream.session.headers {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	if stream.lastEventId != "" {
+		req.Header.Set("Last-Event-ID", stream.lastEventId)
+	}
+
+	// The stream stays open for as long as the chain lives, the client timeout is meant for requests
+	client := *stream.session.client()
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return false, classifyNodeError(&NodeError{StatusCode: resp.StatusCode, Method: http.MethodGet, Path: path, Body: string(body)})
+	}
+
+	delivered := false
+	reader := bufio.NewReader(resp.Body)
+	event := serverSentEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err == io.EOF {
+			return delivered, fmt.Errorf("event stream of %s closed by the node: %w", stream.blockchainId, io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return delivered, err
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			sent, err := stream.dispatch(ctx, event)
+			if err != nil {
+				return delivered, err
+			}
+			delivered = delivered || sent
+			event = serverSentEvent{}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			// Comments keep idle connections open
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			event.id = value
+		case "event":
+			event.event = value
+		case "data":
+			event.data = append(event.data, value)
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms > 0 {
+				stream.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// dispatch decodes an event and delivers it, it reports whether the event was delivered. Events of
+// unknown types are skipped so that nodes can add types.
+func (stream *chainStream) dispatch(ctx context.Context, sse serverSentEvent) (bool, error) {
+	if sse.id != "" {
+		stream.lastEventId = sse.id
+	}
+	if len(sse.data) == 0 {
+		return false, nil
+	}
+
+	event := ChainEvent{Type: ChainEventType(sse.event), BlockchainId: stream.blockchainId, Id: sse.id}
+	data := []byte(strings.Join(sse.data, "\n"))
+	var err error
+	switch event.Type {
+	case CHAIN_EVENT_BLOCK:
+		err = json.Unmarshal(data, &event.Block)
+	case CHAIN_EVENT_TRANSACTION:
+		err = json.Unmarshal(data, &event.Transaction)
+	case CHAIN_EVENT_REORG:
+		err = json.Unmarshal(data, &event.Reorg)
+	default:
+		return false, nil
+	}
+	if err != nil {
+		if stream.opts.OnError != nil {
+			stream.opts.OnError(&utils.ErrMalformed{What: fmt.Sprintf("%s event %s", event.Type, sse.id), Msg: err.Error()})
+		}
+		return false, nil
+	}
+
+	select {
+	case stream.events <- event:
+		return true, nil
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+}
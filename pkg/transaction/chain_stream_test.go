@@ -0,0 +1,86 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestStreamChain(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := session.StreamChain(ctx, testBlockchainId, transaction.StreamOptions{}); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("StreamChain() without event streams error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_EVENT_STREAM)
+	tx := submitData(t, session, "payment")
+
+	errs := make(chan error, 16)
+	events, err := session.StreamChain(ctx, testBlockchainId, transaction.StreamOptions{
+		LastEventId:    "0",
+		ReconnectDelay: 10 * time.Millisecond,
+		OnError:        func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatalf("StreamChain() error = %v", err)
+	}
+	next := func() transaction.ChainEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event streamed")
+			return transaction.ChainEvent{}
+		}
+	}
+
+	sealed := next()
+	if sealed.Type != transaction.CHAIN_EVENT_BLOCK || sealed.BlockchainId != testBlockchainId || sealed.Block.Height != tx.BlockHeight || !sealed.Block.ContainsTransaction(tx.TransactionId) {
+		t.Fatalf("first event = %+v, want the block of %s", sealed, tx.TransactionId)
+	}
+	if event := next(); event.Type != transaction.CHAIN_EVENT_TRANSACTION || event.Transaction.TransactionId != tx.TransactionId || event.Transaction.BlockHeight != tx.BlockHeight {
+		t.Fatalf("second event = %+v, want the confirmation of %s", event, tx.TransactionId)
+	}
+
+	if err := node.ReplaceBlock(testBlockchainId, tx.BlockHeight, true); err != nil {
+		t.Fatalf("ReplaceBlock() error = %v", err)
+	}
+	want := transaction.ChainReorg{Height: tx.BlockHeight, Hash: sealed.Block.Hash, Depth: 1}
+	if event := next(); event.Type != transaction.CHAIN_EVENT_REORG || event.Reorg != want {
+		t.Fatalf("event after ReplaceBlock() = %+v, want reorg %+v", event, want)
+	}
+	replacement := next()
+	if replacement.Type != transaction.CHAIN_EVENT_BLOCK || replacement.Block.Height != tx.BlockHeight || replacement.Block.Hash == sealed.Block.Hash || len(replacement.Block.Transactions) != 0 {
+		t.Fatalf("event after the reorg = %+v, want the empty replacement block", replacement)
+	}
+
+	// The stream resumes after the last event once the node drops it
+	node.DropStreams()
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Fatal("OnError() called without an error")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnError() not told about the dropped stream")
+	}
+	height := node.AppendEmptyBlock(testBlockchainId)
+	if event := next(); event.Type != transaction.CHAIN_EVENT_BLOCK || event.Block.Height != height || event.Block.PreviousBlockHash != replacement.Block.Hash {
+		t.Fatalf("event after reconnecting = %+v, want block %d", event, height)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("unexpected event after reconnecting %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	for range events {
+	}
+}
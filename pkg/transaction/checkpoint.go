@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Checkpoint is the last block a consumer finished processing
+type Checkpoint struct {
+	Height    int       `json:"height"`
+	Hash      string    `json:"hash"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// CheckpointStore persists the progress of block consumers. Keys are opaque strings made of
+// the blockchain id and the consumer name, so several consumers can share a store.
+type CheckpointStore interface {
+	LoadCheckpoint(key string) (Checkpoint, bool, error)
+	SaveCheckpoint(key string, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps checkpoints for the lifetime of the process
+type MemoryCheckpointStore struct {
+	mu          sync.Mutex
+	checkpoints map[string]Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{checkpoints: make(map[string]Checkpoint)}
+}
+
+func (store *MemoryCheckpointStore) LoadCheckpoint(key string) (Checkpoint, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	checkpoint, ok := store.checkpoints[key]
+	return checkpoint, ok, nil
+}
+
+func (store *MemoryCheckpointStore) SaveCheckpoint(key string, checkpoint Checkpoint) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.checkpoints[key] = checkpoint
+	return nil
+}
+
+// FileCheckpointStore writes one JSON file per consumer inside a directory, surviving restarts
+type FileCheckpointStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+func (store *FileCheckpointStore) path(key string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(key)
+	return filepath.Join(store.dir, name+".checkpoint.json")
+}
+
+func (store *FileCheckpointStore) LoadCheckpoint(key string) (Checkpoint, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := os.ReadFile(store.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return Checkpoint{}, false, fmt.Errorf("failed to read checkpoint: %w", err)
+	}
+
+	checkpoint := Checkpoint{}
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return Checkpoint{}, false, fmt.Errorf("failed to parse checkpoint: %w", err)
+	}
+	return checkpoint, true, nil
+}
+
+func (store *FileCheckpointStore) SaveCheckpoint(key string, checkpoint Checkpoint) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoint: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half written checkpoint behind
+	path := store.path(key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit checkpoint: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// QueryAPI reads the state of a chain without signing anything
+type QueryAPI interface {
+	GetChainConfig(ctx context.Context, blockchainId string) (ChainConfig, error)
+	GetBlockHeight(ctx context.Context, blockchainId string) (int, error)
+	GetBlock(ctx context.Context, blockchainId string, height int) (ULBlock, error)
+	GetBlockByHeight(ctx context.Context, blockchainId string, height int) (ULBlock, error)
+	GetBlockByHash(ctx context.Context, blockchainId string, hash string) (ULBlock, error)
+	GetLatestBlock(ctx context.Context, blockchainId string) (ULBlock, error)
+	GetTransaction(ctx context.Context, blockchainId string, transactionId string) (ULTransaction, error)
+	GetFinality(ctx context.Context, blockchainId string, transactionId string) (Finality, error)
+	WaitForTransaction(ctx context.Context, blockchainId string, transactionId string, opts WaitOptions) (ULTransaction, error)
+	PendingTransactions(ctx context.Context, blockchainId string) ([]string, error)
+	ListBlocks(blockchainId string, opts ListOptions) *Iterator[ULBlock]
+	ListTransactions(blockchainId string, filter TransactionFilter, opts ListOptions) *Iterator[ULTransaction]
+	ListTokens(blockchainId string, opts ListOptions) *Iterator[ULToken]
+	ListWallets(blockchainId string, parent string, opts ListOptions) *Iterator[ULWalletInfo]
+	GetWalletState(ctx context.Context, blockchainId string, address string) (WalletState, error)
+	DiscoverWallets(ctx context.Context, blockchainId string, hd *wallet.HDWallet, gapLimit int) ([]wallet.DerivedWallet, error)
+	SubscribeBlocks(ctx context.Context, blockchainId string, opts BlockSubscriptionOptions, handler BlockHandler) error
+	StreamChain(ctx context.Context, blockchainId string, opts StreamOptions) (<-chan ChainEvent, error)
+}
+
+// TokenAPI reads token state and sends the token operations that need more than a payload
+type TokenAPI interface {
+	GetTokenIdInfo(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (ULTokenIdInfo, error)
+	GetTokenSupply(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (Amount, error)
+	GetTokenURI(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (string, error)
+	ListTokenHolders(blockchainId string, tokenAddress string, tokenId uint64, opts ListOptions) *Iterator[ULTokenHolder]
+	IsApprovedForAll(ctx context.Context, blockchainId string, tokenAddress string, owner string, operator string) (bool, error)
+	SetApprovalForAll(ctx context.Context, blockchainId string, tokenAddress string, operator string, approved bool) (ULTransaction, error)
+	OperatorTransfer(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenIds []uint64, amounts []Amount) (ULTransaction, error)
+	OperatorTransferNFT(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenId uint64) (ULTransaction, error)
+	Burn(ctx context.Context, blockchainId string, tokenAddress string, amount Amount, tokenId uint64) (ULTransaction, error)
+	BurnFrom(ctx context.Context, blockchainId string, tokenAddress string, from string, amount Amount, tokenId uint64) (ULTransaction, error)
+}
+
+// ContractAPI uploads and deploys smart contracts, calls their read-only functions and follows
+// their events, invocations are plain transactions
+type ContractAPI interface {
+	UploadContractSource(ctx context.Context, blockchainId string, source []byte, opts ContractUploadOptions) (ContractSource, error)
+	DeployContract(ctx context.Context, blockchainId string, source []byte, opts ContractUploadOptions) (ULTransaction, error)
+	CallContract(ctx context.Context, blockchainId string, contractAddress string, functionName string, args ...any) (interface{}, error)
+	EstimateGas(ctx context.Context, blockchainId string, contractAddress string, payload InvokeContractPayload, opts GasEstimateOptions) (GasEstimate, error)
+	SubscribeContractEvents(ctx context.Context, blockchainId string, contractAddress string, fromBlock int, opts ContractEventOptions) (<-chan ContractEvent, error)
+}
+
+// ERC20API is the client of one ERC20 token, implemented by ERC20Client
+type ERC20API interface {
+	TokenAddress() string
+	CreateToken(ctx context.Context, payload CreateTokenPayload) (ULTransaction, error)
+	Transfer(ctx context.Context, to string, amount Amount) (ULTransaction, error)
+	Approve(ctx context.Context, spender string, amount Amount) (ULTransaction, error)
+	TransferFrom(ctx context.Context, from string, to string, amount Amount) (ULTransaction, error)
+	Mint(ctx context.Context, to string, amount Amount) (ULTransaction, error)
+	Burn(ctx context.Context, amount Amount) (ULTransaction, error)
+	BalanceOf(ctx context.Context, owner string) (Amount, error)
+	Allowance(ctx context.Context, owner string, spender string) (Amount, error)
+}
+
+// ERC721API is the client of one ERC721 token, implemented by ERC721Client
+type ERC721API interface {
+	TokenAddress() string
+	CreateToken(ctx context.Context, payload CreateTokenPayload) (ULTransaction, error)
+	Mint(ctx context.Context, to string, tokenId uint64, tokenURI string) (ULTransaction, error)
+	TransferFrom(ctx context.Context, from string, to string, tokenId uint64) (ULTransaction, error)
+	Approve(ctx context.Context, spender string, tokenId uint64) (ULTransaction, error)
+	SetApprovalForAll(ctx context.Context, operator string, approved bool) (ULTransaction, error)
+	Burn(ctx context.Context, tokenId uint64) (ULTransaction, error)
+	OwnerOf(ctx context.Context, tokenId uint64) (string, error)
+	BalanceOf(ctx context.Context, owner string) (uint64, error)
+	GetApproved(ctx context.Context, tokenId uint64) (string, error)
+	TokenURI(ctx context.Context, tokenId uint64) (string, error)
+}
+
+// ContractClientAPI is the client of one deployed contract, implemented by ContractClient
+type ContractClientAPI interface {
+	ContractAddress() string
+	Invoke(ctx context.Context, functionName string, gasLimit uint64, args ...any) (ULTransaction, error)
+	Call(ctx context.Context, functionName string, args ...any) (interface{}, error)
+	EstimateGas(ctx context.Context, functionName string, args ...any) (GasEstimate, error)
+	Events(ctx context.Context, fromBlock int, opts ContractEventOptions) (<-chan ContractEvent, error)
+}
+
+// SessionAPI is everything a UL_TransactionSession offers once configured. Code depending on it
+// rather than on the session can be unit tested with the mocks package, without a node.
+type SessionAPI interface {
+	QueryAPI
+	TokenAPI
+	ContractAPI
+	GetWallet() wallet.UL_Wallet
+	GetSuggestor() string
+	GenerateTransaction(input ULTransactionInput) (ULTransaction, error)
+	GenerateIdempotentTransaction(ctx context.Context, idempotencyKey string, input ULTransactionInput) (ULTransaction, error)
+	SubmitAndWait(ctx context.Context, input ULTransactionInput) (ULTransaction, error)
+}
+
+var (
+	_ SessionAPI        = (*UL_TransactionSession)(nil)
+	_ ERC20API          = (*ERC20Client)(nil)
+	_ ERC721API         = (*ERC721Client)(nil)
+	_ ContractClientAPI = (*ContractClient)(nil)
+)
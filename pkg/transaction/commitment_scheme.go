@@ -0,0 +1,234 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	// COMMITMENT_SCHEME_MIMC builds the payload tree with the MiMC hasher of the key type, every node
+	// verifies it and it is used when ULTransactionInput.CommitmentScheme is empty
+	COMMITMENT_SCHEME_MIMC = "mimc"
+	// COMMITMENT_SCHEME_SHA256_PARALLEL builds a binary SHA-256 tree over SHA256_CHUNK_SIZE byte chunks,
+	// hashing the chunks and the levels of large payloads in parallel. It is much faster than MiMC for
+	// big payloads but only nodes advertising NODE_FEATURE_COMMITMENT_SHA256_PARALLEL verify it.
+	COMMITMENT_SCHEME_SHA256_PARALLEL = "sha256-parallel"
+
+	NODE_FEATURE_COMMITMENT_SHA256_PARALLEL = "commitment-sha256-parallel"
+
+	// SHA256_CHUNK_SIZE is the leaf size of COMMITMENT_SCHEME_SHA256_PARALLEL, a chunk and its domain
+	// byte fit a single SHA-256 block
+	SHA256_CHUNK_SIZE = 32
+	// PARALLEL_HASH_MIN_LEAVES is the tree level size from which its hashes are split across workers,
+	// smaller levels are hashed faster than goroutines start
+	PARALLEL_HASH_MIN_LEAVES = 1024
+)
+
+// Domain separation bytes of the SHA-256 payload tree, leaves and inner nodes can never collide and
+// the root binds the payload length so trailing zero bytes change it
+const (
+	sha256LeafPrefix   = 0x00
+	sha256NodePrefix   = 0x01
+	sha256LengthPrefix = 0x02
+)
+
+// ErrUnsupportedCommitmentScheme is returned for schemes that are not registered or that the node
+// does not verify
+type ErrUnsupportedCommitmentScheme struct {
+	Msg string
+}
+
+func (e *ErrUnsupportedCommitmentScheme) Error() string {
+	return fmt.Sprintf("unsupported commitment scheme, %s", e.Msg)
+}
+
+func (e *ErrUnsupportedCommitmentScheme) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// CommitmentScheme computes the payload root of the signature commitment. The root of bound payload
+// types is hashed with the transaction fields by the hasher of the key type, unbound payload types
+// sign the root itself, see SigningCommitment.
+type CommitmentScheme interface {
+	Name() string
+	// NodeFeature is the feature nodes advertise on /health when they verify the scheme, empty when
+	// every node does
+	NodeFeature() string
+	// PayloadRoot returns the root of payload, bound reports whether the payload type is limited to
+	// MAX_BOUND_PAYLOAD_SIZE
+	PayloadRoot(payload []byte, keyType crypto.KeyType, bound bool) ([]byte, error)
+}
+
+var commitmentSchemes = struct {
+	sync.RWMutex
+	schemes map[string]CommitmentScheme
+}{schemes: map[string]CommitmentScheme{
+	COMMITMENT_SCHEME_MIMC:            mimcScheme{},
+	COMMITMENT_SCHEME_SHA256_PARALLEL: sha256TreeScheme{},
+}}
+
+// RegisterCommitmentScheme makes a scheme selectable by name, names cannot be registered twice
+func RegisterCommitmentScheme(scheme CommitmentScheme) error {
+	commitmentSchemes.Lock()
+	defer commitmentSchemes.Unlock()
+	if scheme.Name() == "" {
+		return fmt.Errorf("commitment schemes need a name")
+	}
+	if _, ok := commitmentSchemes.schemes[scheme.Name()]; ok {
+		return fmt.Errorf("commitment scheme %s is already registered", scheme.Name())
+	}
+	commitmentSchemes.schemes[scheme.Name()] = scheme
+	return nil
+}
+
+// GetCommitmentScheme returns a registered scheme, the empty name is COMMITMENT_SCHEME_MIMC
+func GetCommitmentScheme(name string) (CommitmentScheme, error) {
+	if name == "" {
+		name = COMMITMENT_SCHEME_MIMC
+	}
+	commitmentSchemes.RLock()
+	defer commitmentSchemes.RUnlock()
+	scheme, ok := commitmentSchemes.schemes[name]
+	if !ok {
+		return nil, &ErrUnsupportedCommitmentScheme{Msg: fmt.Sprintf("%q is not registered", name)}
+	}
+	return scheme, nil
+}
+
+// CommitmentSchemes returns the names of the registered schemes
+func CommitmentSchemes() []string {
+	commitmentSchemes.RLock()
+	defer commitmentSchemes.RUnlock()
+	names := make([]string, 0, len(commitmentSchemes.schemes))
+	for name := range commitmentSchemes.schemes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// SetCommitmentScheme selects the scheme of the transactions the session generates, inputs naming
+// their own CommitmentScheme keep it. Schemes needing a node feature fail with
+// ErrUnsupportedCommitmentScheme when the node does not advertise it.
+func (session *UL_TransactionSession) SetCommitmentScheme(ctx context.Context, name string) error {
+	scheme, err := GetCommitmentScheme(name)
+	if err != nil {
+		return err
+	}
+	if feature := scheme.NodeFeature(); feature != "" {
+		supported, err := session.hasFeature(ctx, feature)
+		if err != nil {
+			return err
+		}
+		if !supported {
+			return &ErrUnsupportedCommitmentScheme{Msg: fmt.Sprintf("the node does not advertise %s", feature)}
+		}
+	}
+	// The default is left out of the input so nodes predating the field accept it
+	session.commitmentScheme = ""
+	if scheme.Name() != COMMITMENT_SCHEME_MIMC {
+		session.commitmentScheme = scheme.Name()
+	}
+	return nil
+}
+
+type mimcScheme struct{}
+
+func (mimcScheme) Name() string        { return COMMITMENT_SCHEME_MIMC }
+func (mimcScheme) NodeFeature() string { return "" }
+
+func (mimcScheme) PayloadRoot(payload []byte, keyType crypto.KeyType, bound bool) ([]byte, error) {
+	field := ECDSA_CURVE
+	if keyType == crypto.KeyTypeBLS12377 {
+		field = BLS_CURVE
+	}
+	hasher := crypto.GetHasherByType(keyType)
+	if bound {
+		root, _, _, _, err := GenerateMerkleTreeWithHardBound(payload, field, CHUNK_SIZE, DEPTH, hasher, uint64(0))
+		return root, err
+	}
+	root, _, _, _, _, err := GenerateMerkleTree(payload, field, CHUNK_SIZE, hasher, uint64(0))
+	return root, err
+}
+
+type sha256TreeScheme struct{}
+
+func (sha256TreeScheme) Name() string        { return COMMITMENT_SCHEME_SHA256_PARALLEL }
+func (sha256TreeScheme) NodeFeature() string { return NODE_FEATURE_COMMITMENT_SHA256_PARALLEL }
+
+func (sha256TreeScheme) PayloadRoot(payload []byte, keyType crypto.KeyType, bound bool) ([]byte, error) {
+	return Sha256PayloadRoot(payload, runtime.GOMAXPROCS(0)), nil
+}
+
+// Sha256PayloadRoot is the root of COMMITMENT_SCHEME_SHA256_PARALLEL, computed by up to workers
+// goroutines. Leaves are SHA-256(0x00 || chunk) of SHA256_CHUNK_SIZE byte chunks, the last one zero
+// padded, and an empty payload is a single zero chunk. Inner nodes are SHA-256(0x01 || left || right),
+// the last node of an odd level is promoted unchanged. The root is SHA-256(0x02 || length || tree root)
+// with the payload length as a big endian uint64.
+func Sha256PayloadRoot(payload []byte, workers int) []byte {
+	numLeaves := max(1, (len(payload)+SHA256_CHUNK_SIZE-1)/SHA256_CHUNK_SIZE)
+	level := make([][sha256.Size]byte, numLeaves)
+	parallelRange(numLeaves, workers, func(lo, hi int) {
+		var block [1 + SHA256_CHUNK_SIZE]byte
+		block[0] = sha256LeafPrefix
+		for i := lo; i < hi; i++ {
+			start := min(i*SHA256_CHUNK_SIZE, len(payload))
+			n := copy(block[1:], payload[start:min(start+SHA256_CHUNK_SIZE, len(payload))])
+			clear(block[1+n:])
+			level[i] = sha256.Sum256(block[:])
+		}
+	})
+
+	next := make([][sha256.Size]byte, (numLeaves+1)/2)
+	for len(level) > 1 {
+		parents := next[:(len(level)+1)/2]
+		parallelRange(len(parents), workers, func(lo, hi int) {
+			var block [1 + 2*sha256.Size]byte
+			block[0] = sha256NodePrefix
+			for i := lo; i < hi; i++ {
+				if 2*i+1 == len(level) {
+					parents[i] = level[2*i]
+					continue
+				}
+				copy(block[1:], level[2*i][:])
+				copy(block[1+sha256.Size:], level[2*i+1][:])
+				parents[i] = sha256.Sum256(block[:])
+			}
+		})
+		// The old level becomes the buffer of the next one, it is at least as long
+		level, next = parents, level
+	}
+
+	var block [1 + 8 + sha256.Size]byte
+	block[0] = sha256LengthPrefix
+	binary.BigEndian.PutUint64(block[1:], uint64(len(payload)))
+	copy(block[9:], level[0][:])
+	root := sha256.Sum256(block[:])
+	return root[:]
+}
+
+// parallelRange calls fn over [0, n) split into one range per worker, or once for small n
+func parallelRange(n int, workers int, fn func(lo, hi int)) {
+	if workers <= 1 || n < PARALLEL_HASH_MIN_LEAVES {
+		fn(0, n)
+		return
+	}
+	step := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += step {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(lo, min(lo+step, n))
+		}()
+	}
+	wg.Wait()
+}
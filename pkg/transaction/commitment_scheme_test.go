@@ -0,0 +1,88 @@
+package transaction_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestSha256PayloadRoot(t *testing.T) {
+	// Two chunks, the second one zero padded
+	payload := []byte(strings.Repeat("a", transaction.SHA256_CHUNK_SIZE) + "b")
+	leaf := func(chunk []byte) []byte {
+		block := append([]byte{0x00}, chunk...)
+		block = append(block, make([]byte, 1+transaction.SHA256_CHUNK_SIZE-len(block))...)
+		sum := sha256.Sum256(block)
+		return sum[:]
+	}
+	left, right := leaf(payload[:transaction.SHA256_CHUNK_SIZE]), leaf(payload[transaction.SHA256_CHUNK_SIZE:])
+	tree := sha256.Sum256(append(append([]byte{0x01}, left...), right...))
+	want := sha256.Sum256(append(append([]byte{0x02}, binary.BigEndian.AppendUint64(nil, uint64(len(payload)))...), tree[:]...))
+	if got := transaction.Sha256PayloadRoot(payload, 1); !bytes.Equal(got, want[:]) {
+		t.Fatalf("Sha256PayloadRoot() = %x, want %x", got, want)
+	}
+
+	// Trailing zeros change the root even though they fill the same chunks
+	if bytes.Equal(transaction.Sha256PayloadRoot([]byte("ab"), 1), transaction.Sha256PayloadRoot([]byte("ab\x00"), 1)) {
+		t.Fatal("Sha256PayloadRoot() ignores the payload length")
+	}
+	// Parallel hashing yields the sequential root, including levels of odd sizes
+	large := bytes.Repeat([]byte("0123456789"), 70_001)
+	if sequential := transaction.Sha256PayloadRoot(large, 1); !bytes.Equal(transaction.Sha256PayloadRoot(large, 7), sequential) {
+		t.Fatal("Sha256PayloadRoot() with 7 workers differs from the sequential root")
+	}
+}
+
+func TestCommitmentScheme(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	schemeErr := &transaction.ErrUnsupportedCommitmentScheme{}
+	if err := session.SetCommitmentScheme(ctx, "blake3"); !errors.As(err, &schemeErr) {
+		t.Fatalf("SetCommitmentScheme() of an unregistered scheme error = %v", err)
+	}
+	if err := session.SetCommitmentScheme(ctx, transaction.COMMITMENT_SCHEME_SHA256_PARALLEL); !errors.As(err, &schemeErr) {
+		t.Fatalf("SetCommitmentScheme() without node support error = %v", err)
+	}
+	if tx := submitData(t, session, "mimc"); tx.CommitmentScheme != "" {
+		t.Fatalf("default commitment scheme = %q", tx.CommitmentScheme)
+	}
+
+	node.SetFeatures(transaction.NODE_FEATURE_COMMITMENT_SHA256_PARALLEL)
+	if err := session.SetCommitmentScheme(ctx, transaction.COMMITMENT_SCHEME_SHA256_PARALLEL); err != nil {
+		t.Fatalf("SetCommitmentScheme() error = %v", err)
+	}
+	w := session.GetWallet()
+	publicKey := w.GetKey().GetPublicKeyHex(false)
+	for _, input := range []transaction.ULTransactionInput{
+		{BlockchainId: testBlockchainId, To: session.GetWallet().Address, Payload: "anchored", PayloadType: transaction.TX_DATA.String()},
+		// Only the MiMC hard bound tree limits bound payloads to MAX_BOUND_PAYLOAD_SIZE
+		{BlockchainId: testBlockchainId, To: session.GetWallet().Address, Payload: strings.Repeat("a", 4*transaction.MAX_BOUND_PAYLOAD_SIZE+1), PayloadType: transaction.TX_DATA.String()},
+		{BlockchainId: testBlockchainId, Payload: strings.Repeat("contract", 8<<10), PayloadType: transaction.DEPLOY_SMART_CONTRACT.String()},
+	} {
+		tx, err := session.GenerateTransaction(input)
+		if err != nil {
+			t.Fatalf("GenerateTransaction(%s) error = %v", input.PayloadType, err)
+		}
+		if tx.CommitmentScheme != transaction.COMMITMENT_SCHEME_SHA256_PARALLEL || tx.PayloadRoot != crypto.BytesToHex(transaction.Sha256PayloadRoot([]byte(input.Payload), 1)) {
+			t.Fatalf("%s scheme %q, payload root %s", input.PayloadType, tx.CommitmentScheme, tx.PayloadRoot)
+		}
+		if decoded := transaction.DecodeTransaction(tx, publicKey); !decoded.Commitment.Valid || !decoded.Signature.Valid {
+			t.Fatalf("DecodeTransaction(%s) = %+v, %+v", input.PayloadType, decoded.Commitment, decoded.Signature)
+		}
+	}
+
+	mimc, err := transaction.GetCommitmentScheme("")
+	if err != nil || mimc.Name() != transaction.COMMITMENT_SCHEME_MIMC {
+		t.Fatalf("GetCommitmentScheme() of the default = %v, %v", mimc, err)
+	}
+	if err := transaction.RegisterCommitmentScheme(mimc); err == nil {
+		t.Fatal("RegisterCommitmentScheme() replaced a registered scheme")
+	}
+}
@@ -0,0 +1,156 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type CommitteeEventType string
+
+const (
+	// COMMITTEE_SNAPSHOT is delivered once with the membership seen when the subscription starts
+	COMMITTEE_SNAPSHOT       CommitteeEventType = "snapshot"
+	COMMITTEE_MEMBER_JOINED  CommitteeEventType = "member_joined"
+	COMMITTEE_MEMBER_LEFT    CommitteeEventType = "member_left"
+	COMMITTEE_NODE_JOINED    CommitteeEventType = "node_joined"
+	COMMITTEE_NODE_LEFT      CommitteeEventType = "node_left"
+	COMMITTEE_VOTING_STARTED CommitteeEventType = "voting_started"
+	COMMITTEE_VOTING_STOPPED CommitteeEventType = "voting_stopped"
+)
+
+// CommitteeEvent is a change of a chain's committee as seen by the session's node. Member is set
+// for member events, the NODE and VOTING events are about the node itself.
+type CommitteeEvent struct {
+	Type         CommitteeEventType
+	BlockchainId string
+	NodeId       string
+	Member       string
+	// Members, InCommittee and Voting describe the state after the change
+	Members     []string
+	InCommittee bool
+	Voting      bool
+	BlockHeight int
+	ObservedAt  time.Time
+}
+
+// CommitteeHandler processes one event. Returning an error redelivers the changes of the same
+// poll after the poll interval.
+type CommitteeHandler func(ctx context.Context, event CommitteeEvent) error
+
+type CommitteeSubscriptionOptions struct {
+	// PollInterval is how often the node is asked for its committee view
+	PollInterval time.Duration
+	// Snapshot delivers a COMMITTEE_SNAPSHOT event before the first change
+	Snapshot bool
+	// OnError is told about failures the subscription recovers from by polling again
+	OnError func(err error)
+}
+
+// committeeState is the part of chainInfo the subscription compares between polls
+type committeeState struct {
+	members     []string
+	inCommittee bool
+	voting      bool
+}
+
+// SubscribeCommittee delivers committee membership and voting changes of a chain, e.g. to alert when
+// the node drops out of the committee. Changes happening between two polls are only seen as their
+// net effect. It blocks until the context is cancelled.
+func (session *UL_TransactionSession) SubscribeCommittee(ctx context.Context, blockchainId string, opts CommitteeSubscriptionOptions, handler CommitteeHandler) error {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_POLL_INTERVAL
+	}
+
+	var previous *committeeState
+	for {
+		current, err := session.pollCommittee(ctx, blockchainId, previous, opts.Snapshot, handler)
+		if err != nil {
+			if opts.OnError != nil && ctx.Err() == nil {
+				opts.OnError(err)
+			}
+		} else {
+			previous = current
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
+
+// pollCommittee hands the changes since previous to handler and returns the state they lead to.
+// Without a previous state only the snapshot is delivered.
+func (session *UL_TransactionSession) pollCommittee(ctx context.Context, blockchainId string, previous *committeeState, snapshot bool, handler CommitteeHandler) (*committeeState, error) {
+	info := healthInfo{}
+	if err := session.getJson(ctx, "/health", &info); err != nil {
+		return nil, err
+	}
+	chain, ok := info.Chains[blockchainId]
+	if !ok {
+		return nil, fmt.Errorf("blockchain %s is not served by the node", blockchainId)
+	}
+	current := &committeeState{
+		members:     slices.Clone(chain.CommitteeMembers),
+		inCommittee: chain.IsInCommittee,
+		voting:      chain.IsVoting,
+	}
+
+	event := CommitteeEvent{
+		BlockchainId: blockchainId,
+		NodeId:       info.NodeId,
+		Members:      current.members,
+		InCommittee:  current.inCommittee,
+		Voting:       current.voting,
+		BlockHeight:  chain.Height,
+		ObservedAt:   time.Now().UTC(),
+	}
+	events := []CommitteeEvent{}
+	emit := func(eventType CommitteeEventType, member string) {
+		e := event
+		e.Type = eventType
+		e.Member = member
+		events = append(events, e)
+	}
+
+	if previous == nil {
+		if snapshot {
+			emit(COMMITTEE_SNAPSHOT, "")
+		}
+	} else {
+		for _, member := range previous.members {
+			if !slices.Contains(current.members, member) {
+				emit(COMMITTEE_MEMBER_LEFT, member)
+			}
+		}
+		for _, member := range current.members {
+			if !slices.Contains(previous.members, member) {
+				emit(COMMITTEE_MEMBER_JOINED, member)
+			}
+		}
+		if previous.inCommittee != current.inCommittee {
+			if current.inCommittee {
+				emit(COMMITTEE_NODE_JOINED, "")
+			} else {
+				emit(COMMITTEE_NODE_LEFT, "")
+			}
+		}
+		if previous.voting != current.voting {
+			if current.voting {
+				emit(COMMITTEE_VOTING_STARTED, "")
+			} else {
+				emit(COMMITTEE_VOTING_STOPPED, "")
+			}
+		}
+	}
+
+	for _, e := range events {
+		if err := handler(ctx, e); err != nil {
+			return nil, err
+		}
+	}
+	return current, nil
+}
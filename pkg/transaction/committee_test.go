@@ -0,0 +1,67 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+)
+
+func TestSubscribeCommittee(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events := make(chan transaction.CommitteeEvent, 16)
+	done := make(chan error, 1)
+	go func() {
+		done <- session.SubscribeCommittee(ctx, testBlockchainId, transaction.CommitteeSubscriptionOptions{
+			PollInterval: 10 * time.Millisecond,
+			Snapshot:     true,
+		}, func(ctx context.Context, event transaction.CommitteeEvent) error {
+			events <- event
+			return nil
+		})
+	}()
+
+	next := func() transaction.CommitteeEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a committee event")
+			return transaction.CommitteeEvent{}
+		}
+	}
+
+	snapshot := next()
+	if snapshot.Type != transaction.COMMITTEE_SNAPSHOT || !snapshot.InCommittee || !snapshot.Voting || snapshot.NodeId != transactiontest.MOCK_NODE_ID {
+		t.Fatalf("first event = %+v, want a snapshot of the voting mock node", snapshot)
+	}
+
+	node.SetVoting(testBlockchainId, false)
+	if event := next(); event.Type != transaction.COMMITTEE_VOTING_STOPPED {
+		t.Fatalf("event = %+v, want voting stopped", event)
+	}
+
+	// The node is replaced by another member
+	node.SetCommittee(testBlockchainId, "other-node")
+	want := []transaction.CommitteeEventType{transaction.COMMITTEE_MEMBER_LEFT, transaction.COMMITTEE_MEMBER_JOINED, transaction.COMMITTEE_NODE_LEFT}
+	for _, eventType := range want {
+		event := next()
+		if event.Type != eventType {
+			t.Fatalf("event = %+v, want %s", event, eventType)
+		}
+		if eventType == transaction.COMMITTEE_MEMBER_LEFT && event.Member != transactiontest.MOCK_NODE_ID {
+			t.Fatalf("member left = %s, want the mock node", event.Member)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("SubscribeCommittee() = %v, want context.Canceled", err)
+	}
+}
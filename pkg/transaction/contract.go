@@ -0,0 +1,223 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_CONTRACT_CALLS is advertised by nodes executing read-only contract calls, see
+// CallContract
+const NODE_FEATURE_CONTRACT_CALLS = "contract-calls"
+
+// ErrContractCallsUnsupported is returned by CallContract when the node does not advertise
+// NODE_FEATURE_CONTRACT_CALLS
+type ErrContractCallsUnsupported struct{}
+
+func (e *ErrContractCallsUnsupported) Error() string {
+	return "the node does not execute read-only contract calls"
+}
+
+func (e *ErrContractCallsUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ContractCallRequest is the body of a read-only call, From is the caller the contract sees
+type ContractCallRequest struct {
+	FunctionName string         `json:"functionName"`
+	Args         []ContractArgs `json:"args"`
+	From         string         `json:"from,omitempty"`
+}
+
+// ContractCallResult is the node's answer to a read-only call, Result is encoded with Encode
+type ContractCallResult struct {
+	Result  []byte `json:"result"`
+	GasUsed uint64 `json:"gasUsed"`
+}
+
+// ErrInvalidContractCall is returned before submitting an invocation that cannot be encoded
+type ErrInvalidContractCall struct {
+	ContractAddress string
+	FunctionName    string
+	Msg             string
+}
+
+func (e *ErrInvalidContractCall) Error() string {
+	return fmt.Sprintf("invalid call of %s on contract %s: %s", e.FunctionName, e.ContractAddress, e.Msg)
+}
+
+func (e *ErrInvalidContractCall) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ContractClient invokes and calls the functions of one deployed contract from the session's
+// wallet, encoding the arguments with Encode. It is embedded by the bindings `uledger contract bind`
+// generates, which add one typed method per contract function.
+//
+//	contract := transaction.NewContractClient(session, contractAddress)
+//	contract.BlockchainId = blockchainId
+//	_, err := contract.Invoke(ctx, "transfer", 100000, recipient, int32(1000))
+type ContractClient struct {
+	// BlockchainId is the chain of the contract, empty uses the session defaults
+	BlockchainId string
+	// GasLimit replaces the gas limit of every invocation when set
+	GasLimit uint64
+
+	session         *UL_TransactionSession
+	contractAddress string
+}
+
+// NewContractClient returns a client of the contract deployed at contractAddress
+func NewContractClient(session *UL_TransactionSession, contractAddress string) *ContractClient {
+	return &ContractClient{session: session, contractAddress: contractAddress}
+}
+
+// ContractAddress returns the address of the contract
+func (c *ContractClient) ContractAddress() string {
+	return c.contractAddress
+}
+
+// Invoke sends an INVOKE_SMART_CONTRACT transaction calling functionName with args, gasLimit is
+// used unless the client's GasLimit is set. It fails unless the node applied the transaction.
+func (c *ContractClient) Invoke(ctx context.Context, functionName string, gasLimit uint64, args ...any) (ULTransaction, error) {
+	encoded, err := encodeContractArgs(c.contractAddress, functionName, args)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if c.GasLimit > 0 {
+		gasLimit = c.GasLimit
+	}
+	payload := InvokeContractPayload{FunctionName: functionName, Args: encoded, GasLimit: gasLimit}
+	return c.session.submitToken(ctx, c.blockchainId(), INVOKE_SMART_CONTRACT, c.contractAddress, payload)
+}
+
+// Call executes the read-only function functionName with args, see CallContract
+func (c *ContractClient) Call(ctx context.Context, functionName string, args ...any) (interface{}, error) {
+	return c.session.CallContract(ctx, c.blockchainId(), c.contractAddress, functionName, args...)
+}
+
+// EstimateGas simulates invoking functionName with args and suggests a gas limit with the default
+// margin, see UL_TransactionSession.EstimateGas
+func (c *ContractClient) EstimateGas(ctx context.Context, functionName string, args ...any) (GasEstimate, error) {
+	encoded, err := encodeContractArgs(c.contractAddress, functionName, args)
+	if err != nil {
+		return GasEstimate{}, err
+	}
+	payload := InvokeContractPayload{FunctionName: functionName, Args: encoded, GasLimit: c.GasLimit}
+	return c.session.EstimateGas(ctx, c.blockchainId(), c.contractAddress, payload, GasEstimateOptions{})
+}
+
+// Events delivers the events of the contract from the block fromBlock on, see SubscribeContractEvents
+func (c *ContractClient) Events(ctx context.Context, fromBlock int, opts ContractEventOptions) (<-chan ContractEvent, error) {
+	return c.session.SubscribeContractEvents(ctx, c.blockchainId(), c.contractAddress, fromBlock, opts)
+}
+
+// CallContractAs executes the read-only function functionName of the contract of c and decodes
+// its result into T, see DecodeContractResult
+func CallContractAs[T any](ctx context.Context, c *ContractClient, functionName string, args ...any) (T, error) {
+	result, err := c.session.callContract(ctx, c.blockchainId(), c.contractAddress, functionName, args)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return DecodeContractResult[T](result.Result)
+}
+
+func (c *ContractClient) blockchainId() string {
+	if c.BlockchainId == "" {
+		return c.session.defaults.BlockchainId
+	}
+	return c.BlockchainId
+}
+
+// CallContract executes the function functionName of a contract with args on the node without
+// submitting a transaction, so nothing is signed, no gas is spent and the contract state is kept.
+// The arguments are encoded with Encode and the decoded result is returned. Nodes must advertise
+// NODE_FEATURE_CONTRACT_CALLS.
+func (session *UL_TransactionSession) CallContract(ctx context.Context, blockchainId string, contractAddress string, functionName string, args ...any) (interface{}, error) {
+	result, err := session.callContract(ctx, blockchainId, contractAddress, functionName, args)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(result.Result)
+}
+
+func (session *UL_TransactionSession) callContract(ctx context.Context, blockchainId string, contractAddress string, functionName string, args []any) (ContractCallResult, error) {
+	encoded, err := encodeContractArgs(contractAddress, functionName, args)
+	if err != nil {
+		return ContractCallResult{}, err
+	}
+	supported, err := session.hasFeature(ctx, NODE_FEATURE_CONTRACT_CALLS)
+	if err != nil {
+		return ContractCallResult{}, err
+	}
+	if !supported {
+		return ContractCallResult{}, &ErrContractCallsUnsupported{}
+	}
+
+	request := ContractCallRequest{FunctionName: functionName, Args: encoded, From: session.wallet.Address}
+	result := ContractCallResult{}
+	if err := session.Do(ctx, "POST", fmt.Sprintf("/blockchains/%s/contracts/%s/call", blockchainId, contractAddress), request, &result); err != nil {
+		return ContractCallResult{}, err
+	}
+	return result, nil
+}
+
+// encodeContractArgs checks the target of a call and encodes its arguments
+func encodeContractArgs(contractAddress string, functionName string, args []any) ([]ContractArgs, error) {
+	switch {
+	case !isAddress(contractAddress):
+		return nil, &ErrInvalidContractCall{ContractAddress: contractAddress, FunctionName: functionName, Msg: "the contract address must be a hex encoded 32 byte address"}
+	case functionName == "":
+		return nil, &ErrInvalidContractCall{ContractAddress: contractAddress, FunctionName: functionName, Msg: "the function name is required"}
+	}
+	encoded := make([]ContractArgs, len(args))
+	for i, arg := range args {
+		value, err := Encode(arg)
+		if err != nil {
+			return nil, &ErrInvalidContractCall{ContractAddress: contractAddress, FunctionName: functionName, Msg: fmt.Sprintf("argument %d: %s", i, err)}
+		}
+		encoded[i] = ContractArgs{Value: value}
+	}
+	return encoded, nil
+}
+
+// DecodeContractResult decodes a value encoded with Encode into T. Arrays decode into slices of
+// the element type and null into the zero value, so bindings can return the declared Go type.
+func DecodeContractResult[T any](data []byte) (T, error) {
+	var result T
+	value, err := Decode(data)
+	if err != nil {
+		return result, err
+	}
+	if err := assignContractValue(reflect.ValueOf(&result).Elem(), value); err != nil {
+		return result, &utils.ErrMalformed{What: "contract result", Msg: err.Error()}
+	}
+	return result, nil
+}
+
+// assignContractValue stores a decoded value into target, converting []interface{} element by element
+func assignContractValue(target reflect.Value, value interface{}) error {
+	if value == nil {
+		target.SetZero()
+		return nil
+	}
+	source := reflect.ValueOf(value)
+	if elements, ok := value.([]interface{}); ok && target.Kind() == reflect.Slice && target.Type().Elem().Kind() != reflect.Interface {
+		slice := reflect.MakeSlice(target.Type(), len(elements), len(elements))
+		for i, element := range elements {
+			if err := assignContractValue(slice.Index(i), element); err != nil {
+				return fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		target.Set(slice)
+		return nil
+	}
+	if !source.Type().AssignableTo(target.Type()) {
+		return fmt.Errorf("cannot decode %T into %s", value, target.Type())
+	}
+	target.Set(source)
+	return nil
+}
@@ -0,0 +1,137 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_CONTRACT_EVENTS is advertised by nodes recording the events contracts emit in the
+// outputs of their transactions, see SubscribeContractEvents
+const NODE_FEATURE_CONTRACT_EVENTS = "contract-events"
+
+// ErrContractEventsUnsupported is returned by SubscribeContractEvents when the node does not
+// advertise NODE_FEATURE_CONTRACT_EVENTS
+type ErrContractEventsUnsupported struct{}
+
+func (e *ErrContractEventsUnsupported) Error() string {
+	return "the node does not record contract events"
+}
+
+func (e *ErrContractEventsUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ContractEventLog is an event emitted while a transaction executed, as the node records it. Data
+// is encoded with Encode.
+type ContractEventLog struct {
+	ContractAddress string `json:"contractAddress"`
+	Name            string `json:"name"`
+	Data            []byte `json:"data"`
+}
+
+// ContractEvent is a decoded event of a contract
+type ContractEvent struct {
+	BlockchainId    string
+	ContractAddress string
+	Name            string
+	// Value is the decoded Data, DecodeContractResult decodes Data into a Go type instead
+	Value interface{}
+	Data  []byte
+	// TransactionId is the transaction that emitted the event, Index its position among the
+	// events of the transaction
+	TransactionId string
+	Index         int
+	BlockHeight   int
+	BlockHash     string
+}
+
+type ContractEventOptions struct {
+	// Names only delivers the events of these names, every event when empty
+	Names []string
+	// PollInterval is how often the node is asked for new blocks
+	PollInterval time.Duration
+	// OnError is told about failures the subscription recovers from and about events whose data
+	// cannot be decoded, which are skipped
+	OnError func(err error)
+}
+
+// SubscribeContractEvents delivers the events of a contract from the block fromBlock on, in chain
+// order, until the context is cancelled and the channel is closed. The blocks are read as
+// SubscribeBlocks reads them, a reorg under already delivered events is reported to OnError and
+// ends the subscription. Nodes must advertise NODE_FEATURE_CONTRACT_EVENTS.
+func (session *UL_TransactionSession) SubscribeContractEvents(ctx context.Context, blockchainId string, contractAddress string, fromBlock int, opts ContractEventOptions) (<-chan ContractEvent, error) {
+	if !isAddress(contractAddress) {
+		return nil, &utils.ErrMalformed{What: "contract address", Msg: "it must be a hex encoded 32 byte address"}
+	}
+	supported, err := session.hasFeature(ctx, NODE_FEATURE_CONTRACT_EVENTS)
+	if err != nil {
+		return nil, err
+	}
+	if !supported {
+		return nil, &ErrContractEventsUnsupported{}
+	}
+	if fromBlock < 1 {
+		fromBlock = 1
+	}
+
+	events := make(chan ContractEvent, 16)
+	handler := func(ctx context.Context, block ULBlock) error {
+		for _, event := range contractEvents(blockchainId, contractAddress, block, opts) {
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	}
+	go func() {
+		defer close(events)
+		blocks := BlockSubscriptionOptions{
+			Consumer:     "contract-events/" + contractAddress,
+			StartHeight:  fromBlock,
+			PollInterval: opts.PollInterval,
+			OnError:      opts.OnError,
+		}
+		err := session.SubscribeBlocks(ctx, blockchainId, blocks, handler)
+		if opts.OnError != nil && !errors.Is(err, ctx.Err()) {
+			opts.OnError(err)
+		}
+	}()
+	return events, nil
+}
+
+// contractEvents returns the events of a contract a block holds that the options select
+func contractEvents(blockchainId string, contractAddress string, block ULBlock, opts ContractEventOptions) []ContractEvent {
+	events := []ContractEvent{}
+	for _, tx := range block.Transactions {
+		for i, log := range tx.Events {
+			if log.ContractAddress != contractAddress || (len(opts.Names) > 0 && !slices.Contains(opts.Names, log.Name)) {
+				continue
+			}
+			value, err := Decode(log.Data)
+			if err != nil {
+				if opts.OnError != nil {
+					opts.OnError(&utils.ErrMalformed{What: "event " + log.Name + " of transaction " + tx.TransactionId, Msg: err.Error()})
+				}
+				continue
+			}
+			events = append(events, ContractEvent{
+				BlockchainId:    blockchainId,
+				ContractAddress: contractAddress,
+				Name:            log.Name,
+				Value:           value,
+				Data:            log.Data,
+				TransactionId:   tx.TransactionId,
+				Index:           i,
+				BlockHeight:     block.Height,
+				BlockHash:       block.Hash,
+			})
+		}
+	}
+	return events
+}
@@ -0,0 +1,93 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestSubscribeContractEvents(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE)
+	deployed, err := session.DeployContract(ctx, testBlockchainId, []byte("(module)"), transaction.ContractUploadOptions{Inline: true, InitialState: map[string]interface{}{"alice": int32(100)}})
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	contract := transaction.NewContractClient(session, deployed.TransactionId)
+	contract.BlockchainId = testBlockchainId
+	node.SetContractFunction(contract.ContractAddress(), "transfer", func(state map[string]interface{}, args []interface{}, emit func(name string, value interface{})) error {
+		to, amount := args[0].(string), args[1].(int32)
+		balance, _ := state["alice"].(int32)
+		if balance < amount {
+			return fmt.Errorf("insufficient balance")
+		}
+		state["alice"], state[to] = balance-amount, amount
+		emit("Log", "transfer")
+		emit("Transfer", map[string]interface{}{"from": "alice", "to": to, "amount": amount})
+		return nil
+	})
+
+	if _, err := contract.Events(ctx, 0, transaction.ContractEventOptions{}); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("Events() without contract events error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_EVENTS)
+	if _, err := session.SubscribeContractEvents(ctx, testBlockchainId, "token", 0, transaction.ContractEventOptions{}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("SubscribeContractEvents() of an invalid address error = %v", err)
+	}
+
+	first, err := contract.Invoke(ctx, "transfer", 0, "bob", int32(10))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if len(first.Events) != 2 || first.Events[1].Name != "Transfer" || first.Events[1].ContractAddress != contract.ContractAddress() {
+		t.Fatalf("Invoke() events = %+v", first.Events)
+	}
+	second, err := contract.Invoke(ctx, "transfer", 0, "carol", int32(20))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	errs := make(chan error, 16)
+	events, err := session.SubscribeContractEvents(ctx, testBlockchainId, contract.ContractAddress(), second.BlockHeight, transaction.ContractEventOptions{
+		Names:        []string{"Transfer"},
+		PollInterval: 10 * time.Millisecond,
+		OnError:      func(err error) { errs <- err },
+	})
+	if err != nil {
+		t.Fatalf("SubscribeContractEvents() error = %v", err)
+	}
+	third, err := contract.Invoke(ctx, "transfer", 0, "dave", int32(30))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	for i, tx := range []transaction.ULTransaction{second, third} {
+		recipient := []string{"carol", "dave"}[i]
+		select {
+		case event := <-events:
+			value, _ := event.Value.(map[string]interface{})
+			if event.Name != "Transfer" || event.TransactionId != tx.TransactionId || event.Index != 1 || event.BlockHeight != tx.BlockHeight || value["to"] != recipient {
+				t.Fatalf("event = %+v, want the Transfer to %s of %s", event, recipient, tx.TransactionId)
+			}
+			if decoded, err := transaction.DecodeContractResult[map[string]interface{}](event.Data); err != nil || decoded["amount"] != value["amount"] {
+				t.Fatalf("DecodeContractResult() of the event = %v, %v", decoded, err)
+			}
+		case err := <-errs:
+			t.Fatalf("OnError() = %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no event for %s", tx.TransactionId)
+		}
+	}
+
+	cancel()
+	for range events {
+		t.Fatal("event delivered after the subscription ended")
+	}
+}
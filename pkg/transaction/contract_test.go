@@ -0,0 +1,107 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestContractClient(t *testing.T) {
+	ctx := context.Background()
+	node, session := newMockSession(t)
+	contract := transaction.NewContractClient(session, fmt.Sprintf("%064x", 7))
+	contract.BlockchainId = testBlockchainId
+	if _, err := contract.Invoke(ctx, "transfer", 150000, "alice", int32(1000)); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	contract.GasLimit = 5000
+	if _, err := contract.Invoke(ctx, "emit", 100000); err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+
+	txs := node.Transactions()
+	payloads := make([]transaction.InvokeContractPayload, len(txs))
+	for i, tx := range txs {
+		if tx.PayloadType != transaction.INVOKE_SMART_CONTRACT.String() || tx.To != contract.ContractAddress() {
+			t.Fatalf("transaction %d = %+v", i, tx)
+		}
+		json.Unmarshal([]byte(tx.Payload), &payloads[i])
+	}
+	if len(payloads) != 2 || payloads[0].FunctionName != "transfer" || payloads[0].GasLimit != 150000 || len(payloads[0].Args) != 2 || payloads[1].GasLimit != 5000 {
+		t.Fatalf("payloads = %+v", payloads)
+	}
+	if amount, err := transaction.DecodeContractResult[int32](payloads[0].Args[1].Value); err != nil || amount != 1000 {
+		t.Fatalf("DecodeContractResult() of the amount = %d, %v", amount, err)
+	}
+
+	if _, err := contract.Invoke(ctx, "transfer", 0, uint8(1)); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Invoke() of an unsupported argument error = %v", err)
+	}
+	if _, err := transaction.NewContractClient(session, "token").Invoke(ctx, "emit", 0); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Invoke() of an invalid address error = %v", err)
+	}
+}
+
+func TestCallContract(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE)
+	deployed, err := session.DeployContract(ctx, testBlockchainId, []byte("(module)"), transaction.ContractUploadOptions{Inline: true, InitialState: map[string]interface{}{"alice": int32(70)}})
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	contract := transaction.NewContractClient(session, deployed.TransactionId)
+	contract.BlockchainId = testBlockchainId
+	node.SetContractView(contract.ContractAddress(), "balanceOf", func(state map[string]interface{}, args []interface{}) (interface{}, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("balanceOf takes the owner")
+		}
+		return state[args[0].(string)], nil
+	})
+
+	if _, err := contract.Call(ctx, "balanceOf", "alice"); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("Call() without contract calls error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_CALLS)
+	submitted := len(node.Transactions())
+	if balance, err := session.CallContract(ctx, testBlockchainId, contract.ContractAddress(), "balanceOf", "alice"); err != nil || balance != int32(70) {
+		t.Fatalf("CallContract() = %v, %v", balance, err)
+	}
+	if balance, err := transaction.CallContractAs[int32](ctx, contract, "balanceOf", "bob"); err != nil || balance != 0 {
+		t.Fatalf("CallContractAs() of a missing owner = %d, %v", balance, err)
+	}
+	if len(node.Transactions()) != submitted {
+		t.Fatal("read-only calls submitted transactions")
+	}
+
+	if _, err := contract.Call(ctx, "balanceOf"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Call() failing in the contract error = %v", err)
+	}
+	if _, err := contract.Call(ctx, "totalSupply"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Call() of an unknown function error = %v", err)
+	}
+	if _, err := session.CallContract(ctx, testBlockchainId, fmt.Sprintf("%064x", 9), "balanceOf", "alice"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("CallContract() of an unknown contract error = %v", err)
+	}
+}
+
+func TestDecodeContractResult(t *testing.T) {
+	encoded, _ := transaction.Encode([]int64{3, 1, 2})
+	values, err := transaction.DecodeContractResult[[]int64](encoded)
+	if err != nil || !slices.Equal(values, []int64{3, 1, 2}) {
+		t.Fatalf("DecodeContractResult() = %v, %v", values, err)
+	}
+	null, _ := transaction.Encode(nil)
+	if name, err := transaction.DecodeContractResult[string](null); err != nil || name != "" {
+		t.Fatalf("DecodeContractResult() of null = %q, %v", name, err)
+	}
+	if _, err := transaction.DecodeContractResult[string](encoded); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("DecodeContractResult() into another type error = %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// DecodedTransaction is a transaction with every encoded field expanded for inspection
+type DecodedTransaction struct {
+	TransactionId   string       `json:"transactionId"`
+	BlockchainId    string       `json:"blockchainId"`
+	From            string       `json:"from"`
+	To              string       `json:"to"`
+	Suggestor       string       `json:"suggestor"`
+	KeyType         string       `json:"keyType"`
+	DelegationId    string       `json:"delegationId,omitempty"`
+	PayloadType     string       `json:"payloadType"`
+	Status          string       `json:"status"`
+	Output          string       `json:"output"`
+	Version         string       `json:"version"`
+	BlockHeight     int          `json:"blockHeight"`
+	SenderTimestamp time.Time    `json:"senderTimestamp"`
+	ExactTime       time.Time    `json:"exactTime"`
+	ApproximateTime time.Time    `json:"approximateTime"`
+	Memo            string       `json:"memo,omitempty"`
+	Payload         any          `json:"payload"`
+	PayloadError    string       `json:"payloadError,omitempty"`
+	Proof           *ParsedProof `json:"proof,omitempty"`
+	Problems        []string     `json:"problems,omitempty"`
+	Commitment      CheckResult  `json:"commitment"`
+	Signature       CheckResult  `json:"signature"`
+	// Hybrid is only set on transactions carrying a post-quantum signature
+	Hybrid *CheckResult `json:"hybrid,omitempty"`
+}
+
+// CheckResult is the outcome of recomputing or verifying part of a transaction
+type CheckResult struct {
+	Checked bool   `json:"checked"`
+	Valid   bool   `json:"valid"`
+	Detail  string `json:"detail"`
+}
+
+// DecodeTransaction expands tx, recomputes its payload root and, when publicKeyHex is given, checks the
+// sender signature. Addresses are hashes of the public key, so the signature cannot be checked without it.
+// Transactions of multisig wallets carry their keys, their signature is always checked.
+func DecodeTransaction(tx ULTransaction, publicKeyHex string) DecodedTransaction {
+	decoded := DecodedTransaction{
+		TransactionId:   tx.TransactionId,
+		BlockchainId:    tx.BlockchainId,
+		From:            tx.From,
+		To:              tx.To,
+		Suggestor:       tx.Suggestor,
+		KeyType:         tx.KeyType.String(),
+		DelegationId:    tx.DelegationId,
+		PayloadType:     tx.PayloadType,
+		Status:          tx.Status,
+		Output:          tx.Output,
+		Version:         tx.Version,
+		BlockHeight:     tx.BlockHeight,
+		SenderTimestamp: tx.SenderTimestamp,
+		ExactTime:       tx.Timestamp.ExactTime,
+		ApproximateTime: tx.Timestamp.ApproximateTime,
+	}
+	if err := checkPayloadType(tx.PayloadType); err != nil {
+		decoded.Problems = append(decoded.Problems, err.Error())
+	}
+	if _, err := ParseTransactionStatus(tx.Status); tx.Status != "" && err != nil {
+		decoded.Problems = append(decoded.Problems, err.Error())
+	}
+	if _, err := ParseTransactionOutput(tx.Output); tx.Output != "" && err != nil {
+		decoded.Problems = append(decoded.Problems, err.Error())
+	}
+
+	if tx.Proof != "" {
+		if proof, err := tx.ParseProof(); err != nil {
+			decoded.Problems = append(decoded.Problems, err.Error())
+		} else {
+			decoded.Proof = &proof
+		}
+	}
+
+	decoded.Memo, _ = tx.GetMemo()
+	payload, err := DecodePayload(tx.PayloadType, tx.GetPayload())
+	if err != nil {
+		decoded.PayloadError = err.Error()
+	}
+	decoded.Payload = payload
+
+	commitment, payloadRoot, err := tx.SigningCommitment()
+	switch {
+	case err != nil:
+		decoded.Commitment = CheckResult{Checked: true, Detail: err.Error()}
+	case !strings.EqualFold(payloadRoot, tx.PayloadRoot):
+		decoded.Commitment = CheckResult{Checked: true, Detail: fmt.Sprintf("payload root is %s, recomputed %s", tx.PayloadRoot, payloadRoot)}
+	default:
+		decoded.Commitment = CheckResult{Checked: true, Valid: true, Detail: "payload root " + payloadRoot}
+	}
+
+	decoded.Signature = checkSignature(tx.ULTransactionInput, commitment, publicKeyHex)
+	if decoded.Commitment.Checked && !decoded.Commitment.Valid {
+		// The signature covers the recomputed commitment, so it can only be trusted if the root matches
+		decoded.Signature.Valid = false
+	}
+	if tx.HybridSignature != "" {
+		// Whether the key is paired with the sender needs the pair, see VerifyHybridTransaction
+		hybrid := checkHybridSignature(tx.ULTransactionInput, commitment)
+		if decoded.Commitment.Checked && !decoded.Commitment.Valid {
+			hybrid.Valid = false
+		}
+		decoded.Hybrid = &hybrid
+	}
+	return decoded
+}
+
+func checkSignature(input ULTransactionInput, commitment []byte, publicKeyHex string) CheckResult {
+	if input.Multisig != nil {
+		return checkMultisigSignature(input, commitment)
+	}
+	if publicKeyHex == "" {
+		return CheckResult{Detail: "skipped, the sender public key is required"}
+	}
+	if commitment == nil {
+		return CheckResult{Detail: "skipped, the commitment could not be recomputed"}
+	}
+	// A delegated key signs for From, whether it may is checked against the delegation itself
+	if input.DelegationId == "" && !strings.EqualFold(wallet.ParseAddress(publicKeyHex), input.From) {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("the public key does not belong to %s", input.From)}
+	}
+	key, err := crypto.GetKeyByType(input.KeyType, crypto.GetHasherByType(input.KeyType))
+	if err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	if err := key.GeneratePublicKeyFromHex(false, publicKeyHex); err != nil {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("invalid public key: %v", err)}
+	}
+	signature, err := crypto.HexToBytes(input.SenderSignature)
+	if err != nil {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("invalid signature: %v", err)}
+	}
+	encoding, err := crypto.ParseSignatureEncoding(string(input.SignatureEncoding))
+	if err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	ok, err := crypto.VerifySignatureWithEncoding(key, commitment, signature, encoding)
+	if err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	if !ok {
+		return CheckResult{Checked: true, Detail: "the signature does not match the commitment"}
+	}
+	if input.DelegationId != "" {
+		return CheckResult{Checked: true, Valid: true, Detail: fmt.Sprintf("%s signature verified (%s) for the key of delegation %s", input.KeyType, encoding, input.DelegationId)}
+	}
+	return CheckResult{Checked: true, Valid: true, Detail: fmt.Sprintf("%s signature verified (%s)", input.KeyType, encoding)}
+}
+
+// DecodePayload parses payload into the struct of its type. DATA payloads decode to their JSON value
+// or stay a string, registered types use their Decode function and unknown types decode to a generic
+// JSON value when possible.
+func DecodePayload(payloadType string, payload string) (any, error) {
+	if custom, ok := GetPayloadType(payloadType); ok && custom.Decode != nil {
+		return custom.Decode(payload)
+	}
+	var target any
+	switch strings.ToUpper(payloadType) {
+	case TX_DATA.String():
+		var value any
+		if json.Unmarshal([]byte(payload), &value) == nil {
+			return value, nil
+		}
+		return payload, nil
+	case DEPLOY_SMART_CONTRACT.String():
+		contract := ContractSource{}
+		if json.Unmarshal([]byte(payload), &contract) == nil && contract.Sha256 != "" {
+			return contract, nil
+		}
+		// Sources deployed before the envelope existed are plain code
+		return map[string]any{"sourceSize": len(payload)}, nil
+	case TX_CREATE_WALLET.String():
+		target = &CreateWalletPayload{}
+	case TX_ALTER_WALLET.String():
+		target = &AlterWalletPayload{}
+	case INVOKE_SMART_CONTRACT.String():
+		target = &InvokeContractPayload{}
+	case UPGRADE_SMART_CONTRACT.String():
+		target = &UpgradeContractPayload{}
+	case ROLLBACK_SMART_CONTRACT.String():
+		target = &RollbackContractPayload{}
+	case CREATE_TOKEN.String():
+		target = &CreateTokenPayload{}
+	case TRANSFER_TOKEN.String(), TRANSFER_NFT.String(), TRANSFER_MULTI_TOKEN.String():
+		target = &TransferTokenPayload{}
+	case APPROVE_TOKEN.String(), APPROVE_NFT.String():
+		target = &ApproveTokenPayload{}
+	case MINT_TOKEN.String(), MINT_NFT.String(), MINT_MULTI_TOKEN.String():
+		target = &MintTokenPayload{}
+	case BURN_TOKEN.String():
+		target = &BurnTokenPayload{}
+	case SET_APPROVAL_FOR_ALL.String():
+		target = &SetApprovalForAllPayload{}
+	case CONVERT_TOKEN.String():
+		target = &ConvertTokenPayload{}
+	case DELEGATE_KEY.String():
+		target = &SignedDelegation{}
+	case REVOKE_DELEGATION.String():
+		target = &RevokeDelegationPayload{}
+	case CANCEL_TRANSACTION.String():
+		target = &CancelTransactionPayload{}
+	case CREATE_MULTISIG.String():
+		target = &CreateMultisigPayload{}
+	case PROPOSE_MULTISIG.String():
+		target = &ProposeMultisigPayload{}
+	case APPROVE_MULTISIG.String(), EXECUTE_MULTISIG.String():
+		target = &MultisigProposalPayload{}
+	default:
+		var value any
+		if err := json.Unmarshal([]byte(payload), &value); err != nil {
+			return payload, fmt.Errorf("%s payload is not JSON: %w", payloadType, err)
+		}
+		return value, nil
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(target); err != nil {
+		return payload, fmt.Errorf("invalid %s payload: %w", payloadType, err)
+	}
+	return target, nil
+}
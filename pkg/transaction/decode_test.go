@@ -0,0 +1,93 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	_, session := newMockSession(t)
+	w := session.GetWallet()
+	publicKeyHex := w.GetKey().GetPublicKeyHex(false)
+
+	memoInput, err := transaction.WithMemo(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           w.Address,
+		Payload:      `{"temperature":21}`,
+		PayloadType:  transaction.TX_DATA.String(),
+	}, "sensor 7")
+	if err != nil {
+		t.Fatalf("WithMemo() error = %v", err)
+	}
+	tx, err := session.GenerateTransaction(memoInput)
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	decoded := transaction.DecodeTransaction(tx, publicKeyHex)
+	if !decoded.Commitment.Valid || !decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() commitment = %+v, signature = %+v", decoded.Commitment, decoded.Signature)
+	}
+	if decoded.Memo != "sensor 7" || decoded.Output != transaction.TX_SUCCESS.String() || len(decoded.Problems) != 0 {
+		t.Fatalf("DecodeTransaction() = %+v", decoded)
+	}
+	if payload, ok := decoded.Payload.(map[string]any); !ok || payload["temperature"] != float64(21) {
+		t.Fatalf("Payload = %#v", decoded.Payload)
+	}
+
+	if skipped := transaction.DecodeTransaction(tx, ""); skipped.Signature.Checked {
+		t.Fatal("the signature was checked without a public key")
+	}
+
+	tampered := tx
+	tampered.Payload = `{"temperature":35}`
+	decoded = transaction.DecodeTransaction(tampered, publicKeyHex)
+	if decoded.Commitment.Valid || decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() accepted a tampered payload: %+v", decoded)
+	}
+}
+
+func TestDERSignedTransaction(t *testing.T) {
+	_, session := newMockSession(t)
+	if err := session.SetSignatureEncoding(crypto.SIGNATURE_ENCODING_DER); err != nil {
+		t.Fatalf("SetSignatureEncoding() error = %v", err)
+	}
+	tx := submitData(t, session, "der signed")
+	if tx.SignatureEncoding != crypto.SIGNATURE_ENCODING_DER {
+		t.Fatalf("SignatureEncoding = %q, want der", tx.SignatureEncoding)
+	}
+	signature, _ := crypto.HexToBytes(tx.SenderSignature)
+	if signature[0] != 0x30 {
+		t.Fatalf("signature %s is not a DER sequence", tx.SenderSignature)
+	}
+
+	w := session.GetWallet()
+	publicKeyHex := w.GetKey().GetPublicKeyHex(false)
+	if decoded := transaction.DecodeTransaction(tx, publicKeyHex); !decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() signature = %+v", decoded.Signature)
+	}
+	// Dropping the recorded encoding makes the signature unverifiable
+	tx.SignatureEncoding = ""
+	if decoded := transaction.DecodeTransaction(tx, publicKeyHex); decoded.Signature.Valid {
+		t.Fatal("a DER signature verified as raw")
+	}
+}
+
+func TestDecodePayload(t *testing.T) {
+	payload, err := transaction.DecodePayload(transaction.TRANSFER_TOKEN.String(), `{"tokenAddress":"t","to":"b","amount":5}`)
+	if err != nil {
+		t.Fatalf("DecodePayload() error = %v", err)
+	}
+	if transfer, ok := payload.(*transaction.TransferTokenPayload); !ok || transfer.Amount.Cmp(transaction.NewAmount(5)) != 0 {
+		t.Fatalf("DecodePayload() = %#v", payload)
+	}
+
+	if _, err := transaction.DecodePayload(transaction.MINT_TOKEN.String(), `{"tokenAddress":"t","ammount":5}`); err == nil {
+		t.Fatal("DecodePayload() accepted an unknown field")
+	}
+	if payload, err := transaction.DecodePayload(transaction.TX_DATA.String(), "plain text"); err != nil || payload != "plain text" {
+		t.Fatalf("DecodePayload() = %v, %v", payload, err)
+	}
+}
@@ -0,0 +1,93 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// TransactionDefaults fill the fields of a ULTransactionInput that are left empty.
+// Metadata keys are merged into JSON object payloads of DATA transactions, keys already in the payload win
+// and the payload is re-encoded with sorted keys. Memo wraps DATA payloads that have none, see WithMemo.
+type TransactionDefaults struct {
+	BlockchainId string
+	To           string
+	PayloadType  string
+	Metadata     map[string]any
+	Memo         string
+}
+
+// ErrMissingBlockchainId is returned when neither the input nor the session defaults name a chain
+type ErrMissingBlockchainId struct{}
+
+func (e *ErrMissingBlockchainId) Error() string {
+	return "missing blockchain id, set it on the input or with SetDefaults"
+}
+
+func (e *ErrMissingBlockchainId) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// SetDefaults configures the values applied to every input passed to GenerateTransaction
+func (session *UL_TransactionSession) SetDefaults(defaults TransactionDefaults) {
+	session.defaults = defaults
+}
+
+// Defaults returns the values applied to every input passed to GenerateTransaction
+func (session *UL_TransactionSession) Defaults() TransactionDefaults {
+	return session.defaults
+}
+
+// Apply returns a copy of input with its empty fields filled from the defaults
+func (defaults TransactionDefaults) Apply(input ULTransactionInput) (ULTransactionInput, error) {
+	if input.BlockchainId == "" {
+		input.BlockchainId = defaults.BlockchainId
+	}
+	if input.To == "" {
+		input.To = defaults.To
+	}
+	if input.PayloadType == "" {
+		input.PayloadType = defaults.PayloadType
+	}
+	if input.BlockchainId == "" {
+		return input, &ErrMissingBlockchainId{}
+	}
+
+	if len(defaults.Metadata) > 0 && input.PayloadType == TX_DATA.String() {
+		payload, err := mergeMetadata(input.Payload, defaults.Metadata)
+		if err != nil {
+			return input, err
+		}
+		input.Payload = payload
+	}
+	if defaults.Memo != "" && input.PayloadType == TX_DATA.String() {
+		if _, ok := input.GetMemo(); !ok {
+			return WithMemo(input, defaults.Memo)
+		}
+	}
+	return input, nil
+}
+
+// mergeMetadata adds metadata to a JSON object payload, other payloads are returned untouched
+func mergeMetadata(payload string, metadata map[string]any) (string, error) {
+	object := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(payload), &object); err != nil || object == nil {
+		return payload, nil
+	}
+	for key, value := range metadata {
+		if _, ok := object[key]; ok {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("unable to encode metadata %s: %w", key, err)
+		}
+		object[key] = raw
+	}
+	merged, err := json.Marshal(object)
+	if err != nil {
+		return "", fmt.Errorf("unable to encode payload: %w", err)
+	}
+	return string(merged), nil
+}
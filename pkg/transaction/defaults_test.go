@@ -0,0 +1,45 @@
+package transaction_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestSessionDefaults(t *testing.T) {
+	node, session := newMockSession(t)
+
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{Payload: "no chain", PayloadType: transaction.TX_DATA.String()})
+	var missing *transaction.ErrMissingBlockchainId
+	if !errors.As(err, &missing) {
+		t.Fatalf("GenerateTransaction() error = %v, want ErrMissingBlockchainId", err)
+	}
+
+	session.SetDefaults(transaction.TransactionDefaults{
+		BlockchainId: testBlockchainId,
+		To:           "archive",
+		PayloadType:  transaction.TX_DATA.String(),
+		Metadata:     map[string]any{"source": "billing", "kind": "default"},
+	})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{Payload: `{"invoice":42,"kind":"refund"}`}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{To: "explicit", Payload: "plain text"}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	txs := node.Transactions()
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+	if txs[0].BlockchainId != testBlockchainId || txs[0].To != "archive" || txs[0].PayloadType != transaction.TX_DATA.String() {
+		t.Errorf("defaults were not applied: %+v", txs[0].ULTransactionInput)
+	}
+	if want := `{"invoice":42,"kind":"refund","source":"billing"}`; txs[0].Payload != want {
+		t.Errorf("payload = %s, want %s", txs[0].Payload, want)
+	}
+	if txs[1].To != "explicit" || txs[1].Payload != "plain text" {
+		t.Errorf("explicit values were overridden: %+v", txs[1].ULTransactionInput)
+	}
+}
@@ -0,0 +1,368 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	DEFAULT_DELEGATION_TTL = time.Hour
+	// MAX_DELEGATION_TTL keeps delegated keys short lived, longer lived keys should be wallets of their own
+	MAX_DELEGATION_TTL = 24 * time.Hour
+	// DEFAULT_DELEGATION_RENEW_BEFORE is how long before expiry a DelegatedSession renews its key
+	DEFAULT_DELEGATION_RENEW_BEFORE = 5 * time.Minute
+)
+
+// Delegation lets an ephemeral key sign the listed payload types on behalf of Delegator on one chain
+// until Expiry. Delegations cannot grant the right to delegate or revoke.
+type Delegation struct {
+	Delegator    string         `json:"delegator"`
+	BlockchainId string         `json:"blockchainId"`
+	KeyType      crypto.KeyType `json:"keyType"`
+	PublicKey    string         `json:"publicKey"`
+	Scope        []string       `json:"scope"`
+	NotBefore    time.Time      `json:"notBefore"`
+	Expiry       time.Time      `json:"expiry"`
+}
+
+// SignedDelegation is the DELEGATE_KEY payload, Signature is a message signature of the delegator
+// over the JSON encoding of Delegation
+type SignedDelegation struct {
+	Delegation Delegation              `json:"delegation"`
+	Signature  wallet.MessageSignature `json:"signature"`
+}
+
+type RevokeDelegationPayload struct {
+	DelegationId string `json:"delegationId"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+type ErrInvalidDelegation struct {
+	Msg string
+}
+
+func (e *ErrInvalidDelegation) Error() string {
+	return fmt.Sprintf("invalid delegation, %s", e.Msg)
+}
+
+func (e *ErrInvalidDelegation) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrDelegationExpired is returned before signing when a delegated key is used outside its validity window
+type ErrDelegationExpired struct {
+	DelegationId string
+	NotBefore    time.Time
+	Expiry       time.Time
+}
+
+func (e *ErrDelegationExpired) Error() string {
+	return fmt.Sprintf("delegation %s is only valid from %s to %s", e.DelegationId, e.NotBefore.Format(time.RFC3339), e.Expiry.Format(time.RFC3339))
+}
+
+func (e *ErrDelegationExpired) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// ErrDelegationScope is returned before signing when a delegated key signs outside its scope
+type ErrDelegationScope struct {
+	DelegationId string
+	BlockchainId string
+	PayloadType  string
+}
+
+func (e *ErrDelegationScope) Error() string {
+	return fmt.Sprintf("delegation %s does not cover %s transactions on %s", e.DelegationId, e.PayloadType, e.BlockchainId)
+}
+
+func (e *ErrDelegationScope) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+func (d Delegation) Bytes() ([]byte, error) {
+	return json.Marshal(d)
+}
+
+// Id is the hex SHA-256 of the JSON encoding, transactions signed with the key reference it
+func (d Delegation) Id() string {
+	data, _ := d.Bytes()
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+// Address returns the address of the delegated key
+func (d Delegation) Address() string {
+	return wallet.ParseAddress(d.PublicKey)
+}
+
+// Validate checks the delegation is well formed
+func (d Delegation) Validate() error {
+	switch {
+	case d.Delegator == "":
+		return &ErrInvalidDelegation{Msg: "the delegator is missing"}
+	case d.BlockchainId == "":
+		return &ErrInvalidDelegation{Msg: "the blockchain id is missing"}
+	case d.PublicKey == "":
+		return &ErrInvalidDelegation{Msg: "the delegated public key is missing"}
+	case d.Address() == d.Delegator:
+		return &ErrInvalidDelegation{Msg: "a wallet cannot delegate to its own key"}
+	case len(d.Scope) == 0:
+		return &ErrInvalidDelegation{Msg: "the scope is empty"}
+	case !d.Expiry.After(d.NotBefore):
+		return &ErrInvalidDelegation{Msg: "the expiry must be after the start"}
+	case d.Expiry.Sub(d.NotBefore) > MAX_DELEGATION_TTL:
+		return &ErrInvalidDelegation{Msg: fmt.Sprintf("the validity exceeds %s", MAX_DELEGATION_TTL)}
+	}
+	for _, payloadType := range d.Scope {
+		if err := checkPayloadType(payloadType); err != nil {
+			return &ErrInvalidDelegation{Msg: err.Error()}
+		}
+		if payloadType == DELEGATE_KEY.String() || payloadType == REVOKE_DELEGATION.String() {
+			return &ErrInvalidDelegation{Msg: fmt.Sprintf("%s cannot be delegated", payloadType)}
+		}
+	}
+	return nil
+}
+
+// Allows fails with ErrDelegationExpired or ErrDelegationScope unless the delegated key may sign input at
+func (d Delegation) Allows(input ULTransactionInput, at time.Time) error {
+	if at.Before(d.NotBefore) || !at.Before(d.Expiry) {
+		return &ErrDelegationExpired{DelegationId: d.Id(), NotBefore: d.NotBefore, Expiry: d.Expiry}
+	}
+	if input.BlockchainId != d.BlockchainId || !slices.Contains(d.Scope, input.PayloadType) {
+		return &ErrDelegationScope{DelegationId: d.Id(), BlockchainId: input.BlockchainId, PayloadType: input.PayloadType}
+	}
+	return nil
+}
+
+// SignDelegation signs d with the delegator's wallet
+func SignDelegation(delegator *wallet.UL_Wallet, d Delegation) (SignedDelegation, error) {
+	if d.Delegator != delegator.Address {
+		return SignedDelegation{}, &ErrInvalidDelegation{Msg: fmt.Sprintf("%s cannot sign a delegation of %s", delegator.Address, d.Delegator)}
+	}
+	if err := d.Validate(); err != nil {
+		return SignedDelegation{}, err
+	}
+	data, err := d.Bytes()
+	if err != nil {
+		return SignedDelegation{}, err
+	}
+	signature, err := delegator.SignMessage(data)
+	if err != nil {
+		return SignedDelegation{}, err
+	}
+	return SignedDelegation{Delegation: d, Signature: signature}, nil
+}
+
+// Verify checks that the delegation is well formed and signed by its delegator
+func (s SignedDelegation) Verify() error {
+	if err := s.Delegation.Validate(); err != nil {
+		return err
+	}
+	data, err := s.Delegation.Bytes()
+	if err != nil {
+		return err
+	}
+	ok, err := wallet.VerifyMessage(s.Delegation.Delegator, data, s.Signature)
+	if err != nil {
+		return &ErrInvalidDelegation{Msg: err.Error()}
+	}
+	if !ok {
+		return &ErrInvalidDelegation{Msg: "the signature does not belong to the delegator"}
+	}
+	return nil
+}
+
+type DelegationOptions struct {
+	BlockchainId string
+	// Scope lists the payload types the delegated key may sign, defaults to DATA
+	Scope []string
+	// TTL is the validity of each delegated key, defaults to DEFAULT_DELEGATION_TTL
+	TTL time.Duration
+	// RenewBefore is how long before expiry the key is replaced, defaults to DEFAULT_DELEGATION_RENEW_BEFORE
+	RenewBefore time.Duration
+	// RevokeOnRenew revokes the replaced delegation instead of letting it expire
+	RevokeOnRenew bool
+}
+
+// DelegatedSession signs routine transactions with an ephemeral key delegated by the wallet of the
+// primary session, which only signs the delegations. The key is renewed automatically before it
+// expires, keeping the primary key out of hot paths.
+type DelegatedSession struct {
+	primary *UL_TransactionSession
+	opts    DelegationOptions
+	now     func() time.Time
+
+	mu         sync.Mutex
+	current    *UL_TransactionSession
+	delegation SignedDelegation
+}
+
+// Delegate registers a delegated key with a DELEGATE_KEY transaction signed by the session's wallet
+// and returns the session signing with it
+func (session *UL_TransactionSession) Delegate(ctx context.Context, opts DelegationOptions) (*DelegatedSession, error) {
+	if opts.BlockchainId == "" {
+		opts.BlockchainId = session.defaults.BlockchainId
+	}
+	if len(opts.Scope) == 0 {
+		opts.Scope = []string{TX_DATA.String()}
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = DEFAULT_DELEGATION_TTL
+	}
+	if opts.RenewBefore <= 0 {
+		opts.RenewBefore = DEFAULT_DELEGATION_RENEW_BEFORE
+	}
+	if opts.RenewBefore >= opts.TTL {
+		return nil, fmt.Errorf("the renewal margin %s must be shorter than the delegation TTL %s", opts.RenewBefore, opts.TTL)
+	}
+	if session.delegation != nil {
+		return nil, &ErrInvalidDelegation{Msg: "a delegated key cannot delegate further"}
+	}
+	if err := session.wallet.CheckKey(); err != nil {
+		return nil, err
+	}
+
+	delegated := &DelegatedSession{primary: session, opts: opts, now: time.Now}
+	if err := delegated.Renew(ctx); err != nil {
+		return nil, err
+	}
+	return delegated, nil
+}
+
+// GenerateTransaction signs input with the delegated key, renewing it first when it is about to expire
+func (d *DelegatedSession) GenerateTransaction(input ULTransactionInput) (ULTransaction, error) {
+	d.mu.Lock()
+	if !d.now().Add(d.opts.RenewBefore).Before(d.delegation.Delegation.Expiry) {
+		if err := d.renew(context.Background()); err != nil {
+			d.mu.Unlock()
+			return ULTransaction{}, fmt.Errorf("unable to renew the delegated key: %w", err)
+		}
+	}
+	current := d.current
+	d.mu.Unlock()
+	return current.GenerateTransaction(input)
+}
+
+// Renew replaces the delegated key with a new one, the previous delegation is revoked when
+// RevokeOnRenew is set
+func (d *DelegatedSession) Renew(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.renew(ctx)
+}
+
+func (d *DelegatedSession) renew(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	// Delegated keys share the key type of the delegator so the node verifies them the same way
+	keyType := d.primary.wallet.GetKey().GetType()
+	ephemeral, _, err := wallet.GenerateNewWallet("", keyType, d.primary.wallet.Address, nil, wallet.DefaultEntropy)
+	if err != nil {
+		return fmt.Errorf("unable to generate the delegated key: %w", err)
+	}
+	now := d.now().UTC().Truncate(time.Second)
+	delegator := d.primary.wallet
+	signed, err := SignDelegation(&delegator, Delegation{
+		Delegator:    delegator.Address,
+		BlockchainId: d.opts.BlockchainId,
+		KeyType:      keyType,
+		PublicKey:    ephemeral.GetKey().GetPublicKeyHex(false),
+		Scope:        d.opts.Scope,
+		NotBefore:    now,
+		Expiry:       now.Add(d.opts.TTL),
+	})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(signed)
+	if err != nil {
+		return err
+	}
+	tx, err := d.primary.GenerateTransaction(ULTransactionInput{
+		BlockchainId: d.opts.BlockchainId,
+		To:           signed.Delegation.Address(),
+		Payload:      string(payload),
+		PayloadType:  DELEGATE_KEY.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to register the delegated key: %w", err)
+	}
+	if tx.Output != TX_SUCCESS.String() {
+		return fmt.Errorf("the node refused the delegation with %s", tx.Output)
+	}
+
+	previous := d.delegation
+	d.current = d.primary.withDelegation(ephemeral, signed)
+	d.delegation = signed
+	if d.opts.RevokeOnRenew && previous.Delegation.PublicKey != "" {
+		if err := d.revoke(previous.Delegation.Id(), "renewed"); err != nil {
+			return fmt.Errorf("renewed the delegated key but could not revoke the previous one: %w", err)
+		}
+	}
+	return nil
+}
+
+// Revoke revokes the current delegation, the session cannot sign afterwards
+func (d *DelegatedSession) Revoke(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.revoke(d.delegation.Delegation.Id(), "revoked")
+}
+
+func (d *DelegatedSession) revoke(delegationId string, reason string) error {
+	payload, err := json.Marshal(RevokeDelegationPayload{DelegationId: delegationId, Reason: reason})
+	if err != nil {
+		return err
+	}
+	tx, err := d.primary.GenerateTransaction(ULTransactionInput{
+		BlockchainId: d.opts.BlockchainId,
+		To:           d.primary.wallet.Address,
+		Payload:      string(payload),
+		PayloadType:  REVOKE_DELEGATION.String(),
+	})
+	if err != nil {
+		return err
+	}
+	if tx.Output != TX_SUCCESS.String() {
+		return fmt.Errorf("the node refused the revocation with %s", tx.Output)
+	}
+	return nil
+}
+
+// Delegation returns the delegation of the current key
+func (d *DelegatedSession) Delegation() SignedDelegation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.delegation
+}
+
+// Session returns the session signing with the current delegated key, it is replaced on renewal
+func (d *DelegatedSession) Session() *UL_TransactionSession {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.current
+}
+
+// withDelegation copies the session to sign with a delegated key, metrics stay shared with the original
+func (session *UL_TransactionSession) withDelegation(key wallet.UL_Wallet, delegation SignedDelegation) *UL_TransactionSession {
+	delegated := *session
+	delegated.wallet = key
+	delegated.headers = session.headers.Clone()
+	delegated.delegation = &delegation
+	return &delegated
+}
@@ -0,0 +1,156 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestDelegatedSession(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	primary := session.GetWallet().Address
+
+	delegated, err := session.Delegate(ctx, transaction.DelegationOptions{BlockchainId: testBlockchainId, RevokeOnRenew: true})
+	if err != nil {
+		t.Fatalf("Delegate() error = %v", err)
+	}
+	first := delegated.Delegation()
+	if err := first.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if _, ok := node.Delegation(first.Delegation.Id()); !ok {
+		t.Fatal("the node did not register the delegation")
+	}
+
+	tx, err := delegated.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           primary,
+		Payload:      "signed by the delegated key",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	if tx.From != primary || tx.DelegationId != first.Delegation.Id() || tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("GenerateTransaction() = %+v, want a successful transaction from the delegator", tx)
+	}
+	decoded := transaction.DecodeTransaction(tx, first.Delegation.PublicKey)
+	if !decoded.Signature.Valid {
+		t.Fatalf("signature check = %+v", decoded.Signature)
+	}
+
+	// The scope only covers DATA on one chain
+	_, err = delegated.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           primary,
+		Payload:      "{}",
+		PayloadType:  transaction.CREATE_TOKEN.String(),
+	})
+	scope := &transaction.ErrDelegationScope{}
+	if !errors.As(err, &scope) || scope.PayloadType != transaction.CREATE_TOKEN.String() {
+		t.Fatalf("GenerateTransaction() error = %v, want ErrDelegationScope", err)
+	}
+
+	if err := delegated.Renew(ctx); err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	second := delegated.Delegation()
+	if second.Delegation.PublicKey == first.Delegation.PublicKey {
+		t.Fatal("Renew() kept the delegated key")
+	}
+	if _, ok := node.Delegation(first.Delegation.Id()); ok {
+		t.Fatal("Renew() did not revoke the previous delegation")
+	}
+
+	// A revoked key is refused by the node
+	stale, err := session.Delegate(ctx, transaction.DelegationOptions{BlockchainId: testBlockchainId})
+	if err != nil {
+		t.Fatalf("Delegate() error = %v", err)
+	}
+	if err := stale.Revoke(ctx); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	tx, err = stale.Session().GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           primary,
+		Payload:      "revoked",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil || tx.Output != transaction.TX_REJECTED_BY_UNAUTHORIZED.String() {
+		t.Fatalf("GenerateTransaction() = %s, %v, want REJECTED_BY_UNAUTHORIZED", tx.Output, err)
+	}
+
+	if _, err := delegated.Session().Delegate(ctx, transaction.DelegationOptions{BlockchainId: testBlockchainId}); err == nil {
+		t.Fatal("a delegated key delegated further")
+	}
+}
+
+func TestDelegatedSessionRenewsBeforeExpiry(t *testing.T) {
+	_, session := newMockSession(t)
+	// Every transaction falls inside the renewal margin
+	delegated, err := session.Delegate(context.Background(), transaction.DelegationOptions{
+		BlockchainId: testBlockchainId,
+		TTL:          time.Hour,
+		RenewBefore:  time.Hour - time.Nanosecond,
+	})
+	if err != nil {
+		t.Fatalf("Delegate() error = %v", err)
+	}
+	before := delegated.Delegation().Delegation.Id()
+	tx, err := delegated.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "renewed first",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil || tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("GenerateTransaction() = %+v, %v", tx, err)
+	}
+	if after := delegated.Delegation().Delegation.Id(); after == before || tx.DelegationId != after {
+		t.Fatalf("the key was not renewed before signing, delegation %s", tx.DelegationId)
+	}
+}
+
+func TestDelegationValidation(t *testing.T) {
+	_, session := newMockSession(t)
+	w := session.GetWallet()
+	now := time.Now().UTC()
+	valid := transaction.Delegation{
+		Delegator:    w.Address,
+		BlockchainId: testBlockchainId,
+		PublicKey:    "04aa",
+		Scope:        []string{transaction.TX_DATA.String()},
+		NotBefore:    now,
+		Expiry:       now.Add(time.Hour),
+	}
+	signed, err := transaction.SignDelegation(&w, valid)
+	if err != nil {
+		t.Fatalf("SignDelegation() error = %v", err)
+	}
+	tampered := signed
+	tampered.Delegation.Expiry = now.Add(2 * time.Hour)
+	if err := tampered.Verify(); err == nil {
+		t.Fatal("Verify() accepted a tampered delegation")
+	}
+
+	invalid := []transaction.Delegation{valid, valid, valid}
+	invalid[0].Scope = []string{transaction.DELEGATE_KEY.String()}
+	invalid[1].Expiry = now.Add(transaction.MAX_DELEGATION_TTL + time.Second)
+	invalid[2].Expiry = now
+	for _, d := range invalid {
+		invalidErr := &transaction.ErrInvalidDelegation{}
+		if _, err := transaction.SignDelegation(&w, d); !errors.As(err, &invalidErr) {
+			t.Fatalf("SignDelegation(%+v) error = %v, want ErrInvalidDelegation", d, err)
+		}
+	}
+
+	expired := &transaction.ErrDelegationExpired{}
+	input := transaction.ULTransactionInput{BlockchainId: testBlockchainId, PayloadType: transaction.TX_DATA.String()}
+	if err := valid.Allows(input, now.Add(time.Hour)); !errors.As(err, &expired) {
+		t.Fatalf("Allows() error = %v, want ErrDelegationExpired", err)
+	}
+}
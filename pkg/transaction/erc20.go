@@ -0,0 +1,131 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrInsufficientAllowance is returned before submitting a TransferFrom the allowance does not cover
+type ErrInsufficientAllowance struct {
+	TokenAddress string
+	Owner        string
+	Spender      string
+	Allowance    Amount
+	Amount       Amount
+}
+
+func (e *ErrInsufficientAllowance) Error() string {
+	return fmt.Sprintf("insufficient allowance, %s may move %s of the %s tokens of %s but %s were asked", e.Spender, e.Allowance, e.TokenAddress, e.Owner, e.Amount)
+}
+
+func (e *ErrInsufficientAllowance) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// ERC20Client sends the operations of one ERC20 token from the session's wallet, building the
+// payloads and picking the payload types. Every method fails unless the node applied the transaction.
+//
+//	coin := transaction.NewERC20Client(session, "")
+//	coin.BlockchainId = blockchainId
+//	_, err := coin.CreateToken(ctx, transaction.CreateTokenPayload{Name: "Coin", Symbol: "CN", Decimals: 18})
+//	...
+//	_, err = coin.Transfer(ctx, recipient, amount)
+type ERC20Client struct {
+	// BlockchainId is the chain of the token, empty uses the session defaults
+	BlockchainId string
+
+	session      *UL_TransactionSession
+	tokenAddress string
+}
+
+// NewERC20Client returns a client of the token at tokenAddress, empty for a client that creates
+// its token with CreateToken
+func NewERC20Client(session *UL_TransactionSession, tokenAddress string) *ERC20Client {
+	return &ERC20Client{session: session, tokenAddress: tokenAddress}
+}
+
+// TokenAddress returns the address of the token, empty until CreateToken succeeded
+func (c *ERC20Client) TokenAddress() string {
+	return c.tokenAddress
+}
+
+// CreateToken creates an ERC20 token owned by the session's wallet, which receives the initial
+// supply, and binds the client to it. TokenType is set by the client.
+func (c *ERC20Client) CreateToken(ctx context.Context, payload CreateTokenPayload) (ULTransaction, error) {
+	if c.tokenAddress != "" {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: CREATE_TOKEN.String(), Msg: fmt.Sprintf("the client is bound to the token %s", c.tokenAddress)}
+	}
+	payload.TokenType = ERC20_TOKEN_TYPE
+	tx, tokenAddress, err := c.session.createToken(ctx, c.BlockchainId, payload)
+	if err != nil {
+		return tx, err
+	}
+	c.tokenAddress = tokenAddress
+	return tx, nil
+}
+
+// Transfer moves amount from the session's wallet to to
+func (c *ERC20Client) Transfer(ctx context.Context, to string, amount Amount) (ULTransaction, error) {
+	return c.submit(ctx, TRANSFER_TOKEN, TransferTokenPayload{TokenAddress: c.tokenAddress, To: to, Amount: amount})
+}
+
+// Approve lets spender transfer up to amount on behalf of the session's wallet, replacing the
+// previous allowance
+func (c *ERC20Client) Approve(ctx context.Context, spender string, amount Amount) (ULTransaction, error) {
+	return c.submit(ctx, APPROVE_TOKEN, ApproveTokenPayload{TokenAddress: c.tokenAddress, Spender: spender, Amount: amount})
+}
+
+// TransferFrom moves amount from from to to, consuming the allowance from granted to the session's
+// wallet. The allowance is checked first so a short one fails without a rejected transaction.
+func (c *ERC20Client) TransferFrom(ctx context.Context, from string, to string, amount Amount) (ULTransaction, error) {
+	spender := c.session.wallet.Address
+	allowance, err := c.Allowance(ctx, from, spender)
+	if err != nil {
+		return ULTransaction{}, fmt.Errorf("unable to check the allowance: %w", err)
+	}
+	if allowance.Cmp(amount) < 0 {
+		return ULTransaction{}, &ErrInsufficientAllowance{TokenAddress: c.tokenAddress, Owner: from, Spender: spender, Allowance: allowance, Amount: amount}
+	}
+	return c.submit(ctx, TRANSFER_TOKEN, TransferTokenPayload{TokenAddress: c.tokenAddress, From: from, To: to, Amount: amount})
+}
+
+// Mint creates amount new units for to, the token must be mintable
+func (c *ERC20Client) Mint(ctx context.Context, to string, amount Amount) (ULTransaction, error) {
+	return c.submit(ctx, MINT_TOKEN, MintTokenPayload{TokenAddress: c.tokenAddress, To: to, Amount: amount})
+}
+
+// Burn destroys amount units of the session's wallet, the token must be burnable
+func (c *ERC20Client) Burn(ctx context.Context, amount Amount) (ULTransaction, error) {
+	if amount.IsZero() {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: BURN_TOKEN.String(), Msg: "the amount to burn is zero"}
+	}
+	return c.session.Burn(ctx, c.BlockchainId, c.tokenAddress, amount, 0)
+}
+
+// BalanceOf returns the units owner holds
+func (c *ERC20Client) BalanceOf(ctx context.Context, owner string) (Amount, error) {
+	return c.tokens().BalanceOf(ctx, c.tokenAddress, owner)
+}
+
+// Allowance returns the units spender may still transfer on behalf of owner
+func (c *ERC20Client) Allowance(ctx context.Context, owner string, spender string) (Amount, error) {
+	return c.tokens().Allowance(ctx, c.tokenAddress, owner, spender)
+}
+
+func (c *ERC20Client) tokens() *TokenClient {
+	blockchainId := c.BlockchainId
+	if blockchainId == "" {
+		blockchainId = c.session.defaults.BlockchainId
+	}
+	return NewTokenClient(c.session, blockchainId)
+}
+
+// submit sends a payload of the client's token, transactions are addressed to the token
+func (c *ERC20Client) submit(ctx context.Context, payloadType ULTransactionType, payload any) (ULTransaction, error) {
+	if !isAddress(c.tokenAddress) {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: payloadType.String(), Msg: fmt.Sprintf("invalid token address %q", c.tokenAddress)}
+	}
+	return c.session.submitToken(ctx, c.BlockchainId, payloadType, c.tokenAddress, payload)
+}
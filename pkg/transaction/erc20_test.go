@@ -0,0 +1,74 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestERC20Client(t *testing.T) {
+	node, owner := newMockSession(t)
+	spender := newPeerSession(t, node)
+	ctx := context.Background()
+	recipient := spender.GetWallet().Address
+
+	coin := transaction.NewERC20Client(owner, "")
+	coin.BlockchainId = testBlockchainId
+	if _, err := coin.CreateToken(ctx, transaction.CreateTokenPayload{Name: "Coin", Symbol: "CN", InitialSupply: transaction.NewAmount(1000), Mintable: true, Burnable: true}); err != nil || coin.TokenAddress() == "" {
+		t.Fatalf("CreateToken() = %q, %v", coin.TokenAddress(), err)
+	}
+	if _, err := coin.CreateToken(ctx, transaction.CreateTokenPayload{Name: "Again", Symbol: "AG"}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("CreateToken() on a bound client error = %v", err)
+	}
+
+	steps := []struct {
+		name string
+		run  func() (transaction.ULTransaction, error)
+	}{
+		{"Transfer", func() (transaction.ULTransaction, error) {
+			return coin.Transfer(ctx, recipient, transaction.NewAmount(100))
+		}},
+		{"Mint", func() (transaction.ULTransaction, error) { return coin.Mint(ctx, recipient, transaction.NewAmount(50)) }},
+		{"Burn", func() (transaction.ULTransaction, error) { return coin.Burn(ctx, transaction.NewAmount(200)) }},
+		{"Approve", func() (transaction.ULTransaction, error) {
+			return coin.Approve(ctx, recipient, transaction.NewAmount(30))
+		}},
+	}
+	for _, step := range steps {
+		if tx, err := step.run(); err != nil || tx.Output != transaction.TX_SUCCESS.String() {
+			t.Fatalf("%s() = %+v, %v", step.name, tx.ULTransactionOutput, err)
+		}
+	}
+
+	// The spender moves the owner's tokens through its own client of the token
+	spent := transaction.NewERC20Client(spender, coin.TokenAddress())
+	spent.BlockchainId = testBlockchainId
+	ownerAddress := owner.GetWallet().Address
+	var short *transaction.ErrInsufficientAllowance
+	if _, err := spent.TransferFrom(ctx, ownerAddress, recipient, transaction.NewAmount(31)); !errors.As(err, &short) || !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("TransferFrom() past the allowance error = %v", err)
+	}
+	if _, err := spent.TransferFrom(ctx, ownerAddress, recipient, transaction.NewAmount(30)); err != nil {
+		t.Fatalf("TransferFrom() error = %v", err)
+	}
+
+	balances := map[string]string{ownerAddress: "670", recipient: "180"}
+	for address, want := range balances {
+		if balance, err := coin.BalanceOf(ctx, address); err != nil || balance.String() != want {
+			t.Fatalf("BalanceOf(%s) = %s, %v, want %s", address, balance, err, want)
+		}
+	}
+	if allowance, err := coin.Allowance(ctx, ownerAddress, recipient); err != nil || !allowance.IsZero() {
+		t.Fatalf("Allowance() after TransferFrom = %s, %v", allowance, err)
+	}
+
+	if _, err := transaction.NewERC20Client(owner, "coin").Transfer(ctx, recipient, transaction.NewAmount(1)); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Transfer() of an invalid token error = %v", err)
+	}
+	if _, err := coin.Burn(ctx, transaction.Amount{}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Burn() of nothing error = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+)
+
+// ERC721Client sends the operations of one ERC721 token from the session's wallet, building the
+// payloads and picking the payload types. Every method fails unless the node applied the transaction.
+//
+//	art := transaction.NewERC721Client(session, "")
+//	art.BlockchainId = blockchainId
+//	_, err := art.CreateToken(ctx, transaction.CreateTokenPayload{Name: "Art", Symbol: "ART", Mintable: true})
+//	...
+//	_, err = art.Mint(ctx, owner, 1, "ipfs://art/1.json")
+type ERC721Client struct {
+	// BlockchainId is the chain of the token, empty uses the session defaults
+	BlockchainId string
+
+	session      *UL_TransactionSession
+	tokenAddress string
+}
+
+// NewERC721Client returns a client of the token at tokenAddress, empty for a client that creates
+// its token with CreateToken
+func NewERC721Client(session *UL_TransactionSession, tokenAddress string) *ERC721Client {
+	return &ERC721Client{session: session, tokenAddress: tokenAddress}
+}
+
+// TokenAddress returns the address of the token, empty until CreateToken succeeded
+func (c *ERC721Client) TokenAddress() string {
+	return c.tokenAddress
+}
+
+// CreateToken creates an ERC721 token owned by the session's wallet and binds the client to it.
+// TokenType is set by the client.
+func (c *ERC721Client) CreateToken(ctx context.Context, payload CreateTokenPayload) (ULTransaction, error) {
+	if c.tokenAddress != "" {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: CREATE_TOKEN.String(), Msg: fmt.Sprintf("the client is bound to the token %s", c.tokenAddress)}
+	}
+	payload.TokenType = ERC721_TOKEN_TYPE
+	tx, tokenAddress, err := c.session.createToken(ctx, c.BlockchainId, payload)
+	if err != nil {
+		return tx, err
+	}
+	c.tokenAddress = tokenAddress
+	return tx, nil
+}
+
+// Mint creates the token tokenId for to, the token must be mintable and the id never minted. An
+// empty tokenURI leaves the URI to the token's BaseURI.
+func (c *ERC721Client) Mint(ctx context.Context, to string, tokenId uint64, tokenURI string) (ULTransaction, error) {
+	return c.submit(ctx, MINT_NFT, MintTokenPayload{TokenAddress: c.tokenAddress, To: to, TokenId: tokenId, TokenURI: tokenURI})
+}
+
+// TransferFrom moves the token tokenId of from to to. Unless from is the session's wallet, the
+// wallet must be approved for the token or an operator of from, which is checked first so a missing
+// approval fails without a rejected transaction.
+func (c *ERC721Client) TransferFrom(ctx context.Context, from string, to string, tokenId uint64) (ULTransaction, error) {
+	if from != c.session.wallet.Address {
+		approved, err := c.GetApproved(ctx, tokenId)
+		if err != nil {
+			return ULTransaction{}, fmt.Errorf("unable to check the token approval: %w", err)
+		}
+		if approved != c.session.wallet.Address {
+			if err := c.session.checkOperator(ctx, c.blockchainId(), c.tokenAddress, from); err != nil {
+				return ULTransaction{}, err
+			}
+		}
+	}
+	return c.submit(ctx, TRANSFER_NFT, TransferTokenPayload{TokenAddress: c.tokenAddress, From: from, To: to, TokenId: tokenId})
+}
+
+// Approve lets spender move the token tokenId, replacing the previous approval of the token. An
+// empty spender clears it. Transfers clear the approval too.
+func (c *ERC721Client) Approve(ctx context.Context, spender string, tokenId uint64) (ULTransaction, error) {
+	return c.submit(ctx, APPROVE_NFT, ApproveTokenPayload{TokenAddress: c.tokenAddress, Spender: spender, TokenId: tokenId})
+}
+
+// SetApprovalForAll lets operator move every token of the session's wallet, or withdraws it
+func (c *ERC721Client) SetApprovalForAll(ctx context.Context, operator string, approved bool) (ULTransaction, error) {
+	return c.session.SetApprovalForAll(ctx, c.BlockchainId, c.tokenAddress, operator, approved)
+}
+
+// Burn destroys the token tokenId of the session's wallet, the token must be burnable
+func (c *ERC721Client) Burn(ctx context.Context, tokenId uint64) (ULTransaction, error) {
+	return c.session.Burn(ctx, c.BlockchainId, c.tokenAddress, Amount{}, tokenId)
+}
+
+// OwnerOf returns the owner of the token tokenId, tokens never minted or burned are not found
+func (c *ERC721Client) OwnerOf(ctx context.Context, tokenId uint64) (string, error) {
+	return c.tokens().OwnerOf(ctx, c.tokenAddress, tokenId)
+}
+
+// BalanceOf returns the number of tokens owner holds
+func (c *ERC721Client) BalanceOf(ctx context.Context, owner string) (uint64, error) {
+	balance, err := c.tokens().BalanceOf(ctx, c.tokenAddress, owner)
+	if err != nil {
+		return 0, err
+	}
+	count, ok := balance.Uint64()
+	if !ok {
+		return 0, fmt.Errorf("the node answered a balance of %s tokens", balance)
+	}
+	return count, nil
+}
+
+// GetApproved returns the account approved to move the token tokenId, empty when there is none
+func (c *ERC721Client) GetApproved(ctx context.Context, tokenId uint64) (string, error) {
+	if err := checkTokenAddresses(c.tokenAddress); err != nil {
+		return "", err
+	}
+	response := struct {
+		Approved string `json:"approved"`
+	}{}
+	if err := c.session.getJson(ctx, tokenIdPath(c.blockchainId(), c.tokenAddress, tokenId)+"/approved", &response); err != nil {
+		return "", err
+	}
+	return response.Approved, nil
+}
+
+// TokenURI returns the metadata URI of the token tokenId
+func (c *ERC721Client) TokenURI(ctx context.Context, tokenId uint64) (string, error) {
+	if err := checkTokenAddresses(c.tokenAddress); err != nil {
+		return "", err
+	}
+	return c.session.GetTokenURI(ctx, c.blockchainId(), c.tokenAddress, tokenId)
+}
+
+func (c *ERC721Client) blockchainId() string {
+	if c.BlockchainId == "" {
+		return c.session.defaults.BlockchainId
+	}
+	return c.BlockchainId
+}
+
+func (c *ERC721Client) tokens() *TokenClient {
+	return NewTokenClient(c.session, c.blockchainId())
+}
+
+// submit sends a payload of the client's token, transactions are addressed to the token
+func (c *ERC721Client) submit(ctx context.Context, payloadType ULTransactionType, payload any) (ULTransaction, error) {
+	if !isAddress(c.tokenAddress) {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: payloadType.String(), Msg: fmt.Sprintf("invalid token address %q", c.tokenAddress)}
+	}
+	return c.session.submitToken(ctx, c.BlockchainId, payloadType, c.tokenAddress, payload)
+}
@@ -0,0 +1,87 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestERC721Client(t *testing.T) {
+	node, owner := newMockSession(t)
+	spender := newPeerSession(t, node)
+	ctx := context.Background()
+	ownerAddress, spenderAddress := owner.GetWallet().Address, spender.GetWallet().Address
+	other := fmt.Sprintf("%064x", 1)
+
+	art := transaction.NewERC721Client(owner, "")
+	art.BlockchainId = testBlockchainId
+	if _, err := art.CreateToken(ctx, transaction.CreateTokenPayload{Name: "Art", Symbol: "ART", BaseURI: "ipfs://art/{id}", Mintable: true, Burnable: true}); err != nil || art.TokenAddress() == "" {
+		t.Fatalf("CreateToken() = %q, %v", art.TokenAddress(), err)
+	}
+
+	steps := []struct {
+		name string
+		run  func() (transaction.ULTransaction, error)
+	}{
+		{"Mint", func() (transaction.ULTransaction, error) { return art.Mint(ctx, ownerAddress, 1, "ipfs://one.json") }},
+		{"Mint", func() (transaction.ULTransaction, error) { return art.Mint(ctx, ownerAddress, 2, "") }},
+		{"Mint", func() (transaction.ULTransaction, error) { return art.Mint(ctx, ownerAddress, 3, "") }},
+		{"Approve", func() (transaction.ULTransaction, error) { return art.Approve(ctx, spenderAddress, 1) }},
+		{"Burn", func() (transaction.ULTransaction, error) { return art.Burn(ctx, 3) }},
+	}
+	for _, step := range steps {
+		if tx, err := step.run(); err != nil || tx.Output != transaction.TX_SUCCESS.String() {
+			t.Fatalf("%s() = %+v, %v", step.name, tx.ULTransactionOutput, err)
+		}
+	}
+	if _, err := art.Mint(ctx, ownerAddress, 1, ""); err == nil {
+		t.Fatalf("Mint() of a minted id succeeded")
+	}
+
+	if uri, err := art.TokenURI(ctx, 1); err != nil || uri != "ipfs://one.json" {
+		t.Fatalf("TokenURI() = %q, %v", uri, err)
+	}
+	if uri, err := art.TokenURI(ctx, 2); err != nil || uri != fmt.Sprintf("ipfs://art/%064x", 2) {
+		t.Fatalf("TokenURI() from the base URI = %q, %v", uri, err)
+	}
+	if _, err := art.OwnerOf(ctx, 3); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("OwnerOf() of a burned token error = %v", err)
+	}
+
+	// The spender moves the token it was approved for, and the others once it is an operator
+	spent := transaction.NewERC721Client(spender, art.TokenAddress())
+	spent.BlockchainId = testBlockchainId
+	if _, err := spent.TransferFrom(ctx, ownerAddress, other, 1); err != nil {
+		t.Fatalf("TransferFrom() of an approved token error = %v", err)
+	}
+	if approved, err := art.GetApproved(ctx, 1); err != nil || approved != "" {
+		t.Fatalf("GetApproved() after TransferFrom = %q, %v", approved, err)
+	}
+	var notApproved *transaction.ErrOperatorNotApproved
+	if _, err := spent.TransferFrom(ctx, ownerAddress, other, 2); !errors.As(err, &notApproved) {
+		t.Fatalf("TransferFrom() without approval error = %v", err)
+	}
+	if _, err := art.SetApprovalForAll(ctx, spenderAddress, true); err != nil {
+		t.Fatalf("SetApprovalForAll() error = %v", err)
+	}
+	if _, err := spent.TransferFrom(ctx, ownerAddress, spenderAddress, 2); err != nil {
+		t.Fatalf("TransferFrom() as an operator error = %v", err)
+	}
+
+	counts := map[string]uint64{ownerAddress: 0, spenderAddress: 1, other: 1}
+	for address, want := range counts {
+		if count, err := art.BalanceOf(ctx, address); err != nil || count != want {
+			t.Fatalf("BalanceOf(%s) = %d, %v, want %d", address, count, err, want)
+		}
+	}
+	if got, err := art.OwnerOf(ctx, 1); err != nil || got != other {
+		t.Fatalf("OwnerOf() = %s, %v", got, err)
+	}
+	if _, err := transaction.NewERC721Client(owner, "art").TokenURI(ctx, 1); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("TokenURI() of an invalid token error = %v", err)
+	}
+}
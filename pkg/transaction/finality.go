@@ -0,0 +1,294 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// Blocks, including the one holding the transaction, required to consider it final
+	DEFAULT_FINALITY_THRESHOLD = 3
+)
+
+// Finality describes how deeply a transaction is buried in the chain
+type Finality struct {
+	TransactionId string
+	BlockHeight   int // Zero while the transaction has not been sealed in a block
+	BlockHash     string
+	ChainHeight   int
+	Confirmations int // Blocks on top of the transaction's block plus the block itself
+	Threshold     int
+	Final         bool
+}
+
+// SetFinalityThreshold changes the confirmations GetFinality requires before reporting a transaction as final
+func (session *UL_TransactionSession) SetFinalityThreshold(threshold int) {
+	session.finalityThreshold = threshold
+}
+
+func (session *UL_TransactionSession) getFinalityThreshold() int {
+	if session.finalityThreshold <= 0 {
+		return DEFAULT_FINALITY_THRESHOLD
+	}
+	return session.finalityThreshold
+}
+
+// GetTransaction fetches a transaction by id as currently known by the node
+func (session *UL_TransactionSession) GetTransaction(ctx context.Context, blockchainId string, transactionId string) (ULTransaction, error) {
+	tx := ULTransaction{}
+	err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/transactions/%s", blockchainId, transactionId), &tx)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	return tx, nil
+}
+
+// GetFinality reports the confirmations of a transaction against the session's finality threshold
+func (session *UL_TransactionSession) GetFinality(ctx context.Context, blockchainId string, transactionId string) (Finality, error) {
+	finality := Finality{
+		TransactionId: transactionId,
+		Threshold:     session.getFinalityThreshold(),
+	}
+
+	tx, err := session.GetTransaction(ctx, blockchainId, transactionId)
+	if err != nil {
+		return Finality{}, err
+	}
+
+	chain, err := session.getChainInfo(ctx, blockchainId)
+	if err != nil {
+		return Finality{}, err
+	}
+	finality.ChainHeight = chain.Height
+
+	if tx.BlockHeight <= 0 {
+		return finality, nil
+	}
+
+	block, err := session.GetBlock(ctx, blockchainId, tx.BlockHeight)
+	if err != nil {
+		return Finality{}, err
+	}
+
+	// The node may still point at a block that no longer holds the transaction after a reorg
+	if !block.ContainsTransaction(transactionId) {
+		return finality, nil
+	}
+
+	finality.BlockHeight = block.Height
+	finality.BlockHash = block.Hash
+	finality.Confirmations = chain.Height - block.Height + 1
+	finality.Final = finality.Confirmations >= finality.Threshold
+	return finality, nil
+}
+
+// ContainsTransaction reports whether the block includes the transaction id
+func (b *ULBlock) ContainsTransaction(transactionId string) bool {
+	for _, tx := range b.Transactions {
+		if tx.TransactionId == transactionId {
+			return true
+		}
+	}
+	return false
+}
+
+type FinalityEventType int
+
+const (
+	// The transaction reached the finality threshold, it is no longer tracked
+	FINALITY_REACHED FinalityEventType = iota
+	// The block the transaction was seen in has been replaced
+	FINALITY_REORGED
+)
+
+func (t FinalityEventType) String() string {
+	switch t {
+	case FINALITY_REACHED:
+		return "FINALITY_REACHED"
+	case FINALITY_REORGED:
+		return "FINALITY_REORGED"
+	default:
+		return ""
+	}
+}
+
+type FinalityEvent struct {
+	Type FinalityEventType
+	// Finality after the change, BlockHeight is zero if the transaction is no longer in any block
+	Finality Finality
+	// Block the transaction was previously seen in, only set for FINALITY_REORGED
+	PreviousBlockHeight int
+	PreviousBlockHash   string
+}
+
+// FinalityWatcher follows transactions until they become final and reports blocks that get replaced under them
+type FinalityWatcher struct {
+	session      *UL_TransactionSession
+	blockchainId string
+	events       chan FinalityEvent
+	added        chan struct{}
+
+	mu      sync.Mutex
+	tracked map[string]Finality
+}
+
+// WatchFinality starts a watcher that re-evaluates the tracked transactions on every block and reorg
+// of the chain until the context is cancelled, at which point the events channel is closed. Changes
+// come from StreamChain when the node supports it, otherwise from SubscribeBlocks polling the node
+// at the given interval.
+func (session *UL_TransactionSession) WatchFinality(ctx context.Context, blockchainId string, pollInterval time.Duration) *FinalityWatcher {
+	watcher := &FinalityWatcher{
+		session:      session,
+		blockchainId: blockchainId,
+		events:       make(chan FinalityEvent, 16),
+		added:        make(chan struct{}, 1),
+		tracked:      make(map[string]Finality),
+	}
+	changes, err := session.StreamChain(ctx, blockchainId, StreamOptions{})
+	if err != nil {
+		changes = watcher.subscribe(ctx, pollInterval)
+	}
+	go watcher.run(ctx, changes)
+	return watcher
+}
+
+// Track adds a transaction to the watch list
+func (watcher *FinalityWatcher) Track(transactionId string) {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+	if _, ok := watcher.tracked[transactionId]; !ok {
+		watcher.tracked[transactionId] = Finality{TransactionId: transactionId}
+	}
+	// Evaluate it right away, it may already be sealed
+	select {
+	case watcher.added <- struct{}{}:
+	default:
+	}
+}
+
+func (watcher *FinalityWatcher) Events() <-chan FinalityEvent {
+	return watcher.events
+}
+
+// subscribe turns the blocks of a subscription into chain events. The subscription stops at a block
+// that does not extend the one before it, which is reported as a reorg of that block before
+// following the chain again from its height.
+func (watcher *FinalityWatcher) subscribe(ctx context.Context, pollInterval time.Duration) <-chan ChainEvent {
+	changes := make(chan ChainEvent, 16)
+	send := func(ctx context.Context, event ChainEvent) error {
+		select {
+		case changes <- event:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	go func() {
+		defer close(changes)
+		// Every block only triggers an evaluation, so older blocks need not be delivered
+		start, _ := watcher.session.GetBlockHeight(ctx, watcher.blockchainId)
+		for {
+			opts := BlockSubscriptionOptions{Consumer: "finality", StartHeight: start, PollInterval: pollInterval}
+			err := watcher.session.SubscribeBlocks(ctx, watcher.blockchainId, opts, func(ctx context.Context, block ULBlock) error {
+				return send(ctx, ChainEvent{Type: CHAIN_EVENT_BLOCK, BlockchainId: watcher.blockchainId, Block: block})
+			})
+			var mismatch *ErrCheckpointMismatch
+			if !errors.As(err, &mismatch) {
+				return
+			}
+			reorg := ChainReorg{Height: mismatch.Checkpoint.Height, Hash: mismatch.Checkpoint.Hash, Depth: 1}
+			if send(ctx, ChainEvent{Type: CHAIN_EVENT_REORG, BlockchainId: watcher.blockchainId, Reorg: reorg}) != nil {
+				return
+			}
+			start = reorg.Height
+		}
+	}()
+	return changes
+}
+
+func (watcher *FinalityWatcher) run(ctx context.Context, changes <-chan ChainEvent) {
+	defer close(watcher.events)
+	for {
+		var reorg *ChainReorg
+		select {
+		case <-ctx.Done():
+			return
+		case <-watcher.added:
+		case change, ok := <-changes:
+			if !ok {
+				return
+			}
+			switch change.Type {
+			case CHAIN_EVENT_TRANSACTION:
+				// The block carrying it is evaluated as a whole
+				continue
+			case CHAIN_EVENT_REORG:
+				reorg = &change.Reorg
+			}
+		}
+
+		for _, event := range watcher.evaluate(ctx, reorg) {
+			select {
+			case watcher.events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// evaluate refreshes the finality of every tracked transaction. Transactions in blocks replaced by
+// reorg are reported as no longer sealed if the node cannot tell where they are yet.
+func (watcher *FinalityWatcher) evaluate(ctx context.Context, reorg *ChainReorg) []FinalityEvent {
+	watcher.mu.Lock()
+	tracked := make([]Finality, 0, len(watcher.tracked))
+	for _, finality := range watcher.tracked {
+		tracked = append(tracked, finality)
+	}
+	watcher.mu.Unlock()
+
+	events := make([]FinalityEvent, 0)
+	for _, previous := range tracked {
+		current, err := watcher.session.GetFinality(ctx, watcher.blockchainId, previous.TransactionId)
+		if err != nil {
+			if reorg == nil || previous.BlockHash == "" || previous.BlockHeight < reorg.Height || previous.BlockHeight >= reorg.Height+max(reorg.Depth, 1) {
+				// The transaction may not be visible yet, try again on the next change
+				continue
+			}
+			current = Finality{TransactionId: previous.TransactionId, Threshold: watcher.session.getFinalityThreshold()}
+		}
+		events = append(events, watcher.update(previous.TransactionId, current)...)
+	}
+	return events
+}
+
+// update records the latest finality of a transaction and returns the events the change produced
+func (watcher *FinalityWatcher) update(transactionId string, current Finality) []FinalityEvent {
+	watcher.mu.Lock()
+	defer watcher.mu.Unlock()
+
+	previous, ok := watcher.tracked[transactionId]
+	if !ok {
+		return nil
+	}
+	events := make([]FinalityEvent, 0)
+	if previous.BlockHash != "" && previous.BlockHash != current.BlockHash {
+		events = append(events, FinalityEvent{
+			Type:                FINALITY_REORGED,
+			Finality:            current,
+			PreviousBlockHeight: previous.BlockHeight,
+			PreviousBlockHash:   previous.BlockHash,
+		})
+	}
+
+	if current.Final {
+		events = append(events, FinalityEvent{Type: FINALITY_REACHED, Finality: current})
+		delete(watcher.tracked, transactionId)
+		return events
+	}
+	watcher.tracked[transactionId] = current
+	return events
+}
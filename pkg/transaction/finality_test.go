@@ -0,0 +1,92 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestGetFinality(t *testing.T) {
+	node, session := newMockSession(t)
+	session.SetFinalityThreshold(3)
+	tx := submitData(t, session, "payment")
+
+	finality, err := session.GetFinality(context.Background(), testBlockchainId, tx.TransactionId)
+	if err != nil {
+		t.Fatalf("GetFinality() error = %v", err)
+	}
+	if finality.Confirmations != 1 || finality.Final {
+		t.Errorf("GetFinality() = %+v, want 1 confirmation and not final", finality)
+	}
+
+	node.AppendEmptyBlock(testBlockchainId)
+	node.AppendEmptyBlock(testBlockchainId)
+	finality, err = session.GetFinality(context.Background(), testBlockchainId, tx.TransactionId)
+	if err != nil {
+		t.Fatalf("GetFinality() error = %v", err)
+	}
+	if finality.Confirmations != 3 || !finality.Final {
+		t.Errorf("GetFinality() = %+v, want 3 confirmations and final", finality)
+	}
+}
+
+func TestWatchFinalityReportsReorg(t *testing.T) {
+	node, session := newMockSession(t)
+	node.SetFeatures(transaction.NODE_FEATURE_EVENT_STREAM)
+	session.SetFinalityThreshold(2)
+	tx := submitData(t, session, "payment")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watcher := session.WatchFinality(ctx, testBlockchainId, 10*time.Millisecond)
+	watcher.Track(tx.TransactionId)
+
+	// Give the watcher time to connect and record the original block before replacing it
+	time.Sleep(50 * time.Millisecond)
+	if err := node.ReplaceBlock(testBlockchainId, tx.BlockHeight, false); err != nil {
+		t.Fatalf("ReplaceBlock() error = %v", err)
+	}
+
+	event := <-watcher.Events()
+	if event.Type != transaction.FINALITY_REORGED {
+		t.Fatalf("first event = %s, want %s", event.Type, transaction.FINALITY_REORGED)
+	}
+	if event.PreviousBlockHash == event.Finality.BlockHash {
+		t.Errorf("reorg event kept the same block hash %s", event.PreviousBlockHash)
+	}
+
+	node.AppendEmptyBlock(testBlockchainId)
+	event = <-watcher.Events()
+	if event.Type != transaction.FINALITY_REACHED || !event.Finality.Final {
+		t.Errorf("second event = %+v, want final", event)
+	}
+}
+
+func TestWatchFinalityPollsWithoutEventStream(t *testing.T) {
+	node, session := newMockSession(t)
+	session.SetFinalityThreshold(2)
+	tx := submitData(t, session, "payment")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	watcher := session.WatchFinality(ctx, testBlockchainId, 10*time.Millisecond)
+	watcher.Track(tx.TransactionId)
+
+	time.Sleep(50 * time.Millisecond)
+	if err := node.ReplaceBlock(testBlockchainId, tx.BlockHeight, false); err != nil {
+		t.Fatalf("ReplaceBlock() error = %v", err)
+	}
+	// The subscription notices the replaced block once the next one does not extend it
+	node.AppendEmptyBlock(testBlockchainId)
+
+	event := <-watcher.Events()
+	if event.Type != transaction.FINALITY_REORGED || event.PreviousBlockHash == event.Finality.BlockHash {
+		t.Fatalf("first event = %+v, want %s", event, transaction.FINALITY_REORGED)
+	}
+	event = <-watcher.Events()
+	if event.Type != transaction.FINALITY_REACHED || !event.Finality.Final {
+		t.Errorf("second event = %+v, want final", event)
+	}
+}
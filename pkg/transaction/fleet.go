@@ -0,0 +1,198 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// FleetIssue is a difference between a wallet file and the registration of its wallet
+type FleetIssue string
+
+const (
+	// FLEET_INVALID files cannot be read or their address does not match their public key
+	FLEET_INVALID FleetIssue = "invalid"
+	// FLEET_UNREGISTERED wallets are not registered on the chain
+	FLEET_UNREGISTERED FleetIssue = "unregistered"
+	// FLEET_DISABLED wallets are disabled on the chain while their file says enabled
+	FLEET_DISABLED FleetIssue = "disabled"
+	// FLEET_AUTH_MISMATCH wallets are registered with other auth groups than their file
+	FLEET_AUTH_MISMATCH FleetIssue = "auth_mismatch"
+	// FLEET_KEY_MISMATCH wallets are registered with another public key or key type than their file
+	FLEET_KEY_MISMATCH FleetIssue = "key_mismatch"
+)
+
+// FleetWallet is the health of one wallet file, it is healthy without issues
+type FleetWallet struct {
+	Path    string       `json:"path"`
+	Address string       `json:"address,omitempty"`
+	Issues  []FleetIssue `json:"issues,omitempty"`
+	// Detail explains the issues
+	Detail     string            `json:"detail,omitempty"`
+	Local      wallet.WalletData `json:"-"`
+	Registered *ULWalletInfo     `json:"registered,omitempty"`
+}
+
+func (w FleetWallet) Healthy() bool {
+	return len(w.Issues) == 0
+}
+
+func (w *FleetWallet) addIssue(issue FleetIssue, detail string) {
+	w.Issues = append(w.Issues, issue)
+	if w.Detail != "" {
+		w.Detail += "; "
+	}
+	w.Detail += detail
+}
+
+// FleetReport lists the wallet files of a directory by path
+type FleetReport struct {
+	BlockchainId string        `json:"blockchainId"`
+	Wallets      []FleetWallet `json:"wallets"`
+	Checked      time.Time     `json:"checked"`
+}
+
+// Count returns the number of wallets with issue
+func (report FleetReport) Count(issue FleetIssue) int {
+	count := 0
+	for _, w := range report.Wallets {
+		if slices.Contains(w.Issues, issue) {
+			count++
+		}
+	}
+	return count
+}
+
+// Unhealthy returns the number of wallets with any issue
+func (report FleetReport) Unhealthy() int {
+	count := 0
+	for _, w := range report.Wallets {
+		if !w.Healthy() {
+			count++
+		}
+	}
+	return count
+}
+
+// Healthy reports whether every wallet matches its registration
+func (report FleetReport) Healthy() bool {
+	return report.Unhealthy() == 0
+}
+
+// CheckFleet compares the .ukey files of dir with the wallets registered on a chain. Only the public
+// part of the files is read, so no passphrase is needed. An error is only returned when the directory
+// or the registered wallets cannot be listed.
+func CheckFleet(ctx context.Context, session QueryAPI, blockchainId string, dir string) (FleetReport, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return FleetReport{}, fmt.Errorf("failed to read wallet directory: %w", err)
+	}
+	registered := map[string]ULWalletInfo{}
+	it := session.ListWallets(blockchainId, "", ListOptions{PageSize: MAX_PAGE_SIZE})
+	for it.Next(ctx) {
+		registered[it.Value().Address] = it.Value()
+	}
+	if err := it.Err(); err != nil {
+		return FleetReport{}, fmt.Errorf("unable to list the registered wallets: %w", err)
+	}
+
+	report := FleetReport{BlockchainId: blockchainId, Wallets: []FleetWallet{}, Checked: time.Now()}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".ukey") {
+			continue
+		}
+		report.Wallets = append(report.Wallets, checkFleetWallet(filepath.Join(dir, entry.Name()), registered))
+	}
+	return report, nil
+}
+
+func checkFleetWallet(path string, registered map[string]ULWalletInfo) FleetWallet {
+	result := FleetWallet{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.addIssue(FLEET_INVALID, err.Error())
+		return result
+	}
+	if err := json.Unmarshal(data, &result.Local); err != nil {
+		result.addIssue(FLEET_INVALID, utils.HandleJsonError(err))
+		return result
+	}
+	// The report never holds secrets
+	result.Local.Mnemonic, result.Local.PrivateKeyHex, result.Local.Crypto = "", "", nil
+	local := result.Local
+	result.Address = strings.ToLower(local.Address)
+	if local.PublicKeyHex == "" || wallet.ParseAddress(local.PublicKeyHex) != result.Address {
+		result.addIssue(FLEET_INVALID, "the address does not match the public key")
+		return result
+	}
+
+	info, ok := registered[result.Address]
+	if !ok {
+		result.addIssue(FLEET_UNREGISTERED, "not registered")
+		return result
+	}
+	result.Registered = &info
+	if !strings.EqualFold(info.PublicKey, local.PublicKeyHex) || info.KeyType != local.KeyType {
+		result.addIssue(FLEET_KEY_MISMATCH, "registered with another key")
+	}
+	if local.Enabled && !info.Enabled {
+		result.addIssue(FLEET_DISABLED, "disabled on the chain")
+	}
+	if !maps.Equal(local.AuthGroups, info.AuthGroups) {
+		result.addIssue(FLEET_AUTH_MISMATCH, fmt.Sprintf("auth groups %s registered, %s in the file", authGroupNames(info.AuthGroups), authGroupNames(local.AuthGroups)))
+	}
+	return result
+}
+
+func authGroupNames(groups map[string]wallet.UL_AuthPermission) string {
+	if len(groups) == 0 {
+		return "none"
+	}
+	return strings.Join(slices.Sorted(maps.Keys(groups)), ",")
+}
+
+// FixInputs builds the transactions aligning the chain with the files: CREATE_WALLET for unregistered
+// wallets, sent from their parent, and ALTER_WALLET restoring the enabled state and auth groups of
+// the file, to be signed by a wallet allowed to alter the target. Invalid files and key mismatches
+// cannot be fixed by a transaction and are left out. Review the inputs before submitting them.
+func (report FleetReport) FixInputs() ([]ULTransactionInput, error) {
+	inputs := []ULTransactionInput{}
+	for _, w := range report.Wallets {
+		var payload any
+		input := ULTransactionInput{BlockchainId: report.BlockchainId, To: w.Address}
+		switch {
+		case slices.Contains(w.Issues, FLEET_INVALID), slices.Contains(w.Issues, FLEET_KEY_MISMATCH):
+			continue
+		case slices.Contains(w.Issues, FLEET_UNREGISTERED):
+			input.From = w.Local.Parent
+			input.PayloadType = TX_CREATE_WALLET.String()
+			payload = CreateWalletPayload{
+				PublicKey:  w.Local.PublicKeyHex,
+				Parent:     w.Local.Parent,
+				KeyType:    w.Local.KeyType,
+				AuthGroups: w.Local.AuthGroups,
+			}
+		case slices.Contains(w.Issues, FLEET_DISABLED), slices.Contains(w.Issues, FLEET_AUTH_MISMATCH):
+			input.PayloadType = TX_ALTER_WALLET.String()
+			payload = AlterWalletPayload{Target: w.Address, Enabled: w.Local.Enabled, AuthGroups: w.Local.AuthGroups}
+		default:
+			continue
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		input.Payload = string(data)
+		inputs = append(inputs, input)
+	}
+	return inputs, nil
+}
@@ -0,0 +1,109 @@
+package transaction_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestCheckFleet(t *testing.T) {
+	node, admin := newMockSession(t)
+	registerSigner(t, admin)
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	groups := map[string]wallet.UL_AuthPermission{"data": {Create: true}}
+	// fleetWallet generates a wallet, registers it when asked and saves its file
+	fleetWallet := func(name string, register bool) (wallet.UL_Wallet, *transaction.UL_TransactionSession) {
+		w, mnemonic, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, admin.GetWallet().Address, groups, wallet.DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		session, _ := transaction.NewUL_TransactionSession(node.URL(), w)
+		if register {
+			input, _ := transaction.RegisterWalletInput(testBlockchainId, w)
+			if _, err := session.GenerateTransaction(input); err != nil {
+				t.Fatalf("registering %s error = %v", name, err)
+			}
+		}
+		if err := w.SaveToFile(filepath.Join(dir, name), mnemonic, false); err != nil {
+			t.Fatalf("SaveToFile() error = %v", err)
+		}
+		return w, &session
+	}
+
+	fleetWallet("healthy", true)
+	unregistered, _ := fleetWallet("unregistered", false)
+	disabled, disabledSession := fleetWallet("disabled", true)
+	retire, _ := transaction.RetireWalletInput(testBlockchainId, disabled)
+	if _, err := disabledSession.GenerateTransaction(retire); err != nil {
+		t.Fatalf("retiring error = %v", err)
+	}
+	mismatched, _ := fleetWallet("mismatched", true)
+	mismatched.AuthGroups = map[string]wallet.UL_AuthPermission{"data": {Create: true, Read: true}}
+	if err := mismatched.SaveToFile(filepath.Join(dir, "mismatched"), "", false); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "broken.ukey"), []byte("{"), 0600)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a wallet"), 0600)
+
+	report, err := transaction.CheckFleet(ctx, admin, testBlockchainId, dir)
+	if err != nil {
+		t.Fatalf("CheckFleet() error = %v", err)
+	}
+	want := map[string][]transaction.FleetIssue{
+		"broken.ukey":       {transaction.FLEET_INVALID},
+		"disabled.ukey":     {transaction.FLEET_DISABLED},
+		"healthy.ukey":      nil,
+		"mismatched.ukey":   {transaction.FLEET_AUTH_MISMATCH},
+		"unregistered.ukey": {transaction.FLEET_UNREGISTERED},
+	}
+	if len(report.Wallets) != len(want) {
+		t.Fatalf("CheckFleet() reported %d wallets, want %d", len(report.Wallets), len(want))
+	}
+	for _, result := range report.Wallets {
+		if issues := want[filepath.Base(result.Path)]; !slices.Equal(result.Issues, issues) {
+			t.Errorf("%s issues = %v (%s), want %v", filepath.Base(result.Path), result.Issues, result.Detail, issues)
+		}
+		if result.Local.Mnemonic != "" {
+			t.Errorf("%s report holds the mnemonic", result.Path)
+		}
+	}
+	if report.Healthy() || report.Unhealthy() != 4 || report.Count(transaction.FLEET_UNREGISTERED) != 1 {
+		t.Fatalf("report counts unhealthy = %d, unregistered = %d", report.Unhealthy(), report.Count(transaction.FLEET_UNREGISTERED))
+	}
+
+	inputs, err := report.FixInputs()
+	if err != nil || len(inputs) != 3 {
+		t.Fatalf("FixInputs() = %d inputs, %v, want 3", len(inputs), err)
+	}
+	for _, input := range inputs {
+		// Unregistered wallets send their own registration, like RegisterWalletInput
+		signer := admin
+		if input.To == unregistered.Address {
+			session, _ := transaction.NewUL_TransactionSession(node.URL(), unregistered)
+			signer = &session
+		}
+		tx, err := signer.GenerateTransaction(input)
+		if err != nil || tx.Output != transaction.TX_SUCCESS.String() {
+			t.Fatalf("fix-up %s of %s = %s, %v", input.PayloadType, input.To, tx.Output, err)
+		}
+	}
+
+	report, err = transaction.CheckFleet(ctx, admin, testBlockchainId, dir)
+	if err != nil {
+		t.Fatalf("CheckFleet() error = %v", err)
+	}
+	if report.Unhealthy() != 1 || report.Count(transaction.FLEET_INVALID) != 1 {
+		t.Fatalf("after the fix-ups unhealthy = %d, want only the broken file", report.Unhealthy())
+	}
+	if inputs, _ := report.FixInputs(); len(inputs) != 0 {
+		t.Fatalf("FixInputs() of a fixed fleet = %+v", inputs)
+	}
+}
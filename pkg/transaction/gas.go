@@ -0,0 +1,92 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_GAS_ESTIMATION is advertised by nodes simulating contract invocations, see EstimateGas
+const NODE_FEATURE_GAS_ESTIMATION = "gas-estimation"
+
+// DEFAULT_GAS_MARGIN is the share of the simulated gas EstimateGas adds to the suggested limit
+const DEFAULT_GAS_MARGIN = 0.2
+
+// ErrGasEstimationUnsupported is returned by EstimateGas when the node does not advertise
+// NODE_FEATURE_GAS_ESTIMATION
+type ErrGasEstimationUnsupported struct{}
+
+func (e *ErrGasEstimationUnsupported) Error() string {
+	return "the node does not simulate contract invocations"
+}
+
+func (e *ErrGasEstimationUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// GasEstimateRequest is the body of a simulated invocation, From is the sender the contract sees.
+// A GasLimit of the payload caps the simulation, the node's maximum applies when zero.
+type GasEstimateRequest struct {
+	InvokeContractPayload
+	From string `json:"from,omitempty"`
+}
+
+// GasEstimateResult is the node's answer to a simulated invocation
+type GasEstimateResult struct {
+	GasUsed uint64 `json:"gasUsed"`
+}
+
+type GasEstimateOptions struct {
+	// Margin is the share of the simulated gas added to the suggested limit, DEFAULT_GAS_MARGIN when
+	// zero. A negative Margin adds nothing.
+	Margin float64
+}
+
+// GasEstimate is the gas a simulated invocation used and the limit to invoke the function with
+type GasEstimate struct {
+	GasUsed  uint64
+	GasLimit uint64
+}
+
+// EstimateGas simulates the invocation payload describes on the node, from the session's wallet,
+// and suggests a gas limit: the gas used plus a safety margin, since the state may change before
+// the invocation executes. Nothing is signed or recorded. Simulations the contract fails return
+// the node's error. Nodes must advertise NODE_FEATURE_GAS_ESTIMATION.
+func (session *UL_TransactionSession) EstimateGas(ctx context.Context, blockchainId string, contractAddress string, payload InvokeContractPayload, opts GasEstimateOptions) (GasEstimate, error) {
+	// The arguments of the payload are encoded already, only the target is checked
+	if _, err := encodeContractArgs(contractAddress, payload.FunctionName, nil); err != nil {
+		return GasEstimate{}, err
+	}
+	supported, err := session.hasFeature(ctx, NODE_FEATURE_GAS_ESTIMATION)
+	if err != nil {
+		return GasEstimate{}, err
+	}
+	if !supported {
+		return GasEstimate{}, &ErrGasEstimationUnsupported{}
+	}
+
+	request := GasEstimateRequest{InvokeContractPayload: payload, From: session.wallet.Address}
+	result := GasEstimateResult{}
+	if err := session.Do(ctx, "POST", fmt.Sprintf("/blockchains/%s/contracts/%s/estimate", blockchainId, contractAddress), request, &result); err != nil {
+		return GasEstimate{}, err
+	}
+	return GasEstimate{GasUsed: result.GasUsed, GasLimit: gasLimitWithMargin(result.GasUsed, opts.Margin)}, nil
+}
+
+// gasLimitWithMargin adds the margin to the gas used, rounding up
+func gasLimitWithMargin(gasUsed uint64, margin float64) uint64 {
+	if margin == 0 {
+		margin = DEFAULT_GAS_MARGIN
+	}
+	if margin < 0 {
+		return gasUsed
+	}
+	// Products such as 1000 * 0.2 can land a hair above the whole number they stand for
+	extra := math.Ceil(float64(gasUsed)*margin - 1e-6)
+	if extra >= float64(math.MaxUint64-gasUsed) {
+		return math.MaxUint64
+	}
+	return gasUsed + uint64(extra)
+}
@@ -0,0 +1,74 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestEstimateGas(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	deployed, err := session.DeployContract(ctx, testBlockchainId, []byte("(module)"), transaction.ContractUploadOptions{Inline: true})
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	contract := transaction.NewContractClient(session, deployed.TransactionId)
+	contract.BlockchainId = testBlockchainId
+	node.SetContractFunction(contract.ContractAddress(), "mint", func(state map[string]interface{}, args []interface{}, emit func(name string, value interface{})) error {
+		amount := args[0].(int32)
+		if amount <= 0 {
+			return fmt.Errorf("nothing to mint")
+		}
+		state["supply"] = amount
+		return nil
+	})
+
+	if _, err := contract.EstimateGas(ctx, "mint", int32(10)); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("EstimateGas() without gas estimation error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_GAS_ESTIMATION)
+
+	estimate, err := contract.EstimateGas(ctx, "mint", int32(10))
+	if err != nil {
+		t.Fatalf("EstimateGas() error = %v", err)
+	}
+	if estimate.GasUsed != transactiontest.MOCK_INVOKE_GAS || estimate.GasLimit != 25200 {
+		t.Fatalf("EstimateGas() = %+v, want the mock gas plus 20%%", estimate)
+	}
+	if state, _ := node.ContractState(contract.ContractAddress()); state["supply"] != nil {
+		t.Fatalf("EstimateGas() changed the contract state to %v", state)
+	}
+
+	encoded, _ := transaction.Encode(int32(10))
+	payload := transaction.InvokeContractPayload{FunctionName: "mint", Args: []transaction.ContractArgs{{Value: encoded}}}
+	for _, test := range []struct {
+		margin float64
+		want   uint64
+	}{
+		{0.1, 23100},
+		{0.5, 31500},
+		{-1, transactiontest.MOCK_INVOKE_GAS},
+	} {
+		estimate, err := session.EstimateGas(ctx, testBlockchainId, contract.ContractAddress(), payload, transaction.GasEstimateOptions{Margin: test.margin})
+		if err != nil || estimate.GasLimit != test.want {
+			t.Errorf("EstimateGas() with margin %v = %+v, %v, want a limit of %d", test.margin, estimate, err, test.want)
+		}
+	}
+
+	if _, err := contract.EstimateGas(ctx, "mint", int32(0)); err == nil {
+		t.Fatal("EstimateGas() of an invocation failing in the contract succeeded")
+	}
+	payload.GasLimit = 1000
+	if _, err := session.EstimateGas(ctx, testBlockchainId, contract.ContractAddress(), payload, transaction.GasEstimateOptions{}); err == nil {
+		t.Fatal("EstimateGas() running out of gas succeeded")
+	}
+	if _, err := session.EstimateGas(ctx, testBlockchainId, "token", payload, transaction.GasEstimateOptions{}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("EstimateGas() of an invalid address error = %v", err)
+	}
+}
@@ -0,0 +1,362 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// MIN_UPGRADE_APPROVALS keeps a single approver from upgrading a contract on its own
+const MIN_UPGRADE_APPROVALS = 2
+
+// UpgradeStatus is where a proposal stands in its UpgradeGovernance
+type UpgradeStatus string
+
+const (
+	UPGRADE_PROPOSED  UpgradeStatus = "PROPOSED"
+	UPGRADE_APPROVED  UpgradeStatus = "APPROVED"
+	UPGRADE_EXECUTED  UpgradeStatus = "EXECUTED"
+	UPGRADE_FAILED    UpgradeStatus = "FAILED"
+	UPGRADE_CANCELLED UpgradeStatus = "CANCELLED"
+)
+
+// UpgradeProposal describes an UPGRADE_SMART_CONTRACT the approvers sign. The source is identified
+// by its hash, see HashContractSource, so approvers sign exactly the code that will be submitted.
+type UpgradeProposal struct {
+	BlockchainId    string    `json:"blockchainId"`
+	ContractAddress string    `json:"contractAddress"`
+	SourceHash      string    `json:"sourceHash"`
+	Reason          string    `json:"reason"`
+	EffectiveAt     time.Time `json:"effectiveAt"`
+	Proposer        string    `json:"proposer"`
+	ProposedAt      time.Time `json:"proposedAt"`
+}
+
+// UpgradeApproval is the message signature of an approver over the JSON encoding of a proposal
+type UpgradeApproval struct {
+	ProposalId string                  `json:"proposalId"`
+	Signature  wallet.MessageSignature `json:"signature"`
+}
+
+// GovernanceEvent is one entry of the audit trail of a proposal
+type GovernanceEvent struct {
+	ProposalId string        `json:"proposalId"`
+	Status     UpgradeStatus `json:"status"`
+	Actor      string        `json:"actor"`
+	At         time.Time     `json:"at"`
+	Detail     string        `json:"detail,omitempty"`
+}
+
+// UpgradeRecord is a proposal with its approvals, the upgrade transaction once executed and its
+// audit trail
+type UpgradeRecord struct {
+	Proposal    UpgradeProposal   `json:"proposal"`
+	Status      UpgradeStatus     `json:"status"`
+	Approvals   []UpgradeApproval `json:"approvals"`
+	Transaction ULTransaction     `json:"transaction,omitzero"`
+	Events      []GovernanceEvent `json:"events"`
+}
+
+// ErrInvalidUpgradeProposal is returned for malformed proposals and approvals
+type ErrInvalidUpgradeProposal struct {
+	ProposalId string
+	Msg        string
+}
+
+func (e *ErrInvalidUpgradeProposal) Error() string {
+	if e.ProposalId == "" {
+		return fmt.Sprintf("invalid upgrade proposal, %s", e.Msg)
+	}
+	return fmt.Sprintf("invalid upgrade proposal %s, %s", e.ProposalId, e.Msg)
+}
+
+func (e *ErrInvalidUpgradeProposal) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrUpgradeNotAllowed is returned instead of submitting an upgrade the governance does not allow yet
+type ErrUpgradeNotAllowed struct {
+	ProposalId string
+	Status     UpgradeStatus
+	Msg        string
+}
+
+func (e *ErrUpgradeNotAllowed) Error() string {
+	return fmt.Sprintf("upgrade %s is %s, %s", e.ProposalId, e.Status, e.Msg)
+}
+
+func (e *ErrUpgradeNotAllowed) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// HashContractSource returns the hex SHA-256 of a contract source, the SourceHash of its proposals
+func HashContractSource(source string) string {
+	digest := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(digest[:])
+}
+
+// Bytes is the JSON encoding approvers sign
+func (p UpgradeProposal) Bytes() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+// Id is the hex SHA-256 of the JSON encoding
+func (p UpgradeProposal) Id() string {
+	data, _ := p.Bytes()
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+// Validate checks the proposal is well formed
+func (p UpgradeProposal) Validate() error {
+	switch {
+	case p.BlockchainId == "":
+		return &ErrInvalidUpgradeProposal{Msg: "the blockchain id is missing"}
+	case !isAddress(p.ContractAddress):
+		return &ErrInvalidUpgradeProposal{Msg: fmt.Sprintf("invalid contract address %q", p.ContractAddress)}
+	case !isAddress(p.SourceHash):
+		return &ErrInvalidUpgradeProposal{Msg: fmt.Sprintf("invalid source hash %q", p.SourceHash)}
+	case p.Reason == "":
+		return &ErrInvalidUpgradeProposal{Msg: "the reason is missing"}
+	case p.Proposer == "":
+		return &ErrInvalidUpgradeProposal{Msg: "the proposer is missing"}
+	}
+	return nil
+}
+
+// ApproveUpgrade signs proposal with the approver's wallet
+func ApproveUpgrade(approver *wallet.UL_Wallet, proposal UpgradeProposal) (UpgradeApproval, error) {
+	if err := proposal.Validate(); err != nil {
+		return UpgradeApproval{}, err
+	}
+	data, err := proposal.Bytes()
+	if err != nil {
+		return UpgradeApproval{}, err
+	}
+	signature, err := approver.SignMessage(data)
+	if err != nil {
+		return UpgradeApproval{}, err
+	}
+	return UpgradeApproval{ProposalId: proposal.Id(), Signature: signature}, nil
+}
+
+// UpgradeGovernance holds contract upgrades back until a threshold of its approvers signed them. It
+// keeps the proposals in memory and is safe for concurrent use, Records returns them with their
+// audit trails for archiving.
+//
+//	governance, _ := transaction.NewUpgradeGovernance(approvers, 2)
+//	id, _ := governance.Propose(proposal)
+//	// each approver: approval, _ := transaction.ApproveUpgrade(&approverWallet, proposal)
+//	err := governance.Approve(approval)
+//	...
+//	tx, err := governance.Execute(&session, id, source)
+type UpgradeGovernance struct {
+	approvers []string
+	threshold int
+
+	mu        sync.Mutex
+	proposals map[string]*UpgradeRecord
+	order     []string
+}
+
+// NewUpgradeGovernance returns a governance where threshold of approvers, by address, must sign a
+// proposal before it is executed
+func NewUpgradeGovernance(approvers []string, threshold int) (*UpgradeGovernance, error) {
+	if threshold < MIN_UPGRADE_APPROVALS || threshold > len(approvers) {
+		return nil, &ErrInvalidUpgradeProposal{Msg: fmt.Sprintf("a threshold of %d for %d approvers, at least %d approvals are needed", threshold, len(approvers), MIN_UPGRADE_APPROVALS)}
+	}
+	for i, approver := range approvers {
+		if slices.Contains(approvers[:i], approver) {
+			return nil, &ErrInvalidUpgradeProposal{Msg: fmt.Sprintf("%s approves twice", approver)}
+		}
+	}
+	return &UpgradeGovernance{
+		approvers: slices.Clone(approvers),
+		threshold: threshold,
+		proposals: make(map[string]*UpgradeRecord),
+	}, nil
+}
+
+// Propose records a proposal and returns its id. ProposedAt is set when it is zero.
+func (g *UpgradeGovernance) Propose(proposal UpgradeProposal) (string, error) {
+	if proposal.ProposedAt.IsZero() {
+		proposal.ProposedAt = time.Now().UTC()
+	}
+	if err := proposal.Validate(); err != nil {
+		return "", err
+	}
+	id := proposal.Id()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := g.proposals[id]; ok {
+		return "", &ErrInvalidUpgradeProposal{ProposalId: id, Msg: "it was already proposed"}
+	}
+	record := &UpgradeRecord{Proposal: proposal, Status: UPGRADE_PROPOSED}
+	g.proposals[id] = record
+	g.order = append(g.order, id)
+	g.record(record, proposal.Proposer, proposal.Reason)
+	return id, nil
+}
+
+// Proposal returns the proposal id, e.g. for the next approver to sign
+func (g *UpgradeGovernance) Proposal(id string) (UpgradeProposal, error) {
+	record, err := g.Record(id)
+	return record.Proposal, err
+}
+
+// Approve records the approval of a listed approver, the proposal is approved once the threshold of
+// them signed it
+func (g *UpgradeGovernance) Approve(approval UpgradeApproval) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	record, err := g.lookup(approval.ProposalId)
+	if err != nil {
+		return err
+	}
+	approver := approval.Signature.Address
+	switch {
+	case record.Status != UPGRADE_PROPOSED && record.Status != UPGRADE_APPROVED:
+		return &ErrUpgradeNotAllowed{ProposalId: approval.ProposalId, Status: record.Status, Msg: "it no longer takes approvals"}
+	case !slices.Contains(g.approvers, approver):
+		return &ErrInvalidUpgradeProposal{ProposalId: approval.ProposalId, Msg: fmt.Sprintf("%s is not an approver", approver)}
+	case slices.ContainsFunc(record.Approvals, func(a UpgradeApproval) bool { return a.Signature.Address == approver }):
+		return &ErrInvalidUpgradeProposal{ProposalId: approval.ProposalId, Msg: fmt.Sprintf("%s already approved", approver)}
+	}
+	data, err := record.Proposal.Bytes()
+	if err != nil {
+		return err
+	}
+	if ok, err := wallet.VerifyMessage(approver, data, approval.Signature); err != nil || !ok {
+		return &ErrInvalidUpgradeProposal{ProposalId: approval.ProposalId, Msg: fmt.Sprintf("the approval of %s is not signed by it", approver)}
+	}
+
+	record.Approvals = append(record.Approvals, approval)
+	if len(record.Approvals) >= g.threshold {
+		record.Status = UPGRADE_APPROVED
+	}
+	g.record(record, approver, fmt.Sprintf("%d of %d approvals", len(record.Approvals), g.threshold))
+	return nil
+}
+
+// Cancel withdraws a proposal that was not executed, by names who cancelled it in the audit trail
+func (g *UpgradeGovernance) Cancel(id string, by string, reason string) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	record, err := g.lookup(id)
+	if err != nil {
+		return err
+	}
+	if record.Status == UPGRADE_EXECUTED || record.Status == UPGRADE_CANCELLED {
+		return &ErrUpgradeNotAllowed{ProposalId: id, Status: record.Status, Msg: "it cannot be cancelled"}
+	}
+	record.Status = UPGRADE_CANCELLED
+	g.record(record, by, reason)
+	return nil
+}
+
+// Execute submits the upgrade of an approved proposal once it is effective. source must hash to
+// the proposal's SourceHash. A failed upgrade is FAILED and cannot be retried, it is proposed again.
+func (g *UpgradeGovernance) Execute(session *UL_TransactionSession, id string, source string) (ULTransaction, error) {
+	// The lock is held while submitting so the same upgrade is never submitted twice
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	record, err := g.lookup(id)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	proposal := record.Proposal
+	switch {
+	case record.Status != UPGRADE_APPROVED:
+		return ULTransaction{}, &ErrUpgradeNotAllowed{ProposalId: id, Status: record.Status, Msg: fmt.Sprintf("%d of %d approvals", len(record.Approvals), g.threshold)}
+	case time.Now().Before(proposal.EffectiveAt):
+		return ULTransaction{}, &ErrUpgradeNotAllowed{ProposalId: id, Status: record.Status, Msg: fmt.Sprintf("it is effective at %s", proposal.EffectiveAt.Format(time.RFC3339))}
+	case HashContractSource(source) != proposal.SourceHash:
+		return ULTransaction{}, &ErrInvalidUpgradeProposal{ProposalId: id, Msg: "the source is not the one approved"}
+	}
+
+	payload, err := json.Marshal(UpgradeContractPayload{NewSourceCode: source, UpgradeReason: proposal.Reason})
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	tx, err := session.GenerateTransaction(ULTransactionInput{
+		BlockchainId: proposal.BlockchainId,
+		To:           proposal.ContractAddress,
+		Payload:      string(payload),
+		PayloadType:  UPGRADE_SMART_CONTRACT.String(),
+	})
+	if err != nil {
+		// Nothing reached the chain, the approved proposal can be executed again
+		return tx, err
+	}
+	record.Transaction = tx
+	if tx.Output != TX_SUCCESS.String() {
+		record.Status = UPGRADE_FAILED
+		g.record(record, session.wallet.Address, fmt.Sprintf("%s: %s", tx.TransactionId, tx.Output))
+		return tx, fmt.Errorf("%s %s was not applied: %s", UPGRADE_SMART_CONTRACT, tx.TransactionId, tx.Output)
+	}
+	record.Status = UPGRADE_EXECUTED
+	g.record(record, session.wallet.Address, tx.TransactionId)
+	return tx, nil
+}
+
+// Status returns where the proposal id stands
+func (g *UpgradeGovernance) Status(id string) (UpgradeStatus, error) {
+	record, err := g.Record(id)
+	return record.Status, err
+}
+
+// Record returns a copy of the proposal id with its approvals and audit trail
+func (g *UpgradeGovernance) Record(id string) (UpgradeRecord, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	record, err := g.lookup(id)
+	if err != nil {
+		return UpgradeRecord{}, err
+	}
+	return record.clone(), nil
+}
+
+// Records returns a copy of every proposal in the order they were proposed
+func (g *UpgradeGovernance) Records() []UpgradeRecord {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	records := make([]UpgradeRecord, 0, len(g.order))
+	for _, id := range g.order {
+		records = append(records, g.proposals[id].clone())
+	}
+	return records
+}
+
+func (g *UpgradeGovernance) lookup(id string) (*UpgradeRecord, error) {
+	record, ok := g.proposals[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: upgrade proposal %s", utils.ErrNotFound, id)
+	}
+	return record, nil
+}
+
+// record appends the current status of record to its audit trail
+func (g *UpgradeGovernance) record(record *UpgradeRecord, actor string, detail string) {
+	record.Events = append(record.Events, GovernanceEvent{
+		ProposalId: record.Proposal.Id(),
+		Status:     record.Status,
+		Actor:      actor,
+		At:         time.Now().UTC(),
+		Detail:     detail,
+	})
+}
+
+func (r *UpgradeRecord) clone() UpgradeRecord {
+	clone := *r
+	clone.Approvals = slices.Clone(r.Approvals)
+	clone.Events = slices.Clone(r.Events)
+	return clone
+}
@@ -0,0 +1,140 @@
+package transaction_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestUpgradeGovernance(t *testing.T) {
+	node, session := newMockSession(t)
+	approvers := make([]wallet.UL_Wallet, 3)
+	addresses := make([]string, len(approvers))
+	for i := range approvers {
+		w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		approvers[i], addresses[i] = w, w.Address
+	}
+	governance, err := transaction.NewUpgradeGovernance(addresses, 2)
+	if err != nil {
+		t.Fatalf("NewUpgradeGovernance() error = %v", err)
+	}
+
+	source := "contract v2"
+	id, err := governance.Propose(transaction.UpgradeProposal{
+		BlockchainId:    testBlockchainId,
+		ContractAddress: fmt.Sprintf("%064x", 7),
+		SourceHash:      transaction.HashContractSource(source),
+		Reason:          "emit transfer events",
+		Proposer:        session.GetWallet().Address,
+	})
+	if err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+	proposal, _ := governance.Proposal(id)
+
+	// A single approval is not enough
+	first, err := transaction.ApproveUpgrade(&approvers[0], proposal)
+	if err != nil {
+		t.Fatalf("ApproveUpgrade() error = %v", err)
+	}
+	if err := governance.Approve(first); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if err := governance.Approve(first); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Approve() twice error = %v", err)
+	}
+	if _, err := governance.Execute(session, id, source); !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Execute() with one approval error = %v", err)
+	}
+	outsider, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if approval, _ := transaction.ApproveUpgrade(&outsider, proposal); !errors.Is(governance.Approve(approval), utils.ErrInvalidInput) {
+		t.Fatal("Approve() of an outsider succeeded")
+	}
+	// A signature over another proposal does not approve this one
+	altered := proposal
+	altered.Reason = "something else"
+	forged, _ := transaction.ApproveUpgrade(&approvers[1], altered)
+	forged.ProposalId = id
+	if err := governance.Approve(forged); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Approve() of another proposal error = %v", err)
+	}
+
+	second, _ := transaction.ApproveUpgrade(&approvers[1], proposal)
+	if err := governance.Approve(second); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if status, _ := governance.Status(id); status != transaction.UPGRADE_APPROVED {
+		t.Fatalf("Status() = %s, want %s", status, transaction.UPGRADE_APPROVED)
+	}
+	if _, err := governance.Execute(session, id, "contract v3"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Execute() of another source error = %v", err)
+	}
+	tx, err := governance.Execute(session, id, source)
+	if err != nil || tx.PayloadType != transaction.UPGRADE_SMART_CONTRACT.String() {
+		t.Fatalf("Execute() = %+v, %v", tx, err)
+	}
+	if _, err := governance.Execute(session, id, source); !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Execute() twice error = %v", err)
+	}
+	if txs := node.Transactions(); len(txs) != 1 || txs[0].To != proposal.ContractAddress {
+		t.Fatalf("node transactions = %d", len(txs))
+	}
+
+	record, _ := governance.Record(id)
+	statuses := []transaction.UpgradeStatus{}
+	for _, event := range record.Events {
+		statuses = append(statuses, event.Status)
+	}
+	if want := fmt.Sprint([]transaction.UpgradeStatus{transaction.UPGRADE_PROPOSED, transaction.UPGRADE_PROPOSED, transaction.UPGRADE_APPROVED, transaction.UPGRADE_EXECUTED}); fmt.Sprint(statuses) != want || record.Transaction.TransactionId != tx.TransactionId {
+		t.Fatalf("audit trail = %v, want %s", statuses, want)
+	}
+}
+
+func TestUpgradeGovernanceEffectiveTime(t *testing.T) {
+	_, session := newMockSession(t)
+	a, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeED25519, "", nil, wallet.DefaultEntropy)
+	b, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeED25519, "", nil, wallet.DefaultEntropy)
+	if _, err := transaction.NewUpgradeGovernance([]string{a.Address}, 1); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("NewUpgradeGovernance() of a single approver error = %v", err)
+	}
+	governance, _ := transaction.NewUpgradeGovernance([]string{a.Address, b.Address}, 2)
+
+	proposal := transaction.UpgradeProposal{
+		BlockchainId:    testBlockchainId,
+		ContractAddress: fmt.Sprintf("%064x", 7),
+		SourceHash:      transaction.HashContractSource("v2"),
+		Reason:          "maintenance window",
+		EffectiveAt:     time.Now().Add(time.Hour),
+		Proposer:        a.Address,
+	}
+	id, _ := governance.Propose(proposal)
+	proposal, _ = governance.Proposal(id)
+	for _, approver := range []*wallet.UL_Wallet{&a, &b} {
+		approval, _ := transaction.ApproveUpgrade(approver, proposal)
+		if err := governance.Approve(approval); err != nil {
+			t.Fatalf("Approve() error = %v", err)
+		}
+	}
+	var notAllowed *transaction.ErrUpgradeNotAllowed
+	if _, err := governance.Execute(session, id, "v2"); !errors.As(err, &notAllowed) {
+		t.Fatalf("Execute() before the effective time error = %v", err)
+	}
+	if err := governance.Cancel(id, b.Address, "postponed"); err != nil {
+		t.Fatalf("Cancel() error = %v", err)
+	}
+	if status, _ := governance.Status(id); status != transaction.UPGRADE_CANCELLED {
+		t.Fatalf("Status() after Cancel = %s", status)
+	}
+	if _, err := governance.Status("unknown"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("Status() of an unknown proposal error = %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// GuardrailLimit names the limit a transaction exceeded
+type GuardrailLimit string
+
+const (
+	GUARDRAIL_PAYLOAD_BYTES GuardrailLimit = "payload bytes"
+	GUARDRAIL_WEIGHT        GuardrailLimit = "weight"
+	GUARDRAIL_GAS           GuardrailLimit = "gas"
+)
+
+// Guardrails are client side limits checked before a transaction is signed, so pipelines fed by a
+// faulty upstream stop instead of submitting pathological transactions. Zero disables a limit.
+type Guardrails struct {
+	MaxPayloadBytes int
+	// MaxWeight bounds the weight the node will compute for the transaction, see EstimateWeight
+	MaxWeight int
+	// MaxGas bounds the gas limit of INVOKE_SMART_CONTRACT payloads. A zero gas limit leaves the
+	// budget to the node and is not checked.
+	MaxGas uint64
+}
+
+// ErrGuardrail is returned by GenerateTransaction when a transaction exceeds a guardrail
+type ErrGuardrail struct {
+	Limit       GuardrailLimit
+	PayloadType string
+	Max         uint64
+	Value       uint64
+}
+
+func (e *ErrGuardrail) Error() string {
+	return fmt.Sprintf("%s transaction exceeds the %s guardrail, max is %d, got %d", e.PayloadType, e.Limit, e.Max, e.Value)
+}
+
+func (e *ErrGuardrail) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// SetGuardrails replaces the limits checked before every transaction is signed
+func (session *UL_TransactionSession) SetGuardrails(guardrails Guardrails) {
+	session.guardrails = guardrails
+}
+
+func (session *UL_TransactionSession) Guardrails() Guardrails {
+	return session.guardrails
+}
+
+// Check fails with ErrGuardrail when the input exceeds one of the limits, keyType sizes the
+// signature for the weight estimate
+func (guardrails Guardrails) Check(input ULTransactionInput, keyType crypto.KeyType) error {
+	if guardrails.MaxPayloadBytes > 0 && len(input.Payload) > guardrails.MaxPayloadBytes {
+		return &ErrGuardrail{
+			Limit:       GUARDRAIL_PAYLOAD_BYTES,
+			PayloadType: input.PayloadType,
+			Max:         uint64(guardrails.MaxPayloadBytes),
+			Value:       uint64(len(input.Payload)),
+		}
+	}
+	if guardrails.MaxWeight > 0 {
+		weight, err := EstimateWeight(input, keyType)
+		if err != nil {
+			return err
+		}
+		if weight > guardrails.MaxWeight {
+			return &ErrGuardrail{Limit: GUARDRAIL_WEIGHT, PayloadType: input.PayloadType, Max: uint64(guardrails.MaxWeight), Value: uint64(weight)}
+		}
+	}
+	if guardrails.MaxGas > 0 && input.PayloadType == INVOKE_SMART_CONTRACT.String() {
+		invoke := InvokeContractPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &invoke); err != nil {
+			return fmt.Errorf("unable to read the gas limit of the payload: %w", err)
+		}
+		if invoke.GasLimit > guardrails.MaxGas {
+			return &ErrGuardrail{Limit: GUARDRAIL_GAS, PayloadType: input.PayloadType, Max: guardrails.MaxGas, Value: invoke.GasLimit}
+		}
+	}
+	return nil
+}
+
+// EstimateWeight returns the weight SetTransactionWeight gives the input once it is signed by a key
+// of keyType and assigned an id, before either exists. It assumes raw signatures, DER adds at most
+// 16 hex characters.
+func EstimateWeight(input ULTransactionInput, keyType crypto.KeyType) (int, error) {
+	capabilities, err := crypto.Capabilities(keyType)
+	if err != nil {
+		return 0, err
+	}
+	tx := ULTransaction{ULTransactionInput: input}
+	tx.SenderSignature = ""
+	tx.Version = TRANSACTION_VERSION
+	tx.SetTransactionWeight()
+	// The signature and the id are hex encoded, the id is a SHA-256
+	return tx.Weight + 2*capabilities.SignatureSize + 64, nil
+}
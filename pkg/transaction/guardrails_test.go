@@ -0,0 +1,81 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestGuardrails(t *testing.T) {
+	node, session := newMockSession(t)
+	session.SetGuardrails(transaction.Guardrails{MaxPayloadBytes: 64, MaxWeight: 600, MaxGas: 1000})
+
+	tx := submitData(t, session, "within limits")
+	tx.SetTransactionWeight()
+	estimate, err := transaction.EstimateWeight(tx.ULTransactionInput, crypto.KeyTypeSecp256k1)
+	if err != nil || estimate != tx.Weight {
+		t.Fatalf("EstimateWeight() = %d, %v, want %d", estimate, err, tx.Weight)
+	}
+	submitted := len(node.Transactions())
+
+	invoke, _ := json.Marshal(transaction.InvokeContractPayload{FunctionName: "spin", GasLimit: 5000})
+	tests := []struct {
+		name        string
+		input       transaction.ULTransactionInput
+		limit       transaction.GuardrailLimit
+		max         uint64
+		value       uint64
+		payloadType string
+	}{
+		{
+			name:        "payload",
+			input:       transaction.ULTransactionInput{Payload: strings.Repeat("a", 65), PayloadType: transaction.TX_DATA.String()},
+			limit:       transaction.GUARDRAIL_PAYLOAD_BYTES,
+			max:         64,
+			value:       65,
+			payloadType: transaction.TX_DATA.String(),
+		},
+		{
+			name:        "gas",
+			input:       transaction.ULTransactionInput{Payload: string(invoke), PayloadType: transaction.INVOKE_SMART_CONTRACT.String()},
+			limit:       transaction.GUARDRAIL_GAS,
+			max:         1000,
+			value:       5000,
+			payloadType: transaction.INVOKE_SMART_CONTRACT.String(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.BlockchainId = testBlockchainId
+			tt.input.To = session.GetWallet().Address
+			_, err := session.GenerateTransaction(tt.input)
+			guardrail := &transaction.ErrGuardrail{}
+			if !errors.As(err, &guardrail) {
+				t.Fatalf("GenerateTransaction() error = %v, want ErrGuardrail", err)
+			}
+			if guardrail.Limit != tt.limit || guardrail.Max != tt.max || guardrail.Value != tt.value || guardrail.PayloadType != tt.payloadType {
+				t.Fatalf("ErrGuardrail = %+v", guardrail)
+			}
+		})
+	}
+
+	// The weight counts the addresses and signature on top of the payload
+	session.SetGuardrails(transaction.Guardrails{MaxWeight: estimate})
+	_, err = session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "within limits, barely not",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	guardrail := &transaction.ErrGuardrail{}
+	if !errors.As(err, &guardrail) || guardrail.Limit != transaction.GUARDRAIL_WEIGHT {
+		t.Fatalf("GenerateTransaction() error = %v, want the weight guardrail", err)
+	}
+	if len(node.Transactions()) != submitted {
+		t.Fatal("a transaction exceeding a guardrail was submitted")
+	}
+}
@@ -0,0 +1,214 @@
+package transaction
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const DEFAULT_PROBE_TIMEOUT = 5 * time.Second
+
+type ProbeOptions struct {
+	// Timeout bounds each probe, DEFAULT_PROBE_TIMEOUT when zero
+	Timeout time.Duration
+	// Headers are sent with every probe, e.g. an Authorization token required by a gateway
+	Headers http.Header
+}
+
+// ChainHealth is one endpoint's view of a chain. HeightLag is how far the endpoint is behind the
+// highest height any probed endpoint reported for the chain.
+type ChainHealth struct {
+	Height           int       `json:"height"`
+	HeightLag        int       `json:"heightLag"`
+	Pending          int       `json:"pending"`
+	PeerCount        int       `json:"peerCount"`
+	CommitteeMembers []string  `json:"committeeMembers"`
+	IsInCommittee    bool      `json:"isInCommittee"`
+	IsVoting         bool      `json:"isVoting"`
+	LastMessage      time.Time `json:"lastMessage"`
+}
+
+// EndpointHealth is the outcome of probing one endpoint. ClockSkew is the node clock minus the local
+// clock, read from the Date header of the response, so it is only accurate to the second.
+type EndpointHealth struct {
+	Endpoint  string                 `json:"endpoint"`
+	Reachable bool                   `json:"reachable"`
+	Error     string                 `json:"error,omitempty"`
+	Latency   time.Duration          `json:"latency"`
+	NodeId    string                 `json:"nodeId,omitempty"`
+	PeerId    string                 `json:"peerId,omitempty"`
+	Version   string                 `json:"version,omitempty"`
+	Features  []string               `json:"features,omitempty"`
+	ClockSkew time.Duration          `json:"clockSkew"`
+	Chains    map[string]ChainHealth `json:"chains,omitempty"`
+}
+
+// HealthReport compares the endpoints probed together, Endpoints keeps the order they were given in
+type HealthReport struct {
+	ProbedAt   time.Time        `json:"probedAt"`
+	Endpoints  []EndpointHealth `json:"endpoints"`
+	MaxHeights map[string]int   `json:"maxHeights"`
+}
+
+// ProbeEndpoints probes every endpoint concurrently and compares their views of each chain.
+// Unreachable endpoints are reported rather than failing the whole probe.
+func ProbeEndpoints(ctx context.Context, endpoints []string, opts ProbeOptions) HealthReport {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DEFAULT_PROBE_TIMEOUT
+	}
+	report := HealthReport{
+		ProbedAt:   time.Now().UTC(),
+		Endpoints:  make([]EndpointHealth, len(endpoints)),
+		MaxHeights: make(map[string]int),
+	}
+	var wg sync.WaitGroup
+	for i, endpoint := range endpoints {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Endpoints[i] = probeEndpoint(ctx, endpoint, opts)
+		}()
+	}
+	wg.Wait()
+
+	for _, endpoint := range report.Endpoints {
+		for id, chain := range endpoint.Chains {
+			if chain.Height > report.MaxHeights[id] {
+				report.MaxHeights[id] = chain.Height
+			}
+		}
+	}
+	for _, endpoint := range report.Endpoints {
+		for id, chain := range endpoint.Chains {
+			chain.HeightLag = report.MaxHeights[id] - chain.Height
+			endpoint.Chains[id] = chain
+		}
+	}
+	return report
+}
+
+func probeEndpoint(ctx context.Context, endpoint string, opts ProbeOptions) EndpointHealth {
+	health := EndpointHealth{Endpoint: endpoint}
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(endpoint, "/")+"/health", nil)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	for key, values := range opts.Headers {
+		req.Header[key] = values
+	}
+	started := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	health.Latency = time.Since(started)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		health.Error = err.Error()
+		return health
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		health.Error = (&NodeError{StatusCode: resp.StatusCode, Method: http.MethodGet, Path: "/health", Body: string(body)}).Error()
+		return health
+	}
+	info := healthInfo{}
+	if err := json.Unmarshal(body, &info); err != nil {
+		health.Error = fmt.Sprintf("invalid health response: %v", err)
+		return health
+	}
+
+	health.Reachable = true
+	health.NodeId = info.NodeId
+	health.PeerId = info.PeerId
+	health.Version = info.Version
+	health.Features = info.Features
+	if date, err := http.ParseTime(resp.Header.Get("Date")); err == nil {
+		// Compare against the middle of the round trip, the Date header has a one second resolution
+		health.ClockSkew = date.Sub(started.Add(health.Latency / 2).Truncate(time.Second))
+	}
+	health.Chains = make(map[string]ChainHealth, len(info.Chains))
+	for id, chain := range info.Chains {
+		health.Chains[id] = ChainHealth{
+			Height:           chain.Height,
+			Pending:          len(chain.Pending),
+			PeerCount:        chain.PeerCount,
+			CommitteeMembers: chain.CommitteeMembers,
+			IsInCommittee:    chain.IsInCommittee,
+			IsVoting:         chain.IsVoting,
+			LastMessage:      chain.LastMessage,
+		}
+	}
+	return health
+}
+
+// Best picks the endpoint to use for a chain, the reachable endpoint serving it with the smallest
+// height lag, then the lowest latency. ok is false when no endpoint serves the chain.
+func (report HealthReport) Best(blockchainId string) (EndpointHealth, bool) {
+	candidates := []EndpointHealth{}
+	for _, endpoint := range report.Endpoints {
+		if _, serves := endpoint.Chains[blockchainId]; endpoint.Reachable && serves {
+			candidates = append(candidates, endpoint)
+		}
+	}
+	if len(candidates) == 0 {
+		return EndpointHealth{}, false
+	}
+	slices.SortStableFunc(candidates, func(a, b EndpointHealth) int {
+		if lag := a.Chains[blockchainId].HeightLag - b.Chains[blockchainId].HeightLag; lag != 0 {
+			return lag
+		}
+		return cmp.Compare(a.Latency, b.Latency)
+	})
+	return candidates[0], true
+}
+
+// Unhealthy lists the endpoints that are unreachable, lag more than maxLag blocks behind on a chain
+// or whose clock is off by more than maxSkew. A zero maxSkew skips the clock check.
+func (report HealthReport) Unhealthy(maxLag int, maxSkew time.Duration) []EndpointHealth {
+	unhealthy := []EndpointHealth{}
+	for _, endpoint := range report.Endpoints {
+		skewed := maxSkew > 0 && (endpoint.ClockSkew > maxSkew || endpoint.ClockSkew < -maxSkew)
+		lagging := false
+		for _, chain := range endpoint.Chains {
+			lagging = lagging || chain.HeightLag > maxLag
+		}
+		if !endpoint.Reachable || skewed || lagging {
+			unhealthy = append(unhealthy, endpoint)
+		}
+	}
+	return unhealthy
+}
+
+// WatchEndpoints probes the endpoints every interval and hands each report to handler, starting
+// immediately. It blocks until the context is cancelled.
+func WatchEndpoints(ctx context.Context, endpoints []string, opts ProbeOptions, interval time.Duration, handler func(HealthReport)) error {
+	if interval <= 0 {
+		interval = DEFAULT_POLL_INTERVAL
+	}
+	for {
+		report := ProbeEndpoints(ctx, endpoints, opts)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handler(report)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
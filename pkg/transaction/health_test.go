@@ -0,0 +1,67 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+)
+
+func TestProbeEndpoints(t *testing.T) {
+	ahead := transactiontest.NewMockNode(testBlockchainId)
+	defer ahead.Close()
+	behind := transactiontest.NewMockNode(testBlockchainId, "Other")
+	defer behind.Close()
+	down := transactiontest.NewMockNode(testBlockchainId)
+	down.Close()
+
+	ahead.AppendEmptyBlock(testBlockchainId)
+	ahead.AppendEmptyBlock(testBlockchainId)
+	behind.SetVoting(testBlockchainId, false)
+
+	endpoints := []string{behind.URL(), down.URL(), ahead.URL()}
+	report := transaction.ProbeEndpoints(context.Background(), endpoints, transaction.ProbeOptions{Timeout: time.Second})
+	if len(report.Endpoints) != 3 || report.Endpoints[0].Endpoint != behind.URL() || report.Endpoints[2].Endpoint != ahead.URL() {
+		t.Fatalf("Endpoints = %+v, want the given order", report.Endpoints)
+	}
+	if report.Endpoints[1].Reachable || report.Endpoints[1].Error == "" {
+		t.Fatalf("closed endpoint = %+v, want it unreachable", report.Endpoints[1])
+	}
+	lagging := report.Endpoints[0].Chains[testBlockchainId]
+	if report.MaxHeights[testBlockchainId] != 2 || lagging.HeightLag != 2 || lagging.IsVoting {
+		t.Fatalf("lagging chain = %+v, max heights %v", lagging, report.MaxHeights)
+	}
+	if _, ok := report.Endpoints[0].Chains["Other"]; !ok {
+		t.Fatal("the chains only one endpoint serves are missing")
+	}
+	if skew := report.Endpoints[2].ClockSkew; skew > 2*time.Second || skew < -2*time.Second {
+		t.Fatalf("ClockSkew = %v against a local node", skew)
+	}
+
+	best, ok := report.Best(testBlockchainId)
+	if !ok || best.Endpoint != ahead.URL() {
+		t.Fatalf("Best() = %s, want the endpoint that is not behind", best.Endpoint)
+	}
+	if best, ok := report.Best("Other"); !ok || best.Endpoint != behind.URL() {
+		t.Fatalf("Best(Other) = %s, %v", best.Endpoint, ok)
+	}
+	if _, ok := report.Best("Unknown"); ok {
+		t.Fatal("Best() found an endpoint for an unknown chain")
+	}
+	if unhealthy := report.Unhealthy(1, time.Minute); len(unhealthy) != 2 {
+		t.Fatalf("Unhealthy() = %+v, want the closed and the lagging endpoint", unhealthy)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reports := 0
+	err := transaction.WatchEndpoints(ctx, endpoints[2:], transaction.ProbeOptions{}, 10*time.Millisecond, func(transaction.HealthReport) {
+		if reports++; reports == 2 {
+			cancel()
+		}
+	})
+	if err != context.Canceled || reports != 2 {
+		t.Fatalf("WatchEndpoints() = %v after %d reports", err, reports)
+	}
+}
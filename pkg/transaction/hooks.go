@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"time"
+)
+
+// HookStage names the step of GenerateTransaction that failed
+type HookStage string
+
+const (
+	HOOK_STAGE_PREPARE HookStage = "prepare"
+	HOOK_STAGE_COMMIT  HookStage = "commit"
+	HOOK_STAGE_SIGN    HookStage = "sign"
+	HOOK_STAGE_SUBMIT  HookStage = "submit"
+	HOOK_STAGE_DECODE  HookStage = "decode"
+)
+
+// BeforeSignEvent carries the final input and the exact bytes the wallet is about to sign
+type BeforeSignEvent struct {
+	Time       time.Time
+	Input      ULTransactionInput
+	Commitment []byte
+}
+
+// AfterSignEvent carries the input with its sender signature, before it is submitted
+type AfterSignEvent struct {
+	Time       time.Time
+	Input      ULTransactionInput
+	Commitment []byte
+}
+
+// AfterSubmitEvent carries the signed input, the raw node response and its decoded form
+type AfterSubmitEvent struct {
+	Time        time.Time
+	Input       ULTransactionInput
+	Response    []byte
+	Transaction ULTransaction
+	Duration    time.Duration
+}
+
+// ErrorEvent reports a failed GenerateTransaction, Input holds whatever was prepared up to Stage
+type ErrorEvent struct {
+	Time  time.Time
+	Stage HookStage
+	Input ULTransactionInput
+	Err   error
+}
+
+// SessionHooks observe every transaction the session generates. Hooks run synchronously on the
+// calling goroutine and cannot change the transaction, unset hooks are skipped.
+type SessionHooks struct {
+	OnBeforeSign  func(BeforeSignEvent)
+	OnAfterSign   func(AfterSignEvent)
+	OnAfterSubmit func(AfterSubmitEvent)
+	OnError       func(ErrorEvent)
+}
+
+// SetHooks replaces the lifecycle hooks of the session
+func (session *UL_TransactionSession) SetHooks(hooks SessionHooks) {
+	session.hooks = hooks
+}
+
+// Hooks returns the lifecycle hooks of the session so helpers can chain their own
+func (session *UL_TransactionSession) Hooks() SessionHooks {
+	return session.hooks
+}
+
+func (hooks SessionHooks) beforeSign(input ULTransactionInput, commitment []byte) {
+	if hooks.OnBeforeSign != nil {
+		hooks.OnBeforeSign(BeforeSignEvent{
+			Time:       time.Now().UTC(),
+			Input:      input,
+			Commitment: append([]byte(nil), commitment...),
+		})
+	}
+}
+
+func (hooks SessionHooks) afterSign(input ULTransactionInput, commitment []byte) {
+	if hooks.OnAfterSign != nil {
+		hooks.OnAfterSign(AfterSignEvent{
+			Time:       time.Now().UTC(),
+			Input:      input,
+			Commitment: append([]byte(nil), commitment...),
+		})
+	}
+}
+
+func (hooks SessionHooks) afterSubmit(input ULTransactionInput, response []byte, tx ULTransaction, duration time.Duration) {
+	if hooks.OnAfterSubmit != nil {
+		hooks.OnAfterSubmit(AfterSubmitEvent{
+			Time:        time.Now().UTC(),
+			Input:       input,
+			Response:    response,
+			Transaction: tx,
+			Duration:    duration,
+		})
+	}
+}
+
+// fail reports err to OnError and returns it unchanged
+func (hooks SessionHooks) fail(stage HookStage, input ULTransactionInput, err error) error {
+	if hooks.OnError != nil {
+		hooks.OnError(ErrorEvent{Time: time.Now().UTC(), Stage: stage, Input: input, Err: err})
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestSessionHooks(t *testing.T) {
+	node, session := newMockSession(t)
+
+	var signed []transaction.BeforeSignEvent
+	var afterSign []transaction.AfterSignEvent
+	var submitted []transaction.AfterSubmitEvent
+	var failed []transaction.ErrorEvent
+	session.SetHooks(transaction.SessionHooks{
+		OnBeforeSign:  func(e transaction.BeforeSignEvent) { signed = append(signed, e) },
+		OnAfterSign:   func(e transaction.AfterSignEvent) { afterSign = append(afterSign, e) },
+		OnAfterSubmit: func(e transaction.AfterSubmitEvent) { submitted = append(submitted, e) },
+		OnError:       func(e transaction.ErrorEvent) { failed = append(failed, e) },
+	})
+
+	tx := submitData(t, session, `{"hello":"world"}`)
+	if len(signed) != 1 || len(submitted) != 1 || len(failed) != 0 {
+		t.Fatalf("events = %d signed, %d submitted, %d failed", len(signed), len(submitted), len(failed))
+	}
+	if len(signed[0].Commitment) == 0 || signed[0].Input.PayloadRoot == "" || signed[0].Input.SenderSignature != "" {
+		t.Fatalf("BeforeSignEvent = %+v, want the unsigned input and its commitment", signed[0])
+	}
+	if len(afterSign) != 1 || afterSign[0].Input.SenderSignature != submitted[0].Input.SenderSignature {
+		t.Fatalf("AfterSignEvent = %+v, want the signed input", afterSign)
+	}
+	if submitted[0].Transaction.TransactionId != tx.TransactionId || submitted[0].Input.SenderSignature == "" {
+		t.Fatalf("AfterSubmitEvent = %+v", submitted[0])
+	}
+	response := transaction.ULTransaction{}
+	if err := json.Unmarshal(submitted[0].Response, &response); err != nil || response.TransactionId != tx.TransactionId {
+		t.Fatalf("Response = %s, want the raw node response", submitted[0].Response)
+	}
+
+	// Payloads past the hard bound are rejected while preparing the transaction
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		Payload:      string(make([]byte, 4096)),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err == nil {
+		t.Fatal("GenerateTransaction() accepted an oversized payload")
+	}
+	if len(failed) != 1 || failed[0].Stage != transaction.HOOK_STAGE_PREPARE || !errors.Is(failed[0].Err, err) {
+		t.Fatalf("ErrorEvent = %+v, want a prepare failure", failed)
+	}
+	if len(signed) != 1 {
+		t.Fatal("OnBeforeSign ran for a transaction that was never signed")
+	}
+
+	// Failures to reach the node are reported from the submit stage
+	node.Close()
+	_, err = session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "data",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err == nil || len(failed) != 2 || failed[1].Stage != transaction.HOOK_STAGE_SUBMIT {
+		t.Fatalf("ErrorEvent = %+v, want a submit failure", failed)
+	}
+}
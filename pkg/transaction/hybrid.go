@@ -0,0 +1,175 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// HYBRID_KEY_TYPE is the key type of the post-quantum half of a hybrid signature
+const HYBRID_KEY_TYPE = crypto.KeyTypeMlDSA87
+
+// HybridKeyPair binds the ML-DSA-87 key of PostQuantum to the classical wallet of Classical. Each key
+// signs the same pairing statement, so neither can be paired with a key without its consent.
+type HybridKeyPair struct {
+	Classical   wallet.MessageSignature `json:"classical"`
+	PostQuantum wallet.MessageSignature `json:"postQuantum"`
+}
+
+type ErrInvalidHybridSignature struct {
+	Msg string
+}
+
+func (e *ErrInvalidHybridSignature) Error() string {
+	return fmt.Sprintf("invalid hybrid signature, %s", e.Msg)
+}
+
+func (e *ErrInvalidHybridSignature) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// pairingStatement is the message both keys of a pair sign
+func pairingStatement(classical string, postQuantum string) []byte {
+	return []byte(fmt.Sprintf("ULedger hybrid key pair\nclassical:%s\npostQuantum:%s", classical, postQuantum))
+}
+
+// PairKeys pairs the ML-DSA-87 wallet pq with the classical wallet. Publish the pair next to the
+// classical wallet, verifiers need it to trust the post-quantum half of its transactions.
+func PairKeys(classical *wallet.UL_Wallet, pq *wallet.UL_Wallet) (HybridKeyPair, error) {
+	if err := errors.Join(classical.CheckKey(), pq.CheckKey()); err != nil {
+		return HybridKeyPair{}, err
+	}
+	if err := checkHybridKeyTypes(classical.GetKey().GetType(), pq.GetKey().GetType()); err != nil {
+		return HybridKeyPair{}, err
+	}
+	statement := pairingStatement(classical.Address, pq.Address)
+	classicalSignature, err := classical.SignMessage(statement)
+	if err != nil {
+		return HybridKeyPair{}, err
+	}
+	pqSignature, err := pq.SignMessage(statement)
+	if err != nil {
+		return HybridKeyPair{}, err
+	}
+	return HybridKeyPair{Classical: classicalSignature, PostQuantum: pqSignature}, nil
+}
+
+// Verify checks that both keys of the pair signed the pairing
+func (p HybridKeyPair) Verify() error {
+	if err := checkHybridKeyTypes(p.Classical.KeyType, p.PostQuantum.KeyType); err != nil {
+		return err
+	}
+	statement := pairingStatement(p.Classical.Address, p.PostQuantum.Address)
+	for _, signature := range []wallet.MessageSignature{p.Classical, p.PostQuantum} {
+		ok, err := wallet.VerifyMessage(signature.Address, statement, signature)
+		if err != nil {
+			return &ErrInvalidHybridSignature{Msg: err.Error()}
+		}
+		if !ok {
+			return &ErrInvalidHybridSignature{Msg: fmt.Sprintf("%s did not sign the pairing", signature.Address)}
+		}
+	}
+	return nil
+}
+
+func checkHybridKeyTypes(classical crypto.KeyType, pq crypto.KeyType) error {
+	if classical != crypto.KeyTypeSecp256k1 && classical != crypto.KeyTypeED25519 {
+		return &ErrInvalidHybridSignature{Msg: fmt.Sprintf("%s keys cannot be the classical half of a pair", classical)}
+	}
+	if pq != HYBRID_KEY_TYPE {
+		return &ErrInvalidHybridSignature{Msg: fmt.Sprintf("the post-quantum half must be a %s key, not %s", HYBRID_KEY_TYPE, pq)}
+	}
+	return nil
+}
+
+// SetHybridKey has the session sign every transaction with pq as well, the ML-DSA-87 signature over
+// the same commitment is recorded in ULTransactionInput.HybridSignature. Nodes only check the
+// classical signature, the second one lets verifiers trust the transaction should the classical key
+// be broken. A nil pq turns hybrid signing off.
+func (session *UL_TransactionSession) SetHybridKey(pq *wallet.UL_Wallet) error {
+	if pq == nil {
+		session.hybridKey = nil
+		return nil
+	}
+	if err := errors.Join(session.wallet.CheckKey(), pq.CheckKey()); err != nil {
+		return err
+	}
+	if err := checkHybridKeyTypes(session.wallet.GetKey().GetType(), pq.GetKey().GetType()); err != nil {
+		return err
+	}
+	session.hybridKey = pq
+	return nil
+}
+
+// signHybrid adds the post-quantum signature of commitment to input when a hybrid key is set
+func (session *UL_TransactionSession) signHybrid(input *ULTransactionInput, commitment []byte) error {
+	if session.hybridKey == nil {
+		return nil
+	}
+	signature, err := session.hybridKey.GetKey().SignData(commitment)
+	if err != nil {
+		return fmt.Errorf("unable to sign with the hybrid key: %w", err)
+	}
+	input.HybridPublicKey = session.hybridKey.GetKey().GetPublicKeyHex(false)
+	input.HybridSignature = crypto.BytesToHex(signature)
+	return nil
+}
+
+// VerifyHybridTransaction checks both signatures of input: the classical one against the key of pair
+// and the post-quantum one against the key it is paired with. The pair itself is verified too.
+func VerifyHybridTransaction(input ULTransactionInput, pair HybridKeyPair) error {
+	if err := pair.Verify(); err != nil {
+		return err
+	}
+	if input.HybridSignature == "" {
+		return &ErrInvalidHybridSignature{Msg: "the transaction has no post-quantum signature"}
+	}
+	if input.DelegationId == "" && !strings.EqualFold(pair.Classical.Address, input.From) {
+		return &ErrInvalidHybridSignature{Msg: fmt.Sprintf("the pair belongs to %s, not %s", pair.Classical.Address, input.From)}
+	}
+	if !strings.EqualFold(input.HybridPublicKey, pair.PostQuantum.PublicKey) {
+		return &ErrInvalidHybridSignature{Msg: fmt.Sprintf("the post-quantum key is not the one paired with %s", pair.Classical.Address)}
+	}
+	commitment, _, err := input.SigningCommitment()
+	if err != nil {
+		return err
+	}
+	if result := checkSignature(input, commitment, pair.Classical.PublicKey); !result.Valid {
+		return &ErrInvalidHybridSignature{Msg: "classical signature: " + result.Detail}
+	}
+	if result := checkHybridSignature(input, commitment); !result.Valid {
+		return &ErrInvalidHybridSignature{Msg: "post-quantum signature: " + result.Detail}
+	}
+	return nil
+}
+
+// checkHybridSignature verifies the post-quantum signature against the key the transaction carries,
+// whether that key is paired with the sender is up to VerifyHybridTransaction
+func checkHybridSignature(input ULTransactionInput, commitment []byte) CheckResult {
+	if commitment == nil {
+		return CheckResult{Detail: "skipped, the commitment could not be recomputed"}
+	}
+	key, err := crypto.GetKeyByType(HYBRID_KEY_TYPE, nil)
+	if err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	if err := key.GeneratePublicKeyFromHex(false, input.HybridPublicKey); err != nil {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("invalid public key: %v", err)}
+	}
+	signature, err := crypto.HexToBytes(input.HybridSignature)
+	if err != nil {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("invalid signature: %v", err)}
+	}
+	ok, err := key.VerifySignature(commitment, signature)
+	if err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	if !ok {
+		return CheckResult{Checked: true, Detail: "the signature does not match the commitment"}
+	}
+	return CheckResult{Checked: true, Valid: true, Detail: fmt.Sprintf("%s signature verified for %s", HYBRID_KEY_TYPE, wallet.ParseAddress(input.HybridPublicKey))}
+}
@@ -0,0 +1,71 @@
+package transaction_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestHybridSignature(t *testing.T) {
+	_, session := newMockSession(t)
+	classical := session.GetWallet()
+	pq, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeMlDSA87, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	pair, err := transaction.PairKeys(&classical, &pq)
+	if err != nil {
+		t.Fatalf("PairKeys() error = %v", err)
+	}
+	if err := pair.Verify(); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	other, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err := session.SetHybridKey(&other); err == nil {
+		t.Fatal("SetHybridKey() accepted a secp256k1 key as the post-quantum half")
+	}
+	if err := session.SetHybridKey(&pq); err != nil {
+		t.Fatalf("SetHybridKey() error = %v", err)
+	}
+	tx := submitData(t, session, "hybrid signed")
+	if tx.HybridSignature == "" || tx.HybridPublicKey != pq.GetKey().GetPublicKeyHex(false) {
+		t.Fatalf("transaction = %+v, want a hybrid signature", tx.ULTransactionInput)
+	}
+	if err := transaction.VerifyHybridTransaction(tx.ULTransactionInput, pair); err != nil {
+		t.Fatalf("VerifyHybridTransaction() error = %v", err)
+	}
+	decoded := transaction.DecodeTransaction(tx, classical.GetKey().GetPublicKeyHex(false))
+	if !decoded.Signature.Valid || decoded.Hybrid == nil || !decoded.Hybrid.Valid {
+		t.Fatalf("DecodeTransaction() signature = %+v, hybrid = %+v", decoded.Signature, decoded.Hybrid)
+	}
+
+	// A pair with another post-quantum key must not vouch for the transaction
+	impostor, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeMlDSA87, "", nil, wallet.DefaultEntropy)
+	forged, _ := transaction.PairKeys(&classical, &impostor)
+	invalid := &transaction.ErrInvalidHybridSignature{}
+	if err := transaction.VerifyHybridTransaction(tx.ULTransactionInput, forged); !errors.As(err, &invalid) {
+		t.Fatalf("VerifyHybridTransaction() with another pair error = %v", err)
+	}
+	tampered := pair
+	tampered.PostQuantum = forged.PostQuantum
+	if err := tampered.Verify(); err == nil {
+		t.Fatal("Verify() accepted a pair signed for another key")
+	}
+
+	altered := tx.ULTransactionInput
+	altered.HybridSignature = forged.PostQuantum.Signature
+	if err := transaction.VerifyHybridTransaction(altered, pair); !errors.As(err, &invalid) {
+		t.Fatalf("VerifyHybridTransaction() with a wrong signature error = %v", err)
+	}
+
+	if err := session.SetHybridKey(nil); err != nil {
+		t.Fatalf("SetHybridKey(nil) error = %v", err)
+	}
+	if tx := submitData(t, session, "classical only"); tx.HybridSignature != "" {
+		t.Fatal("hybrid signing was not turned off")
+	}
+}
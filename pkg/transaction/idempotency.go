@@ -0,0 +1,245 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// IdempotencyRecord is the transaction an idempotency key produced. Fingerprint identifies the
+// input the key was first used with.
+type IdempotencyRecord struct {
+	Key         string        `json:"key"`
+	Fingerprint string        `json:"fingerprint"`
+	Transaction ULTransaction `json:"transaction"`
+	CreatedAt   time.Time     `json:"createdAt"`
+}
+
+// IdempotencyStore persists the transactions of idempotency keys, see GenerateIdempotentTransaction.
+// Keys are chosen by the application, sessions sharing a store share its keys.
+type IdempotencyStore interface {
+	LoadIdempotencyRecord(key string) (IdempotencyRecord, bool, error)
+	SaveIdempotencyRecord(record IdempotencyRecord) error
+}
+
+// MemoryIdempotencyStore keeps idempotency keys for the lifetime of the process
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{records: make(map[string]IdempotencyRecord)}
+}
+
+func (store *MemoryIdempotencyStore) LoadIdempotencyRecord(key string) (IdempotencyRecord, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	record, ok := store.records[key]
+	return record, ok, nil
+}
+
+func (store *MemoryIdempotencyStore) SaveIdempotencyRecord(record IdempotencyRecord) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.records[record.Key] = record
+	return nil
+}
+
+// FileIdempotencyStore writes one JSON file per key inside a directory, surviving restarts
+type FileIdempotencyStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileIdempotencyStore(dir string) (*FileIdempotencyStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create idempotency directory: %w", err)
+	}
+	return &FileIdempotencyStore{dir: dir}, nil
+}
+
+// path names the file of a key by its hash, keys come from upstream systems and may hold anything
+func (store *FileIdempotencyStore) path(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(store.dir, hex.EncodeToString(hash[:])+".idempotency.json")
+}
+
+func (store *FileIdempotencyStore) LoadIdempotencyRecord(key string) (IdempotencyRecord, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := os.ReadFile(store.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return IdempotencyRecord{}, false, nil
+	}
+	if err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("failed to read idempotency record: %w", err)
+	}
+
+	record := IdempotencyRecord{}
+	if err := json.Unmarshal(data, &record); err != nil {
+		return IdempotencyRecord{}, false, fmt.Errorf("failed to parse idempotency record: %w", err)
+	}
+	return record, true, nil
+}
+
+func (store *FileIdempotencyStore) SaveIdempotencyRecord(record IdempotencyRecord) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	// Write then rename so a crash never leaves a half written record behind
+	path := store.path(record.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write idempotency record: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit idempotency record: %w", err)
+	}
+	return nil
+}
+
+// ErrIdempotencyKeyReused is returned by GenerateIdempotentTransaction when a key is repeated with
+// another input than the one it was first used with
+type ErrIdempotencyKeyReused struct {
+	Key           string
+	TransactionId string
+}
+
+func (e *ErrIdempotencyKeyReused) Error() string {
+	return fmt.Sprintf("idempotency key %q was used for transaction %s with a different input", e.Key, e.TransactionId)
+}
+
+func (e *ErrIdempotencyKeyReused) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrNoIdempotencyStore is returned by GenerateIdempotentTransaction when the session has no store
+// to record its keys in, see SessionOptions.IdempotencyStore
+type ErrNoIdempotencyStore struct{}
+
+func (e *ErrNoIdempotencyStore) Error() string {
+	return "no idempotency store is configured for the session"
+}
+
+func (e *ErrNoIdempotencyStore) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// idempotency guards the keys of a session, a key is submitted by one call at a time
+type idempotency struct {
+	store IdempotencyStore
+	mu    sync.Mutex
+	// inFlight holds a channel per key being submitted, closed once the submission settles
+	inFlight map[string]chan struct{}
+}
+
+func newIdempotency(store IdempotencyStore) *idempotency {
+	return &idempotency{store: store, inFlight: make(map[string]chan struct{})}
+}
+
+// lock waits until no other call submits key and returns the function releasing it
+func (guard *idempotency) lock(ctx context.Context, key string) (func(), error) {
+	for {
+		guard.mu.Lock()
+		busy, ok := guard.inFlight[key]
+		if !ok {
+			done := make(chan struct{})
+			guard.inFlight[key] = done
+			guard.mu.Unlock()
+			return func() {
+				guard.mu.Lock()
+				delete(guard.inFlight, key)
+				guard.mu.Unlock()
+				close(done)
+			}, nil
+		}
+		guard.mu.Unlock()
+		select {
+		case <-busy:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (guard *idempotency) getStore() IdempotencyStore {
+	guard.mu.Lock()
+	defer guard.mu.Unlock()
+	return guard.store
+}
+
+// SetIdempotencyStore replaces the store of idempotency keys set by SessionOptions.IdempotencyStore,
+// nil leaves the session without one. Use a durable store such as a FileIdempotencyStore for keys to
+// survive restarts.
+func (session *UL_TransactionSession) SetIdempotencyStore(store IdempotencyStore) {
+	session.idempotency.mu.Lock()
+	defer session.idempotency.mu.Unlock()
+	session.idempotency.store = store
+}
+
+// GenerateIdempotentTransaction is GenerateTransaction under an application idempotency key, such
+// as the id of a payment request. The first call with a key submits the transaction and records it,
+// repeats of the key with the same input return the recorded transaction without submitting again
+// and repeats with another input fail with ErrIdempotencyKeyReused. Concurrent calls with one key
+// wait for each other. Failed submissions are not recorded so that they can be retried; when the
+// outcome of a submission is unknown, e.g. after a timeout, check the node before retrying.
+// Sessions without an idempotency store fail with ErrNoIdempotencyStore rather than forget the keys
+// on restart.
+func (session *UL_TransactionSession) GenerateIdempotentTransaction(ctx context.Context, idempotencyKey string, input ULTransactionInput) (ULTransaction, error) {
+	if idempotencyKey == "" {
+		return ULTransaction{}, &utils.ErrMalformed{What: "idempotency key", Msg: "it must not be empty"}
+	}
+	// The input as the caller passed it, before the defaults and the timestamp of each attempt
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	hash := sha256.Sum256(encoded)
+	fingerprint := hex.EncodeToString(hash[:])
+
+	store := session.idempotency.getStore()
+	if store == nil {
+		return ULTransaction{}, &ErrNoIdempotencyStore{}
+	}
+	unlock, err := session.idempotency.lock(ctx, idempotencyKey)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	defer unlock()
+	record, found, err := store.LoadIdempotencyRecord(idempotencyKey)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if found {
+		if record.Fingerprint != fingerprint {
+			return ULTransaction{}, &ErrIdempotencyKeyReused{Key: idempotencyKey, TransactionId: record.Transaction.TransactionId}
+		}
+		return record.Transaction, nil
+	}
+
+	tx, err := session.generateTransaction(ctx, input, "")
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	record = IdempotencyRecord{Key: idempotencyKey, Fingerprint: fingerprint, Transaction: tx, CreatedAt: time.Now().UTC()}
+	if err := store.SaveIdempotencyRecord(record); err != nil {
+		return tx, fmt.Errorf("submitted %s but could not record its idempotency key: %w", tx.TransactionId, err)
+	}
+	return tx, nil
+}
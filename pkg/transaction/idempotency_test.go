@@ -0,0 +1,85 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestGenerateIdempotentTransaction(t *testing.T) {
+	ctx := context.Background()
+	node, session := newMockSession(t)
+	input := transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "charge 100",
+		PayloadType:  transaction.TX_DATA.String(),
+	}
+
+	// Keys kept only in memory would be forgotten on restart, a store is required
+	if _, err := session.GenerateIdempotentTransaction(ctx, "payment-1", input); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("GenerateIdempotentTransaction() without a store error = %v", err)
+	}
+
+	store, err := transaction.NewFileIdempotencyStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileIdempotencyStore() error = %v", err)
+	}
+	session.SetIdempotencyStore(store)
+
+	first, err := session.GenerateIdempotentTransaction(ctx, "payment-1", input)
+	if err != nil {
+		t.Fatalf("GenerateIdempotentTransaction() error = %v", err)
+	}
+	repeated, err := session.GenerateIdempotentTransaction(ctx, "payment-1", input)
+	if err != nil || repeated.TransactionId != first.TransactionId {
+		t.Fatalf("GenerateIdempotentTransaction() repeated = %s, %v, want %s", repeated.TransactionId, err, first.TransactionId)
+	}
+
+	changed := input
+	changed.Payload = "charge 200"
+	reused := &transaction.ErrIdempotencyKeyReused{}
+	if _, err := session.GenerateIdempotentTransaction(ctx, "payment-1", changed); !errors.As(err, &reused) || !errors.Is(err, utils.ErrInvalidInput) || reused.TransactionId != first.TransactionId {
+		t.Fatalf("GenerateIdempotentTransaction() with another input error = %v", err)
+	}
+	if _, err := session.GenerateIdempotentTransaction(ctx, "", input); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("GenerateIdempotentTransaction() without a key error = %v", err)
+	}
+
+	// Concurrent retries of one key submit once
+	var wg sync.WaitGroup
+	ids := make([]string, 8)
+	for i := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx, err := session.GenerateIdempotentTransaction(ctx, "payment-2", changed)
+			if err != nil {
+				t.Errorf("GenerateIdempotentTransaction() error = %v", err)
+			}
+			ids[i] = tx.TransactionId
+		}()
+	}
+	wg.Wait()
+	for _, id := range ids {
+		if id != ids[0] {
+			t.Fatalf("concurrent GenerateIdempotentTransaction() = %v, want one transaction", ids)
+		}
+	}
+
+	// The keys survive the session
+	restarted, err := transaction.NewUL_TransactionSession(node.URL(), session.GetWallet(), transaction.SessionOptions{IdempotencyStore: store})
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	if tx, err := restarted.GenerateIdempotentTransaction(ctx, "payment-1", input); err != nil || tx.TransactionId != first.TransactionId {
+		t.Fatalf("GenerateIdempotentTransaction() after a restart = %s, %v, want %s", tx.TransactionId, err, first.TransactionId)
+	}
+	if submitted := node.Transactions(); len(submitted) != 2 {
+		t.Fatalf("node received %d transactions, want 2", len(submitted))
+	}
+}
@@ -0,0 +1,194 @@
+package transaction
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+)
+
+const (
+	DEFAULT_PAGE_SIZE = 100
+	MAX_PAGE_SIZE     = 1000
+)
+
+// Page is the response body of every list endpoint
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor"`
+	// Total is the number of items across all pages, -1 when the node does not count them
+	Total int `json:"total"`
+}
+
+// PageInfo describes the page an Iterator is currently walking
+type PageInfo struct {
+	// Cursor fetched the current page, pass it back in ListOptions to restart from this page
+	Cursor string
+	// NextCursor fetches the following page, empty on the last page
+	NextCursor string
+	PageSize   int
+	// Total is the number of items across all pages, -1 when unknown
+	Total int
+	// Pages is the number of pages fetched so far
+	Pages int
+}
+
+// HasMore reports whether pages remain after the current one
+func (info PageInfo) HasMore() bool {
+	return info.NextCursor != ""
+}
+
+type ListOptions struct {
+	// PageSize is the number of items per request, defaults to DEFAULT_PAGE_SIZE
+	PageSize int
+	// Cursor resumes a listing from PageInfo.Cursor or PageInfo.NextCursor
+	Cursor string
+	// Limit stops the iterator after this many items, zero means no limit
+	Limit int
+}
+
+func (opts ListOptions) pageSize() int {
+	switch {
+	case opts.PageSize <= 0:
+		return DEFAULT_PAGE_SIZE
+	case opts.PageSize > MAX_PAGE_SIZE:
+		return MAX_PAGE_SIZE
+	default:
+		return opts.PageSize
+	}
+}
+
+// PageFetcher loads the page starting at cursor, the empty cursor is the first page
+type PageFetcher[T any] func(ctx context.Context, cursor string, pageSize int) (Page[T], error)
+
+// Iterator walks a paginated listing one item at a time and fetches pages on demand:
+//
+//	it := session.ListTransactions(blockchainId, TransactionFilter{}, ListOptions{})
+//	for it.Next(ctx) {
+//		tx := it.Value()
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type Iterator[T any] struct {
+	fetch PageFetcher[T]
+	opts  ListOptions
+
+	info    PageInfo
+	items   []T
+	index   int
+	yielded int
+	started bool
+	done    bool
+	err     error
+}
+
+func NewIterator[T any](fetch PageFetcher[T], opts ListOptions) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		opts:  opts,
+		info:  PageInfo{NextCursor: opts.Cursor, PageSize: opts.pageSize(), Total: -1},
+		index: -1,
+	}
+}
+
+// Next advances to the next item, fetching the next page when the current one is exhausted. It
+// returns false at the end of the listing or on error, check Err to tell them apart.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	if it.opts.Limit > 0 && it.yielded >= it.opts.Limit {
+		it.done = true
+		return false
+	}
+	it.index++
+	for it.index >= len(it.items) {
+		// The first page is fetched even when the cursor is empty
+		if it.started && !it.info.HasMore() {
+			it.done = true
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		cursor := it.info.NextCursor
+		page, err := it.fetch(ctx, cursor, it.info.PageSize)
+		if err != nil {
+			it.err = err
+			it.done = true
+			return false
+		}
+		it.started = true
+		it.items = page.Items
+		it.index = 0
+		it.info.Cursor = cursor
+		it.info.NextCursor = page.NextCursor
+		it.info.Total = page.Total
+		it.info.Pages++
+		if page.NextCursor == cursor {
+			// A node handing back the same cursor would make the iterator loop forever
+			it.info.NextCursor = ""
+		}
+	}
+	it.yielded++
+	return true
+}
+
+// Value returns the item Next advanced to
+func (it *Iterator[T]) Value() T {
+	if it.index < 0 || it.index >= len(it.items) {
+		var zero T
+		return zero
+	}
+	return it.items[it.index]
+}
+
+// Err returns the error that stopped the iterator, nil when the listing was exhausted
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// PageInfo describes the page of the current item
+func (it *Iterator[T]) PageInfo() PageInfo {
+	return it.info
+}
+
+// All ranges over the remaining items, check Err once the loop ends
+func (it *Iterator[T]) All(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for it.Next(ctx) {
+			if !yield(it.Value()) {
+				return
+			}
+		}
+	}
+}
+
+// Collect drains the iterator into a slice
+func (it *Iterator[T]) Collect(ctx context.Context) ([]T, error) {
+	items := []T{}
+	for it.Next(ctx) {
+		items = append(items, it.Value())
+	}
+	return items, it.Err()
+}
+
+// pagePath appends the cursor, page size and extra filters to a list endpoint
+func pagePath(path string, cursor string, pageSize int, filters url.Values) string {
+	query := url.Values{}
+	for key, values := range filters {
+		for _, value := range values {
+			if value != "" {
+				query.Add(key, value)
+			}
+		}
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	query.Set("limit", strconv.Itoa(pageSize))
+	return path + "?" + query.Encode()
+}
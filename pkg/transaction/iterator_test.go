@@ -0,0 +1,131 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestIteratorPagination(t *testing.T) {
+	ctx := context.Background()
+	fetches := 0
+	fetch := func(ctx context.Context, cursor string, pageSize int) (transaction.Page[int], error) {
+		fetches++
+		start := 0
+		if cursor != "" {
+			start, _ = strconv.Atoi(cursor)
+		}
+		page := transaction.Page[int]{Total: 10}
+		for i := start; i < min(start+pageSize, 10); i++ {
+			page.Items = append(page.Items, i)
+		}
+		if start+pageSize < 10 {
+			page.NextCursor = strconv.Itoa(start + pageSize)
+		}
+		return page, nil
+	}
+
+	items, err := transaction.NewIterator(fetch, transaction.ListOptions{PageSize: 3}).Collect(ctx)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(items) != 10 || items[9] != 9 || fetches != 4 {
+		t.Fatalf("Collect() = %v after %d fetches", items, fetches)
+	}
+
+	// Resuming from a cursor and stopping at a limit
+	it := transaction.NewIterator(fetch, transaction.ListOptions{PageSize: 3, Cursor: "6", Limit: 2})
+	got := []int{}
+	for value := range it.All(ctx) {
+		got = append(got, value)
+	}
+	if fmt.Sprint(got) != "[6 7]" || it.Err() != nil {
+		t.Fatalf("All() = %v, %v", got, it.Err())
+	}
+	if info := it.PageInfo(); info.Cursor != "6" || info.NextCursor != "9" || !info.HasMore() || info.Total != 10 {
+		t.Fatalf("PageInfo() = %+v", info)
+	}
+}
+
+func TestIteratorStopsOnError(t *testing.T) {
+	failure := errors.New("node unavailable")
+	it := transaction.NewIterator(func(ctx context.Context, cursor string, pageSize int) (transaction.Page[string], error) {
+		if cursor == "" {
+			return transaction.Page[string]{Items: []string{"a"}, NextCursor: "next"}, nil
+		}
+		return transaction.Page[string]{}, failure
+	}, transaction.ListOptions{})
+
+	items, err := it.Collect(context.Background())
+	if !errors.Is(err, failure) || len(items) != 1 {
+		t.Fatalf("Collect() = %v, %v", items, err)
+	}
+	if it.Next(context.Background()) {
+		t.Fatal("Next() resumed after an error")
+	}
+
+	// A node repeating its cursor must not loop forever
+	repeating := transaction.NewIterator(func(ctx context.Context, cursor string, pageSize int) (transaction.Page[string], error) {
+		return transaction.Page[string]{Items: []string{"a"}, NextCursor: "same"}, nil
+	}, transaction.ListOptions{Cursor: "same"})
+	if items, err := repeating.Collect(context.Background()); err != nil || len(items) != 1 {
+		t.Fatalf("Collect() = %v, %v", items, err)
+	}
+}
+
+func TestListQueries(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	address := session.GetWallet().Address
+
+	for i := range 5 {
+		submitData(t, session, fmt.Sprintf("list %d", i))
+	}
+	payload, _ := json.Marshal(transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Test", Symbol: "TST", InitialSupply: transaction.NewAmount(100)})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           address,
+		Payload:      string(payload),
+		PayloadType:  transaction.CREATE_TOKEN.String(),
+	}); err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	blocks, err := session.ListBlocks(testBlockchainId, transaction.ListOptions{PageSize: 2}).Collect(ctx)
+	if err != nil || len(blocks) != 6 || blocks[5].Height != 6 {
+		t.Fatalf("ListBlocks() = %d blocks, %v", len(blocks), err)
+	}
+
+	it := session.ListTransactions(testBlockchainId, transaction.TransactionFilter{Address: address, PayloadType: transaction.TX_DATA.String()}, transaction.ListOptions{PageSize: 2})
+	count := 0
+	for tx := range it.All(ctx) {
+		if tx.PayloadType != transaction.TX_DATA.String() {
+			t.Fatalf("ListTransactions() returned a %s transaction", tx.PayloadType)
+		}
+		count++
+	}
+	if it.Err() != nil || count != 5 || it.PageInfo().Pages != 3 || it.PageInfo().Total != 5 {
+		t.Fatalf("ListTransactions() = %d, %+v, %v", count, it.PageInfo(), it.Err())
+	}
+
+	tokens, err := session.ListTokens(testBlockchainId, transaction.ListOptions{}).Collect(ctx)
+	if err != nil || len(tokens) != 1 || tokens[0].Symbol != "TST" {
+		t.Fatalf("ListTokens() = %+v, %v", tokens, err)
+	}
+	if balance := node.Balance(tokens[0].TokenAddress, address); balance != 100 {
+		t.Fatalf("initial supply = %d, want 100", balance)
+	}
+
+	wallets, err := session.ListWallets(testBlockchainId, address, transaction.ListOptions{}).Collect(ctx)
+	if err != nil || len(wallets) != 0 {
+		t.Fatalf("ListWallets() = %+v, %v", wallets, err)
+	}
+	if _, err := session.ListBlocks("unknown", transaction.ListOptions{}).Collect(ctx); err != nil {
+		t.Fatalf("ListBlocks() of an empty chain error = %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// ULToken is an entry of the token registry of a chain
+type ULToken struct {
+	TokenAddress string `json:"tokenAddress"`
+	TokenMetadata
+}
+
+// ULWalletInfo is the on-chain registration of a wallet, it never carries key material
+type ULWalletInfo struct {
+	Address      string                              `json:"address"`
+	Parent       string                              `json:"parent"`
+	Enabled      bool                                `json:"enabled"`
+	KeyType      crypto.KeyType                      `json:"keyType"`
+	PublicKey    string                              `json:"publicKey"`
+	AuthGroups   map[string]wallet.UL_AuthPermission `json:"authGroups"`
+	CreatedBlock int                                 `json:"createdBlock"`
+}
+
+// TransactionFilter narrows ListTransactions, empty fields match everything
+type TransactionFilter struct {
+	// Address matches transactions sent from or to the address
+	Address     string
+	PayloadType string
+}
+
+// ListBlocks iterates over the blocks of a chain from the oldest to the newest
+func (session *UL_TransactionSession) ListBlocks(blockchainId string, opts ListOptions) *Iterator[ULBlock] {
+	return listPages[ULBlock](session, fmt.Sprintf("/blockchains/%s/blocks", blockchainId), nil, opts)
+}
+
+// ListTransactions iterates over the sealed transactions of a chain in block order
+func (session *UL_TransactionSession) ListTransactions(blockchainId string, filter TransactionFilter, opts ListOptions) *Iterator[ULTransaction] {
+	filters := url.Values{"address": {filter.Address}, "payloadType": {filter.PayloadType}}
+	return listPages[ULTransaction](session, fmt.Sprintf("/blockchains/%s/transactions", blockchainId), filters, opts)
+}
+
+// ListTokens iterates over the tokens created on a chain
+func (session *UL_TransactionSession) ListTokens(blockchainId string, opts ListOptions) *Iterator[ULToken] {
+	return listPages[ULToken](session, fmt.Sprintf("/blockchains/%s/tokens", blockchainId), nil, opts)
+}
+
+// ListWallets iterates over the wallets registered on a chain, parent restricts the listing to
+// the direct children of a wallet
+func (session *UL_TransactionSession) ListWallets(blockchainId string, parent string, opts ListOptions) *Iterator[ULWalletInfo] {
+	filters := url.Values{"parent": {parent}}
+	return listPages[ULWalletInfo](session, fmt.Sprintf("/blockchains/%s/wallets", blockchainId), filters, opts)
+}
+
+// listPages builds an iterator over a list endpoint of the node
+func listPages[T any](session *UL_TransactionSession, path string, filters url.Values, opts ListOptions) *Iterator[T] {
+	return NewIterator(func(ctx context.Context, cursor string, pageSize int) (Page[T], error) {
+		page := Page[T]{Total: -1}
+		if err := session.getJson(ctx, pagePath(path, cursor, pageSize, filters), &page); err != nil {
+			return Page[T]{}, err
+		}
+		return page, nil
+	}, opts)
+}
@@ -0,0 +1,65 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+const (
+	MEMO_ENVELOPE_VERSION = "memo/v1"
+	MAX_MEMO_LENGTH       = 256
+)
+
+// MemoEnvelope wraps a DATA payload with a human readable reference. The envelope is the signed
+// payload, so the memo is part of the commitment and cannot be altered without breaking the signature.
+// Payloads without the envelope marker are returned untouched by the readers below.
+type MemoEnvelope struct {
+	Version string `json:"ulEnvelope"`
+	Memo    string `json:"memo"`
+	Payload string `json:"payload"`
+}
+
+// WithMemo returns a copy of input whose payload is wrapped in a memo envelope. Only DATA payloads
+// can carry a memo, other payload types are decoded by the node.
+func WithMemo(input ULTransactionInput, memo string) (ULTransactionInput, error) {
+	if input.PayloadType != TX_DATA.String() {
+		return input, fmt.Errorf("memos are only supported on %s transactions, got %s", TX_DATA, input.PayloadType)
+	}
+	if !utf8.ValidString(memo) || len(memo) > MAX_MEMO_LENGTH {
+		return input, fmt.Errorf("memo must be valid UTF-8 of at most %d bytes", MAX_MEMO_LENGTH)
+	}
+	if _, ok := ParseMemoEnvelope(input.Payload); ok {
+		return input, fmt.Errorf("payload already carries a memo")
+	}
+
+	envelope, err := json.Marshal(MemoEnvelope{Version: MEMO_ENVELOPE_VERSION, Memo: memo, Payload: input.Payload})
+	if err != nil {
+		return input, err
+	}
+	input.Payload = string(envelope)
+	return input, nil
+}
+
+// ParseMemoEnvelope decodes a payload produced by WithMemo
+func ParseMemoEnvelope(payload string) (MemoEnvelope, bool) {
+	envelope := MemoEnvelope{}
+	if err := json.Unmarshal([]byte(payload), &envelope); err != nil || envelope.Version != MEMO_ENVELOPE_VERSION {
+		return MemoEnvelope{}, false
+	}
+	return envelope, true
+}
+
+// GetMemo returns the memo of the transaction, if it carries one
+func (t *ULTransactionInput) GetMemo() (string, bool) {
+	envelope, ok := ParseMemoEnvelope(t.Payload)
+	return envelope.Memo, ok
+}
+
+// GetPayload returns the application payload with any memo envelope removed
+func (t *ULTransactionInput) GetPayload() string {
+	if envelope, ok := ParseMemoEnvelope(t.Payload); ok {
+		return envelope.Payload
+	}
+	return t.Payload
+}
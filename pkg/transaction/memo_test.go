@@ -0,0 +1,71 @@
+package transaction_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestMemo(t *testing.T) {
+	node, session := newMockSession(t)
+
+	input, err := transaction.WithMemo(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      `{"document":"9f86d081884c7d65"}`,
+		PayloadType:  transaction.TX_DATA.String(),
+	}, "Invoice INV-2024-118")
+	if err != nil {
+		t.Fatalf("WithMemo() error = %v", err)
+	}
+	tx, err := session.GenerateTransaction(input)
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+
+	stored := node.Transactions()[0]
+	if memo, ok := stored.GetMemo(); !ok || memo != "Invoice INV-2024-118" {
+		t.Errorf("GetMemo() = %q, %v", memo, ok)
+	}
+	if stored.GetPayload() != `{"document":"9f86d081884c7d65"}` {
+		t.Errorf("GetPayload() = %s", stored.GetPayload())
+	}
+
+	// The memo is covered by the signed payload root
+	tampered := tx.ULTransactionInput
+	tampered.Payload = strings.Replace(tampered.Payload, "INV-2024-118", "INV-2024-119", 1)
+	hasher := crypto.GetHasherByType(tampered.KeyType)
+	commitment, err := tampered.GetSignatureCommitment(hasher, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if crypto.BytesToHex(commitment.PayloadRoot) == tx.PayloadRoot {
+		t.Error("expected a different memo to change the payload root")
+	}
+
+	plain := submitData(t, session, "no memo")
+	if _, ok := plain.GetMemo(); ok || plain.GetPayload() != "no memo" {
+		t.Errorf("plain payloads must be returned untouched, got %s", plain.GetPayload())
+	}
+
+	if _, err := transaction.WithMemo(transaction.ULTransactionInput{PayloadType: transaction.TRANSFER_TOKEN.String()}, "memo"); err == nil {
+		t.Error("expected an error for a token transfer")
+	}
+	if _, err := transaction.WithMemo(input, "again"); err == nil {
+		t.Error("expected an error for a payload that already has a memo")
+	}
+	if _, err := transaction.WithMemo(transaction.ULTransactionInput{PayloadType: transaction.TX_DATA.String()}, strings.Repeat("x", transaction.MAX_MEMO_LENGTH+1)); err == nil {
+		t.Error("expected an error for an oversized memo")
+	}
+}
+
+func TestDefaultMemo(t *testing.T) {
+	_, session := newMockSession(t)
+	session.SetDefaults(transaction.TransactionDefaults{BlockchainId: testBlockchainId, Memo: "batch 7"})
+	tx := submitData(t, session, "reading 12.4")
+	if memo, ok := tx.GetMemo(); !ok || memo != "batch 7" || tx.GetPayload() != "reading 12.4" {
+		t.Errorf("default memo was not applied: %s", tx.Payload)
+	}
+}
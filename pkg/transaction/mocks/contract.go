@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// ContractClient is a mock of transaction.ContractClientAPI, it is safe for concurrent use once
+// configured
+type ContractClient struct {
+	// Address is returned by ContractAddress
+	Address string
+
+	InvokeFunc      func(ctx context.Context, functionName string, gasLimit uint64, args ...any) (transaction.ULTransaction, error)
+	CallFunc        func(ctx context.Context, functionName string, args ...any) (interface{}, error)
+	EstimateGasFunc func(ctx context.Context, functionName string, args ...any) (transaction.GasEstimate, error)
+	EventsFunc      func(ctx context.Context, fromBlock int, opts transaction.ContractEventOptions) (<-chan transaction.ContractEvent, error)
+
+	recorder
+}
+
+var _ transaction.ContractClientAPI = (*ContractClient)(nil)
+
+func (m *ContractClient) ContractAddress() string {
+	m.record("ContractAddress")
+	return m.Address
+}
+
+func (m *ContractClient) Invoke(ctx context.Context, functionName string, gasLimit uint64, args ...any) (transaction.ULTransaction, error) {
+	m.record("Invoke", functionName, gasLimit, args)
+	if m.InvokeFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Invoke"}
+	}
+	return m.InvokeFunc(ctx, functionName, gasLimit, args...)
+}
+
+func (m *ContractClient) Call(ctx context.Context, functionName string, args ...any) (interface{}, error) {
+	m.record("Call", functionName, args)
+	if m.CallFunc == nil {
+		return nil, &ErrNotMocked{Method: "Call"}
+	}
+	return m.CallFunc(ctx, functionName, args...)
+}
+
+func (m *ContractClient) EstimateGas(ctx context.Context, functionName string, args ...any) (transaction.GasEstimate, error) {
+	m.record("EstimateGas", functionName, args)
+	if m.EstimateGasFunc == nil {
+		return transaction.GasEstimate{}, &ErrNotMocked{Method: "EstimateGas"}
+	}
+	return m.EstimateGasFunc(ctx, functionName, args...)
+}
+
+func (m *ContractClient) Events(ctx context.Context, fromBlock int, opts transaction.ContractEventOptions) (<-chan transaction.ContractEvent, error) {
+	m.record("Events", fromBlock, opts)
+	if m.EventsFunc == nil {
+		return nil, &ErrNotMocked{Method: "Events"}
+	}
+	return m.EventsFunc(ctx, fromBlock, opts)
+}
@@ -0,0 +1,458 @@
+// Package mocks provides test doubles of the transaction client interfaces, so code depending on
+// transaction.SessionAPI, QueryAPI, TokenAPI, ContractAPI or the token and contract clients can be
+// unit tested without a node.
+//
+// Session implements the session interfaces, ERC20Client, ERC721Client and ContractClient the
+// interfaces of the matching clients. Every method records its call and runs the matching Func field,
+// methods whose field is nil fail with ErrNotMocked:
+//
+//	session := &mocks.Session{
+//		GenerateTransactionFunc: func(input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+//			output := transaction.ULTransactionOutput{TransactionId: "tx", Output: transaction.TX_SUCCESS.String()}
+//			return transaction.ULTransaction{ULTransactionInput: input, ULTransactionOutput: output}, nil
+//		},
+//	}
+//	service := NewService(session)
+//	...
+//	if calls := session.CallsTo("GenerateTransaction"); len(calls) != 1 {
+//		t.Fatalf("GenerateTransaction called %d times", len(calls))
+//	}
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Call is a method invoked on a mock with its arguments, contexts excluded
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// recorder keeps the calls made to a mock
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns every call in the order they were made
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.calls)
+}
+
+// CallsTo returns the calls to method in the order they were made
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	calls := []Call{}
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls
+func (r *recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// ErrNotMocked is returned by the methods whose Func field is not set
+type ErrNotMocked struct {
+	Method string
+}
+
+func (e *ErrNotMocked) Error() string {
+	return fmt.Sprintf("%s is not mocked", e.Method)
+}
+
+func (e *ErrNotMocked) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// Iterator returns an iterator over items, as a single page
+func Iterator[T any](items ...T) *transaction.Iterator[T] {
+	return transaction.NewIterator(func(ctx context.Context, cursor string, pageSize int) (transaction.Page[T], error) {
+		return transaction.Page[T]{Items: items, Total: len(items)}, nil
+	}, transaction.ListOptions{})
+}
+
+// FailingIterator returns an iterator failing with err on its first page
+func FailingIterator[T any](err error) *transaction.Iterator[T] {
+	return transaction.NewIterator(func(ctx context.Context, cursor string, pageSize int) (transaction.Page[T], error) {
+		return transaction.Page[T]{}, err
+	}, transaction.ListOptions{})
+}
+
+// Session is a mock of transaction.SessionAPI, it is safe for concurrent use once configured
+type Session struct {
+	// Wallet is returned by GetWallet
+	Wallet wallet.UL_Wallet
+	// Suggestor is returned by GetSuggestor
+	Suggestor string
+
+	GenerateTransactionFunc           func(input transaction.ULTransactionInput) (transaction.ULTransaction, error)
+	GenerateIdempotentTransactionFunc func(ctx context.Context, idempotencyKey string, input transaction.ULTransactionInput) (transaction.ULTransaction, error)
+	SubmitAndWaitFunc                 func(ctx context.Context, input transaction.ULTransactionInput) (transaction.ULTransaction, error)
+
+	GetChainConfigFunc      func(ctx context.Context, blockchainId string) (transaction.ChainConfig, error)
+	GetBlockHeightFunc      func(ctx context.Context, blockchainId string) (int, error)
+	GetBlockFunc            func(ctx context.Context, blockchainId string, height int) (transaction.ULBlock, error)
+	GetBlockByHeightFunc    func(ctx context.Context, blockchainId string, height int) (transaction.ULBlock, error)
+	GetBlockByHashFunc      func(ctx context.Context, blockchainId string, hash string) (transaction.ULBlock, error)
+	GetLatestBlockFunc      func(ctx context.Context, blockchainId string) (transaction.ULBlock, error)
+	GetTransactionFunc      func(ctx context.Context, blockchainId string, transactionId string) (transaction.ULTransaction, error)
+	GetFinalityFunc         func(ctx context.Context, blockchainId string, transactionId string) (transaction.Finality, error)
+	WaitForTransactionFunc  func(ctx context.Context, blockchainId string, transactionId string, opts transaction.WaitOptions) (transaction.ULTransaction, error)
+	PendingTransactionsFunc func(ctx context.Context, blockchainId string) ([]string, error)
+	ListBlocksFunc          func(blockchainId string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULBlock]
+	ListTransactionsFunc    func(blockchainId string, filter transaction.TransactionFilter, opts transaction.ListOptions) *transaction.Iterator[transaction.ULTransaction]
+	ListTokensFunc          func(blockchainId string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULToken]
+	ListWalletsFunc         func(blockchainId string, parent string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULWalletInfo]
+	GetWalletStateFunc      func(ctx context.Context, blockchainId string, address string) (transaction.WalletState, error)
+	DiscoverWalletsFunc     func(ctx context.Context, blockchainId string, hd *wallet.HDWallet, gapLimit int) ([]wallet.DerivedWallet, error)
+	SubscribeBlocksFunc     func(ctx context.Context, blockchainId string, opts transaction.BlockSubscriptionOptions, handler transaction.BlockHandler) error
+	StreamChainFunc         func(ctx context.Context, blockchainId string, opts transaction.StreamOptions) (<-chan transaction.ChainEvent, error)
+
+	GetTokenIdInfoFunc      func(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (transaction.ULTokenIdInfo, error)
+	GetTokenSupplyFunc      func(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (transaction.Amount, error)
+	GetTokenURIFunc         func(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (string, error)
+	ListTokenHoldersFunc    func(blockchainId string, tokenAddress string, tokenId uint64, opts transaction.ListOptions) *transaction.Iterator[transaction.ULTokenHolder]
+	IsApprovedForAllFunc    func(ctx context.Context, blockchainId string, tokenAddress string, owner string, operator string) (bool, error)
+	SetApprovalForAllFunc   func(ctx context.Context, blockchainId string, tokenAddress string, operator string, approved bool) (transaction.ULTransaction, error)
+	OperatorTransferFunc    func(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenIds []uint64, amounts []transaction.Amount) (transaction.ULTransaction, error)
+	OperatorTransferNFTFunc func(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenId uint64) (transaction.ULTransaction, error)
+	BurnFunc                func(ctx context.Context, blockchainId string, tokenAddress string, amount transaction.Amount, tokenId uint64) (transaction.ULTransaction, error)
+	BurnFromFunc            func(ctx context.Context, blockchainId string, tokenAddress string, from string, amount transaction.Amount, tokenId uint64) (transaction.ULTransaction, error)
+
+	UploadContractSourceFunc    func(ctx context.Context, blockchainId string, source []byte, opts transaction.ContractUploadOptions) (transaction.ContractSource, error)
+	DeployContractFunc          func(ctx context.Context, blockchainId string, source []byte, opts transaction.ContractUploadOptions) (transaction.ULTransaction, error)
+	CallContractFunc            func(ctx context.Context, blockchainId string, contractAddress string, functionName string, args ...any) (interface{}, error)
+	EstimateGasFunc             func(ctx context.Context, blockchainId string, contractAddress string, payload transaction.InvokeContractPayload, opts transaction.GasEstimateOptions) (transaction.GasEstimate, error)
+	SubscribeContractEventsFunc func(ctx context.Context, blockchainId string, contractAddress string, fromBlock int, opts transaction.ContractEventOptions) (<-chan transaction.ContractEvent, error)
+
+	recorder
+}
+
+var (
+	_ transaction.SessionAPI  = (*Session)(nil)
+	_ transaction.QueryAPI    = (*Session)(nil)
+	_ transaction.TokenAPI    = (*Session)(nil)
+	_ transaction.ContractAPI = (*Session)(nil)
+)
+
+func (m *Session) GetWallet() wallet.UL_Wallet {
+	m.record("GetWallet")
+	return m.Wallet
+}
+
+func (m *Session) GetSuggestor() string {
+	m.record("GetSuggestor")
+	return m.Suggestor
+}
+
+func (m *Session) GenerateTransaction(input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	m.record("GenerateTransaction", input)
+	if m.GenerateTransactionFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "GenerateTransaction"}
+	}
+	return m.GenerateTransactionFunc(input)
+}
+
+func (m *Session) GenerateIdempotentTransaction(ctx context.Context, idempotencyKey string, input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	m.record("GenerateIdempotentTransaction", idempotencyKey, input)
+	if m.GenerateIdempotentTransactionFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "GenerateIdempotentTransaction"}
+	}
+	return m.GenerateIdempotentTransactionFunc(ctx, idempotencyKey, input)
+}
+
+func (m *Session) SubmitAndWait(ctx context.Context, input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+	m.record("SubmitAndWait", input)
+	if m.SubmitAndWaitFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "SubmitAndWait"}
+	}
+	return m.SubmitAndWaitFunc(ctx, input)
+}
+
+func (m *Session) GetChainConfig(ctx context.Context, blockchainId string) (transaction.ChainConfig, error) {
+	m.record("GetChainConfig", blockchainId)
+	if m.GetChainConfigFunc == nil {
+		return transaction.ChainConfig{}, &ErrNotMocked{Method: "GetChainConfig"}
+	}
+	return m.GetChainConfigFunc(ctx, blockchainId)
+}
+
+func (m *Session) GetBlockHeight(ctx context.Context, blockchainId string) (int, error) {
+	m.record("GetBlockHeight", blockchainId)
+	if m.GetBlockHeightFunc == nil {
+		return 0, &ErrNotMocked{Method: "GetBlockHeight"}
+	}
+	return m.GetBlockHeightFunc(ctx, blockchainId)
+}
+
+func (m *Session) GetBlock(ctx context.Context, blockchainId string, height int) (transaction.ULBlock, error) {
+	m.record("GetBlock", blockchainId, height)
+	if m.GetBlockFunc == nil {
+		return transaction.ULBlock{}, &ErrNotMocked{Method: "GetBlock"}
+	}
+	return m.GetBlockFunc(ctx, blockchainId, height)
+}
+
+func (m *Session) GetBlockByHeight(ctx context.Context, blockchainId string, height int) (transaction.ULBlock, error) {
+	m.record("GetBlockByHeight", blockchainId, height)
+	if m.GetBlockByHeightFunc == nil {
+		return transaction.ULBlock{}, &ErrNotMocked{Method: "GetBlockByHeight"}
+	}
+	return m.GetBlockByHeightFunc(ctx, blockchainId, height)
+}
+
+func (m *Session) GetBlockByHash(ctx context.Context, blockchainId string, hash string) (transaction.ULBlock, error) {
+	m.record("GetBlockByHash", blockchainId, hash)
+	if m.GetBlockByHashFunc == nil {
+		return transaction.ULBlock{}, &ErrNotMocked{Method: "GetBlockByHash"}
+	}
+	return m.GetBlockByHashFunc(ctx, blockchainId, hash)
+}
+
+func (m *Session) GetLatestBlock(ctx context.Context, blockchainId string) (transaction.ULBlock, error) {
+	m.record("GetLatestBlock", blockchainId)
+	if m.GetLatestBlockFunc == nil {
+		return transaction.ULBlock{}, &ErrNotMocked{Method: "GetLatestBlock"}
+	}
+	return m.GetLatestBlockFunc(ctx, blockchainId)
+}
+
+func (m *Session) GetTransaction(ctx context.Context, blockchainId string, transactionId string) (transaction.ULTransaction, error) {
+	m.record("GetTransaction", blockchainId, transactionId)
+	if m.GetTransactionFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "GetTransaction"}
+	}
+	return m.GetTransactionFunc(ctx, blockchainId, transactionId)
+}
+
+func (m *Session) GetFinality(ctx context.Context, blockchainId string, transactionId string) (transaction.Finality, error) {
+	m.record("GetFinality", blockchainId, transactionId)
+	if m.GetFinalityFunc == nil {
+		return transaction.Finality{}, &ErrNotMocked{Method: "GetFinality"}
+	}
+	return m.GetFinalityFunc(ctx, blockchainId, transactionId)
+}
+
+func (m *Session) WaitForTransaction(ctx context.Context, blockchainId string, transactionId string, opts transaction.WaitOptions) (transaction.ULTransaction, error) {
+	m.record("WaitForTransaction", blockchainId, transactionId, opts)
+	if m.WaitForTransactionFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "WaitForTransaction"}
+	}
+	return m.WaitForTransactionFunc(ctx, blockchainId, transactionId, opts)
+}
+
+func (m *Session) PendingTransactions(ctx context.Context, blockchainId string) ([]string, error) {
+	m.record("PendingTransactions", blockchainId)
+	if m.PendingTransactionsFunc == nil {
+		return nil, &ErrNotMocked{Method: "PendingTransactions"}
+	}
+	return m.PendingTransactionsFunc(ctx, blockchainId)
+}
+
+func (m *Session) ListBlocks(blockchainId string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULBlock] {
+	m.record("ListBlocks", blockchainId, opts)
+	if m.ListBlocksFunc == nil {
+		return FailingIterator[transaction.ULBlock](&ErrNotMocked{Method: "ListBlocks"})
+	}
+	return m.ListBlocksFunc(blockchainId, opts)
+}
+
+func (m *Session) ListTransactions(blockchainId string, filter transaction.TransactionFilter, opts transaction.ListOptions) *transaction.Iterator[transaction.ULTransaction] {
+	m.record("ListTransactions", blockchainId, filter, opts)
+	if m.ListTransactionsFunc == nil {
+		return FailingIterator[transaction.ULTransaction](&ErrNotMocked{Method: "ListTransactions"})
+	}
+	return m.ListTransactionsFunc(blockchainId, filter, opts)
+}
+
+func (m *Session) ListTokens(blockchainId string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULToken] {
+	m.record("ListTokens", blockchainId, opts)
+	if m.ListTokensFunc == nil {
+		return FailingIterator[transaction.ULToken](&ErrNotMocked{Method: "ListTokens"})
+	}
+	return m.ListTokensFunc(blockchainId, opts)
+}
+
+func (m *Session) ListWallets(blockchainId string, parent string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULWalletInfo] {
+	m.record("ListWallets", blockchainId, parent, opts)
+	if m.ListWalletsFunc == nil {
+		return FailingIterator[transaction.ULWalletInfo](&ErrNotMocked{Method: "ListWallets"})
+	}
+	return m.ListWalletsFunc(blockchainId, parent, opts)
+}
+
+func (m *Session) GetWalletState(ctx context.Context, blockchainId string, address string) (transaction.WalletState, error) {
+	m.record("GetWalletState", blockchainId, address)
+	if m.GetWalletStateFunc == nil {
+		return transaction.WalletState{}, &ErrNotMocked{Method: "GetWalletState"}
+	}
+	return m.GetWalletStateFunc(ctx, blockchainId, address)
+}
+
+func (m *Session) DiscoverWallets(ctx context.Context, blockchainId string, hd *wallet.HDWallet, gapLimit int) ([]wallet.DerivedWallet, error) {
+	m.record("DiscoverWallets", blockchainId, gapLimit)
+	if m.DiscoverWalletsFunc == nil {
+		return nil, &ErrNotMocked{Method: "DiscoverWallets"}
+	}
+	return m.DiscoverWalletsFunc(ctx, blockchainId, hd, gapLimit)
+}
+
+func (m *Session) SubscribeBlocks(ctx context.Context, blockchainId string, opts transaction.BlockSubscriptionOptions, handler transaction.BlockHandler) error {
+	m.record("SubscribeBlocks", blockchainId, opts)
+	if m.SubscribeBlocksFunc == nil {
+		return &ErrNotMocked{Method: "SubscribeBlocks"}
+	}
+	return m.SubscribeBlocksFunc(ctx, blockchainId, opts, handler)
+}
+
+func (m *Session) StreamChain(ctx context.Context, blockchainId string, opts transaction.StreamOptions) (<-chan transaction.ChainEvent, error) {
+	m.record("StreamChain", blockchainId, opts)
+	if m.StreamChainFunc == nil {
+		return nil, &ErrNotMocked{Method: "StreamChain"}
+	}
+	return m.StreamChainFunc(ctx, blockchainId, opts)
+}
+
+func (m *Session) GetTokenIdInfo(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (transaction.ULTokenIdInfo, error) {
+	m.record("GetTokenIdInfo", blockchainId, tokenAddress, tokenId)
+	if m.GetTokenIdInfoFunc == nil {
+		return transaction.ULTokenIdInfo{}, &ErrNotMocked{Method: "GetTokenIdInfo"}
+	}
+	return m.GetTokenIdInfoFunc(ctx, blockchainId, tokenAddress, tokenId)
+}
+
+func (m *Session) GetTokenSupply(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (transaction.Amount, error) {
+	m.record("GetTokenSupply", blockchainId, tokenAddress, tokenId)
+	if m.GetTokenSupplyFunc == nil {
+		return transaction.Amount{}, &ErrNotMocked{Method: "GetTokenSupply"}
+	}
+	return m.GetTokenSupplyFunc(ctx, blockchainId, tokenAddress, tokenId)
+}
+
+func (m *Session) GetTokenURI(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (string, error) {
+	m.record("GetTokenURI", blockchainId, tokenAddress, tokenId)
+	if m.GetTokenURIFunc == nil {
+		return "", &ErrNotMocked{Method: "GetTokenURI"}
+	}
+	return m.GetTokenURIFunc(ctx, blockchainId, tokenAddress, tokenId)
+}
+
+func (m *Session) ListTokenHolders(blockchainId string, tokenAddress string, tokenId uint64, opts transaction.ListOptions) *transaction.Iterator[transaction.ULTokenHolder] {
+	m.record("ListTokenHolders", blockchainId, tokenAddress, tokenId, opts)
+	if m.ListTokenHoldersFunc == nil {
+		return FailingIterator[transaction.ULTokenHolder](&ErrNotMocked{Method: "ListTokenHolders"})
+	}
+	return m.ListTokenHoldersFunc(blockchainId, tokenAddress, tokenId, opts)
+}
+
+func (m *Session) IsApprovedForAll(ctx context.Context, blockchainId string, tokenAddress string, owner string, operator string) (bool, error) {
+	m.record("IsApprovedForAll", blockchainId, tokenAddress, owner, operator)
+	if m.IsApprovedForAllFunc == nil {
+		return false, &ErrNotMocked{Method: "IsApprovedForAll"}
+	}
+	return m.IsApprovedForAllFunc(ctx, blockchainId, tokenAddress, owner, operator)
+}
+
+func (m *Session) SetApprovalForAll(ctx context.Context, blockchainId string, tokenAddress string, operator string, approved bool) (transaction.ULTransaction, error) {
+	m.record("SetApprovalForAll", blockchainId, tokenAddress, operator, approved)
+	if m.SetApprovalForAllFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "SetApprovalForAll"}
+	}
+	return m.SetApprovalForAllFunc(ctx, blockchainId, tokenAddress, operator, approved)
+}
+
+func (m *Session) OperatorTransfer(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenIds []uint64, amounts []transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("OperatorTransfer", blockchainId, tokenAddress, from, to, tokenIds, amounts)
+	if m.OperatorTransferFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "OperatorTransfer"}
+	}
+	return m.OperatorTransferFunc(ctx, blockchainId, tokenAddress, from, to, tokenIds, amounts)
+}
+
+func (m *Session) OperatorTransferNFT(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("OperatorTransferNFT", blockchainId, tokenAddress, from, to, tokenId)
+	if m.OperatorTransferNFTFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "OperatorTransferNFT"}
+	}
+	return m.OperatorTransferNFTFunc(ctx, blockchainId, tokenAddress, from, to, tokenId)
+}
+
+func (m *Session) Burn(ctx context.Context, blockchainId string, tokenAddress string, amount transaction.Amount, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("Burn", blockchainId, tokenAddress, amount, tokenId)
+	if m.BurnFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Burn"}
+	}
+	return m.BurnFunc(ctx, blockchainId, tokenAddress, amount, tokenId)
+}
+
+func (m *Session) BurnFrom(ctx context.Context, blockchainId string, tokenAddress string, from string, amount transaction.Amount, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("BurnFrom", blockchainId, tokenAddress, from, amount, tokenId)
+	if m.BurnFromFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "BurnFrom"}
+	}
+	return m.BurnFromFunc(ctx, blockchainId, tokenAddress, from, amount, tokenId)
+}
+
+func (m *Session) UploadContractSource(ctx context.Context, blockchainId string, source []byte, opts transaction.ContractUploadOptions) (transaction.ContractSource, error) {
+	m.record("UploadContractSource", blockchainId, source, opts)
+	if m.UploadContractSourceFunc == nil {
+		return transaction.ContractSource{}, &ErrNotMocked{Method: "UploadContractSource"}
+	}
+	return m.UploadContractSourceFunc(ctx, blockchainId, source, opts)
+}
+
+func (m *Session) DeployContract(ctx context.Context, blockchainId string, source []byte, opts transaction.ContractUploadOptions) (transaction.ULTransaction, error) {
+	m.record("DeployContract", blockchainId, source, opts)
+	if m.DeployContractFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "DeployContract"}
+	}
+	return m.DeployContractFunc(ctx, blockchainId, source, opts)
+}
+
+func (m *Session) CallContract(ctx context.Context, blockchainId string, contractAddress string, functionName string, args ...any) (interface{}, error) {
+	m.record("CallContract", blockchainId, contractAddress, functionName, args)
+	if m.CallContractFunc == nil {
+		return nil, &ErrNotMocked{Method: "CallContract"}
+	}
+	return m.CallContractFunc(ctx, blockchainId, contractAddress, functionName, args...)
+}
+
+func (m *Session) EstimateGas(ctx context.Context, blockchainId string, contractAddress string, payload transaction.InvokeContractPayload, opts transaction.GasEstimateOptions) (transaction.GasEstimate, error) {
+	m.record("EstimateGas", blockchainId, contractAddress, payload, opts)
+	if m.EstimateGasFunc == nil {
+		return transaction.GasEstimate{}, &ErrNotMocked{Method: "EstimateGas"}
+	}
+	return m.EstimateGasFunc(ctx, blockchainId, contractAddress, payload, opts)
+}
+
+func (m *Session) SubscribeContractEvents(ctx context.Context, blockchainId string, contractAddress string, fromBlock int, opts transaction.ContractEventOptions) (<-chan transaction.ContractEvent, error) {
+	m.record("SubscribeContractEvents", blockchainId, contractAddress, fromBlock, opts)
+	if m.SubscribeContractEventsFunc == nil {
+		return nil, &ErrNotMocked{Method: "SubscribeContractEvents"}
+	}
+	return m.SubscribeContractEventsFunc(ctx, blockchainId, contractAddress, fromBlock, opts)
+}
@@ -0,0 +1,115 @@
+package mocks_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/mocks"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestSession(t *testing.T) {
+	ctx := context.Background()
+	session := &mocks.Session{}
+
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{}); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("GenerateTransaction() without a func error = %v", err)
+	}
+	if _, err := session.ListTokens("chain", transaction.ListOptions{}).Collect(ctx); !errors.As(err, new(*mocks.ErrNotMocked)) {
+		t.Fatalf("ListTokens() without a func error = %v", err)
+	}
+
+	session.Reset()
+	session.GenerateTransactionFunc = func(input transaction.ULTransactionInput) (transaction.ULTransaction, error) {
+		return transaction.ULTransaction{ULTransactionInput: input, ULTransactionOutput: transaction.ULTransactionOutput{TransactionId: "tx-" + input.To}}, nil
+	}
+	session.ListWalletsFunc = func(blockchainId string, parent string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULWalletInfo] {
+		return mocks.Iterator(transaction.ULWalletInfo{Address: "a", Parent: parent}, transaction.ULWalletInfo{Address: "b", Parent: parent})
+	}
+
+	// The mock stands in for the session wherever the interfaces are accepted
+	var api transaction.SessionAPI = session
+	tx, err := api.GenerateTransaction(transaction.ULTransactionInput{To: "bob"})
+	if err != nil || tx.TransactionId != "tx-bob" {
+		t.Fatalf("GenerateTransaction() = %+v, %v", tx, err)
+	}
+	wallets, err := api.ListWallets("chain", "root", transaction.ListOptions{}).Collect(ctx)
+	if err != nil || len(wallets) != 2 || wallets[1].Parent != "root" {
+		t.Fatalf("ListWallets() = %+v, %v", wallets, err)
+	}
+
+	calls := session.Calls()
+	if len(calls) != 2 || calls[0].Method != "GenerateTransaction" || calls[1].Method != "ListWallets" {
+		t.Fatalf("Calls() = %+v", calls)
+	}
+	if input := calls[0].Args[0].(transaction.ULTransactionInput); input.To != "bob" {
+		t.Fatalf("recorded input = %+v", input)
+	}
+	if got := session.CallsTo("ListWallets"); len(got) != 1 || got[0].Args[1] != "root" {
+		t.Fatalf("CallsTo() = %+v", got)
+	}
+}
+
+func TestSessionWithQueryConsumer(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "broken.ukey"), []byte("{"), 0600)
+
+	listErr := errors.New("node unreachable")
+	session := &mocks.Session{
+		ListWalletsFunc: func(blockchainId string, parent string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULWalletInfo] {
+			return mocks.FailingIterator[transaction.ULWalletInfo](listErr)
+		},
+	}
+	if _, err := transaction.CheckFleet(context.Background(), session, "chain", dir); !errors.Is(err, listErr) {
+		t.Fatalf("CheckFleet() error = %v, want the listing error", err)
+	}
+
+	session.ListWalletsFunc = func(blockchainId string, parent string, opts transaction.ListOptions) *transaction.Iterator[transaction.ULWalletInfo] {
+		return mocks.Iterator[transaction.ULWalletInfo]()
+	}
+	report, err := transaction.CheckFleet(context.Background(), session, "chain", dir)
+	if err != nil || report.Count(transaction.FLEET_INVALID) != 1 {
+		t.Fatalf("CheckFleet() = %+v, %v", report, err)
+	}
+}
+
+func TestTokenClients(t *testing.T) {
+	ctx := context.Background()
+	coin := &mocks.ERC20Client{Address: "coin"}
+	if _, err := coin.Transfer(ctx, "bob", transaction.NewAmount(5)); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("Transfer() without a func error = %v", err)
+	}
+
+	coin.Reset()
+	coin.BalanceOfFunc = func(ctx context.Context, owner string) (transaction.Amount, error) {
+		return transaction.NewAmount(100), nil
+	}
+	var api transaction.ERC20API = coin
+	balance, err := api.BalanceOf(ctx, "alice")
+	if err != nil || balance.Cmp(transaction.NewAmount(100)) != 0 {
+		t.Fatalf("BalanceOf() = %s, %v", balance, err)
+	}
+	if calls := coin.CallsTo("BalanceOf"); len(calls) != 1 || calls[0].Args[0] != "alice" {
+		t.Fatalf("CallsTo() = %+v", calls)
+	}
+
+	contract := &mocks.ContractClient{
+		InvokeFunc: func(ctx context.Context, functionName string, gasLimit uint64, args ...any) (transaction.ULTransaction, error) {
+			return transaction.ULTransaction{ULTransactionOutput: transaction.ULTransactionOutput{TransactionId: "tx-" + functionName}}, nil
+		},
+	}
+	var client transaction.ContractClientAPI = contract
+	if tx, err := client.Invoke(ctx, "transfer", 1000, "bob", int32(5)); err != nil || tx.TransactionId != "tx-transfer" {
+		t.Fatalf("Invoke() = %+v, %v", tx, err)
+	}
+	if _, err := client.Call(ctx, "balanceOf", "bob"); !errors.As(err, new(*mocks.ErrNotMocked)) {
+		t.Fatalf("Call() without a func error = %v", err)
+	}
+	if calls := contract.Calls(); len(calls) != 2 || calls[0].Args[0] != "transfer" {
+		t.Fatalf("Calls() = %+v", calls)
+	}
+}
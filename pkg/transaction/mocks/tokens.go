@@ -0,0 +1,201 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// ERC20Client is a mock of transaction.ERC20API, it is safe for concurrent use once configured
+type ERC20Client struct {
+	// Address is returned by TokenAddress
+	Address string
+
+	CreateTokenFunc  func(ctx context.Context, payload transaction.CreateTokenPayload) (transaction.ULTransaction, error)
+	TransferFunc     func(ctx context.Context, to string, amount transaction.Amount) (transaction.ULTransaction, error)
+	ApproveFunc      func(ctx context.Context, spender string, amount transaction.Amount) (transaction.ULTransaction, error)
+	TransferFromFunc func(ctx context.Context, from string, to string, amount transaction.Amount) (transaction.ULTransaction, error)
+	MintFunc         func(ctx context.Context, to string, amount transaction.Amount) (transaction.ULTransaction, error)
+	BurnFunc         func(ctx context.Context, amount transaction.Amount) (transaction.ULTransaction, error)
+	BalanceOfFunc    func(ctx context.Context, owner string) (transaction.Amount, error)
+	AllowanceFunc    func(ctx context.Context, owner string, spender string) (transaction.Amount, error)
+
+	recorder
+}
+
+var _ transaction.ERC20API = (*ERC20Client)(nil)
+
+func (m *ERC20Client) TokenAddress() string {
+	m.record("TokenAddress")
+	return m.Address
+}
+
+func (m *ERC20Client) CreateToken(ctx context.Context, payload transaction.CreateTokenPayload) (transaction.ULTransaction, error) {
+	m.record("CreateToken", payload)
+	if m.CreateTokenFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "CreateToken"}
+	}
+	return m.CreateTokenFunc(ctx, payload)
+}
+
+func (m *ERC20Client) Transfer(ctx context.Context, to string, amount transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("Transfer", to, amount)
+	if m.TransferFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Transfer"}
+	}
+	return m.TransferFunc(ctx, to, amount)
+}
+
+func (m *ERC20Client) Approve(ctx context.Context, spender string, amount transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("Approve", spender, amount)
+	if m.ApproveFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Approve"}
+	}
+	return m.ApproveFunc(ctx, spender, amount)
+}
+
+func (m *ERC20Client) TransferFrom(ctx context.Context, from string, to string, amount transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("TransferFrom", from, to, amount)
+	if m.TransferFromFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "TransferFrom"}
+	}
+	return m.TransferFromFunc(ctx, from, to, amount)
+}
+
+func (m *ERC20Client) Mint(ctx context.Context, to string, amount transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("Mint", to, amount)
+	if m.MintFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Mint"}
+	}
+	return m.MintFunc(ctx, to, amount)
+}
+
+func (m *ERC20Client) Burn(ctx context.Context, amount transaction.Amount) (transaction.ULTransaction, error) {
+	m.record("Burn", amount)
+	if m.BurnFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Burn"}
+	}
+	return m.BurnFunc(ctx, amount)
+}
+
+func (m *ERC20Client) BalanceOf(ctx context.Context, owner string) (transaction.Amount, error) {
+	m.record("BalanceOf", owner)
+	if m.BalanceOfFunc == nil {
+		return transaction.Amount{}, &ErrNotMocked{Method: "BalanceOf"}
+	}
+	return m.BalanceOfFunc(ctx, owner)
+}
+
+func (m *ERC20Client) Allowance(ctx context.Context, owner string, spender string) (transaction.Amount, error) {
+	m.record("Allowance", owner, spender)
+	if m.AllowanceFunc == nil {
+		return transaction.Amount{}, &ErrNotMocked{Method: "Allowance"}
+	}
+	return m.AllowanceFunc(ctx, owner, spender)
+}
+
+// ERC721Client is a mock of transaction.ERC721API, it is safe for concurrent use once configured
+type ERC721Client struct {
+	// Address is returned by TokenAddress
+	Address string
+
+	CreateTokenFunc       func(ctx context.Context, payload transaction.CreateTokenPayload) (transaction.ULTransaction, error)
+	MintFunc              func(ctx context.Context, to string, tokenId uint64, tokenURI string) (transaction.ULTransaction, error)
+	TransferFromFunc      func(ctx context.Context, from string, to string, tokenId uint64) (transaction.ULTransaction, error)
+	ApproveFunc           func(ctx context.Context, spender string, tokenId uint64) (transaction.ULTransaction, error)
+	SetApprovalForAllFunc func(ctx context.Context, operator string, approved bool) (transaction.ULTransaction, error)
+	BurnFunc              func(ctx context.Context, tokenId uint64) (transaction.ULTransaction, error)
+	OwnerOfFunc           func(ctx context.Context, tokenId uint64) (string, error)
+	BalanceOfFunc         func(ctx context.Context, owner string) (uint64, error)
+	GetApprovedFunc       func(ctx context.Context, tokenId uint64) (string, error)
+	TokenURIFunc          func(ctx context.Context, tokenId uint64) (string, error)
+
+	recorder
+}
+
+var _ transaction.ERC721API = (*ERC721Client)(nil)
+
+func (m *ERC721Client) TokenAddress() string {
+	m.record("TokenAddress")
+	return m.Address
+}
+
+func (m *ERC721Client) CreateToken(ctx context.Context, payload transaction.CreateTokenPayload) (transaction.ULTransaction, error) {
+	m.record("CreateToken", payload)
+	if m.CreateTokenFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "CreateToken"}
+	}
+	return m.CreateTokenFunc(ctx, payload)
+}
+
+func (m *ERC721Client) Mint(ctx context.Context, to string, tokenId uint64, tokenURI string) (transaction.ULTransaction, error) {
+	m.record("Mint", to, tokenId, tokenURI)
+	if m.MintFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Mint"}
+	}
+	return m.MintFunc(ctx, to, tokenId, tokenURI)
+}
+
+func (m *ERC721Client) TransferFrom(ctx context.Context, from string, to string, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("TransferFrom", from, to, tokenId)
+	if m.TransferFromFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "TransferFrom"}
+	}
+	return m.TransferFromFunc(ctx, from, to, tokenId)
+}
+
+func (m *ERC721Client) Approve(ctx context.Context, spender string, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("Approve", spender, tokenId)
+	if m.ApproveFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Approve"}
+	}
+	return m.ApproveFunc(ctx, spender, tokenId)
+}
+
+func (m *ERC721Client) SetApprovalForAll(ctx context.Context, operator string, approved bool) (transaction.ULTransaction, error) {
+	m.record("SetApprovalForAll", operator, approved)
+	if m.SetApprovalForAllFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "SetApprovalForAll"}
+	}
+	return m.SetApprovalForAllFunc(ctx, operator, approved)
+}
+
+func (m *ERC721Client) Burn(ctx context.Context, tokenId uint64) (transaction.ULTransaction, error) {
+	m.record("Burn", tokenId)
+	if m.BurnFunc == nil {
+		return transaction.ULTransaction{}, &ErrNotMocked{Method: "Burn"}
+	}
+	return m.BurnFunc(ctx, tokenId)
+}
+
+func (m *ERC721Client) OwnerOf(ctx context.Context, tokenId uint64) (string, error) {
+	m.record("OwnerOf", tokenId)
+	if m.OwnerOfFunc == nil {
+		return "", &ErrNotMocked{Method: "OwnerOf"}
+	}
+	return m.OwnerOfFunc(ctx, tokenId)
+}
+
+func (m *ERC721Client) BalanceOf(ctx context.Context, owner string) (uint64, error) {
+	m.record("BalanceOf", owner)
+	if m.BalanceOfFunc == nil {
+		return 0, &ErrNotMocked{Method: "BalanceOf"}
+	}
+	return m.BalanceOfFunc(ctx, owner)
+}
+
+func (m *ERC721Client) GetApproved(ctx context.Context, tokenId uint64) (string, error) {
+	m.record("GetApproved", tokenId)
+	if m.GetApprovedFunc == nil {
+		return "", &ErrNotMocked{Method: "GetApproved"}
+	}
+	return m.GetApprovedFunc(ctx, tokenId)
+}
+
+func (m *ERC721Client) TokenURI(ctx context.Context, tokenId uint64) (string, error) {
+	m.record("TokenURI", tokenId)
+	if m.TokenURIFunc == nil {
+		return "", &ErrNotMocked{Method: "TokenURI"}
+	}
+	return m.TokenURIFunc(ctx, tokenId)
+}
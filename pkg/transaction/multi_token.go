@@ -0,0 +1,60 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+)
+
+// ULTokenHolder is an owner of a token id with its balance
+type ULTokenHolder struct {
+	Owner   string `json:"owner"`
+	Balance Amount `json:"balance"`
+}
+
+// ULTokenIdInfo describes one id of an ERC1155 token, or one minted ERC721 token. URI is resolved by
+// the node, the URI given when the id was minted or the token's BaseURI with {id} replaced by the hex
+// id.
+type ULTokenIdInfo struct {
+	TokenAddress string `json:"tokenAddress"`
+	TokenId      uint64 `json:"tokenId"`
+	TotalSupply  Amount `json:"totalSupply"`
+	URI          string `json:"uri"`
+	Holders      int    `json:"holders"`
+}
+
+// GetTokenIdInfo fetches the supply, URI and holder count of one id of an ERC1155 or ERC721 token
+func (session *UL_TransactionSession) GetTokenIdInfo(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (ULTokenIdInfo, error) {
+	info := ULTokenIdInfo{}
+	if err := session.getJson(ctx, tokenIdPath(blockchainId, tokenAddress, tokenId), &info); err != nil {
+		return ULTokenIdInfo{}, err
+	}
+	return info, nil
+}
+
+// GetTokenSupply returns the amount of an ERC1155 token id in circulation, burned amounts excluded
+func (session *UL_TransactionSession) GetTokenSupply(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (Amount, error) {
+	info, err := session.GetTokenIdInfo(ctx, blockchainId, tokenAddress, tokenId)
+	if err != nil {
+		return Amount{}, err
+	}
+	return info.TotalSupply, nil
+}
+
+// GetTokenURI returns the metadata URI of an ERC1155 token id or of an ERC721 token
+func (session *UL_TransactionSession) GetTokenURI(ctx context.Context, blockchainId string, tokenAddress string, tokenId uint64) (string, error) {
+	info, err := session.GetTokenIdInfo(ctx, blockchainId, tokenAddress, tokenId)
+	if err != nil {
+		return "", err
+	}
+	return info.URI, nil
+}
+
+// ListTokenHolders iterates over the owners of an ERC1155 token id with a non zero balance, ordered
+// by address
+func (session *UL_TransactionSession) ListTokenHolders(blockchainId string, tokenAddress string, tokenId uint64, opts ListOptions) *Iterator[ULTokenHolder] {
+	return listPages[ULTokenHolder](session, tokenIdPath(blockchainId, tokenAddress, tokenId)+"/holders", nil, opts)
+}
+
+func tokenIdPath(blockchainId string, tokenAddress string, tokenId uint64) string {
+	return fmt.Sprintf("/blockchains/%s/tokens/%s/ids/%d", blockchainId, tokenAddress, tokenId)
+}
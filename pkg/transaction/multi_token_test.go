@@ -0,0 +1,112 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// submitToken submits a token transaction and fails the test unless the node applied it
+func submitToken(t *testing.T, session *transaction.UL_TransactionSession, payloadType transaction.ULTransactionType, payload any) transaction.ULTransaction {
+	t.Helper()
+	tx, err := trySubmitToken(session, payloadType, payload)
+	if err != nil {
+		t.Fatalf("%s error = %v", payloadType, err)
+	}
+	if tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("%s output = %s", payloadType, tx.Output)
+	}
+	return tx
+}
+
+func trySubmitToken(session *transaction.UL_TransactionSession, payloadType transaction.ULTransactionType, payload any) (transaction.ULTransaction, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return transaction.ULTransaction{}, err
+	}
+	return session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      string(data),
+		PayloadType:  payloadType.String(),
+	})
+}
+
+// createToken creates a token and returns its address
+func createToken(t *testing.T, session *transaction.UL_TransactionSession, payload transaction.CreateTokenPayload) string {
+	t.Helper()
+	submitToken(t, session, transaction.CREATE_TOKEN, payload)
+	tokens, err := session.ListTokens(testBlockchainId, transaction.ListOptions{}).Collect(context.Background())
+	if err != nil || len(tokens) == 0 {
+		t.Fatalf("ListTokens() = %+v, %v", tokens, err)
+	}
+	return tokens[len(tokens)-1].TokenAddress
+}
+
+// amounts returns the amounts of an ERC1155 batch
+func amounts(values ...uint64) []transaction.Amount {
+	batch := make([]transaction.Amount, len(values))
+	for i, v := range values {
+		batch[i] = transaction.NewAmount(v)
+	}
+	return batch
+}
+
+func TestMultiTokenQueries(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	owner := session.GetWallet().Address
+
+	token := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC1155_TOKEN_TYPE, Name: "Tickets", Symbol: "TIX", BaseURI: "https://tickets.example.com/{id}.json", Mintable: true})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: owner, TokenId: 1, Amount: transaction.NewAmount(1000)})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: owner, TokenId: 2, Amount: transaction.NewAmount(5), TokenURI: "ipfs://vip"})
+
+	recipients := make([]string, 5)
+	for i := range recipients {
+		recipients[i] = fmt.Sprintf("%064x", i+1)
+		submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipients[i], TokenId: 1, Amount: transaction.NewAmount(uint64(10 * (i + 1)))})
+	}
+	submitToken(t, session, transaction.TRANSFER_MULTI_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipients[0], TokenIds: []uint64{1, 2}, Amounts: amounts(1, 2)})
+
+	info, err := session.GetTokenIdInfo(ctx, testBlockchainId, token, 1)
+	if err != nil || info.TotalSupply.Cmp(transaction.NewAmount(1000)) != 0 || info.Holders != 6 || info.URI != fmt.Sprintf("https://tickets.example.com/%064x.json", 1) {
+		t.Fatalf("GetTokenIdInfo() = %+v, %v", info, err)
+	}
+	if supply, err := session.GetTokenSupply(ctx, testBlockchainId, token, 2); err != nil || supply.Cmp(transaction.NewAmount(5)) != 0 {
+		t.Fatalf("GetTokenSupply() = %s, %v", supply, err)
+	}
+	if uri, err := session.GetTokenURI(ctx, testBlockchainId, token, 2); err != nil || uri != "ipfs://vip" {
+		t.Fatalf("GetTokenURI() = %q, %v", uri, err)
+	}
+
+	it := session.ListTokenHolders(testBlockchainId, token, 1, transaction.ListOptions{PageSize: 2})
+	holders, err := it.Collect(ctx)
+	if err != nil || len(holders) != 6 || it.PageInfo().Pages != 3 {
+		t.Fatalf("ListTokenHolders() = %+v, %v", holders, err)
+	}
+	total := transaction.Amount{}
+	for i, holder := range holders {
+		if i > 0 && holders[i-1].Owner >= holder.Owner {
+			t.Fatalf("holders are not ordered by address: %+v", holders)
+		}
+		total = total.Add(holder.Balance)
+	}
+	if total.Cmp(info.TotalSupply) != 0 || holders[0].Owner != recipients[0] || holders[0].Balance.Cmp(transaction.NewAmount(11)) != 0 {
+		t.Fatalf("holders = %+v", holders)
+	}
+
+	// A batch the owner cannot cover moves nothing
+	tx, err := trySubmitToken(session, transaction.TRANSFER_MULTI_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, To: recipients[1], TokenIds: []uint64{1, 2}, Amounts: amounts(1, 100)})
+	if err != nil || tx.Output == transaction.TX_SUCCESS.String() {
+		t.Fatalf("uncovered batch = %s, %v", tx.Output, err)
+	}
+	if supply, _ := session.GetTokenSupply(ctx, testBlockchainId, token, 7); !supply.IsZero() {
+		t.Fatalf("GetTokenSupply() of an unminted id = %s", supply)
+	}
+	if _, err := session.GetTokenIdInfo(ctx, testBlockchainId, recipients[0], 1); err == nil {
+		t.Fatal("GetTokenIdInfo() of an unknown token succeeded")
+	}
+}
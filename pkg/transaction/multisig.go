@@ -0,0 +1,324 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_MULTISIG is advertised by nodes with the native multisig module
+const NODE_FEATURE_MULTISIG = "multisig"
+
+const (
+	MULTISIG_ACTION_APPROVE = "approve"
+	MULTISIG_ACTION_EXECUTE = "execute"
+)
+
+// MultisigProposalStatus is where a proposal of a multisig account stands
+type MultisigProposalStatus string
+
+const (
+	MULTISIG_PENDING  MultisigProposalStatus = "PENDING"
+	MULTISIG_EXECUTED MultisigProposalStatus = "EXECUTED"
+	// MULTISIG_FAILED proposals were executed but the node did not apply their transaction
+	MULTISIG_FAILED MultisigProposalStatus = "FAILED"
+)
+
+// CreateMultisigPayload is the payload of a CREATE_MULTISIG, Threshold of Owners must approve every
+// transaction of the account
+type CreateMultisigPayload struct {
+	Owners    []string `json:"owners"`
+	Threshold int      `json:"threshold"`
+	Name      string   `json:"name,omitempty"`
+}
+
+// ProposeMultisigPayload is the payload of a PROPOSE_MULTISIG, the transaction the account sends
+// once enough owners approved it
+type ProposeMultisigPayload struct {
+	Account     string `json:"account"`
+	PayloadType string `json:"payloadType"`
+	To          string `json:"to,omitempty"`
+	Payload     string `json:"payload"`
+	Description string `json:"description,omitempty"`
+}
+
+// MultisigProposalPayload is the payload of APPROVE_MULTISIG and EXECUTE_MULTISIG. Action repeats
+// the payload type, MULTISIG_ACTION_APPROVE or MULTISIG_ACTION_EXECUTE, so an owner approving and
+// executing a proposal within a second does not send the same commitment twice.
+type MultisigProposalPayload struct {
+	Account    string `json:"account"`
+	ProposalId string `json:"proposalId"`
+	Action     string `json:"action"`
+}
+
+// MultisigAccount is a multisig account of a chain. Like contracts, it is addressed by the id of
+// the transaction that created it.
+type MultisigAccount struct {
+	Address      string   `json:"address"`
+	BlockchainId string   `json:"blockchainId"`
+	Name         string   `json:"name,omitempty"`
+	Owners       []string `json:"owners"`
+	Threshold    int      `json:"threshold"`
+	CreatedBlock int      `json:"createdBlock"`
+}
+
+// MultisigProposal is a transaction proposed to a multisig account, it is identified by the id of
+// the PROPOSE_MULTISIG transaction. Proposing counts as the approval of the proposer.
+type MultisigProposal struct {
+	ProposalId      string                 `json:"proposalId"`
+	Account         string                 `json:"account"`
+	Proposer        string                 `json:"proposer"`
+	PayloadType     string                 `json:"payloadType"`
+	To              string                 `json:"to,omitempty"`
+	Payload         string                 `json:"payload"`
+	Description     string                 `json:"description,omitempty"`
+	Approvals       []string               `json:"approvals"`
+	Status          MultisigProposalStatus `json:"status"`
+	ExecutionId     string                 `json:"executionId,omitempty"`
+	ExecutionOutput string                 `json:"executionOutput,omitempty"`
+}
+
+// ErrMultisigUnsupported is returned when the node does not advertise NODE_FEATURE_MULTISIG
+type ErrMultisigUnsupported struct{}
+
+func (e *ErrMultisigUnsupported) Error() string {
+	return "the node does not support multisig accounts"
+}
+
+func (e *ErrMultisigUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ErrInvalidMultisig is returned before submitting a multisig payload the node would refuse
+type ErrInvalidMultisig struct {
+	Msg string
+}
+
+func (e *ErrInvalidMultisig) Error() string {
+	return fmt.Sprintf("invalid multisig operation, %s", e.Msg)
+}
+
+func (e *ErrInvalidMultisig) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ErrMultisigRefused is returned instead of submitting an approval or an execution the account
+// would reject, e.g. by a wallet that is not an owner or below the threshold
+type ErrMultisigRefused struct {
+	Account    string
+	ProposalId string
+	Msg        string
+}
+
+func (e *ErrMultisigRefused) Error() string {
+	return fmt.Sprintf("proposal %s of the multisig account %s refused, %s", e.ProposalId, e.Account, e.Msg)
+}
+
+func (e *ErrMultisigRefused) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Validate checks the account is well formed
+func (p CreateMultisigPayload) Validate() error {
+	if len(p.Owners) == 0 {
+		return &ErrInvalidMultisig{Msg: "the account has no owner"}
+	}
+	for i, owner := range p.Owners {
+		if !isAddress(owner) {
+			return &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid owner address %q", owner)}
+		}
+		if slices.Contains(p.Owners[:i], owner) {
+			return &ErrInvalidMultisig{Msg: fmt.Sprintf("%s owns the account twice", owner)}
+		}
+	}
+	if p.Threshold < 1 || p.Threshold > len(p.Owners) {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("a threshold of %d for %d owners", p.Threshold, len(p.Owners))}
+	}
+	return nil
+}
+
+// MultisigClient sends the operations of one multisig account from the session's wallet, one of its
+// owners. Approvals and executions are checked against the account first so a refused one fails
+// without a rejected transaction.
+//
+//	treasury := transaction.NewMultisigClient(session, "")
+//	_, err := treasury.CreateAccount(ctx, transaction.CreateMultisigPayload{Owners: owners, Threshold: 2})
+//	proposed, err := treasury.Propose(ctx, transaction.TRANSFER_TOKEN.String(), token, payload, "pay the auditors")
+//	// each other owner: _, err := transaction.NewMultisigClient(ownerSession, account).Approve(ctx, proposed.TransactionId)
+//	_, err = treasury.Execute(ctx, proposed.TransactionId)
+type MultisigClient struct {
+	// BlockchainId is the chain of the account, empty uses the session defaults
+	BlockchainId string
+
+	session *UL_TransactionSession
+	account string
+}
+
+// NewMultisigClient returns a client of the account at address, empty for a client that creates
+// its account with CreateAccount
+func NewMultisigClient(session *UL_TransactionSession, account string) *MultisigClient {
+	return &MultisigClient{session: session, account: account}
+}
+
+// Account returns the address of the account, empty until CreateAccount succeeded
+func (c *MultisigClient) Account() string {
+	return c.account
+}
+
+// CreateAccount creates a multisig account and binds the client to it
+func (c *MultisigClient) CreateAccount(ctx context.Context, payload CreateMultisigPayload) (ULTransaction, error) {
+	if c.account != "" {
+		return ULTransaction{}, &ErrInvalidMultisig{Msg: fmt.Sprintf("the client is bound to the account %s", c.account)}
+	}
+	if err := payload.Validate(); err != nil {
+		return ULTransaction{}, err
+	}
+	if err := c.supported(ctx); err != nil {
+		return ULTransaction{}, err
+	}
+	tx, err := c.session.submitToken(ctx, c.BlockchainId, CREATE_MULTISIG, "", payload)
+	if err != nil {
+		return tx, err
+	}
+	c.account = tx.TransactionId
+	return tx, nil
+}
+
+// GetAccount fetches the owners and threshold of the account
+func (c *MultisigClient) GetAccount(ctx context.Context) (MultisigAccount, error) {
+	if err := c.checkAccount(); err != nil {
+		return MultisigAccount{}, err
+	}
+	account := MultisigAccount{}
+	if err := c.session.getJson(ctx, c.path(), &account); err != nil {
+		return MultisigAccount{}, err
+	}
+	return account, nil
+}
+
+// Propose submits the transaction the account should send, payload is JSON encoded unless it is a
+// string. The id of the returned transaction identifies the proposal.
+func (c *MultisigClient) Propose(ctx context.Context, payloadType string, to string, payload any, description string) (ULTransaction, error) {
+	encoded, ok := payload.(string)
+	if !ok {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return ULTransaction{}, err
+		}
+		encoded = string(data)
+	}
+	if payloadType == "" {
+		return ULTransaction{}, &ErrInvalidMultisig{Msg: "the proposed payload type is missing"}
+	}
+	return c.submit(ctx, PROPOSE_MULTISIG, ProposeMultisigPayload{Account: c.account, PayloadType: payloadType, To: to, Payload: encoded, Description: description})
+}
+
+// Approve adds the approval of the session's wallet to a pending proposal
+func (c *MultisigClient) Approve(ctx context.Context, proposalId string) (ULTransaction, error) {
+	account, proposal, err := c.pending(ctx, proposalId)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	owner := c.session.wallet.Address
+	if !slices.Contains(account.Owners, owner) {
+		return ULTransaction{}, &ErrMultisigRefused{Account: c.account, ProposalId: proposalId, Msg: fmt.Sprintf("%s is not an owner", owner)}
+	}
+	if slices.Contains(proposal.Approvals, owner) {
+		return ULTransaction{}, &ErrMultisigRefused{Account: c.account, ProposalId: proposalId, Msg: fmt.Sprintf("%s already approved", owner)}
+	}
+	return c.submit(ctx, APPROVE_MULTISIG, MultisigProposalPayload{Account: c.account, ProposalId: proposalId, Action: MULTISIG_ACTION_APPROVE})
+}
+
+// Execute has the account send the transaction of a proposal enough owners approved. It fails
+// unless the node applied the proposed transaction.
+func (c *MultisigClient) Execute(ctx context.Context, proposalId string) (ULTransaction, error) {
+	account, proposal, err := c.pending(ctx, proposalId)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if len(proposal.Approvals) < account.Threshold {
+		return ULTransaction{}, &ErrMultisigRefused{Account: c.account, ProposalId: proposalId, Msg: fmt.Sprintf("%d of %d approvals", len(proposal.Approvals), account.Threshold)}
+	}
+	return c.submit(ctx, EXECUTE_MULTISIG, MultisigProposalPayload{Account: c.account, ProposalId: proposalId, Action: MULTISIG_ACTION_EXECUTE})
+}
+
+// GetProposal fetches a proposal of the account with its approvals
+func (c *MultisigClient) GetProposal(ctx context.Context, proposalId string) (MultisigProposal, error) {
+	if err := c.checkAccount(); err != nil {
+		return MultisigProposal{}, err
+	}
+	proposal := MultisigProposal{}
+	path, _ := url.JoinPath(c.path(), "proposals", proposalId)
+	if err := c.session.getJson(ctx, path, &proposal); err != nil {
+		return MultisigProposal{}, err
+	}
+	return proposal, nil
+}
+
+// ListProposals iterates over the proposals of the account in the order they were made, an empty
+// status lists them all
+func (c *MultisigClient) ListProposals(status MultisigProposalStatus, opts ListOptions) *Iterator[MultisigProposal] {
+	return listPages[MultisigProposal](c.session, c.path()+"/proposals", url.Values{"status": {string(status)}}, opts)
+}
+
+// Pending iterates over the proposals waiting for approvals or execution
+func (c *MultisigClient) Pending(opts ListOptions) *Iterator[MultisigProposal] {
+	return c.ListProposals(MULTISIG_PENDING, opts)
+}
+
+// pending fetches the account and one of its proposals, failing unless the proposal is pending
+func (c *MultisigClient) pending(ctx context.Context, proposalId string) (MultisigAccount, MultisigProposal, error) {
+	account, err := c.GetAccount(ctx)
+	if err != nil {
+		return MultisigAccount{}, MultisigProposal{}, err
+	}
+	proposal, err := c.GetProposal(ctx, proposalId)
+	if err != nil {
+		return MultisigAccount{}, MultisigProposal{}, err
+	}
+	if proposal.Status != MULTISIG_PENDING {
+		return MultisigAccount{}, MultisigProposal{}, &ErrMultisigRefused{Account: c.account, ProposalId: proposalId, Msg: fmt.Sprintf("it is %s", proposal.Status)}
+	}
+	return account, proposal, nil
+}
+
+func (c *MultisigClient) supported(ctx context.Context) error {
+	supported, err := c.session.hasFeature(ctx, NODE_FEATURE_MULTISIG)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return &ErrMultisigUnsupported{}
+	}
+	return nil
+}
+
+func (c *MultisigClient) checkAccount() error {
+	if !isAddress(c.account) {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid account address %q", c.account)}
+	}
+	return nil
+}
+
+func (c *MultisigClient) path() string {
+	blockchainId := c.BlockchainId
+	if blockchainId == "" {
+		blockchainId = c.session.defaults.BlockchainId
+	}
+	return fmt.Sprintf("/blockchains/%s/multisigs/%s", blockchainId, c.account)
+}
+
+// submit sends a payload of the client's account, transactions are addressed to the account
+func (c *MultisigClient) submit(ctx context.Context, payloadType ULTransactionType, payload any) (ULTransaction, error) {
+	if err := c.checkAccount(); err != nil {
+		return ULTransaction{}, err
+	}
+	if err := c.supported(ctx); err != nil {
+		return ULTransaction{}, err
+	}
+	return c.session.submitToken(ctx, c.BlockchainId, payloadType, c.account, payload)
+}
@@ -0,0 +1,83 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestMultisigClient(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	treasury := transaction.NewMultisigClient(session, "")
+	treasury.BlockchainId = testBlockchainId
+	peers := []*transaction.UL_TransactionSession{newPeerSession(t, node), newPeerSession(t, node)}
+	owners := []string{session.GetWallet().Address, peers[0].GetWallet().Address, peers[1].GetWallet().Address}
+	if _, err := treasury.CreateAccount(ctx, transaction.CreateMultisigPayload{Owners: owners, Threshold: 2}); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("CreateAccount() without the multisig module error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_MULTISIG)
+	if _, err := treasury.CreateAccount(ctx, transaction.CreateMultisigPayload{Owners: owners, Threshold: 4}); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("CreateAccount() above the owner count error = %v", err)
+	}
+	if _, err := treasury.CreateAccount(ctx, transaction.CreateMultisigPayload{Owners: owners, Threshold: 2, Name: "treasury"}); err != nil {
+		t.Fatalf("CreateAccount() error = %v", err)
+	}
+	account, err := treasury.GetAccount(ctx)
+	if err != nil || account.Threshold != 2 || len(account.Owners) != 3 || account.Address != treasury.Account() {
+		t.Fatalf("GetAccount() = %+v, %v", account, err)
+	}
+
+	// The account pays from its own balance
+	coin := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Coin", Symbol: "CN", InitialSupply: transaction.NewAmount(1000)})
+	submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: coin, To: treasury.Account(), Amount: transaction.NewAmount(100)})
+	recipient := fmt.Sprintf("%064x", 1)
+	proposed, err := treasury.Propose(ctx, transaction.TRANSFER_TOKEN.String(), coin, transaction.TransferTokenPayload{TokenAddress: coin, To: recipient, Amount: transaction.NewAmount(40)}, "pay the auditors")
+	if err != nil {
+		t.Fatalf("Propose() error = %v", err)
+	}
+	proposalId := proposed.TransactionId
+	if _, err := treasury.Execute(ctx, proposalId); !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Execute() below the threshold error = %v", err)
+	}
+	if _, err := treasury.Approve(ctx, proposalId); !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Approve() by the proposer error = %v", err)
+	}
+	outsider := transaction.NewMultisigClient(newPeerSession(t, node), treasury.Account())
+	outsider.BlockchainId = testBlockchainId
+	var refused *transaction.ErrMultisigRefused
+	if _, err := outsider.Approve(ctx, proposalId); !errors.As(err, &refused) {
+		t.Fatalf("Approve() by an outsider error = %v", err)
+	}
+
+	cosigner := transaction.NewMultisigClient(peers[0], treasury.Account())
+	cosigner.BlockchainId = testBlockchainId
+	if _, err := cosigner.Approve(ctx, proposalId); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	pending, err := treasury.Pending(transaction.ListOptions{}).Collect(ctx)
+	if err != nil || len(pending) != 1 || len(pending[0].Approvals) != 2 || pending[0].Description != "pay the auditors" {
+		t.Fatalf("Pending() = %+v, %v", pending, err)
+	}
+	if _, err := cosigner.Execute(ctx, proposalId); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if balance, _ := transaction.NewTokenClient(session, testBlockchainId).BalanceOf(ctx, coin, recipient); balance.String() != "40" {
+		t.Fatalf("balance of the recipient = %s, want 40", balance)
+	}
+	proposal, err := treasury.GetProposal(ctx, proposalId)
+	if err != nil || proposal.Status != transaction.MULTISIG_EXECUTED {
+		t.Fatalf("GetProposal() after Execute = %+v, %v", proposal, err)
+	}
+	if pending, _ := treasury.Pending(transaction.ListOptions{}).Collect(ctx); len(pending) != 0 {
+		t.Fatalf("Pending() after Execute = %d proposals", len(pending))
+	}
+	if _, err := treasury.Execute(ctx, proposalId); !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Execute() twice error = %v", err)
+	}
+}
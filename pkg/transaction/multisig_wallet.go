@@ -0,0 +1,334 @@
+package transaction
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// MAX_MULTISIG_KEYS bounds the keys of a MultisigWallet, combined signatures index keys with a byte
+const MAX_MULTISIG_KEYS = 64
+
+// MultisigWallet is a wallet controlled by Threshold of its keys, the M-of-N counterpart of a
+// UL_Wallet. Unlike the accounts of MultisigClient it needs no node support to be signed for: the
+// keys sign the same commitment offline and CombineSignatures packs their signatures into the
+// SenderSignature of the transaction, which carries the wallet in ULTransactionInput.Multisig.
+//
+// Every key has the same key type since the commitment depends on it. The keys are kept sorted, so
+// the address does not depend on the order they were given in.
+type MultisigWallet struct {
+	Threshold  int            `json:"threshold"`
+	KeyType    crypto.KeyType `json:"keyType"`
+	PublicKeys []string       `json:"publicKeys"`
+}
+
+// MultisigSignature is the signature of one key of a multisig wallet over a transaction commitment
+type MultisigSignature struct {
+	PublicKey string `json:"publicKey"`
+	Signature string `json:"signature"`
+}
+
+// NewMultisigWallet returns the wallet threshold of the uncompressed publicKeys control
+func NewMultisigWallet(threshold int, keyType crypto.KeyType, publicKeys ...string) (MultisigWallet, error) {
+	m := MultisigWallet{Threshold: threshold, KeyType: keyType, PublicKeys: make([]string, len(publicKeys))}
+	for i, publicKey := range publicKeys {
+		m.PublicKeys[i] = strings.ToLower(strings.TrimPrefix(publicKey, "0x"))
+	}
+	slices.Sort(m.PublicKeys)
+	if err := m.Validate(); err != nil {
+		return MultisigWallet{}, err
+	}
+	return m, nil
+}
+
+// Validate checks the keys and the threshold, e.g. of a wallet read from a transaction
+func (m MultisigWallet) Validate() error {
+	if len(m.PublicKeys) == 0 || len(m.PublicKeys) > MAX_MULTISIG_KEYS {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("a wallet needs 1 to %d keys, not %d", MAX_MULTISIG_KEYS, len(m.PublicKeys))}
+	}
+	if !slices.IsSorted(m.PublicKeys) {
+		return &ErrInvalidMultisig{Msg: "the keys of the wallet are not sorted"}
+	}
+	for i, publicKey := range m.PublicKeys {
+		if i > 0 && publicKey == m.PublicKeys[i-1] {
+			return &ErrInvalidMultisig{Msg: fmt.Sprintf("the key %s is given twice", publicKey)}
+		}
+		if _, err := m.key(i); err != nil {
+			return err
+		}
+	}
+	if m.Threshold < 1 || m.Threshold > len(m.PublicKeys) {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("a threshold of %d for %d keys", m.Threshold, len(m.PublicKeys))}
+	}
+	return nil
+}
+
+// Address returns the address of the wallet, the hash of its key type, threshold and keys
+func (m MultisigWallet) Address() string {
+	return wallet.ParseAddress(fmt.Sprintf("multisig|%s|%d|%s", m.KeyType, m.Threshold, strings.Join(m.PublicKeys, "|")))
+}
+
+// key parses the i-th public key of the wallet
+func (m MultisigWallet) key(i int) (crypto.ULKey, error) {
+	key, err := crypto.GetKeyByType(m.KeyType, crypto.GetHasherByType(m.KeyType))
+	if err != nil {
+		return nil, err
+	}
+	if err := key.GeneratePublicKeyFromHex(false, m.PublicKeys[i]); err != nil {
+		return nil, &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid public key %s: %v", m.PublicKeys[i], err)}
+	}
+	return key, nil
+}
+
+// verify checks signature was made over commitment by a key of the wallet and returns the index of the key
+func (m MultisigWallet) verify(commitment []byte, signature MultisigSignature) (int, error) {
+	i, found := slices.BinarySearch(m.PublicKeys, strings.ToLower(signature.PublicKey))
+	if !found {
+		return 0, &ErrInvalidMultisig{Msg: fmt.Sprintf("%s is not a key of the wallet %s", signature.PublicKey, m.Address())}
+	}
+	key, err := m.key(i)
+	if err != nil {
+		return 0, err
+	}
+	raw, err := crypto.HexToBytes(signature.Signature)
+	if err != nil {
+		return 0, &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid signature of %s: %v", signature.PublicKey, err)}
+	}
+	ok, err := key.VerifySignature(commitment, raw)
+	if err != nil || !ok {
+		return 0, &ErrInvalidMultisig{Msg: fmt.Sprintf("the signature of %s does not match the commitment", signature.PublicKey)}
+	}
+	return i, nil
+}
+
+// CollectSignature signs input, a transaction of a multisig wallet, with w, one of the keys of the wallet
+func CollectSignature(input ULTransactionInput, w *wallet.UL_Wallet) (MultisigSignature, error) {
+	if err := w.CheckKey(); err != nil {
+		return MultisigSignature{}, err
+	}
+	if input.Multisig == nil {
+		return MultisigSignature{}, &ErrInvalidMultisig{Msg: "the transaction is not sent by a multisig wallet"}
+	}
+	publicKey := strings.ToLower(w.GetKey().GetPublicKeyHex(false))
+	if _, found := slices.BinarySearch(input.Multisig.PublicKeys, publicKey); !found {
+		return MultisigSignature{}, &ErrInvalidMultisig{Msg: fmt.Sprintf("%s holds no key of the wallet %s", w.Address, input.Multisig.Address())}
+	}
+	commitment, _, err := input.SigningCommitment()
+	if err != nil {
+		return MultisigSignature{}, err
+	}
+	signature, err := w.GetKey().SignData(commitment)
+	if err != nil {
+		return MultisigSignature{}, fmt.Errorf("unable to sign the commitment: %w", err)
+	}
+	return MultisigSignature{PublicKey: publicKey, Signature: crypto.BytesToHex(signature)}, nil
+}
+
+// CombineSignatures verifies signatures over commitment and packs Threshold of them into the sender
+// signature of the wallet. Each signature is encoded as the index of its key, a 2 byte big endian
+// length and the signature, ordered by key. Extra signatures are left out.
+func CombineSignatures(m MultisigWallet, commitment []byte, signatures []MultisigSignature) (string, error) {
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	signed := map[int][]byte{}
+	for _, signature := range signatures {
+		i, err := m.verify(commitment, signature)
+		if err != nil {
+			return "", err
+		}
+		signed[i], _ = crypto.HexToBytes(signature.Signature)
+	}
+	if len(signed) < m.Threshold {
+		return "", &ErrInvalidMultisig{Msg: fmt.Sprintf("%d of %d signatures", len(signed), m.Threshold)}
+	}
+	combined := []byte{}
+	count := 0
+	for i := range m.PublicKeys {
+		raw, ok := signed[i]
+		if !ok {
+			continue
+		}
+		combined = append(combined, byte(i))
+		combined = binary.BigEndian.AppendUint16(combined, uint16(len(raw)))
+		combined = append(combined, raw...)
+		if count++; count == m.Threshold {
+			break
+		}
+	}
+	return crypto.BytesToHex(combined), nil
+}
+
+// VerifyMultisigSignature checks the combined signature of m over commitment, see CombineSignatures
+func VerifyMultisigSignature(m MultisigWallet, commitment []byte, combined string) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	data, err := crypto.HexToBytes(combined)
+	if err != nil {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid combined signature: %v", err)}
+	}
+	previous := -1
+	for count := 0; count < m.Threshold; count++ {
+		if len(data) < 3 {
+			return &ErrInvalidMultisig{Msg: fmt.Sprintf("the combined signature holds %d of %d signatures", count, m.Threshold)}
+		}
+		i, size := int(data[0]), int(binary.BigEndian.Uint16(data[1:3]))
+		if i <= previous || i >= len(m.PublicKeys) || len(data) < 3+size {
+			return &ErrInvalidMultisig{Msg: "the combined signature is malformed"}
+		}
+		signature := MultisigSignature{PublicKey: m.PublicKeys[i], Signature: crypto.BytesToHex(data[3 : 3+size])}
+		if _, err := m.verify(commitment, signature); err != nil {
+			return err
+		}
+		previous, data = i, data[3+size:]
+	}
+	if len(data) > 0 {
+		return &ErrInvalidMultisig{Msg: "the combined signature has trailing bytes"}
+	}
+	return nil
+}
+
+// PartiallySignedTransaction is a transaction of a multisig wallet on its way from signer to signer.
+// It is JSON encoded to be passed around, each signer parses it, adds a signature and passes it on
+// until Finalize has enough:
+//
+//	pending, _ := treasury.NewTransaction(input)
+//	data, _ := json.Marshal(pending)
+//	// each signer: pending, _ := transaction.ParsePartiallySignedTransaction(data); pending.Sign(&w); data, _ = json.Marshal(pending)
+//	signed, _ := pending.Finalize()
+//	tx, _ := transaction.SubmitSignedTransaction(ctx, nodeEndpoint, signed)
+type PartiallySignedTransaction struct {
+	Input      ULTransactionInput  `json:"input"`
+	Signatures []MultisigSignature `json:"signatures"`
+}
+
+// NewTransaction prepares input to be signed by the keys of the wallet. As with BuildSignedTransaction,
+// input names the chain and the Suggestor and SenderTimestamp defaults to now. Transactions of
+// multisig wallets are signed with raw signatures.
+func (m MultisigWallet) NewTransaction(input ULTransactionInput) (*PartiallySignedTransaction, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if input.BlockchainId == "" {
+		return nil, &ErrMissingBlockchainId{}
+	}
+	if input.Suggestor == "" {
+		return nil, &ErrIncompleteTransaction{Field: "suggestor", Msg: "set it to the id of the node the transaction will be submitted to"}
+	}
+	if input.SignatureEncoding != "" && input.SignatureEncoding != crypto.SIGNATURE_ENCODING_RAW {
+		return nil, &ErrInvalidMultisig{Msg: fmt.Sprintf("multisig wallets cannot sign with %s encoding", input.SignatureEncoding)}
+	}
+	if input.SenderTimestamp.IsZero() {
+		input.SenderTimestamp = time.Now().UTC().Truncate(time.Second)
+	}
+	input.From = m.Address()
+	input.KeyType = m.KeyType
+	input.SignatureEncoding = ""
+	input.SenderSignature = ""
+	input.Multisig = &m
+	if err := ValidateInput(input); err != nil {
+		return nil, err
+	}
+	_, payloadRoot, err := input.SigningCommitment()
+	if err != nil {
+		return nil, err
+	}
+	input.PayloadRoot = payloadRoot
+	return &PartiallySignedTransaction{Input: input, Signatures: []MultisigSignature{}}, nil
+}
+
+// ParsePartiallySignedTransaction decodes a transaction passed by another signer and verifies the
+// signatures it collected so far
+func ParsePartiallySignedTransaction(data []byte) (*PartiallySignedTransaction, error) {
+	p := &PartiallySignedTransaction{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, &ErrInvalidMultisig{Msg: fmt.Sprintf("invalid partially signed transaction: %v", err)}
+	}
+	if p.Input.Multisig == nil {
+		return nil, &ErrInvalidMultisig{Msg: "the transaction is not sent by a multisig wallet"}
+	}
+	if err := p.Input.Multisig.Validate(); err != nil {
+		return nil, err
+	}
+	if p.Input.From != p.Input.Multisig.Address() {
+		return nil, &ErrInvalidMultisig{Msg: fmt.Sprintf("the transaction is sent by %s, not by its wallet %s", p.Input.From, p.Input.Multisig.Address())}
+	}
+	commitment, _, err := p.Input.SigningCommitment()
+	if err != nil {
+		return nil, err
+	}
+	for _, signature := range p.Signatures {
+		if _, err := p.Input.Multisig.verify(commitment, signature); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Sign adds the signature of w, which must hold a key of the wallet that has not signed yet
+func (p *PartiallySignedTransaction) Sign(w *wallet.UL_Wallet) error {
+	signature, err := CollectSignature(p.Input, w)
+	if err != nil {
+		return err
+	}
+	if p.Signed(signature.PublicKey) {
+		return &ErrInvalidMultisig{Msg: fmt.Sprintf("%s already signed", w.Address)}
+	}
+	p.Signatures = append(p.Signatures, signature)
+	return nil
+}
+
+// Signed reports whether the key publicKey signed the transaction
+func (p *PartiallySignedTransaction) Signed(publicKey string) bool {
+	return slices.ContainsFunc(p.Signatures, func(signature MultisigSignature) bool {
+		return strings.EqualFold(signature.PublicKey, publicKey)
+	})
+}
+
+// Missing returns the number of signatures still needed to finalize the transaction
+func (p *PartiallySignedTransaction) Missing() int {
+	if p.Input.Multisig == nil {
+		return 0
+	}
+	return max(p.Input.Multisig.Threshold-len(p.Signatures), 0)
+}
+
+// Finalize combines the signatures into the sender signature, the result can be submitted with
+// SubmitSignedTransaction
+func (p *PartiallySignedTransaction) Finalize() (ULTransactionInput, error) {
+	if p.Input.Multisig == nil {
+		return ULTransactionInput{}, &ErrInvalidMultisig{Msg: "the transaction is not sent by a multisig wallet"}
+	}
+	commitment, _, err := p.Input.SigningCommitment()
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	combined, err := CombineSignatures(*p.Input.Multisig, commitment, p.Signatures)
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	input := p.Input
+	input.SenderSignature = combined
+	return input, nil
+}
+
+// checkMultisigSignature is checkSignature for transactions of multisig wallets, which carry their keys
+func checkMultisigSignature(input ULTransactionInput, commitment []byte) CheckResult {
+	if commitment == nil {
+		return CheckResult{Detail: "skipped, the commitment could not be recomputed"}
+	}
+	if address := input.Multisig.Address(); !strings.EqualFold(address, input.From) {
+		return CheckResult{Checked: true, Detail: fmt.Sprintf("the multisig wallet %s is not %s", address, input.From)}
+	}
+	if err := VerifyMultisigSignature(*input.Multisig, commitment, input.SenderSignature); err != nil {
+		return CheckResult{Checked: true, Detail: err.Error()}
+	}
+	return CheckResult{Checked: true, Valid: true, Detail: fmt.Sprintf("%d of %d %s signatures verified", input.Multisig.Threshold, len(input.Multisig.PublicKeys), input.KeyType)}
+}
@@ -0,0 +1,130 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestMultisigWallet(t *testing.T) {
+	ctx := context.Background()
+	node, session := newMockSession(t)
+	signers := make([]wallet.UL_Wallet, 3)
+	publicKeys := make([]string, len(signers))
+	for i := range signers {
+		w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		signers[i], publicKeys[i] = w, w.GetKey().GetPublicKeyHex(false)
+	}
+	treasury, err := transaction.NewMultisigWallet(2, crypto.KeyTypeSecp256k1, publicKeys...)
+	if err != nil {
+		t.Fatalf("NewMultisigWallet() error = %v", err)
+	}
+	reordered, _ := transaction.NewMultisigWallet(2, crypto.KeyTypeSecp256k1, publicKeys[2], publicKeys[0], publicKeys[1])
+	if reordered.Address() != treasury.Address() {
+		t.Fatal("the address of the wallet depends on the order of its keys")
+	}
+	for _, invalid := range [][]string{{publicKeys[0], publicKeys[0]}, {publicKeys[0], "not a key"}} {
+		if _, err := transaction.NewMultisigWallet(1, crypto.KeyTypeSecp256k1, invalid...); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("NewMultisigWallet(%v) error = %v", invalid, err)
+		}
+	}
+	if _, err := transaction.NewMultisigWallet(4, crypto.KeyTypeSecp256k1, publicKeys...); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("NewMultisigWallet() above the key count error = %v", err)
+	}
+
+	pending, err := treasury.NewTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		Suggestor:    session.GetSuggestor(),
+		To:           signers[0].Address,
+		Payload:      "signed by two of three",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("NewTransaction() error = %v", err)
+	}
+	if pending.Input.From != treasury.Address() || pending.Missing() != 2 {
+		t.Fatalf("NewTransaction() = %+v, want 2 missing signatures from %s", pending, treasury.Address())
+	}
+
+	// The transaction travels as JSON between the signers
+	outsider := session.GetWallet()
+	for i, signer := range signers[:2] {
+		data, err := json.Marshal(pending)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		if pending, err = transaction.ParsePartiallySignedTransaction(data); err != nil {
+			t.Fatalf("ParsePartiallySignedTransaction() error = %v", err)
+		}
+		if err := pending.Sign(&outsider); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Fatalf("Sign() by an outsider error = %v", err)
+		}
+		if i == 1 {
+			if _, err := pending.Finalize(); !errors.Is(err, utils.ErrInvalidInput) {
+				t.Fatalf("Finalize() below the threshold error = %v", err)
+			}
+		}
+		if err := pending.Sign(&signer); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+		if err := pending.Sign(&signer); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Fatalf("Sign() twice error = %v", err)
+		}
+	}
+	if pending.Missing() != 0 || !pending.Signed(publicKeys[1]) || pending.Signed(publicKeys[2]) {
+		t.Fatalf("after two signatures Missing() = %d", pending.Missing())
+	}
+
+	// A signature over another commitment spoils the transaction
+	tampered := *pending
+	tampered.Input.Payload = "tampered"
+	data, _ := json.Marshal(tampered)
+	if _, err := transaction.ParsePartiallySignedTransaction(data); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("ParsePartiallySignedTransaction() of a tampered transaction error = %v", err)
+	}
+
+	signed, err := pending.Finalize()
+	if err != nil {
+		t.Fatalf("Finalize() error = %v", err)
+	}
+	commitment, _, _ := signed.SigningCommitment()
+	if err := transaction.VerifyMultisigSignature(treasury, commitment, signed.SenderSignature); err != nil {
+		t.Fatalf("VerifyMultisigSignature() error = %v", err)
+	}
+	if err := transaction.VerifyMultisigSignature(treasury, commitment, signed.SenderSignature[:len(signed.SenderSignature)-2]); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("VerifyMultisigSignature() of a truncated signature error = %v", err)
+	}
+	tx, err := transaction.SubmitSignedTransaction(ctx, node.URL(), signed)
+	if err != nil || tx.From != treasury.Address() {
+		t.Fatalf("SubmitSignedTransaction() = %+v, %v", tx.ULTransactionOutput, err)
+	}
+	if decoded := transaction.DecodeTransaction(tx, ""); !decoded.Commitment.Valid || !decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() = %+v, %+v", decoded.Commitment, decoded.Signature)
+	}
+
+	// CollectSignature and CombineSignatures work without the partially signed envelope
+	signatures := []transaction.MultisigSignature{}
+	for _, signer := range []wallet.UL_Wallet{signers[2], signers[0]} {
+		signature, err := transaction.CollectSignature(pending.Input, &signer)
+		if err != nil {
+			t.Fatalf("CollectSignature() error = %v", err)
+		}
+		signatures = append(signatures, signature)
+	}
+	combined, err := transaction.CombineSignatures(treasury, commitment, signatures)
+	if err != nil {
+		t.Fatalf("CombineSignatures() error = %v", err)
+	}
+	if err := transaction.VerifyMultisigSignature(treasury, commitment, combined); err != nil {
+		t.Fatalf("VerifyMultisigSignature() of the combined signatures error = %v", err)
+	}
+}
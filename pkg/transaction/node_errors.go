@@ -0,0 +1,189 @@
+package transaction
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NodeFailure is the detail shared by the typed errors of node responses. Failed requests keep
+// their NodeError as Cause, so errors.As still finds it. Rejections reported in the output of a
+// transaction have no Cause.
+type NodeFailure struct {
+	// Reason is the message of the node, or the output of the rejected transaction
+	Reason string
+	Cause  error
+}
+
+func (f NodeFailure) Unwrap() error {
+	return f.Cause
+}
+
+func (f NodeFailure) detail() string {
+	if f.Cause != nil {
+		return f.Cause.Error()
+	}
+	return "transaction output " + f.Reason
+}
+
+// ErrUnauthorized is returned when the node refuses the credentials of the request or the sender
+// may not perform the transaction: HTTP 401 and 403, REJECTED_BY_UNAUTHORIZED and
+// REJECTED_BY_DISABLED outputs
+type ErrUnauthorized struct {
+	NodeFailure
+}
+
+func (e *ErrUnauthorized) Error() string {
+	return "unauthorized, " + e.detail()
+}
+
+func (e *ErrUnauthorized) Is(target error) bool {
+	_, same := target.(*ErrUnauthorized)
+	return same || target == utils.ErrRejected
+}
+
+// ErrDuplicateTransaction is returned when the node already knows the transaction: HTTP 409 and
+// REJECTED_BY_DUPLICATE outputs
+type ErrDuplicateTransaction struct {
+	NodeFailure
+}
+
+func (e *ErrDuplicateTransaction) Error() string {
+	return "duplicate transaction, " + e.detail()
+}
+
+func (e *ErrDuplicateTransaction) Is(target error) bool {
+	_, same := target.(*ErrDuplicateTransaction)
+	return same || target == utils.ErrRejected
+}
+
+// ErrInvalidSignature is returned when the node cannot verify the sender signature: bad requests
+// blaming the signature, REJECTED_BY_INVALID_SIGNATURE and REJECTED_BY_INVALID_KEY_TYPE outputs
+type ErrInvalidSignature struct {
+	NodeFailure
+}
+
+func (e *ErrInvalidSignature) Error() string {
+	return "invalid signature, " + e.detail()
+}
+
+func (e *ErrInvalidSignature) Is(target error) bool {
+	_, same := target.(*ErrInvalidSignature)
+	return same || target == utils.ErrInvalidInput
+}
+
+// ErrChainNotFound is returned for HTTP 404 responses naming the blockchain rather than a resource
+// of it
+type ErrChainNotFound struct {
+	NodeFailure
+	BlockchainId string
+}
+
+func (e *ErrChainNotFound) Error() string {
+	return fmt.Sprintf("blockchain %s not found, %s", e.BlockchainId, e.detail())
+}
+
+func (e *ErrChainNotFound) Is(target error) bool {
+	_, same := target.(*ErrChainNotFound)
+	return same || target == utils.ErrNotFound
+}
+
+// ErrNodeUnavailable is returned for HTTP 429 and 5xx responses, the request may succeed later
+type ErrNodeUnavailable struct {
+	NodeFailure
+	StatusCode int
+}
+
+func (e *ErrNodeUnavailable) Error() string {
+	return "node unavailable, " + e.detail()
+}
+
+func (e *ErrNodeUnavailable) Is(target error) bool {
+	_, same := target.(*ErrNodeUnavailable)
+	return same || target == utils.ErrUnavailable
+}
+
+// classifyNodeError wraps a failed response in the typed error its status code and message call
+// for, responses matching none are returned as is
+func classifyNodeError(nodeErr *NodeError) error {
+	reason := nodeErrorReason(nodeErr.Body)
+	failure := NodeFailure{Reason: reason, Cause: nodeErr}
+	if output, err := ParseTransactionOutput(reason); err == nil {
+		if err := outputError(output, failure); err != nil {
+			return err
+		}
+	}
+
+	lower := strings.ToLower(reason)
+	switch status := nodeErr.StatusCode; {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return &ErrUnauthorized{failure}
+	case status == http.StatusConflict && strings.Contains(nodeErr.Path, "/transactions"):
+		return &ErrDuplicateTransaction{failure}
+	case (status == http.StatusBadRequest || status == http.StatusUnprocessableEntity) && strings.Contains(lower, "signature"):
+		return &ErrInvalidSignature{failure}
+	case status == http.StatusNotFound && strings.Contains(lower, "blockchain") && strings.Contains(lower, "not found"):
+		return &ErrChainNotFound{NodeFailure: failure, BlockchainId: pathBlockchainId(nodeErr.Path)}
+	case errors.Is(nodeErr, utils.ErrUnavailable):
+		return &ErrNodeUnavailable{NodeFailure: failure, StatusCode: status}
+	default:
+		return nodeErr
+	}
+}
+
+// nodeErrorReason returns the message of an error body, nodes answer either plain text or a JSON
+// object carrying the message or the transaction output
+func nodeErrorReason(body string) string {
+	message := struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+		Output  string `json:"output"`
+	}{}
+	if json.Unmarshal([]byte(body), &message) == nil {
+		for _, reason := range []string{message.Output, message.Error, message.Message} {
+			if reason != "" {
+				return reason
+			}
+		}
+	}
+	return strings.TrimSpace(body)
+}
+
+// pathBlockchainId returns the chain of a /blockchains/{id}/... path
+func pathBlockchainId(path string) string {
+	rest, ok := strings.CutPrefix(path, "/blockchains/")
+	if !ok {
+		return ""
+	}
+	id, _, _ := strings.Cut(rest, "/")
+	id, _, _ = strings.Cut(id, "?")
+	return id
+}
+
+// OutputError returns the typed error of a transaction the node rejected: ErrDuplicateTransaction,
+// ErrUnauthorized or ErrInvalidSignature. Other rejections and transactions that were not rejected
+// return nil, see ErrTransactionRejected for every rejection.
+func OutputError(tx ULTransaction) error {
+	output, err := ParseTransactionOutput(tx.Output)
+	if err != nil {
+		return nil
+	}
+	return outputError(output, NodeFailure{Reason: tx.Output})
+}
+
+func outputError(output UL_TransactionOutput, failure NodeFailure) error {
+	switch output {
+	case TX_REJECTED_BY_DUPLICATE:
+		return &ErrDuplicateTransaction{failure}
+	case TX_REJECTED_BY_UNAUTHORIZED, TX_REJECTED_BY_DISABLED:
+		return &ErrUnauthorized{failure}
+	case TX_REJECTED_BY_INVALID_SIGNATURE, TX_REJECTED_BY_INVALID_KEY_TYPE:
+		return &ErrInvalidSignature{failure}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,110 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestNodeErrors(t *testing.T) {
+	ctx := context.Background()
+	server, _ := transportNode(t)
+	// /fail/{status}/... answers with status and the body given in the query
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/fail/")
+		if !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		status, _ := strconv.Atoi(strings.Split(rest, "/")[0])
+		http.Error(w, r.URL.Query().Get("body"), status)
+	})
+	server.Start()
+	w, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	session, err := transaction.NewUL_TransactionSession(server.URL, w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+
+	for _, test := range []struct {
+		path     string
+		target   error
+		category error
+	}{
+		{"/fail/401/blockchains/chain/transactions?body=missing+token", &transaction.ErrUnauthorized{}, utils.ErrRejected},
+		{"/fail/403/blockchains/chain/transactions?body=forbidden", &transaction.ErrUnauthorized{}, utils.ErrRejected},
+		{"/fail/409/blockchains/chain/transactions?body=already+known", &transaction.ErrDuplicateTransaction{}, utils.ErrRejected},
+		{"/fail/400/blockchains/chain/transactions?body=invalid+sender+signature", &transaction.ErrInvalidSignature{}, utils.ErrInvalidInput},
+		{`/fail/400/blockchains/chain/transactions?body={"output":"REJECTED_BY_DISABLED"}`, &transaction.ErrUnauthorized{}, utils.ErrRejected},
+		{"/fail/503/blockchains/chain/transactions?body=overloaded", &transaction.ErrNodeUnavailable{}, utils.ErrUnavailable},
+		{"/fail/429/blockchains/chain/transactions?body=slow+down", &transaction.ErrNodeUnavailable{}, utils.ErrUnavailable},
+	} {
+		err := session.Do(ctx, http.MethodPost, test.path, nil, nil)
+		var nodeErr *transaction.NodeError
+		if !errors.Is(err, test.target) || !errors.Is(err, test.category) || !errors.As(err, &nodeErr) {
+			t.Fatalf("Do(%s) error = %v", test.path, err)
+		}
+	}
+
+	// Unrecognized failures stay plain NodeErrors
+	err = session.Do(ctx, http.MethodPost, "/fail/400/blockchains/chain/transactions?body=bad+payload", nil, nil)
+	if _, ok := err.(*transaction.NodeError); !ok {
+		t.Fatalf("Do() of a bad request error = %T %v", err, err)
+	}
+	err = session.Do(ctx, http.MethodPost, "/fail/409/blockchains?body=exists", nil, nil)
+	if errors.Is(err, &transaction.ErrDuplicateTransaction{}) {
+		t.Fatalf("a conflict creating a chain is a duplicate transaction")
+	}
+}
+
+func TestChainNotFound(t *testing.T) {
+	_, session := newMockSession(t)
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: "unknown",
+		To:           session.GetWallet().Address,
+		Payload:      "lost",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	var notFound *transaction.ErrChainNotFound
+	if !errors.As(err, &notFound) || notFound.BlockchainId != "unknown" || !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GenerateTransaction() on an unknown chain error = %v", err)
+	}
+
+	// Missing transactions are not missing chains
+	_, err = session.GetTransaction(context.Background(), testBlockchainId, "missing")
+	if errors.As(err, &notFound) || !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GetTransaction() of an unknown id error = %v", err)
+	}
+}
+
+func TestOutputError(t *testing.T) {
+	for output, target := range map[transaction.UL_TransactionOutput]error{
+		transaction.TX_REJECTED_BY_DUPLICATE:         &transaction.ErrDuplicateTransaction{},
+		transaction.TX_REJECTED_BY_UNAUTHORIZED:      &transaction.ErrUnauthorized{},
+		transaction.TX_REJECTED_BY_INVALID_SIGNATURE: &transaction.ErrInvalidSignature{},
+	} {
+		tx := transaction.ULTransaction{ULTransactionOutput: transaction.ULTransactionOutput{TransactionId: "id", Output: output.String()}}
+		if err := transaction.OutputError(tx); !errors.Is(err, target) {
+			t.Fatalf("OutputError(%s) = %v", output, err)
+		}
+		// Waiting on the transaction reports the same reason
+		if err := error(&transaction.ErrTransactionRejected{Transaction: tx}); !errors.Is(err, target) || !errors.Is(err, utils.ErrRejected) {
+			t.Fatalf("ErrTransactionRejected of %s does not match its reason", output)
+		}
+	}
+	for _, output := range []transaction.UL_TransactionOutput{transaction.TX_SUCCESS, transaction.TX_TRANSACTION_ERROR} {
+		tx := transaction.ULTransaction{ULTransactionOutput: transaction.ULTransactionOutput{Output: output.String()}}
+		if err := transaction.OutputError(tx); err != nil {
+			t.Fatalf("OutputError(%s) = %v", output, err)
+		}
+	}
+}
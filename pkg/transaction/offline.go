@@ -0,0 +1,108 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// ErrIncompleteTransaction is returned for inputs missing a field offline signing cannot fill in
+type ErrIncompleteTransaction struct {
+	Field string
+	Msg   string
+}
+
+func (e *ErrIncompleteTransaction) Error() string {
+	return fmt.Sprintf("transaction is missing its %s, %s", e.Field, e.Msg)
+}
+
+func (e *ErrIncompleteTransaction) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// GetSuggestor returns the id of the node the session submits to, offline signers need it as the
+// suggestor is part of the signed commitment
+func (session *UL_TransactionSession) GetSuggestor() string {
+	return session.suggestor
+}
+
+// BuildSignedTransaction signs input with w without contacting a node, e.g. on an air-gapped
+// machine. The result is the JSON body SubmitSignedTransaction sends later on.
+//
+// input names the chain and the Suggestor, the id of the node it will be submitted to, see
+// GetSuggestor. SenderTimestamp defaults to now and SignatureEncoding to raw signatures. Session
+// features such as defaults, guardrails, delegations and hybrid signatures do not apply.
+func BuildSignedTransaction(input ULTransactionInput, w *wallet.UL_Wallet) (ULTransactionInput, error) {
+	if err := w.CheckKey(); err != nil {
+		return ULTransactionInput{}, err
+	}
+	if input.BlockchainId == "" {
+		return ULTransactionInput{}, &ErrMissingBlockchainId{}
+	}
+	if input.Suggestor == "" {
+		return ULTransactionInput{}, &ErrIncompleteTransaction{Field: "suggestor", Msg: "set it to the id of the node the transaction will be submitted to"}
+	}
+	if err := input.CheckPayloadSize(); err != nil {
+		return ULTransactionInput{}, err
+	}
+
+	if input.SenderTimestamp.IsZero() {
+		input.SenderTimestamp = time.Now().UTC().Truncate(time.Second)
+	}
+	// Create transactions can come from no yet known source
+	if input.PayloadType != TX_CREATE_WALLET.String() {
+		input.From = w.Address
+	}
+	input.KeyType = w.GetKey().GetType()
+	encoding := input.SignatureEncoding
+	if !crypto.SupportsSignatureEncoding(input.KeyType, encoding) {
+		return ULTransactionInput{}, fmt.Errorf("%s wallets cannot sign with %s encoding", input.KeyType, encoding)
+	}
+	if encoding == crypto.SIGNATURE_ENCODING_RAW {
+		input.SignatureEncoding = ""
+	}
+	if err := ValidateInput(input); err != nil {
+		return ULTransactionInput{}, err
+	}
+
+	commitment, payloadRoot, err := input.SigningCommitment()
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	input.PayloadRoot = payloadRoot
+	signature, err := crypto.SignDataWithEncoding(w.GetKey(), commitment, encoding)
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	input.SenderSignature = crypto.BytesToHex(signature)
+	return input, nil
+}
+
+// SubmitSignedTransaction sends a transaction signed by BuildSignedTransaction to the node at
+// nodeEndpoint. Unlike NewUL_TransactionSession it sends no other request to the node.
+func SubmitSignedTransaction(ctx context.Context, nodeEndpoint string, input ULTransactionInput, opts ...SessionOptions) (ULTransaction, error) {
+	if input.SenderSignature == "" {
+		return ULTransaction{}, &ErrIncompleteTransaction{Field: "sender signature", Msg: "sign it with BuildSignedTransaction"}
+	}
+	if input.BlockchainId == "" {
+		return ULTransaction{}, &ErrMissingBlockchainId{}
+	}
+	options, err := sessionOptions(opts)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	session := unconnectedSession(nodeEndpoint, wallet.UL_Wallet{}, options)
+	if session.ownsClient {
+		defer session.httpClient.CloseIdleConnections()
+	}
+
+	transaction := ULTransaction{}
+	if err := session.Do(ctx, "POST", fmt.Sprintf("/blockchains/%s/transactions", input.BlockchainId), input, &transaction); err != nil {
+		return ULTransaction{}, err
+	}
+	return transaction, nil
+}
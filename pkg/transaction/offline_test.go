@@ -0,0 +1,69 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestOfflineSigning(t *testing.T) {
+	ctx := context.Background()
+	node, session := newMockSession(t)
+	suggestor := session.GetSuggestor()
+
+	for _, encoding := range []crypto.SignatureEncoding{crypto.SIGNATURE_ENCODING_RAW, crypto.SIGNATURE_ENCODING_DER} {
+		t.Run(string(encoding), func(t *testing.T) {
+			// The air-gapped signer only knows the wallet, the chain and the node id
+			w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+			if err != nil {
+				t.Fatalf("GenerateNewWallet() error = %v", err)
+			}
+			signed, err := transaction.BuildSignedTransaction(transaction.ULTransactionInput{
+				BlockchainId:      testBlockchainId,
+				Suggestor:         suggestor,
+				To:                w.Address,
+				Payload:           "signed offline",
+				PayloadType:       transaction.TX_DATA.String(),
+				SignatureEncoding: encoding,
+			}, &w)
+			if err != nil {
+				t.Fatalf("BuildSignedTransaction() error = %v", err)
+			}
+			data, _ := json.Marshal(signed)
+			var carried transaction.ULTransactionInput
+			if err := json.Unmarshal(data, &carried); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+
+			tx, err := transaction.SubmitSignedTransaction(ctx, node.URL(), carried)
+			if err != nil || tx.TransactionId == "" || tx.From != w.Address {
+				t.Fatalf("SubmitSignedTransaction() = %+v, %v", tx.ULTransactionOutput, err)
+			}
+			decoded := transaction.DecodeTransaction(tx, w.GetKey().GetPublicKeyHex(false))
+			if !decoded.Commitment.Valid || !decoded.Signature.Valid {
+				t.Fatalf("DecodeTransaction() = %+v, %+v", decoded.Commitment, decoded.Signature)
+			}
+		})
+	}
+
+	w := session.GetWallet()
+	input := transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: w.Address, Payload: "unsigned", PayloadType: transaction.TX_DATA.String()}
+	var incomplete *transaction.ErrIncompleteTransaction
+	if _, err := transaction.BuildSignedTransaction(input, &w); !errors.As(err, &incomplete) || incomplete.Field != "suggestor" {
+		t.Fatalf("BuildSignedTransaction() without a suggestor error = %v", err)
+	}
+	keyless := wallet.UL_Wallet{Address: w.Address}
+	input.Suggestor = suggestor
+	if _, err := transaction.BuildSignedTransaction(input, &keyless); err == nil {
+		t.Fatalf("BuildSignedTransaction() with a keyless wallet succeeded")
+	}
+	if _, err := transaction.SubmitSignedTransaction(ctx, node.URL(), input); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("SubmitSignedTransaction() of an unsigned input error = %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrOperatorNotApproved is returned before submitting a transfer the operator is not approved for
+type ErrOperatorNotApproved struct {
+	TokenAddress string
+	Owner        string
+	Operator     string
+}
+
+func (e *ErrOperatorNotApproved) Error() string {
+	return fmt.Sprintf("operator not approved, %s may not move the %s tokens of %s", e.Operator, e.TokenAddress, e.Owner)
+}
+
+func (e *ErrOperatorNotApproved) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Validate checks the approval is well formed
+func (p SetApprovalForAllPayload) Validate() error {
+	if !isAddress(p.TokenAddress) {
+		return &ErrInvalidTokenPayload{PayloadType: SET_APPROVAL_FOR_ALL.String(), Msg: fmt.Sprintf("invalid token address %q", p.TokenAddress)}
+	}
+	if !isAddress(p.Operator) {
+		return &ErrInvalidTokenPayload{PayloadType: SET_APPROVAL_FOR_ALL.String(), Msg: fmt.Sprintf("invalid operator address %q", p.Operator)}
+	}
+	return nil
+}
+
+// SetApprovalForAll lets operator move every ERC721 or ERC1155 token of the session's wallet, or
+// withdraws that right when approved is false
+func (session *UL_TransactionSession) SetApprovalForAll(ctx context.Context, blockchainId string, tokenAddress string, operator string, approved bool) (ULTransaction, error) {
+	payload := SetApprovalForAllPayload{TokenAddress: tokenAddress, Operator: operator, Approved: approved}
+	if err := payload.Validate(); err != nil {
+		return ULTransaction{}, err
+	}
+	if operator == session.wallet.Address {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: SET_APPROVAL_FOR_ALL.String(), Msg: "a wallet cannot be its own operator"}
+	}
+	return session.submitToken(ctx, blockchainId, SET_APPROVAL_FOR_ALL, tokenAddress, payload)
+}
+
+// IsApprovedForAll reports whether operator may move every token of owner
+func (session *UL_TransactionSession) IsApprovedForAll(ctx context.Context, blockchainId string, tokenAddress string, owner string, operator string) (bool, error) {
+	response := struct {
+		Approved bool `json:"approved"`
+	}{}
+	path := fmt.Sprintf("/blockchains/%s/tokens/%s/operators/%s/%s", blockchainId, tokenAddress, owner, operator)
+	if err := session.getJson(ctx, path, &response); err != nil {
+		return false, err
+	}
+	return response.Approved, nil
+}
+
+// OperatorTransfer moves amounts of the ERC1155 tokenIds of from to to as its approved operator, in
+// one batch. The approval is checked first so a missing one fails without a rejected transaction.
+func (session *UL_TransactionSession) OperatorTransfer(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenIds []uint64, amounts []Amount) (ULTransaction, error) {
+	if len(tokenIds) == 0 || len(tokenIds) != len(amounts) {
+		return ULTransaction{}, &ErrInvalidTokenPayload{PayloadType: TRANSFER_MULTI_TOKEN.String(), Msg: fmt.Sprintf("%d token ids for %d amounts", len(tokenIds), len(amounts))}
+	}
+	if err := session.checkOperator(ctx, blockchainId, tokenAddress, from); err != nil {
+		return ULTransaction{}, err
+	}
+	payload := TransferTokenPayload{TokenAddress: tokenAddress, From: from, To: to, TokenIds: tokenIds, Amounts: amounts}
+	return session.submitToken(ctx, blockchainId, TRANSFER_MULTI_TOKEN, to, payload)
+}
+
+// OperatorTransferNFT moves the ERC721 token tokenId of from to to as its approved operator
+func (session *UL_TransactionSession) OperatorTransferNFT(ctx context.Context, blockchainId string, tokenAddress string, from string, to string, tokenId uint64) (ULTransaction, error) {
+	if err := session.checkOperator(ctx, blockchainId, tokenAddress, from); err != nil {
+		return ULTransaction{}, err
+	}
+	payload := TransferTokenPayload{TokenAddress: tokenAddress, From: from, To: to, TokenId: tokenId}
+	return session.submitToken(ctx, blockchainId, TRANSFER_NFT, to, payload)
+}
+
+func (session *UL_TransactionSession) checkOperator(ctx context.Context, blockchainId string, tokenAddress string, owner string) error {
+	if owner == session.wallet.Address {
+		return nil
+	}
+	approved, err := session.IsApprovedForAll(ctx, blockchainId, tokenAddress, owner, session.wallet.Address)
+	if err != nil {
+		return fmt.Errorf("unable to check the operator approval: %w", err)
+	}
+	if !approved {
+		return &ErrOperatorNotApproved{TokenAddress: tokenAddress, Owner: owner, Operator: session.wallet.Address}
+	}
+	return nil
+}
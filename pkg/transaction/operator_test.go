@@ -0,0 +1,73 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestOperatorTransfer(t *testing.T) {
+	node, owner := newMockSession(t)
+	operator := newPeerSession(t, node)
+	ctx := context.Background()
+	ownerAddress, operatorAddress := owner.GetWallet().Address, operator.GetWallet().Address
+	recipient := "00000000000000000000000000000000000000000000000000000000000000aa"
+
+	token := createToken(t, owner, transaction.CreateTokenPayload{TokenType: transaction.ERC1155_TOKEN_TYPE, Name: "Tickets", Symbol: "TIX", Mintable: true, Burnable: true})
+	submitToken(t, owner, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: ownerAddress, TokenId: 1, Amount: transaction.NewAmount(100)})
+	submitToken(t, owner, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: token, To: ownerAddress, TokenId: 2, Amount: transaction.NewAmount(10)})
+
+	// Without an approval the transfer fails before anything is submitted
+	notApproved := &transaction.ErrOperatorNotApproved{}
+	if _, err := operator.OperatorTransfer(ctx, testBlockchainId, token, ownerAddress, recipient, []uint64{1}, amounts(5)); !errors.As(err, &notApproved) {
+		t.Fatalf("OperatorTransfer() without approval error = %v", err)
+	}
+
+	if _, err := owner.SetApprovalForAll(ctx, testBlockchainId, token, operatorAddress, true); err != nil {
+		t.Fatalf("SetApprovalForAll() error = %v", err)
+	}
+	if approved, err := owner.IsApprovedForAll(ctx, testBlockchainId, token, ownerAddress, operatorAddress); err != nil || !approved {
+		t.Fatalf("IsApprovedForAll() = %v, %v, want true", approved, err)
+	}
+	if approved, err := owner.IsApprovedForAll(ctx, testBlockchainId, token, operatorAddress, ownerAddress); err != nil || approved {
+		t.Fatalf("IsApprovedForAll() of the reverse pair = %v, %v, want false", approved, err)
+	}
+
+	if _, err := operator.OperatorTransfer(ctx, testBlockchainId, token, ownerAddress, recipient, []uint64{1, 2}, amounts(30, 4)); err != nil {
+		t.Fatalf("OperatorTransfer() error = %v", err)
+	}
+	if a, b := node.MultiTokenBalance(token, 1, recipient), node.MultiTokenBalance(token, 2, recipient); a != 30 || b != 4 {
+		t.Fatalf("recipient balances = %d and %d, want 30 and 4", a, b)
+	}
+	if _, err := operator.BurnFrom(ctx, testBlockchainId, token, ownerAddress, transaction.NewAmount(20), 1); err != nil {
+		t.Fatalf("BurnFrom() as operator error = %v", err)
+	}
+	if balance := node.MultiTokenBalance(token, 1, ownerAddress); balance != 50 {
+		t.Fatalf("owner balance = %d, want 50", balance)
+	}
+
+	if _, err := owner.SetApprovalForAll(ctx, testBlockchainId, token, operatorAddress, false); err != nil {
+		t.Fatalf("SetApprovalForAll(false) error = %v", err)
+	}
+	if approved, err := owner.IsApprovedForAll(ctx, testBlockchainId, token, ownerAddress, operatorAddress); err != nil || approved {
+		t.Fatalf("IsApprovedForAll() after revoking = %v, %v", approved, err)
+	}
+	if _, err := operator.OperatorTransfer(ctx, testBlockchainId, token, ownerAddress, recipient, []uint64{1}, amounts(1)); !errors.As(err, &notApproved) {
+		t.Fatalf("OperatorTransfer() after revoking error = %v", err)
+	}
+	// The node enforces the approval too when the check is bypassed
+	tx, err := trySubmitToken(operator, transaction.TRANSFER_MULTI_TOKEN, transaction.TransferTokenPayload{TokenAddress: token, From: ownerAddress, To: recipient, TokenId: 1, Amount: transaction.NewAmount(1)})
+	if err != nil || tx.Output != transaction.TX_REJECTED_BY_UNAUTHORIZED.String() {
+		t.Fatalf("unapproved transfer = %s, %v", tx.Output, err)
+	}
+
+	invalid := &transaction.ErrInvalidTokenPayload{}
+	if _, err := owner.SetApprovalForAll(ctx, testBlockchainId, token, ownerAddress, true); !errors.As(err, &invalid) {
+		t.Fatalf("SetApprovalForAll() of the own wallet error = %v", err)
+	}
+	if _, err := operator.OperatorTransfer(ctx, testBlockchainId, token, ownerAddress, recipient, []uint64{1, 2}, amounts(1)); !errors.As(err, &invalid) {
+		t.Fatalf("OperatorTransfer() with mismatched amounts error = %v", err)
+	}
+}
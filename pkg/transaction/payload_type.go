@@ -0,0 +1,118 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// CommitmentMode selects the signature commitment of a payload type
+type CommitmentMode int
+
+const (
+	// COMMITMENT_BOUND hashes the payload root with the transaction fields, payloads are limited to
+	// MAX_BOUND_PAYLOAD_SIZE bytes
+	COMMITMENT_BOUND CommitmentMode = iota
+	// COMMITMENT_UNBOUND signs the plain payload root, like contract deployments, and has no size limit
+	COMMITMENT_UNBOUND
+)
+
+func (m CommitmentMode) String() string {
+	switch m {
+	case COMMITMENT_BOUND:
+		return "bound"
+	case COMMITMENT_UNBOUND:
+		return "unbound"
+	default:
+		return "unknown"
+	}
+}
+
+// PayloadType is an application defined payload type. Registered types are signed, validated and
+// decoded like the built in ULTransactionType ones, the node must know them too.
+type PayloadType struct {
+	Name       string
+	Commitment CommitmentMode
+	// Rules are checked by GenerateTransaction, see InputRules
+	Rules InputRules
+	// Decode expands payloads for DecodePayload and DecodeTransaction, nil decodes them as generic JSON
+	Decode func(payload string) (any, error)
+}
+
+// NewPayloadType defines a payload type whose payloads are JSON objects of type T. The payload is
+// required, validated with JsonSchema and decoded into a *T.
+func NewPayloadType[T any](name string, commitment CommitmentMode) PayloadType {
+	return PayloadType{
+		Name:       name,
+		Commitment: commitment,
+		Rules: InputRules{
+			Required:  []InputField{INPUT_FIELD_PAYLOAD},
+			Addresses: []InputField{INPUT_FIELD_FROM},
+			Schema:    JsonSchema[T](),
+		},
+		Decode: func(payload string) (any, error) {
+			target := new(T)
+			decoder := json.NewDecoder(strings.NewReader(payload))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(target); err != nil {
+				return payload, fmt.Errorf("invalid %s payload: %w", name, err)
+			}
+			return target, nil
+		},
+	}
+}
+
+var payloadTypes = struct {
+	sync.RWMutex
+	types map[string]PayloadType
+}{types: map[string]PayloadType{}}
+
+// RegisterPayloadType makes a payload type usable by GenerateTransaction and DecodeTransaction.
+// Names of the built in types and names registered before, with RegisterInputRules too, are refused.
+func RegisterPayloadType(payloadType PayloadType) error {
+	if _, err := ParseTransactionType(payloadType.Name); err == nil {
+		return fmt.Errorf("%s is a built in payload type", payloadType.Name)
+	}
+	if payloadType.Commitment != COMMITMENT_BOUND && payloadType.Commitment != COMMITMENT_UNBOUND {
+		return fmt.Errorf("payload type %s has an unknown commitment mode %d", payloadType.Name, payloadType.Commitment)
+	}
+	payloadTypes.Lock()
+	defer payloadTypes.Unlock()
+	if err := RegisterInputRules(payloadType.Name, payloadType.Rules); err != nil {
+		return err
+	}
+	payloadTypes.types[payloadType.Name] = payloadType
+	return nil
+}
+
+// GetPayloadType returns a registered payload type, false for built in and unknown ones
+func GetPayloadType(name string) (PayloadType, bool) {
+	payloadTypes.RLock()
+	defer payloadTypes.RUnlock()
+	payloadType, ok := payloadTypes.types[name]
+	return payloadType, ok
+}
+
+// PayloadTypes returns the names of the registered payload types
+func PayloadTypes() []string {
+	payloadTypes.RLock()
+	defer payloadTypes.RUnlock()
+	names := make([]string, 0, len(payloadTypes.types))
+	for name := range payloadTypes.types {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// checkPayloadType fails unless name is a built in or a registered payload type
+func checkPayloadType(name string) error {
+	if _, err := ParseTransactionType(name); err != nil {
+		if _, ok := GetPayloadType(name); !ok {
+			return err
+		}
+	}
+	return nil
+}
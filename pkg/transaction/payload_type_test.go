@@ -0,0 +1,76 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+type inspectionPayload struct {
+	Site   string `json:"site"`
+	Passed bool   `json:"passed"`
+}
+
+func (p inspectionPayload) Validate() error {
+	if p.Site == "" {
+		return fmt.Errorf("the site is missing")
+	}
+	return nil
+}
+
+func TestRegisterPayloadType(t *testing.T) {
+	inspection := transaction.NewPayloadType[inspectionPayload]("TEST_INSPECTION", transaction.COMMITMENT_BOUND)
+	if err := transaction.RegisterPayloadType(inspection); err != nil {
+		t.Fatalf("RegisterPayloadType() error = %v", err)
+	}
+	archive := transaction.NewPayloadType[map[string]string]("TEST_ARCHIVE", transaction.COMMITMENT_UNBOUND)
+	if err := transaction.RegisterPayloadType(archive); err != nil {
+		t.Fatalf("RegisterPayloadType() error = %v", err)
+	}
+	if err := transaction.RegisterPayloadType(inspection); err == nil {
+		t.Fatal("RegisterPayloadType() registered a payload type twice")
+	}
+	if err := transaction.RegisterPayloadType(transaction.NewPayloadType[inspectionPayload]("data", transaction.COMMITMENT_BOUND)); err == nil {
+		t.Fatal("RegisterPayloadType() replaced a built in payload type")
+	}
+	if transaction.MaxPayloadSize("TEST_ARCHIVE") != -1 || transaction.MaxPayloadSize("TEST_INSPECTION") != transaction.MAX_BOUND_PAYLOAD_SIZE {
+		t.Fatal("MaxPayloadSize() ignores the commitment mode of registered types")
+	}
+
+	_, session := newMockSession(t)
+	w := session.GetWallet()
+	publicKeyHex := w.GetKey().GetPublicKeyHex(false)
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, Payload: `{"passed":true}`, PayloadType: "TEST_INSPECTION"})
+	var rule *transaction.ErrInputRule
+	if !errors.As(err, &rule) {
+		t.Fatalf("GenerateTransaction() of an invalid payload error = %v, want ErrInputRule", err)
+	}
+
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, Payload: `{"site":"plant 4","passed":true}`, PayloadType: "TEST_INSPECTION"})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	decoded := transaction.DecodeTransaction(tx, publicKeyHex)
+	if !decoded.Commitment.Valid || !decoded.Signature.Valid || len(decoded.Problems) != 0 {
+		t.Fatalf("DecodeTransaction() = %+v", decoded)
+	}
+	if payload, ok := decoded.Payload.(*inspectionPayload); !ok || payload.Site != "plant 4" || !payload.Passed {
+		t.Fatalf("Payload = %#v", decoded.Payload)
+	}
+
+	documents, _ := json.Marshal(map[string]string{"contents": strings.Repeat("a", 2*transaction.MAX_BOUND_PAYLOAD_SIZE)})
+	tx, err = session.GenerateTransaction(transaction.ULTransactionInput{BlockchainId: testBlockchainId, Payload: string(documents), PayloadType: "TEST_ARCHIVE"})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() of an unbound payload error = %v", err)
+	}
+	if decoded := transaction.DecodeTransaction(tx, publicKeyHex); !decoded.Commitment.Valid || !decoded.Signature.Valid {
+		t.Fatalf("DecodeTransaction() = %+v, %+v", decoded.Commitment, decoded.Signature)
+	}
+	if _, err := transaction.DecodePayload("TEST_INSPECTION", `{"site":"plant 4","inspector":"unknown field"}`); err == nil {
+		t.Fatal("DecodePayload() accepted an unknown field")
+	}
+}
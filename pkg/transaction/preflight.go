@@ -0,0 +1,378 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// PreflightCheck names a node side check the SDK can simulate before submitting
+type PreflightCheck string
+
+const (
+	PREFLIGHT_SENDER_EXISTS  PreflightCheck = "sender_exists"
+	PREFLIGHT_SENDER_ENABLED PreflightCheck = "sender_enabled"
+	PREFLIGHT_AUTH_GROUP     PreflightCheck = "auth_group"
+	PREFLIGHT_KEY_TYPE       PreflightCheck = "key_type"
+	PREFLIGHT_DUPLICATE      PreflightCheck = "duplicate"
+)
+
+// Output returns the rejection the node answers with when the check fails
+func (c PreflightCheck) Output() UL_TransactionOutput {
+	switch c {
+	case PREFLIGHT_SENDER_EXISTS:
+		return TX_REJECTED_BY_UNEXISTING
+	case PREFLIGHT_SENDER_ENABLED:
+		return TX_REJECTED_BY_DISABLED
+	case PREFLIGHT_AUTH_GROUP:
+		return TX_REJECTED_BY_UNAUTHORIZED
+	case PREFLIGHT_KEY_TYPE:
+		return TX_REJECTED_BY_INVALID_KEY_TYPE
+	case PREFLIGHT_DUPLICATE:
+		return TX_REJECTED_BY_DUPLICATE
+	default:
+		return INVALID_TX_OUTPUT
+	}
+}
+
+// AuthRequirement is the permission of an auth group a sender needs for a payload type
+type AuthRequirement struct {
+	Group string `json:"group"`
+	// Permission is one of create, read, update or delete
+	Permission string `json:"permission"`
+}
+
+// Allows reports whether groups grant the requirement
+func (r AuthRequirement) Allows(groups map[string]wallet.UL_AuthPermission) bool {
+	permission, ok := groups[r.Group]
+	if !ok {
+		return false
+	}
+	switch r.Permission {
+	case "create":
+		return permission.Create
+	case "read":
+		return permission.Read
+	case "update":
+		return permission.Update
+	case "delete":
+		return permission.Delete
+	default:
+		return false
+	}
+}
+
+// PreflightRules are the checks a node version is known to run, they are plain data so rules for
+// newer nodes can be loaded without upgrading the SDK, see ParsePreflightRules
+type PreflightRules struct {
+	// MinNodeVersion is the first node version the rules apply to
+	MinNodeVersion string           `json:"minNodeVersion"`
+	Checks         []PreflightCheck `json:"checks"`
+	// KeyTypes the node accepts for senders, empty accepts every key type
+	KeyTypes []crypto.KeyType `json:"keyTypes"`
+	// AuthGroups maps payload types to the permission the sender needs, unlisted types need none
+	AuthGroups map[string]AuthRequirement `json:"authGroups"`
+	// ExemptRoots skips the auth group check for root wallets, which have no parent
+	ExemptRoots bool `json:"exemptRoots"`
+}
+
+// DEFAULT_PREFLIGHT_RULES lists the checks of the node versions the SDK knows about, oldest first
+var DEFAULT_PREFLIGHT_RULES = []PreflightRules{
+	{
+		MinNodeVersion: "2.0.0",
+		Checks:         []PreflightCheck{PREFLIGHT_SENDER_EXISTS, PREFLIGHT_SENDER_ENABLED, PREFLIGHT_AUTH_GROUP, PREFLIGHT_KEY_TYPE, PREFLIGHT_DUPLICATE},
+		KeyTypes:       []crypto.KeyType{crypto.KeyTypeSecp256k1, crypto.KeyTypeED25519, crypto.KeyTypeMlDSA87},
+		AuthGroups: map[string]AuthRequirement{
+			TX_DATA.String():          {Group: "data", Permission: "create"},
+			TX_CREATE_WALLET.String(): {Group: wallet.WALLET_GROUP_NAME, Permission: "create"},
+			TX_ALTER_WALLET.String():  {Group: wallet.WALLET_GROUP_NAME, Permission: "update"},
+		},
+		ExemptRoots: true,
+	},
+}
+
+// ParsePreflightRules reads a JSON array of rules and checks they are well formed
+func ParsePreflightRules(data []byte) ([]PreflightRules, error) {
+	rules := []PreflightRules{}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid preflight rules: %w", err)
+	}
+	for _, set := range rules {
+		if _, ok := parseVersion(set.MinNodeVersion); !ok {
+			return nil, fmt.Errorf("invalid preflight rules, %q is not a node version", set.MinNodeVersion)
+		}
+		for _, check := range set.Checks {
+			if check.Output() == INVALID_TX_OUTPUT {
+				return nil, fmt.Errorf("invalid preflight rules, unknown check %q", check)
+			}
+		}
+		for payloadType, requirement := range set.AuthGroups {
+			if err := checkPayloadType(payloadType); err != nil {
+				return nil, fmt.Errorf("invalid preflight rules, %w", err)
+			}
+			if !slices.Contains([]string{"create", "read", "update", "delete"}, requirement.Permission) {
+				return nil, fmt.Errorf("invalid preflight rules, unknown permission %q", requirement.Permission)
+			}
+		}
+	}
+	return rules, nil
+}
+
+// SelectPreflightRules returns the newest rules applying to nodeVersion. Versions that do not parse,
+// like development builds, get the newest rules and versions older than every set get the oldest.
+func SelectPreflightRules(rules []PreflightRules, nodeVersion string) PreflightRules {
+	if len(rules) == 0 {
+		return PreflightRules{}
+	}
+	sorted := slices.Clone(rules)
+	slices.SortFunc(sorted, func(a, b PreflightRules) int { return compareVersions(a.MinNodeVersion, b.MinNodeVersion) })
+	version, ok := parseVersion(nodeVersion)
+	if !ok {
+		return sorted[len(sorted)-1]
+	}
+	selected := sorted[0]
+	for _, set := range sorted {
+		from, _ := parseVersion(set.MinNodeVersion)
+		if slices.Compare(from, version) <= 0 {
+			selected = set
+		}
+	}
+	return selected
+}
+
+// parseVersion reads a dotted version, a leading v and a pre-release or build suffix are ignored
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "-")
+	version, _, _ = strings.Cut(version, "+")
+	if version == "" {
+		return nil, false
+	}
+	parts := []int{}
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	for len(parts) < 3 {
+		parts = append(parts, 0)
+	}
+	return parts, true
+}
+
+func compareVersions(a string, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	return slices.Compare(va, vb)
+}
+
+// PreflightIssue is a rejection the node is expected to answer with
+type PreflightIssue struct {
+	Check  PreflightCheck       `json:"check"`
+	Output UL_TransactionOutput `json:"output"`
+	Detail string               `json:"detail"`
+}
+
+// PreflightResult lists the issues found for Input, which is filled in as GenerateTransaction would
+type PreflightResult struct {
+	Input       ULTransactionInput `json:"input"`
+	NodeVersion string             `json:"nodeVersion"`
+	Issues      []PreflightIssue   `json:"issues"`
+}
+
+func (r PreflightResult) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Output is the expected output of the transaction, TX_SUCCESS unless an issue was found
+func (r PreflightResult) Output() UL_TransactionOutput {
+	if len(r.Issues) == 0 {
+		return TX_SUCCESS
+	}
+	return r.Issues[0].Output
+}
+
+// ErrPreflight is returned by Preflighter.Submit when the transaction would be rejected
+type ErrPreflight struct {
+	Result PreflightResult
+}
+
+func (e *ErrPreflight) Error() string {
+	details := make([]string, 0, len(e.Result.Issues))
+	for _, issue := range e.Result.Issues {
+		details = append(details, issue.Detail)
+	}
+	return fmt.Sprintf("%s transaction would be %s: %s", e.Result.Input.PayloadType, e.Result.Output(), strings.Join(details, "; "))
+}
+
+func (e *ErrPreflight) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Preflighter simulates the node side checks of the rules matching the node version, so clients can
+// map a transaction to its rejection before submitting it. Only the checks the SDK knows about are
+// simulated, a clean result does not guarantee the node accepts the transaction.
+type Preflighter struct {
+	session *UL_TransactionSession
+	rules   PreflightRules
+	version string
+
+	mu sync.Mutex
+	// submitted holds the identities of transactions submitted through Submit, by expiry
+	submitted map[string]time.Time
+}
+
+// NewPreflighter reads the node version and selects the matching rules, nil rules uses
+// DEFAULT_PREFLIGHT_RULES
+func NewPreflighter(ctx context.Context, session *UL_TransactionSession, rules []PreflightRules) (*Preflighter, error) {
+	if rules == nil {
+		rules = DEFAULT_PREFLIGHT_RULES
+	}
+	info := healthInfo{}
+	if err := session.getJson(ctx, "/health", &info); err != nil {
+		return nil, fmt.Errorf("unable to read the node version: %w", err)
+	}
+	return &Preflighter{
+		session:   session,
+		rules:     SelectPreflightRules(rules, info.Version),
+		version:   info.Version,
+		submitted: make(map[string]time.Time),
+	}, nil
+}
+
+// Rules returns the rules selected for the node
+func (p *Preflighter) Rules() PreflightRules {
+	return p.rules
+}
+
+// Check simulates the checks of the rules on input as the session would sign it now
+func (p *Preflighter) Check(ctx context.Context, input ULTransactionInput) (PreflightResult, error) {
+	input, err := p.prepare(input, time.Now().UTC())
+	if err != nil {
+		return PreflightResult{}, err
+	}
+	result := PreflightResult{Input: input, NodeVersion: p.version, Issues: []PreflightIssue{}}
+	fail := func(check PreflightCheck, format string, args ...any) {
+		result.Issues = append(result.Issues, PreflightIssue{Check: check, Output: check.Output(), Detail: fmt.Sprintf(format, args...)})
+	}
+	enabled := func(check PreflightCheck) bool { return slices.Contains(p.rules.Checks, check) }
+
+	if enabled(PREFLIGHT_KEY_TYPE) && len(p.rules.KeyTypes) > 0 && !slices.Contains(p.rules.KeyTypes, input.KeyType) {
+		fail(PREFLIGHT_KEY_TYPE, "node %s does not accept %s keys", p.version, input.KeyType)
+	}
+	if enabled(PREFLIGHT_DUPLICATE) && p.isSubmitted(input) {
+		fail(PREFLIGHT_DUPLICATE, "%s already submitted payload root %s this second", input.From, input.PayloadRoot)
+	}
+
+	// Root wallets are registered by themselves, they have no sender to look up
+	if input.PayloadType == TX_CREATE_WALLET.String() && input.From == "" {
+		return result, nil
+	}
+	needsSender := enabled(PREFLIGHT_SENDER_EXISTS) || enabled(PREFLIGHT_SENDER_ENABLED) || enabled(PREFLIGHT_AUTH_GROUP) || enabled(PREFLIGHT_KEY_TYPE)
+	if !needsSender {
+		return result, nil
+	}
+	sender, found, err := p.findWallet(ctx, input.BlockchainId, input.From)
+	if err != nil {
+		return PreflightResult{}, err
+	}
+	if !found {
+		if enabled(PREFLIGHT_SENDER_EXISTS) {
+			fail(PREFLIGHT_SENDER_EXISTS, "wallet %s is not registered on %s", input.From, input.BlockchainId)
+		}
+		return result, nil
+	}
+	state := WalletState{Address: input.From, Enabled: sender.Enabled, Parent: sender.Parent, AuthGroups: sender.AuthGroups}
+	result.Issues = append(result.Issues, p.rules.Can(state, input.PayloadType).Issues...)
+	// The signer of a wallet creation or of a delegated transaction is not the sender's own key
+	signedBySender := input.PayloadType != TX_CREATE_WALLET.String() && input.DelegationId == ""
+	if enabled(PREFLIGHT_KEY_TYPE) && signedBySender && sender.KeyType != input.KeyType {
+		fail(PREFLIGHT_KEY_TYPE, "wallet %s is registered with a %s key, not %s", input.From, sender.KeyType, input.KeyType)
+	}
+	return result, nil
+}
+
+// Submit checks input and submits it only when no issue was found, otherwise it fails with ErrPreflight
+func (p *Preflighter) Submit(ctx context.Context, input ULTransactionInput) (ULTransaction, error) {
+	result, err := p.Check(ctx, input)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if !result.OK() {
+		return ULTransaction{}, &ErrPreflight{Result: result}
+	}
+	tx, err := p.session.generateTransaction(ctx, input, "")
+	if err != nil {
+		return tx, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for identity, expiry := range p.submitted {
+		if now.After(expiry) {
+			delete(p.submitted, identity)
+		}
+	}
+	p.submitted[submissionIdentity(tx.ULTransactionInput)] = tx.SenderTimestamp.Add(time.Second)
+	return tx, nil
+}
+
+// prepare fills in the fields GenerateTransaction sets before signing
+func (p *Preflighter) prepare(input ULTransactionInput, now time.Time) (ULTransactionInput, error) {
+	session := p.session
+	if err := session.wallet.CheckKey(); err != nil {
+		return input, err
+	}
+	if input.PayloadType != TX_CREATE_WALLET.String() {
+		input.From = session.wallet.Address
+	}
+	input.KeyType = session.wallet.GetKey().GetType()
+	if session.delegation != nil {
+		input.From = session.delegation.Delegation.Delegator
+		input.DelegationId = session.delegation.Delegation.Id()
+	}
+	input.Suggestor = session.suggestor
+	input.SenderTimestamp = now.Truncate(time.Second)
+	_, payloadRoot, err := input.SigningCommitment()
+	if err != nil {
+		return input, err
+	}
+	input.PayloadRoot = payloadRoot
+	return input, nil
+}
+
+// submissionIdentity is what the node deduplicates transactions on: author, payload root and second
+func submissionIdentity(input ULTransactionInput) string {
+	return fmt.Sprintf("%s|%s|%d", input.From, strings.ToLower(input.PayloadRoot), input.SenderTimestamp.Unix())
+}
+
+func (p *Preflighter) isSubmitted(input ULTransactionInput) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.submitted[submissionIdentity(input)]
+	return ok
+}
+
+func (p *Preflighter) findWallet(ctx context.Context, blockchainId string, address string) (ULWalletInfo, bool, error) {
+	it := p.session.ListWallets(blockchainId, "", ListOptions{})
+	for info := range it.All(ctx) {
+		if strings.EqualFold(info.Address, address) {
+			return info, true, nil
+		}
+	}
+	if err := it.Err(); err != nil {
+		return ULWalletInfo{}, false, fmt.Errorf("unable to look up wallet %s: %w", address, err)
+	}
+	return ULWalletInfo{}, false, nil
+}
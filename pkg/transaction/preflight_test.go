@@ -0,0 +1,103 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestPreflight(t *testing.T) {
+	node, admin := newMockSession(t)
+	ctx := context.Background()
+	preflight := func(session *transaction.UL_TransactionSession, payload string) transaction.PreflightResult {
+		t.Helper()
+		p, err := transaction.NewPreflighter(ctx, session, nil)
+		if err != nil {
+			t.Fatalf("NewPreflighter() error = %v", err)
+		}
+		result, err := p.Check(ctx, transaction.ULTransactionInput{
+			BlockchainId: testBlockchainId,
+			To:           session.GetWallet().Address,
+			Payload:      payload,
+			PayloadType:  transaction.TX_DATA.String(),
+		})
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		return result
+	}
+
+	if result := preflight(admin, "unregistered"); result.Output() != transaction.TX_REJECTED_BY_UNEXISTING {
+		t.Fatalf("Check() before registering = %+v", result.Issues)
+	}
+	registerSigner(t, admin)
+	// Root wallets are exempt from auth groups
+	if result := preflight(admin, "root"); !result.OK() || result.Input.From != admin.GetWallet().Address || result.Input.PayloadRoot == "" {
+		t.Fatalf("Check() of a root wallet = %+v", result)
+	}
+
+	child, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, admin.GetWallet().Address, map[string]wallet.UL_AuthPermission{"data": {Read: true}}, wallet.DefaultEntropy)
+	childSession, _ := transaction.NewUL_TransactionSession(node.URL(), child)
+	register, _ := transaction.RegisterWalletInput(testBlockchainId, child)
+	if _, err := childSession.GenerateTransaction(register); err != nil {
+		t.Fatalf("CREATE_WALLET error = %v", err)
+	}
+	if result := preflight(&childSession, "read only"); result.Output() != transaction.TX_REJECTED_BY_UNAUTHORIZED {
+		t.Fatalf("Check() without the data create permission = %+v", result.Issues)
+	}
+	retire, _ := transaction.RetireWalletInput(testBlockchainId, child)
+	if _, err := admin.GenerateTransaction(retire); err != nil {
+		t.Fatalf("ALTER_WALLET error = %v", err)
+	}
+	if result := preflight(&childSession, "disabled"); result.Output() != transaction.TX_REJECTED_BY_DISABLED || len(result.Issues) != 2 {
+		t.Fatalf("Check() of a disabled wallet = %+v", result.Issues)
+	}
+
+	bls, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeBLS12377, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	blsSession, _ := transaction.NewUL_TransactionSession(node.URL(), bls)
+	if result := preflight(&blsSession, "bls"); result.Output() != transaction.TX_REJECTED_BY_INVALID_KEY_TYPE {
+		t.Fatalf("Check() of a BLS12-377 sender = %+v", result.Issues)
+	}
+
+	// A second submission in the same second is a duplicate, start at the beginning of one
+	p, _ := transaction.NewPreflighter(ctx, admin, nil)
+	input := transaction.ULTransactionInput{BlockchainId: testBlockchainId, To: admin.GetWallet().Address, Payload: "twice", PayloadType: transaction.TX_DATA.String()}
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	if _, err := p.Submit(ctx, input); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	preflightErr := &transaction.ErrPreflight{}
+	if _, err := p.Submit(ctx, input); !errors.As(err, &preflightErr) || preflightErr.Result.Output() != transaction.TX_REJECTED_BY_DUPLICATE {
+		t.Fatalf("Submit() of a duplicate error = %v", err)
+	}
+}
+
+func TestSelectPreflightRules(t *testing.T) {
+	legacy, err := transaction.ParsePreflightRules([]byte(`[{"minNodeVersion": "1.0.0", "checks": ["sender_exists"]}]`))
+	if err != nil {
+		t.Fatalf("ParsePreflightRules() error = %v", err)
+	}
+	rules := append(legacy, transaction.DEFAULT_PREFLIGHT_RULES...)
+	for version, want := range map[string]string{"1.4.2": "1.0.0", "v2.3.0-rc1": "2.0.0", "0.9": "1.0.0", "dev": "2.0.0"} {
+		if got := transaction.SelectPreflightRules(rules, version).MinNodeVersion; got != want {
+			t.Errorf("SelectPreflightRules(%q) = %s, want %s", version, got, want)
+		}
+	}
+
+	data, _ := json.Marshal(transaction.DEFAULT_PREFLIGHT_RULES)
+	if _, err := transaction.ParsePreflightRules(data); err != nil {
+		t.Fatalf("ParsePreflightRules() of the defaults error = %v", err)
+	}
+	if _, err := transaction.ParsePreflightRules([]byte(`[{"minNodeVersion": "2.0.0", "checks": ["balance"]}]`)); err == nil {
+		t.Fatal("ParsePreflightRules() accepted an unknown check")
+	}
+}
@@ -0,0 +1,321 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// Priority is the lane a request waits in on a PriorityScheduler
+type Priority string
+
+const (
+	// PRIORITY_HIGH is for interactive requests a user is waiting on
+	PRIORITY_HIGH Priority = "high"
+	// PRIORITY_NORMAL is the lane of requests without a priority
+	PRIORITY_NORMAL Priority = "normal"
+	// PRIORITY_BULK is for batches such as airdrops, served once the other lanes are idle
+	PRIORITY_BULK Priority = "bulk"
+)
+
+// PRIORITIES lists the lanes from the first served to the last
+var PRIORITIES = []Priority{PRIORITY_HIGH, PRIORITY_NORMAL, PRIORITY_BULK}
+
+// ErrUnknownPriority is returned for a priority that is not one of PRIORITIES
+type ErrUnknownPriority struct {
+	Priority Priority
+}
+
+func (e *ErrUnknownPriority) Error() string {
+	return fmt.Sprintf("unknown priority %q, expected one of %v", e.Priority, PRIORITIES)
+}
+
+func (e *ErrUnknownPriority) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ParsePriority returns the priority named str, empty for PRIORITY_NORMAL
+func ParsePriority(str string) (Priority, error) {
+	if str == "" {
+		return PRIORITY_NORMAL, nil
+	}
+	priority := Priority(str)
+	if priority.rank() < 0 {
+		return "", &ErrUnknownPriority{Priority: priority}
+	}
+	return priority, nil
+}
+
+func (p Priority) rank() int {
+	return slices.Index(PRIORITIES, p)
+}
+
+type priorityKey struct{}
+
+// WithPriority makes the requests sent with ctx wait in the lane of priority, whatever the lane of
+// the session
+func WithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// PriorityFrom returns the priority set on ctx by WithPriority, empty when there is none
+func PriorityFrom(ctx context.Context) Priority {
+	priority, _ := ctx.Value(priorityKey{}).(Priority)
+	return priority
+}
+
+// LaneLimit bounds the requests of one lane
+type LaneLimit struct {
+	// Rate is in requests per second, 0 leaves the lane unbounded
+	Rate  float64
+	Burst int
+}
+
+type PriorityOptions struct {
+	// Rate and Burst bound the requests of every lane together, the lanes share them by priority:
+	// a request is only sent once no request of a higher lane is waiting. Rate 0 leaves it unbounded.
+	Rate  float64
+	Burst int
+	// Lanes bound each lane on its own, e.g. so bulk requests never take the whole Rate
+	Lanes map[Priority]LaneLimit
+}
+
+// PriorityScheduler paces the requests of the sessions sharing it so interactive transactions are
+// not starved behind large batches running in the same process. Each session waits in a lane, see
+// Lane, and requests sent with a WithPriority context wait in the lane they name.
+//
+//	scheduler, _ := transaction.NewPriorityScheduler(transaction.PriorityOptions{
+//		Rate:  50,
+//		Lanes: map[transaction.Priority]transaction.LaneLimit{transaction.PRIORITY_BULK: {Rate: 40}},
+//	})
+//	airdrop.SetRateLimiter(scheduler.Lane(transaction.PRIORITY_BULK))
+//	user.SetRateLimiter(scheduler.Lane(transaction.PRIORITY_HIGH))
+type PriorityScheduler struct {
+	now func() time.Time
+
+	mu sync.Mutex
+	// shared is nil when the lanes together are unbounded
+	shared *tokenBucket
+	// lanes holds the buckets of the bounded lanes
+	lanes map[Priority]*tokenBucket
+	// queued holds the requests waiting for shared, by rank and in arrival order
+	queued [][]*priorityWaiter
+	timer  *time.Timer
+}
+
+type priorityWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewPriorityScheduler returns a scheduler for opts, the limits of unknown lanes are refused
+func NewPriorityScheduler(opts PriorityOptions) (*PriorityScheduler, error) {
+	if opts.Rate < 0 {
+		return nil, fmt.Errorf("%w: negative rate %v", utils.ErrInvalidInput, opts.Rate)
+	}
+	scheduler := &PriorityScheduler{
+		now:    time.Now,
+		lanes:  make(map[Priority]*tokenBucket),
+		queued: make([][]*priorityWaiter, len(PRIORITIES)),
+	}
+	if opts.Rate > 0 {
+		scheduler.shared = newTokenBucket(opts.Rate, opts.Burst)
+	}
+	for priority, limit := range opts.Lanes {
+		if priority.rank() < 0 {
+			return nil, &ErrUnknownPriority{Priority: priority}
+		}
+		if limit.Rate < 0 {
+			return nil, fmt.Errorf("%w: negative rate %v for the %s lane", utils.ErrInvalidInput, limit.Rate, priority)
+		}
+		if limit.Rate > 0 {
+			scheduler.lanes[priority] = newTokenBucket(limit.Rate, limit.Burst)
+		}
+	}
+	return scheduler, nil
+}
+
+// Lane returns the RateLimiter of a session waiting in the lane of priority. Requests sent with a
+// WithPriority context wait in the lane they name instead.
+func (scheduler *PriorityScheduler) Lane(priority Priority) RateLimiter {
+	return priorityLane{scheduler: scheduler, priority: priority}
+}
+
+// Wait waits in the lane named by ctx, PRIORITY_NORMAL when ctx has none
+func (scheduler *PriorityScheduler) Wait(ctx context.Context) error {
+	return scheduler.Lane(PRIORITY_NORMAL).Wait(ctx)
+}
+
+// Queued returns the number of requests of a lane waiting for their turn
+func (scheduler *PriorityScheduler) Queued(priority Priority) int {
+	rank := priority.rank()
+	if rank < 0 {
+		return 0
+	}
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	return len(scheduler.queued[rank])
+}
+
+type priorityLane struct {
+	scheduler *PriorityScheduler
+	priority  Priority
+}
+
+func (lane priorityLane) Wait(ctx context.Context) error {
+	priority := lane.priority
+	if override := PriorityFrom(ctx); override != "" {
+		priority = override
+	}
+	rank := priority.rank()
+	if rank < 0 {
+		return &ErrUnknownPriority{Priority: priority}
+	}
+	if err := lane.scheduler.waitLane(ctx, priority); err != nil {
+		return err
+	}
+	return lane.scheduler.waitShared(ctx, rank)
+}
+
+// waitLane takes a token of the lane's own bucket, sleeping for it
+func (scheduler *PriorityScheduler) waitLane(ctx context.Context, priority Priority) error {
+	scheduler.mu.Lock()
+	bucket := scheduler.lanes[priority]
+	if bucket == nil {
+		scheduler.mu.Unlock()
+		return nil
+	}
+	// Reserve the token now so concurrent requests of the lane queue behind this one
+	wait := bucket.reserve(scheduler.now())
+	scheduler.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		scheduler.mu.Lock()
+		bucket.tokens++
+		scheduler.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// waitShared takes a token of the shared bucket once no request of the same or a higher lane is
+// waiting for one
+func (scheduler *PriorityScheduler) waitShared(ctx context.Context, rank int) error {
+	scheduler.mu.Lock()
+	if scheduler.shared == nil {
+		scheduler.mu.Unlock()
+		return nil
+	}
+	ahead := false
+	for _, queued := range scheduler.queued[:rank+1] {
+		ahead = ahead || len(queued) > 0
+	}
+	if !ahead && scheduler.shared.take(scheduler.now()) {
+		scheduler.mu.Unlock()
+		return nil
+	}
+	waiter := &priorityWaiter{ready: make(chan struct{})}
+	scheduler.queued[rank] = append(scheduler.queued[rank], waiter)
+	scheduler.dispatch()
+	scheduler.mu.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+	scheduler.mu.Lock()
+	defer scheduler.mu.Unlock()
+	if waiter.granted {
+		// The token arrived along with the cancellation, the next waiter gets it
+		scheduler.shared.tokens++
+	} else {
+		scheduler.queued[rank] = slices.DeleteFunc(scheduler.queued[rank], func(w *priorityWaiter) bool { return w == waiter })
+	}
+	scheduler.dispatch()
+	return ctx.Err()
+}
+
+// dispatch hands the available shared tokens to the waiting requests, highest lane first, and
+// schedules itself for the next token while requests remain. scheduler.mu must be held.
+func (scheduler *PriorityScheduler) dispatch() {
+	now := scheduler.now()
+	remaining := false
+	for rank, queued := range scheduler.queued {
+		for len(queued) > 0 && scheduler.shared.take(now) {
+			queued[0].granted = true
+			close(queued[0].ready)
+			queued = queued[1:]
+		}
+		scheduler.queued[rank] = queued
+		remaining = remaining || len(queued) > 0
+	}
+	if !remaining || scheduler.timer != nil {
+		return
+	}
+	scheduler.timer = time.AfterFunc(scheduler.shared.untilToken(now), func() {
+		scheduler.mu.Lock()
+		defer scheduler.mu.Unlock()
+		scheduler.timer = nil
+		scheduler.dispatch()
+	})
+}
+
+// tokenBucket is refilled at rate tokens per second up to burst, callers synchronize
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst)}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+}
+
+// take takes a token when one is available
+func (b *tokenBucket) take(now time.Time) bool {
+	b.refill(now)
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// reserve takes a token and returns how long until it is actually available
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.refill(now)
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// untilToken returns how long until a token is available
+func (b *tokenBucket) untilToken(now time.Time) time.Duration {
+	b.refill(now)
+	return max(0, time.Duration((1-b.tokens)/b.rate*float64(time.Second)))
+}
@@ -0,0 +1,111 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestPriorityScheduler(t *testing.T) {
+	scheduler, err := transaction.NewPriorityScheduler(transaction.PriorityOptions{Rate: 20, Burst: 1})
+	if err != nil {
+		t.Fatalf("NewPriorityScheduler() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	bulk := scheduler.Lane(transaction.PRIORITY_BULK)
+	high := scheduler.Lane(transaction.PRIORITY_HIGH)
+	if err := bulk.Wait(ctx); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	// A batch queues up behind the spent token, then a user request arrives
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	wait := func(limiter transaction.RateLimiter, name string) {
+		defer wg.Done()
+		if err := limiter.Wait(ctx); err != nil {
+			t.Errorf("Wait() of %s error = %v", name, err)
+		}
+		mu.Lock()
+		order = append(order, name)
+		mu.Unlock()
+	}
+	for range 3 {
+		wg.Add(1)
+		go wait(bulk, "bulk")
+	}
+	for scheduler.Queued(transaction.PRIORITY_BULK) < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	wg.Add(1)
+	go wait(high, "high")
+	wg.Wait()
+
+	if len(order) != 4 || order[0] != "high" {
+		t.Fatalf("requests served in order %v, want the high one first", order)
+	}
+
+	// The context overrides the lane of the session
+	if err := scheduler.Lane("urgent").Wait(ctx); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Wait() in an unknown lane accepted")
+	}
+	if err := scheduler.Lane("urgent").Wait(transaction.WithPriority(ctx, transaction.PRIORITY_HIGH)); err != nil {
+		t.Fatalf("Wait() with a WithPriority context error = %v", err)
+	}
+}
+
+func TestPrioritySchedulerLaneLimits(t *testing.T) {
+	scheduler, err := transaction.NewPriorityScheduler(transaction.PriorityOptions{
+		Lanes: map[transaction.Priority]transaction.LaneLimit{transaction.PRIORITY_BULK: {Rate: 1, Burst: 2}},
+	})
+	if err != nil {
+		t.Fatalf("NewPriorityScheduler() error = %v", err)
+	}
+	ctx := context.Background()
+	bulk := scheduler.Lane(transaction.PRIORITY_BULK)
+	for range 2 {
+		if err := bulk.Wait(ctx); err != nil {
+			t.Fatalf("Wait() within the burst error = %v", err)
+		}
+	}
+	short, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if err := bulk.Wait(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() past the bulk rate error = %v", err)
+	}
+	// The other lanes are not bound by the bulk rate
+	for range 10 {
+		if err := scheduler.Lane(transaction.PRIORITY_NORMAL).Wait(short); err != nil {
+			t.Fatalf("Wait() in the normal lane error = %v", err)
+		}
+	}
+
+	var unknown *transaction.ErrUnknownPriority
+	_, err = transaction.NewPriorityScheduler(transaction.PriorityOptions{
+		Lanes: map[transaction.Priority]transaction.LaneLimit{"urgent": {Rate: 1}},
+	})
+	if !errors.As(err, &unknown) || !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("NewPriorityScheduler() with an unknown lane error = %v", err)
+	}
+	if priority, err := transaction.ParsePriority(""); err != nil || priority != transaction.PRIORITY_NORMAL {
+		t.Fatalf("ParsePriority(\"\") = %q, %v", priority, err)
+	}
+}
+
+func TestSessionPriority(t *testing.T) {
+	_, session := newMockSession(t)
+	scheduler, _ := transaction.NewPriorityScheduler(transaction.PriorityOptions{Rate: 1000})
+	session.SetRateLimiter(scheduler.Lane(transaction.PRIORITY_BULK))
+	submitData(t, session, "airdrop")
+
+	if _, err := session.GetTransaction(transaction.WithPriority(context.Background(), "urgent"), testBlockchainId, "unknown"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("GetTransaction() with an unknown priority error = %v", err)
+	}
+}
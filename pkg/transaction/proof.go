@@ -0,0 +1,242 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+)
+
+// Proof versions understood by ParseProof. Version 1 is the compact form
+// "<index>:<numLeaves>:<leaf hex>:<element hex>,<element hex>,..." hashed with MiMC over BN254.
+// Version 2 is base64 encoded JSON naming its scheme, an empty version is read as version 1.
+const (
+	PROOF_VERSION_1 = "1"
+	PROOF_VERSION_2 = "2"
+)
+
+// ProofScheme names the hash of the Merkle tree a proof belongs to
+type ProofScheme string
+
+const (
+	PROOF_SCHEME_MIMC_BN254   ProofScheme = "merkle-mimc-bn254"
+	PROOF_SCHEME_MIMC_BW6_761 ProofScheme = "merkle-mimc-bw6-761"
+	PROOF_SCHEME_SHA256       ProofScheme = "merkle-sha256"
+)
+
+// ErrInvalidProof is returned for proofs that cannot be parsed, encoded or verified
+type ErrInvalidProof struct {
+	Msg string
+}
+
+func (e *ErrInvalidProof) Error() string {
+	return fmt.Sprintf("invalid proof, %s", e.Msg)
+}
+
+func (e *ErrInvalidProof) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// ParsedProof is the structured form of ULTransactionOutput.Proof. Elements are the sibling hashes
+// from the leaf up to the root, Indices the positions of the proven leaves, a Merkle proof covers one.
+type ParsedProof struct {
+	Version   string      `json:"version"`
+	Scheme    ProofScheme `json:"scheme"`
+	Leaf      []byte      `json:"leaf"`
+	Elements  [][]byte    `json:"elements"`
+	Indices   []uint64    `json:"indices"`
+	NumLeaves uint64      `json:"numLeaves"`
+}
+
+// proofV2 is the JSON of a version 2 proof, bytes are hex encoded
+type proofV2 struct {
+	Scheme    ProofScheme `json:"scheme"`
+	Leaf      string      `json:"leaf"`
+	Elements  []string    `json:"elements"`
+	Indices   []uint64    `json:"indices"`
+	NumLeaves uint64      `json:"numLeaves"`
+}
+
+// ParseProof parses proof as encoded by version
+func ParseProof(version string, proof string) (ParsedProof, error) {
+	if proof == "" {
+		return ParsedProof{}, &ErrInvalidProof{Msg: "the transaction has no proof"}
+	}
+	var parsed ParsedProof
+	var err error
+	switch version {
+	case "", PROOF_VERSION_1:
+		parsed, err = parseProofV1(proof)
+	case PROOF_VERSION_2:
+		parsed, err = parseProofV2(proof)
+	default:
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("unknown proof version %q", version)}
+	}
+	if err != nil {
+		return ParsedProof{}, err
+	}
+	return parsed, parsed.Validate()
+}
+
+// ParseProof parses the proof of the transaction according to its ProofVersion
+func (output ULTransactionOutput) ParseProof() (ParsedProof, error) {
+	return ParseProof(output.ProofVersion, output.Proof)
+}
+
+func parseProofV1(proof string) (ParsedProof, error) {
+	fields := strings.Split(proof, ":")
+	if len(fields) != 4 {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("expected 4 fields, got %d", len(fields))}
+	}
+	index, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid index %q", fields[0])}
+	}
+	numLeaves, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid leaf count %q", fields[1])}
+	}
+	leaf, err := hex.DecodeString(fields[2])
+	if err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid leaf: %v", err)}
+	}
+	elements := [][]byte{}
+	if fields[3] != "" {
+		for i, element := range strings.Split(fields[3], ",") {
+			decoded, err := hex.DecodeString(element)
+			if err != nil {
+				return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid element %d: %v", i, err)}
+			}
+			elements = append(elements, decoded)
+		}
+	}
+	return ParsedProof{
+		Version:   PROOF_VERSION_1,
+		Scheme:    PROOF_SCHEME_MIMC_BN254,
+		Leaf:      leaf,
+		Elements:  elements,
+		Indices:   []uint64{index},
+		NumLeaves: numLeaves,
+	}, nil
+}
+
+func parseProofV2(proof string) (ParsedProof, error) {
+	data, err := base64.StdEncoding.DecodeString(proof)
+	if err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("not base64: %v", err)}
+	}
+	wire := proofV2{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&wire); err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("not a version 2 proof: %v", err)}
+	}
+	leaf, err := hex.DecodeString(wire.Leaf)
+	if err != nil {
+		return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid leaf: %v", err)}
+	}
+	elements := make([][]byte, len(wire.Elements))
+	for i, element := range wire.Elements {
+		if elements[i], err = hex.DecodeString(element); err != nil {
+			return ParsedProof{}, &ErrInvalidProof{Msg: fmt.Sprintf("invalid element %d: %v", i, err)}
+		}
+	}
+	return ParsedProof{
+		Version:   PROOF_VERSION_2,
+		Scheme:    wire.Scheme,
+		Leaf:      leaf,
+		Elements:  elements,
+		Indices:   wire.Indices,
+		NumLeaves: wire.NumLeaves,
+	}, nil
+}
+
+// Validate checks the proof is well formed, it does not check it against a root
+func (p ParsedProof) Validate() error {
+	if _, err := p.Scheme.hasher(); err != nil {
+		return err
+	}
+	if len(p.Indices) != 1 {
+		return &ErrInvalidProof{Msg: fmt.Sprintf("a Merkle proof covers one leaf, got %d indices", len(p.Indices))}
+	}
+	if p.NumLeaves == 0 || p.Indices[0] >= p.NumLeaves {
+		return &ErrInvalidProof{Msg: fmt.Sprintf("leaf %d is outside a tree of %d leaves", p.Indices[0], p.NumLeaves)}
+	}
+	if len(p.Leaf) == 0 {
+		return &ErrInvalidProof{Msg: "the leaf is empty"}
+	}
+	return nil
+}
+
+// Encode serializes the proof in its Version, re-parsing the result gives back the same proof
+func (p ParsedProof) Encode() (string, error) {
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+	switch p.Version {
+	case "", PROOF_VERSION_1:
+		if p.Scheme != PROOF_SCHEME_MIMC_BN254 {
+			return "", &ErrInvalidProof{Msg: fmt.Sprintf("version 1 proofs cannot use %s", p.Scheme)}
+		}
+		elements := make([]string, len(p.Elements))
+		for i, element := range p.Elements {
+			elements[i] = hex.EncodeToString(element)
+		}
+		return fmt.Sprintf("%d:%d:%s:%s", p.Indices[0], p.NumLeaves, hex.EncodeToString(p.Leaf), strings.Join(elements, ",")), nil
+	case PROOF_VERSION_2:
+		wire := proofV2{Scheme: p.Scheme, Leaf: hex.EncodeToString(p.Leaf), Elements: make([]string, len(p.Elements)), Indices: p.Indices, NumLeaves: p.NumLeaves}
+		for i, element := range p.Elements {
+			wire.Elements[i] = hex.EncodeToString(element)
+		}
+		data, err := json.Marshal(wire)
+		if err != nil {
+			return "", err
+		}
+		return base64.StdEncoding.EncodeToString(data), nil
+	default:
+		return "", &ErrInvalidProof{Msg: fmt.Sprintf("unknown proof version %q", p.Version)}
+	}
+}
+
+// Verify checks that the proof leads from its leaf to root
+func (p ParsedProof) Verify(root []byte) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	hasher, _ := p.Scheme.hasher()
+	proofSet := append([][]byte{p.Leaf}, p.Elements...)
+	if !merkletree.VerifyProof(hasher, root, proofSet, p.Indices[0], p.NumLeaves) {
+		return &ErrInvalidProof{Msg: fmt.Sprintf("leaf %d does not lead to root %x", p.Indices[0], root)}
+	}
+	return nil
+}
+
+// ProofSchemeForKeyType returns the scheme of the payload trees of transactions signed with keyType
+func ProofSchemeForKeyType(keyType crypto.KeyType) ProofScheme {
+	if keyType == crypto.KeyTypeBLS12377 {
+		return PROOF_SCHEME_MIMC_BW6_761
+	}
+	return PROOF_SCHEME_MIMC_BN254
+}
+
+func (scheme ProofScheme) hasher() (hash.Hash, error) {
+	switch scheme {
+	case PROOF_SCHEME_MIMC_BN254:
+		return crypto.GetHasherByType(crypto.KeyTypeSecp256k1), nil
+	case PROOF_SCHEME_MIMC_BW6_761:
+		return crypto.GetHasherByType(crypto.KeyTypeBLS12377), nil
+	case PROOF_SCHEME_SHA256:
+		return sha256.New(), nil
+	default:
+		return nil, &ErrInvalidProof{Msg: fmt.Sprintf("unknown scheme %q", scheme)}
+	}
+}
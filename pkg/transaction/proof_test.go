@@ -0,0 +1,79 @@
+package transaction_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestParsedProof(t *testing.T) {
+	payload := bytes.Repeat([]byte("proof payload "), 20)
+	root, proofSet, _, numLeaves, err := transaction.GenerateMerkleTreeWithHardBound(payload, transaction.ECDSA_CURVE, transaction.CHUNK_SIZE, transaction.DEPTH, crypto.GetHasherByType(crypto.KeyTypeSecp256k1), 3)
+	if err != nil {
+		t.Fatalf("GenerateMerkleTreeWithHardBound() error = %v", err)
+	}
+	proof := transaction.ParsedProof{
+		Version:   transaction.PROOF_VERSION_1,
+		Scheme:    transaction.ProofSchemeForKeyType(crypto.KeyTypeSecp256k1),
+		Leaf:      proofSet[0],
+		Elements:  proofSet[1:],
+		Indices:   []uint64{3},
+		NumLeaves: numLeaves,
+	}
+	if err := proof.Verify(root); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+
+	for _, version := range []string{transaction.PROOF_VERSION_1, transaction.PROOF_VERSION_2} {
+		proof.Version = version
+		encoded, err := proof.Encode()
+		if err != nil {
+			t.Fatalf("Encode() version %s error = %v", version, err)
+		}
+		output := transaction.ULTransactionOutput{Proof: encoded, ProofVersion: version}
+		parsed, err := output.ParseProof()
+		if err != nil {
+			t.Fatalf("ParseProof() version %s error = %v", version, err)
+		}
+		if !reflect.DeepEqual(parsed, proof) {
+			t.Fatalf("ParseProof() version %s = %+v, want %+v", version, parsed, proof)
+		}
+		if again, _ := parsed.Encode(); again != encoded {
+			t.Fatalf("Encode() version %s did not round trip", version)
+		}
+	}
+
+	// Proofs from nodes that predate ProofVersion are read as version 1
+	proof.Version = transaction.PROOF_VERSION_1
+	encoded, _ := proof.Encode()
+	if parsed, err := transaction.ParseProof("", encoded); err != nil || parsed.Indices[0] != 3 {
+		t.Fatalf("ParseProof() = %+v, %v", parsed, err)
+	}
+
+	invalid := &transaction.ErrInvalidProof{}
+	tampered := proof
+	tampered.Indices = []uint64{4}
+	if err := tampered.Verify(root); !errors.As(err, &invalid) {
+		t.Fatalf("Verify() error = %v, want a proof of another leaf rejected", err)
+	}
+	sha := proof
+	sha.Scheme = transaction.PROOF_SCHEME_SHA256
+	if _, err := sha.Encode(); !errors.As(err, &invalid) {
+		t.Fatalf("Encode() error = %v, want version 1 limited to its scheme", err)
+	}
+	for _, bad := range []struct{ version, proof string }{
+		{"", ""},
+		{"1", "3:16:zz:"},
+		{"1", "20:16:00:"},
+		{"2", "not base64!"},
+		{"9", "3:16:00:"},
+	} {
+		if _, err := transaction.ParseProof(bad.version, bad.proof); !errors.As(err, &invalid) {
+			t.Errorf("ParseProof(%q, %q) error = %v", bad.version, bad.proof, err)
+		}
+	}
+}
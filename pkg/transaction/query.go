@@ -0,0 +1,128 @@
+package transaction
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+)
+
+// ErrBlockIntegrity is returned for blocks whose content does not match their header
+type ErrBlockIntegrity struct {
+	Height int
+	Msg    string
+}
+
+func (e *ErrBlockIntegrity) Error() string {
+	return fmt.Sprintf("block %d failed verification, %s", e.Height, e.Msg)
+}
+
+func (e *ErrBlockIntegrity) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// GetBlockHeight returns the height of the newest block the node has sealed on the chain
+func (session *UL_TransactionSession) GetBlockHeight(ctx context.Context, blockchainId string) (int, error) {
+	chain, err := session.getChainInfo(ctx, blockchainId)
+	if err != nil {
+		return 0, err
+	}
+	return chain.Height, nil
+}
+
+// GetBlock fetches the block at height as the node returns it, heights start at 1. GetBlockByHeight
+// also checks the node returned the block asked for.
+func (session *UL_TransactionSession) GetBlock(ctx context.Context, blockchainId string, height int) (ULBlock, error) {
+	block := ULBlock{}
+	if err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/blocks/%d", blockchainId, height), &block); err != nil {
+		return ULBlock{}, err
+	}
+	return block, nil
+}
+
+// SetBlockVerifier makes GetBlockByHeight, GetBlockByHash and GetLatestBlock check every block they
+// return with verify, nil disables the check and is the default. How nodes build the block Merkle
+// root is not part of their API, ULBlock.VerifyMerkleRoot only checks roots built by BlockMerkleRoot.
+func (session *UL_TransactionSession) SetBlockVerifier(verify func(ULBlock) error) {
+	session.blockVerifier = verify
+}
+
+// verifyBlock runs the verifier of the session on block
+func (session *UL_TransactionSession) verifyBlock(block ULBlock) (ULBlock, error) {
+	if session.blockVerifier == nil {
+		return block, nil
+	}
+	if err := session.blockVerifier(block); err != nil {
+		return ULBlock{}, err
+	}
+	return block, nil
+}
+
+// GetBlockByHeight fetches the block at height, checking the node returned that block and running the
+// verifier of the session, see SetBlockVerifier
+func (session *UL_TransactionSession) GetBlockByHeight(ctx context.Context, blockchainId string, height int) (ULBlock, error) {
+	block, err := session.GetBlock(ctx, blockchainId, height)
+	if err != nil {
+		return ULBlock{}, err
+	}
+	if block.Height != height {
+		return ULBlock{}, &ErrBlockIntegrity{Height: height, Msg: fmt.Sprintf("the node returned block %d", block.Height)}
+	}
+	return session.verifyBlock(block)
+}
+
+// GetBlockByHash fetches the block with the hex hash, checking it is the block asked for like
+// GetBlockByHeight
+func (session *UL_TransactionSession) GetBlockByHash(ctx context.Context, blockchainId string, hash string) (ULBlock, error) {
+	block := ULBlock{}
+	if err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/blocks/hash/%s", blockchainId, url.PathEscape(hash)), &block); err != nil {
+		return ULBlock{}, err
+	}
+	if !strings.EqualFold(block.Hash, hash) {
+		return ULBlock{}, &ErrBlockIntegrity{Height: block.Height, Msg: fmt.Sprintf("the node returned block %s for hash %s", block.Hash, hash)}
+	}
+	return session.verifyBlock(block)
+}
+
+// GetLatestBlock fetches the newest sealed block of the chain and runs the verifier of the session
+func (session *UL_TransactionSession) GetLatestBlock(ctx context.Context, blockchainId string) (ULBlock, error) {
+	block := ULBlock{}
+	if err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/blocks/latest", blockchainId), &block); err != nil {
+		return ULBlock{}, err
+	}
+	return session.verifyBlock(block)
+}
+
+// BlockMerkleRoot returns a hex Merkle root committing to the transactions of a block, in order. The
+// leaves are the transaction ids hashed with SHA-256, blocks without transactions have an empty root.
+// It is the root transactiontest.MockNode seals blocks with, nodes may build theirs differently.
+func BlockMerkleRoot(txs []ULTransaction) string {
+	if len(txs) == 0 {
+		return ""
+	}
+	tree := merkletree.New(sha256.New())
+	for _, tx := range txs {
+		tree.Push([]byte(tx.TransactionId))
+	}
+	return hex.EncodeToString(tree.Root())
+}
+
+// VerifyMerkleRoot checks that the transactions of the block are the ones its Merkle root commits to,
+// as BlockMerkleRoot builds it, and that they were all sealed in it. Pass it to SetBlockVerifier for
+// nodes known to build roots that way.
+func (block ULBlock) VerifyMerkleRoot() error {
+	if root := BlockMerkleRoot(block.Transactions); !strings.EqualFold(root, block.MerkleRoot) {
+		return &ErrBlockIntegrity{Height: block.Height, Msg: fmt.Sprintf("the transactions lead to Merkle root %q, the block holds %q", root, block.MerkleRoot)}
+	}
+	for _, tx := range block.Transactions {
+		if tx.BlockHeight != block.Height {
+			return &ErrBlockIntegrity{Height: block.Height, Msg: fmt.Sprintf("transaction %s belongs to block %d", tx.TransactionId, tx.BlockHeight)}
+		}
+	}
+	return nil
+}
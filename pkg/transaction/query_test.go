@@ -0,0 +1,96 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestBlockQueries(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	first := submitData(t, session, "first")
+	second := submitData(t, session, "second")
+
+	block, err := session.GetBlockByHeight(ctx, testBlockchainId, second.BlockHeight)
+	if err != nil {
+		t.Fatalf("GetBlockByHeight() error = %v", err)
+	}
+	if block.Height != second.BlockHeight || len(block.Transactions) != 1 || block.Transactions[0].TransactionId != second.TransactionId {
+		t.Fatalf("GetBlockByHeight() = %+v", block)
+	}
+	if block.MerkleRoot == "" || block.MerkleRoot != transaction.BlockMerkleRoot(block.Transactions) {
+		t.Fatalf("merkle root = %q", block.MerkleRoot)
+	}
+
+	byHash, err := session.GetBlockByHash(ctx, testBlockchainId, block.Hash)
+	if err != nil || byHash.Height != block.Height {
+		t.Fatalf("GetBlockByHash() = %d, %v", byHash.Height, err)
+	}
+	latest, err := session.GetLatestBlock(ctx, testBlockchainId)
+	if err != nil || latest.Hash != block.Hash {
+		t.Fatalf("GetLatestBlock() = %s, %v, want %s", latest.Hash, err, block.Hash)
+	}
+	parent, err := session.GetBlockByHash(ctx, testBlockchainId, latest.PreviousBlockHash)
+	if err != nil || parent.Height != first.BlockHeight {
+		t.Fatalf("GetBlockByHash() of the parent = %d, %v", parent.Height, err)
+	}
+
+	// Roots are only checked when a verifier is set
+	session.SetBlockVerifier(transaction.ULBlock.VerifyMerkleRoot)
+	if _, err := session.GetBlockByHash(ctx, testBlockchainId, block.Hash); err != nil {
+		t.Fatalf("GetBlockByHash() with VerifyMerkleRoot error = %v", err)
+	}
+	rejected := &transaction.ErrBlockIntegrity{Msg: "rejected"}
+	session.SetBlockVerifier(func(transaction.ULBlock) error { return rejected })
+	if _, err := session.GetLatestBlock(ctx, testBlockchainId); !errors.Is(err, rejected) {
+		t.Fatalf("GetLatestBlock() with a failing verifier error = %v", err)
+	}
+	session.SetBlockVerifier(nil)
+
+	if _, err := session.GetBlockByHash(ctx, testBlockchainId, "unknown"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GetBlockByHash() of an unknown hash error = %v", err)
+	}
+	if _, err := session.GetBlockByHeight(ctx, testBlockchainId, 99); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GetBlockByHeight() past the head error = %v", err)
+	}
+}
+
+func TestVerifyMerkleRoot(t *testing.T) {
+	txs := []transaction.ULTransaction{{}, {}, {}}
+	for i, id := range []string{"a", "b", "c"} {
+		txs[i].TransactionId = id
+		txs[i].BlockHeight = 7
+	}
+	block := transaction.ULBlock{Height: 7, Transactions: txs, MerkleRoot: transaction.BlockMerkleRoot(txs)}
+	if err := block.VerifyMerkleRoot(); err != nil {
+		t.Fatalf("VerifyMerkleRoot() error = %v", err)
+	}
+	if err := (transaction.ULBlock{Height: 8}).VerifyMerkleRoot(); err != nil {
+		t.Fatalf("VerifyMerkleRoot() of an empty block error = %v", err)
+	}
+
+	tampered := func(change func(block *transaction.ULBlock)) transaction.ULBlock {
+		copied := block
+		copied.Transactions = append([]transaction.ULTransaction(nil), block.Transactions...)
+		change(&copied)
+		return copied
+	}
+	for name, block := range map[string]transaction.ULBlock{
+		"dropped": tampered(func(block *transaction.ULBlock) { block.Transactions = block.Transactions[:2] }),
+		"reordered": tampered(func(block *transaction.ULBlock) {
+			block.Transactions[0], block.Transactions[1] = block.Transactions[1], block.Transactions[0]
+		}),
+		"replaced": tampered(func(block *transaction.ULBlock) { block.Transactions[2].TransactionId = "d" }),
+		"no root":  tampered(func(block *transaction.ULBlock) { block.MerkleRoot = "" }),
+		"moved":    tampered(func(block *transaction.ULBlock) { block.Transactions[1].BlockHeight = 6 }),
+	} {
+		var integrity *transaction.ErrBlockIntegrity
+		if err := block.VerifyMerkleRoot(); !errors.As(err, &integrity) || !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("VerifyMerkleRoot() of a %s block error = %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NODE_FEATURE_TRANSACTION_REPLACEMENT is advertised by nodes that let senders replace or cancel
+// their pending transactions
+const NODE_FEATURE_TRANSACTION_REPLACEMENT = "transaction-replacement"
+
+// CancelTransactionPayload is the payload of a CANCEL_TRANSACTION, it replaces the pending
+// transaction with one that does nothing
+type CancelTransactionPayload struct {
+	TransactionId string `json:"transactionId"`
+	Reason        string `json:"reason,omitempty"`
+}
+
+// ErrReplacementUnsupported is returned by Replace and CancelPending when the node does not
+// advertise NODE_FEATURE_TRANSACTION_REPLACEMENT
+type ErrReplacementUnsupported struct{}
+
+func (e *ErrReplacementUnsupported) Error() string {
+	return "the node does not support replacing or cancelling pending transactions"
+}
+
+func (e *ErrReplacementUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ErrTransactionDecided is returned when replacing or cancelling a transaction the node already
+// accepted or rejected, Transaction is the original as the node reports it
+type ErrTransactionDecided struct {
+	Transaction ULTransaction
+}
+
+func (e *ErrTransactionDecided) Error() string {
+	return fmt.Sprintf("transaction %s is no longer pending, its status is %s with output %s", e.Transaction.TransactionId, e.Transaction.Status, e.Transaction.Output)
+}
+
+func (e *ErrTransactionDecided) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Replace submits input in place of the pending transaction transactionId of the session's wallet,
+// e.g. to fix its payload. The original ends with the output TX_REJECTED_BY_REPLACEMENT. When the
+// node decided on the original first, before the call or while it ran, Replace fails with an
+// ErrTransactionDecided holding it.
+func (session *UL_TransactionSession) Replace(ctx context.Context, transactionId string, input ULTransactionInput) (ULTransaction, error) {
+	supported, err := session.hasFeature(ctx, NODE_FEATURE_TRANSACTION_REPLACEMENT)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if !supported {
+		return ULTransaction{}, &ErrReplacementUnsupported{}
+	}
+	if err := session.checkPending(ctx, input.BlockchainId, transactionId); err != nil {
+		return ULTransaction{}, err
+	}
+
+	tx, err := session.generateTransaction(ctx, input, transactionId)
+	if err != nil && errors.Is(err, utils.ErrRejected) {
+		// The node refuses to replace a transaction it decided on meanwhile
+		if pendingErr := session.checkPending(ctx, input.BlockchainId, transactionId); pendingErr != nil {
+			return ULTransaction{}, pendingErr
+		}
+	}
+	return tx, err
+}
+
+// CancelPending replaces the pending transaction transactionId of the session's wallet with a
+// CANCEL_TRANSACTION, see Replace
+func (session *UL_TransactionSession) CancelPending(ctx context.Context, blockchainId string, transactionId string) (ULTransaction, error) {
+	payload, err := json.Marshal(CancelTransactionPayload{TransactionId: transactionId})
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	return session.Replace(ctx, transactionId, ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           session.wallet.Address,
+		Payload:      string(payload),
+		PayloadType:  CANCEL_TRANSACTION.String(),
+	})
+}
+
+// checkPending fails with ErrTransactionDecided unless the node still holds the transaction pending
+func (session *UL_TransactionSession) checkPending(ctx context.Context, blockchainId string, transactionId string) error {
+	original, err := session.GetTransaction(ctx, blockchainId, transactionId)
+	if err != nil {
+		return err
+	}
+	if done, _ := transactionOutcome(original); done || original.Status != TX_SUBMITTED.String() {
+		return &ErrTransactionDecided{Transaction: original}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestReplace(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	node.HoldTransactions(true)
+	original := submitData(t, session, "typo")
+	fixed := transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "fixed",
+		PayloadType:  transaction.TX_DATA.String(),
+	}
+
+	if _, err := session.Replace(ctx, original.TransactionId, fixed); !errors.Is(err, utils.ErrUnsupported) {
+		t.Fatalf("Replace() on a node without replacement error = %v", err)
+	}
+	node.SetFeatures(transaction.NODE_FEATURE_TRANSACTION_REPLACEMENT)
+
+	replacement, err := session.Replace(ctx, original.TransactionId, fixed)
+	if err != nil || replacement.Status != transaction.TX_SUBMITTED.String() || replacement.Payload != "fixed" {
+		t.Fatalf("Replace() = %+v, %v", replacement, err)
+	}
+	// Waiting on the original learns it was replaced
+	_, err = session.WaitForTransaction(ctx, testBlockchainId, original.TransactionId, transaction.WaitOptions{PollInterval: 10 * time.Millisecond})
+	var rejected *transaction.ErrTransactionRejected
+	if !errors.As(err, &rejected) || rejected.Transaction.Output != transaction.TX_REJECTED_BY_REPLACEMENT.String() {
+		t.Fatalf("WaitForTransaction() of the replaced transaction error = %v", err)
+	}
+	var decided *transaction.ErrTransactionDecided
+	if _, err := session.Replace(ctx, original.TransactionId, fixed); !errors.As(err, &decided) || !errors.Is(err, utils.ErrRejected) {
+		t.Fatalf("Replace() of a replaced transaction error = %v", err)
+	}
+
+	cancelled, err := session.CancelPending(ctx, testBlockchainId, replacement.TransactionId)
+	if err != nil || cancelled.PayloadType != transaction.CANCEL_TRANSACTION.String() {
+		t.Fatalf("CancelPending() = %+v, %v", cancelled, err)
+	}
+	payload, err := transaction.DecodePayload(cancelled.PayloadType, cancelled.Payload)
+	if cancel, ok := payload.(*transaction.CancelTransactionPayload); err != nil || !ok || cancel.TransactionId != replacement.TransactionId {
+		t.Fatalf("DecodePayload() of the cancellation = %+v, %v", payload, err)
+	}
+
+	// The cancellation itself is accepted once released and cannot be cancelled anymore
+	node.ReleasePending(testBlockchainId)
+	_, err = session.CancelPending(ctx, testBlockchainId, cancelled.TransactionId)
+	if !errors.As(err, &decided) || decided.Transaction.Status != transaction.TX_ACCEPTED.String() {
+		t.Fatalf("CancelPending() of an accepted transaction error = %v", err)
+	}
+	if _, err := session.CancelPending(ctx, testBlockchainId, "unknown"); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("CancelPending() of an unknown transaction error = %v", err)
+	}
+}
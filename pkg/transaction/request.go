@@ -0,0 +1,379 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// NodeError is returned when the node answers with a non successful status code. Responses the
+// SDK recognizes come wrapped in a typed error such as ErrNodeUnavailable or ErrChainNotFound.
+type NodeError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Body       string
+}
+
+func (e *NodeError) Error() string {
+	return fmt.Sprintf("server returned unexpected status code: %d, message:%s", e.StatusCode, e.Body)
+}
+
+func (e *NodeError) Is(target error) bool {
+	return target != nil && target == utils.StatusCategory(e.StatusCode)
+}
+
+// RetryPolicy controls how failed requests are retried. Only requests that are safe to repeat
+// (GET, HEAD, PUT, DELETE) are retried unless RetryNonIdempotent is set, because a repeated
+// submission could be recorded twice by the node.
+type RetryPolicy struct {
+	MaxAttempts        int
+	Backoff            time.Duration // Doubled after every failed attempt
+	RetryNonIdempotent bool
+}
+
+var DEFAULT_RETRY_POLICY = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     200 * time.Millisecond,
+}
+
+// SessionMetrics is a snapshot of the requests a session made to its node
+type SessionMetrics struct {
+	Requests     int64
+	Failures     int64
+	Retries      int64
+	TotalLatency time.Duration
+}
+
+type sessionMetrics struct {
+	requests     atomic.Int64
+	failures     atomic.Int64
+	retries      atomic.Int64
+	totalLatency atomic.Int64
+}
+
+// Metrics returns the request counters accumulated by the session
+func (session *UL_TransactionSession) Metrics() SessionMetrics {
+	if session.metrics == nil {
+		return SessionMetrics{}
+	}
+	return SessionMetrics{
+		Requests:     session.metrics.requests.Load(),
+		Failures:     session.metrics.failures.Load(),
+		Retries:      session.metrics.retries.Load(),
+		TotalLatency: time.Duration(session.metrics.totalLatency.Load()),
+	}
+}
+
+// RateLimiter paces the requests of a session, Wait blocks until the next request may be sent or
+// returns the reason it may not
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// SetRateLimiter makes every request of the session, retries included, wait on limiter first
+func (session *UL_TransactionSession) SetRateLimiter(limiter RateLimiter) {
+	session.limiter = limiter
+}
+
+// SetRetryPolicy replaces the retry policy used for every request of the session
+func (session *UL_TransactionSession) SetRetryPolicy(policy RetryPolicy) {
+	session.retryPolicy = policy
+}
+
+// SetHeader adds a header sent with every request, e.g. an Authorization token required by a gateway
+func (session *UL_TransactionSession) SetHeader(key string, value string) {
+	if session.headers == nil {
+		session.headers = make(http.Header)
+	}
+	session.headers.Set(key, value)
+}
+
+// Do calls an arbitrary node endpoint reusing the session's headers, retries, metrics and error parsing.
+// body may be nil, a []byte, an io.Reader or any value that is streamed as JSON. When out is not nil the
+// response is decoded as JSON into it, or copied as is when out is a *[]byte.
+func (session *UL_TransactionSession) Do(ctx context.Context, method string, path string, body any, out any) error {
+	payload, err := encodeRequestBody(body)
+	if err != nil {
+		return err
+	}
+
+	policy := session.retryPolicy
+	if policy.MaxAttempts <= 0 {
+		policy = DEFAULT_RETRY_POLICY
+	}
+	if !policy.RetryNonIdempotent && !isIdempotent(method) {
+		policy.MaxAttempts = 1
+	}
+
+	backoff := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		respBody, err := session.doOnce(ctx, method, path, payload)
+		if err == nil {
+			return decodeResponseBody(respBody, out)
+		}
+		if attempt >= policy.MaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		session.countRetry()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+func (session *UL_TransactionSession) doOnce(ctx context.Context, method string, path string, payload requestBody) ([]byte, error) {
+	if session.limiter != nil {
+		if err := session.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var trace *requestTrace
+	if session.latencyHook != nil {
+		trace = newRequestTrace(method, path)
+		ctx = httptrace.WithClientTrace(ctx, trace.clientTrace())
+	}
+	reader, finish := payload.open()
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", session.nodeEndpoint, path), reader)
+	if err != nil {
+		finish()
+		return nil, err
+	}
+	for key, values := range session.headers {
+		req.Header[key] = values
+	}
+	if payload.contentType != "" {
+		req.Header.Set("Content-Type", payload.contentType)
+	}
+
+	started := time.Now()
+	defer session.countRequest(started)
+
+	resp, err := session.client().Do(req)
+	body, err := session.readResponse(method, path, resp, err, finish)
+	if trace != nil {
+		session.latencyHook(trace.finish(resp, err))
+	}
+	return body, err
+}
+
+// readResponse reads the response of one attempt, it counts the failures
+func (session *UL_TransactionSession) readResponse(method string, path string, resp *http.Response, err error, finish func() error) ([]byte, error) {
+	if encodeErr := finish(); encodeErr != nil {
+		// The transport only saw the body break off, the encoding error is the cause
+		session.countFailure()
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, encodeErr
+	}
+	if err != nil {
+		session.countFailure()
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		session.countFailure()
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		session.countFailure()
+		return nil, classifyNodeError(&NodeError{StatusCode: resp.StatusCode, Method: method, Path: path, Body: string(body)})
+	}
+	return body, nil
+}
+
+func (session *UL_TransactionSession) countRequest(started time.Time) {
+	if session.metrics != nil {
+		session.metrics.requests.Add(1)
+		session.metrics.totalLatency.Add(int64(time.Since(started)))
+	}
+}
+
+func (session *UL_TransactionSession) countFailure() {
+	if session.metrics != nil {
+		session.metrics.failures.Add(1)
+	}
+}
+
+func (session *UL_TransactionSession) countRetry() {
+	if session.metrics != nil {
+		session.metrics.retries.Add(1)
+	}
+}
+
+// requestBody is the body of every attempt of a request. Values are streamed as JSON, each attempt
+// encodes them again instead of holding the encoded body in memory.
+type requestBody struct {
+	data        []byte
+	value       any
+	contentType string
+}
+
+func encodeRequestBody(body any) (requestBody, error) {
+	switch b := body.(type) {
+	case nil:
+		return requestBody{}, nil
+	case []byte:
+		return requestBody{data: b}, nil
+	case io.Reader:
+		data, err := io.ReadAll(b)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("failed to read request body: %w", err)
+		}
+		return requestBody{data: data}, nil
+	default:
+		return requestBody{value: b, contentType: "application/json"}, nil
+	}
+}
+
+// open returns the reader of one attempt and a function to call once the request is done, it
+// returns the error encoding the body failed with
+func (b requestBody) open() (io.Reader, func() error) {
+	if b.value == nil {
+		if b.data == nil {
+			return nil, func() error { return nil }
+		}
+		return bytes.NewReader(b.data), func() error { return nil }
+	}
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := writeJsonBody(writer, b.value)
+		writer.CloseWithError(err)
+		done <- err
+	}()
+	return reader, func() error {
+		// Unblocks the encoder when the transport stopped reading early
+		reader.Close()
+		if err := <-done; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		return nil
+	}
+}
+
+// STREAM_CHUNK_SIZE is how much of a transaction payload is escaped at once when streaming it
+const STREAM_CHUNK_SIZE = 32 << 10
+
+// writeJsonBody writes value as json.Marshal would. The payload of transaction inputs is escaped in
+// chunks straight to w, so submitting a large payload does not hold a second encoded copy of it.
+func writeJsonBody(w io.Writer, value any) error {
+	var input ULTransactionInput
+	switch v := value.(type) {
+	case ULTransactionInput:
+		input = v
+	case *ULTransactionInput:
+		if v == nil {
+			return json.NewEncoder(w).Encode(v)
+		}
+		input = *v
+	default:
+		return json.NewEncoder(w).Encode(v)
+	}
+
+	payload := input.Payload
+	input.Payload = ""
+	envelope, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	// Quotes inside string values are escaped, so only the key itself can match
+	key := []byte(`"payload":""`)
+	at := bytes.Index(envelope, key)
+	if at < 0 {
+		return fmt.Errorf("no payload field in the encoded transaction")
+	}
+	if _, err := w.Write(envelope[:at+len(key)-1]); err != nil {
+		return err
+	}
+	// The encoder reuses its buffers, escaped holds one chunk at a time
+	var escaped bytes.Buffer
+	encoder := json.NewEncoder(&escaped)
+	for start := 0; start < len(payload); {
+		end := min(start+STREAM_CHUNK_SIZE, len(payload))
+		// Split between runes, invalid bytes are replaced one by one either way
+		for i := 0; i < utf8.UTFMax-1 && end < len(payload) && !utf8.RuneStart(payload[end]); i++ {
+			end--
+		}
+		escaped.Reset()
+		if err := encoder.Encode(payload[start:end]); err != nil {
+			return err
+		}
+		// Drop the quotes and the newline the encoder adds
+		if _, err := w.Write(escaped.Bytes()[1 : escaped.Len()-2]); err != nil {
+			return err
+		}
+		start = end
+	}
+	_, err = w.Write(envelope[at+len(key)-1:])
+	return err
+}
+
+func decodeResponseBody(body []byte, out any) error {
+	switch o := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*o = body
+		return nil
+	default:
+		return json.Unmarshal(body, out)
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable reports failures that are likely to go away on their own
+func isRetryable(err error) bool {
+	var nodeErr *NodeError
+	if errors.As(err, &nodeErr) {
+		switch nodeErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		default:
+			return false
+		}
+	}
+	// Neither an ended context nor a certificate the client does not trust change on a retry
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,150 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestSessionDo(t *testing.T) {
+	var calls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"nodeId":"node"}`))
+	})
+	mux.HandleFunc("GET /blockchains", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["chain"]`))
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		// Fail the first call of every request
+		if calls.Add(1)%2 == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"echo":"` + r.Method + `"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	session := UL_TransactionSession{nodeEndpoint: server.URL, metrics: &sessionMetrics{}}
+	session.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond})
+
+	out := struct {
+		Echo string `json:"echo"`
+	}{}
+	err := session.Do(context.Background(), "GET", "/flaky", nil, &out)
+	var nodeErr *NodeError
+	if !errors.As(err, &nodeErr) || nodeErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Do() without auth error = %v, want a 401 NodeError", err)
+	}
+
+	session.SetHeader("Authorization", "Bearer token")
+	if err := session.Do(context.Background(), "GET", "/flaky", nil, &out); err != nil || out.Echo != "GET" {
+		t.Fatalf("Do() GET = %+v, %v, want a retried success", out, err)
+	}
+
+	// Submissions are not repeated by default, the node might have recorded the first one
+	err = session.Do(context.Background(), "POST", "/flaky", map[string]string{"a": "b"}, &out)
+	if !errors.As(err, &nodeErr) || nodeErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("Do() POST error = %v, want a 503 NodeError", err)
+	}
+
+	metrics := session.Metrics()
+	if metrics.Requests != 4 || metrics.Retries != 1 || metrics.Failures != 3 {
+		t.Errorf("Metrics() = %+v, want 4 requests, 1 retry and 3 failures", metrics)
+	}
+}
+
+func TestStreamRequestBody(t *testing.T) {
+	// Multi-byte runes straddle chunk boundaries, the invalid byte and the HTML characters are escaped
+	payload := strings.Repeat("a", STREAM_CHUNK_SIZE-1) + "é€" + strings.Repeat(`"<\>&`+"\n", STREAM_CHUNK_SIZE/3) + "\xff😀"
+	for _, value := range []any{
+		ULTransactionInput{BlockchainId: "chain", Payload: payload, PayloadType: TX_DATA.String(), Suggestor: `"payload":""`},
+		&ULTransactionInput{Payload: ""},
+		map[string]string{"a": "b"},
+	} {
+		var streamed bytes.Buffer
+		if err := writeJsonBody(&streamed, value); err != nil {
+			t.Fatalf("writeJsonBody(%T) error = %v", value, err)
+		}
+		want, _ := json.Marshal(value)
+		if got := bytes.TrimSuffix(streamed.Bytes(), []byte("\n")); !bytes.Equal(got, want) {
+			t.Fatalf("writeJsonBody(%T) differs from json.Marshal", value)
+		}
+	}
+
+	received := make(chan int, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n, _ := io.Copy(io.Discard, r.Body)
+		received <- int(n)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	session := UL_TransactionSession{nodeEndpoint: server.URL, metrics: &sessionMetrics{}}
+
+	// The encoded body is never held in memory, allocations stay well below the payload size
+	input := ULTransactionInput{Payload: strings.Repeat("x", 8<<20), PayloadType: DEPLOY_SMART_CONTRACT.String()}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if err := session.Do(context.Background(), "POST", "/transactions", input, nil); err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if n := <-received; n < len(input.Payload) {
+		t.Fatalf("the node received %d bytes", n)
+	}
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > uint64(len(input.Payload))/2 {
+		t.Errorf("Do() allocated %d bytes for a %d byte payload", allocated, len(input.Payload))
+	}
+
+	// Encoding errors surface as such rather than as a broken request
+	if err := session.Do(context.Background(), "POST", "/transactions", map[string]any{"bad": make(chan int)}, nil); err == nil || !strings.Contains(err.Error(), "failed to marshal request body") {
+		t.Fatalf("Do() of an unencodable body error = %v", err)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	request := func(err error) error {
+		return &url.Error{Op: "Post", URL: "https://node", Err: err}
+	}
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"unavailable":        {&NodeError{StatusCode: http.StatusServiceUnavailable}, true},
+		"bad request":        {&NodeError{StatusCode: http.StatusBadRequest}, false},
+		"connection refused": {request(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), true},
+		"connection reset":   {request(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+		"timeout":            {request(&net.DNSError{Err: "i/o timeout", IsTimeout: true}), true},
+		"truncated response": {request(io.ErrUnexpectedEOF), true},
+		"unknown host":       {request(&net.DNSError{Err: "no such host", IsNotFound: true}), false},
+		"unsupported scheme": {request(errors.New(`unsupported protocol scheme "ftp"`)), false},
+		"untrusted":          {request(&tls.CertificateVerificationError{Err: errors.New("unknown authority")}), false},
+		"cancelled":          {request(context.Canceled), false},
+		"deadline exceeded":  {request(context.DeadlineExceeded), false},
+		"encoding":           {errors.New("failed to marshal request body"), false},
+	}
+	for name, test := range tests {
+		if got := isRetryable(test.err); got != test.want {
+			t.Errorf("isRetryable(%s) = %t, want %t", name, got, test.want)
+		}
+	}
+}
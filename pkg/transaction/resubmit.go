@@ -0,0 +1,363 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DEFAULT_RESUBMIT_ATTEMPTS = 3
+	// DEFAULT_DROP_AFTER is how long a transaction may be unknown to the node before it counts as dropped
+	DEFAULT_DROP_AFTER = 30 * time.Second
+)
+
+// QueuedSubmission is a transaction that was submitted but not yet accepted. Input is kept as it was
+// before signing so every attempt is signed again with a fresh timestamp.
+type QueuedSubmission struct {
+	Key           string             `json:"key"`
+	Input         ULTransactionInput `json:"input"`
+	TransactionId string             `json:"transactionId"`
+	Attempts      int                `json:"attempts"`
+	SubmittedAt   time.Time          `json:"submittedAt"`
+}
+
+// SubmissionQueue persists the transactions a Resubmitter watches, keys are chosen by the caller and
+// stay the same across attempts
+type SubmissionQueue interface {
+	Put(entry QueuedSubmission) error
+	Remove(key string) error
+	List() ([]QueuedSubmission, error)
+}
+
+// MemorySubmissionQueue keeps submissions for the lifetime of the process
+type MemorySubmissionQueue struct {
+	mu      sync.Mutex
+	entries map[string]QueuedSubmission
+}
+
+func NewMemorySubmissionQueue() *MemorySubmissionQueue {
+	return &MemorySubmissionQueue{entries: make(map[string]QueuedSubmission)}
+}
+
+func (queue *MemorySubmissionQueue) Put(entry QueuedSubmission) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.entries[entry.Key] = entry
+	return nil
+}
+
+func (queue *MemorySubmissionQueue) Remove(key string) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	delete(queue.entries, key)
+	return nil
+}
+
+func (queue *MemorySubmissionQueue) List() ([]QueuedSubmission, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	entries := make([]QueuedSubmission, 0, len(queue.entries))
+	for _, entry := range queue.entries {
+		entries = append(entries, entry)
+	}
+	sortSubmissions(entries)
+	return entries, nil
+}
+
+// FileSubmissionQueue writes one JSON file per submission inside a directory, surviving restarts
+type FileSubmissionQueue struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewFileSubmissionQueue(dir string) (*FileSubmissionQueue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create submission queue directory: %w", err)
+	}
+	return &FileSubmissionQueue{dir: dir}, nil
+}
+
+const submissionFileSuffix = ".submission.json"
+
+func (queue *FileSubmissionQueue) path(key string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(key)
+	return filepath.Join(queue.dir, name+submissionFileSuffix)
+}
+
+func (queue *FileSubmissionQueue) Put(entry QueuedSubmission) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal submission: %w", err)
+	}
+	// Write then rename so a crash never leaves a half written entry behind
+	path := queue.path(entry.Key)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write submission: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit submission: %w", err)
+	}
+	return nil
+}
+
+func (queue *FileSubmissionQueue) Remove(key string) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if err := os.Remove(queue.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove submission: %w", err)
+	}
+	return nil
+}
+
+func (queue *FileSubmissionQueue) List() ([]QueuedSubmission, error) {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	files, err := os.ReadDir(queue.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read submission queue: %w", err)
+	}
+	entries := []QueuedSubmission{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), submissionFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(queue.dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read submission: %w", err)
+		}
+		entry := QueuedSubmission{}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("failed to parse submission %s: %w", file.Name(), err)
+		}
+		entries = append(entries, entry)
+	}
+	sortSubmissions(entries)
+	return entries, nil
+}
+
+// sortSubmissions orders entries by submission time so the oldest are handled first
+func sortSubmissions(entries []QueuedSubmission) {
+	slices.SortFunc(entries, func(a, b QueuedSubmission) int {
+		if c := a.SubmittedAt.Compare(b.SubmittedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Key, b.Key)
+	})
+}
+
+type ResubmitPolicy struct {
+	// MaxAttempts bounds the submissions of one transaction including the first, defaults to DEFAULT_RESUBMIT_ATTEMPTS
+	MaxAttempts int
+	// DropAfter is how long after an attempt a transaction that is neither pending nor accepted
+	// counts as dropped, defaults to DEFAULT_DROP_AFTER
+	DropAfter time.Duration
+}
+
+type ResubmitEventType string
+
+const (
+	RESUBMIT_EVENT_SUBMITTED   ResubmitEventType = "submitted"
+	RESUBMIT_EVENT_CONFIRMED   ResubmitEventType = "confirmed"
+	RESUBMIT_EVENT_REJECTED    ResubmitEventType = "rejected"
+	RESUBMIT_EVENT_DROPPED     ResubmitEventType = "dropped"
+	RESUBMIT_EVENT_RESUBMITTED ResubmitEventType = "resubmitted"
+	// RESUBMIT_EVENT_EXHAUSTED is emitted when a dropped transaction reached MaxAttempts and is given up
+	RESUBMIT_EVENT_EXHAUSTED ResubmitEventType = "exhausted"
+)
+
+// ResubmitEvent reports one step of a watched transaction, PreviousTransactionId is set on resubmission
+type ResubmitEvent struct {
+	Type                  ResubmitEventType
+	Key                   string
+	TransactionId         string
+	PreviousTransactionId string
+	Attempt               int
+	Output                string
+	Err                   error
+	Time                  time.Time
+}
+
+// Resubmitter watches submitted transactions and, when one disappears from the node's pending set
+// without ever being accepted, signs it again with a fresh timestamp and resubmits it up to the
+// policy limit. The queue keeps the watch list across restarts.
+type Resubmitter struct {
+	session *UL_TransactionSession
+	queue   SubmissionQueue
+	policy  ResubmitPolicy
+	now     func() time.Time
+	// OnEvent is told about every submission, confirmation, drop and resubmission
+	OnEvent func(ResubmitEvent)
+}
+
+func NewResubmitter(session *UL_TransactionSession, queue SubmissionQueue, policy ResubmitPolicy) *Resubmitter {
+	if queue == nil {
+		queue = NewMemorySubmissionQueue()
+	}
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DEFAULT_RESUBMIT_ATTEMPTS
+	}
+	if policy.DropAfter <= 0 {
+		policy.DropAfter = DEFAULT_DROP_AFTER
+	}
+	return &Resubmitter{session: session, queue: queue, policy: policy, now: time.Now}
+}
+
+// PendingTransactions returns the ids of the transactions the node holds in its mempool for a chain
+func (session *UL_TransactionSession) PendingTransactions(ctx context.Context, blockchainId string) ([]string, error) {
+	chain, err := session.getChainInfo(ctx, blockchainId)
+	if err != nil {
+		return nil, err
+	}
+	return chain.Pending, nil
+}
+
+// Submit signs and submits input and watches it under key until it is accepted. Transactions the
+// node rejects outright are reported and not watched.
+func (r *Resubmitter) Submit(key string, input ULTransactionInput) (ULTransaction, error) {
+	if key == "" {
+		return ULTransaction{}, fmt.Errorf("a submission key is required")
+	}
+	tx, err := r.session.GenerateTransaction(input)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if tx.Status == TX_REJECTED.String() {
+		r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_REJECTED, Key: key, TransactionId: tx.TransactionId, Attempt: 1, Output: tx.Output})
+		return tx, nil
+	}
+	entry := QueuedSubmission{Key: key, Input: input, TransactionId: tx.TransactionId, Attempts: 1, SubmittedAt: r.now().UTC()}
+	if err := r.queue.Put(entry); err != nil {
+		return tx, fmt.Errorf("submitted %s but could not queue it: %w", tx.TransactionId, err)
+	}
+	r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_SUBMITTED, Key: key, TransactionId: tx.TransactionId, Attempt: 1, Output: tx.Output})
+	return tx, nil
+}
+
+// Check inspects every watched transaction once. Accepted and rejected transactions leave the queue,
+// dropped ones are resubmitted or given up. Failures of single entries are joined in the error.
+func (r *Resubmitter) Check(ctx context.Context) error {
+	entries, err := r.queue.List()
+	if err != nil {
+		return err
+	}
+	pending := make(map[string][]string)
+	errs := []error{}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ids, ok := pending[entry.Input.BlockchainId]
+		if !ok {
+			ids, err = r.session.PendingTransactions(ctx, entry.Input.BlockchainId)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			pending[entry.Input.BlockchainId] = ids
+		}
+		if err := r.check(ctx, entry, slices.Contains(ids, entry.TransactionId)); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run checks the queue every interval until ctx is done, onError receives the failures of each pass
+func (r *Resubmitter) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Check(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Resubmitter) check(ctx context.Context, entry QueuedSubmission, isPending bool) error {
+	if isPending {
+		return nil
+	}
+	// An entry without id failed to resubmit and is due for another attempt
+	missing := entry.TransactionId == ""
+	tx := ULTransaction{}
+	if !missing {
+		var err error
+		tx, err = r.session.GetTransaction(ctx, entry.Input.BlockchainId, entry.TransactionId)
+		nodeErr := &NodeError{}
+		missing = errors.As(err, &nodeErr) && nodeErr.StatusCode == http.StatusNotFound
+		if err != nil && !missing {
+			return err
+		}
+	}
+	if !missing {
+		switch {
+		case tx.Status == TX_ACCEPTED.String() || tx.BlockHeight > 0:
+			r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_CONFIRMED, Key: entry.Key, TransactionId: entry.TransactionId, Attempt: entry.Attempts, Output: tx.Output})
+			return r.queue.Remove(entry.Key)
+		case tx.Status == TX_REJECTED.String():
+			// Rejections are verdicts on the content, signing it again would not change them
+			r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_REJECTED, Key: entry.Key, TransactionId: entry.TransactionId, Attempt: entry.Attempts, Output: tx.Output})
+			return r.queue.Remove(entry.Key)
+		}
+	}
+
+	// Neither pending nor accepted, give the node time to propagate before calling it dropped
+	if r.now().Sub(entry.SubmittedAt) < r.policy.DropAfter {
+		return nil
+	}
+	r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_DROPPED, Key: entry.Key, TransactionId: entry.TransactionId, Attempt: entry.Attempts})
+	if entry.Attempts >= r.policy.MaxAttempts {
+		r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_EXHAUSTED, Key: entry.Key, TransactionId: entry.TransactionId, Attempt: entry.Attempts})
+		return r.queue.Remove(entry.Key)
+	}
+
+	previous := entry.TransactionId
+	entry.Attempts++
+	entry.SubmittedAt = r.now().UTC()
+	resubmitted, err := r.session.GenerateTransaction(entry.Input)
+	if err != nil {
+		// The attempt counts, the entry is retried on a later pass
+		r.emit(ResubmitEvent{Type: RESUBMIT_EVENT_RESUBMITTED, Key: entry.Key, PreviousTransactionId: previous, Attempt: entry.Attempts, Err: err})
+		entry.TransactionId = ""
+		return errors.Join(err, r.queue.Put(entry))
+	}
+	entry.TransactionId = resubmitted.TransactionId
+	r.emit(ResubmitEvent{
+		Type:                  RESUBMIT_EVENT_RESUBMITTED,
+		Key:                   entry.Key,
+		TransactionId:         resubmitted.TransactionId,
+		PreviousTransactionId: previous,
+		Attempt:               entry.Attempts,
+		Output:                resubmitted.Output,
+	})
+	return r.queue.Put(entry)
+}
+
+func (r *Resubmitter) emit(event ResubmitEvent) {
+	if r.OnEvent == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = r.now().UTC()
+	}
+	r.OnEvent(event)
+}
@@ -0,0 +1,91 @@
+package transaction_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestResubmitDroppedTransactions(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	queue, err := transaction.NewFileSubmissionQueue(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileSubmissionQueue() error = %v", err)
+	}
+	resubmitter := transaction.NewResubmitter(session, queue, transaction.ResubmitPolicy{MaxAttempts: 2, DropAfter: time.Millisecond})
+	events := []transaction.ResubmitEvent{}
+	resubmitter.OnEvent = func(event transaction.ResubmitEvent) { events = append(events, event) }
+
+	node.HoldTransactions(true)
+	first, err := resubmitter.Submit("order-1", transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "order 1",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	pending, err := session.PendingTransactions(ctx, testBlockchainId)
+	if err != nil || len(pending) != 1 || pending[0] != first.TransactionId {
+		t.Fatalf("PendingTransactions() = %v, %v", pending, err)
+	}
+
+	// Still pending, nothing to do
+	time.Sleep(2 * time.Millisecond)
+	if err := resubmitter.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Type != transaction.RESUBMIT_EVENT_SUBMITTED {
+		t.Fatalf("events = %+v, want only the submission", events)
+	}
+
+	// Evicted from the mempool, the transaction is signed again and resubmitted
+	node.DropPending(testBlockchainId)
+	time.Sleep(time.Second) // The node deduplicates on the second of the signing timestamp
+	if err := resubmitter.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 3 || events[1].Type != transaction.RESUBMIT_EVENT_DROPPED || events[2].Type != transaction.RESUBMIT_EVENT_RESUBMITTED {
+		t.Fatalf("events = %+v, want a drop and a resubmission", events)
+	}
+	second := events[2]
+	if second.PreviousTransactionId != first.TransactionId || second.TransactionId == first.TransactionId || second.Attempt != 2 {
+		t.Fatalf("resubmission = %+v", second)
+	}
+
+	// Accepted on the second attempt
+	node.ReleasePending(testBlockchainId)
+	if err := resubmitter.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if last := events[len(events)-1]; last.Type != transaction.RESUBMIT_EVENT_CONFIRMED || last.TransactionId != second.TransactionId {
+		t.Fatalf("last event = %+v, want the confirmation", last)
+	}
+	if entries, _ := queue.List(); len(entries) != 0 {
+		t.Fatalf("queue = %+v, want it empty", entries)
+	}
+
+	// Dropped again after the last attempt, the transaction is given up
+	if _, err := resubmitter.Submit("order-2", transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "order 2",
+		PayloadType:  transaction.TX_DATA.String(),
+	}); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	restarted := transaction.NewResubmitter(session, queue, transaction.ResubmitPolicy{MaxAttempts: 1, DropAfter: time.Millisecond})
+	restarted.OnEvent = resubmitter.OnEvent
+	node.DropPending(testBlockchainId)
+	time.Sleep(2 * time.Millisecond)
+	if err := restarted.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if last := events[len(events)-1]; last.Type != transaction.RESUBMIT_EVENT_EXHAUSTED || last.Key != "order-2" {
+		t.Fatalf("last event = %+v, want order-2 to be given up", last)
+	}
+}
@@ -0,0 +1,95 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// KeyRotation records the transactions moving a wallet to a successor key, see wallet.ConvertKeyType
+type KeyRotation struct {
+	Previous   string        `json:"previous"`
+	Successor  string        `json:"successor"`
+	Registered ULTransaction `json:"registered"`
+	Retired    ULTransaction `json:"retired"`
+}
+
+// RegisterWalletInput builds the CREATE_WALLET transaction of w, which w signs itself on behalf of
+// its parent
+func RegisterWalletInput(blockchainId string, w wallet.UL_Wallet) (ULTransactionInput, error) {
+	if err := w.CheckKey(); err != nil {
+		return ULTransactionInput{}, err
+	}
+	payload, err := json.Marshal(CreateWalletPayload{
+		PublicKey:  w.GetKey().GetPublicKeyHex(false),
+		Parent:     w.Parent,
+		KeyType:    w.GetKey().GetType(),
+		AuthGroups: w.AuthGroups,
+	})
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	return ULTransactionInput{
+		BlockchainId: blockchainId,
+		From:         w.Parent,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  TX_CREATE_WALLET.String(),
+	}, nil
+}
+
+// RetireWalletInput builds the ALTER_WALLET transaction disabling w, its auth groups are kept so
+// the wallet can be enabled again as it was
+func RetireWalletInput(blockchainId string, w wallet.UL_Wallet) (ULTransactionInput, error) {
+	payload, err := json.Marshal(AlterWalletPayload{
+		Target:     w.Address,
+		Enabled:    false,
+		AuthGroups: w.AuthGroups,
+	})
+	if err != nil {
+		return ULTransactionInput{}, err
+	}
+	return ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           w.Address,
+		Payload:      string(payload),
+		PayloadType:  TX_ALTER_WALLET.String(),
+	}, nil
+}
+
+// RotateKey registers the wallet of successor and then has the wallet of previous disable itself, so
+// the account is never left without an enabled key. When the registration fails the previous wallet
+// is left untouched. When retiring fails the successor is already registered, RetireWalletInput
+// builds the transaction to retry with.
+func RotateKey(blockchainId string, previous *UL_TransactionSession, successor *UL_TransactionSession) (KeyRotation, error) {
+	rotation := KeyRotation{Previous: previous.wallet.Address, Successor: successor.wallet.Address}
+	if previous.wallet.Parent != successor.wallet.Parent {
+		return rotation, fmt.Errorf("the successor %s has parent %q, the previous wallet %q", rotation.Successor, successor.wallet.Parent, previous.wallet.Parent)
+	}
+
+	register, err := RegisterWalletInput(blockchainId, successor.wallet)
+	if err != nil {
+		return rotation, err
+	}
+	rotation.Registered, err = successor.GenerateTransaction(register)
+	if err != nil {
+		return rotation, fmt.Errorf("unable to register the successor %s: %w", rotation.Successor, err)
+	}
+	if rotation.Registered.Output != TX_SUCCESS.String() {
+		return rotation, fmt.Errorf("registering the successor %s failed with %s", rotation.Successor, rotation.Registered.Output)
+	}
+
+	retire, err := RetireWalletInput(blockchainId, previous.wallet)
+	if err != nil {
+		return rotation, err
+	}
+	rotation.Retired, err = previous.GenerateTransaction(retire)
+	if err != nil {
+		return rotation, fmt.Errorf("unable to retire %s: %w", rotation.Previous, err)
+	}
+	if rotation.Retired.Output != TX_SUCCESS.String() {
+		return rotation, fmt.Errorf("retiring %s failed with %s", rotation.Previous, rotation.Retired.Output)
+	}
+	return rotation, nil
+}
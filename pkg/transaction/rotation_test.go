@@ -0,0 +1,55 @@
+package transaction_test
+
+import (
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestRotateKey(t *testing.T) {
+	node, admin := newMockSession(t)
+	registerSigner(t, admin)
+
+	previous, mnemonic, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, admin.GetWallet().Address, map[string]wallet.UL_AuthPermission{"data": {Create: true}}, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	previousSession, _ := transaction.NewUL_TransactionSession(node.URL(), previous)
+	register, err := transaction.RegisterWalletInput(testBlockchainId, previous)
+	if err != nil {
+		t.Fatalf("RegisterWalletInput() error = %v", err)
+	}
+	if _, err := previousSession.GenerateTransaction(register); err != nil {
+		t.Fatalf("registering the previous wallet error = %v", err)
+	}
+
+	successor, err := wallet.ConvertKeyTypeFromMnemonic(previous, mnemonic, "", crypto.KeyTypeMlDSA87)
+	if err != nil {
+		t.Fatalf("ConvertKeyTypeFromMnemonic() error = %v", err)
+	}
+	successorSession, _ := transaction.NewUL_TransactionSession(node.URL(), successor)
+	rotation, err := transaction.RotateKey(testBlockchainId, &previousSession, &successorSession)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	if rotation.Registered.TransactionId == "" || rotation.Retired.TransactionId == "" {
+		t.Fatalf("RotateKey() = %+v", rotation)
+	}
+
+	wallets := registeredWallets(t, admin)
+	old, registered := wallets[previous.Address], wallets[successor.Address]
+	if old.Enabled || !registered.Enabled {
+		t.Fatalf("previous enabled = %v and successor enabled = %v, want only the successor", old.Enabled, registered.Enabled)
+	}
+	if registered.KeyType != crypto.KeyTypeMlDSA87 || registered.Parent != previous.Parent || !registered.AuthGroups["data"].Create {
+		t.Fatalf("successor registered as %+v", registered)
+	}
+
+	orphan, _, _ := wallet.GenerateNewWallet("", crypto.KeyTypeMlDSA87, "", nil, wallet.DefaultEntropy)
+	orphanSession, _ := transaction.NewUL_TransactionSession(node.URL(), orphan)
+	if _, err := transaction.RotateKey(testBlockchainId, &successorSession, &orphanSession); err == nil {
+		t.Fatal("RotateKey() accepted a successor with another parent")
+	}
+}
@@ -0,0 +1,247 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// InputField names a field of ULTransactionInput checked by InputRules
+type InputField string
+
+const (
+	INPUT_FIELD_TO      InputField = "to"
+	INPUT_FIELD_FROM    InputField = "from"
+	INPUT_FIELD_PAYLOAD InputField = "payload"
+)
+
+func (f InputField) value(input ULTransactionInput) string {
+	switch f {
+	case INPUT_FIELD_TO:
+		return input.To
+	case INPUT_FIELD_FROM:
+		return input.From
+	case INPUT_FIELD_PAYLOAD:
+		return input.Payload
+	default:
+		return ""
+	}
+}
+
+// InputRules describe what a payload type needs from a ULTransactionInput. GenerateTransaction checks
+// them once the session filled From, so inputs the node would refuse, or worse accept with an empty
+// To, fail before they are signed.
+type InputRules struct {
+	// Required fields must not be empty
+	Required []InputField
+	// Forbidden fields must be empty
+	Forbidden []InputField
+	// Addresses lists the fields that must hold hex encoded 32 byte wallet addresses when set
+	Addresses []InputField
+	// Schema validates the payload, nil accepts any payload. See JsonSchema.
+	Schema func(payload string) error
+}
+
+// ErrInputRule is returned for inputs breaking the rules of their payload type
+type ErrInputRule struct {
+	PayloadType string
+	Msg         string
+}
+
+func (e *ErrInputRule) Error() string {
+	return fmt.Sprintf("invalid %s transaction, %s", e.PayloadType, e.Msg)
+}
+
+func (e *ErrInputRule) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// payloadValidator is implemented by payloads checking more than their JSON shape
+type payloadValidator interface {
+	Validate() error
+}
+
+// JsonSchema returns a Schema accepting payloads that decode into a JSON object of type T. Payloads
+// whose T implements Validate() error must pass it too.
+func JsonSchema[T any]() func(payload string) error {
+	return func(payload string) error {
+		var value T
+		if err := json.Unmarshal([]byte(payload), &value); err != nil {
+			return fmt.Errorf("the payload is not a %T, %s", value, utils.HandleJsonError(err))
+		}
+		if validator, ok := any(value).(payloadValidator); ok {
+			return validator.Validate()
+		}
+		return nil
+	}
+}
+
+// Rules shared by the token payload types, the payload names the token so To is optional
+func tokenRules[T any]() InputRules {
+	return InputRules{
+		Required:  []InputField{INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[T](),
+	}
+}
+
+var inputRules = struct {
+	sync.RWMutex
+	rules map[string]InputRules
+}{rules: map[string]InputRules{
+	// DATA is addressed to anything the application chooses, even names that are not addresses
+	TX_DATA.String(): {
+		Required:  []InputField{INPUT_FIELD_TO},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+	},
+	// The parent authors CREATE_WALLET, root wallets have no From
+	TX_CREATE_WALLET.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[CreateWalletPayload](),
+	},
+	TX_ALTER_WALLET.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[AlterWalletPayload](),
+	},
+	// The node derives the address of deployed contracts
+	DEPLOY_SMART_CONTRACT.String(): {
+		Required:  []InputField{INPUT_FIELD_PAYLOAD},
+		Forbidden: []InputField{INPUT_FIELD_TO},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+	},
+	INVOKE_SMART_CONTRACT.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[InvokeContractPayload](),
+	},
+	UPGRADE_SMART_CONTRACT.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[UpgradeContractPayload](),
+	},
+	ROLLBACK_SMART_CONTRACT.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[RollbackContractPayload](),
+	},
+	CREATE_TOKEN.String():         tokenRules[CreateTokenPayload](),
+	TRANSFER_TOKEN.String():       tokenRules[TransferTokenPayload](),
+	APPROVE_TOKEN.String():        tokenRules[ApproveTokenPayload](),
+	MINT_TOKEN.String():           tokenRules[MintTokenPayload](),
+	BURN_TOKEN.String():           tokenRules[BurnTokenPayload](),
+	MINT_NFT.String():             tokenRules[MintTokenPayload](),
+	TRANSFER_NFT.String():         tokenRules[TransferTokenPayload](),
+	APPROVE_NFT.String():          tokenRules[ApproveTokenPayload](),
+	SET_APPROVAL_FOR_ALL.String(): tokenRules[SetApprovalForAllPayload](),
+	TRANSFER_MULTI_TOKEN.String(): tokenRules[TransferTokenPayload](),
+	MINT_MULTI_TOKEN.String():     tokenRules[MintTokenPayload](),
+	CONVERT_TOKEN.String():        tokenRules[ConvertTokenPayload](),
+	DELEGATE_KEY.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[SignedDelegation](),
+	},
+	REVOKE_DELEGATION.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[RevokeDelegationPayload](),
+	},
+	CANCEL_TRANSACTION.String(): {
+		Required:  []InputField{INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[CancelTransactionPayload](),
+	},
+	// The node derives the address of multisig accounts, the other multisig types are addressed to the account
+	CREATE_MULTISIG.String(): {
+		Required:  []InputField{INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_FROM},
+		Schema:    JsonSchema[CreateMultisigPayload](),
+	},
+	PROPOSE_MULTISIG.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[ProposeMultisigPayload](),
+	},
+	APPROVE_MULTISIG.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[MultisigProposalPayload](),
+	},
+	EXECUTE_MULTISIG.String(): {
+		Required:  []InputField{INPUT_FIELD_TO, INPUT_FIELD_PAYLOAD},
+		Addresses: []InputField{INPUT_FIELD_TO, INPUT_FIELD_FROM},
+		Schema:    JsonSchema[MultisigProposalPayload](),
+	},
+}}
+
+// RegisterInputRules makes GenerateTransaction accept a custom payload type and check its inputs. The
+// type signs the bound commitment and decodes as generic JSON, RegisterPayloadType configures both.
+// Payload types cannot be registered twice.
+func RegisterInputRules(payloadType string, rules InputRules) error {
+	inputRules.Lock()
+	defer inputRules.Unlock()
+	if payloadType == "" {
+		return fmt.Errorf("input rules need a payload type")
+	}
+	if _, ok := inputRules.rules[payloadType]; ok {
+		return fmt.Errorf("input rules of %s are already registered", payloadType)
+	}
+	for _, field := range slices.Concat(rules.Required, rules.Forbidden, rules.Addresses) {
+		if field != INPUT_FIELD_TO && field != INPUT_FIELD_FROM && field != INPUT_FIELD_PAYLOAD {
+			return fmt.Errorf("input rules of %s name the unknown field %q", payloadType, field)
+		}
+	}
+	inputRules.rules[payloadType] = rules
+	return nil
+}
+
+// GetInputRules returns the rules of a payload type, false when it is not registered
+func GetInputRules(payloadType string) (InputRules, bool) {
+	inputRules.RLock()
+	defer inputRules.RUnlock()
+	rules, ok := inputRules.rules[payloadType]
+	return rules, ok
+}
+
+// ValidateInput fails with ErrInputRule when the input breaks the rules of its payload type or the
+// payload type is not registered
+func ValidateInput(input ULTransactionInput) error {
+	rules, ok := GetInputRules(input.PayloadType)
+	if !ok {
+		if input.PayloadType == "" {
+			return &ErrInputRule{PayloadType: "untyped", Msg: "the payload type is missing"}
+		}
+		return &ErrInputRule{PayloadType: input.PayloadType, Msg: "unknown payload type, see RegisterPayloadType"}
+	}
+	return rules.Check(input)
+}
+
+// Check fails with ErrInputRule when the input breaks the rules
+func (rules InputRules) Check(input ULTransactionInput) error {
+	for _, field := range rules.Required {
+		if field.value(input) == "" {
+			return &ErrInputRule{PayloadType: input.PayloadType, Msg: fmt.Sprintf("%s is required", field)}
+		}
+	}
+	for _, field := range rules.Forbidden {
+		if field.value(input) != "" {
+			return &ErrInputRule{PayloadType: input.PayloadType, Msg: fmt.Sprintf("%s must be empty", field)}
+		}
+	}
+	for _, field := range rules.Addresses {
+		if value := field.value(input); value != "" && !isAddress(value) {
+			return &ErrInputRule{PayloadType: input.PayloadType, Msg: fmt.Sprintf("%s %q is not a wallet address", field, value)}
+		}
+	}
+	if rules.Schema != nil {
+		if err := rules.Schema(input.Payload); err != nil {
+			return &ErrInputRule{PayloadType: input.PayloadType, Msg: err.Error()}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package transaction_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestInputRules(t *testing.T) {
+	node, session := newMockSession(t)
+	address := session.GetWallet().Address
+	alter, _ := json.Marshal(transaction.AlterWalletPayload{Target: address, Enabled: true})
+
+	for name, input := range map[string]transaction.ULTransactionInput{
+		"empty to":            {Payload: "data", PayloadType: transaction.TX_DATA.String()},
+		"missing type":        {To: address, Payload: "data"},
+		"unknown type":        {To: address, Payload: "data", PayloadType: "SHIPMENT"},
+		"deploy with to":      {To: address, Payload: "contract", PayloadType: transaction.DEPLOY_SMART_CONTRACT.String()},
+		"alter to a name":     {To: "archive", Payload: string(alter), PayloadType: transaction.TX_ALTER_WALLET.String()},
+		"alter without json":  {To: address, Payload: "enable it", PayloadType: transaction.TX_ALTER_WALLET.String()},
+		"invoke without json": {To: address, Payload: `{"gasLimit":"lots"}`, PayloadType: transaction.INVOKE_SMART_CONTRACT.String()},
+		"invalid burn":        {Payload: `{"tokenAddress":"token"}`, PayloadType: transaction.BURN_TOKEN.String()},
+	} {
+		input.BlockchainId = testBlockchainId
+		_, err := session.GenerateTransaction(input)
+		var rule *transaction.ErrInputRule
+		if !errors.As(err, &rule) || !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("GenerateTransaction() of %s error = %v, want ErrInputRule", name, err)
+		}
+	}
+	if txs := node.Transactions(); len(txs) != 0 {
+		t.Fatalf("%d invalid transactions were submitted", len(txs))
+	}
+
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           address,
+		Payload:      string(alter),
+		PayloadType:  transaction.TX_ALTER_WALLET.String(),
+	}); err != nil {
+		t.Fatalf("GenerateTransaction() of a valid ALTER_WALLET error = %v", err)
+	}
+}
+
+type shipmentPayload struct {
+	Tracking string `json:"tracking"`
+}
+
+func (p shipmentPayload) Validate() error {
+	if p.Tracking == "" {
+		return fmt.Errorf("the tracking number is missing")
+	}
+	return nil
+}
+
+func TestRegisterInputRules(t *testing.T) {
+	rules := transaction.InputRules{
+		Required:  []transaction.InputField{transaction.INPUT_FIELD_TO, transaction.INPUT_FIELD_PAYLOAD},
+		Addresses: []transaction.InputField{transaction.INPUT_FIELD_TO},
+		Schema:    transaction.JsonSchema[shipmentPayload](),
+	}
+	if err := transaction.RegisterInputRules("TEST_SHIPMENT", rules); err != nil {
+		t.Fatalf("RegisterInputRules() error = %v", err)
+	}
+	if err := transaction.RegisterInputRules("TEST_SHIPMENT", rules); err == nil {
+		t.Fatal("RegisterInputRules() registered a payload type twice")
+	}
+	if err := transaction.RegisterInputRules(transaction.TX_DATA.String(), rules); err == nil {
+		t.Fatal("RegisterInputRules() replaced the rules of DATA")
+	}
+	if err := transaction.RegisterInputRules("TEST_BROKEN", transaction.InputRules{Required: []transaction.InputField{"memo"}}); err == nil {
+		t.Fatal("RegisterInputRules() accepted an unknown field")
+	}
+
+	address := fmt.Sprintf("%064x", 42)
+	for payload, valid := range map[string]bool{
+		`{"tracking":"1Z999"}`: true,
+		`{"tracking":""}`:      false,
+		`["1Z999"]`:            false,
+	} {
+		err := transaction.ValidateInput(transaction.ULTransactionInput{To: address, Payload: payload, PayloadType: "TEST_SHIPMENT"})
+		if (err == nil) != valid {
+			t.Errorf("ValidateInput() of %s error = %v, want valid %v", payload, err, valid)
+		}
+	}
+	if err := transaction.ValidateInput(transaction.ULTransactionInput{To: "warehouse", Payload: `{"tracking":"1Z999"}`, PayloadType: "TEST_SHIPMENT"}); err == nil {
+		t.Fatal("ValidateInput() accepted a To that is not an address")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"math"
 	"reflect"
 	"sort"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
 )
 
 type ContractDataType byte
@@ -159,13 +161,17 @@ func Encode(data interface{}) ([]byte, error) {
 		binary.BigEndian.PutUint32(result[sizePos:], uint32(totalSize))
 		return result, nil
 	}
-	return nil, fmt.Errorf("unsupported type: %T", data)
+	return nil, fmt.Errorf("%w type: %T", utils.ErrUnsupported, data)
 }
 
 func Decode(data []byte) (interface{}, error) {
 	// First 5 bytes are the type and the length, so we need at least 5 bytes!
 	if len(data) < 5 {
-		return nil, fmt.Errorf("data too short to decode")
+		return nil, &utils.ErrMalformed{What: "contract value", Msg: "data too short to decode"}
+	}
+	// Every length is checked against the data before it is read, truncated values are errors
+	if _, err := encodedSize(data); err != nil {
+		return nil, err
 	}
 
 	dataType := ContractDataType(data[0])
@@ -219,8 +225,11 @@ func Decode(data []byte) (interface{}, error) {
 		result := make(map[string]interface{})
 		offset := 9
 		for i := uint32(0); i < numEntries; i++ {
-			keySize := binary.BigEndian.Uint32(data[offset+1 : offset+5])
-			keyIface, err := Decode(data[offset : offset+5+int(keySize)])
+			keySize, err := encodedSize(data[offset:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode key: %w", err)
+			}
+			keyIface, err := Decode(data[offset : offset+keySize])
 			if err != nil {
 				return nil, fmt.Errorf("failed to decode key: %w", err)
 			}
@@ -228,13 +237,16 @@ func Decode(data []byte) (interface{}, error) {
 			if !ok {
 				return nil, fmt.Errorf("key is not a string: %T , error: %w", keyIface, err)
 			}
-			offset += 5 + int(keySize)
-			valueSize := binary.BigEndian.Uint32(data[offset+1 : offset+5])
-			valueIface, err := Decode(data[offset : offset+5+int(valueSize)])
+			offset += keySize
+			valueSize, err := encodedSize(data[offset:])
 			if err != nil {
 				return nil, fmt.Errorf("failed to decode value: %w", err)
 			}
-			offset += 5 + int(valueSize)
+			valueIface, err := Decode(data[offset : offset+valueSize])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode value: %w", err)
+			}
+			offset += valueSize
 			result[key] = valueIface
 		}
 		return result, nil
@@ -245,23 +257,49 @@ func Decode(data []byte) (interface{}, error) {
 			return nil, fmt.Errorf("array data too short: expected %d, got %d", 9+int(totalSize), len(data))
 		}
 
+		// Elements take at least 5 bytes, a larger count is corrupt and must not size the slice
+		if uint64(numElements)*5 > uint64(totalSize) {
+			return nil, &utils.ErrMalformed{What: "contract value", Msg: fmt.Sprintf("%d elements do not fit %d bytes", numElements, totalSize)}
+		}
 		result := make([]interface{}, numElements)
 		offset := 9
 
 		for i := uint32(0); i < numElements; i++ {
-			valueSize := binary.BigEndian.Uint32(data[offset+1 : offset+5])
-			elem, err := Decode(data[offset : offset+5+int(valueSize)])
+			valueSize, err := encodedSize(data[offset:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode element: %w", err)
+			}
+			elem, err := Decode(data[offset : offset+valueSize])
 			if err != nil {
 				return nil, fmt.Errorf("failed to decode element: %w", err)
 			}
 			result[i] = elem
-			offset += 5 + int(valueSize)
+			offset += valueSize
 		}
 		// Convert the result to the correct type
 
 		return result, nil
 	}
-	return nil, fmt.Errorf("unsupported type: %d", dataType)
+	return nil, &utils.ErrMalformed{What: "contract value", Msg: fmt.Sprintf("unsupported type: %d", dataType)}
+}
+
+// encodedSize returns the number of bytes of the value encoded at the start of data. Arrays and maps
+// store their element count in the length field and the byte size right after it.
+func encodedSize(data []byte) (int, error) {
+	if len(data) < 5 {
+		return 0, &utils.ErrMalformed{What: "contract value", Msg: "data too short to decode"}
+	}
+	size := 5 + int(binary.BigEndian.Uint32(data[1:5]))
+	if dataType := ContractDataType(data[0]); dataType == TypeArray || dataType == TypeMap {
+		if len(data) < 9 {
+			return 0, &utils.ErrMalformed{What: "contract value", Msg: "data too short to decode"}
+		}
+		size = 9 + int(binary.BigEndian.Uint32(data[5:9]))
+	}
+	if size > len(data) {
+		return 0, &utils.ErrMalformed{What: "contract value", Msg: fmt.Sprintf("value needs %d bytes, got %d", size, len(data))}
+	}
+	return size, nil
 }
 
 func GetType(data []byte) (ContractDataType, error) {
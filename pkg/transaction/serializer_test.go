@@ -0,0 +1,98 @@
+package transaction_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// decodeNoPanic fails the test when Decode panics instead of returning an error
+func decodeNoPanic(t *testing.T, data []byte) (err error) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != nil {
+			t.Fatalf("Decode(%x) panicked: %v", data, r)
+		}
+	}()
+	_, err = transaction.Decode(data)
+	return err
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	encoded, err := transaction.Encode(map[string]interface{}{
+		"name":   "sensor",
+		"values": []interface{}{int32(1), int64(2), 3.5, true, nil},
+		"raw":    []byte{1, 2, 3},
+	})
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := decodeNoPanic(t, encoded); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	// Every truncation and every corrupted byte either decodes or fails, none panics
+	for i := range encoded {
+		decodeNoPanic(t, encoded[:i])
+		corrupted := append([]byte{}, encoded...)
+		for _, b := range []byte{0x00, 0x7f, 0xff} {
+			corrupted[i] = b
+			decodeNoPanic(t, corrupted)
+		}
+	}
+
+	for name, data := range map[string][]byte{
+		"empty":           {},
+		"short header":    {byte(transaction.TypeString), 0, 0},
+		"truncated int":   {byte(transaction.TypeInt64), 0, 0, 0, 8, 1},
+		"truncated array": {byte(transaction.TypeArray), 0, 0, 0, 9, 1},
+		"huge map":        {byte(transaction.TypeMap), 0xff, 0xff, 0xff, 0x7f},
+		"huge array":      {byte(transaction.TypeArray), 0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0},
+		"unknown type":    {0xee, 0, 0, 0, 0},
+	} {
+		if err := decodeNoPanic(t, data); !errors.Is(err, utils.ErrInvalidInput) {
+			t.Errorf("Decode() of %s error = %v, want an invalid input", name, err)
+		}
+	}
+}
+
+func TestKeylessSession(t *testing.T) {
+	node, _ := newMockSession(t)
+	session, err := transaction.NewUL_TransactionSession(node.URL(), wallet.UL_Wallet{})
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+
+	var noKey *wallet.ErrNoKey
+	_, err = session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		Payload:      "{}",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if !errors.As(err, &noKey) {
+		t.Fatalf("GenerateTransaction() of a keyless session error = %v, want ErrNoKey", err)
+	}
+	if _, err := transaction.RegisterWalletInput(testBlockchainId, wallet.UL_Wallet{}); !errors.As(err, &noKey) {
+		t.Fatalf("RegisterWalletInput() of a keyless wallet error = %v, want ErrNoKey", err)
+	}
+}
+
+func TestNodeErrorCategory(t *testing.T) {
+	for status, want := range map[int]error{
+		http.StatusBadRequest:         utils.ErrInvalidInput,
+		http.StatusForbidden:          utils.ErrRejected,
+		http.StatusNotFound:           utils.ErrNotFound,
+		http.StatusTooManyRequests:    utils.ErrUnavailable,
+		http.StatusNotImplemented:     utils.ErrUnsupported,
+		http.StatusServiceUnavailable: utils.ErrUnavailable,
+	} {
+		err := error(&transaction.NodeError{StatusCode: status})
+		if got := utils.Category(err); got != want {
+			t.Errorf("Category() of status %d = %v, want %v", status, got, want)
+		}
+	}
+}
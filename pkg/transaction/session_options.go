@@ -0,0 +1,122 @@
+package transaction
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// DEFAULT_USER_AGENT identifies the SDK to nodes and the gateways in front of them
+const DEFAULT_USER_AGENT = "uledger-go-sdk"
+
+// SessionOptions configure how a session reaches its node. Sessions created without transport
+// options share one client, so sessions talking to the same node reuse each other's connections.
+type SessionOptions struct {
+	// HTTPClient sends every request as is, Transport, TLS, Proxy and the timeouts are then ignored
+	HTTPClient *http.Client
+	// Transport tunes the connections, defaults to DEFAULT_TRANSPORT_CONFIG
+	Transport *TransportConfig
+	// TLS replaces the TLS config of Transport, e.g. to trust the private CA of a node
+	TLS *tls.Config
+	// Proxy selects the proxy of each request, defaults to the HTTP_PROXY and HTTPS_PROXY variables
+	Proxy func(*http.Request) (*url.URL, error)
+	// Timeout bounds every request attempt including reading the response, zero means no limit
+	Timeout time.Duration
+	// DialTimeout bounds opening a connection, zero keeps the transport default
+	DialTimeout time.Duration
+	// UserAgent defaults to DEFAULT_USER_AGENT
+	UserAgent string
+	// Headers are sent with every request, those made while creating the session included
+	Headers http.Header
+	// IdempotencyStore records the keys of GenerateIdempotentTransaction, which fails without one.
+	// Use a durable store such as a FileIdempotencyStore for keys to survive restarts.
+	IdempotencyStore IdempotencyStore
+}
+
+var defaultHTTPClient = &http.Client{Transport: NewTransport(DEFAULT_TRANSPORT_CONFIG)}
+
+// client returns the client the options describe and whether the session owns it
+func (opts SessionOptions) client() (*http.Client, bool) {
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient, false
+	}
+	if opts.Transport == nil && opts.TLS == nil && opts.Proxy == nil && opts.Timeout == 0 && opts.DialTimeout == 0 {
+		return defaultHTTPClient, false
+	}
+
+	config := DEFAULT_TRANSPORT_CONFIG
+	if opts.Transport != nil {
+		config = *opts.Transport
+	}
+	if opts.TLS != nil {
+		config.TLS = opts.TLS
+	}
+	transport := NewTransport(config)
+	if opts.Proxy != nil {
+		transport.Proxy = opts.Proxy
+	}
+	if opts.DialTimeout > 0 {
+		transport.DialContext = (&net.Dialer{Timeout: opts.DialTimeout, KeepAlive: 30 * time.Second}).DialContext
+	}
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}, true
+}
+
+// newSession connects a session configured by opts to the node, then opens the connections
+// Transport asks to warm up
+func newSession(ctx context.Context, nodeEndpoint string, wallet wallet.UL_Wallet, opts SessionOptions) (UL_TransactionSession, error) {
+	session := unconnectedSession(nodeEndpoint, wallet, opts)
+	fail := func(err error) (UL_TransactionSession, error) {
+		if session.ownsClient {
+			session.httpClient.CloseIdleConnections()
+		}
+		return UL_TransactionSession{}, err
+	}
+	if err := session.connect(ctx); err != nil {
+		return fail(err)
+	}
+	// connect already opened one connection
+	if opts.HTTPClient == nil && opts.Transport != nil && opts.Transport.WarmupConnections > 1 {
+		if err := session.Warmup(ctx, opts.Transport.WarmupConnections); err != nil {
+			return fail(err)
+		}
+	}
+	return session, nil
+}
+
+// unconnectedSession returns a session configured by opts that has not contacted the node yet
+func unconnectedSession(nodeEndpoint string, wallet wallet.UL_Wallet, opts SessionOptions) UL_TransactionSession {
+	client, owned := opts.client()
+	session := UL_TransactionSession{
+		nodeEndpoint: nodeEndpoint,
+		wallet:       wallet,
+		metrics:      &sessionMetrics{},
+		httpClient:   client,
+		ownsClient:   owned,
+		headers:      opts.Headers.Clone(),
+		idempotency:  newIdempotency(opts.IdempotencyStore),
+	}
+	userAgent := opts.UserAgent
+	if userAgent == "" {
+		userAgent = DEFAULT_USER_AGENT
+	}
+	session.SetHeader("User-Agent", userAgent)
+	return session
+}
+
+// sessionOptions returns the options passed to a constructor, at most one set is accepted
+func sessionOptions(opts []SessionOptions) (SessionOptions, error) {
+	switch len(opts) {
+	case 0:
+		return SessionOptions{}, nil
+	case 1:
+		return opts[0], nil
+	default:
+		return SessionOptions{}, fmt.Errorf("at most one SessionOptions may be passed, got %d", len(opts))
+	}
+}
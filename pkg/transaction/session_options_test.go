@@ -0,0 +1,123 @@
+package transaction_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// countingTransport counts the requests of a client supplied through SessionOptions
+type countingTransport struct {
+	requests atomic.Int32
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSessionOptions(t *testing.T) {
+	ctx := context.Background()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+
+	server, connections := transportNode(t)
+	var mu sync.Mutex
+	var seen []*http.Request
+	handler := server.Config.Handler
+	server.Config.Handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, r)
+		mu.Unlock()
+		handler.ServeHTTP(rw, r)
+	})
+	server.Start()
+	lastRequest := func() *http.Request {
+		mu.Lock()
+		defer mu.Unlock()
+		return seen[len(seen)-1]
+	}
+
+	t.Run("connections are reused", func(t *testing.T) {
+		before := connections.Load()
+		session, err := transaction.NewUL_TransactionSession(server.URL, w)
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSession() error = %v", err)
+		}
+		for range 5 {
+			if err := session.Do(ctx, http.MethodGet, "/health", nil, nil); err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+		}
+		if _, err := transaction.NewUL_TransactionSession(server.URL, w); err != nil {
+			t.Fatalf("NewUL_TransactionSession() error = %v", err)
+		}
+		if opened := connections.Load() - before; opened != 1 {
+			t.Fatalf("two sessions opened %d connections, want 1", opened)
+		}
+		if got := lastRequest().Header.Get("User-Agent"); got != transaction.DEFAULT_USER_AGENT {
+			t.Fatalf("User-Agent = %q", got)
+		}
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		_, err := transaction.NewUL_TransactionSession(server.URL, w, transaction.SessionOptions{
+			UserAgent: "indexer/2.1",
+			Headers:   http.Header{"Authorization": {"Bearer token"}},
+		})
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSession() error = %v", err)
+		}
+		// The requests made while creating the session carry them too
+		mu.Lock()
+		defer mu.Unlock()
+		for _, r := range seen {
+			if r.URL.Path == "/health" && r.Header.Get("User-Agent") == "indexer/2.1" && r.Header.Get("Authorization") == "Bearer token" {
+				return
+			}
+		}
+		t.Fatalf("no /health request carried the configured headers")
+	})
+
+	t.Run("http client", func(t *testing.T) {
+		transport := &countingTransport{}
+		session, err := transaction.NewUL_TransactionSession(server.URL, w, transaction.SessionOptions{
+			HTTPClient: &http.Client{Transport: transport},
+		})
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSession() error = %v", err)
+		}
+		if err := session.Do(ctx, http.MethodGet, "/health", nil, nil); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		if got := transport.requests.Load(); got != 3 {
+			t.Fatalf("the supplied client sent %d requests, want 3", got)
+		}
+	})
+
+	t.Run("proxy", func(t *testing.T) {
+		proxy, _ := url.Parse(server.URL)
+		_, err := transaction.NewUL_TransactionSession("http://node.invalid", w, transaction.SessionOptions{
+			Proxy: http.ProxyURL(proxy),
+		})
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSession() through a proxy error = %v", err)
+		}
+		if got := lastRequest().Host; got != "node.invalid" {
+			t.Fatalf("the proxy received a request for %q", got)
+		}
+	})
+
+	if _, err := transaction.NewUL_TransactionSession(server.URL, w, transaction.SessionOptions{}, transaction.SessionOptions{}); err == nil {
+		t.Fatalf("NewUL_TransactionSession() accepted two SessionOptions")
+	}
+}
@@ -0,0 +1,79 @@
+package transaction
+
+import (
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// MAX_BOUND_PAYLOAD_SIZE is the largest payload the hard bound signature commitment can hold
+const MAX_BOUND_PAYLOAD_SIZE = CHUNK_SIZE * (1 << DEPTH)
+
+// ErrPayloadTooLarge is returned before signing when a payload does not fit the signature commitment
+type ErrPayloadTooLarge struct {
+	PayloadType string
+	Limit       int
+	Size        int
+	// Chunks is the number of payloads of at most Limit bytes the payload splits into
+	Chunks     int
+	Suggestion string
+}
+
+func (e *ErrPayloadTooLarge) Error() string {
+	msg := fmt.Sprintf("payload is too large, max size is %d bytes, got %d bytes", e.Limit, e.Size)
+	if e.PayloadType != "" {
+		msg = fmt.Sprintf("%s payload is too large, max size is %d bytes, got %d bytes", e.PayloadType, e.Limit, e.Size)
+	}
+	if e.Suggestion != "" {
+		msg += ": " + e.Suggestion
+	}
+	return msg
+}
+
+func (e *ErrPayloadTooLarge) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// MaxPayloadSize returns the payload limit of a payload type under COMMITMENT_SCHEME_MIMC in bytes,
+// -1 when the type signs an unbound commitment and has no limit
+func MaxPayloadSize(payloadType string) int {
+	input := ULTransactionInput{PayloadType: payloadType}
+	if input.IsUnbound() {
+		return -1
+	}
+	return MAX_BOUND_PAYLOAD_SIZE
+}
+
+// CheckPayloadSize fails with ErrPayloadTooLarge when the payload does not fit the commitment of
+// its type, so oversized payloads are reported before any Merkle tree is built. Only the hard bound
+// tree of COMMITMENT_SCHEME_MIMC is limited, other schemes sign payloads of any size.
+func (t *ULTransactionInput) CheckPayloadSize() error {
+	if t.CommitmentScheme != "" && t.CommitmentScheme != COMMITMENT_SCHEME_MIMC {
+		return nil
+	}
+	limit := MaxPayloadSize(t.PayloadType)
+	if limit < 0 || len(t.Payload) <= limit {
+		return nil
+	}
+	return newErrPayloadTooLarge(t.PayloadType, limit, len(t.Payload))
+}
+
+func newErrPayloadTooLarge(payloadType string, limit int, size int) *ErrPayloadTooLarge {
+	chunks := (size + limit - 1) / limit
+	err := &ErrPayloadTooLarge{PayloadType: payloadType, Limit: limit, Size: size, Chunks: chunks}
+
+	switch payloadType {
+	case TX_DATA.String():
+		err.Suggestion = fmt.Sprintf("split it into %d %s transactions of at most %d bytes or record only its hash", chunks, payloadType, limit)
+	case INVOKE_SMART_CONTRACT.String():
+		err.Suggestion = fmt.Sprintf("pass large arguments through contract storage in calls of at most %d bytes", limit)
+	case "":
+	default:
+		if _, ok := GetPayloadType(payloadType); ok {
+			break
+		}
+		err.Suggestion = fmt.Sprintf("large contract sources are deployed with the unbound %s type and UploadContractSource, other payloads must stay within %d bytes",
+			DEPLOY_SMART_CONTRACT, limit)
+	}
+	return err
+}
@@ -0,0 +1,46 @@
+package transaction_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+func TestPayloadTooLarge(t *testing.T) {
+	node, session := newMockSession(t)
+	failures := 0
+	session.SetHooks(transaction.SessionHooks{OnError: func(event transaction.ErrorEvent) {
+		if event.Stage == transaction.HOOK_STAGE_PREPARE {
+			failures++
+		}
+	}})
+
+	_, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      strings.Repeat("a", transaction.MAX_BOUND_PAYLOAD_SIZE*2+1),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	var tooLarge *transaction.ErrPayloadTooLarge
+	if !errors.As(err, &tooLarge) {
+		t.Fatalf("GenerateTransaction() error = %v, want ErrPayloadTooLarge", err)
+	}
+	if tooLarge.Limit != 1024 || tooLarge.Size != 2049 || tooLarge.Chunks != 3 || tooLarge.Suggestion == "" {
+		t.Fatalf("ErrPayloadTooLarge = %+v", tooLarge)
+	}
+	if failures != 1 || len(node.Transactions()) != 0 {
+		t.Fatalf("the payload reached the node or skipped the prepare hook")
+	}
+
+	// A payload at the limit and unbound payloads of any size are signed
+	fits := transaction.ULTransactionInput{Payload: strings.Repeat("a", transaction.MAX_BOUND_PAYLOAD_SIZE), PayloadType: transaction.TX_DATA.String()}
+	if err := fits.CheckPayloadSize(); err != nil {
+		t.Fatalf("CheckPayloadSize() at the limit error = %v", err)
+	}
+	deploy := transaction.ULTransactionInput{Payload: strings.Repeat("a", 10*transaction.MAX_BOUND_PAYLOAD_SIZE), PayloadType: transaction.DEPLOY_SMART_CONTRACT.String()}
+	if err := deploy.CheckPayloadSize(); err != nil || transaction.MaxPayloadSize(deploy.PayloadType) != -1 {
+		t.Fatalf("CheckPayloadSize() of a deploy error = %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// SESSION_SNAPSHOT_VERSION is the format of the snapshots Snapshot takes, RestoreSession refuses others
+const SESSION_SNAPSHOT_VERSION = 1
+
+// NodeInfo is what a session learns about its node when it connects
+type NodeInfo struct {
+	NodeId   string   `json:"nodeId"`
+	Version  string   `json:"version"`
+	Features []string `json:"features,omitempty"`
+	Chains   []string `json:"chains"`
+}
+
+// ErrSnapshotMismatch is returned when a snapshot cannot restore the session asked for
+type ErrSnapshotMismatch struct {
+	Msg string
+}
+
+func (e *ErrSnapshotMismatch) Error() string {
+	return fmt.Sprintf("session snapshot mismatch, %s", e.Msg)
+}
+
+func (e *ErrSnapshotMismatch) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// SessionSnapshot is the state a session and its helpers rebuild from the node when a process
+// starts. Services save it on shutdown and restore it on the next start instead of handshaking and
+// re-scanning from scratch. It holds no key material.
+type SessionSnapshot struct {
+	Version       int       `json:"version"`
+	TakenAt       time.Time `json:"takenAt"`
+	NodeEndpoint  string    `json:"nodeEndpoint"`
+	WalletAddress string    `json:"walletAddress"`
+	Suggestor     string    `json:"suggestor"`
+	Node          NodeInfo  `json:"node"`
+	// Submitted is the duplicate cache of a Preflighter, submission identities by expiry
+	Submitted map[string]time.Time `json:"submitted,omitempty"`
+	// PreflightVersion is the node version the Preflighter selected its rules for
+	PreflightVersion string `json:"preflightVersion,omitempty"`
+	// Checkpoints are the block consumer cursors of a MemoryCheckpointStore
+	Checkpoints map[string]Checkpoint `json:"checkpoints,omitempty"`
+}
+
+// SnapshotParts are the helpers of a session whose state is kept in its snapshot, nil ones are
+// left out
+type SnapshotParts struct {
+	Preflighter *Preflighter
+	Checkpoints *MemoryCheckpointStore
+}
+
+// NodeInfo returns what the session learned about its node when it connected or was restored
+func (session *UL_TransactionSession) NodeInfo() NodeInfo {
+	return session.node
+}
+
+// Refresh asks the node for its metadata again, e.g. after restoring a session from an old snapshot
+func (session *UL_TransactionSession) Refresh(ctx context.Context) error {
+	return session.connect(ctx)
+}
+
+// Snapshot captures the state of the session and of parts, see RestoreSession
+func (session *UL_TransactionSession) Snapshot(parts SnapshotParts) SessionSnapshot {
+	snapshot := SessionSnapshot{
+		Version:       SESSION_SNAPSHOT_VERSION,
+		TakenAt:       time.Now().UTC(),
+		NodeEndpoint:  session.nodeEndpoint,
+		WalletAddress: session.wallet.Address,
+		Suggestor:     session.suggestor,
+		Node:          session.node,
+	}
+	snapshot.Node.Features = slices.Clone(session.node.Features)
+	snapshot.Node.Chains = slices.Clone(session.node.Chains)
+
+	if p := parts.Preflighter; p != nil {
+		p.mu.Lock()
+		now := time.Now()
+		snapshot.Submitted = make(map[string]time.Time, len(p.submitted))
+		for identity, expiry := range p.submitted {
+			if expiry.After(now) {
+				snapshot.Submitted[identity] = expiry
+			}
+		}
+		p.mu.Unlock()
+		snapshot.PreflightVersion = p.version
+	}
+	if store := parts.Checkpoints; store != nil {
+		store.mu.Lock()
+		snapshot.Checkpoints = maps.Clone(store.checkpoints)
+		store.mu.Unlock()
+	}
+	return snapshot
+}
+
+// RestoreSession returns a session of wallet at nodeEndpoint as snapshot left it, without contacting
+// the node. The snapshot must have been taken of a session of the same endpoint and wallet.
+func RestoreSession(nodeEndpoint string, wallet wallet.UL_Wallet, snapshot SessionSnapshot, opts ...SessionOptions) (UL_TransactionSession, error) {
+	options, err := sessionOptions(opts)
+	if err != nil {
+		return UL_TransactionSession{}, err
+	}
+	switch {
+	case snapshot.Version != SESSION_SNAPSHOT_VERSION:
+		return UL_TransactionSession{}, &ErrSnapshotMismatch{Msg: fmt.Sprintf("version %d is not %d", snapshot.Version, SESSION_SNAPSHOT_VERSION)}
+	case snapshot.NodeEndpoint != nodeEndpoint:
+		return UL_TransactionSession{}, &ErrSnapshotMismatch{Msg: fmt.Sprintf("it was taken of %s", snapshot.NodeEndpoint)}
+	case snapshot.WalletAddress != wallet.Address:
+		return UL_TransactionSession{}, &ErrSnapshotMismatch{Msg: fmt.Sprintf("it was taken of the wallet %s", snapshot.WalletAddress)}
+	}
+
+	session := unconnectedSession(nodeEndpoint, wallet, options)
+	session.suggestor = snapshot.Suggestor
+	session.node = snapshot.Node
+	return session, nil
+}
+
+// RestorePreflighter returns a Preflighter of session with the rules of the node version and the
+// duplicate cache of snapshot, without contacting the node. nil rules uses DEFAULT_PREFLIGHT_RULES.
+func RestorePreflighter(session *UL_TransactionSession, rules []PreflightRules, snapshot SessionSnapshot) *Preflighter {
+	if rules == nil {
+		rules = DEFAULT_PREFLIGHT_RULES
+	}
+	version := snapshot.PreflightVersion
+	if version == "" {
+		version = snapshot.Node.Version
+	}
+	submitted := make(map[string]time.Time, len(snapshot.Submitted))
+	now := time.Now()
+	for identity, expiry := range snapshot.Submitted {
+		if expiry.After(now) {
+			submitted[identity] = expiry
+		}
+	}
+	return &Preflighter{
+		session:   session,
+		rules:     SelectPreflightRules(rules, version),
+		version:   version,
+		submitted: submitted,
+	}
+}
+
+// RestoreCheckpoints saves the checkpoints of snapshot to store
+func (snapshot SessionSnapshot) RestoreCheckpoints(store CheckpointStore) error {
+	for key, checkpoint := range snapshot.Checkpoints {
+		if err := store.SaveCheckpoint(key, checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveSessionSnapshot writes snapshot to path as JSON, readable by the owner only
+func SaveSessionSnapshot(path string, snapshot SessionSnapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session snapshot: %w", err)
+	}
+	// Write then rename so a crash never leaves a half written snapshot behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write session snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to commit session snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSessionSnapshot reads a snapshot written by SaveSessionSnapshot, a missing file fails with an
+// error matching os.ErrNotExist
+func LoadSessionSnapshot(path string) (SessionSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return SessionSnapshot{}, fmt.Errorf("failed to read session snapshot: %w", err)
+	}
+	snapshot := SessionSnapshot{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return SessionSnapshot{}, fmt.Errorf("failed to parse session snapshot: %w", err)
+	}
+	return snapshot, nil
+}
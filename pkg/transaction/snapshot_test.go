@@ -0,0 +1,92 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestSessionSnapshot(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	node.SetFeatures(transaction.NODE_FEATURE_TRANSACTION_REPLACEMENT)
+	if err := session.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	preflighter, err := transaction.NewPreflighter(ctx, session, nil)
+	if err != nil {
+		t.Fatalf("NewPreflighter() error = %v", err)
+	}
+	checkpoints := transaction.NewMemoryCheckpointStore()
+	key := transaction.CheckpointKey(testBlockchainId, "indexer")
+	checkpoints.SaveCheckpoint(key, transaction.Checkpoint{Height: 42, Hash: "abc"})
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	if err := transaction.SaveSessionSnapshot(path, session.Snapshot(transaction.SnapshotParts{Preflighter: preflighter, Checkpoints: checkpoints})); err != nil {
+		t.Fatalf("SaveSessionSnapshot() error = %v", err)
+	}
+	snapshot, err := transaction.LoadSessionSnapshot(path)
+	if err != nil {
+		t.Fatalf("LoadSessionSnapshot() error = %v", err)
+	}
+
+	// The node is gone, a restored session does not need it
+	node.Close()
+	restored, err := transaction.RestoreSession(node.URL(), session.GetWallet(), snapshot)
+	if err != nil {
+		t.Fatalf("RestoreSession() error = %v", err)
+	}
+	info := restored.NodeInfo()
+	if restored.GetSuggestor() != transactiontest.MOCK_NODE_ID || info.Version != transactiontest.MOCK_NODE_VERSION || !slices.Contains(info.Features, transaction.NODE_FEATURE_TRANSACTION_REPLACEMENT) || !slices.Equal(info.Chains, []string{testBlockchainId}) {
+		t.Fatalf("RestoreSession() node = %+v, suggestor %q", info, restored.GetSuggestor())
+	}
+
+	store := transaction.NewMemoryCheckpointStore()
+	if err := snapshot.RestoreCheckpoints(store); err != nil {
+		t.Fatalf("RestoreCheckpoints() error = %v", err)
+	}
+	if checkpoint, ok, _ := store.LoadCheckpoint(key); !ok || checkpoint.Height != 42 {
+		t.Fatalf("LoadCheckpoint() after RestoreCheckpoints = %+v, %v", checkpoint, ok)
+	}
+
+	// Expired identities are not worth restoring
+	snapshot.Submitted = map[string]time.Time{"recent": time.Now().Add(time.Hour), "expired": time.Now().Add(-time.Second)}
+	restoredPreflighter := transaction.RestorePreflighter(&restored, nil, snapshot)
+	if restoredPreflighter.Rules().MinNodeVersion != preflighter.Rules().MinNodeVersion {
+		t.Fatalf("RestorePreflighter() rules = %+v, want %+v", restoredPreflighter.Rules(), preflighter.Rules())
+	}
+	again := restored.Snapshot(transaction.SnapshotParts{Preflighter: restoredPreflighter})
+	if _, ok := again.Submitted["recent"]; !ok || len(again.Submitted) != 1 {
+		t.Fatalf("Snapshot() of the restored preflighter submitted = %v", again.Submitted)
+	}
+}
+
+func TestRestoreSessionMismatch(t *testing.T) {
+	node, session := newMockSession(t)
+	snapshot := session.Snapshot(transaction.SnapshotParts{})
+	other, _ := newMockSession(t)
+
+	if _, err := transaction.RestoreSession(other.URL(), session.GetWallet(), snapshot); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("RestoreSession() of another node error = %v", err)
+	}
+	peer := newPeerSession(t, node)
+	if _, err := transaction.RestoreSession(node.URL(), peer.GetWallet(), snapshot); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("RestoreSession() of another wallet error = %v", err)
+	}
+	snapshot.Version++
+	var mismatch *transaction.ErrSnapshotMismatch
+	if _, err := transaction.RestoreSession(node.URL(), session.GetWallet(), snapshot); !errors.As(err, &mismatch) {
+		t.Fatalf("RestoreSession() of a newer snapshot error = %v", err)
+	}
+	if _, err := transaction.LoadSessionSnapshot(filepath.Join(t.TempDir(), "missing.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("LoadSessionSnapshot() of a missing file error = %v", err)
+	}
+}
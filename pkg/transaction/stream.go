@@ -0,0 +1,146 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	DEFAULT_POLL_INTERVAL = 2 * time.Second
+)
+
+// BlockHandler processes a single block. Returning an error leaves the checkpoint untouched
+// and the same block is delivered again after the poll interval.
+type BlockHandler func(ctx context.Context, block ULBlock) error
+
+type BlockSubscriptionOptions struct {
+	// Consumer names the consumer group, each group keeps its own checkpoint
+	Consumer string
+	// StartHeight is used when the consumer has no checkpoint yet, heights start at 1 which is the default
+	StartHeight int
+	// PollInterval is how often the node is asked for new blocks
+	PollInterval time.Duration
+	// Store persists the checkpoint, defaults to an in memory store
+	Store CheckpointStore
+	// OnError is told about failures the subscription recovers from by polling again
+	OnError func(err error)
+}
+
+// ErrCheckpointMismatch is returned when the block after a checkpoint does not extend it,
+// which means the chain the consumer processed is not the one the node is serving now
+type ErrCheckpointMismatch struct {
+	Checkpoint        Checkpoint
+	PreviousBlockHash string
+}
+
+func (e *ErrCheckpointMismatch) Error() string {
+	return fmt.Sprintf("block %d does not extend checkpoint %s, previous block hash is %s", e.Checkpoint.Height+1, e.Checkpoint.Hash, e.PreviousBlockHash)
+}
+
+func (e *ErrCheckpointMismatch) Is(target error) bool {
+	return target == utils.ErrConflict
+}
+
+// CheckpointKey is the key a consumer's progress is stored under
+func CheckpointKey(blockchainId string, consumer string) string {
+	return fmt.Sprintf("%s/%s", blockchainId, consumer)
+}
+
+// SubscribeBlocks delivers every block of the chain to handler in order, at least once.
+// The checkpoint only advances after handler succeeds, so a crash or a reconnect replays
+// from the last processed block. It blocks until the context is cancelled.
+func (session *UL_TransactionSession) SubscribeBlocks(ctx context.Context, blockchainId string, opts BlockSubscriptionOptions, handler BlockHandler) error {
+	if opts.Consumer == "" {
+		return fmt.Errorf("a consumer name is required")
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_POLL_INTERVAL
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryCheckpointStore()
+	}
+	if opts.StartHeight < 1 {
+		opts.StartHeight = 1
+	}
+
+	sub := &blockSubscription{
+		session:      session,
+		blockchainId: blockchainId,
+		key:          CheckpointKey(blockchainId, opts.Consumer),
+		store:        opts.Store,
+		handler:      handler,
+		next:         opts.StartHeight,
+	}
+
+	checkpoint, found, err := sub.store.LoadCheckpoint(sub.key)
+	if err != nil {
+		return err
+	}
+	if found {
+		sub.checkpoint = checkpoint
+		sub.next = checkpoint.Height + 1
+	}
+
+	for {
+		if err := sub.poll(ctx); err != nil {
+			var mismatch *ErrCheckpointMismatch
+			if errors.As(err, &mismatch) {
+				return err
+			}
+			if opts.OnError != nil && ctx.Err() == nil {
+				opts.OnError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(opts.PollInterval):
+		}
+	}
+}
+
+type blockSubscription struct {
+	session      *UL_TransactionSession
+	blockchainId string
+	key          string
+	store        CheckpointStore
+	handler      BlockHandler
+	next         int
+	checkpoint   Checkpoint
+}
+
+// poll hands every block up to the current chain height to the handler, stopping at the first failure
+func (sub *blockSubscription) poll(ctx context.Context) error {
+	chain, err := sub.session.getChainInfo(ctx, sub.blockchainId)
+	if err != nil {
+		return err
+	}
+
+	for sub.next <= chain.Height {
+		block, err := sub.session.GetBlock(ctx, sub.blockchainId, sub.next)
+		if err != nil {
+			return err
+		}
+
+		if sub.checkpoint.Hash != "" && block.PreviousBlockHash != sub.checkpoint.Hash {
+			return &ErrCheckpointMismatch{Checkpoint: sub.checkpoint, PreviousBlockHash: block.PreviousBlockHash}
+		}
+
+		if err := sub.handler(ctx, block); err != nil {
+			return err
+		}
+
+		checkpoint := Checkpoint{Height: block.Height, Hash: block.Hash, UpdatedAt: time.Now().UTC()}
+		if err := sub.store.SaveCheckpoint(sub.key, checkpoint); err != nil {
+			return err
+		}
+		sub.checkpoint = checkpoint
+		sub.next = block.Height + 1
+	}
+	return nil
+}
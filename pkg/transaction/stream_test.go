@@ -0,0 +1,154 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+func newMockSession(t *testing.T) (*transactiontest.MockNode, *transaction.UL_TransactionSession) {
+	t.Helper()
+	privateKeyHex := "63f6062f2034bcbcc08bae2eaabee8dd780d352cd76c595dce3a631ce8877934"
+	publicKeyHex := "04f2f0fd15ba3a7f4ba62cd705c4df8094917e7e85cab345beaf0b378f84a3422ced9a9cf925c05ded76c63ab677207287a5b64b2fb683803abef934259fa37c5d"
+	w, err := wallet.GetWalletFromHex(publicKeyHex, privateKeyHex, crypto.KeyTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("GetWalletFromHex() error = %v", err)
+	}
+
+	node := transactiontest.NewMockNode(testBlockchainId)
+	t.Cleanup(node.Close)
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	return node, &session
+}
+
+func submitData(t *testing.T, session *transaction.UL_TransactionSession, payload string) transaction.ULTransaction {
+	t.Helper()
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      payload,
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("GenerateTransaction() error = %v", err)
+	}
+	return tx
+}
+
+// consume runs a subscription until want blocks were handled
+func consume(t *testing.T, session *transaction.UL_TransactionSession, opts transaction.BlockSubscriptionOptions, want int, handler transaction.BlockHandler) []int {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	heights := make([]int, 0)
+	err := session.SubscribeBlocks(ctx, testBlockchainId, opts, func(ctx context.Context, block transaction.ULBlock) error {
+		if err := handler(ctx, block); err != nil {
+			return err
+		}
+		heights = append(heights, block.Height)
+		if len(heights) == want {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("SubscribeBlocks() error = %v, handled %v", err, heights)
+	}
+	return heights
+}
+
+func TestSubscribeBlocksReplaysFromCheckpoint(t *testing.T) {
+	_, session := newMockSession(t)
+	for _, payload := range []string{"one", "two", "three"} {
+		submitData(t, session, payload)
+	}
+
+	store := transaction.NewMemoryCheckpointStore()
+	// Without a StartHeight the subscription starts at the first block
+	opts := transaction.BlockSubscriptionOptions{
+		Consumer:     "indexer",
+		PollInterval: 10 * time.Millisecond,
+		Store:        store,
+	}
+
+	failed := false
+	heights := consume(t, session, opts, 3, func(ctx context.Context, block transaction.ULBlock) error {
+		// The first attempt at block 2 fails and must be delivered again
+		if block.Height == 2 && !failed {
+			failed = true
+			return errors.New("transient failure")
+		}
+		return nil
+	})
+	if len(heights) != 3 || heights[0] != 1 || heights[1] != 2 || heights[2] != 3 {
+		t.Fatalf("SubscribeBlocks() delivered heights %v, want [1 2 3]", heights)
+	}
+
+	checkpoint, found, err := store.LoadCheckpoint(transaction.CheckpointKey(testBlockchainId, "indexer"))
+	if err != nil || !found || checkpoint.Height != 3 {
+		t.Fatalf("LoadCheckpoint() = %+v, %v, %v, want height 3", checkpoint, found, err)
+	}
+
+	// A restarted consumer only sees the blocks produced after its checkpoint
+	submitData(t, session, "four")
+	heights = consume(t, session, opts, 1, func(ctx context.Context, block transaction.ULBlock) error { return nil })
+	if heights[0] != 4 {
+		t.Errorf("SubscribeBlocks() after restart delivered %v, want [4]", heights)
+	}
+}
+
+func TestSubscribeBlocksStopsAtReorg(t *testing.T) {
+	_, session := newMockSession(t)
+	submitData(t, session, "one")
+	submitData(t, session, "two")
+
+	// The consumer processed a block 1 the node no longer serves
+	store := transaction.NewMemoryCheckpointStore()
+	if err := store.SaveCheckpoint(transaction.CheckpointKey(testBlockchainId, "indexer"), transaction.Checkpoint{Height: 1, Hash: "replaced"}); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	opts := transaction.BlockSubscriptionOptions{Consumer: "indexer", PollInterval: 10 * time.Millisecond, Store: store}
+	err := session.SubscribeBlocks(ctx, testBlockchainId, opts, func(ctx context.Context, block transaction.ULBlock) error {
+		t.Fatalf("SubscribeBlocks() delivered block %d past a mismatching checkpoint", block.Height)
+		return nil
+	})
+	var mismatch *transaction.ErrCheckpointMismatch
+	if !errors.As(err, &mismatch) || !errors.Is(err, utils.ErrConflict) || errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("SubscribeBlocks() error = %v, want a conflict", err)
+	}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store, err := transaction.NewFileCheckpointStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileCheckpointStore() error = %v", err)
+	}
+
+	if _, found, err := store.LoadCheckpoint("chain/consumer"); found || err != nil {
+		t.Fatalf("LoadCheckpoint() on empty store = %v, %v", found, err)
+	}
+
+	want := transaction.Checkpoint{Height: 7, Hash: "abc"}
+	if err := store.SaveCheckpoint("chain/consumer", want); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+	got, found, err := store.LoadCheckpoint("chain/consumer")
+	if err != nil || !found || got.Height != want.Height || got.Hash != want.Hash {
+		t.Errorf("LoadCheckpoint() = %+v, %v, %v, want %+v", got, found, err, want)
+	}
+}
@@ -0,0 +1,100 @@
+package transaction
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrInvalidTokenPayload is returned before submitting a token payload the node would refuse
+type ErrInvalidTokenPayload struct {
+	PayloadType string
+	Msg         string
+}
+
+func (e *ErrInvalidTokenPayload) Error() string {
+	return fmt.Sprintf("invalid %s payload, %s", e.PayloadType, e.Msg)
+}
+
+func (e *ErrInvalidTokenPayload) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Validate checks the burn is well formed. Amount burns fungible units, a zero Amount burns the
+// ERC721 token TokenId. From burns on behalf of another account, which needs its approval.
+func (p BurnTokenPayload) Validate() error {
+	if !isAddress(p.TokenAddress) {
+		return &ErrInvalidTokenPayload{PayloadType: BURN_TOKEN.String(), Msg: fmt.Sprintf("invalid token address %q", p.TokenAddress)}
+	}
+	if p.From != "" && !isAddress(p.From) {
+		return &ErrInvalidTokenPayload{PayloadType: BURN_TOKEN.String(), Msg: fmt.Sprintf("invalid owner address %q", p.From)}
+	}
+	return nil
+}
+
+// Burn destroys amount units of a token held by the session's wallet, or the ERC721 token tokenId
+// when amount is zero
+func (session *UL_TransactionSession) Burn(ctx context.Context, blockchainId string, tokenAddress string, amount Amount, tokenId uint64) (ULTransaction, error) {
+	return session.BurnFrom(ctx, blockchainId, tokenAddress, "", amount, tokenId)
+}
+
+// BurnFrom burns tokens of from on its behalf. Fungible burns consume the allowance from granted to
+// the session's wallet with APPROVE_TOKEN, ERC1155 and ERC721 burns need an operator approval.
+func (session *UL_TransactionSession) BurnFrom(ctx context.Context, blockchainId string, tokenAddress string, from string, amount Amount, tokenId uint64) (ULTransaction, error) {
+	payload := BurnTokenPayload{TokenAddress: tokenAddress, From: from, Amount: amount, TokenId: tokenId}
+	if err := payload.Validate(); err != nil {
+		return ULTransaction{}, err
+	}
+	return session.submitToken(ctx, blockchainId, BURN_TOKEN, tokenAddress, payload)
+}
+
+// createToken sends a CREATE_TOKEN and returns the address the node assigned to the token
+func (session *UL_TransactionSession) createToken(ctx context.Context, blockchainId string, payload CreateTokenPayload) (ULTransaction, string, error) {
+	tx, err := session.submitToken(ctx, blockchainId, CREATE_TOKEN, "", payload)
+	if err != nil {
+		return tx, "", err
+	}
+
+	// The node assigns the address, the token is the one the transaction registered
+	tokens := session.ListTokens(tx.BlockchainId, ListOptions{})
+	for tokens.Next(ctx) {
+		token := tokens.Value()
+		if token.Owner == tx.From && token.CreatedBlock == tx.BlockHeight && token.Symbol == payload.Symbol && token.Name == payload.Name {
+			return tx, token.TokenAddress, nil
+		}
+	}
+	if err := tokens.Err(); err != nil {
+		return tx, "", err
+	}
+	return tx, "", fmt.Errorf("%w: the token created by %s is not listed", utils.ErrNotFound, tx.TransactionId)
+}
+
+// submitToken sends a token transaction and fails unless the node applied it
+func (session *UL_TransactionSession) submitToken(ctx context.Context, blockchainId string, payloadType ULTransactionType, to string, payload any) (ULTransaction, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	tx, err := session.generateTransaction(ctx, ULTransactionInput{
+		BlockchainId: blockchainId,
+		To:           to,
+		Payload:      string(data),
+		PayloadType:  payloadType.String(),
+	}, "")
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	if tx.Output != TX_SUCCESS.String() {
+		return tx, fmt.Errorf("%s %s was not applied: %s", payloadType, tx.TransactionId, tx.Output)
+	}
+	return tx, nil
+}
+
+// isAddress reports whether s is a hex encoded 32 byte address
+func isAddress(s string) bool {
+	decoded, err := hex.DecodeString(s)
+	return err == nil && len(decoded) == 32
+}
@@ -0,0 +1,200 @@
+package transaction
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// MAX_BALANCE_BATCH_SIZE bounds the balances asked for in one request of BalanceOfBatch, bigger
+// batches are split
+const MAX_BALANCE_BATCH_SIZE = 100
+
+// ErrInvalidTokenQuery is returned before asking the node about malformed addresses
+type ErrInvalidTokenQuery struct {
+	Msg string
+}
+
+func (e *ErrInvalidTokenQuery) Error() string {
+	return fmt.Sprintf("invalid token query, %s", e.Msg)
+}
+
+func (e *ErrInvalidTokenQuery) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// TokenBalance is the balance of one owner, TokenId is the id of ERC1155 balances
+type TokenBalance struct {
+	TokenAddress string `json:"tokenAddress"`
+	Owner        string `json:"owner"`
+	TokenId      uint64 `json:"tokenId,omitempty"`
+	Balance      Amount `json:"balance"`
+}
+
+// BalanceQuery selects one balance of a BalanceOfBatch, TokenId is ignored but for ERC1155 tokens
+type BalanceQuery struct {
+	Owner   string
+	TokenId uint64
+}
+
+// TokenClient reads the token state of a chain: metadata, balances of every token type, ERC20
+// allowances and ERC721 owners. It only queries the node, the token operations are sent with the
+// session.
+type TokenClient struct {
+	session      *UL_TransactionSession
+	blockchainId string
+
+	mu          sync.Mutex
+	metadataTTL time.Duration
+	metadata    map[string]cachedMetadata
+}
+
+type cachedMetadata struct {
+	metadata TokenMetadata
+	expiry   time.Time
+}
+
+// NewTokenClient returns a client reading the tokens of blockchainId through session
+func NewTokenClient(session *UL_TransactionSession, blockchainId string) *TokenClient {
+	return &TokenClient{session: session, blockchainId: blockchainId}
+}
+
+// GetTokenMetadata fetches the name, symbol, decimals, supply and owner of a token
+func (session *UL_TransactionSession) GetTokenMetadata(ctx context.Context, blockchainId string, tokenAddress string) (TokenMetadata, error) {
+	if err := checkTokenAddresses(tokenAddress); err != nil {
+		return TokenMetadata{}, err
+	}
+	token := ULToken{}
+	if err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/tokens/%s", blockchainId, tokenAddress), &token); err != nil {
+		return TokenMetadata{}, err
+	}
+	return token.TokenMetadata, nil
+}
+
+// CacheMetadata keeps the metadata Metadata fetched for ttl, a ttl of zero turns the cache off.
+// Names, symbols and decimals never change but the TotalSupply of a cached token can be ttl old.
+func (c *TokenClient) CacheMetadata(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.metadataTTL = ttl
+	c.metadata = nil
+}
+
+// Metadata returns the name, symbol, decimals, supply and owner of a token, from the cache when
+// CacheMetadata turned it on
+func (c *TokenClient) Metadata(ctx context.Context, tokenAddress string) (TokenMetadata, error) {
+	c.mu.Lock()
+	ttl := c.metadataTTL
+	cached, ok := c.metadata[tokenAddress]
+	c.mu.Unlock()
+	if ok && time.Now().Before(cached.expiry) {
+		return cached.metadata, nil
+	}
+
+	metadata, err := c.session.GetTokenMetadata(ctx, c.blockchainId, tokenAddress)
+	if err != nil || ttl <= 0 {
+		return metadata, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.metadataTTL == ttl {
+		if c.metadata == nil {
+			c.metadata = make(map[string]cachedMetadata)
+		}
+		c.metadata[tokenAddress] = cachedMetadata{metadata: metadata, expiry: time.Now().Add(ttl)}
+	}
+	return metadata, nil
+}
+
+// BalanceOf returns the ERC20 units owner holds, or the number of ERC721 tokens it owns. ERC1155
+// balances are per id, see BalanceOfBatch.
+func (c *TokenClient) BalanceOf(ctx context.Context, tokenAddress string, owner string) (Amount, error) {
+	if err := checkTokenAddresses(tokenAddress, owner); err != nil {
+		return Amount{}, err
+	}
+	balance := TokenBalance{}
+	if err := c.session.getJson(ctx, c.tokenPath(tokenAddress, "balances", owner), &balance); err != nil {
+		return Amount{}, err
+	}
+	return balance.Balance, nil
+}
+
+// BalanceOfBatch returns the balances of queries in their order. Like the ERC1155 balanceOfBatch the
+// same owner may be asked about several ids, other token types ignore TokenId.
+func (c *TokenClient) BalanceOfBatch(ctx context.Context, tokenAddress string, queries []BalanceQuery) ([]TokenBalance, error) {
+	if err := checkTokenAddresses(tokenAddress); err != nil {
+		return nil, err
+	}
+	for _, query := range queries {
+		if err := checkTokenAddresses(query.Owner); err != nil {
+			return nil, err
+		}
+	}
+
+	balances := make([]TokenBalance, 0, len(queries))
+	for start := 0; start < len(queries); start += MAX_BALANCE_BATCH_SIZE {
+		batch := queries[start:min(start+MAX_BALANCE_BATCH_SIZE, len(queries))]
+		params := url.Values{}
+		for _, query := range batch {
+			params.Add("owner", query.Owner)
+			params.Add("tokenId", strconv.FormatUint(query.TokenId, 10))
+		}
+		page := []TokenBalance{}
+		if err := c.session.getJson(ctx, c.tokenPath(tokenAddress, "balances")+"?"+params.Encode(), &page); err != nil {
+			return nil, err
+		}
+		if len(page) != len(batch) {
+			return nil, fmt.Errorf("the node answered %d balances for %d queries", len(page), len(batch))
+		}
+		balances = append(balances, page...)
+	}
+	return balances, nil
+}
+
+// Allowance returns the ERC20 units spender may still transfer on behalf of owner
+func (c *TokenClient) Allowance(ctx context.Context, tokenAddress string, owner string, spender string) (Amount, error) {
+	if err := checkTokenAddresses(tokenAddress, owner, spender); err != nil {
+		return Amount{}, err
+	}
+	response := struct {
+		Allowance Amount `json:"allowance"`
+	}{}
+	if err := c.session.getJson(ctx, c.tokenPath(tokenAddress, "allowances", owner, spender), &response); err != nil {
+		return Amount{}, err
+	}
+	return response.Allowance, nil
+}
+
+// OwnerOf returns the owner of an ERC721 token, tokens never minted or burned are not found
+func (c *TokenClient) OwnerOf(ctx context.Context, tokenAddress string, tokenId uint64) (string, error) {
+	if err := checkTokenAddresses(tokenAddress); err != nil {
+		return "", err
+	}
+	response := struct {
+		Owner string `json:"owner"`
+	}{}
+	if err := c.session.getJson(ctx, tokenIdPath(c.blockchainId, tokenAddress, tokenId)+"/owner", &response); err != nil {
+		return "", err
+	}
+	return response.Owner, nil
+}
+
+func (c *TokenClient) tokenPath(tokenAddress string, elements ...string) string {
+	path, _ := url.JoinPath(fmt.Sprintf("/blockchains/%s/tokens/%s", c.blockchainId, tokenAddress), elements...)
+	return path
+}
+
+// checkTokenAddresses fails for the first address that is not a hex encoded 32 byte address
+func checkTokenAddresses(addresses ...string) error {
+	for _, address := range addresses {
+		if !isAddress(address) {
+			return &ErrInvalidTokenQuery{Msg: fmt.Sprintf("invalid address %q", address)}
+		}
+	}
+	return nil
+}
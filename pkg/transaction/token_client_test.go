@@ -0,0 +1,104 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func TestTokenClient(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	owner := session.GetWallet().Address
+	other := fmt.Sprintf("%064x", 1)
+	client := transaction.NewTokenClient(session, testBlockchainId)
+
+	coin := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Coin", Symbol: "CN", InitialSupply: transaction.NewAmount(1000)})
+	submitToken(t, session, transaction.TRANSFER_TOKEN, transaction.TransferTokenPayload{TokenAddress: coin, To: other, Amount: transaction.NewAmount(250)})
+	submitToken(t, session, transaction.APPROVE_TOKEN, transaction.ApproveTokenPayload{TokenAddress: coin, Spender: other, Amount: transaction.NewAmount(40)})
+	if balance, err := client.BalanceOf(ctx, coin, owner); err != nil || balance.String() != "750" {
+		t.Fatalf("BalanceOf() = %s, %v", balance, err)
+	}
+	if allowance, err := client.Allowance(ctx, coin, owner, other); err != nil || allowance.String() != "40" {
+		t.Fatalf("Allowance() = %s, %v", allowance, err)
+	}
+
+	art := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC721_TOKEN_TYPE, Name: "Art", Symbol: "ART", Mintable: true})
+	for id := uint64(1); id <= 3; id++ {
+		submitToken(t, session, transaction.MINT_NFT, transaction.MintTokenPayload{TokenAddress: art, To: owner, TokenId: id})
+	}
+	submitToken(t, session, transaction.TRANSFER_NFT, transaction.TransferTokenPayload{TokenAddress: art, To: other, TokenId: 2})
+	if got, err := client.OwnerOf(ctx, art, 2); err != nil || got != other {
+		t.Fatalf("OwnerOf() = %s, %v", got, err)
+	}
+	if balance, err := client.BalanceOf(ctx, art, owner); err != nil || balance.String() != "2" {
+		t.Fatalf("BalanceOf() of an ERC721 token = %s, %v", balance, err)
+	}
+	if _, err := client.OwnerOf(ctx, art, 9); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("OwnerOf() of a token never minted error = %v", err)
+	}
+
+	tickets := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC1155_TOKEN_TYPE, Name: "Tickets", Symbol: "TIX", Mintable: true})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: tickets, To: owner, TokenId: 1, Amount: transaction.NewAmount(10)})
+	submitToken(t, session, transaction.MINT_MULTI_TOKEN, transaction.MintTokenPayload{TokenAddress: tickets, To: other, TokenId: 2, Amount: transaction.NewAmount(3)})
+	// Batches bigger than MAX_BALANCE_BATCH_SIZE are split, the order is kept
+	queries := make([]transaction.BalanceQuery, 0, transaction.MAX_BALANCE_BATCH_SIZE+2)
+	for range transaction.MAX_BALANCE_BATCH_SIZE {
+		queries = append(queries, transaction.BalanceQuery{Owner: owner, TokenId: 2})
+	}
+	queries = append(queries, transaction.BalanceQuery{Owner: owner, TokenId: 1}, transaction.BalanceQuery{Owner: other, TokenId: 2})
+	balances, err := client.BalanceOfBatch(ctx, tickets, queries)
+	if err != nil || len(balances) != len(queries) {
+		t.Fatalf("BalanceOfBatch() = %d balances, %v", len(balances), err)
+	}
+	last := balances[len(balances)-2:]
+	if last[0].Balance.String() != "10" || last[1].Balance.String() != "3" || last[1].Owner != other || last[1].TokenId != 2 || !balances[0].Balance.IsZero() {
+		t.Fatalf("BalanceOfBatch() = %+v", last)
+	}
+
+	if _, err := client.BalanceOf(ctx, coin, "alice"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("BalanceOf() of an invalid owner error = %v", err)
+	}
+	if _, err := client.BalanceOf(ctx, fmt.Sprintf("%064x", 99), owner); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("BalanceOf() of an unknown token error = %v", err)
+	}
+}
+
+func TestTokenClientMetadata(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	client := transaction.NewTokenClient(session, testBlockchainId)
+	coin := createToken(t, session, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Coin", Symbol: "CN", Decimals: 6, Burnable: true, InitialSupply: transaction.NewAmount(1000)})
+
+	metadata, err := client.Metadata(ctx, coin)
+	if err != nil || metadata.Name != "Coin" || metadata.Symbol != "CN" || metadata.Decimals != 6 || metadata.TotalSupply.String() != "1000" || metadata.Owner != session.GetWallet().Address {
+		t.Fatalf("Metadata() = %+v, %v", metadata, err)
+	}
+
+	// The cached supply stays until the entry expires
+	client.CacheMetadata(time.Hour)
+	client.Metadata(ctx, coin)
+	submitToken(t, session, transaction.BURN_TOKEN, transaction.BurnTokenPayload{TokenAddress: coin, Amount: transaction.NewAmount(100)})
+	if metadata, _ := client.Metadata(ctx, coin); metadata.TotalSupply.String() != "1000" {
+		t.Fatalf("Metadata() from the cache supply = %s, want 1000", metadata.TotalSupply)
+	}
+	if metadata, _ := session.GetTokenMetadata(ctx, testBlockchainId, coin); metadata.TotalSupply.String() != "900" {
+		t.Fatalf("GetTokenMetadata() supply = %s, want 900", metadata.TotalSupply)
+	}
+	client.CacheMetadata(0)
+	if metadata, _ := client.Metadata(ctx, coin); metadata.TotalSupply.String() != "900" {
+		t.Fatalf("Metadata() without the cache supply = %s, want 900", metadata.TotalSupply)
+	}
+
+	if _, err := client.Metadata(ctx, fmt.Sprintf("%064x", 99)); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("Metadata() of an unknown token error = %v", err)
+	}
+	if _, err := client.Metadata(ctx, "coin"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Metadata() of an invalid address error = %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package transaction_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// newPeerSession opens a session with a fresh wallet on the node of another test session
+func newPeerSession(t *testing.T, node *transactiontest.MockNode) *transaction.UL_TransactionSession {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	return &session
+}
+
+func TestBurnFrom(t *testing.T) {
+	ctx := context.Background()
+	node, owner := newMockSession(t)
+	spender := newPeerSession(t, node)
+	ownerAddress, spenderAddress := owner.GetWallet().Address, spender.GetWallet().Address
+
+	token := createToken(t, owner, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Burnable", Symbol: "BRN", InitialSupply: transaction.NewAmount(1000), Burnable: true})
+	if _, err := owner.Burn(ctx, testBlockchainId, token, transaction.NewAmount(100), 0); err != nil {
+		t.Fatalf("Burn() error = %v", err)
+	}
+	if balance := node.Balance(token, ownerAddress); balance != 900 {
+		t.Fatalf("balance after Burn() = %d, want 900", balance)
+	}
+
+	// Without an allowance the node refuses to burn on the owner's behalf
+	tx, err := spender.BurnFrom(ctx, testBlockchainId, token, ownerAddress, transaction.NewAmount(50), 0)
+	if err == nil || tx.Output != transaction.TX_REJECTED_BY_UNAUTHORIZED.String() {
+		t.Fatalf("BurnFrom() without allowance = %s, %v", tx.Output, err)
+	}
+
+	submitToken(t, owner, transaction.APPROVE_TOKEN, transaction.ApproveTokenPayload{TokenAddress: token, Spender: spenderAddress, Amount: transaction.NewAmount(80)})
+	if _, err := spender.BurnFrom(ctx, testBlockchainId, token, ownerAddress, transaction.NewAmount(40), 0); err != nil {
+		t.Fatalf("BurnFrom() error = %v", err)
+	}
+	if balance, allowance := node.Balance(token, ownerAddress), node.Allowance(token, ownerAddress, spenderAddress); balance != 860 || allowance != 40 {
+		t.Fatalf("after BurnFrom() balance = %d and allowance = %d, want 860 and 40", balance, allowance)
+	}
+	if _, err := spender.BurnFrom(ctx, testBlockchainId, token, ownerAddress, transaction.NewAmount(41), 0); err == nil {
+		t.Fatal("BurnFrom() burned past the allowance")
+	}
+
+	tokens, _ := owner.ListTokens(testBlockchainId, transaction.ListOptions{}).Collect(t.Context())
+	if tokens[0].TotalSupply.Cmp(transaction.NewAmount(860)) != 0 {
+		t.Fatalf("TotalSupply = %s, want the burns deducted", tokens[0].TotalSupply)
+	}
+
+	fixed := createToken(t, owner, transaction.CreateTokenPayload{TokenType: transaction.ERC20_TOKEN_TYPE, Name: "Fixed", Symbol: "FIX", InitialSupply: transaction.NewAmount(10)})
+	if _, err := owner.Burn(ctx, testBlockchainId, fixed, transaction.NewAmount(1), 0); err == nil {
+		t.Fatal("Burn() destroyed a token that is not burnable")
+	}
+
+	invalid := &transaction.ErrInvalidTokenPayload{}
+	if _, err := spender.BurnFrom(ctx, testBlockchainId, token, "not-an-address", transaction.NewAmount(1), 0); !errors.As(err, &invalid) {
+		t.Fatalf("BurnFrom() error = %v, want the owner address rejected before submitting", err)
+	}
+	if _, err := owner.Burn(ctx, testBlockchainId, "", transaction.NewAmount(1), 0); !errors.As(err, &invalid) {
+		t.Fatalf("Burn() error = %v, want the token address rejected", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash"
 	"math"
@@ -13,6 +14,8 @@ import (
 	"time"
 
 	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
 	"github.com/consensys/gnark-crypto/accumulator/merkletree"
 	"github.com/consensys/gnark-crypto/ecc"
 )
@@ -42,6 +45,10 @@ func (e *ErrParsingTransactionStatus) Error() string {
 	return fmt.Sprintf("invalid transaction status, %s", e.Msg)
 }
 
+func (e *ErrParsingTransactionStatus) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
 type UL_TransactionStatus int
 
 const (
@@ -85,6 +92,10 @@ func (e *ErrParsingTransactionType) Error() string {
 	return fmt.Sprintf("invalid transaction type, %s", e.Msg)
 }
 
+func (e *ErrParsingTransactionType) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
 type ULTransactionType int
 
 const (
@@ -108,6 +119,13 @@ const (
 	TRANSFER_MULTI_TOKEN
 	MINT_MULTI_TOKEN
 	CONVERT_TOKEN
+	DELEGATE_KEY
+	REVOKE_DELEGATION
+	CANCEL_TRANSACTION
+	CREATE_MULTISIG
+	PROPOSE_MULTISIG
+	APPROVE_MULTISIG
+	EXECUTE_MULTISIG
 )
 
 func (tt ULTransactionType) String() string {
@@ -150,6 +168,20 @@ func (tt ULTransactionType) String() string {
 		return "MINT_MULTI_TOKEN"
 	case CONVERT_TOKEN:
 		return "CONVERT_TOKEN"
+	case DELEGATE_KEY:
+		return "DELEGATE_KEY"
+	case REVOKE_DELEGATION:
+		return "REVOKE_DELEGATION"
+	case CANCEL_TRANSACTION:
+		return "CANCEL_TRANSACTION"
+	case CREATE_MULTISIG:
+		return "CREATE_MULTISIG"
+	case PROPOSE_MULTISIG:
+		return "PROPOSE_MULTISIG"
+	case APPROVE_MULTISIG:
+		return "APPROVE_MULTISIG"
+	case EXECUTE_MULTISIG:
+		return "EXECUTE_MULTISIG"
 	default:
 		return ""
 	}
@@ -159,6 +191,10 @@ func ParseTransactionType(str string) (ULTransactionType, error) {
 	switch strings.ToUpper(str) {
 	case TX_DATA.String():
 		return TX_DATA, nil
+	case TX_CREATE_WALLET.String():
+		return TX_CREATE_WALLET, nil
+	case TX_ALTER_WALLET.String():
+		return TX_ALTER_WALLET, nil
 	case DEPLOY_SMART_CONTRACT.String():
 		return DEPLOY_SMART_CONTRACT, nil
 	case INVOKE_SMART_CONTRACT.String():
@@ -191,6 +227,20 @@ func ParseTransactionType(str string) (ULTransactionType, error) {
 		return MINT_MULTI_TOKEN, nil
 	case CONVERT_TOKEN.String():
 		return CONVERT_TOKEN, nil
+	case DELEGATE_KEY.String():
+		return DELEGATE_KEY, nil
+	case REVOKE_DELEGATION.String():
+		return REVOKE_DELEGATION, nil
+	case CANCEL_TRANSACTION.String():
+		return CANCEL_TRANSACTION, nil
+	case CREATE_MULTISIG.String():
+		return CREATE_MULTISIG, nil
+	case PROPOSE_MULTISIG.String():
+		return PROPOSE_MULTISIG, nil
+	case APPROVE_MULTISIG.String():
+		return APPROVE_MULTISIG, nil
+	case EXECUTE_MULTISIG.String():
+		return EXECUTE_MULTISIG, nil
 	default:
 		return INVALID_TX_TYPE, &ErrParsingTransactionType{Msg: str}
 	}
@@ -204,6 +254,10 @@ func (e *ErrParsingTransactionOutput) Error() string {
 	return fmt.Sprintf("invalid transaction output, %s", e.Msg)
 }
 
+func (e *ErrParsingTransactionOutput) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
 type UL_TransactionOutput int
 
 const (
@@ -217,6 +271,8 @@ const (
 	TX_REJECTED_BY_INVALID_SIGNATURE UL_TransactionOutput = 7
 	TX_TRANSACTION_ERROR             UL_TransactionOutput = 8
 	TX_REJECTED_BY_INVALID_KEY_TYPE  UL_TransactionOutput = 9
+	// TX_REJECTED_BY_REPLACEMENT is the output of a pending transaction its sender replaced or cancelled
+	TX_REJECTED_BY_REPLACEMENT UL_TransactionOutput = 10
 )
 
 func (tt UL_TransactionOutput) String() string {
@@ -239,6 +295,8 @@ func (tt UL_TransactionOutput) String() string {
 		return "TRANSACTION_ERROR"
 	case TX_REJECTED_BY_INVALID_KEY_TYPE:
 		return "REJECTED_BY_INVALID_KEY_TYPE"
+	case TX_REJECTED_BY_REPLACEMENT:
+		return "REJECTED_BY_REPLACEMENT"
 	default:
 		return ""
 	}
@@ -264,6 +322,8 @@ func ParseTransactionOutput(str string) (UL_TransactionOutput, error) {
 		return TX_TRANSACTION_ERROR, nil
 	case TX_REJECTED_BY_INVALID_KEY_TYPE.String():
 		return TX_REJECTED_BY_INVALID_KEY_TYPE, nil
+	case TX_REJECTED_BY_REPLACEMENT.String():
+		return TX_REJECTED_BY_REPLACEMENT, nil
 	default:
 		return INVALID_TX_OUTPUT, &ErrParsingTransactionOutput{Msg: str}
 	}
@@ -305,6 +365,8 @@ type TransactionCommitment struct {
 	ChunkSize        int
 	ProofChunk       []byte
 	Depth            int
+	// Scheme is the commitment scheme of PayloadRoot, empty for COMMITMENT_SCHEME_MIMC
+	Scheme string
 }
 
 // Helper to hash the data! Using SHA256
@@ -393,6 +455,85 @@ func (t *ULTransactionInput) GetUnboundCommitment(hasher hash.Hash) ([]byte, err
 	return payloadRoot, nil
 }
 
+// IsUnbound reports whether the payload type signs the plain Merkle root of an unbounded payload
+// instead of the hard bound signature commitment
+func (t *ULTransactionInput) IsUnbound() bool {
+	if custom, ok := GetPayloadType(t.PayloadType); ok {
+		return custom.Commitment == COMMITMENT_UNBOUND
+	}
+	return t.PayloadType == DEPLOY_SMART_CONTRACT.String() || t.PayloadType == UPGRADE_SMART_CONTRACT.String() ||
+		t.PayloadType == TX_CREATE_WALLET.String() || t.PayloadType == TX_ALTER_WALLET.String()
+}
+
+// SigningCommitment returns the bytes the sender signs and the payload root recorded with the transaction
+func (t *ULTransactionInput) SigningCommitment() ([]byte, string, error) {
+	if err := t.CheckPayloadSize(); err != nil {
+		return nil, "", err
+	}
+	scheme, err := GetCommitmentScheme(t.CommitmentScheme)
+	if err != nil {
+		return nil, "", err
+	}
+	hasher := crypto.GetHasherByType(t.KeyType)
+	if scheme.Name() != COMMITMENT_SCHEME_MIMC {
+		return t.schemeCommitment(hasher, scheme)
+	}
+	if t.IsUnbound() {
+		commitment, err := t.GetUnboundCommitment(hasher)
+		if err != nil {
+			return nil, "", err
+		}
+		return commitment, crypto.BytesToHex(commitment), nil
+	}
+
+	signatureCommitment, err := t.GetSignatureCommitment(hasher, true)
+	if err != nil {
+		return nil, "", err
+	}
+	commitment, err := t.HashSignatureCommitment(hasher, signatureCommitment)
+	if err != nil {
+		return nil, "", err
+	}
+	return commitment, crypto.BytesToHex(signatureCommitment.PayloadRoot), nil
+}
+
+// schemeCommitment is SigningCommitment for roots of other schemes than MiMC. Those roots need not
+// be field elements, so they are always hashed by the hasher of the key type before signing.
+func (t *ULTransactionInput) schemeCommitment(hasher hash.Hash, scheme CommitmentScheme) ([]byte, string, error) {
+	payloadRoot, err := scheme.PayloadRoot([]byte(t.Payload), t.KeyType, !t.IsUnbound())
+	if err != nil {
+		return nil, "", err
+	}
+	if t.IsUnbound() {
+		hasher.Reset()
+		writeHalves(hasher, payloadRoot)
+		return hasher.Sum(nil), crypto.BytesToHex(payloadRoot), nil
+	}
+
+	commitment := TransactionCommitment{PayloadRoot: payloadRoot, Scheme: scheme.Name()}
+	var errs [4]error
+	commitment.BlockchainIdHigh, commitment.BlockchainIdLow, errs[0] = splitHash32(t.BlockchainId)
+	commitment.FromHigh, commitment.FromLow, errs[1] = splitHash32(t.From)
+	commitment.ToHigh, commitment.ToLow, errs[2] = splitHash32(t.To)
+	commitment.SuggestorHigh, commitment.SuggestorLow, errs[3] = splitHash32(t.Suggestor)
+	if err := errors.Join(errs[:]...); err != nil {
+		return nil, "", err
+	}
+	commitment.Timestamp = uint64(t.SenderTimestamp.Unix())
+	signed, err := t.HashSignatureCommitment(hasher, commitment)
+	if err != nil {
+		return nil, "", err
+	}
+	return signed, crypto.BytesToHex(payloadRoot), nil
+}
+
+// writeHalves writes data in 16 byte words, each is below the modulus of every MiMC hasher
+func writeHalves(hasher hash.Hash, data []byte) {
+	for start := 0; start < len(data); start += 16 {
+		hasher.Write(data[start:min(start+16, len(data))])
+	}
+}
+
 func (t *ULTransactionInput) HashSignatureCommitment(hasher hash.Hash, commitment TransactionCommitment) ([]byte, error) {
 	hasher.Reset()
 	hasher.Write(commitment.BlockchainIdHigh)
@@ -401,7 +542,11 @@ func (t *ULTransactionInput) HashSignatureCommitment(hasher hash.Hash, commitmen
 	hasher.Write(commitment.FromLow)
 	hasher.Write(commitment.ToHigh)
 	hasher.Write(commitment.ToLow)
-	hasher.Write(commitment.PayloadRoot)
+	if commitment.Scheme == "" || commitment.Scheme == COMMITMENT_SCHEME_MIMC {
+		hasher.Write(commitment.PayloadRoot)
+	} else {
+		writeHalves(hasher, commitment.PayloadRoot)
+	}
 	binary.Write(hasher, binary.BigEndian, commitment.Timestamp)
 	hasher.Write(commitment.SuggestorHigh)
 	hasher.Write(commitment.SuggestorLow)
@@ -430,6 +575,18 @@ type ULTransactionInput struct {
 	SenderTimestamp time.Time      `json:"senderTimestamp"`
 	PayloadRoot     string         `json:"payloadRoot"`
 	KeyType         crypto.KeyType `json:"keyType"`
+	// SignatureEncoding of SenderSignature, empty means raw
+	SignatureEncoding crypto.SignatureEncoding `json:"signatureEncoding,omitempty"`
+	// DelegationId is set when a key delegated by From signed the transaction, see Delegate
+	DelegationId string `json:"delegationId,omitempty"`
+	// HybridSignature is an ML-DSA-87 signature over the same commitment by HybridPublicKey, see SetHybridKey
+	HybridSignature string `json:"hybridSignature,omitempty"`
+	HybridPublicKey string `json:"hybridPublicKey,omitempty"`
+	// CommitmentScheme of PayloadRoot, empty means COMMITMENT_SCHEME_MIMC, see SetCommitmentScheme
+	CommitmentScheme string `json:"commitmentScheme,omitempty"`
+	// Multisig is the wallet of a multisig sender, SenderSignature then combines the signatures of
+	// its keys, see MultisigWallet
+	Multisig *MultisigWallet `json:"multisig,omitempty"`
 }
 
 // These fields are generated by the node!
@@ -444,6 +601,8 @@ type ULTransactionOutput struct {
 	Output        string      `json:"output"`
 	Proof         string      `json:"proof"`
 	ProofVersion  string      `json:"proofVersion"`
+	// Events are emitted by the contracts the transaction executed, see SubscribeContractEvents
+	Events []ContractEventLog `json:"events,omitempty"`
 }
 
 type ULTransaction struct {
@@ -464,6 +623,8 @@ func (t *ULTransaction) SetTransactionWeight() {
 	weight += len(t.From)
 	weight += len(t.Payload)
 	weight += len(t.SenderSignature)
+	weight += len(t.HybridSignature)
+	weight += len(t.HybridPublicKey)
 	weight += len(t.Version)
 	weight += len(t.Suggestor)
 
@@ -492,7 +653,7 @@ func TransactionFromBytes(data []byte) (*ULTransaction, error) {
 func GenerateMerkleTreeWithHardBound(payload []byte, modulus *big.Int, chunkSize int, depth int, hasher hash.Hash, proofIndex uint64) ([]byte, [][]byte, []byte, uint64, error) {
 	maxSize := chunkSize * (1 << depth) // Maximum size of the payload in bytes
 	if len(payload) > maxSize {
-		return nil, nil, nil, 0, fmt.Errorf("payload is too large, max size is %d bytes, got %d bytes", maxSize, len(payload))
+		return nil, nil, nil, 0, newErrPayloadTooLarge("", maxSize, len(payload))
 	}
 
 	modulusSizeBytes := len(modulus.Bytes())
@@ -585,6 +746,22 @@ func GenerateMerkleTree(payload []byte, modulus *big.Int, chunkSize int, hasher
 	return merkleRoot, proofElements, proofChunk, numLeaves, treeDepth, nil
 }
 
+// CreateWalletPayload registers a wallet, the transaction is sent to the new wallet's address
+// and authored by its parent
+type CreateWalletPayload struct {
+	PublicKey  string                              `json:"publicKey"`
+	Parent     string                              `json:"parent"`
+	KeyType    crypto.KeyType                      `json:"keyType"`
+	AuthGroups map[string]wallet.UL_AuthPermission `json:"authGroups,omitempty"`
+}
+
+// AlterWalletPayload enables, disables or changes the auth groups of Target
+type AlterWalletPayload struct {
+	Target     string                              `json:"target"`
+	Enabled    bool                                `json:"enabled"`
+	AuthGroups map[string]wallet.UL_AuthPermission `json:"authGroups"`
+}
+
 type ContractArgs struct {
 	Value []byte `json:"value"` // To match the serialization/deserialization of the contract
 }
@@ -609,9 +786,9 @@ type CreateTokenPayload struct {
 	TokenType     string `json:"tokenType"` // "ERC20", "ERC721", "ERC1155"
 	Name          string `json:"name"`
 	Symbol        string `json:"symbol"`
-	Decimals      uint8  `json:"decimals,omitempty"`      // ERC20 only
-	InitialSupply uint64 `json:"initialSupply,omitempty"` // ERC20 only
-	BaseURI       string `json:"baseURI,omitempty"`       // NFT only
+	Decimals      uint8  `json:"decimals,omitempty"`     // ERC20 only
+	InitialSupply Amount `json:"initialSupply,omitzero"` // ERC20 only
+	BaseURI       string `json:"baseURI,omitempty"`      // NFT only
 	Mintable      bool   `json:"mintable"`
 	Burnable      bool   `json:"burnable"`
 }
@@ -621,10 +798,10 @@ type TransferTokenPayload struct {
 	TokenAddress string   `json:"tokenAddress"`
 	From         string   `json:"from,omitempty"` // Optional - defaults to tx.From
 	To           string   `json:"to"`
-	Amount       uint64   `json:"amount,omitempty"`   // ERC20/ERC1155
+	Amount       Amount   `json:"amount,omitzero"`    // ERC20/ERC1155
 	TokenId      uint64   `json:"tokenId,omitempty"`  // ERC721/ERC1155
 	TokenIds     []uint64 `json:"tokenIds,omitempty"` // ERC1155 batch
-	Amounts      []uint64 `json:"amounts,omitempty"`  // ERC1155 batch
+	Amounts      []Amount `json:"amounts,omitempty"`  // ERC1155 batch
 	Data         []byte   `json:"data,omitempty"`     // ERC1155 additional data
 }
 
@@ -634,7 +811,7 @@ type BatchTransferTokenPayload struct {
 	From         string   `json:"from,omitempty"` // Optional - defaults to tx.From
 	To           string   `json:"to"`
 	TokenIds     []uint64 `json:"tokenIds"`
-	Amounts      []uint64 `json:"amounts"`
+	Amounts      []Amount `json:"amounts"`
 	Data         []byte   `json:"data,omitempty"`
 }
 
@@ -642,7 +819,7 @@ type BatchTransferTokenPayload struct {
 type ApproveTokenPayload struct {
 	TokenAddress string `json:"tokenAddress"`
 	Spender      string `json:"spender"`
-	Amount       uint64 `json:"amount,omitempty"`  // ERC20/ERC1155
+	Amount       Amount `json:"amount,omitzero"`   // ERC20/ERC1155
 	TokenId      uint64 `json:"tokenId,omitempty"` // ERC721/ERC1155
 }
 
@@ -650,7 +827,7 @@ type ApproveTokenPayload struct {
 type MintTokenPayload struct {
 	TokenAddress string `json:"tokenAddress"`
 	To           string `json:"to"`
-	Amount       uint64 `json:"amount,omitempty"`   // ERC20
+	Amount       Amount `json:"amount,omitzero"`    // ERC20
 	TokenId      uint64 `json:"tokenId,omitempty"`  // ERC721
 	TokenURI     string `json:"tokenURI,omitempty"` // ERC721 metadata
 }
@@ -658,7 +835,8 @@ type MintTokenPayload struct {
 // Burn payload
 type BurnTokenPayload struct {
 	TokenAddress string `json:"tokenAddress"`
-	Amount       uint64 `json:"amount,omitempty"`  // ERC20/ERC1155
+	From         string `json:"from,omitempty"`    // Optional - defaults to tx.From, burning from another account needs its approval
+	Amount       Amount `json:"amount,omitzero"`   // ERC20/ERC1155
 	TokenId      uint64 `json:"tokenId,omitempty"` // ERC721/ERC1155
 }
 
@@ -680,7 +858,7 @@ type TokenMetadata struct {
 	Mintable     bool   `json:"mintable"`
 	Burnable     bool   `json:"burnable"`
 	BaseURI      string `json:"baseURI,omitempty"`
-	TotalSupply  uint64 `json:"totalSupply"`
+	TotalSupply  Amount `json:"totalSupply"`
 	CreatedBlock int    `json:"createdBlock"`
 }
 
@@ -689,7 +867,7 @@ type ConvertTokenPayload struct {
 	TokenAddress   string `json:"tokenAddress"`
 	FromTokenId    uint64 `json:"fromTokenId"`
 	ToTokenId      uint64 `json:"toTokenId,omitempty"`
-	Amount         uint64 `json:"amount"`
+	Amount         Amount `json:"amount"`
 	NewTokenURI    string `json:"newTokenURI,omitempty"`
 	PreserveTokens bool   `json:"preserveTokens,omitempty"` // Whether to keep original tokens (default: burn them)
 }
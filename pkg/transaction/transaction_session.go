@@ -1,11 +1,10 @@
 package transaction
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/ULedgerInc/go-sdk/pkg/crypto"
@@ -13,9 +12,34 @@ import (
 )
 
 type UL_TransactionSession struct {
-	nodeEndpoint string
-	suggestor    string
-	wallet       wallet.UL_Wallet
+	nodeEndpoint      string
+	suggestor         string
+	wallet            wallet.UL_Wallet
+	finalityThreshold int
+	headers           http.Header
+	retryPolicy       RetryPolicy
+	metrics           *sessionMetrics
+	defaults          TransactionDefaults
+	hooks             SessionHooks
+	limiter           RateLimiter
+	signatureEncoding crypto.SignatureEncoding
+	guardrails        Guardrails
+	// delegation is set on sessions signing with a delegated key
+	delegation *SignedDelegation
+	// hybridKey co-signs every transaction when set, see SetHybridKey
+	hybridKey *wallet.UL_Wallet
+	// commitmentScheme is the scheme of generated transactions, see SetCommitmentScheme
+	commitmentScheme string
+	// blockVerifier checks the blocks of the block queries, see SetBlockVerifier
+	blockVerifier func(ULBlock) error
+	// node is what connect learned about the node, see NodeInfo
+	node NodeInfo
+	// httpClient carries every request, ownsClient is set when no other session shares it
+	httpClient  *http.Client
+	ownsClient  bool
+	latencyHook func(LatencySample)
+	// idempotency records the transactions of idempotency keys, see GenerateIdempotentTransaction
+	idempotency *idempotency
 }
 
 type chainInfo struct {
@@ -35,73 +59,77 @@ type healthInfo struct {
 	Chains  map[string]chainInfo `json:"chainsInfo"`
 	NodeId  string               `json:"nodeId"`
 	PeerId  string               `json:"peerId"`
+	// Features lists optional capabilities, older nodes omit it
+	Features []string `json:"features"`
 }
 
-func NewUL_TransactionSession(nodeEndpoint string, wallet wallet.UL_Wallet) (UL_TransactionSession, error) {
-	// Fetch the Node Metadata
-	httpClient := &http.Client{}
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/health", nodeEndpoint), nil)
-	// Read the response
-	resp, err := httpClient.Do(req)
+// NewUL_TransactionSession connects to the node at nodeEndpoint, the session signs with wallet.
+// opts configure the HTTP client, see SessionOptions.
+func NewUL_TransactionSession(nodeEndpoint string, wallet wallet.UL_Wallet, opts ...SessionOptions) (UL_TransactionSession, error) {
+	options, err := sessionOptions(opts)
 	if err != nil {
 		return UL_TransactionSession{}, err
 	}
-	// Parse the response
-	body, err := io.ReadAll(resp.Body)
+	return newSession(context.Background(), nodeEndpoint, wallet, options)
+}
+
+// connect fetches the node metadata and checks the node serves at least one chain
+func (session *UL_TransactionSession) connect(ctx context.Context) error {
 	info := healthInfo{}
-	err = json.Unmarshal(body, &info)
-	if err != nil {
-		return UL_TransactionSession{}, err
+	if err := session.Do(ctx, "GET", "/health", nil, &info); err != nil {
+		return err
 	}
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return UL_TransactionSession{}, fmt.Errorf("server returned unexpected status code: %d", resp.StatusCode)
-	}
-
-	nodeId := info.NodeId
-	resp.Body.Close()
 
-	req, err = http.NewRequest("GET", fmt.Sprintf("%s/blockchains", nodeEndpoint), nil)
-	if err != nil {
-		return UL_TransactionSession{}, err
+	chains := make([]string, 0)
+	if err := session.Do(ctx, "GET", "/blockchains", nil, &chains); err != nil {
+		return err
 	}
 
-	// Read the response
-	resp, err = httpClient.Do(req)
-	if err != nil {
-		return UL_TransactionSession{}, err
+	if len(chains) == 0 {
+		return fmt.Errorf("no chains found for the node")
 	}
 
-	// Parse the response
-	defer resp.Body.Close()
-
-	// Parse the response
-	body, err = io.ReadAll(resp.Body)
-	if err != nil {
-		return UL_TransactionSession{}, err
-	}
+	session.suggestor = info.NodeId
+	session.node = NodeInfo{NodeId: info.NodeId, Version: info.Version, Features: info.Features, Chains: chains}
+	return nil
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return UL_TransactionSession{}, fmt.Errorf("server returned unexpected status code: %d", resp.StatusCode)
+// SetSignatureEncoding selects the encoding of transaction signatures, the choice is recorded in
+// ULTransactionInput.SignatureEncoding. DER is only available for secp256k1 wallets.
+func (session *UL_TransactionSession) SetSignatureEncoding(encoding crypto.SignatureEncoding) error {
+	if err := session.wallet.CheckKey(); err != nil {
+		return err
 	}
-
-	chains := make([]string, 0)
-	err = json.Unmarshal(body, &chains)
-	if err != nil {
-		return UL_TransactionSession{}, err
+	if !crypto.SupportsSignatureEncoding(session.wallet.GetKey().GetType(), encoding) {
+		return fmt.Errorf("%s wallets cannot sign with %s encoding", session.wallet.GetKey().GetType(), encoding)
 	}
-
-	if len(chains) == 0 {
-		return UL_TransactionSession{}, fmt.Errorf("no chains found for the node")
+	if encoding == "" {
+		encoding = crypto.SIGNATURE_ENCODING_RAW
 	}
+	session.signatureEncoding = encoding
+	return nil
+}
 
-	return UL_TransactionSession{
-		nodeEndpoint: nodeEndpoint,
-		suggestor:    nodeId,
-		wallet:       wallet,
-	}, nil
+// GetWallet returns the wallet the session signs with
+func (session *UL_TransactionSession) GetWallet() wallet.UL_Wallet {
+	return session.wallet
 }
 
 func (session *UL_TransactionSession) GenerateTransaction(input ULTransactionInput) (ULTransaction, error) {
+	return session.generateTransaction(context.Background(), input, "")
+}
+
+// generateTransaction signs input and submits it, replacing the pending transaction replaces when set
+func (session *UL_TransactionSession) generateTransaction(ctx context.Context, input ULTransactionInput, replaces string) (ULTransaction, error) {
+	// Sessions of keyless wallets only read from the node
+	if err := session.wallet.CheckKey(); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
+	}
+	input, err := session.defaults.Apply(input)
+	if err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
+	}
+
 	// Generate a new transaction
 	// Attach the suggestor
 	input.Suggestor = session.suggestor
@@ -113,79 +141,92 @@ func (session *UL_TransactionSession) GenerateTransaction(input ULTransactionInp
 		input.From = session.wallet.Address
 	}
 	input.KeyType = session.wallet.GetKey().GetType()
-
-	hasher := crypto.GetHasherByType(input.KeyType)
-
-	var commitment []byte
-	var err error
-	// If the transaction is a deploy, we just need to hash the payload with SHA3-512 and sign it
-	if input.PayloadType == DEPLOY_SMART_CONTRACT.String() || input.PayloadType == UPGRADE_SMART_CONTRACT.String() ||
-		input.PayloadType == TX_CREATE_WALLET.String() || input.PayloadType == TX_ALTER_WALLET.String() {
-		fmt.Println("Generating commitment for deploy or create wallet transaction")
-		commitment, err = input.GetUnboundCommitment(hasher)
-		if err != nil {
-			return ULTransaction{}, err
-		}
-		input.PayloadRoot = crypto.BytesToHex(commitment)
-	} else {
-		signatureCommitment, err := input.GetSignatureCommitment(hasher, true)
-		if err != nil {
-			return ULTransaction{}, err
-		}
-		commitment, err = input.HashSignatureCommitment(hasher, signatureCommitment)
-		if err != nil {
-			return ULTransaction{}, err
+	if input.CommitmentScheme == "" {
+		input.CommitmentScheme = session.commitmentScheme
+	}
+	if err := input.CheckPayloadSize(); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
+	}
+	if session.delegation != nil {
+		if err := session.delegation.Delegation.Allows(input, curTime); err != nil {
+			return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
 		}
+		input.From = session.delegation.Delegation.Delegator
+		input.DelegationId = session.delegation.Delegation.Id()
+	}
+	if err := ValidateInput(input); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
+	}
+	if err := session.guardrails.Check(input, input.KeyType); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_PREPARE, input, err)
+	}
 
-		// Set the payload root
-		input.PayloadRoot = crypto.BytesToHex(signatureCommitment.PayloadRoot)
+	// Deploy and wallet transactions sign the Merkle root of the whole payload
+	commitment, payloadRoot, err := input.SigningCommitment()
+	if err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_COMMIT, input, err)
 	}
+	input.PayloadRoot = payloadRoot
 
 	// Sign the commitment
-	signature, err := session.wallet.GetKey().SignData(commitment)
+	session.hooks.beforeSign(input, commitment)
+	signature, err := crypto.SignDataWithEncoding(session.wallet.GetKey(), commitment, session.signatureEncoding)
 	if err != nil {
-		return ULTransaction{}, err
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_SIGN, input, err)
+	}
+	if encoding := session.signatureEncoding; encoding != "" && encoding != crypto.SIGNATURE_ENCODING_RAW {
+		input.SignatureEncoding = session.signatureEncoding
 	}
 
 	input.SenderSignature = crypto.BytesToHex(signature)
-
-	// HTTP Request to the Node
-	httpClient := &http.Client{}
-
-	// Parse the input to JSON
-	jsonInput, err := json.Marshal(input)
-	if err != nil {
-		return ULTransaction{}, err
+	if err := session.signHybrid(&input, commitment); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_SIGN, input, err)
 	}
+	session.hooks.afterSign(input, commitment)
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/blockchains/%s/transactions", session.nodeEndpoint, input.BlockchainId), bytes.NewBuffer(jsonInput))
-	if err != nil {
-		return ULTransaction{}, err
+	// Submit the signed transaction to the Node
+	started := time.Now()
+	response := []byte{}
+	path := fmt.Sprintf("/blockchains/%s/transactions", input.BlockchainId)
+	if replaces != "" {
+		path = fmt.Sprintf("%s/%s/replace", path, replaces)
 	}
-
-	// Perform the request
-	resp, err := httpClient.Do(req)
+	err = session.Do(ctx, "POST", path, input, &response)
 	if err != nil {
-		return ULTransaction{}, err
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_SUBMIT, input, err)
 	}
-	defer resp.Body.Close()
-
-	// Parse the response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return ULTransaction{}, err
+	transaction := ULTransaction{}
+	if err := decodeResponseBody(response, &transaction); err != nil {
+		return ULTransaction{}, session.hooks.fail(HOOK_STAGE_DECODE, input, err)
 	}
+	session.hooks.afterSubmit(input, response, transaction, time.Since(started))
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return ULTransaction{}, fmt.Errorf("server returned unexpected status code: %d, message:%s", resp.StatusCode, body)
-	}
+	return transaction, nil
+}
 
-	transaction := ULTransaction{}
-	err = json.Unmarshal(body, &transaction)
-	if err != nil {
-		return ULTransaction{}, err
+// getJson fetches a node resource and decodes the JSON response into out
+func (session *UL_TransactionSession) getJson(ctx context.Context, path string, out any) error {
+	return session.Do(ctx, "GET", path, nil, out)
+}
+
+// hasFeature reports whether the node advertises an optional capability
+func (session *UL_TransactionSession) hasFeature(ctx context.Context, feature string) (bool, error) {
+	info := healthInfo{}
+	if err := session.getJson(ctx, "/health", &info); err != nil {
+		return false, err
 	}
+	return slices.Contains(info.Features, feature), nil
+}
 
-	return transaction, nil
+// getChainInfo returns the node's view of a single blockchain
+func (session *UL_TransactionSession) getChainInfo(ctx context.Context, blockchainId string) (chainInfo, error) {
+	info := healthInfo{}
+	if err := session.getJson(ctx, "/health", &info); err != nil {
+		return chainInfo{}, err
+	}
+	chain, ok := info.Chains[blockchainId]
+	if !ok {
+		return chainInfo{}, fmt.Errorf("blockchain %s is not served by the node", blockchainId)
+	}
+	return chain, nil
 }
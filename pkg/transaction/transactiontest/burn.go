@@ -0,0 +1,45 @@
+package transactiontest
+
+import (
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// applyBurn destroys tokens of payload.From, or of the sender when it is empty. Burning for another
+// account consumes the sender's allowance on ERC20 tokens, needs an operator approval on ERC1155
+// tokens and an operator or token approval on ERC721 tokens.
+func (node *MockNode) applyBurn(input transaction.ULTransactionInput, payload transaction.BurnTokenPayload) transaction.UL_TransactionOutput {
+	token, ok := node.token(payload.TokenAddress)
+	if !ok || !token.Burnable {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	owner := input.From
+	if payload.From != "" {
+		owner = payload.From
+	}
+
+	switch token.TokenType {
+	case transaction.ERC20_TOKEN_TYPE:
+		if owner != input.From && !node.spendAllowance(payload.TokenAddress, owner, input.From, payload.Amount) {
+			return transaction.TX_REJECTED_BY_UNAUTHORIZED
+		}
+		if payload.Amount.IsZero() || !node.debit(payload.TokenAddress, owner, "", payload.Amount) {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+	case transaction.ERC1155_TOKEN_TYPE:
+		if owner != input.From && !node.isOperator(payload.TokenAddress, owner, input.From) {
+			return transaction.TX_REJECTED_BY_UNAUTHORIZED
+		}
+		amount, ok := payload.Amount.Uint64()
+		balances := node.multiToken(payload.TokenAddress).balances[payload.TokenId]
+		if !ok || amount == 0 || balances[owner] < amount {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		balances[owner] -= amount
+	case transaction.ERC721_TOKEN_TYPE:
+		return node.applyNFTBurn(input, token, owner, payload.TokenId)
+	default:
+		return transaction.TX_SUCCESS
+	}
+	token.TotalSupply, _ = token.TotalSupply.Sub(payload.Amount)
+	return transaction.TX_SUCCESS
+}
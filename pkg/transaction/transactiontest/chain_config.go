@@ -0,0 +1,60 @@
+package transactiontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// MOCK_BLOCK_TIME_MS is the block time the mock reports, it seals blocks on every submission
+const MOCK_BLOCK_TIME_MS = 1000
+
+// SetChainConfig replaces the config served for a chain
+func (node *MockNode) SetChainConfig(blockchainId string, config transaction.ChainConfig) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.chainConfigs[blockchainId] = config
+}
+
+// DisableChainConfig makes the config endpoint answer 404 like a node that predates it
+func (node *MockNode) DisableChainConfig() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.chainConfigDisabled = true
+}
+
+func (node *MockNode) handleChainConfig(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if node.chainConfigDisabled || !slices.Contains(node.chains, id) {
+		http.NotFound(w, r)
+		return
+	}
+	if config, ok := node.chainConfigs[id]; ok {
+		writeJson(w, http.StatusOK, config)
+		return
+	}
+	hash := sha256.Sum256([]byte(id))
+	members := node.committee(id)
+	writeJson(w, http.StatusOK, transaction.ChainConfig{
+		BlockchainId: id,
+		NodeVersion:  MOCK_NODE_VERSION,
+		Genesis: transaction.ChainGenesis{
+			BlockchainId: id,
+			CreatedAt:    node.started,
+			Hash:         hex.EncodeToString(hash[:]),
+		},
+		Consensus: transaction.ConsensusConfig{
+			Algorithm:        "mock",
+			BlockTimeMs:      MOCK_BLOCK_TIME_MS,
+			CommitteeSize:    len(members),
+			Quorum:           len(members)*2/3 + 1,
+			CommitteeMembers: members,
+		},
+		Features: node.features,
+	})
+}
@@ -0,0 +1,99 @@
+package transactiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// mockStreamEvent is an event of the stream of a chain, its id is its position in the stream from 1
+type mockStreamEvent struct {
+	event string
+	data  []byte
+}
+
+// publish appends an event to the stream of a chain and wakes the open streams. The caller holds node.mu.
+func (node *MockNode) publish(blockchainId string, eventType transaction.ChainEventType, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	node.streams[blockchainId] = append(node.streams[blockchainId], mockStreamEvent{event: string(eventType), data: data})
+	close(node.streamed)
+	node.streamed = make(chan struct{})
+}
+
+// publishBlock streams a sealed block followed by the confirmations of its transactions. The caller
+// holds node.mu.
+func (node *MockNode) publishBlock(blockchainId string, block transaction.ULBlock) {
+	node.publish(blockchainId, transaction.CHAIN_EVENT_BLOCK, block)
+	for _, tx := range block.Transactions {
+		node.publish(blockchainId, transaction.CHAIN_EVENT_TRANSACTION, tx)
+	}
+}
+
+// DropStreams closes the open event streams the way a restarting node would, clients reconnect
+func (node *MockNode) DropStreams() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	close(node.streamsDropped)
+	node.streamsDropped = make(chan struct{})
+}
+
+// handleEvents streams the changes of a chain as server-sent events from the Last-Event-ID on, or
+// from the next change. Nodes without NODE_FEATURE_EVENT_STREAM do not know the endpoint.
+func (node *MockNode) handleEvents(w http.ResponseWriter, r *http.Request) {
+	blockchainId := r.PathValue("id")
+	node.mu.Lock()
+	supported := slices.Contains(node.features, transaction.NODE_FEATURE_EVENT_STREAM)
+	next := len(node.streams[blockchainId])
+	node.mu.Unlock()
+	if !supported {
+		http.NotFound(w, r)
+		return
+	}
+	if lastEventId := r.Header.Get("Last-Event-ID"); lastEventId != "" {
+		id, err := strconv.Atoi(lastEventId)
+		if err != nil || id < 0 || id > next {
+			http.Error(w, "unknown event id", http.StatusBadRequest)
+			return
+		}
+		next = id
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		node.mu.Lock()
+		// The stream only grows, the events stay valid after unlocking
+		events := node.streams[blockchainId][next:]
+		streamed := node.streamed
+		dropped := node.streamsDropped
+		node.mu.Unlock()
+
+		for _, event := range events {
+			next++
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", next, event.event, event.data)
+		}
+		flusher.Flush()
+
+		select {
+		case <-streamed:
+		case <-dropped:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
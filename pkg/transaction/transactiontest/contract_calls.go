@@ -0,0 +1,69 @@
+package transactiontest
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// MOCK_CALL_GAS is the gas the mock node reports for every read-only call
+const MOCK_CALL_GAS = 1000
+
+// ContractView stands in for a read-only contract function, it receives the decoded arguments and
+// the storage of the contract, which it must not modify
+type ContractView func(state map[string]interface{}, args []interface{}) (interface{}, error)
+
+// SetContractView makes the read-only function functionName of a deployed contract answer with view
+func (node *MockNode) SetContractView(contractAddress string, functionName string, view ContractView) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if node.views[contractAddress] == nil {
+		node.views[contractAddress] = make(map[string]ContractView)
+	}
+	node.views[contractAddress][functionName] = view
+}
+
+// handleContractCall executes a view of a contract deployed on the mock node, the call is not recorded
+func (node *MockNode) handleContractCall(w http.ResponseWriter, r *http.Request) {
+	request := transaction.ContractCallRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	state, ok := node.contracts[r.PathValue("address")]
+	if !slices.Contains(node.features, transaction.NODE_FEATURE_CONTRACT_CALLS) || !ok {
+		http.Error(w, "contract not found", http.StatusNotFound)
+		return
+	}
+	view, ok := node.views[r.PathValue("address")][request.FunctionName]
+	if !ok {
+		http.Error(w, "the contract has no read-only function "+request.FunctionName, http.StatusBadRequest)
+		return
+	}
+	args := make([]interface{}, len(request.Args))
+	for i, arg := range request.Args {
+		value, err := transaction.Decode(arg.Value)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		args[i] = value
+	}
+
+	value, err := view(state, args)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+	result, err := transaction.Encode(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJson(w, http.StatusOK, transaction.ContractCallResult{Result: result, GasUsed: MOCK_CALL_GAS})
+}
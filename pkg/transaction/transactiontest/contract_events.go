@@ -0,0 +1,62 @@
+package transactiontest
+
+import (
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// ContractFunction stands in for a contract function invoked by transactions. It receives the
+// decoded arguments and the storage of the contract, which it may modify, and emit records an
+// event whose value is encoded with Encode. Returning an error fails the transaction.
+type ContractFunction func(state map[string]interface{}, args []interface{}, emit func(name string, value interface{})) error
+
+// SetContractFunction makes the invocations of functionName of a deployed contract run fn, the
+// events it emits are recorded in the transaction output when NODE_FEATURE_CONTRACT_EVENTS is set
+func (node *MockNode) SetContractFunction(contractAddress string, functionName string, fn ContractFunction) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if node.functions[contractAddress] == nil {
+		node.functions[contractAddress] = make(map[string]ContractFunction)
+	}
+	node.functions[contractAddress][functionName] = fn
+}
+
+// applyInvoke runs the function set for an invocation, the invocations of other functions are
+// accepted as is
+func (node *MockNode) applyInvoke(transactionId string, input transaction.ULTransactionInput, payload transaction.InvokeContractPayload) transaction.UL_TransactionOutput {
+	fn, ok := node.functions[input.To][payload.FunctionName]
+	if !ok {
+		return transaction.TX_SUCCESS
+	}
+	state, ok := node.contracts[input.To]
+	if !ok {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	args := make([]interface{}, len(payload.Args))
+	for i, arg := range payload.Args {
+		value, err := transaction.Decode(arg.Value)
+		if err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		args[i] = value
+	}
+
+	events := []transaction.ContractEventLog{}
+	var encodeErr error
+	emit := func(name string, value interface{}) {
+		data, err := transaction.Encode(value)
+		if err != nil {
+			encodeErr = err
+			return
+		}
+		events = append(events, transaction.ContractEventLog{ContractAddress: input.To, Name: name, Data: data})
+	}
+	if err := fn(state, args, emit); err != nil || encodeErr != nil {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if slices.Contains(node.features, transaction.NODE_FEATURE_CONTRACT_EVENTS) && len(events) > 0 {
+		node.events[transactionId] = events
+	}
+	return transaction.TX_SUCCESS
+}
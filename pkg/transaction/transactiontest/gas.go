@@ -0,0 +1,52 @@
+package transactiontest
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// MOCK_INVOKE_GAS is the gas the mock node reports for every simulated invocation
+const MOCK_INVOKE_GAS = 21000
+
+// handleEstimateGas simulates an invocation of a contract deployed on the mock node. The function
+// set with SetContractFunction runs on a copy of the storage and its events are dropped.
+func (node *MockNode) handleEstimateGas(w http.ResponseWriter, r *http.Request) {
+	request := transaction.GasEstimateRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	address := r.PathValue("address")
+	state, ok := node.contracts[address]
+	if !slices.Contains(node.features, transaction.NODE_FEATURE_GAS_ESTIMATION) || !ok {
+		http.Error(w, "contract not found", http.StatusNotFound)
+		return
+	}
+	if request.GasLimit > 0 && request.GasLimit < MOCK_INVOKE_GAS {
+		http.Error(w, "out of gas", http.StatusUnprocessableEntity)
+		return
+	}
+	if fn, ok := node.functions[address][request.FunctionName]; ok {
+		args := make([]interface{}, len(request.Args))
+		for i, arg := range request.Args {
+			value, err := transaction.Decode(arg.Value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			args[i] = value
+		}
+		if err := fn(maps.Clone(state), args, func(string, interface{}) {}); err != nil {
+			http.Error(w, transaction.TX_TRANSACTION_ERROR.String(), http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	writeJson(w, http.StatusOK, transaction.GasEstimateResult{GasUsed: MOCK_INVOKE_GAS})
+}
@@ -0,0 +1,897 @@
+package transactiontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+const (
+	MOCK_NODE_ID      = "mock-node"
+	MOCK_NODE_VERSION = "mock"
+)
+
+// MockNode is an in-process stand-in for a ULedger node. It implements the
+// endpoints used by UL_TransactionSession and a minimal token ledger so that
+// integrators can exercise duplicate and allowance handling without a network.
+type MockNode struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	chains       []string
+	blocks       map[string][]transaction.ULBlock
+	reorgs       int
+	seen         map[string]bool
+	transactions map[string]transaction.ULTransaction
+	// order holds transaction ids in submission order
+	order []string
+	// pending holds the ids of held transactions per chain, see HoldTransactions
+	pending    map[string][]string
+	hold       bool
+	balances   map[string]map[string]transaction.Amount
+	allowances map[string]map[string]map[string]transaction.Amount
+	tokens     map[string][]transaction.ULToken
+	// multiTokens holds the per id ledgers of ERC1155 tokens
+	multiTokens map[string]*mockMultiToken
+	// nfts holds the ledgers of ERC721 tokens
+	nfts map[string]*mockNFT
+	// operators holds the SET_APPROVAL_FOR_ALL approvals per token, owner then operator
+	operators map[string]map[string]map[string]bool
+	wallets   map[string][]transaction.ULWalletInfo
+	contracts map[string]map[string]interface{}
+	// views holds the read-only functions per contract, see SetContractView
+	views map[string]map[string]ContractView
+	// functions holds the functions invocations run per contract, see SetContractFunction
+	functions map[string]map[string]ContractFunction
+	// events holds the contract events of executed transactions until they are recorded
+	events map[string][]transaction.ContractEventLog
+	// multisigs holds the multisig accounts by address
+	multisigs map[string]*mockMultisig
+	// delegations maps delegation ids to the registered delegations, revoked ones are dropped
+	delegations map[string]transaction.SignedDelegation
+	features    []string
+	started     time.Time
+
+	// streams holds the events streamed per chain, streamed is closed and replaced on every event
+	// and streamsDropped by DropStreams
+	streams        map[string][]mockStreamEvent
+	streamed       chan struct{}
+	streamsDropped chan struct{}
+
+	// committees overrides the default committee of only the mock node, notVoting marks chains it abstains on
+	committees map[string][]string
+	notVoting  map[string]bool
+
+	chainConfigs        map[string]transaction.ChainConfig
+	chainConfigDisabled bool
+
+	uploads         map[string]*mockUpload
+	uploadsDisabled bool
+	failChunks      int
+}
+
+// NewMockNode starts a mock node serving the given blockchain ids
+func NewMockNode(blockchainIds ...string) *MockNode {
+	node := &MockNode{
+		chains:       blockchainIds,
+		blocks:       make(map[string][]transaction.ULBlock),
+		seen:         make(map[string]bool),
+		transactions: make(map[string]transaction.ULTransaction),
+		pending:      make(map[string][]string),
+		balances:     make(map[string]map[string]transaction.Amount),
+		allowances:   make(map[string]map[string]map[string]transaction.Amount),
+		tokens:       make(map[string][]transaction.ULToken),
+		multiTokens:  make(map[string]*mockMultiToken),
+		nfts:         make(map[string]*mockNFT),
+		operators:    make(map[string]map[string]map[string]bool),
+		wallets:      make(map[string][]transaction.ULWalletInfo),
+		contracts:    make(map[string]map[string]interface{}),
+		views:        make(map[string]map[string]ContractView),
+		functions:    make(map[string]map[string]ContractFunction),
+		events:       make(map[string][]transaction.ContractEventLog),
+		multisigs:    make(map[string]*mockMultisig),
+		delegations:  make(map[string]transaction.SignedDelegation),
+		uploads:      make(map[string]*mockUpload),
+		started:      time.Now().UTC(),
+		committees:   make(map[string][]string),
+		notVoting:    make(map[string]bool),
+		chainConfigs: make(map[string]transaction.ChainConfig),
+
+		streams:        make(map[string][]mockStreamEvent),
+		streamed:       make(chan struct{}),
+		streamsDropped: make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", node.handleHealth)
+	mux.HandleFunc("GET /blockchains", node.handleBlockchains)
+	mux.HandleFunc("POST /blockchains", node.handleCreateBlockchain)
+	mux.HandleFunc("GET /blockchains/{id}/config", node.handleChainConfig)
+	mux.HandleFunc("POST /blockchains/{id}/transactions", node.handleSubmit)
+	mux.HandleFunc("GET /blockchains/{id}/transactions/{txId}", node.handleTransaction)
+	mux.HandleFunc("POST /blockchains/{id}/transactions/{txId}/replace", node.handleReplace)
+	mux.HandleFunc("GET /blockchains/{id}/transactions", node.handleListTransactions)
+	mux.HandleFunc("GET /blockchains/{id}/blocks", node.handleListBlocks)
+	mux.HandleFunc("GET /blockchains/{id}/blocks/{height}", node.handleBlock)
+	mux.HandleFunc("GET /blockchains/{id}/blocks/latest", node.handleLatestBlock)
+	mux.HandleFunc("GET /blockchains/{id}/blocks/hash/{hash}", node.handleBlockByHash)
+	mux.HandleFunc("GET /blockchains/{id}/events", node.handleEvents)
+	mux.HandleFunc("GET /blockchains/{id}/tokens", node.handleListTokens)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}", node.handleToken)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/ids/{tokenId}", node.handleTokenId)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/ids/{tokenId}/holders", node.handleTokenHolders)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/ids/{tokenId}/owner", node.handleNFTOwner)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/ids/{tokenId}/approved", node.handleNFTApproved)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/balances", node.handleBalances)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/balances/{owner}", node.handleBalance)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/allowances/{owner}/{spender}", node.handleAllowance)
+	mux.HandleFunc("GET /blockchains/{id}/tokens/{address}/operators/{owner}/{operator}", node.handleOperator)
+	mux.HandleFunc("GET /blockchains/{id}/wallets", node.handleListWallets)
+	mux.HandleFunc("GET /blockchains/{id}/wallets/{address}", node.handleWallet)
+	mux.HandleFunc("GET /blockchains/{id}/multisigs/{account}", node.handleMultisig)
+	mux.HandleFunc("GET /blockchains/{id}/multisigs/{account}/proposals", node.handleMultisigProposals)
+	mux.HandleFunc("GET /blockchains/{id}/multisigs/{account}/proposals/{proposalId}", node.handleMultisigProposal)
+	mux.HandleFunc("POST /blockchains/{id}/contracts/{address}/call", node.handleContractCall)
+	mux.HandleFunc("POST /blockchains/{id}/contracts/{address}/estimate", node.handleEstimateGas)
+	mux.HandleFunc("POST /blockchains/{id}/uploads", node.handleUploadStart)
+	mux.HandleFunc("PUT /blockchains/{id}/uploads/{sha}", node.handleUploadChunk)
+	node.server = httptest.NewServer(mux)
+
+	return node
+}
+
+// URL returns the endpoint to pass to NewUL_TransactionSession
+func (node *MockNode) URL() string {
+	return node.server.URL
+}
+
+func (node *MockNode) Close() {
+	// Open event streams would keep the server from closing
+	node.DropStreams()
+	node.server.Close()
+}
+
+// SetBalance seeds the token balance of an address
+func (node *MockNode) SetBalance(tokenAddress string, owner string, amount uint64) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.ledger(tokenAddress)[owner] = transaction.NewAmount(amount)
+}
+
+// Balance returns the current token balance of an address, see BalanceAmount for balances that do
+// not fit a uint64
+func (node *MockNode) Balance(tokenAddress string, owner string) uint64 {
+	balance, _ := node.BalanceAmount(tokenAddress, owner).Uint64()
+	return balance
+}
+
+// BalanceAmount returns the current token balance of an address
+func (node *MockNode) BalanceAmount(tokenAddress string, owner string) transaction.Amount {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return node.balances[tokenAddress][owner]
+}
+
+// SetAllowance seeds the amount a spender may transfer on behalf of an owner
+func (node *MockNode) SetAllowance(tokenAddress string, owner string, spender string, amount uint64) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.setAllowance(tokenAddress, owner, spender, transaction.NewAmount(amount))
+}
+
+// Allowance returns the remaining amount a spender may transfer for an owner
+func (node *MockNode) Allowance(tokenAddress string, owner string, spender string) uint64 {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	allowance, _ := node.allowances[tokenAddress][owner][spender].Uint64()
+	return allowance
+}
+
+// SetFeatures sets the optional capabilities the node advertises on /health
+func (node *MockNode) SetFeatures(features ...string) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.features = features
+}
+
+// SetCommittee replaces the committee members reported for a chain, the mock node is in the
+// committee when MOCK_NODE_ID is one of them
+func (node *MockNode) SetCommittee(blockchainId string, members ...string) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.committees[blockchainId] = members
+}
+
+// SetVoting sets whether the mock node votes on a chain while it is in the committee
+func (node *MockNode) SetVoting(blockchainId string, voting bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.notVoting[blockchainId] = !voting
+}
+
+func (node *MockNode) committee(blockchainId string) []string {
+	if members, ok := node.committees[blockchainId]; ok {
+		return members
+	}
+	return []string{MOCK_NODE_ID}
+}
+
+// Delegation returns a registered delegation that has not been revoked
+func (node *MockNode) Delegation(delegationId string) (transaction.SignedDelegation, bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	delegation, ok := node.delegations[delegationId]
+	return delegation, ok
+}
+
+// HoldTransactions keeps new submissions pending instead of executing them, they are answered as
+// SUBMITTED and listed in pendingTransactions until DropPending or ReleasePending
+func (node *MockNode) HoldTransactions(hold bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.hold = hold
+}
+
+// DropPending forgets the pending transactions of a chain as if they were evicted from the mempool,
+// they can be submitted again afterwards
+func (node *MockNode) DropPending(blockchainId string) []string {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	dropped := node.pending[blockchainId]
+	delete(node.pending, blockchainId)
+	for _, id := range dropped {
+		tx := node.transactions[id]
+		delete(node.seen, fmt.Sprintf("%s|%s|%d", tx.From, tx.PayloadRoot, tx.SenderTimestamp.Unix()))
+		delete(node.transactions, id)
+		node.order = slices.DeleteFunc(node.order, func(ordered string) bool { return ordered == id })
+	}
+	return dropped
+}
+
+// ReleasePending executes and seals the pending transactions of a chain
+func (node *MockNode) ReleasePending(blockchainId string) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	released := node.pending[blockchainId]
+	delete(node.pending, blockchainId)
+	for _, id := range released {
+		tx := node.transactions[id]
+		output := node.apply(id, tx.ULTransactionInput)
+		tx.Output = output.String()
+		tx.Events = node.takeEvents(id)
+		tx.Status = transaction.TX_ACCEPTED.String()
+		if output != transaction.TX_SUCCESS {
+			tx.Status = transaction.TX_REJECTED.String()
+		}
+		node.transactions[id] = tx
+		if output == transaction.TX_SUCCESS {
+			node.appendBlock(blockchainId, tx)
+		}
+	}
+}
+
+// takeEvents returns and forgets the contract events of an executed transaction
+func (node *MockNode) takeEvents(transactionId string) []transaction.ContractEventLog {
+	events := node.events[transactionId]
+	delete(node.events, transactionId)
+	return events
+}
+
+// ContractState returns the storage of the contract deployed by a transaction
+func (node *MockNode) ContractState(transactionId string) (map[string]interface{}, bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	state, ok := node.contracts[transactionId]
+	return state, ok
+}
+
+// Transactions returns a copy of every transaction the node has processed in submission order
+func (node *MockNode) Transactions() []transaction.ULTransaction {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	txs := make([]transaction.ULTransaction, 0, len(node.order))
+	for _, id := range node.order {
+		txs = append(txs, node.transactions[id])
+	}
+	return txs
+}
+
+// ledger returns the ERC20 balances of a token, creating them on first use
+func (node *MockNode) ledger(tokenAddress string) map[string]transaction.Amount {
+	if node.balances[tokenAddress] == nil {
+		node.balances[tokenAddress] = make(map[string]transaction.Amount)
+	}
+	return node.balances[tokenAddress]
+}
+
+// debit moves amount from one balance to another, failing without a change when from holds less
+func (node *MockNode) debit(tokenAddress string, from string, to string, amount transaction.Amount) bool {
+	balances := node.ledger(tokenAddress)
+	remaining, err := balances[from].Sub(amount)
+	if err != nil {
+		return false
+	}
+	balances[from] = remaining
+	if to != "" {
+		balances[to] = balances[to].Add(amount)
+	}
+	return true
+}
+
+// spendAllowance consumes amount from the allowance owner granted spender
+func (node *MockNode) spendAllowance(tokenAddress string, owner string, spender string, amount transaction.Amount) bool {
+	remaining, err := node.allowances[tokenAddress][owner][spender].Sub(amount)
+	if err != nil {
+		return false
+	}
+	node.setAllowance(tokenAddress, owner, spender, remaining)
+	return true
+}
+
+func (node *MockNode) setAllowance(tokenAddress string, owner string, spender string, amount transaction.Amount) {
+	if node.allowances[tokenAddress] == nil {
+		node.allowances[tokenAddress] = make(map[string]map[string]transaction.Amount)
+	}
+	if node.allowances[tokenAddress][owner] == nil {
+		node.allowances[tokenAddress][owner] = make(map[string]transaction.Amount)
+	}
+	node.allowances[tokenAddress][owner][spender] = amount
+}
+
+func (node *MockNode) handleHealth(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	chains := make(map[string]any, len(node.chains))
+	for _, id := range node.chains {
+		members := node.committee(id)
+		inCommittee := slices.Contains(members, MOCK_NODE_ID)
+		chains[id] = map[string]any{
+			"blockHeight":         len(node.blocks[id]),
+			"pendingTransactions": append([]string{}, node.pending[id]...),
+			"committeeMembers":    members,
+			"isInCommittee":       inCommittee,
+			"isVoting":            inCommittee && !node.notVoting[id],
+		}
+	}
+
+	writeJson(w, http.StatusOK, map[string]any{
+		"nodeVersion": MOCK_NODE_VERSION,
+		"chainsInfo":  chains,
+		"nodeId":      MOCK_NODE_ID,
+		"peerId":      MOCK_NODE_ID,
+		"features":    node.features,
+	})
+}
+
+func (node *MockNode) handleBlockchains(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	writeJson(w, http.StatusOK, node.chains)
+}
+
+// handleCreateBlockchain adds an empty chain, creating an existing chain is a conflict
+func (node *MockNode) handleCreateBlockchain(w http.ResponseWriter, r *http.Request) {
+	request := struct {
+		BlockchainId string `json:"blockchainId"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.BlockchainId == "" {
+		http.Error(w, "a blockchain id is required", http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	for _, id := range node.chains {
+		if id == request.BlockchainId {
+			http.Error(w, "blockchain already exists", http.StatusConflict)
+			return
+		}
+	}
+	node.chains = append(node.chains, request.BlockchainId)
+	writeJson(w, http.StatusCreated, map[string]string{"blockchainId": request.BlockchainId})
+}
+
+func (node *MockNode) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	input := transaction.ULTransactionInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if input.BlockchainId != r.PathValue("id") {
+		http.Error(w, "blockchain id mismatch", http.StatusBadRequest)
+		return
+	}
+	node.submit(w, input)
+}
+
+// submit records input and answers with the transaction, executed unless transactions are held
+func (node *MockNode) submit(w http.ResponseWriter, input transaction.ULTransactionInput) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if !slices.Contains(node.chains, input.BlockchainId) {
+		http.Error(w, "blockchain not found", http.StatusNotFound)
+		return
+	}
+
+	// The commitment identity of a transaction is its author, payload root and timestamp
+	identity := fmt.Sprintf("%s|%s|%d", input.From, input.PayloadRoot, input.SenderTimestamp.Unix())
+	id := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", identity, input.SenderSignature, len(node.transactions))))
+	transactionId := hex.EncodeToString(id[:])
+	output := transaction.TX_SUCCESS
+	status := transaction.TX_ACCEPTED
+	switch {
+	case node.seen[identity]:
+		output = transaction.TX_REJECTED_BY_DUPLICATE
+	case node.hold:
+		node.seen[identity] = true
+		output = transaction.TO_BE_PROCESSED
+		status = transaction.TX_SUBMITTED
+		node.pending[input.BlockchainId] = append(node.pending[input.BlockchainId], transactionId)
+	default:
+		node.seen[identity] = true
+		output = node.apply(transactionId, input)
+	}
+	if output != transaction.TX_SUCCESS && output != transaction.TO_BE_PROCESSED {
+		status = transaction.TX_REJECTED
+	}
+
+	tx := transaction.ULTransaction{
+		ULTransactionInput: input,
+		ULTransactionOutput: transaction.ULTransactionOutput{
+			TransactionId: transactionId,
+			Timestamp:     transaction.Timestamp{ExactTime: time.Now().UTC(), ApproximateTime: time.Now().UTC()},
+			Version:       transaction.TRANSACTION_VERSION,
+			Status:        status.String(),
+			Output:        output.String(),
+			Events:        node.takeEvents(transactionId),
+		},
+	}
+	tx.SetTransactionWeight()
+	if status == transaction.TX_ACCEPTED {
+		// Every accepted transaction is sealed in its own block
+		tx.BlockHeight = node.appendBlock(input.BlockchainId, tx)
+	}
+	node.transactions[tx.TransactionId] = tx
+	node.order = append(node.order, tx.TransactionId)
+
+	writeJson(w, http.StatusCreated, tx)
+}
+
+func (node *MockNode) handleTransaction(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	tx, ok := node.transactions[r.PathValue("txId")]
+	if !ok || tx.BlockchainId != r.PathValue("id") {
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	}
+	writeJson(w, http.StatusOK, tx)
+}
+
+func (node *MockNode) handleBlock(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.Atoi(r.PathValue("height"))
+	if err != nil {
+		http.Error(w, "invalid height", http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	blocks := node.blocks[r.PathValue("id")]
+	if height < 1 || height > len(blocks) {
+		http.Error(w, "block not found", http.StatusNotFound)
+		return
+	}
+	writeJson(w, http.StatusOK, blocks[height-1])
+}
+
+func (node *MockNode) handleLatestBlock(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	blocks := node.blocks[r.PathValue("id")]
+	if len(blocks) == 0 {
+		http.Error(w, "block not found", http.StatusNotFound)
+		return
+	}
+	writeJson(w, http.StatusOK, blocks[len(blocks)-1])
+}
+
+func (node *MockNode) handleBlockByHash(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	for _, block := range node.blocks[r.PathValue("id")] {
+		if block.Hash == r.PathValue("hash") {
+			writeJson(w, http.StatusOK, block)
+			return
+		}
+	}
+	http.Error(w, "block not found", http.StatusNotFound)
+}
+
+// AppendEmptyBlock grows the chain by one block without transactions
+func (node *MockNode) AppendEmptyBlock(blockchainId string) int {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	return node.appendBlock(blockchainId)
+}
+
+// ReplaceBlock simulates a reorg: the block at height and every block after it are rebuilt with new
+// hashes. When dropTransactions is set the transactions of the replaced block are not included again.
+func (node *MockNode) ReplaceBlock(blockchainId string, height int, dropTransactions bool) error {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+
+	blocks := node.blocks[blockchainId]
+	if height < 1 || height > len(blocks) {
+		return fmt.Errorf("block %d not found", height)
+	}
+
+	replaced := blocks[height-1:]
+	node.blocks[blockchainId] = blocks[:height-1]
+	node.reorgs++
+	node.publish(blockchainId, transaction.CHAIN_EVENT_REORG, transaction.ChainReorg{Height: height, Hash: replaced[0].Hash, Depth: len(replaced)})
+	for i, block := range replaced {
+		txs := block.Transactions
+		if i == 0 && dropTransactions {
+			for _, tx := range txs {
+				tx.BlockHeight = 0
+				node.transactions[tx.TransactionId] = tx
+			}
+			txs = nil
+		}
+		node.appendBlock(blockchainId, txs...)
+	}
+	return nil
+}
+
+// appendBlock seals the transactions in a new block and returns its height, heights start at 1
+func (node *MockNode) appendBlock(blockchainId string, txs ...transaction.ULTransaction) int {
+	blocks := node.blocks[blockchainId]
+	previousHash := ""
+	if len(blocks) > 0 {
+		previousHash = blocks[len(blocks)-1].Hash
+	}
+
+	height := len(blocks) + 1
+	hasher := sha256.New()
+	fmt.Fprintf(hasher, "%s|%d|%d", previousHash, height, node.reorgs)
+	for i := range txs {
+		txs[i].BlockHeight = height
+		hasher.Write([]byte(txs[i].TransactionId))
+		if _, ok := node.transactions[txs[i].TransactionId]; ok {
+			node.transactions[txs[i].TransactionId] = txs[i]
+		}
+	}
+
+	block := transaction.ULBlock{
+		Hash:              hex.EncodeToString(hasher.Sum(nil)),
+		PreviousBlockHash: previousHash,
+		Height:            height,
+		Transactions:      txs,
+		MerkleRoot:        transaction.BlockMerkleRoot(txs),
+		Voters:            map[string]string{MOCK_NODE_ID: "yes"},
+	}
+	node.blocks[blockchainId] = append(blocks, block)
+	node.publishBlock(blockchainId, block)
+	return height
+}
+
+// apply executes the token semantics the mock understands, everything else is accepted as is
+func (node *MockNode) apply(transactionId string, input transaction.ULTransactionInput) transaction.UL_TransactionOutput {
+	if input.DelegationId != "" {
+		delegation, ok := node.delegations[input.DelegationId]
+		if !ok || delegation.Delegation.Delegator != input.From || delegation.Delegation.Allows(input, input.SenderTimestamp) != nil {
+			return transaction.TX_REJECTED_BY_UNAUTHORIZED
+		}
+	}
+
+	switch input.PayloadType {
+	case transaction.DELEGATE_KEY.String():
+		signed := transaction.SignedDelegation{}
+		if err := json.Unmarshal([]byte(input.Payload), &signed); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		if signed.Verify() != nil || signed.Delegation.Delegator != input.From {
+			return transaction.TX_REJECTED_BY_UNAUTHORIZED
+		}
+		node.delegations[signed.Delegation.Id()] = signed
+	case transaction.REVOKE_DELEGATION.String():
+		payload := transaction.RevokeDelegationPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		delegation, ok := node.delegations[payload.DelegationId]
+		if !ok || delegation.Delegation.Delegator != input.From {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		delete(node.delegations, payload.DelegationId)
+	case transaction.DEPLOY_SMART_CONTRACT.String():
+		contract := transaction.ContractSource{}
+		if err := json.Unmarshal([]byte(input.Payload), &contract); err != nil {
+			// Raw sources predate the envelope
+			node.contracts[transactionId] = map[string]interface{}{}
+			return transaction.TX_SUCCESS
+		}
+		state := map[string]interface{}{}
+		if slices.Contains(node.features, transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE) {
+			initial, err := contract.DecodeInitialState()
+			if err != nil {
+				return transaction.TX_TRANSACTION_ERROR
+			}
+			if initial != nil {
+				state = initial
+			}
+		}
+		node.contracts[transactionId] = state
+	case transaction.INVOKE_SMART_CONTRACT.String():
+		payload := transaction.InvokeContractPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyInvoke(transactionId, input, payload)
+	case transaction.TRANSFER_TOKEN.String(), transaction.TRANSFER_MULTI_TOKEN.String():
+		payload := transaction.TransferTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		if node.isMultiToken(payload.TokenAddress) {
+			return node.applyMultiTransfer(input, payload)
+		}
+		owner := input.From
+		if payload.From != "" && payload.From != input.From {
+			// Spending on behalf of someone else consumes the allowance first
+			owner = payload.From
+			if !node.spendAllowance(payload.TokenAddress, owner, input.From, payload.Amount) {
+				return transaction.TX_TRANSACTION_ERROR
+			}
+		}
+		if !node.debit(payload.TokenAddress, owner, payload.To, payload.Amount) {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+	case transaction.MINT_NFT.String():
+		payload := transaction.MintTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyNFTMint(payload)
+	case transaction.TRANSFER_NFT.String():
+		payload := transaction.TransferTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyNFTTransfer(input, payload)
+	case transaction.APPROVE_NFT.String():
+		payload := transaction.ApproveTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyNFTApprove(input, payload)
+	case transaction.APPROVE_TOKEN.String():
+		payload := transaction.ApproveTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		node.setAllowance(payload.TokenAddress, input.From, payload.Spender, payload.Amount)
+	case transaction.CREATE_TOKEN.String():
+		payload := transaction.CreateTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		address := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%s|%d", input.From, payload.Name, payload.Symbol, input.SenderTimestamp.UnixNano())))
+		token := transaction.ULToken{
+			TokenAddress: hex.EncodeToString(address[:]),
+			TokenMetadata: transaction.TokenMetadata{
+				TokenType:    payload.TokenType,
+				Name:         payload.Name,
+				Symbol:       payload.Symbol,
+				Decimals:     payload.Decimals,
+				Owner:        input.From,
+				BlockchainId: input.BlockchainId,
+				Mintable:     payload.Mintable,
+				Burnable:     payload.Burnable,
+				BaseURI:      payload.BaseURI,
+				TotalSupply:  payload.InitialSupply,
+				CreatedBlock: len(node.blocks[input.BlockchainId]) + 1,
+			},
+		}
+		node.tokens[input.BlockchainId] = append(node.tokens[input.BlockchainId], token)
+		if !payload.InitialSupply.IsZero() {
+			node.ledger(token.TokenAddress)[input.From] = payload.InitialSupply
+		}
+	case transaction.TX_CREATE_WALLET.String():
+		payload := transaction.CreateWalletPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		for _, registered := range node.wallets[input.BlockchainId] {
+			if registered.Address == input.To {
+				return transaction.TX_TRANSACTION_ERROR
+			}
+		}
+		node.wallets[input.BlockchainId] = append(node.wallets[input.BlockchainId], transaction.ULWalletInfo{
+			Address:      input.To,
+			Parent:       payload.Parent,
+			Enabled:      true,
+			KeyType:      payload.KeyType,
+			PublicKey:    payload.PublicKey,
+			AuthGroups:   payload.AuthGroups,
+			CreatedBlock: len(node.blocks[input.BlockchainId]) + 1,
+		})
+	case transaction.TX_ALTER_WALLET.String():
+		payload := transaction.AlterWalletPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		wallets := node.wallets[input.BlockchainId]
+		for i := range wallets {
+			if wallets[i].Address == payload.Target {
+				wallets[i].Enabled = payload.Enabled
+				if payload.AuthGroups != nil {
+					wallets[i].AuthGroups = payload.AuthGroups
+				}
+				return transaction.TX_SUCCESS
+			}
+		}
+		return transaction.TX_TRANSACTION_ERROR
+	case transaction.BURN_TOKEN.String():
+		payload := transaction.BurnTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyBurn(input, payload)
+	case transaction.CREATE_MULTISIG.String():
+		payload := transaction.CreateMultisigPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyCreateMultisig(transactionId, input, payload)
+	case transaction.PROPOSE_MULTISIG.String():
+		payload := transaction.ProposeMultisigPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyProposeMultisig(transactionId, input, payload)
+	case transaction.APPROVE_MULTISIG.String(), transaction.EXECUTE_MULTISIG.String():
+		payload := transaction.MultisigProposalPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		switch {
+		case input.PayloadType == transaction.APPROVE_MULTISIG.String() && payload.Action == transaction.MULTISIG_ACTION_APPROVE:
+			return node.applyApproveMultisig(input, payload)
+		case input.PayloadType == transaction.EXECUTE_MULTISIG.String() && payload.Action == transaction.MULTISIG_ACTION_EXECUTE:
+			return node.applyExecuteMultisig(transactionId, input, payload)
+		}
+		return transaction.TX_TRANSACTION_ERROR
+	case transaction.SET_APPROVAL_FOR_ALL.String():
+		payload := transaction.SetApprovalForAllPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyApprovalForAll(input, payload)
+	case transaction.MINT_MULTI_TOKEN.String():
+		payload := transaction.MintTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		return node.applyMultiMint(payload)
+	case transaction.MINT_TOKEN.String():
+		payload := transaction.MintTokenPayload{}
+		if err := json.Unmarshal([]byte(input.Payload), &payload); err != nil {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+		balances := node.ledger(payload.TokenAddress)
+		balances[payload.To] = balances[payload.To].Add(payload.Amount)
+		if token, ok := node.token(payload.TokenAddress); ok {
+			token.TotalSupply = token.TotalSupply.Add(payload.Amount)
+		}
+	}
+	return transaction.TX_SUCCESS
+}
+
+func (node *MockNode) handleListBlocks(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	writePage(w, r, node.blocks[r.PathValue("id")])
+}
+
+func (node *MockNode) handleListTransactions(w http.ResponseWriter, r *http.Request) {
+	address := r.URL.Query().Get("address")
+	payloadType := r.URL.Query().Get("payloadType")
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	txs := []transaction.ULTransaction{}
+	for _, block := range node.blocks[r.PathValue("id")] {
+		for _, tx := range block.Transactions {
+			if address != "" && tx.From != address && tx.To != address {
+				continue
+			}
+			if payloadType != "" && tx.PayloadType != payloadType {
+				continue
+			}
+			txs = append(txs, tx)
+		}
+	}
+	writePage(w, r, txs)
+}
+
+func (node *MockNode) handleListTokens(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	writePage(w, r, node.tokens[r.PathValue("id")])
+}
+
+func (node *MockNode) handleListWallets(w http.ResponseWriter, r *http.Request) {
+	parent := r.URL.Query().Get("parent")
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	wallets := []transaction.ULWalletInfo{}
+	for _, registered := range node.wallets[r.PathValue("id")] {
+		if parent == "" || registered.Parent == parent {
+			wallets = append(wallets, registered)
+		}
+	}
+	writePage(w, r, wallets)
+}
+
+func (node *MockNode) handleWallet(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	for _, registered := range node.wallets[r.PathValue("id")] {
+		if registered.Address == r.PathValue("address") {
+			writeJson(w, http.StatusOK, registered)
+			return
+		}
+	}
+	http.Error(w, "wallet not found", http.StatusNotFound)
+}
+
+// writePage serves the slice of items selected by the cursor and limit query parameters, the
+// mock's cursors are plain offsets
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	offset := 0
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		parsed, err := strconv.Atoi(cursor)
+		if err != nil || parsed < 0 {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		offset = parsed
+	}
+	limit := transaction.DEFAULT_PAGE_SIZE
+	if value := r.URL.Query().Get("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			http.Error(w, "invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	page := transaction.Page[T]{Items: []T{}, Total: len(items)}
+	if offset < len(items) {
+		end := min(offset+limit, len(items))
+		page.Items = items[offset:end]
+		if end < len(items) {
+			page.NextCursor = strconv.Itoa(end)
+		}
+	}
+	writeJson(w, http.StatusOK, page)
+}
+
+func writeJson(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
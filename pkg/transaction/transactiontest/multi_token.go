@@ -0,0 +1,180 @@
+package transactiontest
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// mockMultiToken is the ERC1155 ledger of one token, balances are per id then owner
+type mockMultiToken struct {
+	balances map[uint64]map[string]uint64
+	uris     map[uint64]string
+}
+
+// MultiTokenBalance returns the balance of one id of an ERC1155 token
+func (node *MockNode) MultiTokenBalance(tokenAddress string, tokenId uint64, owner string) uint64 {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if token, ok := node.multiTokens[tokenAddress]; ok {
+		return token.balances[tokenId][owner]
+	}
+	return 0
+}
+
+// token finds a token of any chain in the registry
+func (node *MockNode) token(tokenAddress string) (*transaction.ULToken, bool) {
+	for _, tokens := range node.tokens {
+		for i := range tokens {
+			if tokens[i].TokenAddress == tokenAddress {
+				return &tokens[i], true
+			}
+		}
+	}
+	return nil, false
+}
+
+func (node *MockNode) isMultiToken(tokenAddress string) bool {
+	token, ok := node.token(tokenAddress)
+	return ok && token.TokenType == transaction.ERC1155_TOKEN_TYPE
+}
+
+func (node *MockNode) multiToken(tokenAddress string) *mockMultiToken {
+	token, ok := node.multiTokens[tokenAddress]
+	if !ok {
+		token = &mockMultiToken{balances: make(map[uint64]map[string]uint64), uris: make(map[uint64]string)}
+		node.multiTokens[tokenAddress] = token
+	}
+	return token
+}
+
+func (node *MockNode) applyMultiMint(payload transaction.MintTokenPayload) transaction.UL_TransactionOutput {
+	registered, ok := node.token(payload.TokenAddress)
+	amount, fits := payload.Amount.Uint64()
+	if !ok || registered.TokenType != transaction.ERC1155_TOKEN_TYPE || !fits || amount == 0 {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	token := node.multiToken(payload.TokenAddress)
+	if token.balances[payload.TokenId] == nil {
+		token.balances[payload.TokenId] = make(map[string]uint64)
+	}
+	token.balances[payload.TokenId][payload.To] += amount
+	if payload.TokenURI != "" {
+		token.uris[payload.TokenId] = payload.TokenURI
+	}
+	registered.TotalSupply = registered.TotalSupply.Add(payload.Amount)
+	return transaction.TX_SUCCESS
+}
+
+// applyMultiTransfer moves single or batch amounts, nothing moves unless every amount is covered
+func (node *MockNode) applyMultiTransfer(input transaction.ULTransactionInput, payload transaction.TransferTokenPayload) transaction.UL_TransactionOutput {
+	ids, batch := payload.TokenIds, payload.Amounts
+	if len(ids) == 0 {
+		ids, batch = []uint64{payload.TokenId}, []transaction.Amount{payload.Amount}
+	}
+	amounts := make([]uint64, len(batch))
+	for i, amount := range batch {
+		var ok bool
+		if amounts[i], ok = amount.Uint64(); !ok {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+	}
+	owner := input.From
+	if payload.From != "" {
+		owner = payload.From
+	}
+	if len(ids) != len(amounts) {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if owner != input.From && !node.isOperator(payload.TokenAddress, owner, input.From) {
+		return transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+	token := node.multiToken(payload.TokenAddress)
+	needed := make(map[uint64]uint64, len(ids))
+	for i, id := range ids {
+		needed[id] += amounts[i]
+		if token.balances[id][owner] < needed[id] {
+			return transaction.TX_TRANSACTION_ERROR
+		}
+	}
+	for i, id := range ids {
+		token.balances[id][owner] -= amounts[i]
+		token.balances[id][payload.To] += amounts[i]
+	}
+	return transaction.TX_SUCCESS
+}
+
+// tokenIdRequest resolves the ERC1155 or ERC721 token and id of a request, answering 404 for unknown
+// ones
+func (node *MockNode) tokenIdRequest(w http.ResponseWriter, r *http.Request) (*transaction.ULToken, uint64, bool) {
+	tokenId, err := strconv.ParseUint(r.PathValue("tokenId"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid token id", http.StatusBadRequest)
+		return nil, 0, false
+	}
+	token, ok := node.token(r.PathValue("address"))
+	if !ok || token.BlockchainId != r.PathValue("id") || (token.TokenType != transaction.ERC1155_TOKEN_TYPE && token.TokenType != transaction.ERC721_TOKEN_TYPE) {
+		http.NotFound(w, r)
+		return nil, 0, false
+	}
+	return token, tokenId, true
+}
+
+func (node *MockNode) holders(tokenAddress string, tokenId uint64) []transaction.ULTokenHolder {
+	holders := []transaction.ULTokenHolder{}
+	if owner, ok := node.nft(tokenAddress).owners[tokenId]; ok {
+		return append(holders, transaction.ULTokenHolder{Owner: owner, Balance: transaction.NewAmount(1)})
+	}
+	if token, ok := node.multiTokens[tokenAddress]; ok {
+		for owner, balance := range token.balances[tokenId] {
+			if balance > 0 {
+				holders = append(holders, transaction.ULTokenHolder{Owner: owner, Balance: transaction.NewAmount(balance)})
+			}
+		}
+	}
+	slices.SortFunc(holders, func(a, b transaction.ULTokenHolder) int { return strings.Compare(a.Owner, b.Owner) })
+	return holders
+}
+
+func (node *MockNode) handleTokenId(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, tokenId, ok := node.tokenIdRequest(w, r)
+	if !ok {
+		return
+	}
+	info := transaction.ULTokenIdInfo{TokenAddress: token.TokenAddress, TokenId: tokenId}
+	for _, holder := range node.holders(token.TokenAddress, tokenId) {
+		info.TotalSupply = info.TotalSupply.Add(holder.Balance)
+		info.Holders++
+	}
+	if token.TokenType == transaction.ERC721_TOKEN_TYPE && info.Holders == 0 {
+		http.Error(w, "token not found", http.StatusNotFound)
+		return
+	}
+	info.URI = strings.ReplaceAll(token.BaseURI, "{id}", fmt.Sprintf("%064x", tokenId))
+	var uris map[uint64]string
+	if token.TokenType == transaction.ERC721_TOKEN_TYPE {
+		uris = node.nft(token.TokenAddress).uris
+	} else {
+		uris = node.multiToken(token.TokenAddress).uris
+	}
+	if uri := uris[tokenId]; uri != "" {
+		info.URI = uri
+	}
+	writeJson(w, http.StatusOK, info)
+}
+
+func (node *MockNode) handleTokenHolders(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, tokenId, ok := node.tokenIdRequest(w, r)
+	if !ok {
+		return
+	}
+	writePage(w, r, node.holders(token.TokenAddress, tokenId))
+}
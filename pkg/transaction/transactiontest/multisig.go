@@ -0,0 +1,173 @@
+package transactiontest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// mockMultisig is a multisig account with its proposals in the order they were made
+type mockMultisig struct {
+	account   transaction.MultisigAccount
+	proposals []*transaction.MultisigProposal
+}
+
+func (node *MockNode) multisigEnabled() bool {
+	return slices.Contains(node.features, transaction.NODE_FEATURE_MULTISIG)
+}
+
+// multisigOwner resolves the account of payload, the sender must be one of its owners
+func (node *MockNode) multisigOwner(input transaction.ULTransactionInput, account string) (*mockMultisig, transaction.UL_TransactionOutput) {
+	multisig, ok := node.multisigs[account]
+	if !node.multisigEnabled() || !ok || multisig.account.BlockchainId != input.BlockchainId || input.To != account {
+		return nil, transaction.TX_TRANSACTION_ERROR
+	}
+	if !slices.Contains(multisig.account.Owners, input.From) {
+		return nil, transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+	return multisig, transaction.TX_SUCCESS
+}
+
+func (multisig *mockMultisig) proposal(proposalId string) (*transaction.MultisigProposal, bool) {
+	for _, proposal := range multisig.proposals {
+		if proposal.ProposalId == proposalId {
+			return proposal, true
+		}
+	}
+	return nil, false
+}
+
+// applyCreateMultisig registers an account addressed by the creating transaction
+func (node *MockNode) applyCreateMultisig(transactionId string, input transaction.ULTransactionInput, payload transaction.CreateMultisigPayload) transaction.UL_TransactionOutput {
+	if !node.multisigEnabled() || payload.Validate() != nil {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	node.multisigs[transactionId] = &mockMultisig{account: transaction.MultisigAccount{
+		Address:      transactionId,
+		BlockchainId: input.BlockchainId,
+		Name:         payload.Name,
+		Owners:       payload.Owners,
+		Threshold:    payload.Threshold,
+		CreatedBlock: len(node.blocks[input.BlockchainId]) + 1,
+	}}
+	return transaction.TX_SUCCESS
+}
+
+// applyProposeMultisig records a proposal approved by its proposer
+func (node *MockNode) applyProposeMultisig(transactionId string, input transaction.ULTransactionInput, payload transaction.ProposeMultisigPayload) transaction.UL_TransactionOutput {
+	multisig, output := node.multisigOwner(input, payload.Account)
+	if output != transaction.TX_SUCCESS {
+		return output
+	}
+	multisig.proposals = append(multisig.proposals, &transaction.MultisigProposal{
+		ProposalId:  transactionId,
+		Account:     payload.Account,
+		Proposer:    input.From,
+		PayloadType: payload.PayloadType,
+		To:          payload.To,
+		Payload:     payload.Payload,
+		Description: payload.Description,
+		Approvals:   []string{input.From},
+		Status:      transaction.MULTISIG_PENDING,
+	})
+	return transaction.TX_SUCCESS
+}
+
+func (node *MockNode) applyApproveMultisig(input transaction.ULTransactionInput, payload transaction.MultisigProposalPayload) transaction.UL_TransactionOutput {
+	multisig, output := node.multisigOwner(input, payload.Account)
+	if output != transaction.TX_SUCCESS {
+		return output
+	}
+	proposal, ok := multisig.proposal(payload.ProposalId)
+	if !ok || proposal.Status != transaction.MULTISIG_PENDING || slices.Contains(proposal.Approvals, input.From) {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	proposal.Approvals = append(proposal.Approvals, input.From)
+	return transaction.TX_SUCCESS
+}
+
+// applyExecuteMultisig applies the proposed transaction as sent by the account, the execution has
+// the output of the proposed transaction
+func (node *MockNode) applyExecuteMultisig(transactionId string, input transaction.ULTransactionInput, payload transaction.MultisigProposalPayload) transaction.UL_TransactionOutput {
+	multisig, output := node.multisigOwner(input, payload.Account)
+	if output != transaction.TX_SUCCESS {
+		return output
+	}
+	proposal, ok := multisig.proposal(payload.ProposalId)
+	if !ok || proposal.Status != transaction.MULTISIG_PENDING {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if len(proposal.Approvals) < multisig.account.Threshold {
+		return transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+
+	output = node.apply(transactionId, transaction.ULTransactionInput{
+		BlockchainId:    input.BlockchainId,
+		From:            payload.Account,
+		To:              proposal.To,
+		Payload:         proposal.Payload,
+		PayloadType:     proposal.PayloadType,
+		SenderTimestamp: input.SenderTimestamp,
+	})
+	proposal.Status = transaction.MULTISIG_EXECUTED
+	if output != transaction.TX_SUCCESS {
+		proposal.Status = transaction.MULTISIG_FAILED
+	}
+	proposal.ExecutionId = transactionId
+	proposal.ExecutionOutput = output.String()
+	return output
+}
+
+// multisigRequest resolves the account of a request, answering 404 unless the module is enabled
+func (node *MockNode) multisigRequest(w http.ResponseWriter, r *http.Request) (*mockMultisig, bool) {
+	multisig, ok := node.multisigs[r.PathValue("account")]
+	if !node.multisigEnabled() || !ok || multisig.account.BlockchainId != r.PathValue("id") {
+		http.Error(w, "multisig account not found", http.StatusNotFound)
+		return nil, false
+	}
+	return multisig, true
+}
+
+func (node *MockNode) handleMultisig(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	multisig, ok := node.multisigRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, multisig.account)
+}
+
+func (node *MockNode) handleMultisigProposals(w http.ResponseWriter, r *http.Request) {
+	status := transaction.MultisigProposalStatus(r.URL.Query().Get("status"))
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	multisig, ok := node.multisigRequest(w, r)
+	if !ok {
+		return
+	}
+	proposals := []transaction.MultisigProposal{}
+	for _, proposal := range multisig.proposals {
+		if status == "" || proposal.Status == status {
+			proposals = append(proposals, *proposal)
+		}
+	}
+	writePage(w, r, proposals)
+}
+
+func (node *MockNode) handleMultisigProposal(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	multisig, ok := node.multisigRequest(w, r)
+	if !ok {
+		return
+	}
+	proposal, ok := multisig.proposal(r.PathValue("proposalId"))
+	if !ok {
+		http.Error(w, "proposal not found", http.StatusNotFound)
+		return
+	}
+	writeJson(w, http.StatusOK, proposal)
+}
@@ -0,0 +1,145 @@
+package transactiontest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// mockNFT is the ledger of one ERC721 token, per id its owner, approved account and minted URI
+type mockNFT struct {
+	owners    map[uint64]string
+	approvals map[uint64]string
+	uris      map[uint64]string
+}
+
+// NFTOwner returns the owner of an ERC721 token, false when it was never minted or was burned
+func (node *MockNode) NFTOwner(tokenAddress string, tokenId uint64) (string, bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	owner, ok := node.nft(tokenAddress).owners[tokenId]
+	return owner, ok
+}
+
+func (node *MockNode) nft(tokenAddress string) *mockNFT {
+	token, ok := node.nfts[tokenAddress]
+	if !ok {
+		token = &mockNFT{owners: make(map[uint64]string), approvals: make(map[uint64]string), uris: make(map[uint64]string)}
+		node.nfts[tokenAddress] = token
+	}
+	return token
+}
+
+// mayMoveNFT reports whether account may move the token tokenId of owner, as its owner, its
+// approved account or an operator of owner
+func (node *MockNode) mayMoveNFT(tokenAddress string, tokenId uint64, owner string, account string) bool {
+	return account == owner || node.nft(tokenAddress).approvals[tokenId] == account || node.isOperator(tokenAddress, owner, account)
+}
+
+// applyNFTMint gives an ERC721 token id that was never minted to payload.To
+func (node *MockNode) applyNFTMint(payload transaction.MintTokenPayload) transaction.UL_TransactionOutput {
+	registered, ok := node.token(payload.TokenAddress)
+	if !ok || registered.TokenType != transaction.ERC721_TOKEN_TYPE {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	token := node.nft(payload.TokenAddress)
+	if _, minted := token.owners[payload.TokenId]; minted {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	token.owners[payload.TokenId] = payload.To
+	if payload.TokenURI != "" {
+		token.uris[payload.TokenId] = payload.TokenURI
+	}
+	registered.TotalSupply = registered.TotalSupply.Add(transaction.NewAmount(1))
+	return transaction.TX_SUCCESS
+}
+
+// applyNFTTransfer moves an ERC721 token of the sender, or of payload.From as its approved account
+// or operator. The approval of the token is cleared.
+func (node *MockNode) applyNFTTransfer(input transaction.ULTransactionInput, payload transaction.TransferTokenPayload) transaction.UL_TransactionOutput {
+	token := node.nft(payload.TokenAddress)
+	owner, ok := token.owners[payload.TokenId]
+	if !ok || (payload.From != "" && payload.From != owner) {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if !node.mayMoveNFT(payload.TokenAddress, payload.TokenId, owner, input.From) {
+		return transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+	token.owners[payload.TokenId] = payload.To
+	delete(token.approvals, payload.TokenId)
+	return transaction.TX_SUCCESS
+}
+
+// applyNFTApprove lets payload.Spender move one ERC721 token, an empty spender clears the approval.
+// Only the owner and its operators may approve.
+func (node *MockNode) applyNFTApprove(input transaction.ULTransactionInput, payload transaction.ApproveTokenPayload) transaction.UL_TransactionOutput {
+	token := node.nft(payload.TokenAddress)
+	owner, ok := token.owners[payload.TokenId]
+	if !ok || payload.Spender == owner {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if input.From != owner && !node.isOperator(payload.TokenAddress, owner, input.From) {
+		return transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+	if payload.Spender == "" {
+		delete(token.approvals, payload.TokenId)
+	} else {
+		token.approvals[payload.TokenId] = payload.Spender
+	}
+	return transaction.TX_SUCCESS
+}
+
+// applyNFTBurn destroys an ERC721 token of owner with its approval and URI
+func (node *MockNode) applyNFTBurn(input transaction.ULTransactionInput, registered *transaction.ULToken, owner string, tokenId uint64) transaction.UL_TransactionOutput {
+	token := node.nft(registered.TokenAddress)
+	if token.owners[tokenId] != owner {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if !node.mayMoveNFT(registered.TokenAddress, tokenId, owner, input.From) {
+		return transaction.TX_REJECTED_BY_UNAUTHORIZED
+	}
+	delete(token.owners, tokenId)
+	delete(token.approvals, tokenId)
+	delete(token.uris, tokenId)
+	registered.TotalSupply, _ = registered.TotalSupply.Sub(transaction.NewAmount(1))
+	return transaction.TX_SUCCESS
+}
+
+// nftRequest resolves the ERC721 token and the minted id of a request, answering 404 otherwise
+func (node *MockNode) nftRequest(w http.ResponseWriter, r *http.Request) (*mockNFT, uint64, bool) {
+	tokenId, err := strconv.ParseUint(r.PathValue("tokenId"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid token id", http.StatusBadRequest)
+		return nil, 0, false
+	}
+	token, ok := node.tokenRequest(w, r)
+	if !ok {
+		return nil, 0, false
+	}
+	if _, minted := node.nft(token.TokenAddress).owners[tokenId]; token.TokenType != transaction.ERC721_TOKEN_TYPE || !minted {
+		http.Error(w, "token not found", http.StatusNotFound)
+		return nil, 0, false
+	}
+	return node.nft(token.TokenAddress), tokenId, true
+}
+
+func (node *MockNode) handleNFTOwner(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, tokenId, ok := node.nftRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]string{"owner": token.owners[tokenId]})
+}
+
+func (node *MockNode) handleNFTApproved(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, tokenId, ok := node.nftRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]string{"approved": token.approvals[tokenId]})
+}
@@ -0,0 +1,43 @@
+package transactiontest
+
+import (
+	"net/http"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// applyApprovalForAll records or withdraws an operator approval of the sender on a registered token
+func (node *MockNode) applyApprovalForAll(input transaction.ULTransactionInput, payload transaction.SetApprovalForAllPayload) transaction.UL_TransactionOutput {
+	if _, ok := node.token(payload.TokenAddress); !ok || payload.Operator == input.From {
+		return transaction.TX_TRANSACTION_ERROR
+	}
+	if node.operators[payload.TokenAddress] == nil {
+		node.operators[payload.TokenAddress] = make(map[string]map[string]bool)
+	}
+	if node.operators[payload.TokenAddress][input.From] == nil {
+		node.operators[payload.TokenAddress][input.From] = make(map[string]bool)
+	}
+	if payload.Approved {
+		node.operators[payload.TokenAddress][input.From][payload.Operator] = true
+	} else {
+		delete(node.operators[payload.TokenAddress][input.From], payload.Operator)
+	}
+	return transaction.TX_SUCCESS
+}
+
+func (node *MockNode) isOperator(tokenAddress string, owner string, operator string) bool {
+	return node.operators[tokenAddress][owner][operator]
+}
+
+func (node *MockNode) handleOperator(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, ok := node.token(r.PathValue("address"))
+	if !ok || token.BlockchainId != r.PathValue("id") {
+		http.NotFound(w, r)
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]bool{
+		"approved": node.isOperator(token.TokenAddress, r.PathValue("owner"), r.PathValue("operator")),
+	})
+}
@@ -0,0 +1,201 @@
+package transactiontest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// RaceSubmission is a single transaction that takes part in a race
+type RaceSubmission struct {
+	Label   string
+	Session *transaction.UL_TransactionSession
+	Input   transaction.ULTransactionInput
+}
+
+// RaceOutcome records what happened to a submission once the race finished
+type RaceOutcome struct {
+	Label       string
+	Order       int // Position in which the node answered, starting at 0
+	Transaction transaction.ULTransaction
+	Output      transaction.UL_TransactionOutput
+	Err         error
+	Latency     time.Duration
+}
+
+// RaceReport summarizes the ordering outcomes of a race
+type RaceReport struct {
+	Outcomes []RaceOutcome // Sorted by answer order
+	Accepted int
+	Rejected int
+	Failed   int // Submissions that never got a transaction back from the node
+	ByOutput map[transaction.UL_TransactionOutput]int
+}
+
+// Winners returns the labels of the submissions the node accepted, in answer order
+func (r RaceReport) Winners() []string {
+	labels := make([]string, 0, r.Accepted)
+	for _, outcome := range r.Outcomes {
+		if outcome.Err == nil && outcome.Output == transaction.TX_SUCCESS {
+			labels = append(labels, outcome.Label)
+		}
+	}
+	return labels
+}
+
+func (r RaceReport) String() string {
+	s := fmt.Sprintf("accepted=%d rejected=%d failed=%d", r.Accepted, r.Rejected, r.Failed)
+	for _, outcome := range r.Outcomes {
+		if outcome.Err != nil {
+			s += fmt.Sprintf("\n  #%d %s: error %v", outcome.Order, outcome.Label, outcome.Err)
+			continue
+		}
+		s += fmt.Sprintf("\n  #%d %s: %s %s (%s)", outcome.Order, outcome.Label, outcome.Transaction.Status, outcome.Output, outcome.Latency)
+	}
+	return s
+}
+
+// RunRace releases all submissions at the same instant and collects how the node ordered them.
+// The context only guards the start of the race, submissions already in flight run to completion.
+func RunRace(ctx context.Context, submissions []RaceSubmission) (RaceReport, error) {
+	if len(submissions) == 0 {
+		return RaceReport{}, fmt.Errorf("no submissions to race")
+	}
+
+	start := make(chan struct{})
+	aborted := false
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	order := 0
+	outcomes := make([]RaceOutcome, len(submissions))
+
+	for i, submission := range submissions {
+		wg.Add(1)
+		go func(i int, submission RaceSubmission) {
+			defer wg.Done()
+			<-start
+			if aborted {
+				return
+			}
+			began := time.Now()
+			tx, err := submission.Session.GenerateTransaction(submission.Input)
+			latency := time.Since(began)
+
+			mu.Lock()
+			defer mu.Unlock()
+			outcome := RaceOutcome{
+				Label:       submission.Label,
+				Order:       order,
+				Transaction: tx,
+				Err:         err,
+				Latency:     latency,
+			}
+			order++
+			if err == nil {
+				outcome.Output, outcome.Err = transaction.ParseTransactionOutput(tx.Output)
+			}
+			outcomes[i] = outcome
+		}(i, submission)
+	}
+
+	if ctx.Err() != nil {
+		// Release the goroutines without submitting anything
+		aborted = true
+		close(start)
+		wg.Wait()
+		return RaceReport{}, ctx.Err()
+	}
+	close(start)
+	wg.Wait()
+
+	sort.Slice(outcomes, func(a, b int) bool { return outcomes[a].Order < outcomes[b].Order })
+	report := RaceReport{
+		Outcomes: outcomes,
+		ByOutput: make(map[transaction.UL_TransactionOutput]int),
+	}
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			report.Failed++
+			continue
+		}
+		report.ByOutput[outcome.Output]++
+		if outcome.Output == transaction.TX_SUCCESS {
+			report.Accepted++
+		} else {
+			report.Rejected++
+		}
+	}
+	return report, nil
+}
+
+// DoubleSpend builds transfers of the same amount from the session's wallet to each recipient,
+// together they should exceed the balance so only some of them may be accepted
+func DoubleSpend(session *transaction.UL_TransactionSession, blockchainId string, tokenAddress string, amount uint64, recipients ...string) ([]RaceSubmission, error) {
+	submissions := make([]RaceSubmission, 0, len(recipients))
+	for i, recipient := range recipients {
+		payload, err := json.Marshal(transaction.TransferTokenPayload{
+			TokenAddress: tokenAddress,
+			To:           recipient,
+			Amount:       transaction.NewAmount(amount),
+		})
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, RaceSubmission{
+			Label:   fmt.Sprintf("transfer-%d", i),
+			Session: session,
+			Input: transaction.ULTransactionInput{
+				BlockchainId: blockchainId,
+				To:           tokenAddress,
+				Payload:      string(payload),
+				PayloadType:  transaction.TRANSFER_TOKEN.String(),
+			},
+		})
+	}
+	return submissions, nil
+}
+
+// AllowanceRace builds transfers of amount on behalf of owner from the spender session to each
+// recipient, together they should exceed the allowance of the spender so only some of them may be
+// accepted
+func AllowanceRace(spender *transaction.UL_TransactionSession, blockchainId string, tokenAddress string, owner string, amount uint64, recipients ...string) ([]RaceSubmission, error) {
+	submissions := make([]RaceSubmission, 0, len(recipients))
+	for i, recipient := range recipients {
+		payload, err := json.Marshal(transaction.TransferTokenPayload{
+			TokenAddress: tokenAddress,
+			From:         owner,
+			To:           recipient,
+			Amount:       transaction.NewAmount(amount),
+		})
+		if err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, RaceSubmission{
+			Label:   fmt.Sprintf("allowance-%d", i),
+			Session: spender,
+			Input: transaction.ULTransactionInput{
+				BlockchainId: blockchainId,
+				To:           tokenAddress,
+				Payload:      string(payload),
+				PayloadType:  transaction.TRANSFER_TOKEN.String(),
+			},
+		})
+	}
+	return submissions, nil
+}
+
+// Replay returns the same submission n times, which the node should reject as duplicates
+// whenever two copies land within the same timestamp second
+func Replay(submission RaceSubmission, n int) []RaceSubmission {
+	submissions := make([]RaceSubmission, n)
+	for i := range submissions {
+		submissions[i] = submission
+		submissions[i].Label = fmt.Sprintf("%s-%d", submission.Label, i)
+	}
+	return submissions
+}
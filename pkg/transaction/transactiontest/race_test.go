@@ -0,0 +1,148 @@
+package transactiontest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	testBlockchainId = "MyBlockchain1"
+	testToken        = "token"
+)
+
+func newTestSession(t *testing.T, node *MockNode, privateKeyHex string, publicKeyHex string) *transaction.UL_TransactionSession {
+	t.Helper()
+	w, err := wallet.GetWalletFromHex(publicKeyHex, privateKeyHex, crypto.KeyTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("GetWalletFromHex() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	return &session
+}
+
+func TestRunRace(t *testing.T) {
+	tests := []struct {
+		name         string
+		allowance    bool
+		balance      uint64
+		amount       uint64
+		racers       int
+		wantAccepted int
+	}{
+		{name: "double spend", balance: 100, amount: 80, racers: 3, wantAccepted: 1},
+		{name: "enough balance", balance: 300, amount: 80, racers: 3, wantAccepted: 3},
+		{name: "allowance race", allowance: true, balance: 1000, amount: 60, racers: 3, wantAccepted: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node := NewMockNode(testBlockchainId)
+			defer node.Close()
+
+			owner := newTestSession(t, node,
+				"46871FC92D83F41BEC1BE9C820BEBAF1DF906CDA4E11A5E66784B09C3C6B1F76",
+				"042D14822C75648ACCC0E44BAE5312D11000351A302AE047A2D0B55984F6D9D392178B12427749ACB67E3A15F4C0EBDD23BE7DBCFAC82826A5FD3055F81B4ACC82")
+			spender := newTestSession(t, node,
+				"8511885EE2FFBACE539EA454C5C1FEC54F04EE57F8820F910E9AE842C7F71972",
+				"04CB435FDF7D9AE78F4D6A6CCE3CC4AB9E21B8577EFAE2DD628D4093230010FF3394D9D3F14E8665D927ABB93E09835AD4A1565446A4F173CC03061D0467C469A3")
+			ownerAddress := owner.GetWallet().Address
+			node.SetBalance(testToken, ownerAddress, tt.balance)
+
+			// Distinct recipients keep the payloads apart, so the node cannot reject them as duplicates
+			recipients := make([]string, tt.racers)
+			for i := range recipients {
+				recipients[i] = fmt.Sprintf("recipient-%d", i)
+			}
+			var submissions []RaceSubmission
+			var err error
+			if tt.allowance {
+				node.SetAllowance(testToken, ownerAddress, spender.GetWallet().Address, 100)
+				submissions, err = AllowanceRace(spender, testBlockchainId, testToken, ownerAddress, tt.amount, recipients...)
+			} else {
+				submissions, err = DoubleSpend(owner, testBlockchainId, testToken, tt.amount, recipients...)
+			}
+			if err != nil {
+				t.Fatalf("building submissions error = %v", err)
+			}
+
+			report, err := RunRace(context.Background(), submissions)
+			if err != nil {
+				t.Fatalf("RunRace() error = %v", err)
+			}
+			if report.Failed != 0 {
+				t.Fatalf("RunRace() failed submissions:\n%s", report)
+			}
+			if report.Accepted != tt.wantAccepted {
+				t.Errorf("RunRace() accepted = %d, want %d\n%s", report.Accepted, tt.wantAccepted, report)
+			}
+			if report.Accepted+report.Rejected != tt.racers {
+				t.Errorf("RunRace() answered %d submissions, want %d", report.Accepted+report.Rejected, tt.racers)
+			}
+			// The losers ran out of balance or allowance, they were not rejected for another reason
+			if report.ByOutput[transaction.TX_TRANSACTION_ERROR] != tt.racers-tt.wantAccepted {
+				t.Errorf("RunRace() outputs = %v, want %d insufficient funds errors", report.ByOutput, tt.racers-tt.wantAccepted)
+			}
+			if tt.allowance && node.Allowance(testToken, ownerAddress, spender.GetWallet().Address) != 100-tt.amount {
+				t.Errorf("allowance after the race = %d, want %d", node.Allowance(testToken, ownerAddress, spender.GetWallet().Address), 100-tt.amount)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	node := NewMockNode(testBlockchainId)
+	defer node.Close()
+	session := newTestSession(t, node,
+		"46871FC92D83F41BEC1BE9C820BEBAF1DF906CDA4E11A5E66784B09C3C6B1F76",
+		"042D14822C75648ACCC0E44BAE5312D11000351A302AE047A2D0B55984F6D9D392178B12427749ACB67E3A15F4C0EBDD23BE7DBCFAC82826A5FD3055F81B4ACC82")
+	submission := RaceSubmission{Label: "payment", Session: session, Input: transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "pay once",
+		PayloadType:  transaction.TX_DATA.String(),
+	}}
+
+	// Copies are only duplicates within the timestamp second of the original, start a fresh one
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	committed, err := session.GenerateTransaction(submission.Input)
+	if err != nil || committed.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("GenerateTransaction() = %s, %v", committed.Output, err)
+	}
+
+	report, err := RunRace(context.Background(), Replay(submission, 3))
+	if err != nil {
+		t.Fatalf("RunRace() error = %v", err)
+	}
+	if report.Accepted != 0 || report.ByOutput[transaction.TX_REJECTED_BY_DUPLICATE] != 3 {
+		t.Fatalf("RunRace() of replays = %v, want every copy rejected as a duplicate\n%s", report.ByOutput, report)
+	}
+	for _, outcome := range report.Outcomes {
+		if outcome.Transaction.Status != transaction.TX_REJECTED.String() {
+			t.Errorf("replay %s status = %s, want %s", outcome.Label, outcome.Transaction.Status, transaction.TX_REJECTED)
+		}
+	}
+}
+
+func TestRunRaceCancelled(t *testing.T) {
+	node := NewMockNode(testBlockchainId)
+	defer node.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := RunRace(ctx, []RaceSubmission{{Label: "never"}})
+	if err == nil {
+		t.Error("RunRace() expected an error for a cancelled context")
+	}
+	if len(node.Transactions()) != 0 {
+		t.Error("RunRace() submitted transactions after the context was cancelled")
+	}
+}
@@ -0,0 +1,58 @@
+package transactiontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// handleReplace evicts a held transaction and submits the replacement signed by the same sender.
+// Nodes without NODE_FEATURE_TRANSACTION_REPLACEMENT do not know the endpoint.
+func (node *MockNode) handleReplace(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	supported := slices.Contains(node.features, transaction.NODE_FEATURE_TRANSACTION_REPLACEMENT)
+	node.mu.Unlock()
+	if !supported {
+		http.NotFound(w, r)
+		return
+	}
+
+	input := transaction.ULTransactionInput{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	id := r.PathValue("txId")
+	original, ok := node.transactions[id]
+	if !ok || original.BlockchainId != r.PathValue("id") || input.BlockchainId != original.BlockchainId {
+		node.mu.Unlock()
+		http.Error(w, "transaction not found", http.StatusNotFound)
+		return
+	}
+	if original.From != input.From {
+		node.mu.Unlock()
+		http.Error(w, "only the sender may replace a transaction", http.StatusForbidden)
+		return
+	}
+	pending := node.pending[original.BlockchainId]
+	if !slices.Contains(pending, id) {
+		node.mu.Unlock()
+		http.Error(w, "transaction is no longer pending", http.StatusConflict)
+		return
+	}
+	node.pending[original.BlockchainId] = slices.DeleteFunc(pending, func(pendingId string) bool { return pendingId == id })
+	// The replacement may carry the same payload, it is not a duplicate of the evicted original
+	delete(node.seen, fmt.Sprintf("%s|%s|%d", original.From, original.PayloadRoot, original.SenderTimestamp.Unix()))
+	original.Status = transaction.TX_REJECTED.String()
+	original.Output = transaction.TX_REJECTED_BY_REPLACEMENT.String()
+	node.transactions[id] = original
+	node.mu.Unlock()
+
+	// The replacement goes through the regular submission, held or executed
+	node.submit(w, input)
+}
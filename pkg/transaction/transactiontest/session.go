@@ -0,0 +1,24 @@
+package transactiontest
+
+import (
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// NewSession returns a session on the node signing with a new wallet of keyType. parent is the
+// parent address of the wallet and may be empty.
+func (node *MockNode) NewSession(t testing.TB, keyType crypto.KeyType, parent string) (*transaction.UL_TransactionSession, wallet.UL_Wallet) {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", keyType, parent, nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	return &session, w
+}
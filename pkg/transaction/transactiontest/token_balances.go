@@ -0,0 +1,97 @@
+package transactiontest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// balance returns the balance of owner whatever the token type, tokenId selects the ERC1155 id
+func (node *MockNode) balance(token *transaction.ULToken, owner string, tokenId uint64) transaction.TokenBalance {
+	balance := transaction.TokenBalance{TokenAddress: token.TokenAddress, Owner: owner}
+	switch token.TokenType {
+	case transaction.ERC721_TOKEN_TYPE:
+		count := uint64(0)
+		for _, nftOwner := range node.nft(token.TokenAddress).owners {
+			if nftOwner == owner {
+				count++
+			}
+		}
+		balance.Balance = transaction.NewAmount(count)
+	case transaction.ERC1155_TOKEN_TYPE:
+		balance.TokenId = tokenId
+		balance.Balance = transaction.NewAmount(node.multiToken(token.TokenAddress).balances[tokenId][owner])
+	default:
+		balance.Balance = node.balances[token.TokenAddress][owner]
+	}
+	return balance
+}
+
+// tokenRequest resolves the token of a request, answering 404 for tokens of other chains
+func (node *MockNode) tokenRequest(w http.ResponseWriter, r *http.Request) (*transaction.ULToken, bool) {
+	token, ok := node.token(r.PathValue("address"))
+	if !ok || token.BlockchainId != r.PathValue("id") {
+		http.Error(w, "token not found", http.StatusNotFound)
+		return nil, false
+	}
+	return token, true
+}
+
+func (node *MockNode) handleToken(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, ok := node.tokenRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, token)
+}
+
+func (node *MockNode) handleBalance(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, ok := node.tokenRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, node.balance(token, r.PathValue("owner"), 0))
+}
+
+// handleBalances answers the balances of the owner and tokenId query parameters, paired by position
+func (node *MockNode) handleBalances(w http.ResponseWriter, r *http.Request) {
+	owners, ids := r.URL.Query()["owner"], r.URL.Query()["tokenId"]
+	if len(ids) != len(owners) {
+		http.Error(w, "every owner needs a token id", http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, ok := node.tokenRequest(w, r)
+	if !ok {
+		return
+	}
+	balances := make([]transaction.TokenBalance, len(owners))
+	for i, owner := range owners {
+		tokenId, err := strconv.ParseUint(ids[i], 10, 64)
+		if err != nil {
+			http.Error(w, "invalid token id", http.StatusBadRequest)
+			return
+		}
+		balances[i] = node.balance(token, owner, tokenId)
+	}
+	writeJson(w, http.StatusOK, balances)
+}
+
+func (node *MockNode) handleAllowance(w http.ResponseWriter, r *http.Request) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	token, ok := node.tokenRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJson(w, http.StatusOK, map[string]transaction.Amount{
+		"allowance": node.allowances[token.TokenAddress][r.PathValue("owner")][r.PathValue("spender")],
+	})
+}
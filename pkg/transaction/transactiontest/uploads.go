@@ -0,0 +1,105 @@
+package transactiontest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+type mockUpload struct {
+	status transaction.UploadStatus
+	data   []byte
+}
+
+// DisableUploads makes the upload endpoints answer 404 like a node without chunked uploads
+func (node *MockNode) DisableUploads() {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.uploadsDisabled = true
+}
+
+// FailUploadChunks makes the next n chunk uploads fail with a 500
+func (node *MockNode) FailUploadChunks(n int) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	node.failChunks = n
+}
+
+// Upload returns a completed upload by blob hash
+func (node *MockNode) Upload(sha string) ([]byte, bool) {
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	upload, ok := node.uploads[sha]
+	if !ok || upload.status.Received != upload.status.Size {
+		return nil, false
+	}
+	return upload.data, true
+}
+
+func (node *MockNode) handleUploadStart(w http.ResponseWriter, r *http.Request) {
+	request := transaction.UploadStatus{}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	if node.uploadsDisabled {
+		http.NotFound(w, r)
+		return
+	}
+	upload, ok := node.uploads[request.Sha256]
+	if !ok {
+		request.Received = 0
+		upload = &mockUpload{status: request}
+		node.uploads[request.Sha256] = upload
+	}
+	writeJson(w, http.StatusOK, upload.status)
+}
+
+func (node *MockNode) handleUploadChunk(w http.ResponseWriter, r *http.Request) {
+	chunk, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	offset, err := strconv.ParseInt(r.URL.Query().Get("offset"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	node.mu.Lock()
+	defer node.mu.Unlock()
+	upload, ok := node.uploads[r.PathValue("sha")]
+	if node.uploadsDisabled || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	if node.failChunks > 0 {
+		node.failChunks--
+		http.Error(w, "chunk lost", http.StatusInternalServerError)
+		return
+	}
+	if offset != upload.status.Received || offset+int64(len(chunk)) > upload.status.Size {
+		http.Error(w, "unexpected offset", http.StatusConflict)
+		return
+	}
+	upload.data = append(upload.data, chunk...)
+	upload.status.Received += int64(len(chunk))
+	if upload.status.Received == upload.status.Size {
+		digest := sha256.Sum256(upload.data)
+		if hex.EncodeToString(digest[:]) != upload.status.Sha256 {
+			delete(node.uploads, upload.status.Sha256)
+			http.Error(w, "upload hash mismatch", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	writeJson(w, http.StatusOK, upload.status)
+}
@@ -0,0 +1,215 @@
+package transaction
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// TransportConfig tunes the connections a session keeps open to its node. Latency sensitive
+// clients create their session with NewUL_TransactionSessionWithTransport so the first submission
+// does not pay for the TCP and TLS handshakes.
+type TransportConfig struct {
+	// HTTP2 negotiates HTTP/2 with TLS nodes, requests are multiplexed on one connection.
+	// HTTP/1.1 stays available for nodes that do not offer HTTP/2.
+	HTTP2 bool
+	// UnencryptedHTTP2 speaks HTTP/2 without TLS (h2c) to http:// endpoints, e.g. a node behind
+	// a local proxy. The node must support it, HTTP/1.1 is not attempted.
+	UnencryptedHTTP2 bool
+	TLS              *tls.Config
+	// MaxIdleConnsPerHost is how many idle connections are kept for reuse
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	// PingInterval sends HTTP/2 pings on idle connections so a dead connection is detected
+	// before a submission uses it, zero disables the pings
+	PingInterval time.Duration
+	// WarmupConnections is how many connections SetTransport opens ahead of the first request
+	WarmupConnections int
+}
+
+var DEFAULT_TRANSPORT_CONFIG = TransportConfig{
+	HTTP2:               true,
+	MaxIdleConnsPerHost: 64,
+	IdleConnTimeout:     90 * time.Second,
+	WarmupConnections:   1,
+}
+
+// NewTransport builds the http.Transport described by config
+func NewTransport(config TransportConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(!config.UnencryptedHTTP2)
+	protocols.SetHTTP2(config.HTTP2 || config.UnencryptedHTTP2)
+	protocols.SetUnencryptedHTTP2(config.UnencryptedHTTP2)
+	transport.Protocols = protocols
+	if config.TLS != nil {
+		transport.TLSClientConfig = config.TLS.Clone()
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+		transport.MaxIdleConns = max(transport.MaxIdleConns, config.MaxIdleConnsPerHost)
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	if config.PingInterval > 0 {
+		transport.HTTP2 = &http.HTTP2Config{SendPingTimeout: config.PingInterval}
+	}
+	return transport
+}
+
+// NewUL_TransactionSessionWithTransport is NewUL_TransactionSession over a transport built from
+// config, bounded by ctx. The handshakes happen while the session is created, it then keeps
+// config.WarmupConnections connections open.
+func NewUL_TransactionSessionWithTransport(ctx context.Context, nodeEndpoint string, wallet wallet.UL_Wallet, config TransportConfig) (UL_TransactionSession, error) {
+	return newSession(ctx, nodeEndpoint, wallet, SessionOptions{Transport: &config})
+}
+
+// SetTransport replaces the connections of the session with a transport built from config and
+// opens config.WarmupConnections of them. The transport stays installed when the warmup fails.
+func (session *UL_TransactionSession) SetTransport(ctx context.Context, config TransportConfig) error {
+	if session.ownsClient {
+		session.httpClient.CloseIdleConnections()
+	}
+	session.httpClient = &http.Client{Transport: NewTransport(config)}
+	session.ownsClient = true
+	if config.WarmupConnections > 0 {
+		return session.Warmup(ctx, config.WarmupConnections)
+	}
+	return nil
+}
+
+// Warmup opens up to connections connections to the node with concurrent HEAD /health requests,
+// completing the TLS handshakes before they are needed. Over HTTP/2 a single connection carries
+// every request, so one is enough.
+func (session *UL_TransactionSession) Warmup(ctx context.Context, connections int) error {
+	connections = max(connections, 1)
+	errs := make(chan error, connections)
+	for range connections {
+		go func() {
+			errs <- session.warmupOnce(ctx)
+		}()
+	}
+	var err error
+	for range connections {
+		err = errors.Join(err, <-errs)
+	}
+	return err
+}
+
+func (session *UL_TransactionSession) warmupOnce(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, session.nodeEndpoint+"/health", nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range session.headers {
+		req.Header[key] = values
+	}
+	resp, err := session.client().Do(req)
+	if err != nil {
+		return err
+	}
+	// The connection only goes back to the pool once the body is drained
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &NodeError{StatusCode: resp.StatusCode, Method: http.MethodHead, Path: "/health"}
+	}
+	return nil
+}
+
+// client returns the client of the session, sessions built without a constructor share the default one
+func (session *UL_TransactionSession) client() *http.Client {
+	if session.httpClient != nil {
+		return session.httpClient
+	}
+	return defaultHTTPClient
+}
+
+// LatencySample breaks down the time of one request to the node. Connect, TLSHandshake and
+// FirstByte are zero when the request did not get that far, Connect is zero on reused connections.
+type LatencySample struct {
+	Time         time.Time
+	Method       string
+	Path         string
+	StatusCode   int
+	Protocol     string // e.g. HTTP/1.1 or HTTP/2.0
+	Reused       bool   // The request went over an already open connection
+	Connect      time.Duration
+	TLSHandshake time.Duration
+	FirstByte    time.Duration // From the start of the request to the first byte of the response
+	Total        time.Duration
+	Err          error
+}
+
+// SetLatencyHook calls hook after every request the session sends, retries included, with the
+// timings of the request. The hook runs synchronously on the calling goroutine, nil removes it.
+func (session *UL_TransactionSession) SetLatencyHook(hook func(LatencySample)) {
+	session.latencyHook = hook
+}
+
+// requestTrace collects the timings of one request. The transport may dial on another goroutine
+// that outlives the request, so the fields are guarded.
+type requestTrace struct {
+	mu             sync.Mutex
+	sample         LatencySample
+	connectStarted time.Time
+	tlsStarted     time.Time
+}
+
+func newRequestTrace(method string, path string) *requestTrace {
+	return &requestTrace{sample: LatencySample{Time: time.Now(), Method: method, Path: path}}
+}
+
+func (trace *requestTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			trace.mu.Lock()
+			defer trace.mu.Unlock()
+			trace.connectStarted = time.Now()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			trace.mu.Lock()
+			defer trace.mu.Unlock()
+			trace.sample.Reused = info.Reused
+			if !info.Reused {
+				trace.sample.Connect = time.Since(trace.connectStarted)
+			}
+		},
+		TLSHandshakeStart: func() {
+			trace.mu.Lock()
+			defer trace.mu.Unlock()
+			trace.tlsStarted = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			trace.mu.Lock()
+			defer trace.mu.Unlock()
+			trace.sample.TLSHandshake = time.Since(trace.tlsStarted)
+		},
+		GotFirstResponseByte: func() {
+			trace.mu.Lock()
+			defer trace.mu.Unlock()
+			trace.sample.FirstByte = time.Since(trace.sample.Time)
+		},
+	}
+}
+
+func (trace *requestTrace) finish(resp *http.Response, err error) LatencySample {
+	trace.mu.Lock()
+	defer trace.mu.Unlock()
+	sample := trace.sample
+	sample.Total = time.Since(sample.Time)
+	if resp != nil {
+		sample.StatusCode = resp.StatusCode
+		sample.Protocol = resp.Proto
+	}
+	sample.Err = err
+	return sample
+}
@@ -0,0 +1,167 @@
+package transaction_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// transportNode answers the requests of a session creation and counts the connections opened to it
+func transportNode(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"nodeId":"node"}`))
+	})
+	mux.HandleFunc("GET /blockchains", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`["chain"]`))
+	})
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(mux)
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	t.Cleanup(server.Close)
+	return server, &connections
+}
+
+// latencySamples collects the samples of the latency hook
+type latencySamples struct {
+	mu      sync.Mutex
+	samples []transaction.LatencySample
+}
+
+func (l *latencySamples) add(sample transaction.LatencySample) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.samples = append(l.samples, sample)
+}
+
+func (l *latencySamples) take() []transaction.LatencySample {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	samples := l.samples
+	l.samples = nil
+	return samples
+}
+
+func TestTransport(t *testing.T) {
+	ctx := context.Background()
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+
+	t.Run("http2 over tls", func(t *testing.T) {
+		server, connections := transportNode(t)
+		server.EnableHTTP2 = true
+		server.StartTLS()
+
+		config := transaction.DEFAULT_TRANSPORT_CONFIG
+		config.TLS = server.Client().Transport.(*http.Transport).TLSClientConfig
+		session, err := transaction.NewUL_TransactionSessionWithTransport(ctx, server.URL, w, config)
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSessionWithTransport() error = %v", err)
+		}
+		opened := connections.Load()
+
+		samples := &latencySamples{}
+		session.SetLatencyHook(samples.add)
+		if err := session.Do(ctx, http.MethodGet, "/health", nil, nil); err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		got := samples.take()
+		if len(got) != 1 {
+			t.Fatalf("latency hook called %d times, want 1", len(got))
+		}
+		sample := got[0]
+		if sample.Protocol != "HTTP/2.0" || sample.StatusCode != http.StatusOK || sample.Path != "/health" || sample.Err != nil {
+			t.Fatalf("sample = %+v", sample)
+		}
+		// The handshake happened while the session was created
+		if !sample.Reused || sample.Connect != 0 || sample.TLSHandshake != 0 || sample.FirstByte <= 0 || sample.Total < sample.FirstByte {
+			t.Fatalf("sample timings = %+v", sample)
+		}
+		if connections.Load() != opened {
+			t.Fatalf("the request opened a new connection")
+		}
+	})
+
+	t.Run("unencrypted http2", func(t *testing.T) {
+		server, _ := transportNode(t)
+		server.Config.Protocols = new(http.Protocols)
+		server.Config.Protocols.SetHTTP1(true)
+		server.Config.Protocols.SetUnencryptedHTTP2(true)
+		server.Start()
+
+		session, err := transaction.NewUL_TransactionSessionWithTransport(ctx, server.URL, w, transaction.TransportConfig{UnencryptedHTTP2: true})
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSessionWithTransport() error = %v", err)
+		}
+		samples := &latencySamples{}
+		session.SetLatencyHook(samples.add)
+		session.Do(ctx, http.MethodGet, "/health", nil, nil)
+		if got := samples.take(); len(got) != 1 || got[0].Protocol != "HTTP/2.0" {
+			t.Fatalf("samples = %+v", got)
+		}
+	})
+
+	t.Run("warmup", func(t *testing.T) {
+		server, connections := transportNode(t)
+		server.Start()
+		created, err := transaction.NewUL_TransactionSession(server.URL, w)
+		if err != nil {
+			t.Fatalf("NewUL_TransactionSession() error = %v", err)
+		}
+		session := &created
+
+		config := transaction.DEFAULT_TRANSPORT_CONFIG
+		config.HTTP2 = false
+		config.WarmupConnections = 3
+		before := connections.Load()
+		if err := session.SetTransport(ctx, config); err != nil {
+			t.Fatalf("SetTransport() error = %v", err)
+		}
+		warmed := connections.Load()
+		if warmed <= before || warmed > before+3 {
+			t.Fatalf("SetTransport() opened %d connections, want 1 to 3", warmed-before)
+		}
+
+		samples := &latencySamples{}
+		session.SetLatencyHook(samples.add)
+		for range 3 {
+			session.Do(ctx, http.MethodGet, "/blockchains", nil, nil)
+		}
+		for _, sample := range samples.take() {
+			if !sample.Reused || sample.Protocol != "HTTP/1.1" {
+				t.Fatalf("sample after the warmup = %+v", sample)
+			}
+		}
+		if connections.Load() != warmed {
+			t.Fatalf("requests opened %d connections after the warmup", connections.Load()-warmed)
+		}
+
+		// Failed requests are reported with their status
+		session.SetRetryPolicy(transaction.RetryPolicy{MaxAttempts: 1})
+		if err := session.Do(ctx, http.MethodGet, "/missing", nil, nil); err == nil {
+			t.Fatalf("Do() of a missing path succeeded")
+		}
+		if got := samples.take(); len(got) != 1 || got[0].StatusCode != http.StatusNotFound || got[0].Err == nil {
+			t.Fatalf("samples of a failed request = %+v", got)
+		}
+
+		server.Close()
+		if err := session.Warmup(ctx, 2); err == nil {
+			t.Fatalf("Warmup() of a closed node succeeded")
+		}
+	})
+}
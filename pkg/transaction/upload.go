@@ -0,0 +1,313 @@
+package transaction
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	CONTRACT_ENCODING_IDENTITY = "identity"
+	CONTRACT_ENCODING_GZIP     = "gzip"
+	DEFAULT_UPLOAD_CHUNK_SIZE  = 256 << 10
+	// NODE_FEATURE_CONTRACT_INITIAL_STATE is advertised by nodes that apply ContractSource.InitialState
+	NODE_FEATURE_CONTRACT_INITIAL_STATE = "contract-initial-state"
+)
+
+// ErrInitialStateUnsupported is returned when the node would ignore the initial state of a deploy
+type ErrInitialStateUnsupported struct {
+	Msg string
+}
+
+func (e *ErrInitialStateUnsupported) Error() string {
+	return fmt.Sprintf("the node does not support contract initial state, %s", e.Msg)
+}
+
+func (e *ErrInitialStateUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ErrUploadUnsupported is returned when the node has no upload endpoint. Such nodes predate the
+// ContractSource envelope and only deploy raw sources.
+type ErrUploadUnsupported struct {
+	Msg string
+}
+
+func (e *ErrUploadUnsupported) Error() string {
+	if e.Msg == "" {
+		return "the node does not support contract uploads"
+	}
+	return fmt.Sprintf("the node does not support contract uploads, %s", e.Msg)
+}
+
+func (e *ErrUploadUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// ContractSource is the deploy payload envelope for large contracts. The source is either inlined
+// as base64 in Data or was uploaded to the node beforehand and is referenced by the blob hash in Upload.
+// Size and Sha256 describe the decoded source so the signed payload pins the exact code.
+type ContractSource struct {
+	Encoding string `json:"encoding"`
+	Size     int    `json:"size"`
+	Sha256   string `json:"sha256"`
+	Data     string `json:"data,omitempty"`
+	Upload   string `json:"upload,omitempty"`
+	// InitialState is the base64 of the serializer encoded storage map written before the contract
+	// accepts its first call
+	InitialState string `json:"initialState,omitempty"`
+}
+
+type UploadStage string
+
+const (
+	UPLOAD_STAGE_COMPRESS UploadStage = "compress"
+	UPLOAD_STAGE_UPLOAD   UploadStage = "upload"
+	UPLOAD_STAGE_SUBMIT   UploadStage = "submit"
+)
+
+// UploadProgress is reported after every step, Done and Total are in bytes of the current stage
+type UploadProgress struct {
+	Stage UploadStage
+	Done  int64
+	Total int64
+}
+
+type ContractUploadOptions struct {
+	Compress  bool
+	ChunkSize int
+	// Inline skips the upload endpoint and embeds the source in the transaction payload
+	Inline   bool
+	Progress func(UploadProgress)
+	// InitialState seeds the contract storage at deployment, values must be types the serializer
+	// encodes (nil, bool, int32, int64, float32, float64, string, []byte, slices and nested maps)
+	InitialState map[string]interface{}
+}
+
+// UploadStatus is the node's view of a chunked upload, uploads are keyed by the SHA256 of the blob
+// so an interrupted upload resumes from Received when it is started again
+type UploadStatus struct {
+	Sha256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Encoding string `json:"encoding"`
+	Received int64  `json:"received"`
+}
+
+func (opts ContractUploadOptions) report(stage UploadStage, done int64, total int64) {
+	if opts.Progress != nil {
+		opts.Progress(UploadProgress{Stage: stage, Done: done, Total: total})
+	}
+}
+
+// NewContractSource builds an inline envelope for source, optionally gzip compressed
+func NewContractSource(source []byte, compress bool) (ContractSource, []byte, error) {
+	digest := sha256.Sum256(source)
+	contract := ContractSource{
+		Encoding: CONTRACT_ENCODING_IDENTITY,
+		Size:     len(source),
+		Sha256:   hex.EncodeToString(digest[:]),
+	}
+
+	blob := source
+	if compress {
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(source); err != nil {
+			return ContractSource{}, nil, fmt.Errorf("unable to compress contract source: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return ContractSource{}, nil, fmt.Errorf("unable to compress contract source: %w", err)
+		}
+		contract.Encoding = CONTRACT_ENCODING_GZIP
+		blob = buf.Bytes()
+	}
+	contract.Data = base64.StdEncoding.EncodeToString(blob)
+	return contract, blob, nil
+}
+
+// Decode returns the contract source, checking its size and hash. Uploaded sources need the blob
+// fetched from the node, inline sources pass nil.
+func (contract ContractSource) Decode(blob []byte) ([]byte, error) {
+	if blob == nil {
+		if contract.Data == "" {
+			return nil, fmt.Errorf("contract source was uploaded as %s, the blob is required", contract.Upload)
+		}
+		var err error
+		blob, err = base64.StdEncoding.DecodeString(contract.Data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid contract data: %w", err)
+		}
+	}
+
+	source := blob
+	switch contract.Encoding {
+	case CONTRACT_ENCODING_IDENTITY, "":
+	case CONTRACT_ENCODING_GZIP:
+		reader, err := gzip.NewReader(bytes.NewReader(blob))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+		// Never inflate past the declared size
+		source, err = io.ReadAll(io.LimitReader(reader, int64(contract.Size)+1))
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip data: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown contract encoding: %s", contract.Encoding)
+	}
+
+	if len(source) != contract.Size {
+		return nil, fmt.Errorf("contract source is %d bytes, expected %d", len(source), contract.Size)
+	}
+	digest := sha256.Sum256(source)
+	if hex.EncodeToString(digest[:]) != contract.Sha256 {
+		return nil, fmt.Errorf("contract source hash mismatch")
+	}
+	return source, nil
+}
+
+// EncodeInitialState encodes a storage map for ContractSource.InitialState
+func EncodeInitialState(state map[string]interface{}) (string, error) {
+	// Encode each value first so the error names the offending key
+	for key, value := range state {
+		if _, err := Encode(value); err != nil {
+			return "", fmt.Errorf("invalid initial state for key %q: %w", key, err)
+		}
+	}
+	encoded, err := Encode(state)
+	if err != nil {
+		return "", fmt.Errorf("invalid initial state: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// DecodeInitialState returns the storage map of the envelope, nil when it has none
+func (contract ContractSource) DecodeInitialState() (map[string]interface{}, error) {
+	if contract.InitialState == "" {
+		return nil, nil
+	}
+	encoded, err := base64.StdEncoding.DecodeString(contract.InitialState)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initial state: %w", err)
+	}
+	decoded, err := Decode(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid initial state: %w", err)
+	}
+	state, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("initial state is a %T, expected a map", decoded)
+	}
+	return state, nil
+}
+
+// UploadContractSource prepares the deploy envelope for source, sending the blob to the upload
+// endpoint in resumable chunks unless opts.Inline embeds it in the envelope. Nodes without the endpoint
+// fail with ErrUploadUnsupported, they do not read envelopes either.
+func (session *UL_TransactionSession) UploadContractSource(ctx context.Context, blockchainId string, source []byte, opts ContractUploadOptions) (ContractSource, error) {
+	initialState := ""
+	if len(opts.InitialState) > 0 {
+		var err error
+		if initialState, err = EncodeInitialState(opts.InitialState); err != nil {
+			return ContractSource{}, err
+		}
+	}
+
+	opts.report(UPLOAD_STAGE_COMPRESS, 0, int64(len(source)))
+	contract, blob, err := NewContractSource(source, opts.Compress)
+	if err != nil {
+		return ContractSource{}, err
+	}
+	contract.InitialState = initialState
+	opts.report(UPLOAD_STAGE_COMPRESS, int64(len(source)), int64(len(source)))
+	if opts.Inline {
+		return contract, nil
+	}
+
+	blobDigest := sha256.Sum256(blob)
+	status := UploadStatus{}
+	request := UploadStatus{Sha256: hex.EncodeToString(blobDigest[:]), Size: int64(len(blob)), Encoding: contract.Encoding}
+	err = session.Do(ctx, "POST", fmt.Sprintf("/blockchains/%s/uploads", blockchainId), request, &status)
+	var nodeErr *NodeError
+	if errors.As(err, &nodeErr) && (nodeErr.StatusCode == http.StatusNotFound || nodeErr.StatusCode == http.StatusMethodNotAllowed) {
+		return ContractSource{}, &ErrUploadUnsupported{Msg: "deploy the raw source instead"}
+	}
+	if err != nil {
+		return ContractSource{}, fmt.Errorf("unable to start upload: %w", err)
+	}
+
+	chunkSize := opts.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DEFAULT_UPLOAD_CHUNK_SIZE
+	}
+	for offset := status.Received; offset < int64(len(blob)); offset = status.Received {
+		opts.report(UPLOAD_STAGE_UPLOAD, offset, int64(len(blob)))
+		end := min(offset+int64(chunkSize), int64(len(blob)))
+		path := fmt.Sprintf("/blockchains/%s/uploads/%s?offset=%d", blockchainId, request.Sha256, offset)
+		if err := session.Do(ctx, "PUT", path, blob[offset:end], &status); err != nil {
+			return ContractSource{}, fmt.Errorf("upload interrupted at byte %d: %w", offset, err)
+		}
+		if status.Received <= offset {
+			return ContractSource{}, fmt.Errorf("upload made no progress at byte %d", offset)
+		}
+	}
+	opts.report(UPLOAD_STAGE_UPLOAD, int64(len(blob)), int64(len(blob)))
+
+	contract.Data = ""
+	contract.Upload = request.Sha256
+	return contract, nil
+}
+
+// DeployContract deploys source through a ContractSource envelope, see UploadContractSource. An
+// InitialState is only sent to nodes advertising NODE_FEATURE_CONTRACT_INITIAL_STATE, other nodes would
+// deploy the contract with empty storage. Nodes without the upload endpoint get the raw source as the
+// payload, Compress and InitialState fail with ErrUploadUnsupported there as they need the envelope.
+func (session *UL_TransactionSession) DeployContract(ctx context.Context, blockchainId string, source []byte, opts ContractUploadOptions) (ULTransaction, error) {
+	if len(opts.InitialState) > 0 {
+		supported, err := session.hasFeature(ctx, NODE_FEATURE_CONTRACT_INITIAL_STATE)
+		if err != nil {
+			return ULTransaction{}, err
+		}
+		if !supported {
+			return ULTransaction{}, &ErrInitialStateUnsupported{Msg: "deploy without it and initialize the contract with a call"}
+		}
+	}
+	var payload []byte
+	contract, err := session.UploadContractSource(ctx, blockchainId, source, opts)
+	var unsupported *ErrUploadUnsupported
+	switch {
+	case errors.As(err, &unsupported):
+		if opts.Compress || len(opts.InitialState) > 0 {
+			return ULTransaction{}, &ErrUploadUnsupported{Msg: "compressed sources and initial state need the envelope"}
+		}
+		payload = source
+	case err != nil:
+		return ULTransaction{}, err
+	default:
+		if payload, err = json.Marshal(contract); err != nil {
+			return ULTransaction{}, err
+		}
+	}
+
+	opts.report(UPLOAD_STAGE_SUBMIT, 0, int64(len(payload)))
+	tx, err := session.generateTransaction(ctx, ULTransactionInput{
+		BlockchainId: blockchainId,
+		Payload:      string(payload),
+		PayloadType:  DEPLOY_SMART_CONTRACT.String(),
+	}, "")
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	opts.report(UPLOAD_STAGE_SUBMIT, int64(len(payload)), int64(len(payload)))
+	return tx, nil
+}
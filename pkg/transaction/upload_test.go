@@ -0,0 +1,166 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+func contractSource() []byte {
+	var sb strings.Builder
+	sb.WriteString("(module\n")
+	for i := 0; i < 2000; i++ {
+		fmt.Fprintf(&sb, "  (func $f%d (result i32) i32.const %d)\n", i, i*7919%104729)
+	}
+	sb.WriteString(")\n")
+	return []byte(sb.String())
+}
+
+func TestDeployContractRawFallback(t *testing.T) {
+	node, session := newMockSession(t)
+	node.DisableUploads()
+	ctx := context.Background()
+	source := contractSource()
+
+	// Nodes without the upload endpoint do not read envelopes, so nothing needing one is sent
+	var unsupported *transaction.ErrUploadUnsupported
+	if _, err := session.UploadContractSource(ctx, testBlockchainId, source, transaction.ContractUploadOptions{}); !errors.As(err, &unsupported) {
+		t.Fatalf("UploadContractSource() error = %v, want ErrUploadUnsupported", err)
+	}
+	for _, opts := range []transaction.ContractUploadOptions{{Compress: true}, {InitialState: map[string]interface{}{"paused": false}}} {
+		node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE)
+		if _, err := session.DeployContract(ctx, testBlockchainId, source, opts); !errors.Is(err, utils.ErrUnsupported) {
+			t.Fatalf("DeployContract(%+v) error = %v, want ErrUploadUnsupported", opts, err)
+		}
+	}
+	if len(node.Transactions()) != 0 {
+		t.Fatal("a deploy needing the envelope reached the node")
+	}
+
+	tx, err := session.DeployContract(ctx, testBlockchainId, source, transaction.ContractUploadOptions{})
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	if tx.Payload != string(source) {
+		t.Fatalf("expected the raw source as the payload, got %d bytes", len(tx.Payload))
+	}
+
+	// Inline envelopes are still built on request
+	tx, err = session.DeployContract(ctx, testBlockchainId, source, transaction.ContractUploadOptions{Compress: true, Inline: true})
+	if err != nil {
+		t.Fatalf("DeployContract() inline error = %v", err)
+	}
+	contract := transaction.ContractSource{}
+	if err := json.Unmarshal([]byte(tx.Payload), &contract); err != nil {
+		t.Fatalf("payload is not a contract envelope: %v", err)
+	}
+	if contract.Encoding != transaction.CONTRACT_ENCODING_GZIP || contract.Upload != "" || len(contract.Data) >= len(source) {
+		t.Fatalf("expected a compressed inline envelope, got encoding %s, %d bytes", contract.Encoding, len(contract.Data))
+	}
+	decoded, err := contract.Decode(nil)
+	if err != nil || string(decoded) != string(source) {
+		t.Fatalf("Decode() = %d bytes, %v", len(decoded), err)
+	}
+
+	contract.Size--
+	if _, err := contract.Decode(nil); err == nil {
+		t.Fatal("expected a size mismatch to be rejected")
+	}
+}
+
+func TestDeployContractResumesUpload(t *testing.T) {
+	node, session := newMockSession(t)
+	source := contractSource()
+
+	chunks := 0
+	opts := transaction.ContractUploadOptions{
+		ChunkSize: 4096,
+		Progress: func(progress transaction.UploadProgress) {
+			if progress.Stage == transaction.UPLOAD_STAGE_UPLOAD && progress.Done < progress.Total {
+				chunks++
+				if chunks == 3 {
+					node.FailUploadChunks(1)
+				}
+			}
+		},
+	}
+	if _, err := session.DeployContract(context.Background(), testBlockchainId, source, opts); err == nil {
+		t.Fatal("expected the interrupted upload to fail")
+	}
+
+	var resumedAt int64 = -1
+	opts.Progress = func(progress transaction.UploadProgress) {
+		if progress.Stage == transaction.UPLOAD_STAGE_UPLOAD && resumedAt < 0 {
+			resumedAt = progress.Done
+		}
+	}
+	tx, err := session.DeployContract(context.Background(), testBlockchainId, source, opts)
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	if resumedAt != 2*4096 {
+		t.Errorf("expected the upload to resume at byte %d, got %d", 2*4096, resumedAt)
+	}
+
+	contract := transaction.ContractSource{}
+	if err := json.Unmarshal([]byte(tx.Payload), &contract); err != nil {
+		t.Fatalf("payload is not a contract envelope: %v", err)
+	}
+	blob, ok := node.Upload(contract.Upload)
+	if !ok || contract.Data != "" {
+		t.Fatalf("expected the envelope to reference a completed upload, got %+v", contract)
+	}
+	decoded, err := contract.Decode(blob)
+	if err != nil || string(decoded) != string(source) {
+		t.Fatalf("Decode() = %d bytes, %v", len(decoded), err)
+	}
+}
+
+func TestDeployContractInitialState(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx := context.Background()
+	state := map[string]interface{}{
+		"owner":  session.GetWallet().Address,
+		"supply": int64(1_000_000),
+		"paused": false,
+		"admins": []interface{}{"alice", "bob"},
+	}
+	opts := transaction.ContractUploadOptions{Inline: true, InitialState: state}
+
+	// Nodes that do not advertise the feature would silently drop the state
+	var unsupported *transaction.ErrInitialStateUnsupported
+	if _, err := session.DeployContract(ctx, testBlockchainId, contractSource(), opts); !errors.As(err, &unsupported) {
+		t.Fatalf("DeployContract() error = %v, want ErrInitialStateUnsupported", err)
+	}
+
+	node.SetFeatures(transaction.NODE_FEATURE_CONTRACT_INITIAL_STATE)
+	tx, err := session.DeployContract(ctx, testBlockchainId, contractSource(), opts)
+	if err != nil {
+		t.Fatalf("DeployContract() error = %v", err)
+	}
+	applied, ok := node.ContractState(tx.TransactionId)
+	if !ok || !reflect.DeepEqual(applied, state) {
+		t.Fatalf("ContractState() = %v, want %v", applied, state)
+	}
+
+	contract := transaction.ContractSource{}
+	if err := json.Unmarshal([]byte(tx.Payload), &contract); err != nil {
+		t.Fatalf("payload is not a contract envelope: %v", err)
+	}
+	if decoded, err := contract.DecodeInitialState(); err != nil || !reflect.DeepEqual(decoded, state) {
+		t.Fatalf("DecodeInitialState() = %v, %v", decoded, err)
+	}
+
+	// Values the serializer cannot encode are reported with their key
+	opts.InitialState = map[string]interface{}{"count": 1}
+	if _, err := session.DeployContract(ctx, testBlockchainId, contractSource(), opts); err == nil || !strings.Contains(err.Error(), `"count"`) {
+		t.Fatalf("DeployContract() error = %v, want the invalid key", err)
+	}
+}
@@ -0,0 +1,127 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// DEFAULT_OUTCOME_POLL_INTERVAL is how often the node is asked about a transaction still in flight
+const DEFAULT_OUTCOME_POLL_INTERVAL = 250 * time.Millisecond
+
+// ErrTransactionRejected is the outcome of a transaction the node refused, Output names the reason
+type ErrTransactionRejected struct {
+	Transaction ULTransaction
+}
+
+func (e *ErrTransactionRejected) Error() string {
+	return fmt.Sprintf("transaction %s rejected: %s", e.Transaction.TransactionId, e.Transaction.Output)
+}
+
+func (e *ErrTransactionRejected) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Unwrap returns the typed error of the rejection reason, e.g. ErrDuplicateTransaction
+func (e *ErrTransactionRejected) Unwrap() error {
+	return OutputError(e.Transaction)
+}
+
+// ErrTransactionDropped is returned when the node forgot a transaction before deciding on it, e.g.
+// it was evicted from the mempool. It may be submitted again.
+type ErrTransactionDropped struct {
+	TransactionId string
+	Missing       time.Duration
+}
+
+func (e *ErrTransactionDropped) Error() string {
+	return fmt.Sprintf("transaction %s dropped, the node has not known it for %s", e.TransactionId, e.Missing)
+}
+
+func (e *ErrTransactionDropped) Is(target error) bool {
+	return target == utils.ErrNotFound
+}
+
+type WaitOptions struct {
+	// PollInterval defaults to DEFAULT_OUTCOME_POLL_INTERVAL
+	PollInterval time.Duration
+	// DropAfter is how long the node may not know the transaction before it is reported dropped,
+	// defaults to DEFAULT_DROP_AFTER
+	DropAfter time.Duration
+}
+
+// SubmitAndWait generates and submits input, then returns once the transaction reached a terminal
+// status: sealed in a block, or rejected with an ErrTransactionRejected. A transaction the node
+// loses ends with an ErrTransactionDropped. When ctx ends first its error is returned along with the
+// transaction as last seen, which may still be decided later.
+func (session *UL_TransactionSession) SubmitAndWait(ctx context.Context, input ULTransactionInput) (ULTransaction, error) {
+	tx, err := session.generateTransaction(ctx, input, "")
+	if err != nil {
+		return ULTransaction{}, err
+	}
+	blockchainId := tx.BlockchainId
+	if blockchainId == "" {
+		blockchainId = input.BlockchainId
+	}
+	return session.waitOutcome(ctx, blockchainId, tx, WaitOptions{})
+}
+
+// WaitForTransaction waits for a submitted transaction to reach a terminal status, see SubmitAndWait
+func (session *UL_TransactionSession) WaitForTransaction(ctx context.Context, blockchainId string, transactionId string, opts WaitOptions) (ULTransaction, error) {
+	return session.waitOutcome(ctx, blockchainId, ULTransaction{ULTransactionOutput: ULTransactionOutput{TransactionId: transactionId}}, opts)
+}
+
+func (session *UL_TransactionSession) waitOutcome(ctx context.Context, blockchainId string, tx ULTransaction, opts WaitOptions) (ULTransaction, error) {
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DEFAULT_OUTCOME_POLL_INTERVAL
+	}
+	if opts.DropAfter <= 0 {
+		opts.DropAfter = DEFAULT_DROP_AFTER
+	}
+
+	lastSeen := time.Now()
+	ticker := time.NewTicker(opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if done, err := transactionOutcome(tx); done {
+			return tx, err
+		}
+		select {
+		case <-ctx.Done():
+			return tx, ctx.Err()
+		case <-ticker.C:
+		}
+
+		current, err := session.GetTransaction(ctx, blockchainId, tx.TransactionId)
+		switch {
+		case err == nil:
+			tx = current
+			lastSeen = time.Now()
+		case errors.Is(err, utils.ErrNotFound):
+			if missing := time.Since(lastSeen); missing >= opts.DropAfter {
+				return tx, &ErrTransactionDropped{TransactionId: tx.TransactionId, Missing: missing.Round(time.Millisecond)}
+			}
+		case ctx.Err() != nil:
+			return tx, ctx.Err()
+		case !errors.Is(err, utils.ErrUnavailable):
+			return tx, err
+		}
+		// An unavailable node is asked again at the next tick
+	}
+}
+
+// transactionOutcome reports whether the node decided on tx, err is set for rejections
+func transactionOutcome(tx ULTransaction) (bool, error) {
+	rejected := tx.Output != "" && tx.Output != TX_SUCCESS.String() && tx.Output != TO_BE_PROCESSED.String()
+	switch {
+	case tx.Status == TX_REJECTED.String() || rejected:
+		return true, &ErrTransactionRejected{Transaction: tx}
+	case tx.Status == TX_ACCEPTED.String() && tx.BlockHeight > 0:
+		return true, nil
+	default:
+		return false, nil
+	}
+}
@@ -0,0 +1,126 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestSubmitAndWait(t *testing.T) {
+	node, session := newMockSession(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	data := func(payload string) transaction.ULTransactionInput {
+		return transaction.ULTransactionInput{
+			BlockchainId: testBlockchainId,
+			To:           session.GetWallet().Address,
+			Payload:      payload,
+			PayloadType:  transaction.TX_DATA.String(),
+		}
+	}
+
+	tx, err := session.SubmitAndWait(ctx, data("sealed at once"))
+	if err != nil || tx.BlockHeight == 0 || tx.Output != transaction.TX_SUCCESS.String() {
+		t.Fatalf("SubmitAndWait() = %+v, %v", tx.ULTransactionOutput, err)
+	}
+
+	// The outcome is polled until the node seals the transaction
+	node.HoldTransactions(true)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		node.ReleasePending(testBlockchainId)
+	}()
+	tx, err = session.SubmitAndWait(ctx, data("sealed later"))
+	if err != nil || tx.BlockHeight == 0 || tx.Status != transaction.TX_ACCEPTED.String() {
+		t.Fatalf("SubmitAndWait() of a held transaction = %+v, %v", tx.ULTransactionOutput, err)
+	}
+	node.HoldTransactions(false)
+
+	revoke, _ := json.Marshal(transaction.RevokeDelegationPayload{DelegationId: "unknown"})
+	_, err = session.SubmitAndWait(ctx, transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      string(revoke),
+		PayloadType:  transaction.REVOKE_DELEGATION.String(),
+	})
+	var rejected *transaction.ErrTransactionRejected
+	if !errors.As(err, &rejected) || !errors.Is(err, utils.ErrRejected) || rejected.Transaction.Output != transaction.TX_TRANSACTION_ERROR.String() {
+		t.Fatalf("SubmitAndWait() of a failing transaction error = %v", err)
+	}
+
+	node.HoldTransactions(true)
+	short, cancelShort := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelShort()
+	tx, err = session.SubmitAndWait(short, data("never sealed"))
+	if !errors.Is(err, context.DeadlineExceeded) || tx.TransactionId == "" || tx.Status != transaction.TX_SUBMITTED.String() {
+		t.Fatalf("SubmitAndWait() past the deadline = %+v, %v", tx.ULTransactionOutput, err)
+	}
+}
+
+func TestSubmitAndWaitCancelsSubmission(t *testing.T) {
+	node, _ := newMockSession(t)
+	target, _ := url.Parse(node.URL())
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	// The node never answers a submission, only the context can end it
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			<-release
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer slow.Close()
+	defer close(release)
+	w, _, err := wallet.GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(slow.URL, w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err = session.SubmitAndWait(ctx, transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           session.GetWallet().Address,
+		Payload:      "stuck",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SubmitAndWait() on a stuck node error = %v", err)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("SubmitAndWait() returned %s after the context ended", elapsed)
+	}
+}
+
+func TestWaitForDroppedTransaction(t *testing.T) {
+	node, session := newMockSession(t)
+	node.HoldTransactions(true)
+	tx := submitData(t, session, "evicted")
+	node.DropPending(testBlockchainId)
+
+	_, err := session.WaitForTransaction(context.Background(), testBlockchainId, tx.TransactionId, transaction.WaitOptions{
+		PollInterval: 10 * time.Millisecond,
+		DropAfter:    50 * time.Millisecond,
+	})
+	var dropped *transaction.ErrTransactionDropped
+	if !errors.As(err, &dropped) || !errors.Is(err, utils.ErrNotFound) || dropped.TransactionId != tx.TransactionId {
+		t.Fatalf("WaitForTransaction() of a dropped transaction error = %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// WalletState is the authorization of a wallet as its chain records it, which may differ from the
+// wallet file after ALTER_WALLET transactions. AuthGroups maps payload types to the permissions of
+// the wallet, it is never nil.
+type WalletState struct {
+	Address    string                              `json:"address"`
+	Enabled    bool                                `json:"enabled"`
+	Parent     string                              `json:"parent"`
+	AuthGroups map[string]wallet.UL_AuthPermission `json:"authGroups"`
+}
+
+// Groups returns the names of the auth groups of the wallet in order
+func (s WalletState) Groups() []string {
+	groups := make([]string, 0, len(s.AuthGroups))
+	for group := range s.AuthGroups {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+	return groups
+}
+
+// GetWalletState reads the registration of a wallet on a chain. Unregistered wallets return an
+// error matching utils.ErrNotFound.
+func (session *UL_TransactionSession) GetWalletState(ctx context.Context, blockchainId string, address string) (WalletState, error) {
+	if !isAddress(address) {
+		return WalletState{}, &utils.ErrMalformed{What: "wallet address", Msg: "it must be a hex encoded 32 byte address"}
+	}
+	info := ULWalletInfo{}
+	if err := session.getJson(ctx, fmt.Sprintf("/blockchains/%s/wallets/%s", blockchainId, address), &info); err != nil {
+		return WalletState{}, err
+	}
+	state := WalletState{Address: info.Address, Enabled: info.Enabled, Parent: info.Parent, AuthGroups: info.AuthGroups}
+	if state.AuthGroups == nil {
+		state.AuthGroups = map[string]wallet.UL_AuthPermission{}
+	}
+	return state, nil
+}
+
+// DiscoverWallets finds the wallets of hd registered on a chain with BIP-44 account discovery, see
+// wallet.HDWallet.Discover. The wallets found carry their parent, status and auth groups on the chain.
+func (session *UL_TransactionSession) DiscoverWallets(ctx context.Context, blockchainId string, hd *wallet.HDWallet, gapLimit int) ([]wallet.DerivedWallet, error) {
+	states := map[string]WalletState{}
+	found, err := hd.Discover(gapLimit, func(w wallet.UL_Wallet) (bool, error) {
+		state, err := session.GetWalletState(ctx, blockchainId, w.Address)
+		if errors.Is(err, utils.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		states[w.Address] = state
+		return true, nil
+	})
+	for i, derived := range found {
+		state := states[derived.Wallet.Address]
+		found[i].Wallet.Parent, found[i].Wallet.Enabled, found[i].Wallet.AuthGroups = state.Parent, state.Enabled, state.AuthGroups
+	}
+	return found, err
+}
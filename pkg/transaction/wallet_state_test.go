@@ -0,0 +1,98 @@
+package transaction_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+func TestGetWalletState(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	admin := session.GetWallet().Address
+	registerSigner(t, session)
+	child := registerWallet(t, session, admin)
+
+	state, err := session.GetWalletState(ctx, testBlockchainId, child)
+	if err != nil {
+		t.Fatalf("GetWalletState() error = %v", err)
+	}
+	if state.Address != child || !state.Enabled || state.Parent != admin || state.AuthGroups == nil || len(state.AuthGroups) != 0 {
+		t.Fatalf("GetWalletState() = %+v, want an enabled child of %s without auth groups", state, admin)
+	}
+
+	groups := map[string]wallet.UL_AuthPermission{
+		transaction.TX_DATA.String():         {Create: true, Read: true},
+		transaction.TX_ALTER_WALLET.String(): {Read: true},
+	}
+	payload, _ := json.Marshal(transaction.AlterWalletPayload{Target: child, Enabled: false, AuthGroups: groups})
+	if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: testBlockchainId,
+		To:           child,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_ALTER_WALLET.String(),
+	}); err != nil {
+		t.Fatalf("ALTER_WALLET error = %v", err)
+	}
+	state, err = session.GetWalletState(ctx, testBlockchainId, child)
+	if err != nil {
+		t.Fatalf("GetWalletState() error = %v", err)
+	}
+	if state.Enabled || state.AuthGroups[transaction.TX_DATA.String()] != groups[transaction.TX_DATA.String()] {
+		t.Fatalf("GetWalletState() after ALTER_WALLET = %+v, want disabled with %v", state, groups)
+	}
+	if want := []string{transaction.TX_ALTER_WALLET.String(), transaction.TX_DATA.String()}; !slices.Equal(state.Groups(), want) {
+		t.Fatalf("Groups() = %v, want %v", state.Groups(), want)
+	}
+
+	if _, err := session.GetWalletState(ctx, testBlockchainId, fmt.Sprintf("%064x", 7)); !errors.Is(err, utils.ErrNotFound) {
+		t.Fatalf("GetWalletState() of an unregistered wallet error = %v", err)
+	}
+	if _, err := session.GetWalletState(ctx, testBlockchainId, "admin"); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("GetWalletState() of an invalid address error = %v", err)
+	}
+}
+
+func TestDiscoverWallets(t *testing.T) {
+	_, session := newMockSession(t)
+	ctx := context.Background()
+	admin := session.GetWallet().Address
+	registerSigner(t, session)
+
+	hd, err := wallet.NewHDWallet("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about", "", crypto.KeyTypeSecp256k1)
+	if err != nil {
+		t.Fatalf("NewHDWallet() error = %v", err)
+	}
+	for _, path := range []string{"m/44'/60'/0'/0/0", "m/44'/60'/0'/0/2"} {
+		w, _ := hd.DeriveChild(path)
+		payload, _ := json.Marshal(transaction.CreateWalletPayload{PublicKey: w.GetKey().GetPublicKeyHex(false), Parent: admin, KeyType: crypto.KeyTypeSecp256k1})
+		if _, err := session.GenerateTransaction(transaction.ULTransactionInput{
+			BlockchainId: testBlockchainId,
+			From:         admin,
+			To:           w.Address,
+			Payload:      string(payload),
+			PayloadType:  transaction.TX_CREATE_WALLET.String(),
+		}); err != nil {
+			t.Fatalf("CREATE_WALLET error = %v", err)
+		}
+	}
+
+	found, err := session.DiscoverWallets(ctx, testBlockchainId, hd, 2)
+	if err != nil {
+		t.Fatalf("DiscoverWallets() error = %v", err)
+	}
+	if len(found) != 2 || found[1].Path.String() != "m/44'/60'/0'/0/2" {
+		t.Fatalf("DiscoverWallets() = %v, want the wallets at indexes 0 and 2", found)
+	}
+	if w := found[0].Wallet; w.Parent != admin || !w.Enabled || w.GetKey().GetPrivateKeyHex() == "" {
+		t.Fatalf("DiscoverWallets() wallet = %+v, want an enabled child of %s with its key", w, admin)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error taxonomy of the SDK. Errors keep their own types for errors.As, and every typed error of
+// the SDK packages also matches one of these categories with errors.Is:
+//
+//	ErrInvalidInput  the caller passed malformed or inconsistent data: JSON, hex, keys, signatures,
+//	                 proofs, payloads, amounts, encoded contract values
+//	ErrUnsupported   a key type, encoding, scheme or node feature is not available
+//	ErrNotFound      a wallet, tenant, document or other resource does not exist
+//	ErrRejected      the node or a local policy refused an otherwise well formed request
+//	ErrUnavailable   a key, service or node cannot serve the request right now, retrying may help
+//	ErrConflict      the state the caller built on changed under it, e.g. a reorg past a checkpoint
+//
+// Library code never panics on bad input, it returns one of these instead. The Must functions made
+// for package level constants are the only exception.
+var (
+	ErrInvalidInput = errors.New("invalid input")
+	ErrUnsupported  = errors.New("unsupported")
+	ErrNotFound     = errors.New("not found")
+	ErrRejected     = errors.New("rejected")
+	ErrUnavailable  = errors.New("unavailable")
+	ErrConflict     = errors.New("conflict")
+)
+
+// ErrMalformed is returned for input that cannot be parsed, it is an ErrInvalidInput
+type ErrMalformed struct {
+	// What names the kind of input, e.g. "wallet file" or "contract value"
+	What string
+	Msg  string
+}
+
+func (e *ErrMalformed) Error() string {
+	return fmt.Sprintf("malformed %s, %s", e.What, e.Msg)
+}
+
+func (e *ErrMalformed) Is(target error) bool {
+	return target == ErrInvalidInput
+}
+
+// Category returns the taxonomy category err belongs to, nil when it belongs to none
+func Category(err error) error {
+	for _, category := range []error{ErrInvalidInput, ErrUnsupported, ErrNotFound, ErrRejected, ErrUnavailable, ErrConflict} {
+		if errors.Is(err, category) {
+			return category
+		}
+	}
+	return nil
+}
+
+// StatusCategory maps the status code of a failed HTTP response to its category
+func StatusCategory(statusCode int) error {
+	switch {
+	case statusCode == http.StatusNotFound:
+		return ErrNotFound
+	case statusCode == http.StatusNotImplemented:
+		return ErrUnsupported
+	case statusCode == http.StatusTooManyRequests || statusCode >= 500:
+		return ErrUnavailable
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+		return ErrInvalidInput
+	case statusCode >= 400:
+		return ErrRejected
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package vc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+// Anchor points at the TX_DATA transaction that recorded a credential hash
+type Anchor struct {
+	BlockchainId  string `json:"blockchainId"`
+	TransactionId string `json:"transactionId"`
+}
+
+// AnchorPayload is the TX_DATA payload of an anchoring transaction, sent by the issuer's wallet
+type AnchorPayload struct {
+	Hash   string `json:"vcAnchor"`
+	Issuer string `json:"issuer"`
+}
+
+// AnchorCredential records the hash of a signed credential on the issuer's chain and stores the
+// anchor in the proof
+func AnchorCredential(session *transaction.UL_TransactionSession, credential Credential) (Credential, error) {
+	if credential.Proof == nil {
+		return Credential{}, &ErrInvalidCredential{Msg: "the credential must be signed before it is anchored"}
+	}
+	hash, err := credential.Hash()
+	if err != nil {
+		return Credential{}, err
+	}
+	anchor, err := anchorHash(session, credential.Issuer, hash)
+	if err != nil {
+		return Credential{}, err
+	}
+	proof := *credential.Proof
+	proof.Anchor = &anchor
+	credential.Proof = &proof
+	return credential, nil
+}
+
+// AnchorJWT records the hash of a JWT credential, the token cannot carry its own anchor so the
+// caller keeps it next to the token
+func AnchorJWT(session *transaction.UL_TransactionSession, token string) (Anchor, error) {
+	credential, err := ParseJWT(token)
+	if err != nil {
+		return Anchor{}, err
+	}
+	return anchorHash(session, credential.Issuer, JWTHash(token))
+}
+
+// JWTHash is the SHA256 of the compact token, the value anchored on-chain
+func JWTHash(token string) []byte {
+	digest := sha256.Sum256([]byte(token))
+	return digest[:]
+}
+
+func anchorHash(session *transaction.UL_TransactionSession, issuer string, hash []byte) (Anchor, error) {
+	issuerDID, err := did.Parse(issuer)
+	if err != nil {
+		return Anchor{}, err
+	}
+	if session.GetWallet().Address != issuerDID.Address {
+		return Anchor{}, fmt.Errorf("credentials of %s must be anchored by its wallet", issuer)
+	}
+	payload, err := json.Marshal(AnchorPayload{Hash: hex.EncodeToString(hash), Issuer: issuer})
+	if err != nil {
+		return Anchor{}, err
+	}
+	tx, err := session.GenerateTransaction(transaction.ULTransactionInput{
+		BlockchainId: issuerDID.BlockchainId,
+		To:           issuerDID.Address,
+		Payload:      string(payload),
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		return Anchor{}, fmt.Errorf("unable to anchor credential: %w", err)
+	}
+	return Anchor{BlockchainId: tx.BlockchainId, TransactionId: tx.TransactionId}, nil
+}
+
+// checkAnchor verifies the anchoring transaction was sealed, succeeded, was sent by the issuer and records hash
+func checkAnchor(ctx context.Context, session *transaction.UL_TransactionSession, anchor Anchor, issuer did.DID, hash []byte) error {
+	if anchor.BlockchainId != issuer.BlockchainId {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("anchored on %s, the issuer lives on %s", anchor.BlockchainId, issuer.BlockchainId)}
+	}
+	tx, err := session.GetTransaction(ctx, anchor.BlockchainId, anchor.TransactionId)
+	if err != nil {
+		return fmt.Errorf("unable to fetch anchor %s: %w", anchor.TransactionId, err)
+	}
+	if tx.BlockHeight <= 0 || tx.Output != transaction.TX_SUCCESS.String() {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("anchor %s is not sealed", anchor.TransactionId)}
+	}
+	if tx.From != issuer.Address {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("anchor %s was not sent by the issuer", anchor.TransactionId)}
+	}
+	payload := AnchorPayload{}
+	if err := json.Unmarshal([]byte(tx.GetPayload()), &payload); err != nil || payload.Hash != hex.EncodeToString(hash) || payload.Issuer != issuer.String() {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("anchor %s does not record this credential", anchor.TransactionId)}
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package vc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	CREDENTIALS_CONTEXT = "https://www.w3.org/2018/credentials/v1"
+	CREDENTIAL_TYPE     = "VerifiableCredential"
+	// PROOF_TYPE signs the credential without its proof, serialized as JSON with sorted keys,
+	// using the wallet message signature so every ULedger key type can issue
+	PROOF_TYPE    = "ULedgerMessageSignature2025"
+	PROOF_PURPOSE = "assertionMethod"
+)
+
+type ErrInvalidCredential struct {
+	Msg string
+}
+
+func (e *ErrInvalidCredential) Error() string {
+	return fmt.Sprintf("invalid credential, %s", e.Msg)
+}
+
+func (e *ErrInvalidCredential) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Credential is a W3C Verifiable Credential, Issuer is the issuer's did:uledger identifier
+type Credential struct {
+	Context           []string       `json:"@context"`
+	Id                string         `json:"id,omitempty"`
+	Type              []string       `json:"type"`
+	Issuer            string         `json:"issuer"`
+	IssuanceDate      time.Time      `json:"issuanceDate"`
+	ExpirationDate    *time.Time     `json:"expirationDate,omitempty"`
+	CredentialSubject map[string]any `json:"credentialSubject"`
+	Proof             *Proof         `json:"proof,omitempty"`
+}
+
+// Proof is the embedded proof of a JSON-LD credential. Anchor is added after signing and is not covered
+// by the signature, it points at the transaction holding the credential hash.
+type Proof struct {
+	Type               string    `json:"type"`
+	Created            time.Time `json:"created"`
+	VerificationMethod string    `json:"verificationMethod"`
+	ProofPurpose       string    `json:"proofPurpose"`
+	ProofValue         string    `json:"proofValue"`
+	Anchor             *Anchor   `json:"anchor,omitempty"`
+}
+
+// NewCredential returns an unsigned credential issued now, types are added after VerifiableCredential
+func NewCredential(issuer string, subject map[string]any, types ...string) Credential {
+	return Credential{
+		Context:           []string{CREDENTIALS_CONTEXT},
+		Type:              append([]string{CREDENTIAL_TYPE}, types...),
+		Issuer:            issuer,
+		IssuanceDate:      time.Now().UTC().Truncate(time.Second),
+		CredentialSubject: subject,
+	}
+}
+
+// Canonical returns the bytes the proof signs: the credential without its proof as JSON with sorted keys
+func (c Credential) Canonical() ([]byte, error) {
+	c.Proof = nil
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	// Round trip through a generic value so nested subject maps are sorted and numbers are kept verbatim
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return json.Marshal(value)
+}
+
+// Hash is the SHA256 of Canonical, the value anchored on-chain
+func (c Credential) Hash() ([]byte, error) {
+	canonical, err := c.Canonical()
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(canonical)
+	return digest[:], nil
+}
+
+// Sign adds a proof made by the issuer's wallet, which must hold an assertion method of issuerDoc
+func Sign(w *wallet.UL_Wallet, issuerDoc did.Document, credential Credential) (Credential, error) {
+	if credential.Issuer != issuerDoc.Id {
+		return Credential{}, &ErrInvalidCredential{Msg: fmt.Sprintf("issuer %s does not match document %s", credential.Issuer, issuerDoc.Id)}
+	}
+	method, err := assertionMethodOf(w, issuerDoc)
+	if err != nil {
+		return Credential{}, err
+	}
+	canonical, err := credential.Canonical()
+	if err != nil {
+		return Credential{}, err
+	}
+	signature, err := w.SignMessage(canonical)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	credential.Proof = &Proof{
+		Type:               PROOF_TYPE,
+		Created:            time.Now().UTC().Truncate(time.Second),
+		VerificationMethod: issuerDoc.Id + method.Id,
+		ProofPurpose:       PROOF_PURPOSE,
+		ProofValue:         signature.Signature,
+	}
+	return credential, nil
+}
+
+// assertionMethodOf finds the verification method holding the wallet key
+func assertionMethodOf(w *wallet.UL_Wallet, issuerDoc did.Document) (did.VerificationMethod, error) {
+	if w.GetKey() == nil {
+		return did.VerificationMethod{}, fmt.Errorf("wallet has no key")
+	}
+	publicKeyHex := w.GetKey().GetPublicKeyHex(false)
+	for _, method := range issuerDoc.VerificationMethod {
+		if method.Address() == wallet.ParseAddress(publicKeyHex) && issuerDoc.Authorizes(issuerDoc.AssertionMethod, method.Id) {
+			return method, nil
+		}
+	}
+	return did.VerificationMethod{}, fmt.Errorf("the wallet key is not an assertion method of %s", issuerDoc.Id)
+}
+
+// verifySignature checks signature over message against a verification method of the issuer
+func verifySignature(method did.VerificationMethod, message []byte, signature string) error {
+	ok, err := wallet.VerifyMessage(method.Address(), message, wallet.MessageSignature{
+		Address:   method.Address(),
+		KeyType:   method.KeyType,
+		PublicKey: method.PublicKeyHex,
+		Signature: signature,
+	})
+	if err != nil {
+		return &ErrInvalidCredential{Msg: err.Error()}
+	}
+	if !ok {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("signature does not verify against %s", method.Id)}
+	}
+	return nil
+}
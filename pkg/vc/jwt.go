@@ -0,0 +1,155 @@
+package vc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// JWT algorithms for ULedger keys, the signing input is signed as a wallet message which is why
+// the standard ES256K and EdDSA names are not used
+const (
+	JWT_ALG_SECP256K1 = "UL-SECP256K1"
+	JWT_ALG_ED25519   = "UL-ED25519"
+	JWT_ALG_BLS12377  = "UL-BLS12377"
+	JWT_ALG_MLDSA87   = "UL-MLDSA87"
+)
+
+// JWTAlgorithm returns the alg header value for keys of keyType
+func JWTAlgorithm(keyType crypto.KeyType) string {
+	switch keyType {
+	case crypto.KeyTypeED25519:
+		return JWT_ALG_ED25519
+	case crypto.KeyTypeBLS12377:
+		return JWT_ALG_BLS12377
+	case crypto.KeyTypeMlDSA87:
+		return JWT_ALG_MLDSA87
+	default:
+		return JWT_ALG_SECP256K1
+	}
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ"`
+	Kid string `json:"kid"`
+}
+
+// jwtClaims follows the JWT encoding of the VC data model, the credential travels without a proof
+type jwtClaims struct {
+	Issuer     string     `json:"iss"`
+	Subject    string     `json:"sub,omitempty"`
+	Id         string     `json:"jti,omitempty"`
+	NotBefore  int64      `json:"nbf"`
+	Expires    int64      `json:"exp,omitempty"`
+	Credential Credential `json:"vc"`
+}
+
+var jwtEncoding = base64.RawURLEncoding
+
+// SignJWT encodes the credential as a compact JWT signed by the issuer's wallet
+func SignJWT(w *wallet.UL_Wallet, issuerDoc did.Document, credential Credential) (string, error) {
+	if credential.Issuer != issuerDoc.Id {
+		return "", &ErrInvalidCredential{Msg: fmt.Sprintf("issuer %s does not match document %s", credential.Issuer, issuerDoc.Id)}
+	}
+	method, err := assertionMethodOf(w, issuerDoc)
+	if err != nil {
+		return "", err
+	}
+
+	credential.Proof = nil
+	claims := jwtClaims{
+		Issuer:     credential.Issuer,
+		Id:         credential.Id,
+		NotBefore:  credential.IssuanceDate.Unix(),
+		Credential: credential,
+	}
+	if subject, ok := credential.CredentialSubject["id"].(string); ok {
+		claims.Subject = subject
+	}
+	if credential.ExpirationDate != nil {
+		claims.Expires = credential.ExpirationDate.Unix()
+	}
+
+	header, err := json.Marshal(jwtHeader{Alg: JWTAlgorithm(method.KeyType), Typ: "JWT", Kid: issuerDoc.Id + method.Id})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := jwtEncoding.EncodeToString(header) + "." + jwtEncoding.EncodeToString(payload)
+
+	signature, err := w.SignMessage([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	signatureBytes, err := crypto.HexToBytes(signature.Signature)
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + jwtEncoding.EncodeToString(signatureBytes), nil
+}
+
+// parsedJWT is a decoded but not yet verified token
+type parsedJWT struct {
+	header       jwtHeader
+	claims       jwtClaims
+	signingInput string
+	signature    string
+}
+
+// ParseJWT decodes a JWT credential without verifying it
+func ParseJWT(token string) (Credential, error) {
+	parsed, err := parseJWT(token)
+	if err != nil {
+		return Credential{}, err
+	}
+	return parsed.claims.Credential, nil
+}
+
+func parseJWT(token string) (parsedJWT, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: "a JWT has three parts"}
+	}
+	parsed := parsedJWT{signingInput: parts[0] + "." + parts[1]}
+
+	header, err := jwtEncoding.DecodeString(parts[0])
+	if err != nil {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: fmt.Sprintf("invalid JWT header: %v", err)}
+	}
+	if err := json.Unmarshal(header, &parsed.header); err != nil {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: fmt.Sprintf("invalid JWT header: %v", err)}
+	}
+	payload, err := jwtEncoding.DecodeString(parts[1])
+	if err != nil {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: fmt.Sprintf("invalid JWT payload: %v", err)}
+	}
+	if err := json.Unmarshal(payload, &parsed.claims); err != nil {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: fmt.Sprintf("invalid JWT payload: %v", err)}
+	}
+	signature, err := jwtEncoding.DecodeString(parts[2])
+	if err != nil {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: fmt.Sprintf("invalid JWT signature: %v", err)}
+	}
+	parsed.signature = crypto.BytesToHex(signature)
+
+	// The registered claims take precedence over the copies inside vc
+	if parsed.claims.Issuer != parsed.claims.Credential.Issuer {
+		return parsedJWT{}, &ErrInvalidCredential{Msg: "iss does not match the credential issuer"}
+	}
+	parsed.claims.Credential.IssuanceDate = time.Unix(parsed.claims.NotBefore, 0).UTC()
+	if parsed.claims.Expires != 0 {
+		expires := time.Unix(parsed.claims.Expires, 0).UTC()
+		parsed.claims.Credential.ExpirationDate = &expires
+	}
+	return parsed, nil
+}
@@ -0,0 +1,182 @@
+package vc_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction/transactiontest"
+	"github.com/ULedgerInc/go-sdk/pkg/vc"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const testBlockchainId = "MyBlockchain1"
+
+type issuer struct {
+	session *transaction.UL_TransactionSession
+	wallet  wallet.UL_Wallet
+	doc     did.Document
+}
+
+// newIssuer creates a wallet and publishes its DID document on the mock node
+func newIssuer(t *testing.T, node *transactiontest.MockNode, keyType crypto.KeyType) issuer {
+	t.Helper()
+	w, _, err := wallet.GenerateNewWallet("", keyType, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		t.Fatalf("GenerateNewWallet() error = %v", err)
+	}
+	session, err := transaction.NewUL_TransactionSession(node.URL(), w)
+	if err != nil {
+		t.Fatalf("NewUL_TransactionSession() error = %v", err)
+	}
+	doc, err := did.NewDocument(testBlockchainId, &w)
+	if err != nil {
+		t.Fatalf("NewDocument() error = %v", err)
+	}
+	if _, err := did.Create(&session, doc); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return issuer{session: &session, wallet: w, doc: doc}
+}
+
+func newCredential(doc did.Document) vc.Credential {
+	credential := vc.NewCredential(doc.Id, map[string]any{
+		"id":     "did:uledger:" + testBlockchainId + ":" + strings.Repeat("ab", 32),
+		"degree": map[string]any{"type": "BachelorDegree", "name": "Computer Science"},
+	}, "UniversityDegreeCredential")
+	credential.IssuanceDate = credential.IssuanceDate.Add(-time.Minute)
+	return credential
+}
+
+func TestSignAndVerifyCredential(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+
+	for _, keyType := range []crypto.KeyType{crypto.KeyTypeSecp256k1, crypto.KeyTypeED25519, crypto.KeyTypeBLS12377} {
+		is := newIssuer(t, node, keyType)
+		verifier := vc.NewVerifier(is.session, nil)
+
+		signed, err := vc.Sign(&is.wallet, is.doc, newCredential(is.doc))
+		if err != nil {
+			t.Fatalf("%s: Sign() error = %v", keyType, err)
+		}
+		if err := verifier.Verify(ctx, signed, vc.VerifyOptions{}); err != nil {
+			t.Fatalf("%s: Verify() error = %v", keyType, err)
+		}
+		if err := verifier.Verify(ctx, signed, vc.VerifyOptions{RequireAnchor: true}); err == nil {
+			t.Fatalf("%s: Verify() accepted a credential without anchor", keyType)
+		}
+
+		anchored, err := vc.AnchorCredential(is.session, signed)
+		if err != nil {
+			t.Fatalf("%s: AnchorCredential() error = %v", keyType, err)
+		}
+		if err := verifier.Verify(ctx, anchored, vc.VerifyOptions{RequireAnchor: true}); err != nil {
+			t.Fatalf("%s: Verify() anchored error = %v", keyType, err)
+		}
+
+		tampered := anchored
+		tampered.CredentialSubject = map[string]any{"id": anchored.CredentialSubject["id"], "degree": "PhD"}
+		var errInvalid *vc.ErrInvalidCredential
+		if err := verifier.Verify(ctx, tampered, vc.VerifyOptions{}); !errors.As(err, &errInvalid) {
+			t.Fatalf("%s: Verify() tampered error = %v, want ErrInvalidCredential", keyType, err)
+		}
+	}
+}
+
+func TestVerifyRejectsForeignAnchor(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+	is := newIssuer(t, node, crypto.KeyTypeSecp256k1)
+	other := newIssuer(t, node, crypto.KeyTypeSecp256k1)
+
+	first, err := vc.Sign(&is.wallet, is.doc, newCredential(is.doc))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	second, err := vc.Sign(&other.wallet, other.doc, newCredential(other.doc))
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	second, err = vc.AnchorCredential(other.session, second)
+	if err != nil {
+		t.Fatalf("AnchorCredential() error = %v", err)
+	}
+
+	// Borrowing another credential's anchor must fail
+	first.Proof.Anchor = second.Proof.Anchor
+	if err := vc.NewVerifier(is.session, nil).Verify(ctx, first, vc.VerifyOptions{}); err == nil {
+		t.Fatal("Verify() accepted an anchor of another issuer")
+	}
+
+	if _, err := vc.AnchorCredential(other.session, first); err == nil {
+		t.Fatal("AnchorCredential() anchored a credential of another issuer")
+	}
+}
+
+func TestVerifyExpiredAndDeactivated(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+	is := newIssuer(t, node, crypto.KeyTypeSecp256k1)
+	verifier := vc.NewVerifier(is.session, nil)
+
+	credential := newCredential(is.doc)
+	expires := credential.IssuanceDate.Add(time.Hour)
+	credential.ExpirationDate = &expires
+	signed, err := vc.Sign(&is.wallet, is.doc, credential)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if err := verifier.Verify(ctx, signed, vc.VerifyOptions{At: expires.Add(time.Second)}); err == nil {
+		t.Fatal("Verify() accepted an expired credential")
+	}
+
+	if _, err := did.Deactivate(is.session, is.doc.Id); err != nil {
+		t.Fatalf("Deactivate() error = %v", err)
+	}
+	if err := verifier.Verify(ctx, signed, vc.VerifyOptions{}); err == nil {
+		t.Fatal("Verify() accepted a credential of a deactivated issuer")
+	}
+}
+
+func TestSignAndVerifyJWT(t *testing.T) {
+	node := transactiontest.NewMockNode(testBlockchainId)
+	defer node.Close()
+	ctx := context.Background()
+	is := newIssuer(t, node, crypto.KeyTypeED25519)
+	verifier := vc.NewVerifier(is.session, nil)
+
+	token, err := vc.SignJWT(&is.wallet, is.doc, newCredential(is.doc))
+	if err != nil {
+		t.Fatalf("SignJWT() error = %v", err)
+	}
+	credential, err := verifier.VerifyJWT(ctx, token, nil, vc.VerifyOptions{})
+	if err != nil {
+		t.Fatalf("VerifyJWT() error = %v", err)
+	}
+	if credential.Issuer != is.doc.Id {
+		t.Fatalf("Issuer = %s, want %s", credential.Issuer, is.doc.Id)
+	}
+
+	anchor, err := vc.AnchorJWT(is.session, token)
+	if err != nil {
+		t.Fatalf("AnchorJWT() error = %v", err)
+	}
+	if _, err := verifier.VerifyJWT(ctx, token, &anchor, vc.VerifyOptions{RequireAnchor: true}); err != nil {
+		t.Fatalf("VerifyJWT() anchored error = %v", err)
+	}
+
+	parts := strings.Split(token, ".")
+	forged := parts[0] + "." + parts[1] + "." + parts[2][:len(parts[2])-4] + "AAAA"
+	if _, err := verifier.VerifyJWT(ctx, forged, nil, vc.VerifyOptions{}); err == nil {
+		t.Fatal("VerifyJWT() accepted a forged signature")
+	}
+}
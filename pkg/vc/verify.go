@@ -0,0 +1,141 @@
+package vc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/did"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+)
+
+type VerifyOptions struct {
+	// RequireAnchor fails credentials that were never anchored on-chain
+	RequireAnchor bool
+	// At is the time validity is checked at, defaults to now
+	At time.Time
+}
+
+// Verifier checks credentials against the issuer's on-chain DID document and anchor
+type Verifier struct {
+	session  *transaction.UL_TransactionSession
+	resolver *did.Resolver
+}
+
+func NewVerifier(session *transaction.UL_TransactionSession, resolver *did.Resolver) *Verifier {
+	if resolver == nil {
+		resolver = did.NewResolver(session)
+	}
+	return &Verifier{session: session, resolver: resolver}
+}
+
+// Verify checks the embedded proof of a JSON-LD credential and, when present, its anchor
+func (v *Verifier) Verify(ctx context.Context, credential Credential, opts VerifyOptions) error {
+	proof := credential.Proof
+	if proof == nil {
+		return &ErrInvalidCredential{Msg: "the credential has no proof"}
+	}
+	if proof.Type != PROOF_TYPE || proof.ProofPurpose != PROOF_PURPOSE {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("unsupported proof %s for %s", proof.Type, proof.ProofPurpose)}
+	}
+	issuer, method, err := v.assertionMethod(ctx, credential.Issuer, proof.VerificationMethod)
+	if err != nil {
+		return err
+	}
+	if err := checkValidity(credential, opts); err != nil {
+		return err
+	}
+
+	canonical, err := credential.Canonical()
+	if err != nil {
+		return err
+	}
+	if err := verifySignature(method, canonical, proof.ProofValue); err != nil {
+		return err
+	}
+
+	if proof.Anchor == nil {
+		if opts.RequireAnchor {
+			return &ErrInvalidCredential{Msg: "the credential is not anchored"}
+		}
+		return nil
+	}
+	hash, err := credential.Hash()
+	if err != nil {
+		return err
+	}
+	return checkAnchor(ctx, v.session, *proof.Anchor, issuer, hash)
+}
+
+// VerifyJWT checks a JWT credential, anchor is the result of AnchorJWT or nil. The decoded
+// credential is returned once it verifies.
+func (v *Verifier) VerifyJWT(ctx context.Context, token string, anchor *Anchor, opts VerifyOptions) (Credential, error) {
+	parsed, err := parseJWT(token)
+	if err != nil {
+		return Credential{}, err
+	}
+	credential := parsed.claims.Credential
+	issuer, method, err := v.assertionMethod(ctx, credential.Issuer, parsed.header.Kid)
+	if err != nil {
+		return Credential{}, err
+	}
+	if parsed.header.Alg != JWTAlgorithm(method.KeyType) {
+		return Credential{}, &ErrInvalidCredential{Msg: fmt.Sprintf("alg %s does not match %s", parsed.header.Alg, method.Id)}
+	}
+	if err := checkValidity(credential, opts); err != nil {
+		return Credential{}, err
+	}
+	if err := verifySignature(method, []byte(parsed.signingInput), parsed.signature); err != nil {
+		return Credential{}, err
+	}
+
+	if anchor == nil {
+		if opts.RequireAnchor {
+			return Credential{}, &ErrInvalidCredential{Msg: "the credential is not anchored"}
+		}
+		return credential, nil
+	}
+	if err := checkAnchor(ctx, v.session, *anchor, issuer, JWTHash(token)); err != nil {
+		return Credential{}, err
+	}
+	return credential, nil
+}
+
+// assertionMethod resolves the issuer and checks methodId is one of its active assertion methods
+func (v *Verifier) assertionMethod(ctx context.Context, issuer string, methodId string) (did.DID, did.VerificationMethod, error) {
+	issuerDID, err := did.Parse(issuer)
+	if err != nil {
+		return did.DID{}, did.VerificationMethod{}, err
+	}
+	if methodDID, err := did.Parse(methodId); err != nil || methodDID != issuerDID {
+		return did.DID{}, did.VerificationMethod{}, &ErrInvalidCredential{Msg: fmt.Sprintf("%s is not a key of %s", methodId, issuer)}
+	}
+
+	resolution, err := v.resolver.Resolve(ctx, issuer)
+	if err != nil {
+		return did.DID{}, did.VerificationMethod{}, err
+	}
+	if resolution.Metadata.Deactivated {
+		return did.DID{}, did.VerificationMethod{}, &ErrInvalidCredential{Msg: fmt.Sprintf("issuer %s is deactivated", issuer)}
+	}
+	doc := resolution.Document
+	method, ok := doc.GetVerificationMethod(methodId)
+	if !ok || !doc.Authorizes(doc.AssertionMethod, methodId) {
+		return did.DID{}, did.VerificationMethod{}, &ErrInvalidCredential{Msg: fmt.Sprintf("%s is not an assertion method of %s", methodId, issuer)}
+	}
+	return issuerDID, method, nil
+}
+
+func checkValidity(credential Credential, opts VerifyOptions) error {
+	at := opts.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if at.Before(credential.IssuanceDate) {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("not valid before %s", credential.IssuanceDate.Format(time.RFC3339))}
+	}
+	if credential.ExpirationDate != nil && !at.Before(*credential.ExpirationDate) {
+		return &ErrInvalidCredential{Msg: fmt.Sprintf("expired at %s", credential.ExpirationDate.Format(time.RFC3339))}
+	}
+	return nil
+}
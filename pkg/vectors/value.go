@@ -0,0 +1,172 @@
+package vectors
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Value is a contract value in JSON, tagged with its serializer type so that every implementation
+// reads the same value. The types are the names of bindgen.Types plus "null" and "array": int64
+// values are decimal strings so they survive JavaScript numbers, bytes are hex, and arrays and maps
+// hold Values.
+type Value struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type serializerExample struct {
+	name  string
+	value interface{}
+}
+
+// serializerExamples cover every serializer type with its edge cases
+func serializerExamples() []serializerExample {
+	return []serializerExample{
+		{"null", nil},
+		{"bool/true", true},
+		{"bool/false", false},
+		{"int32/zero", int32(0)},
+		{"int32/negative", int32(-1)},
+		{"int32/max", int32(math.MaxInt32)},
+		{"int32/min", int32(math.MinInt32)},
+		{"int64/zero", int64(0)},
+		{"int64/max", int64(math.MaxInt64)},
+		{"int64/min", int64(math.MinInt64)},
+		{"float32", float32(1.5)},
+		{"float32/negative", float32(-0.25)},
+		{"float64", float64(3.141592653589793)},
+		{"float64/small", float64(-1e-300)},
+		{"string/empty", ""},
+		{"string", "ULedger"},
+		{"string/unicode", "héllo, 世界"},
+		{"bytes/empty", []byte{}},
+		{"bytes", []byte{0x00, 0x01, 0xfe, 0xff}},
+		{"array/empty", []interface{}{}},
+		{"array", []interface{}{int32(1), "two", true, nil}},
+		{"array/nested", []interface{}{[]interface{}{int64(1)}, []interface{}{}}},
+		{"map/empty", map[string]interface{}{}},
+		{"map", map[string]interface{}{"b": int32(2), "a": "first", "c": []byte{0xca, 0xfe}}},
+		{"map/nested", map[string]interface{}{"owner": map[string]interface{}{"balance": int64(1000), "frozen": false}, "tags": []interface{}{"x", "y"}}},
+	}
+}
+
+// valueOf returns the Value of a value the serializer encodes
+func valueOf(v interface{}) (Value, error) {
+	var (
+		typeName string
+		value    interface{}
+	)
+	switch v := v.(type) {
+	case nil:
+		return Value{Type: "null"}, nil
+	case bool:
+		typeName, value = "bool", v
+	case int32:
+		typeName, value = "int32", v
+	case int64:
+		typeName, value = "int64", strconv.FormatInt(v, 10)
+	case float32:
+		typeName, value = "float32", json.Number(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case float64:
+		typeName, value = "float64", v
+	case string:
+		typeName, value = "string", v
+	case []byte:
+		typeName, value = "bytes", hex.EncodeToString(v)
+	case []interface{}:
+		elements := make([]Value, len(v))
+		for i, element := range v {
+			var err error
+			if elements[i], err = valueOf(element); err != nil {
+				return Value{}, err
+			}
+		}
+		typeName, value = "array", elements
+	case map[string]interface{}:
+		entries := make(map[string]Value, len(v))
+		for key, entry := range v {
+			var err error
+			if entries[key], err = valueOf(entry); err != nil {
+				return Value{}, err
+			}
+		}
+		typeName, value = "map", entries
+	default:
+		return Value{}, fmt.Errorf("unsupported type %T", v)
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return Value{}, err
+	}
+	return Value{Type: typeName, Value: raw}, nil
+}
+
+// goValue returns the Go value of v, of the type Decode returns
+func (v Value) goValue() (interface{}, error) {
+	switch v.Type {
+	case "null":
+		return nil, nil
+	case "bool":
+		var b bool
+		return b, json.Unmarshal(v.Value, &b)
+	case "int32":
+		var i int32
+		return i, json.Unmarshal(v.Value, &i)
+	case "int64":
+		var s string
+		if err := json.Unmarshal(v.Value, &s); err != nil {
+			return nil, err
+		}
+		return strconv.ParseInt(s, 10, 64)
+	case "float32":
+		var n json.Number
+		if err := json.Unmarshal(v.Value, &n); err != nil {
+			return nil, err
+		}
+		f, err := strconv.ParseFloat(n.String(), 32)
+		return float32(f), err
+	case "float64":
+		var f float64
+		return f, json.Unmarshal(v.Value, &f)
+	case "string":
+		var s string
+		return s, json.Unmarshal(v.Value, &s)
+	case "bytes":
+		var s string
+		if err := json.Unmarshal(v.Value, &s); err != nil {
+			return nil, err
+		}
+		return hex.DecodeString(s)
+	case "array":
+		elements := []Value{}
+		if err := json.Unmarshal(v.Value, &elements); err != nil {
+			return nil, err
+		}
+		array := make([]interface{}, len(elements))
+		for i, element := range elements {
+			var err error
+			if array[i], err = element.goValue(); err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+		}
+		return array, nil
+	case "map":
+		entries := map[string]Value{}
+		if err := json.Unmarshal(v.Value, &entries); err != nil {
+			return nil, err
+		}
+		m := make(map[string]interface{}, len(entries))
+		for key, entry := range entries {
+			var err error
+			if m[key], err = entry.goValue(); err != nil {
+				return nil, fmt.Errorf("key %s: %w", key, err)
+			}
+		}
+		return m, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q", v.Type)
+	}
+}
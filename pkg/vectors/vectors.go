@@ -0,0 +1,245 @@
+// Package vectors generates the deterministic test vectors other ULedger SDKs check themselves
+// against: keys derived from mnemonics, addresses, transaction commitments and signatures, and the
+// contract serializer encodings of every type. An SDK reads the inputs of a suite, writes its own
+// outputs in the same format and Verify reports where they differ from the Go SDK:
+//
+//	uledger vectors generate --out vectors.json
+//	uledger vectors verify js-vectors.json
+//
+// Signatures of key types whose signatures are randomized, see crypto.KeyCapabilities, differ from
+// run to run. They are verified against the public key instead of compared.
+package vectors
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+const (
+	// SUITE_VERSION is the version of the suites Generate writes, Verify rejects other versions
+	SUITE_VERSION = 1
+	// DEFAULT_SEED derives the entropy of the keys of the published suite
+	DEFAULT_SEED = "uledger-test-vectors"
+	// PASSPHRASE protects the seeds of the mnemonics of every key vector
+	PASSPHRASE = "uledger"
+)
+
+// TIMESTAMP is the sender timestamp of every commitment vector
+var TIMESTAMP = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrUnsupportedSuite is returned by Verify for suites it cannot read
+type ErrUnsupportedSuite struct {
+	Msg string
+}
+
+func (e *ErrUnsupportedSuite) Error() string {
+	return fmt.Sprintf("unsupported test vector suite: %s", e.Msg)
+}
+
+func (e *ErrUnsupportedSuite) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Suite is a set of test vectors, the fields of each vector are documented as inputs or outputs
+type Suite struct {
+	Version     int                `json:"version"`
+	Seed        string             `json:"seed"`
+	Keys        []KeyVector        `json:"keys"`
+	Commitments []CommitmentVector `json:"commitments"`
+	Serializer  []SerializerVector `json:"serializer"`
+}
+
+// KeyVector derives a wallet from BIP-39 entropy. KeyType, Entropy and Passphrase are inputs, the
+// other fields are outputs. Byte strings are hex encoded.
+type KeyVector struct {
+	Name       string `json:"name"`
+	KeyType    string `json:"keyType"`
+	Entropy    string `json:"entropy"`
+	Passphrase string `json:"passphrase"`
+	Mnemonic   string `json:"mnemonic"`
+	Seed       string `json:"seed"`
+	PrivateKey string `json:"privateKey"`
+	PublicKey  string `json:"publicKey"`
+	Address    string `json:"address"`
+}
+
+// CommitmentVector is a transaction signed by the key of a KeyVector, the sender. Input holds the
+// inputs but for its outputs PayloadRoot and SenderSignature, Commitment is the signed commitment.
+type CommitmentVector struct {
+	Name       string                         `json:"name"`
+	Input      transaction.ULTransactionInput `json:"input"`
+	Commitment string                         `json:"commitment"`
+}
+
+// SerializerVector is a contract value, the input, and its Encode output
+type SerializerVector struct {
+	Name    string `json:"name"`
+	Value   Value  `json:"value"`
+	Encoded string `json:"encoded"`
+}
+
+// Mismatch is an output of a vector that differs from the output of the Go SDK
+type Mismatch struct {
+	Vector string `json:"vector"`
+	Field  string `json:"field"`
+	Want   string `json:"want"`
+	Got    string `json:"got"`
+}
+
+func (m Mismatch) String() string {
+	return fmt.Sprintf("%s %s: want %s, got %s", m.Vector, m.Field, m.Want, m.Got)
+}
+
+// Generate returns the suite whose keys draw their entropy from seed, see DEFAULT_SEED
+func Generate(seed string) (Suite, error) {
+	suite := Suite{Version: SUITE_VERSION, Seed: seed}
+	senders := map[crypto.KeyType]wallet.UL_Wallet{}
+	for _, keyType := range crypto.SupportedKeyTypes() {
+		entropy := make([]byte, wallet.DefaultEntropy/8)
+		if _, err := io.ReadFull(wallet.NewDeterministicSource(seed+"/"+keyType.String()), entropy); err != nil {
+			return Suite{}, err
+		}
+		vector := KeyVector{Name: "key/" + keyType.String(), KeyType: keyType.String(), Entropy: hex.EncodeToString(entropy), Passphrase: PASSPHRASE}
+		sender, err := deriveKey(&vector)
+		if err != nil {
+			return Suite{}, fmt.Errorf("%s: %w", vector.Name, err)
+		}
+		suite.Keys = append(suite.Keys, vector)
+		senders[keyType] = sender
+	}
+
+	for _, keyType := range crypto.SupportedKeyTypes() {
+		sender := senders[keyType]
+		for _, input := range commitmentInputs(seed, keyType) {
+			signed, err := transaction.BuildSignedTransaction(input.input, &sender)
+			if err != nil {
+				return Suite{}, fmt.Errorf("%s: %w", input.name, err)
+			}
+			commitment, _, err := signed.SigningCommitment()
+			if err != nil {
+				return Suite{}, fmt.Errorf("%s: %w", input.name, err)
+			}
+			suite.Commitments = append(suite.Commitments, CommitmentVector{
+				Name:       input.name,
+				Input:      signed,
+				Commitment: hex.EncodeToString(commitment),
+			})
+		}
+	}
+
+	for _, example := range serializerExamples() {
+		value, err := valueOf(example.value)
+		if err != nil {
+			return Suite{}, fmt.Errorf("%s: %w", example.name, err)
+		}
+		encoded, err := transaction.Encode(example.value)
+		if err != nil {
+			return Suite{}, fmt.Errorf("%s: %w", example.name, err)
+		}
+		suite.Serializer = append(suite.Serializer, SerializerVector{Name: "serializer/" + example.name, Value: value, Encoded: hex.EncodeToString(encoded)})
+	}
+	return suite, nil
+}
+
+// deriveKey fills in the outputs of a key vector from its inputs and returns the wallet
+func deriveKey(vector *KeyVector) (wallet.UL_Wallet, error) {
+	keyType, err := parseKeyType(vector.KeyType)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	entropy, err := hex.DecodeString(vector.Entropy)
+	if err != nil {
+		return wallet.UL_Wallet{}, fmt.Errorf("invalid entropy: %w", err)
+	}
+	vector.Mnemonic, err = wallet.GenerateMnemonicFrom(bytes.NewReader(entropy), wallet.Entropy(len(entropy)*8))
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	seed, err := wallet.MnemonicToSeed(vector.Mnemonic, vector.Passphrase)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	derived, err := wallet.GenerateFromSeed(seed, keyType)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	vector.Seed = hex.EncodeToString(seed)
+	vector.PrivateKey = derived.GetKey().GetPrivateKeyHex()
+	vector.PublicKey = derived.GetKey().GetPublicKeyHex(false)
+	vector.Address = derived.Address
+	return derived, nil
+}
+
+// parseKeyType is crypto.ParseCryptoKeyType without its fallback to secp256k1
+func parseKeyType(name string) (crypto.KeyType, error) {
+	keyType := crypto.ParseCryptoKeyType(name)
+	if keyType.String() != strings.ToLower(name) {
+		return 0, &ErrUnsupportedSuite{Msg: fmt.Sprintf("unknown key type %q", name)}
+	}
+	return keyType, nil
+}
+
+// commitmentInput is an unsigned transaction of a commitment vector
+type commitmentInput struct {
+	name  string
+	input transaction.ULTransactionInput
+}
+
+// commitmentInputs are the unsigned transactions every key signs: bound payloads of one and of
+// several chunks and an unbound payload, with each commitment scheme. Keys supporting DER signatures
+// also sign a transaction with a DER encoded signature.
+func commitmentInputs(seed string, keyType crypto.KeyType) []commitmentInput {
+	blockchainId := sha256.Sum256([]byte(seed + "/blockchain"))
+	suggestor := sha256.Sum256([]byte(seed + "/suggestor"))
+	recipient := sha256.Sum256([]byte(seed + "/recipient"))
+	base := transaction.ULTransactionInput{
+		BlockchainId:    hex.EncodeToString(blockchainId[:]),
+		Suggestor:       hex.EncodeToString(suggestor[:]),
+		To:              hex.EncodeToString(recipient[:]),
+		PayloadType:     transaction.TX_DATA.String(),
+		SenderTimestamp: TIMESTAMP,
+	}
+	payloads := []struct {
+		name        string
+		payloadType transaction.ULTransactionType
+		payload     string
+	}{
+		{"short", transaction.TX_DATA, "hello"},
+		{"chunked", transaction.TX_DATA, strings.Repeat("ULedger test vectors ", 10)},
+		{"unbound", transaction.DEPLOY_SMART_CONTRACT, "(module)"},
+	}
+
+	inputs := []commitmentInput{}
+	for _, scheme := range []string{transaction.COMMITMENT_SCHEME_MIMC, transaction.COMMITMENT_SCHEME_SHA256_PARALLEL} {
+		for _, payload := range payloads {
+			input := base
+			if scheme != transaction.COMMITMENT_SCHEME_MIMC {
+				input.CommitmentScheme = scheme
+			}
+			input.PayloadType = payload.payloadType.String()
+			input.Payload = payload.payload
+			if payload.payloadType == transaction.DEPLOY_SMART_CONTRACT {
+				// Deployments address no one
+				input.To = ""
+			}
+			inputs = append(inputs, commitmentInput{name: fmt.Sprintf("commitment/%s/%s/%s", keyType, scheme, payload.name), input: input})
+		}
+	}
+	if crypto.SupportsSignatureEncoding(keyType, crypto.SIGNATURE_ENCODING_DER) {
+		input := base
+		input.Payload = "hello"
+		input.SignatureEncoding = crypto.SIGNATURE_ENCODING_DER
+		inputs = append(inputs, commitmentInput{name: fmt.Sprintf("commitment/%s/der", keyType), input: input})
+	}
+	return inputs
+}
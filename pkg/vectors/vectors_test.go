@@ -0,0 +1,118 @@
+package vectors_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/vectors"
+)
+
+func TestGenerateVerify(t *testing.T) {
+	suite, err := vectors.Generate("test")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(suite.Keys) != 4 || len(suite.Commitments) == 0 || len(suite.Serializer) == 0 {
+		t.Fatalf("Generate() = %d keys, %d commitments, %d serializer vectors", len(suite.Keys), len(suite.Commitments), len(suite.Serializer))
+	}
+	if mismatches, err := vectors.Verify(suite); err != nil || len(mismatches) > 0 {
+		t.Fatalf("Verify() of a generated suite = %v, %v", mismatches, err)
+	}
+
+	again, err := vectors.Generate("test")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	for i := range suite.Keys {
+		if again.Keys[i] != suite.Keys[i] {
+			t.Errorf("Generate() key %s = %+v, then %+v", suite.Keys[i].Name, suite.Keys[i], again.Keys[i])
+		}
+	}
+	for i := range suite.Commitments {
+		if again.Commitments[i].Commitment != suite.Commitments[i].Commitment || again.Commitments[i].Input.PayloadRoot != suite.Commitments[i].Input.PayloadRoot {
+			t.Errorf("Generate() commitment %s differs between runs", suite.Commitments[i].Name)
+		}
+	}
+	for _, vector := range suite.Serializer {
+		if vector.Name == "serializer/int64/max" && string(vector.Value.Value) != `"9223372036854775807"` {
+			t.Errorf("%s value = %s, int64 values must be strings", vector.Name, vector.Value.Value)
+		}
+	}
+	if other, _ := vectors.Generate("other"); other.Keys[0].Address == suite.Keys[0].Address {
+		t.Errorf("Generate() of another seed derived the same keys")
+	}
+}
+
+// TestPublishedSuite keeps the Go SDK compatible with the suite other SDKs are tested against,
+// regenerate it with uledger vectors generate --out pkg/vectors/testdata/vectors.json
+func TestPublishedSuite(t *testing.T) {
+	data, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	suite := vectors.Suite{}
+	if err := json.Unmarshal(data, &suite); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if mismatches, err := vectors.Verify(suite); err != nil || len(mismatches) > 0 {
+		t.Fatalf("Verify() = %v, %v", mismatches, err)
+	}
+}
+
+func TestVerifyMismatches(t *testing.T) {
+	suite, err := vectors.Generate("test")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// The JSON round trip is what another implementation's suite goes through
+	data, _ := json.Marshal(suite)
+	json.Unmarshal(data, &suite)
+
+	suite.Keys[0].Address = strings.Repeat("0", 64)
+	suite.Keys[1].Mnemonic = "abandon"
+	byName := map[string]int{}
+	for i, vector := range suite.Commitments {
+		byName[vector.Name] = i
+	}
+	suite.Commitments[byName["commitment/ed25519/mimc/short"]].Commitment = "00"
+	// Deterministic signatures must be equal, randomized ones must verify
+	ed25519 := &suite.Commitments[byName["commitment/ed25519/sha256-parallel/chunked"]].Input
+	ed25519.SenderSignature = suite.Commitments[byName["commitment/ed25519/mimc/chunked"]].Input.SenderSignature
+	secp256k1 := &suite.Commitments[byName["commitment/secp256k1/mimc/unbound"]].Input
+	secp256k1.SenderSignature = suite.Commitments[byName["commitment/secp256k1/mimc/short"]].Input.SenderSignature
+	suite.Serializer[3].Encoded = "ff"
+
+	mismatches, err := vectors.Verify(suite)
+	if err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	got := []string{}
+	for _, mismatch := range mismatches {
+		got = append(got, mismatch.Vector+" "+mismatch.Field)
+	}
+	want := []string{
+		"key/secp256k1 address",
+		"key/mldsa87 mnemonic",
+		"commitment/secp256k1/mimc/unbound input.senderSignature",
+		"commitment/ed25519/mimc/short commitment",
+		"commitment/ed25519/sha256-parallel/chunked input.senderSignature",
+		suite.Serializer[3].Name + " encoded",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("Verify() mismatches =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	suite.Version = vectors.SUITE_VERSION + 1
+	if _, err := vectors.Verify(suite); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Verify() of another version error = %v", err)
+	}
+	suite.Version = vectors.SUITE_VERSION
+	suite.Serializer[0].Value.Type = "uint8"
+	if _, err := vectors.Verify(suite); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Fatalf("Verify() of an unknown serializer type error = %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package vectors
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// Verify recomputes the outputs of every vector of suite from its inputs and returns those that
+// differ. Hex outputs are compared ignoring case. Signatures must verify against the public key of
+// the sender and equal the Go SDK's when the signatures of the key type are deterministic. Suites
+// whose inputs cannot be read return an ErrUnsupportedSuite.
+func Verify(suite Suite) ([]Mismatch, error) {
+	if suite.Version != SUITE_VERSION {
+		return nil, &ErrUnsupportedSuite{Msg: fmt.Sprintf("version %d, expected %d", suite.Version, SUITE_VERSION)}
+	}
+	mismatches := []Mismatch{}
+	compare := func(vector string, field string, want string, got string, hexEncoded bool) {
+		if want == got || (hexEncoded && strings.EqualFold(want, got)) {
+			return
+		}
+		mismatches = append(mismatches, Mismatch{Vector: vector, Field: field, Want: want, Got: got})
+	}
+
+	senders := map[string]wallet.UL_Wallet{}
+	for _, vector := range suite.Keys {
+		want := KeyVector{Name: vector.Name, KeyType: vector.KeyType, Entropy: vector.Entropy, Passphrase: vector.Passphrase}
+		sender, err := deriveKey(&want)
+		if err != nil {
+			return nil, &ErrUnsupportedSuite{Msg: fmt.Sprintf("%s: %s", vector.Name, err)}
+		}
+		senders[sender.Address] = sender
+		compare(vector.Name, "mnemonic", want.Mnemonic, vector.Mnemonic, false)
+		compare(vector.Name, "seed", want.Seed, vector.Seed, true)
+		compare(vector.Name, "privateKey", want.PrivateKey, vector.PrivateKey, true)
+		compare(vector.Name, "publicKey", want.PublicKey, vector.PublicKey, true)
+		compare(vector.Name, "address", want.Address, vector.Address, true)
+	}
+
+	for _, vector := range suite.Commitments {
+		commitment, payloadRoot, err := vector.Input.SigningCommitment()
+		if err != nil {
+			return nil, &ErrUnsupportedSuite{Msg: fmt.Sprintf("%s: %s", vector.Name, err)}
+		}
+		compare(vector.Name, "input.payloadRoot", payloadRoot, vector.Input.PayloadRoot, true)
+		compare(vector.Name, "commitment", hex.EncodeToString(commitment), vector.Commitment, true)
+
+		sender, ok := senders[vector.Input.From]
+		if !ok {
+			mismatches = append(mismatches, Mismatch{Vector: vector.Name, Field: "input.from", Want: "the address of a key vector", Got: vector.Input.From})
+			continue
+		}
+		if mismatch, ok := verifySignature(sender, commitment, vector); !ok {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+
+	for _, vector := range suite.Serializer {
+		value, err := vector.Value.goValue()
+		if err != nil {
+			return nil, &ErrUnsupportedSuite{Msg: fmt.Sprintf("%s: %s", vector.Name, err)}
+		}
+		encoded, err := transaction.Encode(value)
+		if err != nil {
+			return nil, &ErrUnsupportedSuite{Msg: fmt.Sprintf("%s: %s", vector.Name, err)}
+		}
+		compare(vector.Name, "encoded", hex.EncodeToString(encoded), vector.Encoded, true)
+	}
+	return mismatches, nil
+}
+
+// verifySignature checks the sender signature of a commitment vector
+func verifySignature(sender wallet.UL_Wallet, commitment []byte, vector CommitmentVector) (Mismatch, bool) {
+	mismatch := Mismatch{Vector: vector.Name, Field: "input.senderSignature", Want: "a valid signature", Got: vector.Input.SenderSignature}
+	signature, err := hex.DecodeString(vector.Input.SenderSignature)
+	if err != nil {
+		return mismatch, false
+	}
+	if valid, err := crypto.VerifySignatureWithEncoding(sender.GetKey(), commitment, signature, vector.Input.SignatureEncoding); !valid || err != nil {
+		return mismatch, false
+	}
+
+	capabilities, err := crypto.Capabilities(sender.GetKey().GetType())
+	if err != nil || !capabilities.DeterministicSignatures {
+		return Mismatch{}, true
+	}
+	want, err := crypto.SignDataWithEncoding(sender.GetKey(), commitment, vector.Input.SignatureEncoding)
+	if err != nil {
+		mismatch.Want = err.Error()
+		return mismatch, false
+	}
+	if !strings.EqualFold(hex.EncodeToString(want), vector.Input.SenderSignature) {
+		mismatch.Want = hex.EncodeToString(want)
+		return mismatch, false
+	}
+	return Mismatch{}, true
+}
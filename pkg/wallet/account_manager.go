@@ -0,0 +1,92 @@
+package wallet
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+// ErrAccountConflict is returned when an address is already held by an account from another source
+type ErrAccountConflict struct {
+	Address  string
+	Existing string
+	Source   string
+}
+
+func (e *ErrAccountConflict) Error() string {
+	return fmt.Sprintf("account %s from %s conflicts with the one loaded from %s", e.Address, e.Source, e.Existing)
+}
+
+func (e *ErrAccountConflict) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Account is a wallet held by an AccountManager, Source names where it was loaded from
+type Account struct {
+	Wallet   UL_Wallet
+	Source   string
+	LoadedAt time.Time
+}
+
+// AccountManager holds the wallets of a service by address and is safe for concurrent use, so the
+// set of accounts can change at runtime, e.g. through a DirectoryWatcher
+type AccountManager struct {
+	mu       sync.RWMutex
+	accounts map[string]Account
+}
+
+func NewAccountManager() *AccountManager {
+	return &AccountManager{accounts: make(map[string]Account)}
+}
+
+// Add stores a wallet, replacing the account of the same address only when it comes from the same source
+func (m *AccountManager) Add(w UL_Wallet, source string) error {
+	if w.Address == "" {
+		return fmt.Errorf("the wallet from %s has no address", source)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if existing, ok := m.accounts[w.Address]; ok && existing.Source != source {
+		return &ErrAccountConflict{Address: w.Address, Existing: existing.Source, Source: source}
+	}
+	m.accounts[w.Address] = Account{Wallet: w, Source: source, LoadedAt: time.Now().UTC()}
+	return nil
+}
+
+// Remove evicts an account and reports whether it was present
+func (m *AccountManager) Remove(address string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.accounts[address]
+	delete(m.accounts, address)
+	return ok
+}
+
+func (m *AccountManager) Get(address string) (UL_Wallet, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	account, ok := m.accounts[address]
+	return account.Wallet, ok
+}
+
+// Accounts returns the accounts ordered by address
+func (m *AccountManager) Accounts() []Account {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	accounts := make([]Account, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		accounts = append(accounts, account)
+	}
+	slices.SortFunc(accounts, func(a, b Account) int { return strings.Compare(a.Wallet.Address, b.Wallet.Address) })
+	return accounts
+}
+
+func (m *AccountManager) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.accounts)
+}
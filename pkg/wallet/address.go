@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+type AddressMode int
+
+const (
+	// ADDRESS_MODE_ULEDGER is the native address, the SHA256 of the lowercase public key hex
+	ADDRESS_MODE_ULEDGER AddressMode = iota
+	// ADDRESS_MODE_ETHEREUM is the last 20 bytes of the Keccak256 of the uncompressed secp256k1 key, EIP-55 checksummed
+	ADDRESS_MODE_ETHEREUM
+)
+
+func (m AddressMode) String() string {
+	switch m {
+	case ADDRESS_MODE_ULEDGER:
+		return "uledger"
+	case ADDRESS_MODE_ETHEREUM:
+		return "ethereum"
+	default:
+		return "unknown"
+	}
+}
+
+func ParseAddressMode(mode string) (AddressMode, error) {
+	switch strings.ToLower(mode) {
+	case ADDRESS_MODE_ULEDGER.String(), "":
+		return ADDRESS_MODE_ULEDGER, nil
+	case ADDRESS_MODE_ETHEREUM.String():
+		return ADDRESS_MODE_ETHEREUM, nil
+	default:
+		return ADDRESS_MODE_ULEDGER, fmt.Errorf("unknown address mode: %s", mode)
+	}
+}
+
+// ParseAddressWithMode derives the address of a public key in the given mode
+func ParseAddressWithMode(publicKeyHex string, mode AddressMode) (string, error) {
+	switch mode {
+	case ADDRESS_MODE_ULEDGER:
+		return ParseAddress(publicKeyHex), nil
+	case ADDRESS_MODE_ETHEREUM:
+		return ParseEthereumAddress(publicKeyHex)
+	default:
+		return "", fmt.Errorf("unknown address mode: %d", mode)
+	}
+}
+
+// ParseEthereumAddress derives the EIP-55 address of an uncompressed secp256k1 public key
+func ParseEthereumAddress(publicKeyHex string) (string, error) {
+	publicKey, err := hex.DecodeString(strings.TrimPrefix(publicKeyHex, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("invalid public key hex: %w", err)
+	}
+	if len(publicKey) != 65 || publicKey[0] != 0x04 {
+		return "", fmt.Errorf("expected a 65 byte uncompressed secp256k1 public key, got %d bytes", len(publicKey))
+	}
+
+	digest := crypto.Keccak256(publicKey[1:])
+	return ToChecksumAddress(hex.EncodeToString(digest[12:]))
+}
+
+// ToChecksumAddress applies the EIP-55 mixed case checksum to a 20 byte hex address
+func ToChecksumAddress(address string) (string, error) {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if len(lower) != 40 {
+		return "", fmt.Errorf("expected a 20 byte address, got %d hex characters", len(lower))
+	}
+	if _, err := hex.DecodeString(lower); err != nil {
+		return "", fmt.Errorf("invalid address hex: %w", err)
+	}
+
+	digest := hex.EncodeToString(crypto.Keccak256([]byte(lower)))
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c >= 'a' && c <= 'f' && digest[i] >= '8' {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed), nil
+}
+
+// IsValidEthereumAddress reports whether address is a 20 byte hex address, all lowercase or
+// all uppercase addresses carry no checksum, mixed case ones must match EIP-55
+func IsValidEthereumAddress(address string) bool {
+	checksummed, err := ToChecksumAddress(address)
+	if err != nil {
+		return false
+	}
+	body := strings.TrimPrefix(address, "0x")
+	if body == strings.ToLower(body) || body == strings.ToUpper(body) {
+		return true
+	}
+	return "0x"+body == checksummed
+}
+
+// EthereumAddress returns the EVM address controlled by the same secp256k1 key as this wallet
+func (w *UL_Wallet) EthereumAddress() (string, error) {
+	if err := w.CheckKey(); err != nil {
+		return "", err
+	}
+	if w.key.GetType() != crypto.KeyTypeSecp256k1 {
+		return "", fmt.Errorf("ethereum addresses require a %s key, got %s", crypto.KeyTypeSecp256k1, w.key.GetType())
+	}
+	return ParseEthereumAddress(w.key.GetPublicKeyHex(false))
+}
@@ -1,8 +1,8 @@
 package wallet
 
 import (
-	"crypto/rand"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/tyler-smith/go-bip39"
@@ -42,13 +42,18 @@ func MakeEntropy(i int) Entropy {
 // GenerateMnemonic generates a BIP-39 mnemonic phrase with the specified entropy size
 // The entropy size must be a multiple of 32 bits between 128 and 256 bits
 func GenerateMnemonic(entropySize Entropy) (string, error) {
+	return GenerateMnemonicFrom(RandomSource(), entropySize)
+}
+
+// GenerateMnemonicFrom generates a BIP-39 mnemonic phrase drawing its entropy from source
+func GenerateMnemonicFrom(source io.Reader, entropySize Entropy) (string, error) {
 	if entropySize%32 != 0 || entropySize < 128 || entropySize > 256 {
 		return "", fmt.Errorf("entropy size must be a multiple of 32 between 128 and 256 bits")
 	}
 
 	// Generate random entropy
 	entropy := make([]byte, entropySize/8)
-	if _, err := rand.Read(entropy); err != nil {
+	if _, err := io.ReadFull(source, entropy); err != nil {
 		return "", fmt.Errorf("failed to generate entropy: %w", err)
 	}
 
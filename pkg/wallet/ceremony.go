@@ -0,0 +1,332 @@
+package wallet
+
+import (
+	"bytes"
+	"crypto/hkdf"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	// CEREMONY_TRANSCRIPT_VERSION is the format of the transcripts Finish produces
+	CEREMONY_TRANSCRIPT_VERSION = 1
+	// CEREMONY_CONTRIBUTION_SIZE is the entropy every participant contributes, in bytes
+	CEREMONY_CONTRIBUTION_SIZE = 32
+	// MIN_CEREMONY_PARTICIPANTS keeps a single party from generating a ceremony wallet alone
+	MIN_CEREMONY_PARTICIPANTS = 2
+
+	ceremonyInfo = "ULedger key ceremony"
+)
+
+// ErrCeremony is returned when a commitment, a reveal or a transcript does not fit the ceremony
+type ErrCeremony struct {
+	CeremonyId string
+	Msg        string
+}
+
+func (e *ErrCeremony) Error() string {
+	return fmt.Sprintf("key ceremony %s, %s", e.CeremonyId, e.Msg)
+}
+
+func (e *ErrCeremony) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// CeremonyCommitment binds a participant to its contribution before any contribution is revealed,
+// it is signed by the participant's wallet
+type CeremonyCommitment struct {
+	Participant string           `json:"participant"`
+	Commitment  string           `json:"commitment"`
+	Signature   MessageSignature `json:"signature"`
+}
+
+// CeremonyReveal discloses the contribution of a participant. Together the reveals of a ceremony
+// recreate its wallet, they are as sensitive as the mnemonic.
+type CeremonyReveal struct {
+	Participant  string `json:"participant"`
+	Contribution string `json:"contribution"`
+}
+
+// CeremonyTranscript is the public record of a ceremony. It holds no secret: anyone can check who
+// committed with Verify, auditors given the sealed reveals can recreate the wallet with VerifyReveals.
+type CeremonyTranscript struct {
+	Version      int                  `json:"version"`
+	CeremonyId   string               `json:"ceremonyId"`
+	Entropy      Entropy              `json:"entropy"`
+	KeyType      crypto.KeyType       `json:"keyType"`
+	Participants []string             `json:"participants"`
+	Commitments  []CeremonyCommitment `json:"commitments"`
+	Address      string               `json:"address"`
+	PublicKey    string               `json:"publicKey"`
+	CompletedAt  time.Time            `json:"completedAt"`
+}
+
+// CeremonyParticipant is the side of one participant, it draws its contribution from RandomSource
+type CeremonyParticipant struct {
+	ceremonyId   string
+	wallet       *UL_Wallet
+	contribution []byte
+}
+
+// NewCeremonyParticipant draws the contribution of w to the ceremony ceremonyId
+func NewCeremonyParticipant(ceremonyId string, w *UL_Wallet) (*CeremonyParticipant, error) {
+	if err := w.CheckKey(); err != nil {
+		return nil, err
+	}
+	contribution := make([]byte, CEREMONY_CONTRIBUTION_SIZE)
+	if _, err := io.ReadFull(RandomSource(), contribution); err != nil {
+		return nil, fmt.Errorf("failed to generate the contribution: %w", err)
+	}
+	return &CeremonyParticipant{ceremonyId: ceremonyId, wallet: w, contribution: contribution}, nil
+}
+
+// Commit returns the signed commitment to send to the coordinator in the first round
+func (p *CeremonyParticipant) Commit() (CeremonyCommitment, error) {
+	commitment := ceremonyCommitment(p.ceremonyId, p.wallet.Address, p.contribution)
+	signature, err := p.wallet.SignMessage(commitmentStatement(p.ceremonyId, commitment))
+	if err != nil {
+		return CeremonyCommitment{}, err
+	}
+	return CeremonyCommitment{Participant: p.wallet.Address, Commitment: commitment, Signature: signature}, nil
+}
+
+// Reveal returns the contribution to send in the second round, once every participant committed
+func (p *CeremonyParticipant) Reveal() CeremonyReveal {
+	return CeremonyReveal{Participant: p.wallet.Address, Contribution: hex.EncodeToString(p.contribution)}
+}
+
+// Forget wipes the contribution, a participant should not keep it once the ceremony is over
+func (p *CeremonyParticipant) Forget() {
+	clear(p.contribution)
+}
+
+// KeyCeremony is the coordinator of a commit-reveal wallet generation. Every participant commits to
+// a random contribution, then reveals it once all commitments are in, so none can pick its
+// contribution knowing the others. The wallet's entropy is derived from every contribution: it is
+// unpredictable as long as one participant is honest. A participant can still abort by withholding
+// its reveal, the ceremony is then started again under a new id.
+//
+//	ceremony, _ := wallet.NewKeyCeremony("treasury-2025", addresses, wallet.Entropy256)
+//	for _, commitment := range commitments {
+//		err := ceremony.AddCommitment(commitment)
+//	}
+//	for _, reveal := range reveals {
+//		err := ceremony.AddReveal(reveal)
+//	}
+//	treasury, mnemonic, transcript, err := ceremony.Finish("", crypto.KeyTypeSecp256k1)
+type KeyCeremony struct {
+	id           string
+	participants []string
+	entropy      Entropy
+	commitments  map[string]CeremonyCommitment
+	reveals      map[string][]byte
+}
+
+// NewKeyCeremony starts the ceremony ceremonyId of participants, by address. The order of
+// participants is part of the derivation. entropy is the size of the resulting mnemonic.
+func NewKeyCeremony(ceremonyId string, participants []string, entropy Entropy) (*KeyCeremony, error) {
+	if ceremonyId == "" {
+		return nil, &ErrCeremony{Msg: "the ceremony id is empty"}
+	}
+	if len(participants) < MIN_CEREMONY_PARTICIPANTS {
+		return nil, &ErrCeremony{CeremonyId: ceremonyId, Msg: fmt.Sprintf("%d participants, at least %d are needed", len(participants), MIN_CEREMONY_PARTICIPANTS)}
+	}
+	for i, participant := range participants {
+		if slices.Contains(participants[:i], participant) {
+			return nil, &ErrCeremony{CeremonyId: ceremonyId, Msg: fmt.Sprintf("%s participates twice", participant)}
+		}
+	}
+	if entropy%32 != 0 || entropy < Entropy128 || entropy > Entropy256 {
+		return nil, &ErrCeremony{CeremonyId: ceremonyId, Msg: fmt.Sprintf("invalid entropy size %d", entropy)}
+	}
+	return &KeyCeremony{
+		id:           ceremonyId,
+		participants: slices.Clone(participants),
+		entropy:      entropy,
+		commitments:  make(map[string]CeremonyCommitment, len(participants)),
+		reveals:      make(map[string][]byte, len(participants)),
+	}, nil
+}
+
+// AddCommitment records the commitment of a participant after checking its signature
+func (c *KeyCeremony) AddCommitment(commitment CeremonyCommitment) error {
+	if !slices.Contains(c.participants, commitment.Participant) {
+		return c.errorf("%s is not a participant", commitment.Participant)
+	}
+	if _, ok := c.commitments[commitment.Participant]; ok {
+		return c.errorf("%s already committed", commitment.Participant)
+	}
+	if err := verifyCommitment(c.id, commitment); err != nil {
+		return err
+	}
+	c.commitments[commitment.Participant] = commitment
+	return nil
+}
+
+// Committed reports whether every participant committed, reveals are refused until then
+func (c *KeyCeremony) Committed() bool {
+	return len(c.commitments) == len(c.participants)
+}
+
+// AddReveal records the contribution of a participant, which must match its commitment
+func (c *KeyCeremony) AddReveal(reveal CeremonyReveal) error {
+	if !c.Committed() {
+		return c.errorf("%d of %d participants committed, reveals must wait for all", len(c.commitments), len(c.participants))
+	}
+	commitment, ok := c.commitments[reveal.Participant]
+	if !ok {
+		return c.errorf("%s is not a participant", reveal.Participant)
+	}
+	if _, ok := c.reveals[reveal.Participant]; ok {
+		return c.errorf("%s already revealed", reveal.Participant)
+	}
+	contribution, err := hex.DecodeString(reveal.Contribution)
+	if err != nil || len(contribution) != CEREMONY_CONTRIBUTION_SIZE {
+		return c.errorf("the contribution of %s is not %d hex encoded bytes", reveal.Participant, CEREMONY_CONTRIBUTION_SIZE)
+	}
+	expected := ceremonyCommitment(c.id, reveal.Participant, contribution)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(commitment.Commitment)) != 1 {
+		return c.errorf("the contribution of %s does not match its commitment", reveal.Participant)
+	}
+	c.reveals[reveal.Participant] = contribution
+	return nil
+}
+
+// Finish derives the wallet once every participant revealed, returning it with its mnemonic and the
+// transcript of the ceremony. The passphrase is applied to the mnemonic like in GenerateFromMnemonic.
+func (c *KeyCeremony) Finish(passphrase string, keyType crypto.KeyType) (UL_Wallet, string, CeremonyTranscript, error) {
+	if len(c.reveals) != len(c.participants) {
+		return UL_Wallet{}, "", CeremonyTranscript{}, c.errorf("%d of %d participants revealed", len(c.reveals), len(c.participants))
+	}
+	secret := make([]byte, 0, len(c.participants)*CEREMONY_CONTRIBUTION_SIZE)
+	for _, participant := range c.participants {
+		secret = append(secret, c.reveals[participant]...)
+	}
+	defer clear(secret)
+	entropy, err := hkdf.Key(sha512.New, secret, []byte(c.id), ceremonyInfo, int(c.entropy/8))
+	if err != nil {
+		return UL_Wallet{}, "", CeremonyTranscript{}, fmt.Errorf("failed to derive the ceremony entropy: %w", err)
+	}
+	defer clear(entropy)
+
+	mnemonic, err := GenerateMnemonicFrom(bytes.NewReader(entropy), c.entropy)
+	if err != nil {
+		return UL_Wallet{}, "", CeremonyTranscript{}, err
+	}
+	w, err := GenerateFromMnemonic(mnemonic, passphrase, keyType)
+	if err != nil {
+		return UL_Wallet{}, "", CeremonyTranscript{}, err
+	}
+
+	transcript := CeremonyTranscript{
+		Version:      CEREMONY_TRANSCRIPT_VERSION,
+		CeremonyId:   c.id,
+		Entropy:      c.entropy,
+		KeyType:      keyType,
+		Participants: slices.Clone(c.participants),
+		Address:      w.Address,
+		PublicKey:    w.key.GetPublicKeyHex(false),
+		CompletedAt:  time.Now().UTC(),
+	}
+	for _, participant := range c.participants {
+		transcript.Commitments = append(transcript.Commitments, c.commitments[participant])
+	}
+	return w, mnemonic, transcript, nil
+}
+
+func (c *KeyCeremony) errorf(format string, args ...any) error {
+	return &ErrCeremony{CeremonyId: c.id, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Verify checks that every participant signed exactly one commitment and that the public key
+// controls the address. It needs no secret.
+func (t CeremonyTranscript) Verify() error {
+	if t.Version != CEREMONY_TRANSCRIPT_VERSION {
+		return &ErrCeremony{CeremonyId: t.CeremonyId, Msg: fmt.Sprintf("transcript version %d is not %d", t.Version, CEREMONY_TRANSCRIPT_VERSION)}
+	}
+	if _, err := NewKeyCeremony(t.CeremonyId, t.Participants, t.Entropy); err != nil {
+		return err
+	}
+	if len(t.Commitments) != len(t.Participants) {
+		return &ErrCeremony{CeremonyId: t.CeremonyId, Msg: fmt.Sprintf("%d commitments for %d participants", len(t.Commitments), len(t.Participants))}
+	}
+	for i, commitment := range t.Commitments {
+		if commitment.Participant != t.Participants[i] {
+			return &ErrCeremony{CeremonyId: t.CeremonyId, Msg: fmt.Sprintf("commitment %d is of %s, not %s", i, commitment.Participant, t.Participants[i])}
+		}
+		if err := verifyCommitment(t.CeremonyId, commitment); err != nil {
+			return err
+		}
+	}
+	key, err := crypto.GetKeyByType(t.KeyType, crypto.GetHasherByType(t.KeyType))
+	if err != nil {
+		return err
+	}
+	if err := key.GeneratePublicKeyFromHex(false, t.PublicKey); err != nil || !controlsAddress(key, t.Address) {
+		return &ErrCeremony{CeremonyId: t.CeremonyId, Msg: fmt.Sprintf("the public key does not control %s", t.Address)}
+	}
+	return nil
+}
+
+// VerifyReveals replays the ceremony from the sealed reveals of every participant and checks it
+// yields the transcript's wallet, proving the wallet was derived from the committed contributions
+func (t CeremonyTranscript) VerifyReveals(reveals []CeremonyReveal, passphrase string) error {
+	if err := t.Verify(); err != nil {
+		return err
+	}
+	ceremony, err := NewKeyCeremony(t.CeremonyId, t.Participants, t.Entropy)
+	if err != nil {
+		return err
+	}
+	for _, commitment := range t.Commitments {
+		if err := ceremony.AddCommitment(commitment); err != nil {
+			return err
+		}
+	}
+	for _, reveal := range reveals {
+		if err := ceremony.AddReveal(reveal); err != nil {
+			return err
+		}
+	}
+	w, _, _, err := ceremony.Finish(passphrase, t.KeyType)
+	if err != nil {
+		return err
+	}
+	if w.Address != t.Address {
+		return &ErrCeremony{CeremonyId: t.CeremonyId, Msg: fmt.Sprintf("the reveals yield %s, not %s", w.Address, t.Address)}
+	}
+	return nil
+}
+
+// ceremonyCommitment is SHA-256(ceremonyId || 0 || participant || 0 || contribution), binding the
+// contribution to one participant of one ceremony
+func ceremonyCommitment(ceremonyId string, participant string, contribution []byte) string {
+	h := sha256.New()
+	h.Write([]byte(ceremonyId))
+	h.Write([]byte{0})
+	h.Write([]byte(participant))
+	h.Write([]byte{0})
+	h.Write(contribution)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func commitmentStatement(ceremonyId string, commitment string) []byte {
+	return []byte(fmt.Sprintf("%s %s commitment %s", ceremonyInfo, ceremonyId, commitment))
+}
+
+func verifyCommitment(ceremonyId string, commitment CeremonyCommitment) error {
+	ok, err := VerifyMessage(commitment.Participant, commitmentStatement(ceremonyId, commitment.Commitment), commitment.Signature)
+	if err != nil || !ok {
+		return &ErrCeremony{CeremonyId: ceremonyId, Msg: fmt.Sprintf("the commitment of %s is not signed by it", commitment.Participant)}
+	}
+	return nil
+}
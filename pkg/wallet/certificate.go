@@ -0,0 +1,164 @@
+package wallet
+
+import (
+	gocrypto "crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// CertificateBinding ties a wallet to an X.509 certificate chain issued to a legal entity.
+// The certificate key attests the wallet address and the wallet key consents to the certificate,
+// so neither side can claim the other alone. Wallet keys such as secp256k1 are rarely found in
+// certificates, which is why the binding does not require the certificate to hold the wallet key.
+type CertificateBinding struct {
+	// Chain holds base64 DER certificates, leaf first
+	Chain []string `json:"chain"`
+	// Attestation is the leaf key's signature over the binding statement, hex encoded
+	Attestation string `json:"attestation"`
+	// Consent is the wallet's signature over the same statement
+	Consent MessageSignature `json:"consent"`
+}
+
+// bindingStatement is the message both keys sign
+func bindingStatement(address string, leaf *x509.Certificate) []byte {
+	fingerprint := sha256.Sum256(leaf.Raw)
+	return []byte(fmt.Sprintf("ULedger certificate binding\naddress:%s\ncertificate:%s", address, hex.EncodeToString(fingerprint[:])))
+}
+
+// BindCertificate binds the wallet to chain, certificateKey is the private key of the leaf certificate.
+// The binding is stored on the wallet and persisted by SaveToFile.
+func (w *UL_Wallet) BindCertificate(chain []*x509.Certificate, certificateKey gocrypto.Signer) (*CertificateBinding, error) {
+	if len(chain) == 0 {
+		return nil, fmt.Errorf("the certificate chain is empty")
+	}
+	leaf := chain[0]
+	statement := bindingStatement(w.Address, leaf)
+
+	attestation, err := signWithCertificateKey(certificateKey, statement)
+	if err != nil {
+		return nil, err
+	}
+	if err := leaf.CheckSignature(signatureAlgorithm(leaf), statement, attestation); err != nil {
+		return nil, fmt.Errorf("the key does not belong to the leaf certificate: %w", err)
+	}
+	consent, err := w.SignMessage(statement)
+	if err != nil {
+		return nil, err
+	}
+
+	binding := &CertificateBinding{
+		Attestation: hex.EncodeToString(attestation),
+		Consent:     consent,
+	}
+	for _, certificate := range chain {
+		binding.Chain = append(binding.Chain, base64.StdEncoding.EncodeToString(certificate.Raw))
+	}
+	w.Certificate = binding
+	return binding, nil
+}
+
+// Certificates decodes the certificate chain, leaf first
+func (b *CertificateBinding) Certificates() ([]*x509.Certificate, error) {
+	if len(b.Chain) == 0 {
+		return nil, fmt.Errorf("the certificate chain is empty")
+	}
+	chain := make([]*x509.Certificate, 0, len(b.Chain))
+	for i, encoded := range b.Chain {
+		der, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate %d: %w", i, err)
+		}
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate %d: %w", i, err)
+		}
+		chain = append(chain, certificate)
+	}
+	return chain, nil
+}
+
+// VerifyCertificateBinding checks that the chain is valid at the given time against roots, that the
+// leaf key attested address and that the key controlling address consented to the certificate
+func VerifyCertificateBinding(address string, binding *CertificateBinding, roots *x509.CertPool, at time.Time) error {
+	if binding == nil {
+		return fmt.Errorf("the wallet has no certificate binding")
+	}
+	chain, err := binding.Certificates()
+	if err != nil {
+		return err
+	}
+	leaf := chain[0]
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range chain[1:] {
+		intermediates.AddCert(certificate)
+	}
+	_, err = leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return fmt.Errorf("untrusted certificate chain: %w", err)
+	}
+
+	statement := bindingStatement(address, leaf)
+	attestation, err := hex.DecodeString(binding.Attestation)
+	if err != nil {
+		return fmt.Errorf("invalid attestation: %w", err)
+	}
+	if err := leaf.CheckSignature(signatureAlgorithm(leaf), statement, attestation); err != nil {
+		return fmt.Errorf("the certificate does not attest address %s: %w", address, err)
+	}
+
+	ok, err := VerifyMessage(address, statement, binding.Consent)
+	if err != nil {
+		return fmt.Errorf("invalid wallet consent: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("the wallet %s did not consent to the certificate", address)
+	}
+	return nil
+}
+
+// VerifyCertificate checks the wallet's own binding, see VerifyCertificateBinding
+func (w *UL_Wallet) VerifyCertificate(roots *x509.CertPool, at time.Time) error {
+	return VerifyCertificateBinding(w.Address, w.Certificate, roots, at)
+}
+
+func signatureAlgorithm(certificate *x509.Certificate) x509.SignatureAlgorithm {
+	switch certificate.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		return x509.ECDSAWithSHA256
+	case ed25519.PublicKey:
+		return x509.PureEd25519
+	case *rsa.PublicKey:
+		return x509.SHA256WithRSA
+	default:
+		return x509.UnknownSignatureAlgorithm
+	}
+}
+
+func signWithCertificateKey(key gocrypto.Signer, statement []byte) ([]byte, error) {
+	if key == nil {
+		return nil, fmt.Errorf("the certificate key is required")
+	}
+	switch key.Public().(type) {
+	case ed25519.PublicKey:
+		return key.Sign(rand.Reader, statement, gocrypto.Hash(0))
+	case *ecdsa.PublicKey, *rsa.PublicKey:
+		digest := sha256.Sum256(statement)
+		return key.Sign(rand.Reader, digest[:], gocrypto.SHA256)
+	default:
+		return nil, fmt.Errorf("unsupported certificate key type %T", key.Public())
+	}
+}
@@ -0,0 +1,71 @@
+package wallet
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+// ConvertKeyType creates the successor of w with a key of keyType and a fresh mnemonic, which is
+// returned for backup. passphrase protects the seed of the mnemonic, as in GenerateNewWallet. Parent, Enabled and AuthGroups carry over, the address changes with the key
+// and a certificate binding does not carry over as it vouches for the previous key.
+//
+// The successor is never derived from the private key of w: a quantum attacker recovering a
+// secp256k1 key from its public key would then recover the ML-DSA-87 successor as well. Use
+// ConvertKeyTypeFromMnemonic when the mnemonic of w is at hand.
+func ConvertKeyType(w UL_Wallet, passphrase string, keyType crypto.KeyType) (UL_Wallet, string, error) {
+	if err := checkConversion(w, keyType); err != nil {
+		return UL_Wallet{}, "", err
+	}
+	mnemonic, err := GenerateMnemonic(DefaultEntropy)
+	if err != nil {
+		return UL_Wallet{}, "", fmt.Errorf("failed to generate mnemonic: %w", err)
+	}
+	successor, err := GenerateFromMnemonic(mnemonic, passphrase, keyType)
+	if err != nil {
+		return UL_Wallet{}, "", err
+	}
+	return carryOver(w, successor), mnemonic, nil
+}
+
+// ConvertKeyTypeFromMnemonic creates the successor of w with a key of keyType derived from the same
+// mnemonic seed, so the existing backup recovers both keys. It fails unless mnemonic and passphrase
+// derive w itself.
+func ConvertKeyTypeFromMnemonic(w UL_Wallet, mnemonic string, passphrase string, keyType crypto.KeyType) (UL_Wallet, error) {
+	if err := checkConversion(w, keyType); err != nil {
+		return UL_Wallet{}, err
+	}
+	previous, err := GenerateFromMnemonic(mnemonic, passphrase, w.key.GetType())
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	if previous.Address != w.Address {
+		return UL_Wallet{}, fmt.Errorf("the mnemonic does not derive wallet %s", w.Address)
+	}
+	successor, err := GenerateFromMnemonic(mnemonic, passphrase, keyType)
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	return carryOver(w, successor), nil
+}
+
+func checkConversion(w UL_Wallet, keyType crypto.KeyType) error {
+	if err := w.CheckKey(); err != nil {
+		return err
+	}
+	if _, err := crypto.GetKeyByType(keyType, nil); err != nil {
+		return err
+	}
+	if w.key.GetType() == keyType {
+		return fmt.Errorf("wallet %s already uses %s", w.Address, keyType)
+	}
+	return nil
+}
+
+func carryOver(w UL_Wallet, successor UL_Wallet) UL_Wallet {
+	successor.Parent = w.Parent
+	successor.Enabled = w.Enabled
+	successor.AuthGroups = maps.Clone(w.AuthGroups)
+	return successor
+}
@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+var (
+	randomSourceMu sync.RWMutex
+	randomSource   io.Reader = rand.Reader
+)
+
+// RandomSource returns the reader new mnemonics draw their entropy from, crypto/rand unless
+// SetRandomSource replaced it
+func RandomSource() io.Reader {
+	randomSourceMu.RLock()
+	defer randomSourceMu.RUnlock()
+	return randomSource
+}
+
+// SetRandomSource replaces the entropy of every wallet generated without an explicit source,
+// including those the transaction and devnet packages create internally. A nil source restores
+// crypto/rand. It returns a function restoring the previous source, so tests can write
+//
+//	t.Cleanup(wallet.SetRandomSource(wallet.NewDeterministicSource("my-test")))
+//
+// Never install a deterministic source in production, anyone knowing its seed can recreate the keys.
+func SetRandomSource(source io.Reader) (restore func()) {
+	if source == nil {
+		source = rand.Reader
+	}
+	randomSourceMu.Lock()
+	defer randomSourceMu.Unlock()
+	previous := randomSource
+	randomSource = source
+	return func() {
+		randomSourceMu.Lock()
+		defer randomSourceMu.Unlock()
+		randomSource = previous
+	}
+}
+
+// deterministicSource is the endless stream SHA-256(seed || counter) for counter 0, 1, 2...
+type deterministicSource struct {
+	mu      sync.Mutex
+	seed    [sha256.Size]byte
+	counter uint64
+	block   []byte
+}
+
+// NewDeterministicSource returns a reproducible entropy stream for tests and demos, the same seed
+// always yields the same sequence of mnemonics and so the same wallets. It is safe for concurrent
+// use, although concurrent readers make the order in which they receive wallets unpredictable.
+func NewDeterministicSource(seed string) io.Reader {
+	return &deterministicSource{seed: sha256.Sum256([]byte(seed))}
+}
+
+func (s *deterministicSource) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for n < len(p) {
+		if len(s.block) == 0 {
+			input := make([]byte, len(s.seed)+8)
+			copy(input, s.seed[:])
+			binary.BigEndian.PutUint64(input[len(s.seed):], s.counter)
+			block := sha256.Sum256(input)
+			s.block = block[:]
+			s.counter++
+		}
+		copied := copy(p[n:], s.block)
+		s.block = s.block[copied:]
+		n += copied
+	}
+	return n, nil
+}
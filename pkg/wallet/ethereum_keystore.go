@@ -0,0 +1,224 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	// ETHEREUM_KEYSTORE_VERSION is the Web3 Secret Storage version of geth and MetaMask keystores
+	ETHEREUM_KEYSTORE_VERSION = 3
+	// ETHEREUM_KEYSTORE_CIPHER encrypts the private key of Ethereum keystores
+	ETHEREUM_KEYSTORE_CIPHER = "aes-128-ctr"
+	// MAX_PBKDF2_ITERATIONS bounds the cost of the pbkdf2 keystores ImportEthereumKeystore accepts
+	MAX_PBKDF2_ITERATIONS = 1 << 24
+)
+
+// EthereumKeystore is the V3 keystore JSON of geth, MetaMask and most Ethereum wallets. Address is
+// the lowercase Ethereum address without 0x, byte strings are hex encoded.
+type EthereumKeystore struct {
+	Address string               `json:"address,omitempty"`
+	Crypto  EthereumKeystoreData `json:"crypto"`
+	Id      string               `json:"id"`
+	Version int                  `json:"version"`
+}
+
+// EthereumKeystoreData is the encrypted private key of an EthereumKeystore
+type EthereumKeystoreData struct {
+	Cipher       string `json:"cipher"`
+	CipherText   string `json:"ciphertext"`
+	CipherParams struct {
+		IV string `json:"iv"`
+	} `json:"cipherparams"`
+	KDF string `json:"kdf"`
+	// KDFParams are KDFParams for scrypt, pbkdf2Params for pbkdf2
+	KDFParams json.RawMessage `json:"kdfparams"`
+	MAC       string          `json:"mac"`
+}
+
+// pbkdf2Params are the parameters of keystores derived with pbkdf2
+type pbkdf2Params struct {
+	C     int    `json:"c"`
+	DKLen int    `json:"dklen"`
+	PRF   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+// ImportEthereumKeystore creates a secp256k1 wallet from a V3 keystore, derived with scrypt or
+// pbkdf2. A wrong password fails with ErrWrongPassphrase. The wallet has the ULedger address of the
+// key, Parent, Enabled and AuthGroups are left for the caller to fill in.
+func ImportEthereumKeystore(keystoreJson []byte, password string) (UL_Wallet, error) {
+	keystore := EthereumKeystore{}
+	if err := json.Unmarshal(keystoreJson, &keystore); err != nil {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: utils.HandleJsonError(err)}
+	}
+	if keystore.Version != ETHEREUM_KEYSTORE_VERSION {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("unsupported version %d, only version %d is supported", keystore.Version, ETHEREUM_KEYSTORE_VERSION)}
+	}
+	data := keystore.Crypto
+	if data.Cipher != ETHEREUM_KEYSTORE_CIPHER {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("unsupported cipher %q", data.Cipher)}
+	}
+
+	derivedKey, err := ethereumDerivedKey(data, password)
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	ciphertext, err := hex.DecodeString(data.CipherText)
+	if err != nil || len(ciphertext) != 32 {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "the ciphertext must be a hex encoded 32 byte key"}
+	}
+	iv, err := hex.DecodeString(data.CipherParams.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "invalid iv"}
+	}
+	mac, err := hex.DecodeString(data.MAC)
+	if err != nil {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "invalid mac"}
+	}
+	if !hmac.Equal(mac, crypto.Keccak256(derivedKey[16:32], ciphertext)) {
+		return UL_Wallet{}, &ErrWrongPassphrase{Address: keystore.Address}
+	}
+
+	privateKey, err := aesCTR(derivedKey[:16], iv, ciphertext)
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	if !validScalar(privateKey) {
+		return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "the private key is not a valid secp256k1 key"}
+	}
+	w, err := secp256k1Wallet(privateKey)
+	if err != nil {
+		return UL_Wallet{}, fmt.Errorf("failed to import private key: %w", err)
+	}
+
+	// The address is optional, when present it must be the one of the key
+	if keystore.Address != "" {
+		address, err := ParseEthereumAddress(w.key.GetPublicKeyHex(false))
+		if err != nil {
+			return UL_Wallet{}, err
+		}
+		if !strings.EqualFold(strings.TrimPrefix(keystore.Address, "0x"), strings.TrimPrefix(address, "0x")) {
+			return UL_Wallet{}, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("address %s does not match the key of %s", keystore.Address, address)}
+		}
+	}
+	return w, nil
+}
+
+// ExportEthereumKeystore encrypts the key of a secp256k1 wallet into a V3 keystore that geth and
+// MetaMask import with password. The key is derived with scrypt, params default to
+// DEFAULT_SCRYPT_PARAMS and at most one set is accepted.
+func ExportEthereumKeystore(w UL_Wallet, password string, params ...ScryptParams) ([]byte, error) {
+	if err := w.CheckKey(); err != nil {
+		return nil, err
+	}
+	if w.key.GetType() != crypto.KeyTypeSecp256k1 {
+		return nil, &utils.ErrMalformed{What: "wallet", Msg: fmt.Sprintf("Ethereum keystores hold secp256k1 keys, the wallet has a %s key", w.key.GetType())}
+	}
+	privateKey, err := hex.DecodeString(w.key.GetPrivateKeyHex())
+	if err != nil || len(privateKey) != 32 {
+		return nil, &ErrNoKey{Address: w.Address}
+	}
+	cost := DEFAULT_SCRYPT_PARAMS
+	switch len(params) {
+	case 0:
+	case 1:
+		cost = params[0]
+	default:
+		return nil, fmt.Errorf("at most one ScryptParams may be passed, got %d", len(params))
+	}
+
+	kdf := KDFParams{ScryptParams: cost, DKLen: 32, Salt: hex.EncodeToString(randomBytes(32))}
+	derivedKey, err := scryptKey(password, kdf)
+	if err != nil {
+		return nil, err
+	}
+	iv := randomBytes(aes.BlockSize)
+	ciphertext, err := aesCTR(derivedKey[:16], iv, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	address, err := ParseEthereumAddress(w.key.GetPublicKeyHex(false))
+	if err != nil {
+		return nil, err
+	}
+	kdfJson, err := json.Marshal(kdf)
+	if err != nil {
+		return nil, err
+	}
+
+	keystore := EthereumKeystore{
+		Address: strings.ToLower(strings.TrimPrefix(address, "0x")),
+		Crypto: EthereumKeystoreData{
+			Cipher:     ETHEREUM_KEYSTORE_CIPHER,
+			CipherText: hex.EncodeToString(ciphertext),
+			KDF:        KEYSTORE_KDF,
+			KDFParams:  kdfJson,
+			MAC:        hex.EncodeToString(crypto.Keccak256(derivedKey[16:32], ciphertext)),
+		},
+		Id:      newUUID(),
+		Version: ETHEREUM_KEYSTORE_VERSION,
+	}
+	keystore.Crypto.CipherParams.IV = hex.EncodeToString(iv)
+	return json.Marshal(keystore)
+}
+
+// ethereumDerivedKey derives the 32 byte key of a keystore, the first half encrypts and the second
+// half authenticates
+func ethereumDerivedKey(data EthereumKeystoreData, password string) ([]byte, error) {
+	switch data.KDF {
+	case KEYSTORE_KDF:
+		kdf := KDFParams{}
+		if err := json.Unmarshal(data.KDFParams, &kdf); err != nil {
+			return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "invalid scrypt parameters"}
+		}
+		return scryptKey(password, kdf)
+	case "pbkdf2":
+		kdf := pbkdf2Params{}
+		if err := json.Unmarshal(data.KDFParams, &kdf); err != nil {
+			return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "invalid pbkdf2 parameters"}
+		}
+		if kdf.PRF != "hmac-sha256" {
+			return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("unsupported pbkdf2 prf %q", kdf.PRF)}
+		}
+		salt, err := hex.DecodeString(kdf.Salt)
+		if err != nil || len(salt) == 0 {
+			return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: "invalid salt"}
+		}
+		if kdf.DKLen != 32 || kdf.C <= 0 || kdf.C > MAX_PBKDF2_ITERATIONS {
+			return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("unsupported pbkdf2 parameters c=%d dklen=%d", kdf.C, kdf.DKLen)}
+		}
+		return pbkdf2.Key(sha256.New, password, salt, kdf.C, kdf.DKLen)
+	default:
+		return nil, &utils.ErrMalformed{What: "Ethereum keystore", Msg: fmt.Sprintf("unsupported key derivation %q", data.KDF)}
+	}
+}
+
+// aesCTR encrypts or decrypts data, CTR mode is its own inverse
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(out, data)
+	return out, nil
+}
+
+// newUUID returns a random version 4 UUID, the id of exported keystores
+func newUUID() string {
+	id := randomBytes(16)
+	id[6] = id[6]&0x0f | 0x40
+	id[8] = id[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", id[0:4], id[4:6], id[6:8], id[8:10], id[10:])
+}
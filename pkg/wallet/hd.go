@@ -0,0 +1,280 @@
+package wallet
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+const (
+	// HARDENED_OFFSET is added to the index of hardened derivation steps, written 44' or 44h
+	HARDENED_OFFSET uint32 = 0x80000000
+	// BIP44_PURPOSE is the first step of BIP-44 paths
+	BIP44_PURPOSE uint32 = 44
+	// DEFAULT_COIN_TYPE is the SLIP-44 coin type of Ethereum, so secp256k1 wallets derive the same keys
+	// as MetaMask and other Ethereum wallets from one mnemonic
+	DEFAULT_COIN_TYPE uint32 = 60
+	// DEFAULT_GAP_LIMIT is the number of consecutive unused addresses after which BIP-44 discovery
+	// stops scanning an account
+	DEFAULT_GAP_LIMIT = 20
+)
+
+// ErrHDKeyTypeUnsupported is returned for key types without a hierarchical derivation, BIP-32 covers
+// secp256k1 and SLIP-10 covers ed25519
+type ErrHDKeyTypeUnsupported struct {
+	KeyType crypto.KeyType
+}
+
+func (e *ErrHDKeyTypeUnsupported) Error() string {
+	return fmt.Sprintf("hierarchical derivation is not supported for %s keys", e.KeyType)
+}
+
+func (e *ErrHDKeyTypeUnsupported) Is(target error) bool {
+	return target == utils.ErrUnsupported
+}
+
+// DerivationPath is the sequence of child indexes leading from the master key to a derived key
+type DerivationPath []uint32
+
+// ParseDerivationPath reads a path like m/44'/60'/0'/0/5, hardened steps end with ' or h
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	steps := strings.Split(strings.TrimSpace(path), "/")
+	if steps[0] != "m" {
+		return nil, &utils.ErrMalformed{What: "derivation path", Msg: fmt.Sprintf("%q must start with m", path)}
+	}
+	parsed := make(DerivationPath, 0, len(steps)-1)
+	for _, step := range steps[1:] {
+		offset := uint32(0)
+		if trimmed := strings.TrimRight(step, "'hH"); len(trimmed) == len(step)-1 {
+			step, offset = trimmed, HARDENED_OFFSET
+		}
+		index, err := strconv.ParseUint(step, 10, 32)
+		if err != nil || uint32(index) >= HARDENED_OFFSET {
+			return nil, &utils.ErrMalformed{What: "derivation path", Msg: fmt.Sprintf("invalid step %q in %q", step, path)}
+		}
+		parsed = append(parsed, uint32(index)+offset)
+	}
+	return parsed, nil
+}
+
+// BIP44Path returns m/44'/coinType'/account'/change/index
+func BIP44Path(coinType, account, change, index uint32) DerivationPath {
+	return DerivationPath{BIP44_PURPOSE + HARDENED_OFFSET, coinType + HARDENED_OFFSET, account + HARDENED_OFFSET, change, index}
+}
+
+func (p DerivationPath) String() string {
+	var b strings.Builder
+	b.WriteString("m")
+	for _, index := range p {
+		if index >= HARDENED_OFFSET {
+			fmt.Fprintf(&b, "/%d'", index-HARDENED_OFFSET)
+		} else {
+			fmt.Fprintf(&b, "/%d", index)
+		}
+	}
+	return b.String()
+}
+
+// HDWallet derives wallets along BIP-44 paths from a single seed, so one mnemonic backs up every
+// address. secp256k1 keys follow BIP-32, ed25519 keys follow SLIP-10, which only has hardened
+// steps. The derived wallets are not the wallet GenerateFromMnemonic returns for the same mnemonic.
+type HDWallet struct {
+	keyType   crypto.KeyType
+	coinType  uint32
+	key       []byte
+	chainCode []byte
+}
+
+// DerivedWallet is a wallet along with the path it was derived at
+type DerivedWallet struct {
+	Path   DerivationPath
+	Wallet UL_Wallet
+}
+
+// NewHDWallet creates the master key of a mnemonic, passphrase is the BIP-39 passphrase
+func NewHDWallet(mnemonic string, passphrase string, keyType crypto.KeyType) (*HDWallet, error) {
+	if !ValidateMnemonic(mnemonic) {
+		return nil, &utils.ErrMalformed{What: "mnemonic", Msg: "it is not a valid BIP-39 phrase"}
+	}
+	seed, err := MnemonicToSeed(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(seed)
+	return NewHDWalletFromSeed(seed, keyType)
+}
+
+// NewHDWalletFromSeed creates the master key of a seed, e.g. from a SeedProvider
+func NewHDWalletFromSeed(seed []byte, keyType crypto.KeyType) (*HDWallet, error) {
+	if len(seed) < MIN_SEED_SIZE {
+		return nil, fmt.Errorf("seed must be at least %d bytes, got %d", MIN_SEED_SIZE, len(seed))
+	}
+	var domain string
+	switch keyType {
+	case crypto.KeyTypeSecp256k1:
+		domain = "Bitcoin seed"
+	case crypto.KeyTypeED25519:
+		domain = "ed25519 seed"
+	default:
+		return nil, &ErrHDKeyTypeUnsupported{KeyType: keyType}
+	}
+	mac := hmac.New(sha512.New, []byte(domain))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+	if keyType == crypto.KeyTypeSecp256k1 && !validScalar(sum[:32]) {
+		return nil, fmt.Errorf("the seed does not produce a valid master key, use another seed")
+	}
+	return &HDWallet{keyType: keyType, coinType: DEFAULT_COIN_TYPE, key: sum[:32], chainCode: sum[32:]}, nil
+}
+
+func (hd *HDWallet) KeyType() crypto.KeyType {
+	return hd.keyType
+}
+
+// SetCoinType changes the SLIP-44 coin type of the paths of Account and Discover
+func (hd *HDWallet) SetCoinType(coinType uint32) {
+	hd.coinType = coinType % HARDENED_OFFSET
+}
+
+// AccountPath returns the BIP-44 path of an address of account, hardening every step for ed25519
+func (hd *HDWallet) AccountPath(account, index uint32) DerivationPath {
+	path := BIP44Path(hd.coinType, account, 0, index)
+	if hd.keyType == crypto.KeyTypeED25519 {
+		path[3] += HARDENED_OFFSET
+		path[4] += HARDENED_OFFSET
+	}
+	return path
+}
+
+// Account derives the wallet at index of the external chain of account, see AccountPath
+func (hd *HDWallet) Account(account, index uint32) (UL_Wallet, error) {
+	return hd.DerivePath(hd.AccountPath(account, index))
+}
+
+// DeriveChild derives the wallet at a path like m/44'/60'/0'/0/5
+func (hd *HDWallet) DeriveChild(path string) (UL_Wallet, error) {
+	parsed, err := ParseDerivationPath(path)
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	return hd.DerivePath(parsed)
+}
+
+// DerivePath derives the wallet at path
+func (hd *HDWallet) DerivePath(path DerivationPath) (UL_Wallet, error) {
+	key, chainCode := hd.key, hd.chainCode
+	for _, index := range path {
+		var err error
+		if key, chainCode, err = hd.child(key, chainCode, index); err != nil {
+			return UL_Wallet{}, fmt.Errorf("failed to derive %s: %w", path, err)
+		}
+	}
+
+	if hd.keyType == crypto.KeyTypeED25519 {
+		private := ed25519.NewKeyFromSeed(key)
+		return GetWalletFromHex(hex.EncodeToString(private.Public().(ed25519.PublicKey)), hex.EncodeToString(private), crypto.KeyTypeED25519)
+	}
+	return secp256k1Wallet(key)
+}
+
+// child derives one step, CKDpriv of BIP-32 or its SLIP-10 counterpart for ed25519
+func (hd *HDWallet) child(key, chainCode []byte, index uint32) ([]byte, []byte, error) {
+	data := make([]byte, 0, 37)
+	switch {
+	case index >= HARDENED_OFFSET:
+		data = append(append(data, 0), key...)
+	case hd.keyType == crypto.KeyTypeED25519:
+		return nil, nil, &utils.ErrMalformed{What: "derivation path", Msg: fmt.Sprintf("ed25519 keys only derive hardened steps, %d is not hardened", index)}
+	default:
+		parent, err := secp256k1Wallet(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		compressed, err := hex.DecodeString(parent.key.GetPublicKeyHex(true))
+		if err != nil {
+			return nil, nil, err
+		}
+		data = append(data, compressed...)
+	}
+	data = binary.BigEndian.AppendUint32(data, index)
+
+	mac := hmac.New(sha512.New, chainCode)
+	mac.Write(data)
+	sum := mac.Sum(nil)
+	if hd.keyType == crypto.KeyTypeED25519 {
+		return sum[:32], sum[32:], nil
+	}
+
+	// The child key is parse256(IL) + kpar mod n, the 2^-127 chance of an invalid key is an error
+	if !validScalar(sum[:32]) {
+		return nil, nil, fmt.Errorf("index %d produces an invalid key, use the next index", index)
+	}
+	child := new(big.Int).Add(new(big.Int).SetBytes(sum[:32]), new(big.Int).SetBytes(key))
+	child.Mod(child, fr.Modulus())
+	if child.Sign() == 0 {
+		return nil, nil, fmt.Errorf("index %d produces an invalid key, use the next index", index)
+	}
+	return child.FillBytes(make([]byte, 32)), sum[32:], nil
+}
+
+// Discover finds the used addresses of the wallet following BIP-44 account discovery: the external
+// chain of each account is scanned until gapLimit consecutive addresses are unused, and discovery
+// stops at the first account without any used address. used tells whether an address is in use,
+// e.g. registered on a chain. A gapLimit of zero uses DEFAULT_GAP_LIMIT.
+func (hd *HDWallet) Discover(gapLimit int, used func(w UL_Wallet) (bool, error)) ([]DerivedWallet, error) {
+	if gapLimit <= 0 {
+		gapLimit = DEFAULT_GAP_LIMIT
+	}
+	found := []DerivedWallet{}
+	for account := uint32(0); account < HARDENED_OFFSET; account++ {
+		accountUsed := false
+		for index, gap := uint32(0), 0; gap < gapLimit && index < HARDENED_OFFSET; index++ {
+			path := hd.AccountPath(account, index)
+			w, err := hd.DerivePath(path)
+			if err != nil {
+				return found, err
+			}
+			inUse, err := used(w)
+			if err != nil {
+				return found, fmt.Errorf("failed to check %s: %w", path, err)
+			}
+			if !inUse {
+				gap++
+				continue
+			}
+			gap, accountUsed = 0, true
+			found = append(found, DerivedWallet{Path: path, Wallet: w})
+		}
+		if !accountUsed {
+			break
+		}
+	}
+	return found, nil
+}
+
+func secp256k1Wallet(privateKey []byte) (UL_Wallet, error) {
+	key, err := crypto.GetKeyByType(crypto.KeyTypeSecp256k1, crypto.GetHasherByType(crypto.KeyTypeSecp256k1))
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	if err := key.GeneratePrivateKeyFromHex(hex.EncodeToString(privateKey)); err != nil {
+		return UL_Wallet{}, err
+	}
+	return FromKey(key), nil
+}
+
+// validScalar reports whether b is a valid secp256k1 private key, in [1, n)
+func validScalar(b []byte) bool {
+	scalar := new(big.Int).SetBytes(b)
+	return scalar.Sign() > 0 && scalar.Cmp(fr.Modulus()) < 0
+}
@@ -0,0 +1,46 @@
+package wallet
+
+import (
+	"encoding/pem"
+	"fmt"
+	"os"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+// ImportPrivateKey creates a wallet from a PKCS#8 or SEC1 private key, PEM or DER encoded
+func ImportPrivateKey(data []byte) (UL_Wallet, error) {
+	var key crypto.ULKey
+	var err error
+	if block, _ := pem.Decode(data); block != nil {
+		key, err = crypto.ParsePrivateKeyPEM(data)
+	} else {
+		key, err = crypto.ParsePrivateKeyDER(data)
+	}
+	if err != nil {
+		return UL_Wallet{}, fmt.Errorf("failed to import private key: %w", err)
+	}
+
+	return UL_Wallet{
+		Address: ParseAddress(key.GetPublicKeyHex(false)),
+		key:     key,
+	}, nil
+}
+
+// ImportPrivateKeyFile creates a wallet from a .pem, .key or .der file
+func ImportPrivateKeyFile(filePath string) (UL_Wallet, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return UL_Wallet{}, fmt.Errorf("failed to read key file: %w", err)
+	}
+	return ImportPrivateKey(data)
+}
+
+// FromKey creates a wallet signing with key, e.g. a key held by a remote signer or a hardware module.
+// Parent, Enabled and AuthGroups are left for the caller to fill in.
+func FromKey(key crypto.ULKey) UL_Wallet {
+	return UL_Wallet{
+		Address: ParseAddress(key.GetPublicKeyHex(false)),
+		key:     key,
+	}
+}
@@ -0,0 +1,192 @@
+package wallet
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KEYSTORE_CIPHER encrypts the private material of .ukey files
+	KEYSTORE_CIPHER = "aes-256-gcm"
+	// KEYSTORE_KDF derives the key of KEYSTORE_CIPHER from the passphrase
+	KEYSTORE_KDF = "scrypt"
+	// MAX_SCRYPT_N bounds the cost of the files LoadFromFile accepts, 1 GiB of memory with r = 8
+	MAX_SCRYPT_N = 1 << 20
+)
+
+// DEFAULT_SCRYPT_PARAMS cost about 256 MiB of memory and a second per derivation, the parameters
+// of geth's standard keystore
+var DEFAULT_SCRYPT_PARAMS = ScryptParams{N: 1 << 18, R: 8, P: 1}
+
+// ScryptParams are the cost parameters of scrypt, N must be a power of two
+type ScryptParams struct {
+	N int `json:"n"`
+	R int `json:"r"`
+	P int `json:"p"`
+}
+
+// KDFParams are the scrypt parameters a key was derived with
+type KDFParams struct {
+	ScryptParams
+	DKLen int    `json:"dklen"`
+	Salt  string `json:"salt"`
+}
+
+// EncryptedSecrets holds the mnemonic and the private key of a wallet file encrypted with a key
+// derived from a passphrase. Byte strings are hex encoded.
+type EncryptedSecrets struct {
+	Cipher     string    `json:"cipher"`
+	Ciphertext string    `json:"ciphertext"`
+	Nonce      string    `json:"nonce"`
+	KDF        string    `json:"kdf"`
+	KDFParams  KDFParams `json:"kdfparams"`
+}
+
+// walletSecrets is the plaintext of EncryptedSecrets
+type walletSecrets struct {
+	Mnemonic      string `json:"mnemonic,omitempty"`
+	PrivateKeyHex string `json:"privateKeyHex,omitempty"`
+}
+
+// ErrWrongPassphrase is returned when the secrets of a wallet file do not decrypt, either because
+// of the passphrase or because the file was altered
+type ErrWrongPassphrase struct {
+	Address string
+}
+
+func (e *ErrWrongPassphrase) Error() string {
+	return fmt.Sprintf("the passphrase does not decrypt wallet %s or the file was altered", e.Address)
+}
+
+func (e *ErrWrongPassphrase) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Encrypted reports whether the secrets of the wallet data are encrypted
+func (data *WalletData) Encrypted() bool {
+	return data.Crypto != nil
+}
+
+// Encrypt moves the mnemonic and the private key into Crypto, encrypted with AES-256-GCM under a
+// key scrypt derives from passphrase. params default to DEFAULT_SCRYPT_PARAMS, at most one set is
+// accepted. The address is authenticated along with the secrets.
+func (data *WalletData) Encrypt(passphrase string, params ...ScryptParams) error {
+	if data.Encrypted() {
+		return fmt.Errorf("wallet %s is encrypted already", data.Address)
+	}
+	cost := DEFAULT_SCRYPT_PARAMS
+	switch len(params) {
+	case 0:
+	case 1:
+		cost = params[0]
+	default:
+		return fmt.Errorf("at most one ScryptParams may be passed, got %d", len(params))
+	}
+
+	kdf := KDFParams{ScryptParams: cost, DKLen: 32, Salt: hex.EncodeToString(randomBytes(32))}
+	gcm, err := keystoreCipher(passphrase, kdf)
+	if err != nil {
+		return err
+	}
+	plaintext, err := json.Marshal(walletSecrets{Mnemonic: data.Mnemonic, PrivateKeyHex: data.PrivateKeyHex})
+	if err != nil {
+		return err
+	}
+	nonce := randomBytes(gcm.NonceSize())
+	data.Crypto = &EncryptedSecrets{
+		Cipher:     KEYSTORE_CIPHER,
+		Ciphertext: hex.EncodeToString(gcm.Seal(nil, nonce, plaintext, data.additionalData())),
+		Nonce:      hex.EncodeToString(nonce),
+		KDF:        KEYSTORE_KDF,
+		KDFParams:  kdf,
+	}
+	data.Mnemonic, data.PrivateKeyHex = "", ""
+	return nil
+}
+
+// Decrypt restores the mnemonic and the private key of encrypted wallet data, it does nothing to
+// data that is not encrypted. A wrong passphrase fails with ErrWrongPassphrase.
+func (data *WalletData) Decrypt(passphrase string) error {
+	if !data.Encrypted() {
+		return nil
+	}
+	secrets := data.Crypto
+	if secrets.Cipher != KEYSTORE_CIPHER || secrets.KDF != KEYSTORE_KDF {
+		return &utils.ErrMalformed{What: "wallet encryption", Msg: fmt.Sprintf("unsupported cipher %q with key derivation %q", secrets.Cipher, secrets.KDF)}
+	}
+	gcm, err := keystoreCipher(passphrase, secrets.KDFParams)
+	if err != nil {
+		return err
+	}
+	nonce, err := hex.DecodeString(secrets.Nonce)
+	if err != nil || len(nonce) != gcm.NonceSize() {
+		return &utils.ErrMalformed{What: "wallet encryption", Msg: "invalid nonce"}
+	}
+	ciphertext, err := hex.DecodeString(secrets.Ciphertext)
+	if err != nil {
+		return &utils.ErrMalformed{What: "wallet encryption", Msg: "invalid ciphertext"}
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, data.additionalData())
+	if err != nil {
+		return &ErrWrongPassphrase{Address: data.Address}
+	}
+
+	decrypted := walletSecrets{}
+	if err := json.Unmarshal(plaintext, &decrypted); err != nil {
+		return &utils.ErrMalformed{What: "wallet secrets", Msg: utils.HandleJsonError(err)}
+	}
+	data.Mnemonic, data.PrivateKeyHex = decrypted.Mnemonic, decrypted.PrivateKeyHex
+	data.Crypto = nil
+	return nil
+}
+
+// additionalData binds the ciphertext to the wallet it belongs to
+func (data *WalletData) additionalData() []byte {
+	return []byte(strings.ToLower(data.Address))
+}
+
+// keystoreCipher derives the key of a passphrase and returns its AES-256-GCM cipher
+func keystoreCipher(passphrase string, kdf KDFParams) (cipher.AEAD, error) {
+	key, err := scryptKey(passphrase, kdf)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// scryptKey derives the 32 byte key of a passphrase, rejecting parameters too costly to be honest
+func scryptKey(passphrase string, kdf KDFParams) ([]byte, error) {
+	salt, err := hex.DecodeString(kdf.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, &utils.ErrMalformed{What: "wallet encryption", Msg: "invalid salt"}
+	}
+	if kdf.DKLen != 32 || kdf.N <= 1 || kdf.N > MAX_SCRYPT_N || kdf.N&(kdf.N-1) != 0 || kdf.R <= 0 || kdf.P <= 0 || kdf.R*kdf.P >= 1<<30 {
+		return nil, &utils.ErrMalformed{What: "wallet encryption", Msg: fmt.Sprintf("unsupported scrypt parameters n=%d r=%d p=%d dklen=%d", kdf.N, kdf.R, kdf.P, kdf.DKLen)}
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, kdf.N, kdf.R, kdf.P, kdf.DKLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive the wallet key: %w", err)
+	}
+	return key, nil
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
+	}
+	return b
+}
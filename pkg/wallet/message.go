@@ -0,0 +1,114 @@
+package wallet
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	fr_bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// SIGNED_MESSAGE_PREFIX is an ERC-191 prefix with version byte 0x55 ('U'), so a signed message can never be a valid transaction commitment
+const SIGNED_MESSAGE_PREFIX = "\x19ULedger Signed Message:\n"
+
+// MessageSignature carries everything a verifier needs, ULedger addresses are hashes so the public key travels along
+type MessageSignature struct {
+	Address   string         `json:"address"`
+	KeyType   crypto.KeyType `json:"keyType"`
+	PublicKey string         `json:"publicKey"`
+	Signature string         `json:"signature"`
+}
+
+// HashMessage returns keccak256(SIGNED_MESSAGE_PREFIX || len(message) || message)
+func HashMessage(message []byte) []byte {
+	return crypto.Keccak256([]byte(SIGNED_MESSAGE_PREFIX+strconv.Itoa(len(message))), message)
+}
+
+// signingPayload adapts a 32 byte digest to the key, secp256k1 keys hash with MiMC which only
+// accepts canonical BN254 field elements
+func signingPayload(keyType crypto.KeyType, digest []byte) []byte {
+	if keyType != crypto.KeyTypeSecp256k1 {
+		return digest
+	}
+	var element fr_bn254.Element
+	element.SetBytes(digest)
+	b := element.Bytes()
+	return b[:]
+}
+
+func (w *UL_Wallet) signDigest(digest []byte) (MessageSignature, error) {
+	if err := w.CheckKey(); err != nil {
+		return MessageSignature{}, err
+	}
+	signature, err := w.key.SignData(signingPayload(w.key.GetType(), digest))
+	if err != nil {
+		return MessageSignature{}, fmt.Errorf("unable to sign message: %w", err)
+	}
+	return MessageSignature{
+		Address:   w.Address,
+		KeyType:   w.key.GetType(),
+		PublicKey: w.key.GetPublicKeyHex(false),
+		Signature: crypto.BytesToHex(signature),
+	}, nil
+}
+
+// SignMessage signs an arbitrary off-chain message, e.g. a login challenge
+func (w *UL_Wallet) SignMessage(message []byte) (MessageSignature, error) {
+	return w.signDigest(HashMessage(message))
+}
+
+// SignTypedData signs EIP-712 structured data
+func (w *UL_Wallet) SignTypedData(data TypedData) (MessageSignature, error) {
+	digest, err := data.Hash()
+	if err != nil {
+		return MessageSignature{}, err
+	}
+	return w.signDigest(digest)
+}
+
+// VerifyMessage checks that signature was produced over message by the key controlling address.
+// Both the native address and the Ethereum address of a secp256k1 key are accepted.
+func VerifyMessage(address string, message []byte, signature MessageSignature) (bool, error) {
+	return verifyDigest(address, HashMessage(message), signature)
+}
+
+// VerifyTypedData checks a SignTypedData signature against address
+func VerifyTypedData(address string, data TypedData, signature MessageSignature) (bool, error) {
+	digest, err := data.Hash()
+	if err != nil {
+		return false, err
+	}
+	return verifyDigest(address, digest, signature)
+}
+
+func verifyDigest(address string, digest []byte, signature MessageSignature) (bool, error) {
+	key, err := crypto.GetKeyByType(signature.KeyType, crypto.GetHasherByType(signature.KeyType))
+	if err != nil {
+		return false, err
+	}
+	if err := key.GeneratePublicKeyFromHex(false, signature.PublicKey); err != nil {
+		return false, fmt.Errorf("invalid public key: %w", err)
+	}
+	if !controlsAddress(key, address) {
+		return false, nil
+	}
+
+	sig, err := crypto.HexToBytes(signature.Signature)
+	if err != nil {
+		return false, fmt.Errorf("invalid signature: %w", err)
+	}
+	return key.VerifySignature(signingPayload(signature.KeyType, digest), sig)
+}
+
+func controlsAddress(key crypto.ULKey, address string) bool {
+	publicKeyHex := key.GetPublicKeyHex(false)
+	if strings.EqualFold(ParseAddress(publicKeyHex), address) {
+		return true
+	}
+	if key.GetType() != crypto.KeyTypeSecp256k1 {
+		return false
+	}
+	ethereumAddress, err := ParseEthereumAddress(publicKeyHex)
+	return err == nil && strings.EqualFold(ethereumAddress, address)
+}
@@ -0,0 +1,125 @@
+package wallet
+
+import (
+	"crypto/hkdf"
+	"crypto/sha512"
+	"fmt"
+	"io"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+const (
+	// MIN_SEED_SIZE rejects seeds too short to carry 128 bits of entropy
+	MIN_SEED_SIZE = 16
+	// DEFAULT_SEED_SIZE matches the size of a BIP-39 seed
+	DEFAULT_SEED_SIZE = 64
+)
+
+// SeedProvider supplies the root seed of a deterministic wallet. It lets wallets be rooted in an
+// existing key ceremony, e.g. a seed derived inside an HSM or by a corporate KDF, instead of a mnemonic.
+type SeedProvider interface {
+	Seed() ([]byte, error)
+}
+
+// SeedFunc adapts a function to a SeedProvider
+type SeedFunc func() ([]byte, error)
+
+func (f SeedFunc) Seed() ([]byte, error) {
+	return f()
+}
+
+type readerSeedProvider struct {
+	reader io.Reader
+	size   int
+}
+
+// NewReaderSeedProvider reads size bytes of seed from reader on every call, e.g. from an HSM's
+// deterministic output stream. A size of zero reads DEFAULT_SEED_SIZE bytes.
+func NewReaderSeedProvider(reader io.Reader, size int) SeedProvider {
+	if size <= 0 {
+		size = DEFAULT_SEED_SIZE
+	}
+	return &readerSeedProvider{reader: reader, size: size}
+}
+
+func (p *readerSeedProvider) Seed() ([]byte, error) {
+	seed := make([]byte, p.size)
+	if _, err := io.ReadFull(p.reader, seed); err != nil {
+		return nil, fmt.Errorf("unable to read %d seed bytes: %w", p.size, err)
+	}
+	return seed, nil
+}
+
+type hkdfSeedProvider struct {
+	secret []byte
+	salt   []byte
+	info   string
+}
+
+// NewHKDFSeedProvider derives seeds from a master secret with HKDF-SHA512. Info separates the wallets
+// rooted in the same secret, e.g. "payments/2025", so one ceremony can produce many wallets.
+func NewHKDFSeedProvider(secret []byte, salt []byte, info string) SeedProvider {
+	return &hkdfSeedProvider{secret: secret, salt: salt, info: info}
+}
+
+func (p *hkdfSeedProvider) Seed() ([]byte, error) {
+	if len(p.secret) < MIN_SEED_SIZE {
+		return nil, fmt.Errorf("the master secret must be at least %d bytes", MIN_SEED_SIZE)
+	}
+	return hkdf.Key(sha512.New, p.secret, p.salt, p.info, DEFAULT_SEED_SIZE)
+}
+
+// GenerateFromSeed creates a wallet from a raw seed, the same derivation GenerateFromMnemonic uses
+// once the mnemonic is stretched. The seed is not stored, wallets must be saved with their private key.
+func GenerateFromSeed(seed []byte, keyType crypto.KeyType) (UL_Wallet, error) {
+	if len(seed) < MIN_SEED_SIZE {
+		return UL_Wallet{}, fmt.Errorf("seed must be at least %d bytes, got %d", MIN_SEED_SIZE, len(seed))
+	}
+	key, err := crypto.GetKeyByType(keyType, crypto.GetHasherByType(keyType))
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	if err := key.GenerateKeyFromSeed(seed); err != nil {
+		return UL_Wallet{}, err
+	}
+	return UL_Wallet{
+		Address: ParseAddress(key.GetPublicKeyHex(false)),
+		key:     key,
+	}, nil
+}
+
+// GenerateFromSeedProvider creates a wallet from the seed supplied by provider, the seed is wiped afterwards
+func GenerateFromSeedProvider(provider SeedProvider, keyType crypto.KeyType) (UL_Wallet, error) {
+	seed, err := provider.Seed()
+	if err != nil {
+		return UL_Wallet{}, fmt.Errorf("failed to obtain seed: %w", err)
+	}
+	defer clear(seed)
+	return GenerateFromSeed(seed, keyType)
+}
+
+// RegenerateFromSeedProvider derives the key for salt from the provider's seed, following
+// ULKey.RegenerateKeyFromSeed, so keys can be rotated without a new ceremony
+func RegenerateFromSeedProvider(provider SeedProvider, salt []byte, keyType crypto.KeyType) (UL_Wallet, error) {
+	seed, err := provider.Seed()
+	if err != nil {
+		return UL_Wallet{}, fmt.Errorf("failed to obtain seed: %w", err)
+	}
+	defer clear(seed)
+	if len(seed) < MIN_SEED_SIZE {
+		return UL_Wallet{}, fmt.Errorf("seed must be at least %d bytes, got %d", MIN_SEED_SIZE, len(seed))
+	}
+
+	key, err := crypto.GetKeyByType(keyType, crypto.GetHasherByType(keyType))
+	if err != nil {
+		return UL_Wallet{}, err
+	}
+	if err := key.RegenerateKeyFromSeed(seed, salt); err != nil {
+		return UL_Wallet{}, err
+	}
+	return UL_Wallet{
+		Address: ParseAddress(key.GetPublicKeyHex(false)),
+		key:     key,
+	}, nil
+}
@@ -0,0 +1,314 @@
+package wallet
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+// EIP712_DOMAIN_TYPE is the reserved name of the domain struct
+const EIP712_DOMAIN_TYPE = "EIP712Domain"
+
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataDomain separates signatures between applications, only the fields that are set are encoded.
+// BlockchainId is a ULedger extension encoded as a string after chainId.
+type TypedDataDomain struct {
+	Name              string   `json:"name,omitempty"`
+	Version           string   `json:"version,omitempty"`
+	ChainId           *big.Int `json:"chainId,omitempty"`
+	BlockchainId      string   `json:"blockchainId,omitempty"`
+	VerifyingContract string   `json:"verifyingContract,omitempty"`
+	Salt              string   `json:"salt,omitempty"`
+}
+
+// TypedData follows EIP-712, ULedger addresses are 32 bytes and should be typed as string or bytes32
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      TypedDataDomain             `json:"domain"`
+	Message     map[string]any              `json:"message"`
+}
+
+func (d TypedDataDomain) fields() ([]TypedDataField, map[string]any) {
+	var fields []TypedDataField
+	values := map[string]any{}
+	add := func(name, typ string, value any) {
+		fields = append(fields, TypedDataField{Name: name, Type: typ})
+		values[name] = value
+	}
+	if d.Name != "" {
+		add("name", "string", d.Name)
+	}
+	if d.Version != "" {
+		add("version", "string", d.Version)
+	}
+	if d.ChainId != nil {
+		add("chainId", "uint256", d.ChainId)
+	}
+	if d.BlockchainId != "" {
+		add("blockchainId", "string", d.BlockchainId)
+	}
+	if d.VerifyingContract != "" {
+		add("verifyingContract", "address", d.VerifyingContract)
+	}
+	if d.Salt != "" {
+		add("salt", "bytes32", d.Salt)
+	}
+	return fields, values
+}
+
+// DomainSeparator returns the EIP-712 hashStruct of the domain
+func (td *TypedData) DomainSeparator() ([]byte, error) {
+	fields, values := td.Domain.fields()
+	types := map[string][]TypedDataField{EIP712_DOMAIN_TYPE: fields}
+	for name, typ := range td.Types {
+		if name != EIP712_DOMAIN_TYPE {
+			types[name] = typ
+		}
+	}
+	return hashStruct(types, EIP712_DOMAIN_TYPE, values)
+}
+
+// Hash returns the digest that is signed: keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+func (td *TypedData) Hash() ([]byte, error) {
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return nil, fmt.Errorf("primary type %s is not defined", td.PrimaryType)
+	}
+	domainSeparator, err := td.DomainSeparator()
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash domain, %w", err)
+	}
+	messageHash, err := hashStruct(td.Types, td.PrimaryType, td.Message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash message, %w", err)
+	}
+	return crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// encodeType returns e.g. Mail(Person from,Person to,string contents)Person(string name,address wallet)
+func encodeType(types map[string][]TypedDataField, primaryType string) (string, error) {
+	deps := map[string]bool{}
+	if err := findDependencies(types, primaryType, deps); err != nil {
+		return "", err
+	}
+	delete(deps, primaryType)
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var buf strings.Builder
+	for _, name := range append([]string{primaryType}, sorted...) {
+		buf.WriteString(name + "(")
+		for i, field := range types[name] {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(field.Type + " " + field.Name)
+		}
+		buf.WriteString(")")
+	}
+	return buf.String(), nil
+}
+
+func findDependencies(types map[string][]TypedDataField, typ string, found map[string]bool) error {
+	typ = baseType(typ)
+	if found[typ] {
+		return nil
+	}
+	fields, ok := types[typ]
+	if !ok {
+		return nil
+	}
+	found[typ] = true
+	for _, field := range fields {
+		if err := findDependencies(types, field.Type, found); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// baseType strips array suffixes, Person[][2] becomes Person
+func baseType(typ string) string {
+	if i := strings.Index(typ, "["); i >= 0 {
+		return typ[:i]
+	}
+	return typ
+}
+
+func hashStruct(types map[string][]TypedDataField, typ string, data map[string]any) ([]byte, error) {
+	encodedType, err := encodeType(types, typ)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.Write(crypto.Keccak256([]byte(encodedType)))
+	for _, field := range types[typ] {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("missing field %s.%s", typ, field.Name)
+		}
+		encoded, err := encodeValue(types, field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s.%s: %w", typ, field.Name, err)
+		}
+		buf.Write(encoded)
+	}
+	return crypto.Keccak256(buf.Bytes()), nil
+}
+
+func encodeValue(types map[string][]TypedDataField, typ string, value any) ([]byte, error) {
+	if strings.HasSuffix(typ, "]") {
+		items, ok := value.([]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an array for %s, got %T", typ, value)
+		}
+		elemType := typ[:strings.LastIndex(typ, "[")]
+		var buf bytes.Buffer
+		for _, item := range items {
+			encoded, err := encodeValue(types, elemType, item)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(encoded)
+		}
+		return crypto.Keccak256(buf.Bytes()), nil
+	}
+
+	if _, ok := types[typ]; ok {
+		nested, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("expected an object for %s, got %T", typ, value)
+		}
+		return hashStruct(types, typ, nested)
+	}
+
+	switch {
+	case typ == "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string, got %T", value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case typ == "bytes":
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+	case typ == "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool, got %T", value)
+		}
+		word := make([]byte, 32)
+		if b {
+			word[31] = 1
+		}
+		return word, nil
+	case typ == "address":
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != 20 {
+			return nil, fmt.Errorf("expected a 20 byte address, got %d bytes", len(b))
+		}
+		return leftPad32(b), nil
+	case strings.HasPrefix(typ, "bytes"):
+		size, err := strconv.Atoi(strings.TrimPrefix(typ, "bytes"))
+		if err != nil || size < 1 || size > 32 {
+			return nil, fmt.Errorf("unsupported type %s", typ)
+		}
+		b, err := toBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) > size {
+			return nil, fmt.Errorf("expected at most %d bytes, got %d", size, len(b))
+		}
+		word := make([]byte, 32)
+		copy(word, b)
+		return word, nil
+	case strings.HasPrefix(typ, "uint"), strings.HasPrefix(typ, "int"):
+		n, err := toBigInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasPrefix(typ, "uint") && n.Sign() < 0 {
+			return nil, fmt.Errorf("negative value for %s", typ)
+		}
+		// Two's complement over 256 bits
+		if n.Sign() < 0 {
+			n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		if n.BitLen() > 256 {
+			return nil, fmt.Errorf("value overflows %s", typ)
+		}
+		return n.FillBytes(make([]byte, 32)), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+func leftPad32(b []byte) []byte {
+	word := make([]byte, 32)
+	copy(word[32-len(b):], b)
+	return word
+}
+
+func toBytes(value any) ([]byte, error) {
+	switch v := value.(type) {
+	case []byte:
+		return v, nil
+	case string:
+		b, err := hex.DecodeString(strings.TrimPrefix(v, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid hex %q: %w", v, err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("expected hex bytes, got %T", value)
+	}
+}
+
+func toBigInt(value any) (*big.Int, error) {
+	switch v := value.(type) {
+	case *big.Int:
+		return v, nil
+	case int:
+		return big.NewInt(int64(v)), nil
+	case int64:
+		return big.NewInt(v), nil
+	case uint64:
+		return new(big.Int).SetUint64(v), nil
+	case float64:
+		if v != float64(int64(v)) {
+			return nil, fmt.Errorf("expected an integer, got %v", v)
+		}
+		return big.NewInt(int64(v)), nil
+	case json.Number:
+		return toBigInt(v.String())
+	case string:
+		n, ok := new(big.Int).SetString(v, 0)
+		if !ok {
+			return nil, fmt.Errorf("invalid integer %q", v)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("expected an integer, got %T", value)
+	}
+}
@@ -0,0 +1,277 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+)
+
+const (
+	// DEFAULT_VAULT_TTL is how long a KeyVault holds keys after Unlock
+	DEFAULT_VAULT_TTL = 15 * time.Minute
+	// DEFAULT_VAULT_IDLE_TIMEOUT locks a KeyVault whose keys have not signed for that long
+	DEFAULT_VAULT_IDLE_TIMEOUT = 5 * time.Minute
+)
+
+// ErrVaultLocked is returned when a key of a locked KeyVault is asked to sign
+type ErrVaultLocked struct {
+	Address string
+}
+
+func (e *ErrVaultLocked) Error() string {
+	return fmt.Sprintf("key vault is locked, unlock it to sign with %s", e.Address)
+}
+
+func (e *ErrVaultLocked) Is(target error) bool {
+	return target == utils.ErrUnavailable
+}
+
+type VaultConfig struct {
+	// TTL bounds how long keys stay in memory after Unlock, defaults to DEFAULT_VAULT_TTL
+	TTL time.Duration
+	// IdleTimeout locks the vault when no key signed for that long, defaults to DEFAULT_VAULT_IDLE_TIMEOUT
+	IdleTimeout time.Duration
+	// OnLock is told why the vault locked: "manual", "ttl" or "idle"
+	OnLock func(reason string)
+}
+
+// KeyVault holds the private keys of .ukey files in memory only while it is unlocked, so long running
+// daemons do not keep cleartext keys forever. The vault locks itself once TTL has passed since Unlock
+// or when its keys sat idle for IdleTimeout, after which signing fails with ErrVaultLocked until
+// Unlock is called again. Files holding a mnemonic derive their key with the passphrase of Unlock.
+//
+// Wallets returned by Wallet keep working across locks, give them to sessions as usual. Signatures
+// of a vault are serialized. Locking drops the keys, their memory is reclaimed by the garbage collector.
+type KeyVault struct {
+	config VaultConfig
+
+	mu         sync.Mutex
+	files      map[string]string
+	public     map[string]crypto.ULKey
+	keys       map[string]crypto.ULKey
+	unlockedAt time.Time
+	lastUsed   time.Time
+	timer      *time.Timer
+}
+
+func NewKeyVault(config VaultConfig) *KeyVault {
+	if config.TTL <= 0 {
+		config.TTL = DEFAULT_VAULT_TTL
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = DEFAULT_VAULT_IDLE_TIMEOUT
+	}
+	return &KeyVault{config: config, files: make(map[string]string), public: make(map[string]crypto.ULKey)}
+}
+
+// AddFile registers a .ukey file holding a mnemonic or a private key and returns its address. Only the
+// public key is read, the private key is loaded by the next Unlock, which also decrypts encrypted files.
+func (v *KeyVault) AddFile(filePath string) (string, error) {
+	jsonData, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read wallet file: %w", err)
+	}
+	var data WalletData
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		return "", &utils.ErrMalformed{What: "wallet file", Msg: utils.HandleJsonError(err)}
+	}
+	if data.Mnemonic == "" && data.PrivateKeyHex == "" && !data.Encrypted() {
+		return "", fmt.Errorf("%s holds no private key", filePath)
+	}
+	public, err := crypto.GetKeyByType(data.KeyType, crypto.GetHasherByType(data.KeyType))
+	if err != nil {
+		return "", err
+	}
+	if err := public.GeneratePublicKeyFromHex(false, data.PublicKeyHex); err != nil {
+		return "", fmt.Errorf("invalid public key in %s: %w", filePath, err)
+	}
+	address := ParseAddress(public.GetPublicKeyHex(false))
+	if data.Address != "" && data.Address != address {
+		return "", fmt.Errorf("%s records address %s for the key of %s", filePath, data.Address, address)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if existing, ok := v.files[address]; ok && existing != filePath {
+		return "", &ErrAccountConflict{Address: address, Existing: existing, Source: filePath}
+	}
+	v.files[address] = filePath
+	v.public[address] = public
+	return address, nil
+}
+
+// Addresses returns the addresses of the registered files
+func (v *KeyVault) Addresses() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	addresses := make([]string, 0, len(v.files))
+	for address := range v.files {
+		addresses = append(addresses, address)
+	}
+	slices.Sort(addresses)
+	return addresses
+}
+
+// Unlock loads the keys of every registered file. It fails without unlocking anything when a file
+// cannot be loaded or the passphrase derives another key than the one registered.
+func (v *KeyVault) Unlock(passphrase string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	keys := make(map[string]crypto.ULKey, len(v.files))
+	for address, filePath := range v.files {
+		w, err := LoadFromFile(filePath, passphrase)
+		if err != nil {
+			return err
+		}
+		if w.Address != address {
+			return fmt.Errorf("the passphrase does not unlock %s", address)
+		}
+		keys[address] = w.key
+	}
+
+	v.keys = keys
+	v.unlockedAt = time.Now()
+	v.lastUsed = v.unlockedAt
+	v.schedule()
+	return nil
+}
+
+// Lock drops the keys from memory
+func (v *KeyVault) Lock() {
+	v.mu.Lock()
+	locked := v.lockLocked()
+	v.mu.Unlock()
+	if locked && v.config.OnLock != nil {
+		v.config.OnLock("manual")
+	}
+}
+
+func (v *KeyVault) Locked() bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.keys == nil
+}
+
+// Wallet returns the wallet of a registered address, it signs through the vault
+func (v *KeyVault) Wallet(address string) (UL_Wallet, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	public, ok := v.public[address]
+	if !ok {
+		return UL_Wallet{}, fmt.Errorf("no wallet %s in the key vault", address)
+	}
+	return UL_Wallet{Address: address, key: &vaultKey{vault: v, address: address, public: public}}, nil
+}
+
+func (v *KeyVault) sign(address string, data []byte) ([]byte, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	key, ok := v.keys[address]
+	if !ok {
+		return nil, &ErrVaultLocked{Address: address}
+	}
+	v.lastUsed = time.Now()
+	return key.SignData(data)
+}
+
+// lockLocked drops the keys and reports whether the vault was unlocked, v.mu must be held
+func (v *KeyVault) lockLocked() bool {
+	if v.timer != nil {
+		v.timer.Stop()
+		v.timer = nil
+	}
+	locked := v.keys != nil
+	v.keys = nil
+	return locked
+}
+
+// schedule arms the timer for the nearest deadline, v.mu must be held
+func (v *KeyVault) schedule() {
+	if v.timer != nil {
+		v.timer.Stop()
+	}
+	v.timer = time.AfterFunc(time.Until(v.nextDeadline()), v.expire)
+}
+
+func (v *KeyVault) nextDeadline() time.Time {
+	expiry, idle := v.unlockedAt.Add(v.config.TTL), v.lastUsed.Add(v.config.IdleTimeout)
+	if idle.Before(expiry) {
+		return idle
+	}
+	return expiry
+}
+
+// expire locks the vault once a deadline passed, signatures since the timer was armed push the idle
+// deadline back so the timer is armed again instead
+func (v *KeyVault) expire() {
+	v.mu.Lock()
+	if v.keys == nil {
+		v.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	if now.Before(v.nextDeadline()) {
+		v.schedule()
+		v.mu.Unlock()
+		return
+	}
+	reason := "idle"
+	if !now.Before(v.unlockedAt.Add(v.config.TTL)) {
+		reason = "ttl"
+	}
+	v.lockLocked()
+	v.mu.Unlock()
+	if v.config.OnLock != nil {
+		v.config.OnLock(reason)
+	}
+}
+
+// vaultKey is the crypto.ULKey of a vault wallet, public key operations work while the vault is locked
+type vaultKey struct {
+	vault   *KeyVault
+	address string
+	public  crypto.ULKey
+}
+
+func (key *vaultKey) SignData(data []byte) ([]byte, error) {
+	return key.vault.sign(key.address, data)
+}
+
+func (key *vaultKey) VerifySignature(message []byte, signature []byte) (bool, error) {
+	return key.public.VerifySignature(message, signature)
+}
+
+func (key *vaultKey) GetPublicKeyHex(compressed bool) string {
+	return key.public.GetPublicKeyHex(compressed)
+}
+
+// GetPrivateKeyHex is always empty so the key cannot be copied out of the vault
+func (key *vaultKey) GetPrivateKeyHex() string {
+	return ""
+}
+
+func (key *vaultKey) GetType() crypto.KeyType {
+	return key.public.GetType()
+}
+
+func (key *vaultKey) GeneratePublicKeyFromHex(compressed bool, hex string) error {
+	return fmt.Errorf("the key of %s is managed by the key vault", key.address)
+}
+
+func (key *vaultKey) GeneratePrivateKeyFromHex(hex string) error {
+	return fmt.Errorf("the key of %s is managed by the key vault", key.address)
+}
+
+func (key *vaultKey) GenerateKeyFromSeed(seed []byte) error {
+	return fmt.Errorf("the key of %s is managed by the key vault", key.address)
+}
+
+func (key *vaultKey) RegenerateKeyFromSeed(seed []byte, salt []byte) error {
+	return fmt.Errorf("the key of %s is managed by the key vault", key.address)
+}
@@ -0,0 +1,136 @@
+package wallet
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+// Message signed with the stored private key to prove it matches the stored public key
+const verificationMessage = "uledger-wallet-verification"
+
+// WalletVerification is the result of checking a wallet file, a wallet is healthy when Problems is empty
+type WalletVerification struct {
+	Path     string
+	Address  string
+	KeyType  crypto.KeyType
+	Problems []string
+}
+
+func (v *WalletVerification) Valid() bool {
+	return len(v.Problems) == 0
+}
+
+func (v *WalletVerification) addProblem(format string, args ...any) {
+	v.Problems = append(v.Problems, fmt.Sprintf(format, args...))
+}
+
+// Verify checks that a .ukey file is well formed and internally consistent: the address matches the
+// public key, the private key matches the public key and the mnemonic regenerates the same key.
+// An error is only returned when the file cannot be read, every inconsistency is reported as a problem.
+func Verify(filePath string, passphrase string) (WalletVerification, error) {
+	jsonData, err := os.ReadFile(filePath)
+	if err != nil {
+		return WalletVerification{}, fmt.Errorf("failed to read wallet file: %w", err)
+	}
+
+	report := WalletVerification{Path: filePath}
+	if !strings.HasSuffix(filePath, ".ukey") {
+		report.addProblem("file does not have the .ukey extension")
+	}
+	verifyWalletJson(&report, jsonData, passphrase)
+	return report, nil
+}
+
+func verifyWalletJson(report *WalletVerification, jsonData []byte, passphrase string) {
+	// Check the required fields are present before relying on their zero values
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(jsonData, &fields); err != nil {
+		report.addProblem("invalid JSON: %s", err)
+		return
+	}
+	for _, field := range []string{"address", "keyType", "publicKeyHex"} {
+		if _, ok := fields[field]; !ok {
+			report.addProblem("missing field %q", field)
+		}
+	}
+
+	data := WalletData{}
+	if err := json.Unmarshal(jsonData, &data); err != nil {
+		report.addProblem("invalid wallet data: %s", err)
+		return
+	}
+	report.Address = data.Address
+	report.KeyType = data.KeyType
+
+	publicKey, err := crypto.GetKeyByType(data.KeyType, crypto.GetHasherByType(data.KeyType))
+	if err != nil {
+		report.addProblem("unsupported key type: %s", err)
+		return
+	}
+	if err := publicKey.GeneratePublicKeyFromHex(false, data.PublicKeyHex); err != nil {
+		report.addProblem("invalid public key: %s", err)
+		return
+	}
+
+	if derived := ParseAddress(publicKey.GetPublicKeyHex(false)); !strings.EqualFold(derived, data.Address) {
+		report.addProblem("address %s does not match the public key, expected %s", data.Address, derived)
+	}
+
+	if data.Encrypted() {
+		if err := data.Decrypt(passphrase); err != nil {
+			report.addProblem("unable to decrypt the secrets: %s", err)
+			return
+		}
+	}
+
+	if data.PrivateKeyHex != "" {
+		verifyPrivateKey(report, data, publicKey)
+	}
+
+	if data.Mnemonic != "" {
+		if !ValidateMnemonic(data.Mnemonic) {
+			report.addProblem("mnemonic is not a valid BIP-39 phrase")
+			return
+		}
+		derived, err := GenerateFromMnemonic(data.Mnemonic, passphrase, data.KeyType)
+		if err != nil {
+			report.addProblem("unable to regenerate key from mnemonic: %s", err)
+			return
+		}
+		if !strings.EqualFold(derived.GetKey().GetPublicKeyHex(false), publicKey.GetPublicKeyHex(false)) {
+			report.addProblem("mnemonic does not regenerate the stored public key, check the passphrase")
+		}
+	}
+}
+
+// verifyPrivateKey signs with the stored private key and verifies with the stored public key
+func verifyPrivateKey(report *WalletVerification, data WalletData, publicKey crypto.ULKey) {
+	privateKey, err := crypto.GetKeyByType(data.KeyType, crypto.GetHasherByType(data.KeyType))
+	if err != nil {
+		report.addProblem("unsupported key type: %s", err)
+		return
+	}
+	// Some key types rebuild the private key on top of the public key
+	if err := privateKey.GeneratePublicKeyFromHex(false, data.PublicKeyHex); err != nil {
+		report.addProblem("invalid public key: %s", err)
+		return
+	}
+	if err := privateKey.GeneratePrivateKeyFromHex(data.PrivateKeyHex); err != nil {
+		report.addProblem("invalid private key: %s", err)
+		return
+	}
+
+	signature, err := privateKey.SignData([]byte(verificationMessage))
+	if err != nil {
+		report.addProblem("unable to sign with the private key: %s", err)
+		return
+	}
+	valid, err := publicKey.VerifySignature([]byte(verificationMessage), signature)
+	if err != nil || !valid {
+		report.addProblem("private key does not match the public key")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -17,7 +18,9 @@ type UL_Wallet struct {
 	Enabled    bool                         `json:"enabled"`
 	Parent     string                       `json:"parent"`
 	AuthGroups map[string]UL_AuthPermission `json:"authGroups"`
-	key        crypto.ULKey                 `json:"-"`
+	// Certificate optionally binds the wallet to an X.509 identity, see BindCertificate
+	Certificate *CertificateBinding `json:"certificate,omitempty"`
+	key         crypto.ULKey        `json:"-"`
 }
 
 type UL_AuthPermission struct {
@@ -37,6 +40,9 @@ type WalletData struct {
 	KeyType       crypto.KeyType               `json:"keyType"`
 	PublicKeyHex  string                       `json:"publicKeyHex"`
 	PrivateKeyHex string                       `json:"privateKeyHex"`
+	Certificate   *CertificateBinding          `json:"certificate,omitempty"`
+	// Crypto holds Mnemonic and PrivateKeyHex encrypted, which are then empty, see Encrypt
+	Crypto *EncryptedSecrets `json:"crypto,omitempty"`
 }
 
 // These are default known auth group names for common operations
@@ -44,22 +50,50 @@ const (
 	WALLET_GROUP_NAME = "wallet"
 )
 
+// ErrNoKey is returned when a wallet without a key, such as a zero UL_Wallet, is asked to sign
+type ErrNoKey struct {
+	Address string
+}
+
+func (e *ErrNoKey) Error() string {
+	if e.Address == "" {
+		return "wallet has no key"
+	}
+	return fmt.Sprintf("wallet %s has no key", e.Address)
+}
+
+func (e *ErrNoKey) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
 func (w *UL_Wallet) GetKey() crypto.ULKey {
 	return w.key
 }
 
+// CheckKey fails with ErrNoKey when the wallet was not created or loaded with a key
+func (w *UL_Wallet) CheckKey() error {
+	if w.key == nil {
+		return &ErrNoKey{Address: w.Address}
+	}
+	return nil
+}
+
 func FromJson(data string, passphrase string) (*UL_Wallet, error) {
 	wd := &WalletData{}
 	err := json.Unmarshal([]byte(data), wd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal wallet JSON: %s", utils.HandleJsonError(err))
+		return nil, &utils.ErrMalformed{What: "wallet JSON", Msg: utils.HandleJsonError(err)}
+	}
+	if err := wd.Decrypt(passphrase); err != nil {
+		return nil, err
 	}
 
 	wallet := UL_Wallet{
-		Address:    wd.Address,
-		Parent:     wd.Parent,
-		Enabled:    wd.Enabled,
-		AuthGroups: wd.AuthGroups,
+		Address:     wd.Address,
+		Parent:      wd.Parent,
+		Enabled:     wd.Enabled,
+		AuthGroups:  wd.AuthGroups,
+		Certificate: wd.Certificate,
 	}
 
 	wallet.key, err = crypto.GetKeyByType(wd.KeyType, crypto.GetHasherByType(wd.KeyType))
@@ -125,33 +159,20 @@ func GenerateFromMnemonic(mnemonic string, passphrase string, keyType crypto.Key
 		return UL_Wallet{}, fmt.Errorf("failed to convert mnemonic to seed: %w", err)
 	}
 
-	// Generate key from seed
-	hasher := crypto.GetHasherByType(keyType)
-	key, err := crypto.GetKeyByType(keyType, hasher)
-	if err != nil {
-		return UL_Wallet{}, err
-	}
-
-	// Generate key pair
-	err = key.GenerateKeyFromSeed(seed)
-	if err != nil {
-		return UL_Wallet{}, err
-	}
-
 	// Create wallet, this is incomplete as the parent, enabled, and auth fields are populated later
-	address := ParseAddress(key.GetPublicKeyHex(false))
-	wallet := UL_Wallet{
-		Address: address,
-		key:     key,
-	}
-
-	return wallet, nil
+	return GenerateFromSeed(seed, keyType)
 }
 
 // GenerateNewWallet creates a new wallet with a random mnemonic phrase
 func GenerateNewWallet(passphrase string, keyType crypto.KeyType, parent string, authGroups map[string]UL_AuthPermission, entropy Entropy) (UL_Wallet, string, error) {
+	return GenerateNewWalletFrom(RandomSource(), passphrase, keyType, parent, authGroups, entropy)
+}
+
+// GenerateNewWalletFrom creates a new wallet whose mnemonic draws its entropy from source, see
+// NewDeterministicSource for reproducible wallets
+func GenerateNewWalletFrom(source io.Reader, passphrase string, keyType crypto.KeyType, parent string, authGroups map[string]UL_AuthPermission, entropy Entropy) (UL_Wallet, string, error) {
 	// Generate new mnemonic
-	mnemonic, err := GenerateMnemonic(entropy)
+	mnemonic, err := GenerateMnemonicFrom(source, entropy)
 	if err != nil {
 		return UL_Wallet{}, "", fmt.Errorf("failed to generate mnemonic: %w", err)
 	}
@@ -168,14 +189,37 @@ func GenerateNewWallet(passphrase string, keyType crypto.KeyType, parent string,
 	return wallet, mnemonic, nil
 }
 
-// SaveToFile saves the wallet data to a file with .ukey extension
+// SaveToFile saves the wallet data to a file with .ukey extension with the mnemonic and the private
+// key in plaintext. Wallet files are meant to be saved with SaveToFileEncrypted, plaintext only suits
+// files holding neither or kept on storage that is encrypted already.
 func (w *UL_Wallet) SaveToFile(filePath string, mnemonic string, includePrivateKey bool) error {
-	// Ensure file has .ukey extension
-	if !strings.HasSuffix(filePath, ".ukey") {
-		filePath += ".ukey"
+	data, err := w.walletData(mnemonic, includePrivateKey)
+	if err != nil {
+		return err
 	}
+	return saveWalletData(filePath, data)
+}
 
-	// Create wallet data
+// SaveToFileEncrypted saves the wallet data to a file with .ukey extension, with the mnemonic and the
+// private key encrypted under passphrase, see WalletData.Encrypt. It is the default way to save a
+// wallet. LoadFromFile decrypts them with the same passphrase, which also protects the seed of the
+// mnemonic, so the mnemonic must have been generated with it.
+func (w *UL_Wallet) SaveToFileEncrypted(filePath string, mnemonic string, includePrivateKey bool, passphrase string, params ...ScryptParams) error {
+	data, err := w.walletData(mnemonic, includePrivateKey)
+	if err != nil {
+		return err
+	}
+	if err := data.Encrypt(passphrase, params...); err != nil {
+		return err
+	}
+	return saveWalletData(filePath, data)
+}
+
+// walletData returns what SaveToFile persists of the wallet
+func (w *UL_Wallet) walletData(mnemonic string, includePrivateKey bool) (WalletData, error) {
+	if err := w.CheckKey(); err != nil {
+		return WalletData{}, err
+	}
 	data := WalletData{
 		Address:      w.Address,
 		Parent:       w.Parent,
@@ -184,12 +228,22 @@ func (w *UL_Wallet) SaveToFile(filePath string, mnemonic string, includePrivateK
 		Mnemonic:     mnemonic,
 		PublicKeyHex: w.key.GetPublicKeyHex(false),
 		AuthGroups:   w.AuthGroups,
+		Certificate:  w.Certificate,
 	}
 
 	// Only include private key if explicitly requested
 	if includePrivateKey {
 		data.PrivateKeyHex = w.key.GetPrivateKeyHex()
 	}
+	return data, nil
+}
+
+// saveWalletData writes data as JSON to filePath, adding the .ukey extension when missing
+func saveWalletData(filePath string, data WalletData) error {
+	// Ensure file has .ukey extension
+	if !strings.HasSuffix(filePath, ".ukey") {
+		filePath += ".ukey"
+	}
 
 	// Convert to JSON
 	jsonData, err := json.MarshalIndent(data, "", "  ")
@@ -205,7 +259,8 @@ func (w *UL_Wallet) SaveToFile(filePath string, mnemonic string, includePrivateK
 	return nil
 }
 
-// LoadFromFile loads a wallet from a .ukey file
+// LoadFromFile loads a wallet from a .ukey file, decrypting its secrets with passphrase when they
+// are encrypted. passphrase also protects the seed of a mnemonic.
 func LoadFromFile(filePath string, passphrase string) (UL_Wallet, error) {
 	// Read file
 	jsonData, err := os.ReadFile(filePath)
@@ -216,12 +271,17 @@ func LoadFromFile(filePath string, passphrase string) (UL_Wallet, error) {
 	// Parse JSON
 	var data WalletData
 	if err := json.Unmarshal(jsonData, &data); err != nil {
-		return UL_Wallet{}, fmt.Errorf("failed to parse wallet data: %w", err)
+		return UL_Wallet{}, &utils.ErrMalformed{What: "wallet file", Msg: utils.HandleJsonError(err)}
+	}
+	if err := data.Decrypt(passphrase); err != nil {
+		return UL_Wallet{}, err
 	}
 
 	// If mnemonic is present, use it to generate the wallet
 	if data.Mnemonic != "" {
-		return GenerateFromMnemonic(data.Mnemonic, passphrase, data.KeyType)
+		wallet, err := GenerateFromMnemonic(data.Mnemonic, passphrase, data.KeyType)
+		wallet.Certificate = data.Certificate
+		return wallet, err
 	}
 
 	// If private key is present, use it to generate the wallet
@@ -244,8 +304,9 @@ func LoadFromFile(filePath string, passphrase string) (UL_Wallet, error) {
 
 		// Create wallet
 		wallet := UL_Wallet{
-			Address: data.Address,
-			key:     key,
+			Address:     data.Address,
+			Certificate: data.Certificate,
+			key:         key,
 		}
 
 		return wallet, nil
@@ -265,8 +326,9 @@ func LoadFromFile(filePath string, passphrase string) (UL_Wallet, error) {
 
 	// Create wallet
 	wallet := UL_Wallet{
-		Address: data.Address,
-		key:     key,
+		Address:     data.Address,
+		Certificate: data.Certificate,
+		key:         key,
 	}
 
 	return wallet, nil
@@ -0,0 +1,343 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// DEFAULT_WATCH_INTERVAL is how often a polling DirectoryWatcher rescans its directory
+	DEFAULT_WATCH_INTERVAL = 2 * time.Second
+	// WATCH_SETTLE_DELAY is how long a notified file must stay unchanged before it is loaded, so that
+	// a file being written is read once complete
+	WATCH_SETTLE_DELAY = 100 * time.Millisecond
+)
+
+type WatchEventType string
+
+const (
+	WATCH_EVENT_LOADED   WatchEventType = "loaded"
+	WATCH_EVENT_RELOADED WatchEventType = "reloaded"
+	WATCH_EVENT_EVICTED  WatchEventType = "evicted"
+	// WATCH_EVENT_INVALID reports a file that failed validation, a previously loaded version stays in use
+	WATCH_EVENT_INVALID WatchEventType = "invalid"
+	// WATCH_EVENT_CONFLICT reports a file holding an address already loaded from another file
+	WATCH_EVENT_CONFLICT WatchEventType = "conflict"
+)
+
+type WatchEvent struct {
+	Type    WatchEventType
+	Path    string
+	Address string
+	Err     error
+}
+
+type WatchOptions struct {
+	// Interval between scans when polling, defaults to DEFAULT_WATCH_INTERVAL
+	Interval time.Duration
+	// Poll rescans the directory every Interval instead of waiting for change notifications, for
+	// network file systems that do not deliver them
+	Poll bool
+	// Passphrase returns the mnemonic passphrase of a file, nil means no passphrase
+	Passphrase func(path string) string
+	// OnEvent is told about every change Run applies
+	OnEvent func(WatchEvent)
+}
+
+// watchedFile is what a scan remembers of a file to notice changes
+type watchedFile struct {
+	modTime time.Time
+	size    int64
+	// address is empty while the file has never loaded successfully
+	address string
+}
+
+// DirectoryWatcher keeps an AccountManager in sync with the .ukey files of a directory: new files are
+// loaded, changed files reloaded and deleted files evicted. Files are validated with Verify before
+// they replace anything. Run follows the change notifications of the directory and falls back to
+// polling it where they are unavailable.
+type DirectoryWatcher struct {
+	dir     string
+	manager *AccountManager
+	opts    WatchOptions
+	files   map[string]watchedFile
+}
+
+func NewDirectoryWatcher(dir string, manager *AccountManager, opts WatchOptions) *DirectoryWatcher {
+	if opts.Interval <= 0 {
+		opts.Interval = DEFAULT_WATCH_INTERVAL
+	}
+	return &DirectoryWatcher{dir: dir, manager: manager, opts: opts, files: make(map[string]watchedFile)}
+}
+
+// Scan applies the changes since the previous scan and returns them, it is not safe to call
+// concurrently with itself or Run
+func (w *DirectoryWatcher) Scan() ([]WatchEvent, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wallet directory: %w", err)
+	}
+
+	events := []WatchEvent{}
+	present := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || !isWalletFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(w.dir, entry.Name())
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			// Deleted while scanning, the next scan evicts it
+			continue
+		}
+		if err != nil {
+			events = append(events, WatchEvent{Type: WATCH_EVENT_INVALID, Path: path, Err: err})
+			continue
+		}
+		present[path] = true
+		if event, ok := w.update(path, info); ok {
+			events = append(events, event)
+		}
+	}
+
+	// Deleted files, in path order so the events are stable
+	removed := []string{}
+	for path := range w.files {
+		if !present[path] {
+			removed = append(removed, path)
+		}
+	}
+	slices.Sort(removed)
+	evicted := false
+	for _, path := range removed {
+		if event, ok := w.evict(path); ok {
+			events = append(events, event)
+			evicted = true
+		}
+	}
+	if evicted {
+		// Files that conflicted with an evicted account may load now, retry them on the next scan
+		w.forgetUnloaded()
+	}
+	return events, nil
+}
+
+// scanFiles applies the changes of the given files only, as reported by change notifications
+func (w *DirectoryWatcher) scanFiles(paths []string) []WatchEvent {
+	slices.Sort(paths)
+	events := []WatchEvent{}
+	evicted := false
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) || err == nil && info.IsDir() {
+			if event, ok := w.evict(path); ok {
+				events = append(events, event)
+				evicted = true
+			}
+			continue
+		}
+		if err != nil {
+			events = append(events, WatchEvent{Type: WATCH_EVENT_INVALID, Path: path, Err: err})
+			continue
+		}
+		if event, ok := w.update(path, info); ok {
+			events = append(events, event)
+		}
+	}
+	if evicted {
+		// No notification follows for the files that conflicted with an evicted account, retry them now
+		retry := []string{}
+		for path, state := range w.files {
+			if state.address == "" {
+				retry = append(retry, path)
+			}
+		}
+		w.forgetUnloaded()
+		if len(retry) > 0 {
+			events = append(events, w.scanFiles(retry)...)
+		}
+	}
+	return events
+}
+
+// update loads a file found with info if it is new or changed since it was last seen
+func (w *DirectoryWatcher) update(path string, info fs.FileInfo) (WatchEvent, bool) {
+	previous, known := w.files[path]
+	if known && previous.modTime.Equal(info.ModTime()) && previous.size == info.Size() {
+		return WatchEvent{}, false
+	}
+	state := watchedFile{modTime: info.ModTime(), size: info.Size(), address: previous.address}
+	event := w.load(path, previous.address)
+	if event.Type == WATCH_EVENT_LOADED || event.Type == WATCH_EVENT_RELOADED {
+		state.address = event.Address
+	}
+	w.files[path] = state
+	return event, true
+}
+
+// evict forgets a deleted file and removes the account it loaded
+func (w *DirectoryWatcher) evict(path string) (WatchEvent, bool) {
+	state, known := w.files[path]
+	if !known {
+		return WatchEvent{}, false
+	}
+	delete(w.files, path)
+	if state.address == "" || !w.manager.Remove(state.address) {
+		return WatchEvent{}, false
+	}
+	return WatchEvent{Type: WATCH_EVENT_EVICTED, Path: path, Address: state.address}, true
+}
+
+// forgetUnloaded drops the files that never loaded so the next look at them retries
+func (w *DirectoryWatcher) forgetUnloaded() {
+	for path, state := range w.files {
+		if state.address == "" {
+			delete(w.files, path)
+		}
+	}
+}
+
+func isWalletFile(name string) bool {
+	return strings.HasSuffix(name, ".ukey")
+}
+
+// load validates and loads a new or changed file, loaded is the address the file held before
+func (w *DirectoryWatcher) load(path string, loaded string) WatchEvent {
+	passphrase := ""
+	if w.opts.Passphrase != nil {
+		passphrase = w.opts.Passphrase(path)
+	}
+	report, err := Verify(path, passphrase)
+	if err != nil {
+		return WatchEvent{Type: WATCH_EVENT_INVALID, Path: path, Address: loaded, Err: err}
+	}
+	if !report.Valid() {
+		return WatchEvent{Type: WATCH_EVENT_INVALID, Path: path, Address: loaded, Err: fmt.Errorf("%s", strings.Join(report.Problems, "; "))}
+	}
+	account, err := LoadFromFile(path, passphrase)
+	if err != nil {
+		return WatchEvent{Type: WATCH_EVENT_INVALID, Path: path, Address: loaded, Err: err}
+	}
+
+	if err := w.manager.Add(account, path); err != nil {
+		return WatchEvent{Type: WATCH_EVENT_CONFLICT, Path: path, Address: account.Address, Err: err}
+	}
+	if loaded == "" {
+		return WatchEvent{Type: WATCH_EVENT_LOADED, Path: path, Address: account.Address}
+	}
+	if loaded != account.Address {
+		// The file now holds another key, the old one is no longer backed by a file
+		w.manager.Remove(loaded)
+	}
+	return WatchEvent{Type: WATCH_EVENT_RELOADED, Path: path, Address: account.Address}
+}
+
+// Run keeps the manager in sync until ctx is done, reporting each change to OnEvent. Changed files
+// are loaded once notified and settled for WATCH_SETTLE_DELAY. The directory is polled every
+// Interval instead when Poll is set or the platform cannot watch it.
+func (w *DirectoryWatcher) Run(ctx context.Context) error {
+	if !w.opts.Poll {
+		notifier, err := fsnotify.NewWatcher()
+		if err == nil {
+			defer notifier.Close()
+			// Watch before the first scan so that no change falls in between
+			if err := notifier.Add(w.dir); err == nil {
+				return w.runNotified(ctx, notifier)
+			}
+		}
+	}
+	return w.poll(ctx)
+}
+
+func (w *DirectoryWatcher) report(events []WatchEvent) {
+	if w.opts.OnEvent == nil {
+		return
+	}
+	for _, event := range events {
+		w.opts.OnEvent(event)
+	}
+}
+
+// poll scans the directory every interval
+func (w *DirectoryWatcher) poll(ctx context.Context) error {
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+	for {
+		events, err := w.Scan()
+		if err != nil {
+			return err
+		}
+		w.report(events)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// runNotified scans the directory once, then only the files it is notified about
+func (w *DirectoryWatcher) runNotified(ctx context.Context, notifier *fsnotify.Watcher) error {
+	rescan := func() error {
+		events, err := w.Scan()
+		if err != nil {
+			return err
+		}
+		w.report(events)
+		return nil
+	}
+	if err := rescan(); err != nil {
+		return err
+	}
+
+	settle := time.NewTimer(WATCH_SETTLE_DELAY)
+	settle.Stop()
+	pending := make(map[string]bool)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event, ok := <-notifier.Events:
+			if !ok {
+				return w.poll(ctx)
+			}
+			if filepath.Clean(event.Name) == filepath.Clean(w.dir) {
+				// The directory itself was removed or renamed, the scan reports it
+				if err := rescan(); err != nil {
+					return err
+				}
+				continue
+			}
+			if !isWalletFile(event.Name) {
+				continue
+			}
+			pending[event.Name] = true
+			settle.Reset(WATCH_SETTLE_DELAY)
+		case _, ok := <-notifier.Errors:
+			if !ok {
+				return w.poll(ctx)
+			}
+			// Notifications were lost, e.g. the queue overflowed, only a full scan catches up
+			clear(pending)
+			if err := rescan(); err != nil {
+				return err
+			}
+		case <-settle.C:
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			clear(pending)
+			w.report(w.scanFiles(paths))
+		}
+	}
+}
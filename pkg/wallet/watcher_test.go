@@ -0,0 +1,184 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+)
+
+func writeWalletFile(t *testing.T, path string, w UL_Wallet, modTime time.Time) {
+	t.Helper()
+	if err := w.SaveToFile(path, "", true); err != nil {
+		t.Fatalf("SaveToFile() error = %v", err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+func scanTypes(t *testing.T, watcher *DirectoryWatcher) []WatchEventType {
+	t.Helper()
+	events, err := watcher.Scan()
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	types := make([]WatchEventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+func TestDirectoryWatcher(t *testing.T) {
+	dir := t.TempDir()
+	wallets := make([]UL_Wallet, 3)
+	for i := range wallets {
+		w, _, err := GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		wallets[i] = w
+	}
+	start := time.Now().Add(-time.Hour)
+	a := filepath.Join(dir, "a.ukey")
+	b := filepath.Join(dir, "b.ukey")
+	writeWalletFile(t, a, wallets[0], start)
+	writeWalletFile(t, b, wallets[1], start)
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewAccountManager()
+	watcher := NewDirectoryWatcher(dir, manager, WatchOptions{})
+	if got := scanTypes(t, watcher); len(got) != 2 || got[0] != WATCH_EVENT_LOADED || got[1] != WATCH_EVENT_LOADED {
+		t.Fatalf("first scan = %v, want two loads", got)
+	}
+	if got := scanTypes(t, watcher); len(got) != 0 {
+		t.Fatalf("unchanged scan = %v, want nothing", got)
+	}
+
+	// A copy of a loaded key is a conflict and does not replace the original
+	duplicate := filepath.Join(dir, "c.ukey")
+	writeWalletFile(t, duplicate, wallets[0], start)
+	if got := scanTypes(t, watcher); len(got) != 1 || got[0] != WATCH_EVENT_CONFLICT {
+		t.Fatalf("scan = %v, want a conflict", got)
+	}
+
+	// A broken write keeps the loaded version
+	if err := os.WriteFile(b, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := scanTypes(t, watcher); len(got) != 1 || got[0] != WATCH_EVENT_INVALID {
+		t.Fatalf("scan = %v, want an invalid file", got)
+	}
+	if _, ok := manager.Get(wallets[1].Address); !ok {
+		t.Fatal("an invalid file evicted the loaded wallet")
+	}
+
+	// The file is replaced with another key
+	writeWalletFile(t, b, wallets[2], start.Add(time.Minute))
+	if got := scanTypes(t, watcher); len(got) != 1 || got[0] != WATCH_EVENT_RELOADED {
+		t.Fatalf("scan = %v, want a reload", got)
+	}
+	if _, ok := manager.Get(wallets[1].Address); ok {
+		t.Fatal("the replaced key is still loaded")
+	}
+
+	// Deleting the original lets the conflicting copy take over on the next scan
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	if got := scanTypes(t, watcher); len(got) != 1 || got[0] != WATCH_EVENT_EVICTED {
+		t.Fatalf("scan = %v, want an eviction", got)
+	}
+	if got := scanTypes(t, watcher); len(got) != 1 || got[0] != WATCH_EVENT_LOADED {
+		t.Fatalf("scan = %v, want the copy to load", got)
+	}
+	accounts := manager.Accounts()
+	if len(accounts) != 2 || manager.Len() != 2 {
+		t.Fatalf("Accounts() = %+v", accounts)
+	}
+	for _, account := range accounts {
+		if account.Wallet.Address == wallets[0].Address && account.Source != duplicate {
+			t.Fatalf("account %s comes from %s, want %s", account.Wallet.Address, account.Source, duplicate)
+		}
+	}
+
+	conflict := &ErrAccountConflict{}
+	if err := manager.Add(wallets[2], "elsewhere"); !errors.As(err, &conflict) || conflict.Existing != b {
+		t.Fatalf("Add() error = %v, want a conflict with %s", err, b)
+	}
+}
+
+func TestDirectoryWatcherRun(t *testing.T) {
+	wallets := make([]UL_Wallet, 2)
+	for i := range wallets {
+		w, _, err := GenerateNewWallet("", crypto.KeyTypeSecp256k1, "", nil, DefaultEntropy)
+		if err != nil {
+			t.Fatalf("GenerateNewWallet() error = %v", err)
+		}
+		wallets[i] = w
+	}
+
+	for _, opts := range []WatchOptions{
+		// The interval is never reached, changes must come from notifications
+		{Interval: time.Hour},
+		{Interval: 10 * time.Millisecond, Poll: true},
+	} {
+		t.Run(fmt.Sprintf("poll=%t", opts.Poll), func(t *testing.T) {
+			dir := t.TempDir()
+			a := filepath.Join(dir, "a.ukey")
+			writeWalletFile(t, a, wallets[0], time.Now().Add(-time.Hour))
+
+			events := make(chan WatchEvent, 16)
+			opts.OnEvent = func(event WatchEvent) { events <- event }
+			manager := NewAccountManager()
+			watcher := NewDirectoryWatcher(dir, manager, opts)
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() { done <- watcher.Run(ctx) }()
+			defer func() {
+				cancel()
+				if err := <-done; !errors.Is(err, context.Canceled) {
+					t.Errorf("Run() error = %v", err)
+				}
+			}()
+
+			next := func(want WatchEventType, path string) {
+				t.Helper()
+				select {
+				case event := <-events:
+					if event.Type != want || event.Path != path {
+						t.Fatalf("event = %+v, want %s of %s", event, want, path)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("no event, want %s of %s", want, path)
+				}
+			}
+			next(WATCH_EVENT_LOADED, a)
+
+			b := filepath.Join(dir, "b.ukey")
+			writeWalletFile(t, b, wallets[1], time.Now())
+			next(WATCH_EVENT_LOADED, b)
+
+			// The copy conflicts until the original is deleted
+			c := filepath.Join(dir, "c.ukey")
+			writeWalletFile(t, c, wallets[0], time.Now())
+			next(WATCH_EVENT_CONFLICT, c)
+			if err := os.Remove(a); err != nil {
+				t.Fatal(err)
+			}
+			next(WATCH_EVENT_EVICTED, a)
+			next(WATCH_EVENT_LOADED, c)
+			if manager.Len() != 2 {
+				t.Fatalf("Len() = %d, want 2", manager.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+//go:build js && wasm
+
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+)
+
+// Register sets the signing functions on target, usually a global object:
+//
+//	uledger.generateWallet(keyType, passphrase?) -> wallet
+//	uledger.walletFromMnemonic(mnemonic, passphrase, keyType) -> wallet
+//	uledger.signTransaction(input, wallet) -> signed input
+//	uledger.signingCommitment(input) -> {commitment, payloadRoot}
+//
+// Transactions and wallets are objects or their JSON. The functions return an Error instead of
+// throwing, the loader shipped with cmd/uledger-wasm throws it.
+func Register(target js.Value) {
+	target.Set("generateWallet", function(1, func(args []js.Value) (any, error) {
+		passphrase := ""
+		if len(args) > 1 && args[1].Type() == js.TypeString {
+			passphrase = args[1].String()
+		}
+		return GenerateWallet(args[0].String(), passphrase)
+	}))
+	target.Set("walletFromMnemonic", function(3, func(args []js.Value) (any, error) {
+		return WalletFromMnemonic(args[0].String(), args[1].String(), args[2].String())
+	}))
+	target.Set("signTransaction", function(2, func(args []js.Value) (any, error) {
+		w := Wallet{}
+		if err := json.Unmarshal([]byte(jsonArg(args[1])), &w); err != nil {
+			return nil, fmt.Errorf("invalid wallet: %w", err)
+		}
+		signed, err := SignTransaction(jsonArg(args[0]), w)
+		return json.RawMessage(signed), err
+	}))
+	target.Set("signingCommitment", function(1, func(args []js.Value) (any, error) {
+		return SigningCommitment(jsonArg(args[0]))
+	}))
+}
+
+// function wraps fn into a JavaScript function returning its result as an object, or an Error for
+// missing arguments, errors and panics
+func function(arity int, fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(this js.Value, args []js.Value) (result any) {
+		defer func() {
+			if r := recover(); r != nil {
+				result = jsError(fmt.Errorf("%v", r))
+			}
+		}()
+		if len(args) < arity {
+			return jsError(fmt.Errorf("expected %d arguments, got %d", arity, len(args)))
+		}
+		value, err := fn(args)
+		if err != nil {
+			return jsError(err)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return jsError(err)
+		}
+		return js.Global().Get("JSON").Call("parse", string(encoded))
+	})
+}
+
+// jsonArg returns strings as they are and the JSON of other values
+func jsonArg(value js.Value) string {
+	if value.Type() == js.TypeString {
+		return value.String()
+	}
+	return js.Global().Get("JSON").Call("stringify", value).String()
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
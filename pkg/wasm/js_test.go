@@ -0,0 +1,37 @@
+//go:build js && wasm
+
+package wasm_test
+
+import (
+	"syscall/js"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/wasm"
+)
+
+func TestRegister(t *testing.T) {
+	uledger := js.Global().Get("Object").New()
+	wasm.Register(uledger)
+	isError := func(v js.Value) bool { return v.InstanceOf(js.Global().Get("Error")) }
+
+	w := uledger.Call("generateWallet", "secp256k1")
+	if isError(w) || w.Get("address").String() == "" {
+		t.Fatalf("generateWallet() = %v", w)
+	}
+	input := js.Global().Get("JSON").Call("parse", unsignedInput(t, w.Get("address").String()))
+	signed := uledger.Call("signTransaction", input, w)
+	if isError(signed) || signed.Get("senderSignature").String() == "" {
+		t.Fatalf("signTransaction() = %v", signed)
+	}
+	commitment := uledger.Call("signingCommitment", signed)
+	if isError(commitment) || commitment.Get("payloadRoot").String() != signed.Get("payloadRoot").String() {
+		t.Fatalf("signingCommitment() = %v", commitment)
+	}
+
+	if err := uledger.Call("generateWallet", "rsa"); !isError(err) {
+		t.Errorf("generateWallet(rsa) = %v, want an Error", err)
+	}
+	if err := uledger.Call("signTransaction", input); !isError(err) {
+		t.Errorf("signTransaction() without a wallet = %v, want an Error", err)
+	}
+}
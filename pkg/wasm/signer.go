@@ -0,0 +1,158 @@
+// Package wasm signs ULedger transactions in browsers. The SDK's crypto and commitment building
+// compile for GOOS=js GOARCH=wasm, Register exposes them to JavaScript and cmd/uledger-wasm is the
+// module browsers load:
+//
+//	GOOS=js GOARCH=wasm go build -o uledger.wasm ./cmd/uledger-wasm
+//
+// Keys never leave the page, the signed transactions can be submitted through any backend, such as
+// a gateway serving POST /blockchains/{blockchainId}/transactions. The functions behind Register
+// are plain Go so they build and are tested on every platform.
+package wasm
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+)
+
+// ErrUnknownKeyType is returned for key type names no key is generated for
+type ErrUnknownKeyType struct {
+	KeyType string
+}
+
+func (e *ErrUnknownKeyType) Error() string {
+	return fmt.Sprintf("unknown key type %q", e.KeyType)
+}
+
+func (e *ErrUnknownKeyType) Is(target error) bool {
+	return target == utils.ErrInvalidInput
+}
+
+// Wallet holds the hex encoded keys of a wallet as JavaScript sees them
+type Wallet struct {
+	Address    string `json:"address"`
+	KeyType    string `json:"keyType"`
+	PublicKey  string `json:"publicKey"`
+	PrivateKey string `json:"privateKey"`
+	// Mnemonic is only set for generated wallets
+	Mnemonic string `json:"mnemonic,omitempty"`
+}
+
+// Commitment is what a transaction's sender signs and the payload root recorded with it, both hex
+// encoded
+type Commitment struct {
+	Commitment  string `json:"commitment"`
+	PayloadRoot string `json:"payloadRoot"`
+}
+
+// parseKeyType is crypto.ParseCryptoKeyType without its fallback to secp256k1
+func parseKeyType(name string) (crypto.KeyType, error) {
+	keyType := crypto.ParseCryptoKeyType(name)
+	if keyType.String() != strings.ToLower(name) {
+		return 0, &ErrUnknownKeyType{KeyType: name}
+	}
+	return keyType, nil
+}
+
+func fromWallet(w wallet.UL_Wallet, mnemonic string) Wallet {
+	key := w.GetKey()
+	return Wallet{
+		Address:    w.Address,
+		KeyType:    key.GetType().String(),
+		PublicKey:  key.GetPublicKeyHex(false),
+		PrivateKey: key.GetPrivateKeyHex(),
+		Mnemonic:   mnemonic,
+	}
+}
+
+// GenerateWallet returns a new wallet of keyType with a random mnemonic, passphrase protects the
+// seed derived from the mnemonic and may be empty
+func GenerateWallet(keyType string, passphrase string) (Wallet, error) {
+	parsed, err := parseKeyType(keyType)
+	if err != nil {
+		return Wallet{}, err
+	}
+	w, mnemonic, err := wallet.GenerateNewWallet(passphrase, parsed, "", nil, wallet.DefaultEntropy)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return fromWallet(w, mnemonic), nil
+}
+
+// WalletFromMnemonic recovers the wallet of keyType of a BIP-39 mnemonic
+func WalletFromMnemonic(mnemonic string, passphrase string, keyType string) (Wallet, error) {
+	parsed, err := parseKeyType(keyType)
+	if err != nil {
+		return Wallet{}, err
+	}
+	w, err := wallet.GenerateFromMnemonic(mnemonic, passphrase, parsed)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return fromWallet(w, ""), nil
+}
+
+// toWallet loads the keys of w, the address must match the public key when it is set
+func (w Wallet) toWallet() (wallet.UL_Wallet, error) {
+	keyType, err := parseKeyType(w.KeyType)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	loaded, err := wallet.GetWalletFromHex(w.PublicKey, w.PrivateKey, keyType)
+	if err != nil {
+		return wallet.UL_Wallet{}, err
+	}
+	if w.Address != "" && !strings.EqualFold(w.Address, loaded.Address) {
+		return wallet.UL_Wallet{}, &utils.ErrMalformed{What: "wallet", Msg: fmt.Sprintf("the address %s is not the address %s of the public key", w.Address, loaded.Address)}
+	}
+	return loaded, nil
+}
+
+func parseInput(input string) (transaction.ULTransactionInput, error) {
+	parsed := transaction.ULTransactionInput{}
+	if err := json.Unmarshal([]byte(input), &parsed); err != nil {
+		return transaction.ULTransactionInput{}, &utils.ErrMalformed{What: "transaction", Msg: utils.HandleJsonError(err)}
+	}
+	return parsed, nil
+}
+
+// SignTransaction signs the JSON transaction input with w, see transaction.BuildSignedTransaction,
+// and returns the JSON body to submit
+func SignTransaction(input string, w Wallet) (string, error) {
+	parsed, err := parseInput(input)
+	if err != nil {
+		return "", err
+	}
+	signer, err := w.toWallet()
+	if err != nil {
+		return "", err
+	}
+	signed, err := transaction.BuildSignedTransaction(parsed, &signer)
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(signed)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// SigningCommitment returns the commitment of the JSON transaction input, for signers outside the
+// module such as hardware wallets. The input must already carry its sender, key type and timestamp.
+func SigningCommitment(input string) (Commitment, error) {
+	parsed, err := parseInput(input)
+	if err != nil {
+		return Commitment{}, err
+	}
+	commitment, payloadRoot, err := parsed.SigningCommitment()
+	if err != nil {
+		return Commitment{}, err
+	}
+	return Commitment{Commitment: crypto.BytesToHex(commitment), PayloadRoot: payloadRoot}, nil
+}
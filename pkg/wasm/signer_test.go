@@ -0,0 +1,95 @@
+package wasm_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/ULedgerInc/go-sdk/pkg/wallet"
+	"github.com/ULedgerInc/go-sdk/pkg/wasm"
+)
+
+const suggestor = "6c2b3e9c1c1b8f0e5f0d4a6b2d7e8f90a1b2c3d4e5f60718293a4b5c6d7e8f90"
+
+func unsignedInput(t *testing.T, to string) string {
+	t.Helper()
+	input, err := json.Marshal(transaction.ULTransactionInput{
+		BlockchainId: "MyBlockchain1",
+		Suggestor:    suggestor,
+		To:           to,
+		Payload:      "signed in a browser",
+		PayloadType:  transaction.TX_DATA.String(),
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	return string(input)
+}
+
+func TestSignTransaction(t *testing.T) {
+	for _, keyType := range []crypto.KeyType{crypto.KeyTypeSecp256k1, crypto.KeyTypeED25519, crypto.KeyTypeBLS12377} {
+		t.Run(keyType.String(), func(t *testing.T) {
+			w, err := wasm.GenerateWallet(keyType.String(), "")
+			if err != nil || w.Mnemonic == "" || w.KeyType != keyType.String() {
+				t.Fatalf("GenerateWallet() = %+v, %v", w, err)
+			}
+			recovered, err := wasm.WalletFromMnemonic(w.Mnemonic, "", w.KeyType)
+			if err != nil || recovered.Address != w.Address || recovered.PrivateKey != w.PrivateKey {
+				t.Fatalf("WalletFromMnemonic() = %+v, %v, want the keys of %+v", recovered, err, w)
+			}
+
+			body, err := wasm.SignTransaction(unsignedInput(t, w.Address), w)
+			if err != nil {
+				t.Fatalf("SignTransaction() error = %v", err)
+			}
+			signed := transaction.ULTransactionInput{}
+			if err := json.Unmarshal([]byte(body), &signed); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if signed.From != w.Address || signed.KeyType != keyType || signed.SenderSignature == "" {
+				t.Fatalf("SignTransaction() = %+v", signed)
+			}
+
+			commitment, err := wasm.SigningCommitment(body)
+			if err != nil || commitment.PayloadRoot != signed.PayloadRoot {
+				t.Fatalf("SigningCommitment() = %+v, %v, want payload root %s", commitment, err, signed.PayloadRoot)
+			}
+			signer, err := wallet.GetWalletFromHex(w.PublicKey, w.PrivateKey, keyType)
+			if err != nil {
+				t.Fatalf("GetWalletFromHex() error = %v", err)
+			}
+			data, _ := crypto.HexToBytes(commitment.Commitment)
+			signature, _ := crypto.HexToBytes(signed.SenderSignature)
+			if valid, err := signer.GetKey().VerifySignature(data, signature); !valid || err != nil {
+				t.Fatalf("VerifySignature() = %v, %v", valid, err)
+			}
+		})
+	}
+}
+
+func TestSignTransactionErrors(t *testing.T) {
+	var unknown *wasm.ErrUnknownKeyType
+	if _, err := wasm.GenerateWallet("rsa", ""); !errors.As(err, &unknown) || !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("GenerateWallet(rsa) error = %v", err)
+	}
+
+	w, err := wasm.GenerateWallet("ed25519", "")
+	if err != nil {
+		t.Fatalf("GenerateWallet() error = %v", err)
+	}
+	if _, err := wasm.SignTransaction("{", w); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("SignTransaction() of malformed JSON error = %v", err)
+	}
+	other := w
+	other.Address = strings.Repeat("0", 64)
+	if _, err := wasm.SignTransaction(unsignedInput(t, w.Address), other); !errors.Is(err, utils.ErrInvalidInput) {
+		t.Errorf("SignTransaction() with the wrong address error = %v", err)
+	}
+	if _, err := wasm.SigningCommitment(`{"commitmentScheme": "unknown"}`); err == nil {
+		t.Errorf("SigningCommitment() of an unknown scheme succeeded")
+	}
+}
@@ -0,0 +1,142 @@
+package zk
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/native/sw_bls12377"
+)
+
+// BLS12377SignatureCircuit proves, on BW6-761, that a BLS12-377 key signed a transaction commitment
+// for a public chain and sender, while the recipient, payload root, timestamp and signature stay private.
+// Hashing to G2 is too expensive in-circuit, so HashedMessage is derived from Commitment by the verifier.
+type BLS12377SignatureCircuit struct {
+	PublicKey        sw_bls12377.G1Affine `gnark:",public"`
+	HashedMessage    sw_bls12377.G2Affine `gnark:",public"`
+	BlockchainIdHigh frontend.Variable    `gnark:",public"`
+	BlockchainIdLow  frontend.Variable    `gnark:",public"`
+	FromHigh         frontend.Variable    `gnark:",public"`
+	FromLow          frontend.Variable    `gnark:",public"`
+	Commitment       frontend.Variable    `gnark:",public"`
+
+	ToHigh        frontend.Variable
+	ToLow         frontend.Variable
+	PayloadRoot   frontend.Variable
+	Timestamp     frontend.Variable
+	SuggestorHigh frontend.Variable
+	SuggestorLow  frontend.Variable
+	Signature     sw_bls12377.G2Affine
+}
+
+func (c *BLS12377SignatureCircuit) Define(api frontend.API) error {
+	fields := CommitmentFields{
+		BlockchainIdHigh: c.BlockchainIdHigh,
+		BlockchainIdLow:  c.BlockchainIdLow,
+		FromHigh:         c.FromHigh,
+		FromLow:          c.FromLow,
+		ToHigh:           c.ToHigh,
+		ToLow:            c.ToLow,
+		PayloadRoot:      c.PayloadRoot,
+		Timestamp:        c.Timestamp,
+		SuggestorHigh:    c.SuggestorHigh,
+		SuggestorLow:     c.SuggestorLow,
+	}
+	digest, err := fields.Hash(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(digest, c.Commitment)
+
+	// e(-G1, sig) * e(pk, H(m)) == 1, same check as BLS12377Key.VerifySignature
+	_, _, g1, _ := bls12377.Generators()
+	g1.Neg(&g1)
+	return sw_bls12377.PairingCheck(api,
+		[]sw_bls12377.G1Affine{sw_bls12377.NewG1Affine(g1), c.PublicKey},
+		[]sw_bls12377.G2Affine{c.Signature, c.HashedMessage},
+	)
+}
+
+// SetupBLS12377 compiles the BLS12-377 signature circuit and generates its keys
+func SetupBLS12377() (*Keys, error) {
+	return Setup(&BLS12377SignatureCircuit{}, ecc.BW6_761)
+}
+
+// NewBLS12377Assignment builds the full witness for a signed BLS12-377 transaction input
+func NewBLS12377Assignment(input transaction.ULTransactionInput, publicKeyHex string) (*BLS12377SignatureCircuit, error) {
+	if input.KeyType != crypto.KeyTypeBLS12377 {
+		return nil, fmt.Errorf("expected a %s transaction, got %s", crypto.KeyTypeBLS12377, input.KeyType)
+	}
+
+	key := crypto.NewBLS12377Key(nil)
+	if err := key.GeneratePublicKeyFromHex(true, publicKeyHex); err != nil {
+		return nil, fmt.Errorf("invalid public key, %w", err)
+	}
+
+	fields, digest, err := commitmentAssignment(input)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.HexToBytes(input.SenderSignature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature, %w", err)
+	}
+	valid, err := key.VerifySignature(digest, signature)
+	if err != nil || !valid {
+		return nil, fmt.Errorf("the signature does not match the transaction commitment")
+	}
+	_, publicKey, hashedMessage, sig, err := key.GetBLSSignatureParameters(signature, digest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BLS12377SignatureCircuit{
+		PublicKey:        sw_bls12377.NewG1Affine(publicKey),
+		HashedMessage:    sw_bls12377.NewG2Affine(hashedMessage),
+		BlockchainIdHigh: fields.BlockchainIdHigh,
+		BlockchainIdLow:  fields.BlockchainIdLow,
+		FromHigh:         fields.FromHigh,
+		FromLow:          fields.FromLow,
+		Commitment:       new(big.Int).SetBytes(digest),
+		ToHigh:           fields.ToHigh,
+		ToLow:            fields.ToLow,
+		PayloadRoot:      fields.PayloadRoot,
+		Timestamp:        fields.Timestamp,
+		SuggestorHigh:    fields.SuggestorHigh,
+		SuggestorLow:     fields.SuggestorLow,
+		Signature:        sw_bls12377.NewG2Affine(sig),
+	}, nil
+}
+
+// NewBLS12377PublicAssignment builds the public part of the witness a verifier knows up front
+func NewBLS12377PublicAssignment(publicKeyHex string, blockchainId string, from string, commitment []byte) (*BLS12377SignatureCircuit, error) {
+	publicKeyBytes, err := crypto.HexToBytes(publicKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key, %w", err)
+	}
+	var publicKey crypto.BLS12377PublicKey
+	if _, err := publicKey.SetBytes(publicKeyBytes); err != nil {
+		return nil, fmt.Errorf("invalid public key, %w", err)
+	}
+	hashedMessage, err := crypto.HashBLS12377Message(commitment)
+	if err != nil {
+		return nil, fmt.Errorf("unable to hash commitment, %w", err)
+	}
+	blockchainIdHigh, blockchainIdLow := splitHash(blockchainId)
+	fromHigh, fromLow := splitHash(from)
+
+	return &BLS12377SignatureCircuit{
+		PublicKey:        sw_bls12377.NewG1Affine(publicKey.A),
+		HashedMessage:    sw_bls12377.NewG2Affine(hashedMessage),
+		BlockchainIdHigh: blockchainIdHigh,
+		BlockchainIdLow:  blockchainIdLow,
+		FromHigh:         fromHigh,
+		FromLow:          fromLow,
+		Commitment:       new(big.Int).SetBytes(commitment),
+	}, nil
+}
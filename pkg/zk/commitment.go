@@ -0,0 +1,71 @@
+package zk
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+// CommitmentFields are the field elements the signature commitment hashes, in hashing order.
+// Every 16 byte half of a SHA256 and the timestamp are left padded into a single element.
+type CommitmentFields struct {
+	BlockchainIdHigh frontend.Variable
+	BlockchainIdLow  frontend.Variable
+	FromHigh         frontend.Variable
+	FromLow          frontend.Variable
+	ToHigh           frontend.Variable
+	ToLow            frontend.Variable
+	PayloadRoot      frontend.Variable
+	Timestamp        frontend.Variable
+	SuggestorHigh    frontend.Variable
+	SuggestorLow     frontend.Variable
+}
+
+// Hash recomputes transaction.HashSignatureCommitment inside a circuit
+func (c *CommitmentFields) Hash(api frontend.API) (frontend.Variable, error) {
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(c.BlockchainIdHigh, c.BlockchainIdLow, c.FromHigh, c.FromLow, c.ToHigh, c.ToLow,
+		c.PayloadRoot, c.Timestamp, c.SuggestorHigh, c.SuggestorLow)
+	return h.Sum(), nil
+}
+
+// commitmentAssignment computes the commitment of a signed input and the values to assign to a circuit
+func commitmentAssignment(input transaction.ULTransactionInput) (CommitmentFields, []byte, error) {
+	hasher := crypto.GetHasherByType(input.KeyType)
+	commitment, err := input.GetSignatureCommitment(hasher, true)
+	if err != nil {
+		return CommitmentFields{}, nil, fmt.Errorf("unable to build signature commitment, %w", err)
+	}
+	digest, err := input.HashSignatureCommitment(hasher, commitment)
+	if err != nil {
+		return CommitmentFields{}, nil, fmt.Errorf("unable to hash signature commitment, %w", err)
+	}
+
+	fields := CommitmentFields{
+		BlockchainIdHigh: new(big.Int).SetBytes(commitment.BlockchainIdHigh),
+		BlockchainIdLow:  new(big.Int).SetBytes(commitment.BlockchainIdLow),
+		FromHigh:         new(big.Int).SetBytes(commitment.FromHigh),
+		FromLow:          new(big.Int).SetBytes(commitment.FromLow),
+		ToHigh:           new(big.Int).SetBytes(commitment.ToHigh),
+		ToLow:            new(big.Int).SetBytes(commitment.ToLow),
+		PayloadRoot:      new(big.Int).SetBytes(commitment.PayloadRoot),
+		Timestamp:        new(big.Int).SetUint64(commitment.Timestamp),
+		SuggestorHigh:    new(big.Int).SetBytes(commitment.SuggestorHigh),
+		SuggestorLow:     new(big.Int).SetBytes(commitment.SuggestorLow),
+	}
+	return fields, digest, nil
+}
+
+// splitHash mirrors the SHA256 high/low split used by the signature commitment
+func splitHash(data string) (*big.Int, *big.Int) {
+	hash := sha256.Sum256([]byte(data))
+	return new(big.Int).SetBytes(hash[:16]), new(big.Int).SetBytes(hash[16:])
+}
@@ -0,0 +1,322 @@
+package zk
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/ULedgerInc/go-sdk/pkg/utils"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/accumulator/merkle"
+	"github.com/consensys/gnark/std/hash/mimc"
+)
+
+type PredicateOperator int
+
+const (
+	PREDICATE_LESS_THAN PredicateOperator = iota
+	PREDICATE_LESS_OR_EQUAL
+	PREDICATE_EQUAL
+	PREDICATE_GREATER_OR_EQUAL
+	PREDICATE_GREATER_THAN
+)
+
+func (op PredicateOperator) String() string {
+	switch op {
+	case PREDICATE_LESS_THAN:
+		return "<"
+	case PREDICATE_LESS_OR_EQUAL:
+		return "<="
+	case PREDICATE_EQUAL:
+		return "=="
+	case PREDICATE_GREATER_OR_EQUAL:
+		return ">="
+	case PREDICATE_GREATER_THAN:
+		return ">"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// allowed returns which comparison outcomes (less, equal, greater) satisfy the operator
+func (op PredicateOperator) allowed() (int, int, int, error) {
+	switch op {
+	case PREDICATE_LESS_THAN:
+		return 1, 0, 0, nil
+	case PREDICATE_LESS_OR_EQUAL:
+		return 1, 1, 0, nil
+	case PREDICATE_EQUAL:
+		return 0, 1, 0, nil
+	case PREDICATE_GREATER_OR_EQUAL:
+		return 0, 1, 1, nil
+	case PREDICATE_GREATER_THAN:
+		return 0, 0, 1, nil
+	default:
+		return 0, 0, 0, fmt.Errorf("unknown predicate operator: %d", op)
+	}
+}
+
+// ErrPredicateNotSatisfied is returned when asked to prove a predicate the payload does not satisfy
+type ErrPredicateNotSatisfied struct {
+	Msg string
+}
+
+func (e *ErrPredicateNotSatisfied) Error() string {
+	return e.Msg
+}
+
+func (e *ErrPredicateNotSatisfied) Is(target error) bool {
+	return target == utils.ErrRejected
+}
+
+// Predicate describes a comparison over an ASCII decimal number stored in a single payload chunk.
+// Chunk is the Merkle leaf index and Offset the first digit inside that leaf. The number runs to the
+// first byte that is not a digit, which must be in the same chunk. Its length stays in the private
+// witness so a proof does not reveal how many digits the number has.
+type Predicate struct {
+	Chunk    uint64
+	Offset   int
+	Operator PredicateOperator
+	Bound    *big.Int
+}
+
+func (p Predicate) String() string {
+	return fmt.Sprintf("chunk %d [%d:] %s %s", p.Chunk, p.Offset, p.Operator, p.Bound)
+}
+
+func (p Predicate) validate() error {
+	if p.Chunk >= 1<<transaction.DEPTH {
+		return fmt.Errorf("chunk %d is out of range, the payload has %d chunks", p.Chunk, 1<<transaction.DEPTH)
+	}
+	if p.Offset < 0 || p.Offset >= transaction.CHUNK_SIZE {
+		return fmt.Errorf("the number must start in a single %d byte chunk, got offset %d", transaction.CHUNK_SIZE, p.Offset)
+	}
+	if p.Bound == nil || p.Bound.Sign() < 0 {
+		return fmt.Errorf("the bound must be a non negative number")
+	}
+	_, _, _, err := p.Operator.allowed()
+	return err
+}
+
+// PredicateForField locates the number stored under a JSON key, e.g. "amount":"42" or "amount":42,
+// and returns a predicate comparing it to bound. The digits and the byte after them must not straddle
+// two chunks.
+func PredicateForField(payload string, key string, op PredicateOperator, bound *big.Int) (Predicate, error) {
+	needle := []byte(fmt.Sprintf("%q:", key))
+	idx := bytes.Index([]byte(payload), needle)
+	if idx < 0 {
+		return Predicate{}, fmt.Errorf("field %s not found in payload", key)
+	}
+	start := idx + len(needle)
+	for start < len(payload) && (payload[start] == ' ' || payload[start] == '"') {
+		start++
+	}
+	end := start
+	for end < len(payload) && isDigit(payload[end]) {
+		end++
+	}
+	if end == start {
+		return Predicate{}, fmt.Errorf("field %s is not a number", key)
+	}
+	chunk := start / transaction.CHUNK_SIZE
+	if end/transaction.CHUNK_SIZE != chunk {
+		return Predicate{}, fmt.Errorf("field %s spans two payload chunks", key)
+	}
+
+	predicate := Predicate{
+		Chunk:    uint64(chunk),
+		Offset:   start - chunk*transaction.CHUNK_SIZE,
+		Operator: op,
+		Bound:    bound,
+	}
+	return predicate, predicate.validate()
+}
+
+// PayloadPredicateCircuit proves that a chunk of a committed payload holds a decimal number
+// satisfying a comparison, without revealing the chunk or the rest of the payload
+type PayloadPredicateCircuit struct {
+	PayloadRoot  frontend.Variable `gnark:",public"`
+	ChunkIndex   frontend.Variable `gnark:",public"`
+	Offset       frontend.Variable `gnark:",public"`
+	Bound        frontend.Variable `gnark:",public"`
+	AllowLess    frontend.Variable `gnark:",public"`
+	AllowEqual   frontend.Variable `gnark:",public"`
+	AllowGreater frontend.Variable `gnark:",public"`
+
+	Chunk [transaction.CHUNK_SIZE]frontend.Variable
+	Path  [transaction.DEPTH + 1]frontend.Variable
+}
+
+func (c *PayloadPredicateCircuit) Define(api frontend.API) error {
+	// Rebuild the leaf the same way GenerateMerkleTreeWithHardBound lays it out:
+	// 16 zero bytes, the chunk, then zeros up to the field size
+	leaf := frontend.Variable(0)
+	for _, b := range c.Chunk {
+		api.ToBinary(b, 8)
+		leaf = api.Add(api.Mul(leaf, 256), b)
+	}
+	shift := new(big.Int).Lsh(big.NewInt(1), uint(8*(leafSize(api.Compiler().Field())-2*transaction.CHUNK_SIZE)))
+	api.AssertIsEqual(api.Mul(leaf, shift), c.Path[0])
+
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	proof := merkle.MerkleProof{RootHash: c.PayloadRoot, Path: c.Path[:]}
+	proof.VerifyProof(api, &h, c.ChunkIndex)
+
+	// Fold the run of digits starting at Offset into a number. The run is the whole number: the byte
+	// before it is not a digit and it ends on a non digit within the chunk, so its length needs no input.
+	inField := frontend.Variable(0)
+	starts := frontend.Variable(0)
+	value := frontend.Variable(0)
+	for j := 0; j < transaction.CHUNK_SIZE; j++ {
+		start := api.IsZero(api.Sub(j, c.Offset))
+		before := api.IsZero(api.Sub(j+1, c.Offset))
+		starts = api.Add(starts, start)
+
+		digit := api.Sub(c.Chunk[j], '0')
+		isDigit := frontend.Variable(0)
+		for k := 0; k < 10; k++ {
+			isDigit = api.Add(isDigit, api.IsZero(api.Sub(digit, k)))
+		}
+		api.AssertIsEqual(api.Mul(start, api.Sub(1, isDigit)), 0)
+		api.AssertIsEqual(api.Mul(before, isDigit), 0)
+		inField = api.Mul(api.Add(inField, start), isDigit)
+		value = api.Select(inField, api.Add(api.Mul(value, 10), digit), value)
+	}
+	api.AssertIsEqual(starts, 1)
+	api.AssertIsEqual(inField, 0)
+
+	api.AssertIsBoolean(c.AllowLess)
+	api.AssertIsBoolean(c.AllowEqual)
+	api.AssertIsBoolean(c.AllowGreater)
+	cmp := api.Cmp(value, c.Bound)
+	satisfied := api.Add(
+		api.Mul(api.IsZero(api.Add(cmp, 1)), c.AllowLess),
+		api.Mul(api.IsZero(cmp), c.AllowEqual),
+		api.Mul(api.IsZero(api.Sub(cmp, 1)), c.AllowGreater),
+	)
+	api.AssertIsEqual(satisfied, 1)
+	return nil
+}
+
+// leafSize is the size in bytes of a Merkle leaf for the given field
+func leafSize(modulus *big.Int) int {
+	return len(modulus.Bytes())
+}
+
+// predicateKeyType maps a proving curve to the key type whose hasher built the payload commitment
+func predicateKeyType(curve ecc.ID) (crypto.KeyType, error) {
+	switch curve {
+	case ecc.BN254:
+		return crypto.KeyTypeSecp256k1, nil
+	case ecc.BW6_761:
+		return crypto.KeyTypeBLS12377, nil
+	default:
+		return 0, fmt.Errorf("unsupported curve for payload predicates: %s", curve)
+	}
+}
+
+// SetupPredicate compiles the payload predicate circuit. Use ecc.BN254 for payloads committed by
+// secp256k1 wallets and ecc.BW6_761 for BLS12-377 wallets.
+func SetupPredicate(curve ecc.ID) (*Keys, error) {
+	if _, err := predicateKeyType(curve); err != nil {
+		return nil, err
+	}
+	return Setup(&PayloadPredicateCircuit{}, curve)
+}
+
+func newPredicatePublicAssignment(payloadRoot []byte, predicate Predicate) (*PayloadPredicateCircuit, error) {
+	if err := predicate.validate(); err != nil {
+		return nil, err
+	}
+	less, equal, greater, _ := predicate.Operator.allowed()
+	return &PayloadPredicateCircuit{
+		PayloadRoot:  new(big.Int).SetBytes(payloadRoot),
+		ChunkIndex:   predicate.Chunk,
+		Offset:       predicate.Offset,
+		Bound:        predicate.Bound,
+		AllowLess:    less,
+		AllowEqual:   equal,
+		AllowGreater: greater,
+	}, nil
+}
+
+// NewPredicateAssignment builds the full witness proving predicate over payload
+func NewPredicateAssignment(curve ecc.ID, payload string, predicate Predicate) (*PayloadPredicateCircuit, error) {
+	keyType, err := predicateKeyType(curve)
+	if err != nil {
+		return nil, err
+	}
+	if err := predicate.validate(); err != nil {
+		return nil, err
+	}
+
+	root, proofElements, proofChunk, _, err := transaction.GenerateMerkleTreeWithHardBound([]byte(payload), curve.ScalarField(),
+		transaction.CHUNK_SIZE, transaction.DEPTH, crypto.GetHasherByType(keyType), predicate.Chunk)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build payload commitment, %w", err)
+	}
+	if len(proofElements) != transaction.DEPTH+1 {
+		return nil, fmt.Errorf("expected %d proof elements, got %d", transaction.DEPTH+1, len(proofElements))
+	}
+
+	// The leaf is 16 zero bytes followed by the chunk
+	chunk := proofChunk[transaction.CHUNK_SIZE : 2*transaction.CHUNK_SIZE]
+	end := predicate.Offset
+	for end < len(chunk) && isDigit(chunk[end]) {
+		end++
+	}
+	if end == predicate.Offset || end == len(chunk) || (predicate.Offset > 0 && isDigit(chunk[predicate.Offset-1])) {
+		return nil, fmt.Errorf("chunk %d has no whole decimal number at offset %d ending within the chunk", predicate.Chunk, predicate.Offset)
+	}
+	value, _ := new(big.Int).SetString(string(chunk[predicate.Offset:end]), 10)
+	less, equal, greater, _ := predicate.Operator.allowed()
+	if []int{less, equal, greater}[value.Cmp(predicate.Bound)+1] == 0 {
+		return nil, &ErrPredicateNotSatisfied{Msg: fmt.Sprintf("payload does not satisfy %s", predicate)}
+	}
+
+	assignment, err := newPredicatePublicAssignment(root, predicate)
+	if err != nil {
+		return nil, err
+	}
+	for i, b := range chunk {
+		assignment.Chunk[i] = b
+	}
+	for i, element := range proofElements {
+		assignment.Path[i] = new(big.Int).SetBytes(element)
+	}
+	return assignment, nil
+}
+
+func isDigit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// ProvePredicate proves predicate over payload, returning the proof and the payload root it is bound to.
+// The root matches the PayloadRoot of the transaction that carried the payload.
+func ProvePredicate(keys *Keys, payload string, predicate Predicate) (groth16.Proof, []byte, error) {
+	assignment, err := NewPredicateAssignment(keys.Curve, payload, predicate)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, err := Prove(keys, assignment)
+	if err != nil {
+		return nil, nil, err
+	}
+	return proof, assignment.PayloadRoot.(*big.Int).FillBytes(make([]byte, leafSize(keys.Curve.ScalarField()))), nil
+}
+
+// VerifyPredicate checks that the payload committed to by payloadRoot satisfies predicate
+func VerifyPredicate(curve ecc.ID, vk groth16.VerifyingKey, proof groth16.Proof, payloadRoot []byte, predicate Predicate) error {
+	public, err := newPredicatePublicAssignment(payloadRoot, predicate)
+	if err != nil {
+		return err
+	}
+	return Verify(curve, vk, proof, public)
+}
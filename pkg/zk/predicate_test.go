@@ -0,0 +1,135 @@
+package zk
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+const predicatePayload = `{"memo":"invoice 2024-118","amount":"1250","currency":"USD"}`
+
+func TestPredicateForField(t *testing.T) {
+	predicate, err := PredicateForField(predicatePayload, "amount", PREDICATE_LESS_THAN, big.NewInt(5000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := int(predicate.Chunk)*transaction.CHUNK_SIZE + predicate.Offset
+	if got := predicatePayload[start : start+5]; got != `1250"` {
+		t.Fatalf("expected to locate 1250, got %q", got)
+	}
+
+	if _, err := PredicateForField(predicatePayload, "missing", PREDICATE_LESS_THAN, big.NewInt(1)); err == nil {
+		t.Fatal("expected an error for a missing field")
+	}
+	if _, err := PredicateForField(predicatePayload, "currency", PREDICATE_LESS_THAN, big.NewInt(1)); err == nil {
+		t.Fatal("expected an error for a non numeric field")
+	}
+	// The byte ending the number must be in its chunk, the circuit cannot look at the next one
+	if _, err := PredicateForField(`{"memo":"aaaaaaaaaaa","n":123456}`, "n", PREDICATE_LESS_THAN, big.NewInt(1)); err == nil {
+		t.Fatal("expected an error for a number ending on the chunk boundary")
+	}
+}
+
+func TestPayloadPredicateCircuit(t *testing.T) {
+	tests := []struct {
+		name      string
+		op        PredicateOperator
+		bound     int64
+		satisfied bool
+	}{
+		{"less than", PREDICATE_LESS_THAN, 5000, true},
+		{"less than equal bound", PREDICATE_LESS_THAN, 1250, false},
+		{"less or equal", PREDICATE_LESS_OR_EQUAL, 1250, true},
+		{"equal", PREDICATE_EQUAL, 1250, true},
+		{"greater or equal", PREDICATE_GREATER_OR_EQUAL, 1251, false},
+		{"greater than", PREDICATE_GREATER_THAN, 1000, true},
+	}
+
+	for _, curve := range []ecc.ID{ecc.BN254, ecc.BW6_761} {
+		for _, tt := range tests {
+			t.Run(curve.String()+"/"+tt.name, func(t *testing.T) {
+				predicate, err := PredicateForField(predicatePayload, "amount", tt.op, big.NewInt(tt.bound))
+				if err != nil {
+					t.Fatal(err)
+				}
+				assignment, err := NewPredicateAssignment(curve, predicatePayload, predicate)
+				if !tt.satisfied {
+					var notSatisfied *ErrPredicateNotSatisfied
+					if !errors.As(err, &notSatisfied) {
+						t.Fatalf("expected ErrPredicateNotSatisfied, got %v", err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := test.IsSolved(&PayloadPredicateCircuit{}, assignment, curve.ScalarField()); err != nil {
+					t.Fatalf("expected the witness to satisfy the circuit, %v", err)
+				}
+
+				// The prover cannot lie about the bound
+				forged := *assignment
+				forged.Bound = 1
+				forged.AllowLess, forged.AllowEqual, forged.AllowGreater = 1, 0, 0
+				if err := test.IsSolved(&PayloadPredicateCircuit{}, &forged, curve.ScalarField()); err == nil {
+					t.Fatal("expected a forged bound to fail")
+				}
+
+				// Nor compare only the tail of the number
+				forged = *assignment
+				forged.Offset = predicate.Offset + 1
+				if err := test.IsSolved(&PayloadPredicateCircuit{}, &forged, curve.ScalarField()); err == nil {
+					t.Fatal("expected a window inside the number to fail")
+				}
+			})
+		}
+	}
+}
+
+func TestPayloadRootMatchesTransaction(t *testing.T) {
+	input := transaction.ULTransactionInput{Payload: predicatePayload, KeyType: crypto.KeyTypeSecp256k1}
+	commitment, err := input.GetSignatureCommitment(crypto.GetHasherByType(input.KeyType), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	predicate, err := PredicateForField(predicatePayload, "amount", PREDICATE_LESS_THAN, big.NewInt(5000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewPredicateAssignment(ecc.BN254, predicatePayload, predicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assignment.PayloadRoot.(*big.Int).Cmp(new(big.Int).SetBytes(commitment.PayloadRoot)) != 0 {
+		t.Fatal("expected the predicate to be bound to the transaction payload root")
+	}
+}
+
+func TestProveVerifyPredicate(t *testing.T) {
+	keys, err := SetupPredicate(ecc.BN254)
+	if err != nil {
+		t.Fatal(err)
+	}
+	predicate, err := PredicateForField(predicatePayload, "amount", PREDICATE_LESS_THAN, big.NewInt(5000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, root, err := ProvePredicate(keys, predicatePayload, predicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyPredicate(keys.Curve, keys.VerifyingKey, proof, root, predicate); err != nil {
+		t.Fatal(err)
+	}
+
+	stricter := predicate
+	stricter.Bound = big.NewInt(1000)
+	if err := VerifyPredicate(keys.Curve, keys.VerifyingKey, proof, root, stricter); err == nil {
+		t.Fatal("expected verification against another bound to fail")
+	}
+}
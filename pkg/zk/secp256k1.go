@@ -0,0 +1,152 @@
+package zk
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/std/algebra/emulated/sw_emulated"
+	"github.com/consensys/gnark/std/hash/mimc"
+	"github.com/consensys/gnark/std/math/emulated"
+	"github.com/consensys/gnark/std/signature/ecdsa"
+)
+
+// Secp256k1SignatureCircuit proves, on BN254, that a secp256k1 key signed a transaction commitment
+// for a public chain and sender, while the recipient, payload root, timestamp and signature stay private
+type Secp256k1SignatureCircuit struct {
+	PublicKey        ecdsa.PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr] `gnark:",public"`
+	BlockchainIdHigh frontend.Variable                                           `gnark:",public"`
+	BlockchainIdLow  frontend.Variable                                           `gnark:",public"`
+	FromHigh         frontend.Variable                                           `gnark:",public"`
+	FromLow          frontend.Variable                                           `gnark:",public"`
+	Commitment       frontend.Variable                                           `gnark:",public"`
+
+	ToHigh        frontend.Variable
+	ToLow         frontend.Variable
+	PayloadRoot   frontend.Variable
+	Timestamp     frontend.Variable
+	SuggestorHigh frontend.Variable
+	SuggestorLow  frontend.Variable
+	Signature     ecdsa.Signature[emulated.Secp256k1Fr]
+}
+
+func (c *Secp256k1SignatureCircuit) Define(api frontend.API) error {
+	fields := CommitmentFields{
+		BlockchainIdHigh: c.BlockchainIdHigh,
+		BlockchainIdLow:  c.BlockchainIdLow,
+		FromHigh:         c.FromHigh,
+		FromLow:          c.FromLow,
+		ToHigh:           c.ToHigh,
+		ToLow:            c.ToLow,
+		PayloadRoot:      c.PayloadRoot,
+		Timestamp:        c.Timestamp,
+		SuggestorHigh:    c.SuggestorHigh,
+		SuggestorLow:     c.SuggestorLow,
+	}
+	digest, err := fields.Hash(api)
+	if err != nil {
+		return err
+	}
+	api.AssertIsEqual(digest, c.Commitment)
+
+	// SignData hashes the commitment once more before signing, mirror it
+	h, err := mimc.NewMiMC(api)
+	if err != nil {
+		return err
+	}
+	h.Write(digest)
+	message := h.Sum()
+
+	scalarField, err := emulated.NewField[emulated.Secp256k1Fr](api)
+	if err != nil {
+		return err
+	}
+	// The MiMC output lives in the BN254 scalar field, always smaller than the secp256k1 order
+	msg := scalarField.FromBits(api.ToBinary(message, ecc.BN254.ScalarField().BitLen())...)
+	c.PublicKey.Verify(api, sw_emulated.GetSecp256k1Params(), msg, &c.Signature)
+	return nil
+}
+
+// SetupSecp256k1 compiles the secp256k1 signature circuit and generates its keys
+func SetupSecp256k1() (*Keys, error) {
+	return Setup(&Secp256k1SignatureCircuit{}, ecc.BN254)
+}
+
+// NewSecp256k1Assignment builds the full witness for a signed secp256k1 transaction input
+func NewSecp256k1Assignment(input transaction.ULTransactionInput, publicKeyHex string) (*Secp256k1SignatureCircuit, error) {
+	if input.KeyType != crypto.KeyTypeSecp256k1 {
+		return nil, fmt.Errorf("expected a %s transaction, got %s", crypto.KeyTypeSecp256k1, input.KeyType)
+	}
+
+	key := crypto.NewSecp256k1Key(nil)
+	if err := key.GeneratePublicKeyFromHex(len(publicKeyHex) == 66, publicKeyHex); err != nil {
+		return nil, fmt.Errorf("invalid public key, %w", err)
+	}
+
+	fields, digest, err := commitmentAssignment(input)
+	if err != nil {
+		return nil, err
+	}
+
+	signature, err := crypto.HexToBytes(input.SenderSignature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature, %w", err)
+	}
+	valid, err := key.VerifySignature(digest, signature)
+	if err != nil || !valid {
+		return nil, fmt.Errorf("the signature does not match the transaction commitment")
+	}
+	r, s, err := key.GetRSFromSignature(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey := key.GetPublicKey()
+	return &Secp256k1SignatureCircuit{
+		PublicKey: ecdsa.PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](publicKey.A.X.BigInt(new(big.Int))),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](publicKey.A.Y.BigInt(new(big.Int))),
+		},
+		BlockchainIdHigh: fields.BlockchainIdHigh,
+		BlockchainIdLow:  fields.BlockchainIdLow,
+		FromHigh:         fields.FromHigh,
+		FromLow:          fields.FromLow,
+		Commitment:       new(big.Int).SetBytes(digest),
+		ToHigh:           fields.ToHigh,
+		ToLow:            fields.ToLow,
+		PayloadRoot:      fields.PayloadRoot,
+		Timestamp:        fields.Timestamp,
+		SuggestorHigh:    fields.SuggestorHigh,
+		SuggestorLow:     fields.SuggestorLow,
+		Signature: ecdsa.Signature[emulated.Secp256k1Fr]{
+			R: emulated.ValueOf[emulated.Secp256k1Fr](r),
+			S: emulated.ValueOf[emulated.Secp256k1Fr](s),
+		},
+	}, nil
+}
+
+// NewSecp256k1PublicAssignment builds the public part of the witness a verifier knows up front
+func NewSecp256k1PublicAssignment(publicKeyHex string, blockchainId string, from string, commitment []byte) (*Secp256k1SignatureCircuit, error) {
+	key := crypto.NewSecp256k1Key(nil)
+	if err := key.GeneratePublicKeyFromHex(len(publicKeyHex) == 66, publicKeyHex); err != nil {
+		return nil, fmt.Errorf("invalid public key, %w", err)
+	}
+	blockchainIdHigh, blockchainIdLow := splitHash(blockchainId)
+	fromHigh, fromLow := splitHash(from)
+
+	publicKey := key.GetPublicKey()
+	return &Secp256k1SignatureCircuit{
+		PublicKey: ecdsa.PublicKey[emulated.Secp256k1Fp, emulated.Secp256k1Fr]{
+			X: emulated.ValueOf[emulated.Secp256k1Fp](publicKey.A.X.BigInt(new(big.Int))),
+			Y: emulated.ValueOf[emulated.Secp256k1Fp](publicKey.A.Y.BigInt(new(big.Int))),
+		},
+		BlockchainIdHigh: blockchainIdHigh,
+		BlockchainIdLow:  blockchainIdLow,
+		FromHigh:         fromHigh,
+		FromLow:          fromLow,
+		Commitment:       new(big.Int).SetBytes(commitment),
+	}, nil
+}
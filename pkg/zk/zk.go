@@ -0,0 +1,84 @@
+package zk
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/backend/groth16"
+	"github.com/consensys/gnark/constraint"
+	"github.com/consensys/gnark/frontend"
+	"github.com/consensys/gnark/frontend/cs/r1cs"
+)
+
+// Keys holds a compiled circuit together with its Groth16 proving and verifying keys.
+// The setup is not trusted, production deployments should run an MPC ceremony and load
+// the resulting keys instead of calling Setup.
+type Keys struct {
+	Curve            ecc.ID
+	ConstraintSystem constraint.ConstraintSystem
+	ProvingKey       groth16.ProvingKey
+	VerifyingKey     groth16.VerifyingKey
+}
+
+// Setup compiles a circuit on the given curve and generates its Groth16 keys
+func Setup(circuit frontend.Circuit, curve ecc.ID) (*Keys, error) {
+	ccs, err := frontend.Compile(curve.ScalarField(), r1cs.NewBuilder, circuit)
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile circuit, %w", err)
+	}
+
+	pk, vk, err := groth16.Setup(ccs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run groth16 setup, %w", err)
+	}
+
+	return &Keys{
+		Curve:            curve,
+		ConstraintSystem: ccs,
+		ProvingKey:       pk,
+		VerifyingKey:     vk,
+	}, nil
+}
+
+// Prove generates a proof for a fully assigned circuit
+func Prove(keys *Keys, assignment frontend.Circuit) (groth16.Proof, error) {
+	witness, err := frontend.NewWitness(assignment, keys.Curve.ScalarField())
+	if err != nil {
+		return nil, fmt.Errorf("unable to build witness, %w", err)
+	}
+
+	proof, err := groth16.Prove(keys.ConstraintSystem, keys.ProvingKey, witness)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate proof, %w", err)
+	}
+	return proof, nil
+}
+
+// Verify checks a proof against the public part of an assignment, private fields are ignored
+func Verify(curve ecc.ID, vk groth16.VerifyingKey, proof groth16.Proof, publicAssignment frontend.Circuit) error {
+	publicWitness, err := frontend.NewWitness(publicAssignment, curve.ScalarField(), frontend.PublicOnly())
+	if err != nil {
+		return fmt.Errorf("unable to build public witness, %w", err)
+	}
+
+	if err := groth16.Verify(proof, vk, publicWitness); err != nil {
+		return fmt.Errorf("invalid proof, %w", err)
+	}
+	return nil
+}
+
+// WriteProof serializes a proof so it can be shipped to a verifier
+func WriteProof(w io.Writer, proof groth16.Proof) error {
+	_, err := proof.WriteTo(w)
+	return err
+}
+
+// ReadProof deserializes a proof produced on the given curve
+func ReadProof(r io.Reader, curve ecc.ID) (groth16.Proof, error) {
+	proof := groth16.NewProof(curve)
+	if _, err := proof.ReadFrom(r); err != nil {
+		return nil, fmt.Errorf("unable to read proof, %w", err)
+	}
+	return proof, nil
+}
@@ -0,0 +1,189 @@
+package zk
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ULedgerInc/go-sdk/pkg/crypto"
+	"github.com/ULedgerInc/go-sdk/pkg/transaction"
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark/test"
+)
+
+// signInput signs an input the same way UL_TransactionSession.GenerateTransaction does
+func signInput(t *testing.T, key crypto.ULKey, input transaction.ULTransactionInput) transaction.ULTransactionInput {
+	t.Helper()
+	input.KeyType = key.GetType()
+	input.SenderTimestamp = time.Unix(1700000000, 0).UTC()
+
+	hasher := crypto.GetHasherByType(input.KeyType)
+	commitment, err := input.GetSignatureCommitment(hasher, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := input.HashSignatureCommitment(hasher, commitment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := key.SignData(digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.PayloadRoot = crypto.BytesToHex(commitment.PayloadRoot)
+	input.SenderSignature = crypto.BytesToHex(signature)
+	return input
+}
+
+func testInput() transaction.ULTransactionInput {
+	return transaction.ULTransactionInput{
+		BlockchainId: "MyBlockchain1",
+		From:         "sender",
+		To:           "recipient",
+		Payload:      `{"amount":"42","memo":"confidential"}`,
+		PayloadType:  transaction.TX_DATA.String(),
+		Suggestor:    "node",
+	}
+}
+
+func commitmentDigest(t *testing.T, input transaction.ULTransactionInput) []byte {
+	t.Helper()
+	_, digest, err := commitmentAssignment(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return digest
+}
+
+func TestSecp256k1SignatureCircuit(t *testing.T) {
+	key := crypto.NewSecp256k1Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("zk secp256k1 test seed")); err != nil {
+		t.Fatal(err)
+	}
+	input := signInput(t, key, testInput())
+
+	assignment, err := NewSecp256k1Assignment(input, key.GetPublicKeyHex(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.IsSolved(&Secp256k1SignatureCircuit{}, assignment, ecc.BN254.ScalarField()); err != nil {
+		t.Fatalf("expected the witness to satisfy the circuit, %v", err)
+	}
+
+	// A different recipient changes the commitment the signature covers
+	tampered := *assignment
+	tampered.ToLow = 1
+	if err := test.IsSolved(&Secp256k1SignatureCircuit{}, &tampered, ecc.BN254.ScalarField()); err == nil {
+		t.Fatal("expected a tampered witness to fail")
+	}
+
+	input.SenderSignature = signInput(t, key, transaction.ULTransactionInput{BlockchainId: "other"}).SenderSignature
+	if _, err := NewSecp256k1Assignment(input, key.GetPublicKeyHex(false)); err == nil {
+		t.Fatal("expected a mismatched signature to be rejected")
+	}
+}
+
+func TestSecp256k1Groth16(t *testing.T) {
+	if testing.Short() {
+		t.Skip("emulated secp256k1 setup is slow")
+	}
+	key := crypto.NewSecp256k1Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("zk secp256k1 test seed")); err != nil {
+		t.Fatal(err)
+	}
+	input := signInput(t, key, testInput())
+
+	keys, err := SetupSecp256k1()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewSecp256k1Assignment(input, key.GetPublicKeyHex(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(keys, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	public, err := NewSecp256k1PublicAssignment(key.GetPublicKeyHex(false), input.BlockchainId, input.From, commitmentDigest(t, input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(keys.Curve, keys.VerifyingKey, proof, public); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBLS12377SignatureCircuit(t *testing.T) {
+	key := crypto.NewBLS12377Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("zk bls12377 test seed")); err != nil {
+		t.Fatal(err)
+	}
+	input := signInput(t, key, testInput())
+
+	assignment, err := NewBLS12377Assignment(input, key.GetPublicKeyHex(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := test.IsSolved(&BLS12377SignatureCircuit{}, assignment, ecc.BW6_761.ScalarField()); err != nil {
+		t.Fatalf("expected the witness to satisfy the circuit, %v", err)
+	}
+
+	tampered := *assignment
+	tampered.PayloadRoot = 1
+	if err := test.IsSolved(&BLS12377SignatureCircuit{}, &tampered, ecc.BW6_761.ScalarField()); err == nil {
+		t.Fatal("expected a tampered witness to fail")
+	}
+}
+
+func TestBLS12377Groth16(t *testing.T) {
+	if testing.Short() {
+		t.Skip("groth16 setup on BW6-761 is slow")
+	}
+	key := crypto.NewBLS12377Key(nil)
+	if err := key.GenerateKeyFromSeed([]byte("zk bls12377 test seed")); err != nil {
+		t.Fatal(err)
+	}
+	input := signInput(t, key, testInput())
+
+	keys, err := SetupBLS12377()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assignment, err := NewBLS12377Assignment(input, key.GetPublicKeyHex(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := Prove(keys, assignment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteProof(&buf, proof); err != nil {
+		t.Fatal(err)
+	}
+	proof, err = ReadProof(&buf, keys.Curve)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := commitmentDigest(t, input)
+	public, err := NewBLS12377PublicAssignment(key.GetPublicKeyHex(true), input.BlockchainId, input.From, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(keys.Curve, keys.VerifyingKey, proof, public); err != nil {
+		t.Fatal(err)
+	}
+
+	// The proof is bound to the sender
+	other, err := NewBLS12377PublicAssignment(key.GetPublicKeyHex(true), input.BlockchainId, "someone else", digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(keys.Curve, keys.VerifyingKey, proof, other); err == nil {
+		t.Fatal("expected verification against another sender to fail")
+	}
+}